@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/analysis"
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// runAnalysis prints the post-game analysis of the finished game: which
+// moves were deduced or guessed, the 50/50s, and the efficiency.
+//
+//	gamemaster analysis --namespace podsweeper-game
+func runAnalysis(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("analysis", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	asJSON := fs.Bool("json", false, "Print the analysis as JSON.")
+	_ = fs.Parse(args)
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	state, err := game.NewSecretStore(c, game.WithNamespace(*namespace)).Load(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil {
+		return fmt.Errorf("no game in namespace %s", *namespace)
+	}
+	if state.Status == game.StatusPlaying {
+		return fmt.Errorf("the game in namespace %s is still in progress", *namespace)
+	}
+
+	report := analysis.Analyze(state)
+	if *asJSON {
+		return json.NewEncoder(out).Encode(report)
+	}
+	_, err = fmt.Fprint(out, report)
+	return err
+}
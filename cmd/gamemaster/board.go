@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/render"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+// runBoard prints the board of the game as players see it, or with its
+// mines with --full, highlighting the --x and --y cell if set.
+//
+//	gamemaster board --namespace podsweeper-game --format ansi --x 3 --y 4
+func runBoard(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("board", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	format := fs.String("format", string(render.Plain), "How the board is drawn: plain, ansi or unicode.")
+	full := fs.Bool("full", false, "Show the hidden mines.")
+	x := fs.Int("x", -1, "X coordinate of the cell to highlight.")
+	y := fs.Int("y", -1, "Y coordinate of the cell to highlight.")
+	_ = fs.Parse(args)
+
+	r := render.Renderer{View: render.ViewPlayer}
+	var err error
+	if r.Format, err = render.ParseFormat(*format); err != nil {
+		return err
+	}
+	if *full {
+		r.View = render.ViewFull
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	state, err := game.NewSecretStore(c, game.WithNamespace(*namespace)).Load(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil {
+		return fmt.Errorf("no game in namespace %s", *namespace)
+	}
+	r.Theme = theme.Of(state.Theme)
+	if state.IsValidCoordinate(*x, *y) {
+		r.Cursor = &game.Coordinate{X: *x, Y: *y}
+	}
+
+	_, err = fmt.Fprintf(out, "%s\n\n%s\n", state.Stats(), r.Render(state))
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/render"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+// runHistory lists the past games archived in a game namespace, or shows
+// the final board and moves of one of them.
+//
+//	gamemaster history --namespace podsweeper-game
+//	gamemaster history --namespace podsweeper-game --game 42-1718000000 --format ansi
+func runHistory(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	id := fs.String("game", "", "The ID of the game to inspect. Empty lists the archived games.")
+	format := fs.String("format", string(render.Plain), "How the board is drawn: plain, ansi or unicode.")
+	_ = fs.Parse(args)
+	f, err := render.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	archive := results.Archive{Client: c, Namespace: *namespace}
+	ctx := context.Background()
+
+	if *id == "" {
+		archived, err := archive.Results(ctx)
+		if err != nil {
+			return err
+		}
+		if len(archived) == 0 {
+			_, err := fmt.Fprintf(out, "No archived game in %s\n", *namespace)
+			return err
+		}
+		for i := len(archived) - 1; i >= 0; i-- {
+			r := archived[i]
+			fmt.Fprintf(out, "%s\t%s\t%s\t%dx%d\t%d clicks\tscore %d\t%s\n", r.GameID, historyTime(r.EndedAt),
+				r.Status, r.Width, r.Height, r.Clicks, r.Score, strings.Join(r.Players, " "))
+		}
+		return nil
+	}
+
+	archived, err := archive.Game(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if archived == nil {
+		return fmt.Errorf("game %s is not archived in %s", *id, *namespace)
+	}
+	r := archived.Result
+	fmt.Fprintf(out, "Game %s: %dx%d level %d %s, %.0f%% revealed, %d clicks, score %d, %s\n\n",
+		r.GameID, r.Width, r.Height, r.Level, r.Status, r.Progress, r.Clicks, r.Score,
+		(time.Duration(r.ElapsedSeconds) * time.Second).String())
+	if state, err := game.FromBoardString(archived.Board); err == nil {
+		fmt.Fprintf(out, "%s\n\n", render.Renderer{Format: f}.Render(state))
+	}
+	for _, m := range archived.Moves {
+		player := m.Player
+		if player == "" {
+			player = "-"
+		}
+		fmt.Fprintf(out, "%d\t(%d,%d)\t%s\t%s\t%s\n", m.Seq, m.X, m.Y, m.Outcome, player, historyTime(m.At))
+	}
+	return nil
+}
+
+// historyTime formats the times of past games, "-" if unknown.
+func historyTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
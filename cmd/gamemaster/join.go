@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/join"
+	"github.com/zwindler/podsweeper/pkg/player"
+)
+
+// joinTimeout bounds join requests to the Gamemaster.
+const joinTimeout = 30 * time.Second
+
+// runJoinCode generates a code other players redeem to join the game, or
+// lists the codes that can still be redeemed.
+//
+//	gamemaster join-code --namespace podsweeper-game --uses 5 --ttl 2h
+//	gamemaster join-code --namespace podsweeper-game --list
+func runJoinCode(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("join-code", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	owner := fs.String("owner", "", "Who hands out the code, shown in the list of codes.")
+	uses := fs.Int("uses", 1, "How many players can join with the code.")
+	ttl := fs.Duration("ttl", join.DefaultTTL, "How long the code can be redeemed.")
+	list := fs.Bool("list", false, "List the codes that can still be redeemed.")
+	_ = fs.Parse(args)
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	invitations := &join.Invitations{Client: c, Namespace: *namespace}
+	ctx := context.Background()
+
+	if *list {
+		codes, err := invitations.Codes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, code := range codes {
+			fmt.Fprintf(out, "%s\t%d use(s) left\texpires %s\t%s\n",
+				code.Code, code.UsesLeft, code.ExpiresAt.Format(time.RFC3339), code.Owner)
+		}
+		return nil
+	}
+
+	code, err := invitations.Create(ctx, *owner, *ttl, *uses)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Join code %s, for %d player(s) until %s:\n"+
+		"  gamemaster join --url <gamemaster metrics URL> --code %s --player <id> --username <username>\n",
+		code.Code, code.UsesLeft, code.ExpiresAt.Format(time.RFC3339), code.Code)
+	return err
+}
+
+// runJoin redeems a join code with the Gamemaster, which registers the
+// player and binds the player Role to their ServiceAccount: joining needs
+// no access to the cluster beyond the Gamemaster metrics endpoint.
+//
+//	gamemaster join --url http://localhost:8080 --code K7QM-2XHD --player alice \
+//	    --username system:serviceaccount:team-a:alice
+func runJoin(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8080", "The Gamemaster metrics endpoint, serving /join.")
+	var req join.Request
+	fs.StringVar(&req.Code, "code", "", "The join code.")
+	fs.StringVar(&req.Player, "player", "", "Your player ID.")
+	fs.StringVar(&req.DisplayName, "display-name", "", "Your display name. Defaults to the player ID.")
+	fs.StringVar(&req.Username, "username", "", "The Kubernetes username you play as, such as "+
+		"system:serviceaccount:<namespace>:<name>.")
+	_ = fs.Parse(args)
+	if req.Code == "" || req.Player == "" || req.Username == "" {
+		return fmt.Errorf("--code, --player and --username are required")
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode join request: %w", err)
+	}
+	httpClient := &http.Client{Timeout: joinTimeout}
+	resp, err := httpClient.Post(strings.TrimSuffix(*url, "/")+"/join", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to reach the Gamemaster: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("join refused: %s", strings.TrimSpace(string(msg)))
+	}
+
+	var p player.Player
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return fmt.Errorf("invalid join response: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "Joined as %s, turn %d\n", p.DisplayName, p.Turn)
+	return err
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// leaderElectionID is the name of the Lease used for leader election.
+	leaderElectionID = "podsweeper-gamemaster"
+
+	// inClusterNamespacePath holds the namespace of the running pod.
+	inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// leaderElectionConfig holds the leader election tuning flags.
+type leaderElectionConfig struct {
+	Enabled       bool
+	Namespace     string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// leaderIdentity returns a unique identity for this replica.
+// The pod name (POD_NAME, injected through the Downward API) is preferred over
+// the hostname so that `kubectl get lease` points directly at the leader pod.
+func leaderIdentity() (string, error) {
+	name := os.Getenv("POD_NAME")
+	if name == "" {
+		var err error
+		name, err = os.Hostname()
+		if err != nil {
+			return "", fmt.Errorf("failed to get hostname: %w", err)
+		}
+	}
+	return name + "_" + string(uuid.NewUUID()), nil
+}
+
+// leaderElectionNamespace resolves the namespace holding the Lease: the flag
+// value, then the namespace the Gamemaster runs in, then the game namespace.
+func leaderElectionNamespace(configured, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	return ownNamespace(fallback)
+}
+
+// ownNamespace returns the namespace the Gamemaster runs in, or fallback
+// when running out of cluster.
+func ownNamespace(fallback string) string {
+	if data, err := os.ReadFile(inClusterNamespacePath); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return fallback
+}
+
+// newLeaderElectionLock builds the Lease lock used for leader election with
+// an identity that includes the pod name.
+func newLeaderElectionLock(config *rest.Config, namespace string, renewDeadline time.Duration) (resourcelock.Interface, error) {
+	id, err := leaderIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	config = rest.AddUserAgent(rest.CopyConfig(config), "leader-election")
+	// Keep a single hung request from forcing a leader loss
+	if renewDeadline > 0 {
+		config.Timeout = max(renewDeadline/2, time.Second)
+	}
+
+	corev1Client, err := corev1client.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	coordinationClient, err := coordinationv1client.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourcelock.New(resourcelock.LeasesResourceLock,
+		namespace,
+		leaderElectionID,
+		corev1Client,
+		coordinationClient,
+		resourcelock.ResourceLockConfig{Identity: id},
+	)
+}
@@ -4,21 +4,56 @@
 // - Tracking game state (mines, revealed cells, level)
 // - Handling game logic (BFS propagation, victory/defeat detection)
 // - Running the admission webhook for advanced levels
+//
+// Usage:
+//
+//	gamemaster [flags]
+//	gamemaster manifests [--namespace podsweeper-game] | kubectl apply -f -
+//	gamemaster whatif [--namespace podsweeper-game] --x 3 --y 4
+//	gamemaster resign [--namespace podsweeper-game]
+//	gamemaster board [--namespace podsweeper-game] [--format ansi]
+//	gamemaster history [--namespace podsweeper-game] [--game <id>]
+//	gamemaster shard --selector podsweeper.io/workshop=kubecon
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"flag"
+	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/analysis"
+	"github.com/zwindler/podsweeper/pkg/api/v1alpha1"
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/join"
+	"github.com/zwindler/podsweeper/pkg/level"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/schedule"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 var (
@@ -28,20 +63,361 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+}
+
+// profileUsage documents the --profile flag of the commands spawning pods.
+const profileUsage = "Resource profile of the game pods: default, or tiny for laptops and tiny clusters " +
+	"(pause-image cells never scheduled, smaller boards, end-of-game pods exiting after their message)."
+
+// stringSliceFlag is a repeatable string flag.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// bindPort returns the port of a bind address like ":8080".
+func bindPort(addr string) (int32, error) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bind address %q: %w", addr, err)
+	}
+	n, err := strconv.ParseInt(port, 10, 32)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid port in bind address %q", addr)
+	}
+	return int32(n), nil
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "manifests" {
+		if err := runManifests(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to generate manifests: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "whatif" {
+		if err := runWhatIf(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to simulate move: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "analysis" {
+		if err := runAnalysis(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to analyze the game: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "board" {
+		if err := runBoard(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to show the board: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		if err := runHistory(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to browse past games: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "resign" {
+		if err := runResign(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to resign: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "shard" {
+		if err := runShard(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to run sharded gamemaster: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "powerup" {
+		if err := runPowerUp(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to use power-up: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "save" {
+		if err := runSave(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to save the game: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "load" {
+		if err := runLoad(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to load the game: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "join-code" {
+		if err := runJoinCode(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to manage join codes: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "join" {
+		if err := runJoin(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to join the game: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "saves" {
+		if err := runSaves(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to manage save slots: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var probeAddr string
 	var namespace string
-	var enableLeaderElection bool
+	var leaderElection leaderElectionConfig
+	var showVersion bool
+	var heartbeatInterval time.Duration
+	var playersConfigMap string
+	var messagesConfigMap string
+	var messagesLocale string
+	var ratingsConfigMap string
+	var autoplayInterval time.Duration
+	var autoplayLose bool
+	var autoplaySkill float64
+	var gremlinInterval time.Duration
+	var driftInterval time.Duration
+	var speedrunTimerInterval time.Duration
+	var namespaceSummaryInterval time.Duration
+	var hintDecayInterval time.Duration
+	var spectatorDelay time.Duration
+	var defusalTimeout time.Duration
+	var gameLock bool
+	var cellMetrics bool
+	var gameLockDuration time.Duration
+	var networkPolicies bool
+	var gameLevel int
+	var auditWebhook bool
+	var hintGuard bool
+	var hintGuardAllowed stringSliceFlag
+	var hintScrambler bool
+	var moveAttribution bool
+	var webhookFreezeLevel int
+	var levelCRD bool
+	var newGame bool
+	var webhookPort int
+	var webhookCertDir string
+	var webhookSelfSigned bool
+	var webhookCertManager bool
+	var webhookMode string
+	var webhookService string
+	var webhookConfigurations stringSliceFlag
+	var hintAggregator bool
+	var expose controller.ExposeConfig
+	var exposeUI bool
+	var protectedSelectors stringSliceFlag
+	var protectedNamePatterns stringSliceFlag
+	var hookURLs stringSliceFlag
+	var profile spawner.Profile
+	var gameCluster controller.KubeconfigSecretCluster
+	var boardNamespace string
+	var scheduleSpec string
+	var scheduleDifficulty string
+	var scheduleTimezone string
+	var joinCodes bool
+	var execRevealURL string
+	var archiveGames bool
+	var metricsAuth bool
+	var debugShowMines bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.BoolVar(&metricsAuth, "metrics-auth", false,
+		"Require scrapers of /metrics to authenticate with a token allowed to get the /metrics URL, such as one bound to "+
+			"the "+rbac.MetricsReaderName+" ClusterRole, since game metrics leak progress. The board and the other "+
+			"endpoints of the metrics server are unaffected. See `gamemaster manifests --metrics-auth`.")
+	flag.BoolVar(&debugShowMines, "debug-show-mines", false,
+		"Cheat: show the mines of games in progress in the /debug/state dump of the game state. They are only shown "+
+			"once the game is over otherwise.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&showVersion, "version", false, "Print version information and exit.")
 	flag.StringVar(&namespace, "namespace", game.DefaultNamespace, "The namespace to watch for game pods.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+	flag.StringVar(&playersConfigMap, "players-configmap", player.DefaultPlayersConfigMap,
+		"ConfigMap in the game namespace mapping Kubernetes usernames to player display names and colors.")
+	flag.StringVar(&messagesConfigMap, "messages-configmap", messages.DefaultMessagesConfigMap,
+		"ConfigMap in the game namespace overriding the messages of the game pods, such as the victory banner, with "+
+			"Go templates. Built-in messages are used when missing.")
+	flag.StringVar(&messagesLocale, "messages-locale", "",
+		"Locale of the messages, such as fr: messages keyed \"victory.fr\" in --messages-configmap take precedence "+
+			"over \"victory\".")
+	flag.StringVar(&ratingsConfigMap, "ratings-configmap", player.DefaultRatingsConfigMap,
+		"ConfigMap in the game namespace persisting player ratings.")
+	flag.DurationVar(&autoplayInterval, "autoplay-interval", 0,
+		"Demo mode: let the Gamemaster play the game itself, one cell per interval. 0 disables autoplay.")
+	flag.Float64Var(&autoplaySkill, "autoplay-skill", 0,
+		"Let the autoplayer play fair as a bot, never looking at the mines, with this chance from 0 to 1 to spot "+
+			"each cell proven safe. 0 keeps the demo autoplayer, which never loses.")
+	flag.BoolVar(&autoplayLose, "autoplay-lose", false,
+		"Demo mode: click a mine instead of the last safe cell, to show the explosion.")
+	flag.DurationVar(&gremlinInterval, "gremlin-interval", 0,
+		"Chaos mode: a gremlin deletes a random unrevealed pod every interval, mines included. 0 disables the gremlin.")
+	flag.DurationVar(&driftInterval, "drift-interval", controller.DefaultDriftInterval,
+		"How often game pods are compared with the game state and restored. 0 disables drift correction.")
+	flag.DurationVar(&speedrunTimerInterval, "speedrun-timer-interval", controller.DefaultSpeedrunTimerInterval,
+		"How often the timer pod of speedrun games shows the elapsed time. 0 disables the timer pod.")
+	flag.DurationVar(&namespaceSummaryInterval, "namespace-summary-interval", controller.DefaultNamespaceSummaryInterval,
+		"How often the status, progress, remaining mines, score and play time of the game are written as annotations "+
+			"of the game namespace, for `kubectl describe ns`. 0 disables the summary.")
+	flag.DurationVar(&hintDecayInterval, "hint-decay-interval", controller.DefaultHintDecayInterval,
+		"How often the expired hints of games in hint decay mode are removed. 0 leaves hint pods to stop on their own.")
+	flag.DurationVar(&defusalTimeout, "defusal-timeout", controller.DefaultDefusalTimeout,
+		"How long players have to cut the right wire of a bomb in games played in defusal mode.")
+	flag.DurationVar(&spectatorDelay, "spectator-delay", 0,
+		"Serve /board this far behind the live game, such as 30s, so spectators can't help the players. "+
+			"0 serves the live board.")
+	flag.BoolVar(&networkPolicies, "network-policies", true,
+		"Apply the obstacles of the game level: guard hint pods with NetworkPolicies from level 5, so only player pods "+
+			"can reach them, and leak the mines in the "+game.CheatConfigMapName+" ConfigMap at level 0.")
+	flag.IntVar(&gameLevel, "level", -1,
+		"Level of the games, when every game of the Gamemaster is played at the same level, as for `gamemaster "+
+			"manifests --level`. Level 0 games are kept in the "+game.CheatConfigMapName+" ConfigMap, where players "+
+			"can read them as the intended cheat, instead of the state Secret. Negative for games of any level.")
+	flag.BoolVar(&hintAggregator, "hint-aggregator", false,
+		"Serve hints from the hint aggregator Deployment at /hint/X/Y instead of one pod per hint, "+
+			"for very large boards. Deploy it with `gamemaster manifests --hint-aggregator`.")
+	flag.BoolVar(&exposeUI, "expose-ui", false,
+		"Create the podsweeper-ui Service in the Gamemaster namespace, so spectators can reach /board and the "+
+			"leaderboard without port-forwarding. Gamemaster pods must be labelled app.kubernetes.io/name=podsweeper "+
+			"and app.kubernetes.io/component=gamemaster.")
+	flag.StringVar(&expose.IngressHost, "ui-ingress-host", "",
+		"Also create an Ingress for this host routing the spectator endpoints to the UI Service. Implies --expose-ui.")
+	flag.StringVar(&expose.IngressClass, "ui-ingress-class", "",
+		"IngressClass of the UI Ingress. Defaults to the cluster default class.")
+	flag.StringVar(&expose.TLSSecret, "ui-ingress-tls-secret", "",
+		"Secret holding the TLS certificate of the UI Ingress host. Empty serves plain HTTP.")
+	flag.BoolVar(&auditWebhook, "audit-webhook", false,
+		"Serve /audit on the metrics endpoint for the API server audit webhook backend, "+
+			"and taint the score of players reading the game state outside of their level.")
+	flag.BoolVar(&hintGuard, "hint-guard", false,
+		fmt.Sprintf("Serve the %s validating admission webhook on the webhook server, refusing the deletion of hint pods "+
+			"from level %d. Register it in a ValidatingWebhookConfiguration for the DELETE of pods.",
+			controller.HintGuardPath, controller.HintGuardLevel))
+	flag.Var(&hintGuardAllowed, "hint-guard-allow",
+		"Username always allowed to delete hint pods by --hint-guard, besides the Gamemaster and Kubernetes components. "+
+			"Can be repeated.")
+	flag.BoolVar(&hintScrambler, "hint-scrambler", false,
+		fmt.Sprintf("Serve the %s mutating admission webhook on the webhook server, scrambling the hints of hint pods "+
+			"from level %d so they can only be read from their agent. Register it in a MutatingWebhookConfiguration "+
+			"for the CREATE of pods.", controller.HintScramblerPath, controller.HintScrambleLevel))
+	flag.BoolVar(&moveAttribution, "move-attribution", false,
+		fmt.Sprintf("Serve the %s validating admission webhook on the webhook server, attributing moves to the users "+
+			"deleting cell pods for per-player statistics. Register it in a ValidatingWebhookConfiguration for the "+
+			"DELETE of pods, with failurePolicy Ignore.", controller.MoveAttributorPath))
+	flag.IntVar(&webhookFreezeLevel, "webhook-freeze-level", 0,
+		"With --move-attribution, freeze the games of this level and higher while cell pods are deleted without "+
+			"going through the attribution webhook, until it is healthy again. 0 never freezes games: their moves "+
+			"are only recorded unattributed.")
+	flag.BoolVar(&levelCRD, "level-crd", false,
+		"Play the PodSweeperLevels of the game namespace, levels defined declaratively on top of or in place of "+
+			"the built-in ones. Print their CustomResourceDefinition with `gamemaster manifests --crds`.")
+	flag.BoolVar(&newGame, "new-game-annotation", false,
+		fmt.Sprintf("Start a new game when the game namespace is annotated with %s=<difficulty>, archiving the "+
+			"current one, then remove the annotation.", controller.AnnotationNewGame))
+	flag.IntVar(&webhookPort, "webhook-port", webhook.DefaultPort, "The port the webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"The directory holding the tls.crt and tls.key of the webhook server, reloaded when they change, such as a "+
+			"mounted Secret renewed by cert-manager. Defaults to a directory under the system temporary directory.")
+	flag.StringVar(&webhookMode, "webhook-mode", string(controller.WebhookEnforce),
+		"Whether the admission webhooks enforce their decisions: enforce, or shadow to only log and count in "+
+			"podsweeper_webhook_shadow_decisions_total what they would have denied or mutated, to try level 5+ "+
+			"obstacles on an existing cluster.")
+	flag.BoolVar(&webhookSelfSigned, "webhook-self-signed", false,
+		"Serve the webhook server with a self-signed CA kept in the "+rbac.WebhookName+" Secret of the Gamemaster "+
+			"namespace instead of --webhook-cert-dir, patch it into the caBundle of the webhook configurations, and "+
+			"rotate the certificates before they expire, for clusters without cert-manager.")
+	flag.BoolVar(&webhookCertManager, "webhook-cert-manager", false,
+		"Serve the webhook server with the certificate cert-manager issues into the "+rbac.WebhookName+" Secret, "+
+			"mounted in --webhook-cert-dir, and renewed without restarting the Gamemaster. cert-manager injects its "+
+			"CA into the webhook configurations; print them with `gamemaster manifests --webhook-cert-manager`.")
+	flag.StringVar(&webhookService, "webhook-service", rbac.WebhookName,
+		"The Service of the webhook server in the Gamemaster namespace, which self-signed certificates are issued for.")
+	flag.Var(&webhookConfigurations, "webhook-configuration",
+		"Name of the Validating and MutatingWebhookConfigurations self-signed CAs are patched into. Repeatable. "+
+			"Defaults to "+rbac.WebhookName+".")
+	flag.DurationVar(&heartbeatInterval, "heartbeat-interval", controller.DefaultHeartbeatInterval,
+		"How often the game clock is stamped; longer gaps are excluded from play time as downtime.")
+	flag.BoolVar(&cellMetrics, "cell-metrics", true,
+		"Export the board as podsweeper_cell_state metrics, one per cell, for Grafana heatmaps. Hidden mines are not exported.")
+	flag.BoolVar(&gameLock, "game-lock", true,
+		"Lock the game with a Lease so that a second Gamemaster deployed by accident can't play it too.")
+	flag.DurationVar(&gameLockDuration, "game-lock-duration", game.DefaultLockDuration,
+		"How long the game stays locked by a Gamemaster that stopped renewing its Lease.")
+	flag.BoolVar(&leaderElection.Enabled, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElection.Namespace, "leader-elect-namespace", "",
+		"The namespace holding the leader election Lease. Defaults to the Gamemaster's own namespace.")
+	flag.DurationVar(&leaderElection.LeaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration non-leader candidates wait before trying to acquire leadership.")
+	flag.DurationVar(&leaderElection.RenewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration the leader retries refreshing leadership before giving up.")
+	flag.DurationVar(&leaderElection.RetryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration clients wait between leader election attempts.")
+	flag.Var(&profile, "profile", profileUsage)
+	flag.StringVar(&gameCluster.Name, "game-kubeconfig-secret", "",
+		"Play the board in the cluster of the kubeconfig in this Secret of --namespace, such as the vc-<name> Secret "+
+			"of a vcluster. The game state stays in --namespace.")
+	flag.StringVar(&gameCluster.Key, "game-kubeconfig-key", controller.DefaultKubeconfigKey,
+		"Key of the kubeconfig in the --game-kubeconfig-secret Secret.")
+	flag.StringVar(&gameCluster.Server, "game-kubeconfig-server", "",
+		"Replaces the server of the --game-kubeconfig-secret kubeconfig, such as with the in-cluster Service of a vcluster.")
+	flag.StringVar(&boardNamespace, "board-namespace", game.DefaultNamespace,
+		"The namespace of the board in the cluster of --game-kubeconfig-secret.")
+	flag.StringVar(&scheduleSpec, "schedule", "",
+		"Cron expression starting a new game on schedule, such as \"0 9 * * 1\" every Monday at 09:00. "+
+			"The game it replaces is archived. Disabled when empty.")
+	flag.StringVar(&scheduleDifficulty, "schedule-difficulty", string(grid.DifficultyExpert),
+		"Difficulty preset of the games started by --schedule.")
+	flag.StringVar(&scheduleTimezone, "schedule-timezone", "UTC",
+		"Time zone of --schedule, such as Europe/Paris.")
+	flag.BoolVar(&joinCodes, "join", false,
+		"Serve /join, registering the players redeeming the codes of \"gamemaster join-code\" and binding the player "+
+			"Role to their ServiceAccounts.")
+	flag.BoolVar(&archiveGames, "archive", true,
+		"Archive finished games with their board and moves in the "+results.DefaultArchiveConfigMap+" ConfigMap, "+
+			"browsable with `gamemaster history` once the namespace moved on to other games.")
+	flag.StringVar(&execRevealURL, "exec-reveal-url", "",
+		"Let players reveal cells by exec'ing \"hint-agent reveal\" into them instead of deleting them, for clusters "+
+			"granting exec but not delete: cells run the cell agent, which calls /reveal at this URL of the Gamemaster "+
+			"metrics endpoint, such as http://podsweeper-ui.podsweeper-system:8080. Not supported by the tiny profile.")
+	flag.Var(&hookURLs, "hook-url",
+		"URL the game events (gameStart, reveal, mineHit, victory, flag) are posted to as JSON, for lights, buzzers or "+
+			"chat bots. Can be repeated.")
+	flag.Var(&protectedSelectors, "protected-selector",
+		"Label selector of pods the Gamemaster must never touch. Can be repeated.")
+	flag.Var(&protectedNamePatterns, "protected-name-pattern",
+		"Regular expression matching names of pods the Gamemaster must never touch. Can be repeated.")
+
+	naming := game.Naming()
+	naming.BindFlags(flag.CommandLine)
+	tuning := controller.DefaultTuning()
+	tuning.BindFlags(flag.CommandLine)
 
 	opts := zap.Options{
 		Development: true,
@@ -49,28 +425,327 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	if showVersion {
+		fmt.Println(version.Get())
+		os.Exit(0)
+	}
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if err := game.SetNamingScheme(naming); err != nil {
+		setupLog.Error(err, "invalid pod naming scheme")
+		os.Exit(1)
+	}
+
+	protected, err := controller.NewProtectionRules(protectedSelectors, protectedNamePatterns)
+	if err != nil {
+		setupLog.Error(err, "invalid protected pod configuration")
+		os.Exit(1)
+	}
+
+	if webhookCertManager && (webhookSelfSigned || webhookCertDir == "") {
+		setupLog.Error(fmt.Errorf("--webhook-cert-manager requires --webhook-cert-dir, without --webhook-self-signed"),
+			"invalid webhook certificates")
+		os.Exit(1)
+	}
+	admissionMode, err := controller.ParseWebhookMode(webhookMode)
+	if err != nil {
+		setupLog.Error(err, "invalid webhook mode")
+		os.Exit(1)
+	}
+
+	if execRevealURL != "" && profile == spawner.ProfileTiny {
+		setupLog.Error(fmt.Errorf("tiny cells are never scheduled"), "exec reveal is not supported by the tiny profile")
+		os.Exit(1)
+	}
+
+	scheduler, err := newGameScheduler(scheduleSpec, scheduleDifficulty, scheduleTimezone, profile)
+	if err != nil {
+		setupLog.Error(err, "invalid game schedule")
+		os.Exit(1)
+	}
+
+	restConfig := tuning.RestConfig(ctrl.GetConfigOrDie())
+
+	// The board, status badge, leaderboard, flag checks, what-if simulation,
+	// results export, post-game analysis, state dump and audit webhook are
+	// served by the metrics server, which is configured before the manager
+	// exists, so they use their own uncached client
+	apiClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create API client")
+		os.Exit(1)
+	}
+	rbacConfig := rbac.Config{
+		Namespace:         namespace,
+		PlayersConfigMap:  playersConfigMap,
+		MessagesConfigMap: messagesConfigMap,
+		RatingsConfigMap:  ratingsConfigMap,
+		HintAggregator:    hintAggregator,
+		KubeconfigSecret:  gameCluster.Name,
+		Archive:           archiveGames || scheduler != nil,
+		MetricsAuth:       metricsAuth,
+		NamespaceSummary:  namespaceSummaryInterval > 0,
+		HintScrambler:     hintScrambler,
+		LevelCRD:          levelCRD,
+		NewGame:           newGame,
+	}
+	if len(webhookConfigurations) == 0 {
+		webhookConfigurations = stringSliceFlag{rbac.WebhookName}
+	}
+	if webhookSelfSigned {
+		rbacConfig.WebhookCertsNamespace = ownNamespace(namespace)
+		rbacConfig.WebhookConfigurations = webhookConfigurations
+	}
+	if joinCodes {
+		rbacConfig.JoinCodesSecret = join.DefaultSecret
+	}
+	checkRBAC(context.Background(), apiClient, rbacConfig)
+
+	// The board may be played in another cluster than the game state is
+	// kept in, such as the vcluster of the game
+	boardConfig, boardClient := restConfig, apiClient
+	if gameCluster.Name != "" {
+		gameCluster.Reader = apiClient
+		config, err := gameCluster.RestConfig(context.Background(), namespace)
+		if err != nil {
+			setupLog.Error(err, "unable to connect to the game cluster")
+			os.Exit(1)
+		}
+		boardConfig = tuning.RestConfig(config)
+		if boardClient, err = client.New(boardConfig, client.Options{Scheme: scheme}); err != nil {
+			setupLog.Error(err, "unable to create game cluster client")
+			os.Exit(1)
+		}
+	} else {
+		boardNamespace = namespace
+	}
+
+	players := player.NewRegistry()
+	ratings := player.NewRatingStore(apiClient, namespace, ratingsConfigMap, players)
+
+	apiStore := level.NewStore(apiClient, namespace, gameLevel)
+	board := controller.BoardHandler(apiStore)
+	var delayedBoard *controller.DelayedBoard
+	if spectatorDelay > 0 {
+		delayedBoard = &controller.DelayedBoard{Store: apiStore, Delay: spectatorDelay}
+		board = delayedBoard.Handler()
+	}
+	extraHandlers := map[string]http.Handler{
+		"/version":     version.Handler(),
+		"/board":       board,
+		"/badge.svg":   controller.BadgeHandler(apiStore),
+		"/whatif":      controller.WhatIfHandler(apiStore),
+		"/leaderboard": ratings.LeaderboardHandler(),
+		"/flag":        controller.FlagHandler(apiStore),
+		"/export":      results.Handler(results.StoreHistory{Store: apiStore, Namespace: namespace}),
+		"/analysis":    analysis.Handler(apiStore),
+		"/debug/state": controller.DebugStateHandler(apiStore, debugShowMines),
+	}
+	if joinCodes {
+		extraHandlers["/join"] = (&join.Invitations{
+			Client:           apiClient,
+			Namespace:        namespace,
+			PlayersConfigMap: playersConfigMap,
+			Registry:         players,
+		}).Handler()
+	}
+	if execRevealURL != "" {
+		extraHandlers["/reveal"] = (&controller.ExecReveal{Client: boardClient, Namespace: boardNamespace}).Handler()
+	}
+	if auditWebhook {
+		extraHandlers["/audit"] = (&controller.CheatDetector{Store: apiStore, Namespace: namespace}).Handler()
+	}
+
+	mgrOptions := ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "podsweeper-gamemaster",
-	})
+		Metrics: metricsserver.Options{
+			BindAddress:   metricsAddr,
+			ExtraHandlers: extraHandlers,
+		},
+		LeaderElection:   leaderElection.Enabled,
+		LeaderElectionID: leaderElectionID,
+		LeaseDuration:    &leaderElection.LeaseDuration,
+		RenewDeadline:    &leaderElection.RenewDeadline,
+		RetryPeriod:      &leaderElection.RetryPeriod,
+		// Only cache objects from the game namespace instead of the whole
+		// cluster, and only the state Secret so its Role can name it
+		Cache: tuning.CacheOptions(controller.StateSecretCacheOptions(controller.NamespacedCacheOptions(boardNamespace), game.DefaultSecretName)),
+	}
+	if newGame {
+		// The game namespace is cluster-scoped, watch it alone
+		mgrOptions.Cache = controller.GameNamespaceCacheOptions(mgrOptions.Cache, boardNamespace)
+	}
+
+	var certManager *controller.CertManager
+	if hintGuard || hintScrambler || moveAttribution {
+		webhookOptions := webhook.Options{Port: webhookPort, CertDir: webhookCertDir}
+		if webhookSelfSigned {
+			certManager = &controller.CertManager{
+				Client:         apiClient,
+				WebhookClient:  boardClient,
+				Namespace:      ownNamespace(namespace),
+				Service:        webhookService,
+				Configurations: webhookConfigurations,
+			}
+			// Serve the certificates before the webhook server starts, so
+			// the first admission requests don't fail
+			if err := certManager.Ensure(context.Background()); err != nil {
+				setupLog.Error(err, "unable to set up webhook certificates")
+				os.Exit(1)
+			}
+			webhookOptions.TLSOpts = []func(*tls.Config){certManager.TLSOpt}
+		}
+		mgrOptions.WebhookServer = webhook.NewServer(webhookOptions)
+	}
+
+	if metricsAuth {
+		mgrOptions.Metrics.FilterProvider = metricsAuthFilter(restConfig)
+	}
+
+	if leaderElection.Enabled {
+		lock, err := newLeaderElectionLock(restConfig,
+			leaderElectionNamespace(leaderElection.Namespace, namespace),
+			leaderElection.RenewDeadline)
+		if err != nil {
+			setupLog.Error(err, "unable to create leader election lock")
+			os.Exit(1)
+		}
+		mgrOptions.LeaderElectionResourceLockInterface = lock
+	}
+
+	mgr, err := ctrl.NewManager(boardConfig, mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to create manager")
 		os.Exit(1)
 	}
 
-	// Create game state store (persisted in Kubernetes Secret)
-	store := game.NewSecretStore(mgr.GetClient(),
-		game.WithNamespace(namespace),
-	)
+	// Let spectators reach the board without port-forwarding
+	if exposeUI || expose.IngressHost != "" {
+		port, err := bindPort(metricsAddr)
+		if err != nil {
+			setupLog.Error(err, "unable to expose the board")
+			os.Exit(1)
+		}
+		expose.Namespace = ownNamespace(namespace)
+		expose.Port = port
+		if err := mgr.Add(&controller.Exposer{Client: apiClient, Config: expose}); err != nil {
+			setupLog.Error(err, "unable to set up board exposure")
+			os.Exit(1)
+		}
+	}
+
+	// Create game state store (persisted in Kubernetes Secret, or in the
+	// cheat ConfigMap at level 0), out of the cache when the board is played
+	// in another cluster or the ConfigMap, only read by name, keeps it
+	stateClient := mgr.GetClient()
+	if gameCluster.Name != "" || level.ConfigMapStored(gameLevel) {
+		stateClient = apiClient
+	}
+	store := level.NewStore(stateClient, namespace, gameLevel)
+
+	// Only the Gamemaster holding the game lock changes its state
+	var lock *game.Lock
+	if gameLock {
+		identity, err := leaderIdentity()
+		if err != nil {
+			setupLog.Error(err, "unable to create game lock")
+			os.Exit(1)
+		}
+		// Leases are read directly rather than cached in the game namespace
+		lock = &game.Lock{
+			Client:    mgr.GetClient(),
+			Reader:    mgr.GetAPIReader(),
+			Namespace: boardNamespace,
+			Identity:  identity,
+			Duration:  gameLockDuration,
+		}
+		if err := mgr.Add(lock); err != nil {
+			setupLog.Error(err, "unable to set up game lock")
+			os.Exit(1)
+		}
+		store = game.NewLockedStore(store, lock)
+	}
+
+	// Render the board in Grafana; the live board would spoil the spectator delay
+	if cellMetrics && spectatorDelay > 0 {
+		setupLog.Info("cell metrics disabled by the spectator delay")
+	} else if cellMetrics {
+		if err := (&controller.CellStateCollector{Store: store}).Register(); err != nil {
+			setupLog.Error(err, "unable to export cell metrics")
+			os.Exit(1)
+		}
+	}
+
+	// Load the player registry; the cache is not started yet so read directly
+	playersKey := client.ObjectKey{Namespace: namespace, Name: playersConfigMap}
+	if err := players.LoadFromConfigMap(context.Background(), apiClient, playersKey); err != nil {
+		setupLog.Error(err, "unable to load player registry, using default display names")
+	}
+	catalog := messages.NewCatalog(messagesLocale)
+	messagesKey := client.ObjectKey{Namespace: namespace, Name: messagesConfigMap}
+	if err := catalog.LoadFromConfigMap(context.Background(), apiClient, messagesKey); err != nil {
+		setupLog.Error(err, "unable to load custom messages, using built-in messages")
+	}
+
+	// The Gamemaster may only read the hints ConfigMap by name, so it isn't cached
+	var aggregator *controller.HintAggregator
+	if hintAggregator {
+		aggregator = controller.NewHintAggregator(boardClient, boardNamespace)
+	}
+
+	// Games are only started with every permission they need
+	selfCheck := &controller.SelfCheck{
+		Client:    boardClient,
+		Namespace: boardNamespace,
+		Required:  rbac.GamemasterRules(rbacConfig),
+	}
+	if err := mgr.Add(selfCheck); err != nil {
+		setupLog.Error(err, "unable to set up RBAC self-check")
+		os.Exit(1)
+	}
+
+	var archive *results.Archive
+	if archiveGames {
+		archive = &results.Archive{Client: apiClient, Namespace: namespace}
+	}
+
+	var hooks controller.Hooks
+	if len(hookURLs) > 0 {
+		hooks = &controller.HTTPHooks{URLs: hookURLs}
+	}
+
+	// Moves of in-process players are claimed to be attributed to them
+	claims := controller.NewMoveClaims()
+
+	// Deletions bypassing the attribution webhook are detected
+	var webhookHealth *controller.WebhookHealth
+	if moveAttribution {
+		webhookHealth = &controller.WebhookHealth{FreezeLevel: webhookFreezeLevel}
+	}
 
 	// Create and register the game controller
 	gameController := controller.NewGameController(mgr.GetClient(), controller.GameControllerConfig{
-		Namespace: namespace,
-		Store:     store,
+		Namespace:       boardNamespace,
+		Store:           store,
+		Protected:       protected,
+		Players:         players,
+		Ratings:         ratings,
+		Recorder:        mgr.GetEventRecorder("podsweeper-gamemaster"),
+		Claims:          claims,
+		NetworkPolicies: networkPolicies,
+		HintAggregator:  aggregator,
+		DefusalTimeout:  defusalTimeout,
+		Lock:            lock,
+		RateLimiter:     tuning.RateLimiter(),
+		SelfCheck:       selfCheck,
+		Profile:         profile,
+		RevealURL:       execRevealURL,
+		Archive:         archive,
+		Hooks:           hooks,
+		Messages:        catalog,
+		Webhook:         webhookHealth,
 	})
 
 	if err := gameController.SetupWithManager(mgr); err != nil {
@@ -78,7 +753,204 @@ func main() {
 		os.Exit(1)
 	}
 
-	// TODO: Set up admission webhook (for levels 5+)
+	// Levels can be defined declaratively by game organizers
+	presets := grid.NewPresetRegistry()
+	if levelCRD {
+		if err := (&controller.LevelReconciler{
+			Client:    mgr.GetClient(),
+			Namespace: boardNamespace,
+			Presets:   presets,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "LevelReconciler")
+			os.Exit(1)
+		}
+	}
+
+	// Games can be started with kubectl annotate alone
+	if newGame {
+		if err := (&controller.NewGameReconciler{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Archive:   archive,
+			Namespace: boardNamespace,
+			Presets:   presets,
+			Profile:   profile,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NewGameReconciler")
+			os.Exit(1)
+		}
+	}
+
+	// Spawn the boards provisioners leave Pending, such as in a vcluster
+	if err := mgr.Add(&controller.BoardSpawner{
+		Client:          mgr.GetClient(),
+		Store:           store,
+		Namespace:       boardNamespace,
+		Handlers:        gameController.Handlers,
+		Spawner:         gameController.Spawner,
+		CreateNamespace: gameCluster.Name != "",
+	}); err != nil {
+		setupLog.Error(err, "unable to set up board spawner")
+		os.Exit(1)
+	}
+
+	// Recurring games, such as a weekly office competition
+	if scheduler != nil {
+		scheduler.Store = store
+		scheduler.Archive = &results.Archive{Client: apiClient, Namespace: namespace}
+		scheduler.Namespace = namespace
+		if err := mgr.Add(scheduler); err != nil {
+			setupLog.Error(err, "unable to set up game schedule")
+			os.Exit(1)
+		}
+		setupLog.Info("scheduled games", "schedule", scheduleSpec, "difficulty", scheduleDifficulty,
+			"next", scheduler.Next())
+	}
+
+	// Exclude Gamemaster downtime from the game clock
+	if err := mgr.Add(&controller.Heartbeat{Store: store, Interval: heartbeatInterval}); err != nil {
+		setupLog.Error(err, "unable to set up heartbeat")
+		os.Exit(1)
+	}
+
+	// Demo mode: play the game at a steady pace for screens at booths
+	if autoplayInterval > 0 {
+		if err := mgr.Add(&controller.Autoplayer{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Namespace: boardNamespace,
+			Interval:  autoplayInterval,
+			LoseAtEnd: autoplayLose,
+			Skill:     autoplaySkill,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up autoplay")
+			os.Exit(1)
+		}
+	}
+
+	// Restore game pods deleted or edited by hand
+	if driftInterval > 0 {
+		if err := mgr.Add(&controller.DriftCorrector{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Namespace: boardNamespace,
+			Handlers:  gameController.Handlers,
+			Spawner: spawner.NewGridSpawner(mgr.GetClient(), spawner.GridSpawnerConfig{
+				Namespace: boardNamespace,
+				Profile:   profile,
+				RevealURL: execRevealURL,
+				Messages:  catalog,
+			}),
+			Protected: protected,
+			Interval:  driftInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up drift correction")
+			os.Exit(1)
+		}
+	}
+
+	// Spectators watch the board behind the live game
+	if delayedBoard != nil {
+		if err := mgr.Add(delayedBoard); err != nil {
+			setupLog.Error(err, "unable to set up the delayed spectator board")
+			os.Exit(1)
+		}
+	}
+
+	// Speedruns show their clock on a timer pod
+	if speedrunTimerInterval > 0 {
+		if err := mgr.Add(&controller.SpeedrunTimer{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Namespace: boardNamespace,
+			Interval:  speedrunTimerInterval,
+			Messages:  catalog,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up speedrun timer")
+			os.Exit(1)
+		}
+	}
+
+	// kubectl describe ns shows the score
+	if namespaceSummaryInterval > 0 {
+		if err := mgr.Add(&controller.NamespaceSummary{
+			Client:    apiClient,
+			Store:     store,
+			Namespace: namespace,
+			Interval:  namespaceSummaryInterval,
+			Webhook:   webhookHealth,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up namespace summary")
+			os.Exit(1)
+		}
+	}
+
+	// Hints of games in hint decay mode expire
+	if hintDecayInterval > 0 {
+		if err := mgr.Add(&controller.HintDecayer{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Namespace: boardNamespace,
+			Handlers:  gameController.Handlers,
+			Interval:  hintDecayInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up hint decay")
+			os.Exit(1)
+		}
+	}
+
+	// Chaos mode: the gremlin races the players
+	if gremlinInterval > 0 {
+		if err := mgr.Add(&controller.Gremlin{
+			Client:    mgr.GetClient(),
+			Store:     store,
+			Namespace: boardNamespace,
+			Claims:    claims,
+			Interval:  gremlinInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up gremlin")
+			os.Exit(1)
+		}
+	}
+
+	if certManager != nil {
+		if err := mgr.Add(certManager); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate rotation")
+			os.Exit(1)
+		}
+	}
+
+	// Moves are attributed to the players deleting cell pods
+	if moveAttribution {
+		mgr.GetWebhookServer().Register(controller.MoveAttributorPath, &webhook.Admission{
+			Handler: &controller.MoveAttributor{Claims: claims, Namespace: boardNamespace, Health: webhookHealth},
+		})
+	}
+
+	// Players can't erase the hints they revealed from level 5
+	if hintGuard {
+		mgr.GetWebhookServer().Register(controller.HintGuardPath, &webhook.Admission{
+			Handler: admissionMode.Handler(controller.HintGuardPath, &controller.HintGuard{
+				Store:      store,
+				Namespace:  boardNamespace,
+				Gamemaster: "system:serviceaccount:" + ownNamespace(namespace) + ":" + rbac.GamemasterName,
+				Allowed:    hintGuardAllowed,
+			}),
+		})
+	}
+
+	// Players must ask hint agents for their hint from level 7
+	if hintScrambler {
+		key, err := hints.LoadScrambleKey(context.Background(), boardClient, boardNamespace)
+		if err != nil {
+			setupLog.Error(err, "unable to load the hint scramble key")
+			os.Exit(1)
+		}
+		mgr.GetWebhookServer().Register(controller.HintScramblerPath, &webhook.Admission{
+			Handler: admissionMode.Handler(controller.HintScramblerPath,
+				&controller.HintScrambler{Store: store, Namespace: boardNamespace, Key: key}),
+		})
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -88,10 +960,18 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("rbac", selfCheck.ReadyzCheck); err != nil {
+		setupLog.Error(err, "unable to set up RBAC ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting gamemaster",
+		"version", version.Version,
+		"commit", version.Commit,
+		"buildDate", version.BuildDate,
 		"namespace", namespace,
 		"probeAddr", probeAddr,
+		"leaderElection", leaderElection.Enabled,
 	)
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -99,3 +979,28 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newGameScheduler returns the scheduler of the games of spec, nil if
+// empty. Its store and archive are left to set.
+func newGameScheduler(spec, difficulty, timezone string, profile spawner.Profile) (*controller.GameScheduler, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	sched, err := schedule.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule time zone: %w", err)
+	}
+	config, ok := grid.NewPresetRegistry().Get(grid.DifficultyPreset(difficulty))
+	if !ok {
+		return nil, fmt.Errorf("unknown schedule difficulty %q", difficulty)
+	}
+	return &controller.GameScheduler{
+		Schedule: sched,
+		Location: loc,
+		Config:   profile.Board(config),
+	}, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/api/v1alpha1"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/join"
+	"github.com/zwindler/podsweeper/pkg/level"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// runManifests prints the Gamemaster and player RBAC, and the hint
+// aggregator, image pre-pull DaemonSet and cert-manager webhook
+// configurations when enabled, ready for kubectl apply, and the CustomResourceDefinitions of the game with --crds. With
+// --shard-namespace, it prints the RBAC of sharded Gamemasters
+// running there instead.
+//
+//	gamemaster manifests --namespace podsweeper-game | kubectl apply -f -
+//	gamemaster manifests --crds --level-crd | kubectl apply -f -
+//	gamemaster manifests --webhook-cert-manager podsweeper-system --move-attribution | kubectl apply -f -
+//	gamemaster manifests --prepull-namespace podsweeper-system | kubectl apply -f -
+//	gamemaster manifests --shard-namespace podsweeper-system | kubectl apply -f -
+func runManifests(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("manifests", flag.ExitOnError)
+	var cfg rbac.Config
+	fs.StringVar(&cfg.Namespace, "namespace", game.DefaultNamespace, "The game namespace.")
+	fs.StringVar(&cfg.PlayersConfigMap, "players-configmap", player.DefaultPlayersConfigMap,
+		"ConfigMap in the game namespace mapping Kubernetes usernames to players.")
+	fs.StringVar(&cfg.MessagesConfigMap, "messages-configmap", messages.DefaultMessagesConfigMap,
+		"ConfigMap in the game namespace overriding the messages of the game pods.")
+	fs.StringVar(&cfg.RatingsConfigMap, "ratings-configmap", player.DefaultRatingsConfigMap,
+		"ConfigMap in the game namespace persisting player ratings.")
+	fs.StringVar(&cfg.LeaderElectionNamespace, "leader-elect-namespace", "",
+		"Namespace of the leader election Lease. Leave empty when leader election is disabled.")
+	fs.StringVar(&cfg.UINamespace, "ui-namespace", "",
+		"Namespace the Gamemaster runs in, to allow it to expose the board there. Leave empty without --expose-ui.")
+	fs.BoolVar(&cfg.HintAggregator, "hint-aggregator", false,
+		"Include the hint aggregator Deployment and Service, for a Gamemaster run with --hint-aggregator.")
+	fs.BoolVar(&cfg.HintScrambler, "hint-scrambler", false,
+		"Let the Gamemaster read the key hints are scrambled with, and register the hint scrambler webhook with "+
+			"--webhook-cert-manager, for a Gamemaster run with --hint-scrambler.")
+	fs.BoolVar(&cfg.Archive, "archive", true,
+		"Let the Gamemaster archive finished games, for a Gamemaster run with --archive or --schedule.")
+	fs.BoolVar(&cfg.MetricsAuth, "metrics-auth", false,
+		"Let the Gamemaster authenticate the scrapers of its metrics, for a Gamemaster run with --metrics-auth. "+
+			"Bind scrapers to the "+rbac.MetricsReaderName+" ClusterRole.")
+	fs.BoolVar(&cfg.NamespaceSummary, "namespace-summary", true,
+		"Let the Gamemaster annotate the game namespace with the summary of the game, for a Gamemaster run with "+
+			"--namespace-summary-interval.")
+	fs.BoolVar(&cfg.NewGame, "new-game", false,
+		"Let the Gamemaster watch the game namespace for new game requests, for a Gamemaster run with "+
+			"--new-game-annotation.")
+	fs.BoolVar(&cfg.LevelCRD, "level-crd", false,
+		"Let the Gamemaster read the PodSweeperLevels of the game namespace, for a Gamemaster run with --level-crd.")
+	crds := fs.Bool("crds", false,
+		"Also print the CustomResourceDefinitions of the game, such as PodSweeperLevel. They are cluster-wide.")
+	fs.StringVar(&cfg.WebhookCertsNamespace, "webhook-certs-namespace", "",
+		"Namespace the Gamemaster runs in, to let it keep self-signed webhook certificates there and patch their CA "+
+			"into the webhook configurations, for a Gamemaster run with --webhook-self-signed.")
+	var webhookConfigurations stringSliceFlag
+	fs.Var(&webhookConfigurations, "webhook-configuration",
+		"Name of the webhook configurations self-signed CAs are patched into. Repeatable. Defaults to "+rbac.WebhookName+".")
+	var webhooks controller.WebhookManifests
+	fs.StringVar(&webhooks.ServiceNamespace, "webhook-cert-manager", "",
+		"Namespace the Gamemaster runs in: also print a cert-manager Certificate for its webhook Service there, "+
+			"issued into the "+rbac.WebhookName+" Secret to mount in --webhook-cert-dir, and the webhook "+
+			"configurations of --move-attribution, --hint-guard and --hint-scrambler, whose caBundle cert-manager "+
+			"injects, for a Gamemaster run with --webhook-cert-manager.")
+	fs.StringVar(&webhooks.ClusterIssuer, "webhook-cluster-issuer", "",
+		"cert-manager ClusterIssuer issuing the webhook certificate. Defaults to a self-signed Issuer.")
+	fs.StringVar(&webhooks.Service, "webhook-service", rbac.WebhookName,
+		"The Service of the webhook server in the Gamemaster namespace, which certificates are issued for.")
+	fs.BoolVar(&webhooks.MoveAttribution, "move-attribution", false,
+		"Register the move attribution webhook, for a Gamemaster run with --move-attribution.")
+	fs.BoolVar(&webhooks.HintGuard, "hint-guard", false,
+		"Register the hint guard webhook, for a Gamemaster run with --hint-guard.")
+	joinCodes := fs.Bool("join", false,
+		"Let the Gamemaster register players redeeming join codes, for a Gamemaster run with --join.")
+	defusal := fs.Bool("defusal", false,
+		"Let players exec into the bomb pods of games played in defusal mode.")
+	execReveal := fs.Bool("exec-reveal", false,
+		"Let players exec into cells to reveal them, for a Gamemaster run with --exec-reveal-url.")
+	prepullNamespace := fs.String("prepull-namespace", "",
+		"Also print a DaemonSet pulling the game images on every node in this namespace, such as the Gamemaster "+
+			"namespace, so new boards don't wait for image pulls. Keep it out of the game namespace.")
+	lvl := fs.Int("level", level.Intern,
+		"Level of the games, whose policy restricts the player Role: players can't exec into pods from level "+
+			strconv.Itoa(level.Window)+".")
+	shardNamespace := fs.String("shard-namespace", "",
+		"Namespace running `gamemaster shard` replicas: print their cluster-wide RBAC instead.")
+	_ = fs.Parse(args)
+	cfg.WebhookConfigurations = webhookConfigurations
+	webhooks.Namespace = cfg.Namespace
+	webhooks.HintScrambler = cfg.HintScrambler
+	if *joinCodes {
+		cfg.JoinCodesSecret = join.DefaultSecret
+	}
+
+	labels := map[string]string{spawner.LabelApp: "podsweeper"}
+	if *shardNamespace != "" {
+		return writeManifests(out, rbac.ShardObjects(*shardNamespace, labels))
+	}
+	policy := level.For(*lvl)
+	exec := *defusal || *execReveal
+	if exec && policy.RestrictsRBAC() {
+		return fmt.Errorf("players can't exec into pods at level %d (%s), required by --defusal and --exec-reveal",
+			policy.Level(), policy.Name())
+	}
+	var objs []client.Object
+	if *crds {
+		objs = append(objs, v1alpha1.LevelCRD(labels))
+	}
+	objs = append(objs, rbac.GamemasterObjects(cfg, labels)...)
+	objs = append(objs, rbac.PlayerRoleObjects(cfg.Namespace, labels, policy.PlayerRules(exec))...)
+	if cfg.HintAggregator {
+		objs = append(objs, hints.Objects(cfg.Namespace, controller.HintAgentImage, labels)...)
+	}
+	if *prepullNamespace != "" {
+		objs = append(objs, spawner.PrepullObjects(*prepullNamespace, labels)...)
+	}
+	if webhooks.ServiceNamespace != "" {
+		objs = append(objs, controller.CertManagerWebhookObjects(webhooks, labels)...)
+	}
+	return writeManifests(out, objs)
+}
+
+// writeManifests writes objects as a multi-document YAML stream.
+func writeManifests(out io.Writer, objs []client.Object) error {
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		docs = append(docs, string(data))
+	}
+	_, err := io.WriteString(out, strings.Join(docs, "---\n"))
+	return err
+}
+
+// checkRBAC logs the Gamemaster's permissions broader than the generated
+// minimal rules. It never stops the Gamemaster: it may run with broader
+// permissions on purpose. Missing permissions are reported by the
+// controller.SelfCheck, which sees every authorizer.
+func checkRBAC(ctx context.Context, c client.Client, cfg rbac.Config) {
+	report, err := rbac.Check(ctx, c, cfg.Namespace, rbac.GamemasterRules(cfg))
+	if err != nil {
+		setupLog.Error(err, "unable to check RBAC permissions")
+		return
+	}
+	if len(report.Broad) > 0 {
+		setupLog.Info("WARNING: RBAC permissions are broader than the game needs; see `gamemaster manifests`",
+			"namespace", cfg.Namespace, "broad", report.Broad)
+	}
+}
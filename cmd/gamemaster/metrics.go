@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// metricsEndpoint is the path of the metrics served by the metrics server.
+const metricsEndpoint = "/metrics"
+
+// metricsAuthFilter returns a filter provider authenticating and
+// authorizing the scrapers of /metrics against the cluster of config, the
+// one the Gamemaster keeps its state in even when the board is played in a
+// vcluster. The board and the other endpoints served alongside keep their
+// own access rules.
+func metricsAuthFilter(config *rest.Config) func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+	return func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+		httpClient, err := rest.HTTPClientFor(config)
+		if err != nil {
+			return nil, err
+		}
+		auth, err := filters.WithAuthenticationAndAuthorization(config, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+			filtered, err := auth(log, handler)
+			if err != nil {
+				return nil, err
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == metricsEndpoint {
+					filtered.ServeHTTP(w, r)
+					return
+				}
+				handler.ServeHTTP(w, r)
+			}), nil
+		}, nil
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/powerup"
+)
+
+// runPowerUp lists the power-ups and the charges left, or uses one by
+// annotating the pod of the targeted cell, as players allowed to patch pods
+// can do with kubectl annotate. Without a target, any hidden cell is
+// annotated, which suits power-ups ignoring it.
+//
+//	gamemaster powerup --namespace podsweeper-game
+//	gamemaster powerup --namespace podsweeper-game --use neutralize --x 3 --y 5
+//	gamemaster powerup --namespace podsweeper-game --use sweep
+func runPowerUp(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("powerup", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	use := fs.String("use", "", "The power-up to use. Lists them when empty.")
+	x := fs.Int("x", -1, "X coordinate of the targeted cell.")
+	y := fs.Int("y", -1, "Y coordinate of the targeted cell.")
+	naming := game.Naming()
+	naming.BindFlags(fs)
+	_ = fs.Parse(args)
+	if err := game.SetNamingScheme(naming); err != nil {
+		return err
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	ctx := context.Background()
+
+	if *use == "" {
+		state, err := game.NewSecretStore(c, game.WithNamespace(*namespace)).Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load game state: %w", err)
+		}
+		if state != nil {
+			fmt.Fprintf(out, "%d charge(s) left, %d/%d safe clicks to the next one\n",
+				state.PowerUps.Charges(), state.PowerUps.Streak, game.StreakPerCharge)
+		}
+		for _, p := range powerup.All() {
+			fmt.Fprintf(out, "%s\t%s\n", p.Name(), p.Description())
+		}
+		return nil
+	}
+	if _, err := powerup.Get(*use); err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{}
+	if *x >= 0 && *y >= 0 {
+		key := client.ObjectKey{Namespace: *namespace, Name: game.Coordinate{X: *x, Y: *y}.PodName()}
+		if err := c.Get(ctx, key, pod); err != nil {
+			return fmt.Errorf("failed to get the targeted pod: %w", err)
+		}
+	} else {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(*namespace)); err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		found := false
+		for i := range pods.Items {
+			if controller.IsPodName(pods.Items[i].Name) && pods.Items[i].DeletionTimestamp.IsZero() {
+				pod, found = &pods.Items[i], true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no hidden cell in namespace %s", *namespace)
+		}
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[powerup.AnnotationPowerUp] = *use
+	if err := c.Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("failed to annotate %s: %w", pod.Name, err)
+	}
+	_, err = fmt.Fprintf(out, "Using %s on %s, see `kubectl events -n %s --for pod/%s`\n", *use, pod.Name, *namespace, pod.Name)
+	return err
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// runResign concedes the game by deleting its resign pod, which players
+// are allowed to do like deleting any cell pod.
+//
+//	gamemaster resign --namespace podsweeper-game
+func runResign(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("resign", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	_ = fs.Parse(args)
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: spawner.ResignPodName, Namespace: *namespace}}
+	if err := c.Delete(context.Background(), pod); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("no game to resign in namespace %s", *namespace)
+		}
+		return fmt.Errorf("failed to delete the resign pod: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "Resigned the game in %s, see `kubectl logs -n %s %s` for the full board\n",
+		*namespace, *namespace, controller.SummaryPodName)
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// runSave suspends the running game into a named save slot, removing its
+// board pods until it is loaded again.
+//
+//	gamemaster save --namespace podsweeper-game --name friday
+func runSave(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("save", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	name := fs.String("name", "", "The save slot.")
+	naming := game.Naming()
+	naming.BindFlags(fs)
+	_ = fs.Parse(args)
+	if err := game.SetNamingScheme(naming); err != nil {
+		return err
+	}
+
+	slots, err := newSaveSlots(*namespace)
+	if err != nil {
+		return err
+	}
+	state, err := slots.Save(context.Background(), *name, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Saved the game of %s to slot %q after %s (%.0f%% cleared), load it with `gamemaster load --name %s`\n",
+		*namespace, *name, state.Elapsed().Round(time.Second), state.Stats().Progress, *name)
+	return err
+}
+
+// runLoad resumes the game of a save slot, respawning its board pods.
+//
+//	gamemaster load --namespace podsweeper-game --name friday
+func runLoad(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("load", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	name := fs.String("name", "", "The save slot.")
+	var profile spawner.Profile
+	fs.Var(&profile, "profile", profileUsage)
+	naming := game.Naming()
+	naming.BindFlags(fs)
+	_ = fs.Parse(args)
+	if err := game.SetNamingScheme(naming); err != nil {
+		return err
+	}
+
+	slots, err := newSaveSlots(*namespace)
+	if err != nil {
+		return err
+	}
+	slots.Spawner = spawner.NewGridSpawner(slots.Client, spawner.GridSpawnerConfig{Namespace: *namespace, Profile: profile})
+	state, err := slots.Load(context.Background(), *name, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Loaded slot %q in %s: %s\n", *name, *namespace, state.Stats())
+	return err
+}
+
+// runSaves lists the save slots of a game namespace, or deletes one.
+//
+//	gamemaster saves --namespace podsweeper-game [--delete friday]
+func runSaves(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("saves", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	remove := fs.String("delete", "", "Delete this save slot instead of listing them.")
+	_ = fs.Parse(args)
+
+	slots, err := newSaveSlots(*namespace)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if *remove != "" {
+		if err := slots.Delete(ctx, *remove); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "Deleted slot %q\n", *remove)
+		return err
+	}
+
+	names, err := slots.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		state, err := game.NewSlotStore(slots.Client, *namespace, name).Load(ctx)
+		if err != nil {
+			fmt.Fprintf(out, "%s\t%v\n", name, err)
+			continue
+		}
+		if state == nil {
+			continue
+		}
+		fmt.Fprintf(out, "%s\tsaved %s\t%s\n", name, state.PausedAt.Format(time.RFC3339), state.Stats())
+	}
+	return nil
+}
+
+// newSaveSlots connects to the cluster for the save slots of namespace.
+func newSaveSlots(namespace string) (*controller.SaveSlots, error) {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return controller.NewSaveSlots(c, namespace), nil
+}
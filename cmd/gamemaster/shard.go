@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/shard"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/version"
+)
+
+// runShard runs a Gamemaster replica sharing the games of many namespaces
+// with the other replicas of its shard group, instead of one Gamemaster
+// per game. Run it as a Deployment with several replicas and the RBAC of
+// `gamemaster manifests --shard-namespace`.
+//
+//	gamemaster shard --selector podsweeper.io/workshop=kubecon
+func runShard(args []string) error {
+	fs := flag.NewFlagSet("shard", flag.ExitOnError)
+	selector := fs.String("selector", "", "Label selector of the game namespaces to play, such as podsweeper.io/workshop=kubecon.")
+	group := fs.String("group", shard.DefaultGroup, "Shard group: replicas of a group share its games.")
+	leaseNamespace := fs.String("lease-namespace", "",
+		"Namespace of the member Leases. Defaults to the namespace the Gamemaster runs in.")
+	leaseDuration := fs.Duration("lease-duration", shard.DefaultLeaseDuration,
+		"How long a replica keeps its games without renewing its Lease.")
+	interval := fs.Duration("interval", controller.DefaultShardInterval, "How often replicas refresh the games they own.")
+	defusalTimeout := fs.Duration("defusal-timeout", controller.DefaultDefusalTimeout,
+		"How long players have to cut the right wire of a bomb in games played in defusal mode.")
+	metricsAddr := fs.String("metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	probeAddr := fs.String("health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	var profile spawner.Profile
+	fs.Var(&profile, "profile", profileUsage)
+	naming := game.Naming()
+	naming.BindFlags(fs)
+	tuning := controller.DefaultTuning()
+	tuning.BindFlags(fs)
+	opts := zap.Options{Development: true}
+	opts.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if err := game.SetNamingScheme(naming); err != nil {
+		return err
+	}
+	games, err := labels.Parse(*selector)
+	if err != nil || games.Empty() {
+		return fmt.Errorf("--selector must select the game namespaces: %q", *selector)
+	}
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		if identity, err = os.Hostname(); err != nil {
+			return fmt.Errorf("failed to get hostname: %w", err)
+		}
+	}
+	leases := *leaseNamespace
+	if leases == "" {
+		leases = ownNamespace("")
+	}
+	if leases == "" {
+		return fmt.Errorf("--lease-namespace is required out of cluster")
+	}
+
+	// Every game namespace is watched, only the state Secrets of games and
+	// the Leases of the group are cached
+	cacheOptions := controller.StateSecretCacheOptions(cache.Options{}, game.DefaultSecretName)
+	cacheOptions.ByObject[&coordinationv1.Lease{}] = cache.ByObject{
+		Namespaces: map[string]cache.Config{leases: {}},
+		Label:      labels.SelectorFromSet(labels.Set{shard.LabelGroup: *group}),
+	}
+	mgr, err := ctrl.NewManager(tuning.RestConfig(ctrl.GetConfigOrDie()), ctrl.Options{
+		Scheme:                 scheme,
+		HealthProbeBindAddress: *probeAddr,
+		Metrics:                metricsserver.Options{BindAddress: *metricsAddr},
+		Cache:                  tuning.CacheOptions(cacheOptions),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create manager: %w", err)
+	}
+
+	membership := &shard.Membership{
+		Client:        mgr.GetClient(),
+		Namespace:     leases,
+		Group:         *group,
+		Identity:      identity,
+		LeaseDuration: *leaseDuration,
+	}
+	if err := mgr.Add(membership); err != nil {
+		return fmt.Errorf("unable to join the shard group: %w", err)
+	}
+	sharded := &controller.ShardedGames{
+		Client:     mgr.GetClient(),
+		Membership: membership,
+		Selector:   games,
+		Interval:   *interval,
+		Config: controller.GameControllerConfig{
+			Recorder:       mgr.GetEventRecorder("podsweeper-gamemaster"),
+			DefusalTimeout: *defusalTimeout,
+			RateLimiter:    tuning.RateLimiter(),
+			Profile:        profile,
+		},
+	}
+	if err := sharded.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create sharded controller: %w", err)
+	}
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up ready check: %w", err)
+	}
+
+	setupLog.Info("starting sharded gamemaster", "version", version.Version, "identity", identity,
+		"group", *group, "selector", games.String())
+	return mgr.Start(ctrl.SetupSignalHandler())
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// runWhatIf prints what deleting a cell pod would do, without touching the game.
+//
+//	gamemaster whatif --namespace podsweeper-game --x 3 --y 4
+func runWhatIf(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("whatif", flag.ExitOnError)
+	namespace := fs.String("namespace", game.DefaultNamespace, "The game namespace.")
+	x := fs.Int("x", -1, "X coordinate of the cell.")
+	y := fs.Int("y", -1, "Y coordinate of the cell.")
+	asJSON := fs.Bool("json", false, "Print the simulation as JSON.")
+	_ = fs.Parse(args)
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	state, err := game.NewSecretStore(c, game.WithNamespace(*namespace)).Load(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil {
+		return fmt.Errorf("no game in namespace %s", *namespace)
+	}
+
+	result := controller.Simulate(state, game.Coordinate{X: *x, Y: *y})
+	if *asJSON {
+		return json.NewEncoder(out).Encode(result)
+	}
+	_, err = fmt.Fprintln(out, result)
+	return err
+}
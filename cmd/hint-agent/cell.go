@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/version"
+)
+
+// revealTimeout bounds reveal requests to the Gamemaster.
+const revealTimeout = 10 * time.Second
+
+// runCell waits in a cell pod until it is revealed or deleted.
+func runCell() {
+	log.Printf("PodSweeper cell %s ready (x=%s, y=%s, game=%s), reveal it with: hint-agent reveal",
+		version.Version, os.Getenv("POD_X"), os.Getenv("POD_Y"), os.Getenv("GAME_ID"))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+}
+
+// runReveal asks the Gamemaster to reveal the cell this agent runs in.
+func runReveal(out io.Writer) error {
+	url := os.Getenv("GAMEMASTER_URL")
+	if url == "" {
+		return fmt.Errorf("GAMEMASTER_URL is not set, this game isn't played with exec")
+	}
+	x, errX := strconv.Atoi(os.Getenv("POD_X"))
+	y, errY := strconv.Atoi(os.Getenv("POD_Y"))
+	if errX != nil || errY != nil {
+		return fmt.Errorf("invalid coordinates %q, %q", os.Getenv("POD_X"), os.Getenv("POD_Y"))
+	}
+
+	body, err := json.Marshal(map[string]int{"x": x, "y": y})
+	if err != nil {
+		return fmt.Errorf("failed to encode reveal request: %w", err)
+	}
+	httpClient := &http.Client{Timeout: revealTimeout}
+	resp, err := httpClient.Post(strings.TrimSuffix(url, "/")+"/reveal", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to reach the Gamemaster: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("reveal refused: %s", strings.TrimSpace(string(msg)))
+	}
+	_, err = fmt.Fprint(out, string(msg))
+	return err
+}
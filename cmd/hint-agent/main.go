@@ -1,12 +1,26 @@
 // Package main is the entry point for the PodSweeper Hint Agent.
 // The Hint Agent is a minimal HTTP server that runs inside hint pods.
-// It exposes the hint value (number of adjacent mines) via HTTP.
+// It exposes the hint value (number of adjacent mines, or hot, warm or
+// cold in hot/cold games) via HTTP.
 //
-// Configuration via environment variables:
-//   - HINT_VALUE: The number to display (0-8)
+// Configuration via environment variables, which the Gamemaster sets from
+// the labels and annotations of the pod with the Downward API:
+//   - HINT_VALUE: The hint to display: a number (0-8), or a temperature
 //   - POD_X: The X coordinate of this pod
 //   - POD_Y: The Y coordinate of this pod
+//   - GAME_ID: The game this pod belongs to
 //   - PORT: The port to listen on (default: 8080)
+//   - THEME: The theme the hint is rendered in (default: classic)
+//   - HINT_KEY: The key HINT_VALUE is scrambled with from the level
+//     scrambling hints, read from a Secret players can't read
+//   - HINTS_DIR: Run as the hint aggregator instead, serving every hint
+//     mounted in this directory at /hint/X/Y
+//
+// The Hint Agent doubles as the cell agent of exec reveal games: "hint-agent
+// cell" waits in the cell pod, and players reveal the cell with
+// "kubectl exec pod-X-Y -- hint-agent reveal", which asks the Gamemaster at
+// GAMEMASTER_URL to play the POD_X and POD_Y cell. "hint-agent prepull"
+// exits at once: the pre-pull DaemonSet only runs it to pull the image.
 package main
 
 import (
@@ -15,17 +29,41 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "prepull" {
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "cell" {
+		runCell()
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reveal" {
+		if err := runReveal(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to reveal the cell: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Read configuration from environment
-	hintValue := os.Getenv("HINT_VALUE")
+	hintValue, err := hints.Unscramble([]byte(os.Getenv(hints.ScrambleKeyEnv)), os.Getenv("HINT_VALUE"))
+	if err != nil {
+		log.Printf("Unable to read the hint: %v", err)
+	}
 	if hintValue == "" {
 		hintValue = "?"
 	}
+	shown := theme.Of(os.Getenv("THEME")).Hint(hintValue)
 
 	podX := os.Getenv("POD_X")
 	podY := os.Getenv("POD_Y")
+	gameID := os.Getenv("GAME_ID")
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -37,10 +75,26 @@ func main() {
 		log.Fatalf("Invalid PORT value: %s", port)
 	}
 
+	// Aggregator mode: one pod serves the hints of the whole board
+	if dir := os.Getenv("HINTS_DIR"); dir != "" {
+		http.Handle("/hint/", hints.Handler(dir))
+		http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "ok")
+		})
+		http.Handle("/version", version.Handler())
+
+		addr := ":" + port
+		log.Printf("Hint Aggregator %s starting on %s (hints=%s)", version.Version, addr, dir)
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+		return
+	}
+
 	// Create HTTP handler
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprintf(w, "%s\n", hintValue)
+		fmt.Fprintf(w, "%s\n", shown)
 	})
 
 	// Health check endpoint
@@ -49,14 +103,17 @@ func main() {
 		fmt.Fprint(w, "ok")
 	})
 
+	// Version endpoint
+	http.Handle("/version", version.Handler())
+
 	// Info endpoint with coordinates
 	http.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, `{"x":%q,"y":%q,"hint":%q}`, podX, podY, hintValue)
+		fmt.Fprintf(w, `{"x":%q,"y":%q,"hint":%q,"gameId":%q}`, podX, podY, hintValue, gameID)
 	})
 
 	addr := ":" + port
-	log.Printf("Hint Agent starting on %s (hint=%s, x=%s, y=%s)", addr, hintValue, podX, podY)
+	log.Printf("Hint Agent %s starting on %s (hint=%s, x=%s, y=%s, game=%s)", version.Version, addr, hintValue, podX, podY, gameID)
 
 	if err := http.ListenAndServe(addr, nil); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
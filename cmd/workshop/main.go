@@ -0,0 +1,247 @@
+// Package main is the entry point for the PodSweeper workshop provisioner.
+// It sets up one isolated game per participant for classroom settings,
+// serves a dashboard of the whole room to instructors, and tears them all
+// down afterwards. With --race, every participant races a bot playing the
+// mirror image of their board.
+//
+// Usage:
+//
+//	workshop up --name kubecon --games 30 --difficulty easy,medium --out ./kubeconfigs
+//	workshop dashboard --name kubecon --listen :8090
+//	workshop export --name kubecon --format csv --moves > moves.csv
+//	workshop next --name kubecon --games 30 --difficulty easy,medium --adaptive
+//	workshop race --name kubecon --games 30
+//	workshop down --name kubecon
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/vcluster"
+	"github.com/zwindler/podsweeper/pkg/version"
+	"github.com/zwindler/podsweeper/pkg/workshop"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <up|next|race|dashboard|export|down|version> [flags]\n", filepath.Base(os.Args[0]))
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	verb := os.Args[1]
+	if verb == "version" {
+		fmt.Println(version.Get())
+		return
+	}
+
+	fs := flag.NewFlagSet(verb, flag.ExitOnError)
+	name := fs.String("name", "podsweeper-workshop", "Workshop name, prefixing the game namespaces.")
+	games := fs.Int("games", 1, "Number of games (participants) to provision.")
+	difficulties := fs.String("difficulty", string(grid.DifficultyEasy),
+		"Comma-separated difficulty presets, assigned to games in turn.")
+	image := fs.String("gamemaster-image", workshop.DefaultGamemasterImage, "Image of the per-game Gamemaster.")
+	tokenTTL := fs.Duration("token-ttl", workshop.DefaultTokenTTL, "How long participant kubeconfigs stay valid.")
+	out := fs.String("out", "kubeconfigs", "Directory where participant kubeconfigs are written.")
+	timeout := fs.Duration("timeout", 10*time.Minute, "Timeout of the whole operation.")
+	listen := fs.String("listen", ":8090", "Address the dashboard listens on.")
+	format := fs.String("format", results.FormatCSV, "Export format: csv or json.")
+	moves := fs.Bool("moves", false, "Export every move instead of one result per game.")
+	speedrun := fs.Bool("speedrun", false, "Start games in speedrun mode, with a live timer pod and times ranked per seed.")
+	defusal := fs.Bool("defusal", false,
+		"Start games in defusal mode: hitting a mine starts a wire-cutting challenge players exec into instead of losing.")
+	hintDecay := fs.Duration("hint-decay", 0,
+		"Start games in hint decay mode: hints expire that long after they are revealed, so players must remember them. 0 keeps hints.")
+	hintType := fs.String("hints", string(game.HintCount),
+		"What revealed cells tell: count, the number of adjacent mines, or hot-cold, only how close the nearest mine is.")
+	shiftEvery := fs.Int("shift-every", 0,
+		"Start games in shifting mines mode: a hidden mine moves every that many moves, never changing the hints shown. 0 keeps mines in place.")
+	lives := fs.Int("lives", 0,
+		"Start games in multi-life mode: hitting a mine costs one of that many lives instead of the game, which is lost with the last one. 0 plays with a single life.")
+	timeLimit := fs.Duration("time-limit", 0,
+		"Start games in timed mode: a game still in progress after that long is lost. 0 plays without limit.")
+	sharded := fs.Bool("sharded", false,
+		"Don't deploy a Gamemaster per game: games are played by `gamemaster shard --selector "+workshop.LabelWorkshop+"=<name>` replicas.")
+	vclusters := fs.Bool("vcluster", false,
+		"Play each game in its own vcluster, giving participants a whole cluster as cluster-admin. Needs a Gamemaster per game.")
+	adaptive := fs.Bool("adaptive", false,
+		"Tune the density and size of new boards to each participant's recent wins and times.")
+	profile := fs.String("profile", string(spawner.ProfileDefault),
+		"Resource profile of the games: default, or tiny for laptops and tiny clusters (smaller boards, unscheduled pause-image cells).")
+	race := fs.Bool("race", false,
+		"Race each participant against a bot playing the mirror image of their board in a <namespace>"+workshop.BotSuffix+" namespace.")
+	botSkill := fs.Float64("bot-skill", workshop.DefaultBotSkill,
+		"Chance, from 0 to 1, of bots to spot each cell the hints prove safe. Bots never see the mines.")
+	botInterval := fs.Duration("bot-interval", workshop.DefaultBotInterval, "Pace of bots, one cell per interval.")
+	gameTheme := fs.String("theme", string(theme.Classic), "Accessibility theme of the games: classic, high-contrast, colorblind or emoji.")
+	naming := game.Naming()
+	naming.BindFlags(fs)
+	_ = fs.Parse(os.Args[2:])
+	// The Gamemasters are started with the same scheme
+	if err := game.SetNamingScheme(naming); err != nil {
+		fail("invalid pod naming scheme", err)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		fail("unable to create client", err)
+	}
+
+	if verb == "dashboard" {
+		dashboard := workshop.NewDashboard(c, *name)
+		fmt.Printf("Serving the %s dashboard on %s\n", *name, *listen)
+		server := &http.Server{Addr: *listen, Handler: dashboard.Handler(), ReadHeaderTimeout: 10 * time.Second}
+		if err := server.ListenAndServe(); err != nil {
+			fail("dashboard stopped", err)
+		}
+		return
+	}
+
+	if verb == "export" {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		if err := results.Write(ctx, os.Stdout, workshop.NewDashboard(c, *name), *format, *moves); err != nil {
+			fail("export failed", err)
+		}
+		return
+	}
+
+	caData, err := caBundle(restConfig)
+	if err != nil {
+		fail("unable to read the API server CA", err)
+	}
+
+	var presets []grid.DifficultyPreset
+	for _, d := range strings.Split(*difficulties, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			presets = append(presets, grid.DifficultyPreset(d))
+		}
+	}
+
+	p, err := workshop.NewProvisioner(c, workshop.Config{
+		Name:            *name,
+		Games:           *games,
+		Difficulties:    presets,
+		Speedrun:        *speedrun,
+		Defusal:         *defusal,
+		HintDecay:       *hintDecay,
+		ShiftEvery:      *shiftEvery,
+		Lives:           *lives,
+		TimeLimit:       *timeLimit,
+		HintType:        game.HintType(*hintType),
+		Sharded:         *sharded,
+		VCluster:        *vclusters,
+		Theme:           theme.Theme(*gameTheme),
+		Adaptive:        *adaptive,
+		Race:            *race,
+		BotSkill:        *botSkill,
+		BotInterval:     *botInterval,
+		Profile:         spawner.Profile(*profile),
+		GamemasterImage: *image,
+		TokenTTL:        *tokenTTL,
+		Server:          restConfig.Host,
+		CAData:          caData,
+	})
+	if err != nil {
+		fail("invalid workshop", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch verb {
+	case "up":
+		seats, err := p.Up(ctx)
+		for _, seat := range seats {
+			if err := writeKubeconfig(*out, seat); err != nil {
+				fail("unable to write kubeconfig", err)
+			}
+		}
+		if err != nil {
+			fail("provisioning failed", err)
+		}
+		fmt.Printf("Provisioned %d games, kubeconfigs written to %s\n", len(seats), *out)
+		if *vclusters {
+			fmt.Printf("Participants reach their vcluster with:\n"+
+				"  kubectl get secret %s -o jsonpath='{.data.config}' | base64 -d > vcluster.kubeconfig\n"+
+				"  kubectl port-forward svc/%s 8443:443 &\n"+
+				"  export KUBECONFIG=vcluster.kubeconfig\n",
+				vcluster.KubeconfigSecret(vcluster.DefaultName), vcluster.DefaultName)
+		}
+	case "next":
+		started, err := p.Next(ctx)
+		for _, ns := range started {
+			fmt.Printf("Started a new game in %s\n", ns)
+		}
+		if err != nil {
+			fail("starting the next games failed", err)
+		}
+		fmt.Printf("Started %d new games\n", len(started))
+	case "race":
+		races, err := p.Races(ctx)
+		if err != nil {
+			fail("refereeing the races failed", err)
+		}
+		for _, r := range races {
+			fmt.Printf("%s\t%s\thuman %s in %s\tbot %s in %s\n", r.Namespace, r.Verdict,
+				r.Human.Status, r.Human.Elapsed().Round(time.Second), r.Bot.Status, r.Bot.Elapsed().Round(time.Second))
+		}
+	case "down":
+		deleted, err := p.Down(ctx)
+		if err != nil {
+			fail("teardown failed", err)
+		}
+		fmt.Printf("Deleted %d game namespaces\n", deleted)
+	default:
+		usage()
+	}
+}
+
+// caBundle returns the CA data of the rest config, reading its CA file if needed.
+func caBundle(config *rest.Config) ([]byte, error) {
+	if len(config.CAData) > 0 || config.CAFile == "" {
+		return config.CAData, nil
+	}
+	return os.ReadFile(config.CAFile)
+}
+
+// writeKubeconfig writes a participant kubeconfig, readable by its owner only.
+func writeKubeconfig(dir string, seat workshop.Seat) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, seat.Namespace+".kubeconfig"), seat.Kubeconfig, 0o600)
+}
+
+func fail(msg string, err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
+	os.Exit(1)
+}
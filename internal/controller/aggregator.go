@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+)
+
+// HintAggregator publishes hints to the ConfigMap served by the hint
+// aggregator Deployment instead of spawning hint pods.
+type HintAggregator struct {
+	client    client.Client
+	namespace string
+}
+
+// NewHintAggregator creates a HintAggregator for the game namespace. The
+// client should not be cached: the Gamemaster may only read the hints
+// ConfigMap by name.
+func NewHintAggregator(c client.Client, namespace string) *HintAggregator {
+	return &HintAggregator{client: c, namespace: namespace}
+}
+
+// Sync makes the hints ConfigMap serve the hints of state. It rewrites
+// every hint so that the hints of a previous game never leak into the next,
+// and drops the hints decayed by now.
+func (a *HintAggregator) Sync(ctx context.Context, state *game.GameState) error {
+	data := hints.Values(state, time.Now())
+
+	cm := &corev1.ConfigMap{}
+	err := a.client.Get(ctx, client.ObjectKey{Namespace: a.namespace, Name: hints.Name}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hints.Name,
+				Namespace: a.namespace,
+				Labels: map[string]string{
+					LabelApp:       "podsweeper",
+					LabelComponent: "hints",
+				},
+			},
+			Data: data,
+		}
+		if err := a.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create hints configmap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get hints configmap: %w", err)
+	}
+
+	if maps.Equal(cm.Data, data) {
+		return nil
+	}
+	cm.Data = data
+	if err := a.client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update hints configmap: %w", err)
+	}
+	return nil
+}
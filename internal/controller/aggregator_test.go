@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+)
+
+func getHints(t *testing.T, c client.Client) map[string]string {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: hints.Name, Namespace: testNamespace}, cm); err != nil {
+		t.Fatalf("failed to get hints configmap: %v", err)
+	}
+	return cm.Data
+}
+
+func TestHintAggregator_Sync(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	a := NewHintAggregator(c, testNamespace)
+
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	if err := a.Sync(ctx, state); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if data := getHints(t, c); len(data) != 1 || data["0-0"] != "1" {
+		t.Errorf("expected the hint of (0,0), got %v", data)
+	}
+
+	// A new game replaces the hints of the previous one
+	next := createTestGameState(3)
+	next.Reveal(2, 2)
+	if err := a.Sync(ctx, next); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if data := getHints(t, c); len(data) != 1 || data["2-2"] != "1" {
+		t.Errorf("expected only the hint of (2,2), got %v", data)
+	}
+}
+
+func TestGameHandlers_HintAggregatorSpawnsNoHintPods(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(5)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	handlers := NewGameHandlers(c, store, testNamespace)
+	handlers.aggregator = NewHintAggregator(c, testNamespace)
+
+	if _, err := handlers.HandleHintCell(ctx, state, game.Coordinate{X: 0, Y: 1}, 1); err != nil {
+		t.Fatalf("HandleHintCell failed: %v", err)
+	}
+	if _, err := handlers.HandleEmptyCell(ctx, state, game.Coordinate{X: 4, Y: 4}); err != nil {
+		t.Fatalf("HandleEmptyCell failed: %v", err)
+	}
+
+	for name := range remainingCells(t, c) {
+		if IsHintPodName(name) {
+			t.Errorf("expected no hint pod with the aggregator, found %s", name)
+		}
+	}
+	if data := getHints(t, c); data["0-1"] != "1" || data["2-2"] != "1" {
+		t.Errorf("expected the hints of (0,1) and the flood fill boundary to be published, got %v", data)
+	}
+}
+
+func TestDriftCorrector_RepublishesAggregatedHints(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+
+	var objs []client.Object
+	for _, obj := range spawnTestCells(state) {
+		if obj.GetName() != "pod-0-0" {
+			objs = append(objs, obj)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objs...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	d := newTestDriftCorrector(c, store)
+	d.Handlers.aggregator = NewHintAggregator(c, testNamespace)
+
+	drift, err := d.Correct(ctx)
+	if err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	if !drift.IsEmpty() {
+		t.Errorf("expected no hint pods to be missing with the aggregator, got %+v", drift)
+	}
+	if data := getHints(t, c); data["0-0"] != "1" {
+		t.Errorf("expected the hint of (0,0) to be republished, got %v", data)
+	}
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+// MoveAttributorPath is the path the MoveAttributor is served at by the
+// webhook server.
+const MoveAttributorPath = "/attribute-moves"
+
+// MoveAttributor is a validating admission webhook attributing moves to
+// the players deleting cell pods: it claims the move for the user of the
+// admission request, so the GameController records it in the move log
+// and per-player statistics. It never refuses anything. Register it for
+// the DELETE of pods in the game namespace, with failurePolicy Ignore so
+// a missing Gamemaster never blocks players:
+//
+//	mgr.GetWebhookServer().Register(MoveAttributorPath, &webhook.Admission{Handler: attributor})
+//
+// Claims are kept in memory, so with several Gamemaster replicas only the
+// deletions admitted by the leader are attributed. Every cell pod deletion
+// admitted is recorded in Health, to detect those bypassing the webhook.
+// Deletions by the Gamemaster, which claims its own moves, by Kubernetes
+// components and dry-run deletions are not claimed.
+type MoveAttributor struct {
+	// Claims attributes the moves, shared with the GameController.
+	Claims *MoveClaims
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Health detects webhook outages, shared with the GameController.
+	// Optional.
+	Health *WebhookHealth
+}
+
+// Handle allows an admission request, claiming the move it deletes.
+func (a *MoveAttributor) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete || req.Resource.Resource != "pods" || req.Namespace != a.Namespace ||
+		(req.DryRun != nil && *req.DryRun) {
+		return admission.Allowed("")
+	}
+	coords, ok := ParsePodName(req.Name)
+	if !ok {
+		return admission.Allowed("")
+	}
+	a.Health.Admitted(coords)
+	if gameComponent(a.Namespace, req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+	a.Claims.Claim(coords, req.UserInfo.Username)
+	log.FromContext(ctx).V(1).Info("attributed move", "coords", coords, "player", req.UserInfo.Username)
+	return admission.Allowed("")
+}
+
+// gameComponent reports whether username is the Gamemaster of the game
+// namespace or a Kubernetes component, rather than a player.
+// ServiceAccounts of the game namespace other than the Gamemaster are
+// players, as CheatDetector.ignored considers them.
+func gameComponent(namespace, username string) bool {
+	if username == serviceAccountUsername(namespace, rbac.GamemasterName) {
+		return true
+	}
+	return strings.HasPrefix(username, "system:") &&
+		!strings.HasPrefix(username, serviceAccountUsername(namespace, ""))
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestMoveAttributor(t *testing.T) {
+	ctx := context.Background()
+	cell := game.Coordinate{X: 1, Y: 2}
+
+	tests := []struct {
+		name    string
+		user    string
+		pod     string
+		dryRun  bool
+		claimed bool
+	}{
+		{"player", "bob", cell.PodName(), false, true},
+		{"player service account", "system:serviceaccount:" + testNamespace + ":player", cell.PodName(), false, true},
+		{"dry-run", "bob", cell.PodName(), true, false},
+		{"hint pod", "bob", cell.HintPodName(), false, false},
+		{"gamemaster", "system:serviceaccount:" + testNamespace + ":podsweeper-gamemaster", cell.PodName(), false, false},
+		{"kubernetes component", "system:serviceaccount:kube-system:namespace-controller", cell.PodName(), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := NewMoveClaims()
+			attributor := &MoveAttributor{Claims: claims, Namespace: testNamespace}
+			if resp := attributor.Handle(ctx, deleteRequest(tt.user, tt.pod, tt.dryRun)); !resp.Allowed {
+				t.Fatalf("expected the deletion allowed, got %v", resp.Result)
+			}
+			by, ok := claims.Take(cell)
+			if ok != tt.claimed || (ok && by != tt.user) {
+				t.Errorf("Take() = %q, %v, want claimed %v by %q", by, ok, tt.claimed, tt.user)
+			}
+		})
+	}
+}
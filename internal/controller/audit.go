@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/level"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// SecretLevel is the only level where reading the state Secret is part of the game.
+	SecretLevel = level.Junior
+
+	// CheatSheetLevel is the only level where reading the cheat ConfigMap is part of the game.
+	CheatSheetLevel = level.Intern
+
+	// maxAuditBatch bounds the size of an audit webhook request.
+	maxAuditBatch = 10 << 20
+)
+
+// AuditEvent is the part of an audit.k8s.io/v1 Event the CheatDetector reads.
+type AuditEvent struct {
+	Stage                    string           `json:"stage"`
+	Verb                     string           `json:"verb"`
+	RequestURI               string           `json:"requestURI"`
+	User                     AuditUser        `json:"user"`
+	ObjectRef                *AuditObjectRef  `json:"objectRef,omitempty"`
+	ResponseStatus           *metav1.Status   `json:"responseStatus,omitempty"`
+	RequestReceivedTimestamp metav1.MicroTime `json:"requestReceivedTimestamp"`
+}
+
+// DryRun reports whether event is a dry-run request, which the API server
+// audits like any other but never persists.
+func (e AuditEvent) DryRun() bool {
+	u, err := url.ParseRequestURI(e.RequestURI)
+	return err == nil && len(u.Query()["dryRun"]) > 0
+}
+
+// AuditUser identifies who made an audited request.
+type AuditUser struct {
+	Username string `json:"username"`
+}
+
+// AuditObjectRef identifies the object of an audited request.
+type AuditObjectRef struct {
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// AuditEventList is the body of audit webhook requests.
+type AuditEventList struct {
+	Items []AuditEvent `json:"items"`
+}
+
+// CheatDetector reads API server audit events and records players reading
+// the state Secret or the cheat ConfigMap outside of the levels where
+// finding them is the game. Cheaters are recorded in the game state and
+// their score is tainted.
+type CheatDetector struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// StateSecret is the Secret holding the game state. Defaults to game.DefaultSecretName.
+	StateSecret string
+
+	// CheatConfigMap is the ConfigMap exposing the board at level 0.
+	// Defaults to game.CheatConfigMapName.
+	CheatConfigMap string
+
+	// Ignored lists usernames never considered cheating, in addition to the
+	// Gamemaster ServiceAccount and Kubernetes components.
+	Ignored []string
+}
+
+// Cheat returns the resource a player cheated by reading in event, if any.
+// Only successful reads are considered, and reading every object of a kind
+// (a list without a name) counts as reading the object. Dry-run requests,
+// sent by tools and GitOps controllers to validate changes, never count.
+func (d *CheatDetector) Cheat(state *game.GameState, event AuditEvent) (string, bool) {
+	ref := event.ObjectRef
+	if event.Stage != "ResponseComplete" || ref == nil || ref.Namespace != d.Namespace || d.ignored(event.User.Username) {
+		return "", false
+	}
+	if event.DryRun() {
+		return "", false
+	}
+	if event.Verb != "get" && event.Verb != "list" && event.Verb != "watch" {
+		return "", false
+	}
+	if event.ResponseStatus == nil || event.ResponseStatus.Code < 200 || event.ResponseStatus.Code > 299 {
+		return "", false
+	}
+
+	secret, configMap := d.StateSecret, d.CheatConfigMap
+	if secret == "" {
+		secret = game.DefaultSecretName
+	}
+	if configMap == "" {
+		configMap = game.CheatConfigMapName
+	}
+	leak := level.For(state.Level).Leak()
+	switch {
+	case ref.Resource == "secrets" && leak != level.LeakSecret && (ref.Name == "" || ref.Name == secret):
+		return "secrets/" + secret, true
+	case ref.Resource == "configmaps" && leak != level.LeakConfigMap && (ref.Name == "" || ref.Name == configMap):
+		return "configmaps/" + configMap, true
+	}
+	return "", false
+}
+
+// ignored reports whether username is the Gamemaster, a Kubernetes
+// component or explicitly ignored. ServiceAccounts of the game namespace,
+// like the workshop player, are never ignored implicitly.
+func (d *CheatDetector) ignored(username string) bool {
+	if username == serviceAccountUsername(d.Namespace, rbac.GamemasterName) {
+		return true
+	}
+	for _, u := range d.Ignored {
+		if u == username {
+			return true
+		}
+	}
+	return strings.HasPrefix(username, "system:") &&
+		!strings.HasPrefix(username, serviceAccountUsername(d.Namespace, ""))
+}
+
+// Process records the cheats found in events and returns how many are new.
+// Events of ended games are ignored.
+func (d *CheatDetector) Process(ctx context.Context, events []AuditEvent) (int, error) {
+	state, err := d.Store.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return 0, nil
+	}
+
+	logger := log.FromContext(ctx)
+	recorded := 0
+	for _, event := range events {
+		resource, ok := d.Cheat(state, event)
+		if !ok {
+			continue
+		}
+		at := event.RequestReceivedTimestamp.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if state.RecordCheat(event.User.Username, resource, at) {
+			recorded++
+			logger.Info("caught a cheater", "player", event.User.Username, "read", resource, "level", state.Level)
+		}
+	}
+	if recorded == 0 {
+		return 0, nil
+	}
+	if err := d.Store.Save(ctx, state); err != nil {
+		return 0, fmt.Errorf("failed to save game state: %w", err)
+	}
+	return recorded, nil
+}
+
+// Handler receives audit events from the API server audit webhook backend.
+func (d *CheatDetector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "send audit events with POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var list AuditEventList
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxAuditBatch)).Decode(&list); err != nil {
+			http.Error(w, "invalid audit event list", http.StatusBadRequest)
+			return
+		}
+		if _, err := d.Process(r.Context(), list.Items); err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to process audit events")
+			http.Error(w, "failed to process audit events", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// serviceAccountUsername returns the username of a ServiceAccount, or the
+// prefix of all ServiceAccounts of the namespace when name is empty.
+func serviceAccountUsername(namespace, name string) string {
+	return "system:serviceaccount:" + namespace + ":" + name
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func auditRead(user, verb, resource, name string, code int32) AuditEvent {
+	return AuditEvent{
+		Stage:          "ResponseComplete",
+		Verb:           verb,
+		User:           AuditUser{Username: user},
+		ObjectRef:      &AuditObjectRef{Resource: resource, Namespace: testNamespace, Name: name},
+		ResponseStatus: &metav1.Status{Code: code},
+	}
+}
+
+func TestCheatDetector_Cheat(t *testing.T) {
+	d := &CheatDetector{Namespace: testNamespace, Ignored: []string{"admin"}}
+
+	tests := []struct {
+		name  string
+		level int
+		event AuditEvent
+		want  string
+	}{
+		{"secret outside level 1", 3, auditRead("bob", "get", "secrets", game.DefaultSecretName, 200), "secrets/" + game.DefaultSecretName},
+		{"secret at level 1", SecretLevel, auditRead("bob", "get", "secrets", game.DefaultSecretName, 200), ""},
+		{"listing secrets", 3, auditRead("bob", "list", "secrets", "", 200), "secrets/" + game.DefaultSecretName},
+		{"other secret", 3, auditRead("bob", "get", "secrets", "tls", 200), ""},
+		{"forbidden read", 3, auditRead("bob", "get", "secrets", game.DefaultSecretName, 403), ""},
+		{"cheat sheet outside level 0", 2, auditRead("bob", "get", "configmaps", game.CheatConfigMapName, 200), "configmaps/" + game.CheatConfigMapName},
+		{"cheat sheet at level 0", CheatSheetLevel, auditRead("bob", "get", "configmaps", game.CheatConfigMapName, 200), ""},
+		{"deleting pods", 3, auditRead("bob", "delete", "pods", "pod-0-0", 200), ""},
+		{"gamemaster", 3, auditRead("system:serviceaccount:"+testNamespace+":podsweeper-gamemaster", "get", "secrets", game.DefaultSecretName, 200), ""},
+		{"kubernetes component", 3, auditRead("system:kube-controller-manager", "list", "secrets", "", 200), ""},
+		{"player service account", 3, auditRead("system:serviceaccount:"+testNamespace+":player", "get", "secrets", game.DefaultSecretName, 200), "secrets/" + game.DefaultSecretName},
+		{"ignored user", 3, auditRead("admin", "get", "secrets", game.DefaultSecretName, 200), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := createTestGameState(3)
+			state.Level = tt.level
+			got, ok := d.Cheat(state, tt.event)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("Cheat() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	requestStage := auditRead("bob", "get", "secrets", game.DefaultSecretName, 200)
+	requestStage.Stage = "RequestReceived"
+	if _, ok := d.Cheat(createTestGameState(3), requestStage); ok {
+		t.Error("expected only completed requests to count")
+	}
+
+	dryRun := auditRead("bob", "get", "secrets", game.DefaultSecretName, 200)
+	dryRun.RequestURI = "/api/v1/namespaces/" + testNamespace + "/secrets/" + game.DefaultSecretName + "?dryRun=All"
+	if _, ok := d.Cheat(createTestGameState(3), dryRun); ok {
+		t.Error("expected dry-run requests not to count")
+	}
+}
+
+func TestCheatDetector_Handler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.Level = 4
+	_ = store.Save(ctx, state)
+	handler := (&CheatDetector{Store: store, Namespace: testNamespace}).Handler()
+
+	post := func(events ...AuditEvent) int {
+		body, _ := json.Marshal(AuditEventList{Items: events})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/audit", bytes.NewReader(body)))
+		return rec.Code
+	}
+
+	read := auditRead("bob", "get", "secrets", game.DefaultSecretName, 200)
+	if code := post(read, read, auditRead("alice", "delete", "pods", "pod-0-0", 200)); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	loaded, _ := store.Load(ctx)
+	if got := loaded.Cheaters(); len(got) != 1 || got[0] != "bob" {
+		t.Errorf("expected bob to be caught, got %v", got)
+	}
+	if !loaded.Stats().Tainted() {
+		t.Error("expected the score to be tainted")
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/audit", bytes.NewReader([]byte("nope"))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid body, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
+
+func TestCheatDetector_IgnoresEndedGames(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.Level = 4
+	state.SetWon()
+	_ = store.Save(ctx, state)
+
+	d := &CheatDetector{Store: store, Namespace: testNamespace}
+	n, err := d.Process(ctx, []AuditEvent{auditRead("bob", "get", "secrets", game.DefaultSecretName, 200)})
+	if err != nil || n != 0 {
+		t.Errorf("expected reads after the game to be ignored, got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+// DefaultAutoplayInterval is the pace of the demo autoplayer.
+const DefaultAutoplayInterval = 3 * time.Second
+
+// Autoplayer plays the running game on its own, for demos on a screen
+// without anyone driving kubectl. It clicks cells by deleting their pods,
+// so moves go through the regular controller path. Cells are picked with
+// the solver; when it is stuck, a safe cell is picked so demos don't end
+// on an unlucky guess. With a Skill, it plays fair instead, as the bot
+// players race against. It runs as a manager Runnable, only on the leader.
+type Autoplayer struct {
+	// Client deletes the pods of the cells played.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Interval between moves. Defaults to DefaultAutoplayInterval.
+	Interval time.Duration
+
+	// LoseAtEnd makes the autoplayer click a mine instead of the last
+	// safe cell, to show the explosion.
+	LoseAtEnd bool
+
+	// Skill makes the autoplayer a bot playing fair, never looking at the
+	// mines, with the given chance from 0 to 1 to spot each cell proven
+	// safe. Zero keeps the demo autoplayer, which never loses.
+	Skill float64
+
+	rng *rand.Rand
+}
+
+// Start implements manager.Runnable.
+func (a *Autoplayer) Start(ctx context.Context) error {
+	interval := a.Interval
+	if interval <= 0 {
+		interval = DefaultAutoplayInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("autoplay")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := a.move(ctx); err != nil {
+			logger.Error(err, "failed to play")
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (a *Autoplayer) NeedLeaderElection() bool {
+	return true
+}
+
+// move plays one cell of the running game, if any.
+func (a *Autoplayer) move(ctx context.Context) error {
+	state, err := a.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || !state.CurrentPhase().Playable() || state.IsPaused() {
+		return nil
+	}
+
+	coords, ok := a.nextMove(state)
+	if !ok {
+		return nil
+	}
+
+	// Don't wait for the grace period: cell pods only sleep
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: coords.PodName(), Namespace: a.Namespace}}
+	if err := a.Client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil {
+		if errors.IsNotFound(err) {
+			// Still being processed from the previous tick
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s: %w", pod.Name, err)
+	}
+	log.FromContext(ctx).WithName("autoplay").Info("played", "coords", coords)
+	return nil
+}
+
+// nextMove picks the cell to play.
+func (a *Autoplayer) nextMove(state *game.GameState) (game.Coordinate, bool) {
+	if a.Skill > 0 {
+		if a.rng == nil {
+			a.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		return grid.BotMove(state, a.Skill, a.rng)
+	}
+	if a.LoseAtEnd && state.UnrevealedSafeCells() == 1 {
+		w, h := state.Dimensions()
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if state.IsMine(x, y) && !state.IsDefused(x, y) {
+					return game.Coordinate{X: x, Y: y}, true
+				}
+			}
+		}
+	}
+
+	suggestion, ok := grid.SuggestMove(state)
+	return suggestion.Cell, ok
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func spawnTestCells(state *game.GameState) []client.Object {
+	var pods []client.Object
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			pods = append(pods, createTestPod(game.Coordinate{X: x, Y: y}.PodName(), testNamespace))
+		}
+	}
+	return pods
+}
+
+func remainingCells(t *testing.T, c client.Client) map[string]bool {
+	t.Helper()
+	var pods corev1.PodList
+	if err := c.List(context.Background(), &pods); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, pod := range pods.Items {
+		names[pod.Name] = true
+	}
+	return names
+}
+
+func TestAutoplayer_PlaysSafeCells(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(4)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	a := &Autoplayer{Client: c, Store: store, Namespace: testNamespace}
+	if err := a.move(ctx); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+
+	cells := remainingCells(t, c)
+	if len(cells) != 15 {
+		t.Fatalf("expected exactly one pod deleted, %d left", len(cells))
+	}
+	if !cells["pod-1-1"] {
+		t.Error("autoplay clicked the mine")
+	}
+
+	// Clicking the same pod again while it's processed is not an error
+	if err := a.move(ctx); err != nil {
+		t.Errorf("expected deleted pods to be skipped, got %v", err)
+	}
+}
+
+func TestAutoplayer_LoseAtEnd(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(2)
+	state.Reveal(0, 0)
+	state.Reveal(0, 1)
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	a := &Autoplayer{Client: c, Store: store, Namespace: testNamespace, LoseAtEnd: true}
+	if err := a.move(ctx); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if remainingCells(t, c)["pod-1-1"] {
+		t.Error("expected the mine to be clicked instead of the last safe cell")
+	}
+}
+
+func TestAutoplayer_Skill(t *testing.T) {
+	ctx := context.Background()
+	// The revealed 1 at (0,0) can't tell the mine at (1,1) from its neighbors
+	state := createTestGameState(2)
+	state.Reveal(0, 0)
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	// A fair bot may guess the mine, unlike the demo autoplayer
+	a := &Autoplayer{Client: c, Store: store, Namespace: testNamespace, Skill: 1, rng: rand.New(rand.NewSource(1))}
+	coords, ok := a.nextMove(state)
+	if !ok || state.IsRevealed(coords.X, coords.Y) {
+		t.Fatalf("expected a hidden cell, got %v", coords)
+	}
+	mines := 0
+	for range 50 {
+		coords, _ := a.nextMove(state)
+		if state.IsMine(coords.X, coords.Y) {
+			mines++
+		}
+	}
+	if mines == 0 {
+		t.Error("expected the bot not to know where the mine is")
+	}
+}
+
+func TestAutoplayer_IgnoresEndedAndPausedGames(t *testing.T) {
+	ctx := context.Background()
+	for name, setup := range map[string]func(*game.GameState){
+		"ended":  func(s *game.GameState) { s.SetWon() },
+		"paused": func(s *game.GameState) { s.Pause() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := createTestGameState(4)
+			setup(state)
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+			store := game.NewMemoryStore()
+			_ = store.Save(ctx, state)
+
+			a := &Autoplayer{Client: c, Store: store, Namespace: testNamespace}
+			if err := a.move(ctx); err != nil {
+				t.Fatalf("move failed: %v", err)
+			}
+			if len(remainingCells(t, c)) != 16 {
+				t.Error("expected no pod deleted")
+			}
+		})
+	}
+}
+
+func TestAutoplayer_StartStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &Autoplayer{Store: game.NewMemoryStore(), Interval: time.Millisecond}
+
+	done := make(chan error)
+	go func() { done <- a.Start(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start did not stop with its context")
+	}
+}
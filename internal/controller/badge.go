@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+const (
+	// badgeLabel is the left part of the status badge.
+	badgeLabel = "podsweeper"
+
+	// badgeCharWidth approximates the width of a character of the badge
+	// font, in pixels, to size the badge without measuring text.
+	badgeCharWidth = 7
+
+	// badgePadding is the horizontal padding around each part, in pixels.
+	badgePadding = 10
+)
+
+// Badge colors, as used by status badges of CI systems.
+const (
+	badgeGrey  = "#9f9f9f"
+	badgeBlue  = "#007ec6"
+	badgeGreen = "#4c1"
+	badgeRed   = "#e05d44"
+)
+
+// BadgeHandler serves the status of the game as an SVG badge, such as
+// "PLAYING 37%", "WON 02:41" or "BOOM", for dashboards and READMEs of
+// workshop repositories. It is generated from the live state and never
+// cached, but only tells the progress of the game.
+func BadgeHandler(store game.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		status, color := BadgeStatus(state)
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		_, _ = fmt.Fprint(w, badgeSVG(badgeLabel, status, color))
+	})
+}
+
+// BadgeStatus returns the status shown by the badge of state, and its
+// color.
+func BadgeStatus(state *game.GameState) (string, string) {
+	if state == nil {
+		return "NO GAME", badgeGrey
+	}
+	if phase := state.CurrentPhase(); !phase.Spawned() {
+		return "SPAWNING", badgeGrey
+	}
+	stats := state.Stats()
+	switch {
+	case stats.Status == game.StatusWon:
+		return "WON " + badgeDuration(stats.Elapsed()), badgeGreen
+	case stats.Resigned:
+		return "RESIGNED", badgeRed
+	case stats.Status == game.StatusLost:
+		return "BOOM", badgeRed
+	}
+	return fmt.Sprintf("PLAYING %.0f%%", stats.Progress), badgeBlue
+}
+
+// badgeDuration formats d as minutes and seconds, with hours when needed.
+func badgeDuration(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// badgeSVG draws a two-part flat badge. Label and status are never user
+// input, so they are not escaped.
+func badgeSVG(label, status, color string) string {
+	lw := len(label)*badgeCharWidth + badgePadding
+	sw := len(status)*badgeCharWidth + badgePadding
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<rect width="%[2]d" height="20" fill="#555"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`+"\n",
+		lw+sw, lw, sw, label, status, color, lw/2, lw+sw/2)
+}
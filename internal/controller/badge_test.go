@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestBadgeStatus(t *testing.T) {
+	if status, _ := BadgeStatus(nil); status != "NO GAME" {
+		t.Errorf("expected no game, got %q", status)
+	}
+
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.Reveal(1, 1)
+	if status, color := BadgeStatus(state); status != "PLAYING 33%" || color != badgeBlue {
+		t.Errorf("expected a third of the board played, got %q %s", status, color)
+	}
+
+	won := state.Clone()
+	won.StartedAt = time.Now().Add(-161 * time.Second)
+	won.SetWon()
+	if status, color := BadgeStatus(won); status != "WON 02:41" || color != badgeGreen {
+		t.Errorf("expected a win in 02:41, got %q %s", status, color)
+	}
+
+	lost := state.Clone()
+	lost.HitMine(0, 0, "")
+	if status, _ := BadgeStatus(lost); status != "BOOM" {
+		t.Errorf("expected a mine hit, got %q", status)
+	}
+
+	if got := badgeDuration(3*time.Hour + 2*time.Second); got != "3:00:02" {
+		t.Errorf("expected hours for long games, got %q", got)
+	}
+}
+
+func TestBadgeHandler(t *testing.T) {
+	store := game.NewMemoryStore()
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.HitMine(0, 0, "")
+	_ = store.Save(context.Background(), state)
+
+	rec := httptest.NewRecorder()
+	BadgeHandler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/badge.svg", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("expected an SVG, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if body := rec.Body.String(); !strings.HasPrefix(body, "<svg") || !strings.Contains(body, ">BOOM</text>") {
+		t.Errorf("expected a BOOM badge, got %q", body)
+	}
+}
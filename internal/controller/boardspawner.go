@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// DefaultBoardSpawnInterval is how often the Gamemaster looks for a game
+// left for it to spawn.
+const DefaultBoardSpawnInterval = 5 * time.Second
+
+// BoardSpawner spawns the board of games saved Pending, or left Spawning
+// by an interrupted spawn, by provisioners that can't reach the cluster
+// the board is played in, such as the vcluster of a game. The board of a
+// previous game is cleared first. It runs as a manager Runnable, only on
+// the leader.
+type BoardSpawner struct {
+	// Client creates the board pods, in the game cluster.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the board namespace in the game cluster.
+	Namespace string
+
+	// Handlers create and clear the board pods.
+	Handlers *GameHandlers
+
+	// Spawner builds cell and resign pods.
+	Spawner *spawner.GridSpawner
+
+	// CreateNamespace creates the board namespace if missing, in game
+	// clusters where the Gamemaster may.
+	CreateNamespace bool
+
+	// Interval between checks. Defaults to DefaultBoardSpawnInterval.
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable.
+func (b *BoardSpawner) Start(ctx context.Context) error {
+	interval := b.Interval
+	if interval <= 0 {
+		interval = DefaultBoardSpawnInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Spawn(ctx); err != nil {
+			log.FromContext(ctx).WithName("board-spawner").Error(err, "failed to spawn the board")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (b *BoardSpawner) NeedLeaderElection() bool {
+	return true
+}
+
+// Spawn spawns the board of the game if it is not spawned yet.
+func (b *BoardSpawner) Spawn(ctx context.Context) error {
+	state, err := b.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if state == nil || state.CurrentPhase().Spawned() {
+		return nil
+	}
+	log.FromContext(ctx).WithName("board-spawner").Info("spawning the board",
+		"namespace", b.Namespace, "phase", state.CurrentPhase(), "cells", state.TotalCells())
+
+	if b.CreateNamespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: b.Namespace}}
+		if err := b.Client.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create the board namespace: %w", err)
+		}
+	}
+	// The pods of the previous game would keep their names from being reused
+	if state.CurrentPhase() == game.PhasePending {
+		if err := b.Handlers.ClearBoard(ctx, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := state.SetPhase(game.PhaseSpawning); err != nil {
+		return err
+	}
+	if err := b.Store.Save(ctx, state); err != nil {
+		return err
+	}
+	if err := b.Handlers.SpawnBoard(ctx, b.Spawner, state); err != nil {
+		return err
+	}
+	if err := state.SetPhase(game.PhaseReady); err != nil {
+		return err
+	}
+	return b.Store.Save(ctx, state)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func TestBoardSpawner_SpawnsPendingGame(t *testing.T) {
+	ctx := context.Background()
+	// A cell of the previous game is left over
+	leftover := createTestPod("pod-7-7", testNamespace)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(leftover).Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.Phase = game.PhasePending
+	_ = store.Save(ctx, state)
+
+	b := &BoardSpawner{
+		Client:          c,
+		Store:           store,
+		Namespace:       testNamespace,
+		Handlers:        NewGameHandlers(c, store, testNamespace),
+		Spawner:         spawner.NewGridSpawner(c, spawner.GridSpawnerConfig{Namespace: testNamespace}),
+		CreateNamespace: true,
+	}
+	if err := b.Spawn(ctx); err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: testNamespace}, &ns); err != nil {
+		t.Errorf("expected the board namespace to be created: %v", err)
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(testNamespace)); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	// One pod per cell and the resign pod, without the leftover
+	if len(pods.Items) != state.TotalCells()+1 {
+		t.Errorf("expected %d pods, got %d", state.TotalCells()+1, len(pods.Items))
+	}
+	for _, pod := range pods.Items {
+		if pod.Name == "pod-7-7" {
+			t.Error("expected the previous board to be cleared")
+		}
+	}
+	if loaded, _ := store.Load(ctx); loaded.Phase != game.PhaseReady {
+		t.Errorf("expected the game to be Ready, got %s", loaded.Phase)
+	}
+
+	// Spawned games are left alone
+	if err := c.Delete(ctx, &pods.Items[0]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := b.Spawn(ctx); err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	if err := c.List(ctx, pods, client.InNamespace(testNamespace)); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(pods.Items) != state.TotalCells() {
+		t.Errorf("expected a spawned game not to be respawned, got %d pods", len(pods.Items))
+	}
+}
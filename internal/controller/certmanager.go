@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/certs"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// DefaultCertValidity is how long self-managed serving certificates of
+	// the webhook server are valid.
+	DefaultCertValidity = 30 * 24 * time.Hour
+
+	// DefaultCertCheckInterval is how often self-managed certificates are
+	// checked for rotation.
+	DefaultCertCheckInterval = time.Hour
+
+	// caValidityFactor is how many serving certificates a self-managed CA
+	// outlives.
+	caValidityFactor = 12
+
+	// caKeyName is the key of the CA private key in the certificate
+	// Secret, next to corev1.ServiceAccountRootCAKey holding the caBundle.
+	caKeyName = "ca.key"
+)
+
+// CertManager serves the webhook server with self-managed certificates,
+// for clusters without cert-manager. It keeps a self-signed CA and a
+// serving certificate for the webhook Service in the rbac.WebhookName
+// Secret, shared by the Gamemaster replicas, patches the CA into the
+// caBundle of the webhook configurations, and rotates both before they
+// expire. The webhook server reads the certificate from memory on every
+// handshake, so rotating never restarts the Gamemaster:
+//
+//	webhook.NewServer(webhook.Options{TLSOpts: []func(*tls.Config){certManager.TLSOpt}})
+//
+// Without it, the certificates of --webhook-cert-dir are reloaded when
+// they change, such as a mounted Secret renewed by cert-manager: the
+// webhook server of controller-runtime watches them with its
+// certwatcher, which the Gamemaster relies on without testing.
+//
+// A rotated CA stays in the caBundle next to the new one until it expires,
+// so replicas still serving a certificate it signed keep being trusted. It
+// runs as a manager Runnable on every replica, since they all serve the
+// webhooks.
+type CertManager struct {
+	// Client reads and writes the certificate Secret.
+	Client client.Client
+
+	// WebhookClient patches the webhook configurations, in the cluster
+	// the board is played in. Defaults to Client.
+	WebhookClient client.Client
+
+	// Namespace is the namespace of the webhook Service and of the
+	// certificate Secret, the namespace the Gamemaster runs in.
+	Namespace string
+
+	// Service is the webhook Service the certificate is issued for.
+	// Defaults to rbac.WebhookName.
+	Service string
+
+	// Configurations names the Validating and MutatingWebhookConfigurations
+	// whose webhooks get the caBundle. Missing ones are skipped.
+	Configurations []string
+
+	// Validity is how long serving certificates are valid, renewed when a
+	// third of it is left. CAs are valid twelve times longer. Defaults to
+	// DefaultCertValidity.
+	Validity time.Duration
+
+	// Interval between rotation checks. Defaults to
+	// DefaultCertCheckInterval.
+	Interval time.Duration
+
+	// cert is the serving certificate.
+	cert atomic.Pointer[tls.Certificate]
+
+	// now returns the current time, for tests.
+	now func() time.Time
+}
+
+// Start implements manager.Runnable.
+func (m *CertManager) Start(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultCertCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("certs")
+	for {
+		if err := m.Ensure(ctx); err != nil {
+			logger.Error(err, "failed to rotate webhook certificates")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (m *CertManager) NeedLeaderElection() bool {
+	return false
+}
+
+// TLSOpt makes a TLS config serve the current certificate, as a
+// webhook.Options TLSOpts.
+func (m *CertManager) TLSOpt(cfg *tls.Config) {
+	cfg.GetCertificate = m.GetCertificate
+}
+
+// GetCertificate returns the current serving certificate, for
+// tls.Config.GetCertificate.
+func (m *CertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := m.cert.Load()
+	if cert == nil {
+		return nil, errors.New("webhook certificate not ready")
+	}
+	return cert, nil
+}
+
+// Ensure loads the certificates, rotates them when they are about to
+// expire, serves the serving certificate and patches the caBundle into the
+// webhook configurations. Call it once before starting the webhook server
+// so the first handshakes succeed.
+func (m *CertManager) Ensure(ctx context.Context) error {
+	secret, err := m.rotate(ctx)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid webhook certificate: %w", err)
+	}
+	m.cert.Store(&cert)
+	return m.patchCABundle(ctx, secret.Data[corev1.ServiceAccountRootCAKey])
+}
+
+// rotate returns the certificate Secret, creating it or renewing its
+// certificates when they are about to expire.
+func (m *CertManager) rotate(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: rbac.WebhookName}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: rbac.WebhookName, Namespace: m.Namespace},
+			Type:       corev1.SecretTypeTLS,
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", rbac.WebhookName, err)
+	}
+
+	changed, err := m.renew(secret)
+	if err != nil || !changed {
+		return secret, err
+	}
+	if secret.ResourceVersion == "" {
+		if err := m.Client.Create(ctx, secret); apierrors.IsAlreadyExists(err) {
+			// Created by another replica in the meantime
+			return m.rotate(ctx)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to create secret %s: %w", rbac.WebhookName, err)
+		}
+	} else if err := m.Client.Update(ctx, secret); apierrors.IsConflict(err) {
+		// Rotated by another replica in the meantime
+		return m.rotate(ctx)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to update secret %s: %w", rbac.WebhookName, err)
+	}
+	log.FromContext(ctx).Info("rotated webhook certificates", "secret", rbac.WebhookName)
+	return secret, nil
+}
+
+// renew renews the CA and serving certificate of secret that are about to
+// expire, and reports whether it did.
+func (m *CertManager) renew(secret *corev1.Secret) (bool, error) {
+	now, validity := m.clock(), m.validity()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	bundle := secret.Data[corev1.ServiceAccountRootCAKey]
+	ca := certs.Pair{Cert: bundle, Key: secret.Data[caKeyName]}
+	serving := certs.Pair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+
+	// Renew the CA while its last serving certificate can still be
+	// renewed in time, so serving certificates never outlive it
+	renewCA := ca.Expiring(now, validity) || len(ca.Key) == 0
+	if renewCA {
+		var err error
+		if ca, err = certs.NewCA(now, caValidityFactor*validity); err != nil {
+			return false, err
+		}
+	}
+	dnsNames := certs.ServiceDNSNames(m.service(), m.Namespace)
+	if !renewCA && !serving.Expiring(now, validity/3) && serving.Covers(dnsNames) {
+		return false, nil
+	}
+	serving, err := ca.Issue(dnsNames, now, validity)
+	if err != nil {
+		return false, err
+	}
+
+	// The CA comes first: new certificates are signed with the first one
+	secret.Data[corev1.ServiceAccountRootCAKey] = certs.Bundle(now, ca.Cert, bundle)
+	secret.Data[caKeyName] = ca.Key
+	secret.Data[corev1.TLSCertKey] = serving.Cert
+	secret.Data[corev1.TLSPrivateKeyKey] = serving.Key
+	return true, nil
+}
+
+// patchCABundle sets the caBundle of every webhook of the webhook
+// configurations.
+func (m *CertManager) patchCABundle(ctx context.Context, bundle []byte) error {
+	c := m.WebhookClient
+	if c == nil {
+		c = m.Client
+	}
+	for _, name := range m.Configurations {
+		validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := m.updateConfiguration(ctx, c, name, validating, func() bool {
+			changed := false
+			for i := range validating.Webhooks {
+				changed = setCABundle(&validating.Webhooks[i].ClientConfig, bundle) || changed
+			}
+			return changed
+		}); err != nil {
+			return err
+		}
+
+		mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := m.updateConfiguration(ctx, c, name, mutating, func() bool {
+			changed := false
+			for i := range mutating.Webhooks {
+				changed = setCABundle(&mutating.Webhooks[i].ClientConfig, bundle) || changed
+			}
+			return changed
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateConfiguration gets the webhook configuration name into obj, and
+// updates it when mutate changed it. Missing configurations are skipped.
+func (m *CertManager) updateConfiguration(ctx context.Context, c client.Client, name string, obj client.Object, mutate func() bool) error {
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, obj); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get webhook configuration %s: %w", name, err)
+	}
+	if !mutate() {
+		return nil
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to patch the caBundle of webhook configuration %s: %w", name, err)
+	}
+	log.FromContext(ctx).Info("patched the webhook caBundle", "configuration", name)
+	return nil
+}
+
+// setCABundle sets the caBundle of a webhook, and reports whether it
+// changed.
+func setCABundle(cfg *admissionregistrationv1.WebhookClientConfig, bundle []byte) bool {
+	if bytes.Equal(cfg.CABundle, bundle) {
+		return false
+	}
+	cfg.CABundle = bundle
+	return true
+}
+
+// service returns the webhook Service.
+func (m *CertManager) service() string {
+	if m.Service == "" {
+		return rbac.WebhookName
+	}
+	return m.Service
+}
+
+// validity returns how long serving certificates are valid.
+func (m *CertManager) validity() time.Duration {
+	if m.Validity <= 0 {
+		return DefaultCertValidity
+	}
+	return m.Validity
+}
+
+// clock returns the current time.
+func (m *CertManager) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
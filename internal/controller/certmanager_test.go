@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/zwindler/podsweeper/pkg/certs"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+func TestCertManager(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+	_ = admissionregistrationv1.AddToScheme(scheme)
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: rbac.WebhookName},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "hint-guard.podsweeper.io"}},
+	}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: rbac.WebhookName},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "hint-scrambler.podsweeper.io"}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(validating, mutating).Build()
+
+	now := time.Now()
+	m := &CertManager{
+		Client:         c,
+		Namespace:      "podsweeper-system",
+		Configurations: []string{rbac.WebhookName, "missing"},
+		Validity:       30 * time.Hour,
+		now:            func() time.Time { return now },
+	}
+	if _, err := m.GetCertificate(nil); err == nil {
+		t.Error("expected no certificate before Ensure")
+	}
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: rbac.WebhookName}, secret); err != nil {
+		t.Fatalf("expected the certificate secret, got %v", err)
+	}
+	bundle := secret.Data[corev1.ServiceAccountRootCAKey]
+	serving := certs.Pair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+	if !serving.Covers([]string{"podsweeper-webhook.podsweeper-system.svc"}) {
+		t.Error("expected a serving certificate for the webhook Service")
+	}
+	cert, err := m.GetCertificate(nil)
+	if err != nil || !bytes.Equal(cert.Certificate[0], mustParse(t, serving).Raw) {
+		t.Errorf("expected the serving certificate to be served, got %v", err)
+	}
+	assertCABundle(t, c, bundle)
+
+	// Nothing changes until a third of the validity is left
+	now = now.Add(15 * time.Hour)
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	if !bytes.Equal(secret.Data[corev1.TLSCertKey], serving.Cert) {
+		t.Error("expected the serving certificate kept")
+	}
+
+	// The serving certificate is renewed with the same CA
+	now = now.Add(10 * time.Hour)
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	if bytes.Equal(secret.Data[corev1.TLSCertKey], serving.Cert) {
+		t.Error("expected the serving certificate renewed")
+	}
+	if !bytes.Equal(secret.Data[corev1.ServiceAccountRootCAKey], bundle) {
+		t.Error("expected the CA kept")
+	}
+	if cert, _ := m.GetCertificate(nil); bytes.Equal(cert.Certificate[0], mustParse(t, serving).Raw) {
+		t.Error("expected the renewed certificate to be served")
+	}
+
+	// The CA is renewed when its last serving certificate is due, and the
+	// old one stays trusted
+	now = now.Add(11 * 30 * time.Hour)
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	renewed := secret.Data[corev1.ServiceAccountRootCAKey]
+	if bytes.Equal(renewed, bundle) || !bytes.HasSuffix(renewed, bundle) {
+		t.Error("expected the new CA bundled before the old one")
+	}
+	assertCABundle(t, c, renewed)
+}
+
+func mustParse(t *testing.T, p certs.Pair) *x509.Certificate {
+	t.Helper()
+	cert, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return cert
+}
+
+func assertCABundle(t *testing.T, c client.Client, bundle []byte) {
+	t.Helper()
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	_ = c.Get(context.Background(), client.ObjectKey{Name: rbac.WebhookName}, validating)
+	if !bytes.Equal(validating.Webhooks[0].ClientConfig.CABundle, bundle) {
+		t.Error("expected the caBundle patched into the validating webhook")
+	}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	_ = c.Get(context.Background(), client.ObjectKey{Name: rbac.WebhookName}, mutating)
+	if !bytes.Equal(mutating.Webhooks[0].ClientConfig.CABundle, bundle) {
+		t.Error("expected the caBundle patched into the mutating webhook")
+	}
+}
+
+// TestWebhookServer_ServesRotatedCertificate checks that the webhook
+// server serves the certificate rotated by the CertManager without being
+// restarted.
+func TestWebhookServer_ServesRotatedCertificate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheme := newTestScheme()
+	_ = admissionregistrationv1.AddToScheme(scheme)
+	now := time.Now()
+	m := &CertManager{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Namespace: "podsweeper-system",
+		Validity:  30 * time.Hour,
+		now:       func() time.Time { return now },
+	}
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	secret := &corev1.Secret{}
+	_ = m.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: rbac.WebhookName}, secret)
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(secret.Data[corev1.ServiceAccountRootCAKey])
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	server := webhook.NewServer(webhook.Options{
+		Host:    "127.0.0.1",
+		Port:    port,
+		CertDir: t.TempDir(),
+		TLSOpts: []func(*tls.Config){m.TLSOpt},
+	})
+	go func() { _ = server.Start(ctx) }()
+
+	served := func() *x509.Certificate {
+		conn, err := tls.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+			&tls.Config{RootCAs: pool, ServerName: m.service(), Time: func() time.Time { return now }})
+		if err != nil {
+			return nil
+		}
+		defer func() { _ = conn.Close() }()
+		return conn.ConnectionState().PeerCertificates[0]
+	}
+	var first *x509.Certificate
+	for deadline := time.Now().Add(30 * time.Second); first == nil && time.Now().Before(deadline); {
+		if first = served(); first == nil {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if first == nil {
+		t.Fatal("expected the webhook server to serve the certificate")
+	}
+
+	// Rotated past two thirds of its validity
+	now = now.Add(25 * time.Hour)
+	if err := m.Ensure(ctx); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	renewed := served()
+	if renewed == nil || renewed.SerialNumber.Cmp(first.SerialNumber) == 0 {
+		t.Errorf("expected the rotated certificate to be served instead of %s", first.SerialNumber)
+	}
+}
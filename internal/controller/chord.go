@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// reconcileChord chords the revealed cell coords once its hint pod is gone,
+// see GameHandlers.HandleChord.
+func (r *GameController) reconcileChord(ctx context.Context, req ctrl.Request, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	err := r.Get(ctx, req.NamespacedName, &corev1.Pod{})
+	if err == nil || !errors.IsNotFound(err) {
+		// Still there, or terminating: chord once it is fully gone
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		logger.Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to load game state")
+		return ctrl.Result{}, err
+	}
+	// Hint pods also go away with the board being cleared, and with the
+	// hints decaying
+	if state == nil || state.Status != game.StatusPlaying || !state.CurrentPhase().Playable() ||
+		!state.IsRevealed(coords.X, coords.Y) || state.HintDecayed(coords.X, coords.Y, time.Now()) {
+		return ctrl.Result{}, nil
+	}
+	if _, shown := state.HintAt(coords.X, coords.Y); !shown {
+		return ctrl.Result{}, nil
+	}
+
+	return r.Handlers.HandleChord(ctx, state, coords)
+}
+
+// HandleChord processes the deletion of the hint pod of the revealed cell
+// coords: if as many of its neighbors are flagged as its hint tells, the
+// pods of its other hidden neighbors are deleted, each deletion then played
+// as a move. A wrong flag sets off a mine. The hint pod is spawned again
+// either way.
+func (h *GameHandlers) HandleChord(ctx context.Context, state *game.GameState, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := h.spawnHintPod(ctx, state, coords, state.AdjacentMines(coords.X, coords.Y)); client.IgnoreAlreadyExists(err) != nil {
+		logger.Error(err, "failed to spawn hint pod")
+		return ctrl.Result{}, err
+	}
+
+	cells, ok := state.Chord(coords.X, coords.Y)
+	if !ok {
+		logger.Info("not chording, the flags don't match the hint", "coords", coords)
+		return ctrl.Result{}, nil
+	}
+	logger.Info("chording", "coords", coords, "cells", len(cells))
+	for _, c := range cells {
+		if err := h.deletePod(ctx, c); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete %s: %w", c.PodName(), err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGameController_Chord(t *testing.T) {
+	ctx := context.Background()
+	// Mine at (1,1): (0,0) shows 1
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	var pods []client.Object
+	for _, c := range []game.Coordinate{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}} {
+		pods = append(pods, createTestPod(c.PodName(), testNamespace))
+	}
+	controller, c, _ := newTutorialController(t, state, pods...)
+
+	exists := func(name string) bool {
+		err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, &corev1.Pod{})
+		if err != nil && !errors.IsNotFound(err) {
+			t.Fatalf("failed to get %s: %v", name, err)
+		}
+		return err == nil
+	}
+
+	// Without the flag, the hint pod comes back and nothing else happens
+	reconcilePod(t, controller, "hint-0-0")
+	if !exists("hint-0-0") || !exists("pod-1-0") || !exists("pod-0-1") {
+		t.Fatal("expected only the hint pod restored")
+	}
+
+	state.SetFlag(1, 1, true)
+	_ = controller.Store.Save(ctx, state)
+	deletePods(t, c, "hint-0-0")
+	reconcilePod(t, controller, "hint-0-0")
+	if !exists("hint-0-0") {
+		t.Error("expected the hint pod restored")
+	}
+	if exists("pod-1-0") || exists("pod-0-1") {
+		t.Error("expected the unflagged neighbors deleted")
+	}
+	if !exists("pod-1-1") {
+		t.Error("expected the flagged mine kept")
+	}
+
+	// The deletions are then played as moves
+	reconcilePod(t, controller, "pod-1-0")
+	reconcilePod(t, controller, "pod-0-1")
+	saved, _ := controller.Store.Load(ctx)
+	if !saved.IsRevealed(1, 0) || !saved.IsRevealed(0, 1) || saved.Status != game.StatusPlaying {
+		t.Errorf("expected the chorded cells revealed, got %s", saved.Status)
+	}
+}
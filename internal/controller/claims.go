@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultClaimTTL is how long a claimed move waits for its pod deletion.
+const DefaultClaimTTL = time.Minute
+
+// MoveClaims lets in-process players such as the gremlin claim the pod they
+// are about to delete, so that the reveal is attributed to them. A nil
+// MoveClaims has no claims.
+type MoveClaims struct {
+	mu     sync.Mutex
+	claims map[game.Coordinate]moveClaim
+	ttl    time.Duration
+}
+
+type moveClaim struct {
+	by string
+	at time.Time
+}
+
+// NewMoveClaims creates an empty MoveClaims.
+func NewMoveClaims() *MoveClaims {
+	return &MoveClaims{claims: map[game.Coordinate]moveClaim{}, ttl: DefaultClaimTTL}
+}
+
+// Claim attributes the next deletion of the cell's pod to a player.
+func (m *MoveClaims) Claim(c game.Coordinate, by string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claims[c] = moveClaim{by: by, at: time.Now()}
+}
+
+// Release drops a claim, typically after the deletion failed.
+func (m *MoveClaims) Release(c game.Coordinate) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.claims, c)
+}
+
+// Take returns and drops the claim on a cell. Expired claims are ignored so
+// that a failed deletion is never attributed to a later click.
+func (m *MoveClaims) Take(c game.Coordinate) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	claim, ok := m.claims[c]
+	delete(m.claims, c)
+	if !ok || time.Since(claim.at) > m.ttl {
+		return "", false
+	}
+	return claim.by, true
+}
+
+type moverKey struct{}
+
+// withMover returns a context carrying the player making the move.
+func withMover(ctx context.Context, mover string) context.Context {
+	return context.WithValue(ctx, moverKey{}, mover)
+}
+
+// moverFrom returns the player making the move, empty if unknown.
+func moverFrom(ctx context.Context) string {
+	mover, _ := ctx.Value(moverKey{}).(string)
+	return mover
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestMoveClaims(t *testing.T) {
+	claims := NewMoveClaims()
+	c := game.Coordinate{X: 1, Y: 2}
+
+	claims.Claim(c, GremlinName)
+	if by, ok := claims.Take(c); !ok || by != GremlinName {
+		t.Errorf("expected the claim to be taken, got %q %v", by, ok)
+	}
+	if _, ok := claims.Take(c); ok {
+		t.Error("expected a claim to be taken only once")
+	}
+
+	claims.Claim(c, GremlinName)
+	claims.Release(c)
+	if _, ok := claims.Take(c); ok {
+		t.Error("expected a released claim to be dropped")
+	}
+
+	claims.Claim(c, GremlinName)
+	claims.ttl = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, ok := claims.Take(c); ok {
+		t.Error("expected an expired claim to be ignored")
+	}
+}
+
+func TestMoveClaimsNil(t *testing.T) {
+	var claims *MoveClaims
+	claims.Claim(game.Coordinate{}, GremlinName)
+	claims.Release(game.Coordinate{})
+	if _, ok := claims.Take(game.Coordinate{}); ok {
+		t.Error("expected no claim on nil claims")
+	}
+}
+
+func TestMoverContext(t *testing.T) {
+	ctx := context.Background()
+	if got := moverFrom(ctx); got != "" {
+		t.Errorf("expected no mover, got %q", got)
+	}
+	if got := moverFrom(withMover(ctx, "alice")); got != "alice" {
+		t.Errorf("expected alice, got %q", got)
+	}
+}
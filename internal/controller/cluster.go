@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultKubeconfigKey is the key of the kubeconfig in the Secret of a
+// game cluster, as written by vcluster.
+const DefaultKubeconfigKey = "config"
+
+// GameCluster resolves the cluster the board of a game is played in. The
+// game state always stays in the game namespace of the Gamemaster's
+// cluster, where dashboards and provisioners find it; the board pods may
+// live in another cluster, such as a vcluster per game giving players a
+// whole cluster to themselves.
+type GameCluster interface {
+	// RestConfig returns the config of the cluster of the board of the
+	// game of namespace.
+	RestConfig(ctx context.Context, namespace string) (*rest.Config, error)
+}
+
+// HostCluster plays every board in the Gamemaster's cluster.
+type HostCluster struct {
+	Config *rest.Config
+}
+
+// RestConfig implements GameCluster.
+func (h HostCluster) RestConfig(context.Context, string) (*rest.Config, error) {
+	return rest.CopyConfig(h.Config), nil
+}
+
+// KubeconfigSecretCluster plays the board of each game in the cluster of
+// the kubeconfig kept in a Secret of its namespace, such as the one
+// vcluster writes, or one given for an existing cluster.
+type KubeconfigSecretCluster struct {
+	// Reader reads the Secret in the Gamemaster's cluster.
+	Reader client.Reader
+
+	// Name is the name of the Secret.
+	Name string
+
+	// Key holds the kubeconfig in the Secret. Defaults to
+	// DefaultKubeconfigKey.
+	Key string
+
+	// Server replaces the server of the kubeconfig when set, such as
+	// with the in-cluster Service of a vcluster whose kubeconfig targets
+	// a port-forward.
+	Server string
+}
+
+// RestConfig implements GameCluster.
+func (k KubeconfigSecretCluster) RestConfig(ctx context.Context, namespace string) (*rest.Config, error) {
+	key := k.Key
+	if key == "" {
+		key = DefaultKubeconfigKey
+	}
+	secret := &corev1.Secret{}
+	if err := k.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: k.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the kubeconfig of the game cluster: %w", err)
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no %q kubeconfig", namespace, k.Name, key)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s/%s: %w", namespace, k.Name, err)
+	}
+	if k.Server != "" {
+		config.Host = k.Server
+	}
+	return config, nil
+}
+
+// NewGameClient returns an uncached client of the cluster of the board of
+// the game of namespace.
+func NewGameClient(ctx context.Context, cluster GameCluster, namespace string, scheme *runtime.Scheme) (client.Client, error) {
+	config, err := cluster.RestConfig(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
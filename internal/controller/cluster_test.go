@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testKubeconfig(t *testing.T, server string) []byte {
+	t.Helper()
+	config := clientcmdapi.NewConfig()
+	config.Clusters["vcluster"] = &clientcmdapi.Cluster{Server: server}
+	config.AuthInfos["admin"] = &clientcmdapi.AuthInfo{Token: "secret-token"}
+	config.Contexts["vcluster"] = &clientcmdapi.Context{Cluster: "vcluster", AuthInfo: "admin"}
+	config.CurrentContext = "vcluster"
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return data
+}
+
+func TestKubeconfigSecretCluster(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-podsweeper", Namespace: testNamespace},
+		Data:       map[string][]byte{DefaultKubeconfigKey: testKubeconfig(t, "https://localhost:8443")},
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(secret).Build()
+
+	tests := []struct {
+		name       string
+		cluster    KubeconfigSecretCluster
+		wantServer string
+		wantErr    bool
+	}{
+		{"kubeconfig server", KubeconfigSecretCluster{Reader: c, Name: "vc-podsweeper"}, "https://localhost:8443", false},
+		{"server override", KubeconfigSecretCluster{Reader: c, Name: "vc-podsweeper", Server: "https://podsweeper.game.svc:443"},
+			"https://podsweeper.game.svc:443", false},
+		{"missing key", KubeconfigSecretCluster{Reader: c, Name: "vc-podsweeper", Key: "kubeconfig"}, "", true},
+		{"missing secret", KubeconfigSecretCluster{Reader: c, Name: "vc-other"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.cluster.RestConfig(ctx, testNamespace)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RestConfig failed: %v", err)
+			}
+			if config.Host != tt.wantServer || config.BearerToken != "secret-token" {
+				t.Errorf("unexpected config: host %q, token %q", config.Host, config.BearerToken)
+			}
+		})
+	}
+}
+
+func TestHostCluster(t *testing.T) {
+	host := &rest.Config{Host: "https://k8s.example.com:6443"}
+	config, err := HostCluster{Config: host}.RestConfig(context.Background(), testNamespace)
+	if err != nil {
+		t.Fatalf("RestConfig failed: %v", err)
+	}
+	config.Host = "changed"
+	if host.Host != "https://k8s.example.com:6443" {
+		t.Error("expected a copy of the host config")
+	}
+}
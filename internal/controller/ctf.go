@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// maxFlagSubmission bounds the size of a submitted flag.
+const maxFlagSubmission = 1024
+
+// FlagHandler checks flags submitted in capture-the-flag games. The flag is
+// the body of a POST request; the answer is 200 for the right flag and 403
+// otherwise.
+func FlagHandler(store game.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "submit the flag with POST", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxFlagSubmission))
+		if err != nil {
+			http.Error(w, "failed to read flag", http.StatusBadRequest)
+			return
+		}
+
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		if state == nil || state.CTF == nil {
+			http.Error(w, "no capture-the-flag game running", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		if !state.ValidateFlag(string(body)) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintln(w, "wrong flag")
+			return
+		}
+		fmt.Fprintln(w, "correct flag, well played!")
+	})
+}
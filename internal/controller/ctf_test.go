@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestFlagHandler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	handler := FlagHandler(store)
+
+	submit := func(method, flag string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/flag", strings.NewReader(flag)))
+		return rec.Code
+	}
+
+	if code := submit(http.MethodPost, "x"); code != http.StatusNotFound {
+		t.Errorf("expected 404 without a game, got %d", code)
+	}
+
+	state := createTestGameState(4)
+	if err := state.EnableCTF(2); err != nil {
+		t.Fatalf("EnableCTF failed: %v", err)
+	}
+	_ = store.Save(ctx, state)
+
+	if code := submit(http.MethodGet, state.CTF.Flag); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", code)
+	}
+	if code := submit(http.MethodPost, "PODSWEEPER{nope}"); code != http.StatusForbidden {
+		t.Errorf("expected 403 for a wrong flag, got %d", code)
+	}
+	if code := submit(http.MethodPost, state.CTF.Flag); code != http.StatusOK {
+		t.Errorf("expected 200 for the right flag, got %d", code)
+	}
+}
+
+func TestGameHandlers_CTFFragmentsInPods(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	handlers := NewGameHandlers(fakeClient, game.NewMemoryStore(), testNamespace)
+
+	state := createTestGameState(4)
+	if err := state.EnableCTF(1); err != nil {
+		t.Fatalf("EnableCTF failed: %v", err)
+	}
+	hidden := state.CTF.Fragments[0]
+
+	if err := handlers.spawnHintPod(ctx, state, *hidden.Cell, state.AdjacentMines(hidden.Cell.X, hidden.Cell.Y)); err != nil {
+		t.Fatalf("spawnHintPod returned error: %v", err)
+	}
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: hidden.Cell.HintPodName(), Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get hint pod: %v", err)
+	}
+	if got := pod.Annotations[AnnotationFlagFragment]; got != hidden.String() {
+		t.Errorf("expected fragment annotation %q, got %q", hidden.String(), got)
+	}
+
+	if err := handlers.spawnVictoryPod(ctx, state); err != nil {
+		t.Fatalf("spawnVictoryPod returned error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "victory", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get victory pod: %v", err)
+	}
+	final, _ := state.FinalFlagFragment()
+	if command := strings.Join(pod.Spec.Containers[0].Command, " "); !strings.Contains(command, "Final flag fragment: "+final.String()) {
+		t.Errorf("expected final fragment in victory message, got %q", command)
+	}
+}
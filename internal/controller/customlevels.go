@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/zwindler/podsweeper/pkg/api/v1alpha1"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+// levelConflictRetry is how often a PodSweeperLevel defining the same
+// level as another one is retried, in case the other one goes away.
+const levelConflictRetry = time.Minute
+
+// LevelReconciler registers the PodSweeperLevels of the game namespace as
+// custom levels, see level.Register, so game organizers define levels
+// declaratively. The board of a PodSweeperLevel is registered as a
+// difficulty preset named after it, whose games are played at its level.
+// The Ready condition of each PodSweeperLevel tells whether it is played:
+// invalid levels, and levels whose number is already defined by another
+// PodSweeperLevel, are not.
+type LevelReconciler struct {
+	client.Client
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Presets gets the boards of the levels. Optional.
+	Presets *grid.PresetRegistry
+
+	mu sync.Mutex
+	// levels maps the PodSweeperLevels registered to their level.
+	levels map[string]int
+}
+
+// Reconcile registers a PodSweeperLevel, or unregisters it once deleted.
+func (r *LevelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	obj := &v1alpha1.PodSweeperLevel{}
+	if err := r.Get(ctx, req.NamespacedName, obj); errors.IsNotFound(err) {
+		r.unregister(req.Name)
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !obj.DeletionTimestamp.IsZero() {
+		r.unregister(obj.Name)
+		return ctrl.Result{}, nil
+	}
+
+	var result ctrl.Result
+	condition := metav1.Condition{Type: v1alpha1.ConditionReady, ObservedGeneration: obj.Generation}
+	custom := obj.Spec.Custom()
+	board, hasBoard, err := obj.Spec.Config()
+	if err == nil {
+		err = custom.Validate()
+	}
+	switch owner, conflict := r.owner(custom.Level, obj.Name); {
+	case err != nil:
+		r.unregister(obj.Name)
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "Invalid", err.Error()
+	case conflict:
+		r.unregister(obj.Name)
+		condition.Status, condition.Reason = metav1.ConditionFalse, "Conflict"
+		condition.Message = fmt.Sprintf("level %d is already defined by %s", custom.Level, owner)
+		result.RequeueAfter = levelConflictRetry
+	default:
+		r.register(obj.Name, custom, board, hasBoard)
+		condition.Status, condition.Reason = metav1.ConditionTrue, "Registered"
+		condition.Message = fmt.Sprintf("level %d is %s", custom.Level, level.For(custom.Level).Name())
+		logger.Info("registered custom level", "level", custom.Level, "name", obj.Name)
+	}
+
+	if meta.SetStatusCondition(&obj.Status.Conditions, condition) || obj.Status.ObservedGeneration != obj.Generation {
+		obj.Status.ObservedGeneration = obj.Generation
+		if err := r.Status().Update(ctx, obj); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update the status of level %s: %w", obj.Name, err)
+		}
+	}
+	return result, nil
+}
+
+// owner returns the other PodSweeperLevel the level is registered for.
+func (r *LevelReconciler) owner(lvl int, name string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for other, l := range r.levels {
+		if l == lvl && other != name {
+			return other, true
+		}
+	}
+	return "", false
+}
+
+// register registers the level and board of a PodSweeperLevel, replacing
+// its previous level.
+func (r *LevelReconciler) register(name string, custom level.Custom, board grid.Config, hasBoard bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.levels == nil {
+		r.levels = map[string]int{}
+	}
+	if previous, ok := r.levels[name]; ok && previous != custom.Level {
+		level.Unregister(previous)
+	}
+	// Validated by the caller
+	_ = level.Register(custom)
+	r.levels[name] = custom.Level
+	if r.Presets != nil {
+		if hasBoard {
+			r.Presets.Set(grid.DifficultyPreset(name), board)
+		} else {
+			r.Presets.Delete(grid.DifficultyPreset(name))
+		}
+	}
+}
+
+// unregister unregisters the level and board of a PodSweeperLevel.
+func (r *LevelReconciler) unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lvl, ok := r.levels[name]; ok {
+		level.Unregister(lvl)
+		delete(r.levels, name)
+		if r.Presets != nil {
+			r.Presets.Delete(grid.DifficultyPreset(name))
+		}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LevelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("podsweeperlevel").
+		For(&v1alpha1.PodSweeperLevel{}).
+		// Every replica plays the levels in its webhooks
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetNamespace() == r.Namespace
+		})).
+		Complete(r)
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/api/v1alpha1"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+func newLevelReconciler(objs ...client.Object) *LevelReconciler {
+	scheme := newTestScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.PodSweeperLevel{}).Build()
+	return &LevelReconciler{Client: c, Namespace: "game", Presets: grid.NewPresetRegistry()}
+}
+
+func newPodSweeperLevel(name string, spec v1alpha1.PodSweeperLevelSpec) *v1alpha1.PodSweeperLevel {
+	return &v1alpha1.PodSweeperLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "game", Generation: 1},
+		Spec:       spec,
+	}
+}
+
+func reconcileLevel(t *testing.T, r *LevelReconciler, name string) (ctrl.Result, *metav1.Condition) {
+	t.Helper()
+	key := types.NamespacedName{Namespace: "game", Name: name}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	obj := &v1alpha1.PodSweeperLevel{}
+	if err := r.Get(context.Background(), key, obj); err != nil {
+		return result, nil
+	}
+	return result, meta.FindStatusCondition(obj.Status.Conditions, v1alpha1.ConditionReady)
+}
+
+func TestLevelReconciler_Register(t *testing.T) {
+	t.Cleanup(func() { level.Unregister(12) })
+	vault := newPodSweeperLevel("vault", v1alpha1.PodSweeperLevelSpec{
+		Level:       12,
+		DisplayName: "The Vault",
+		Board:       &v1alpha1.LevelBoard{Width: 20, Height: 12, MineDensity: 0.2},
+	})
+	r := newLevelReconciler(vault)
+
+	_, condition := reconcileLevel(t, r, "vault")
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "Registered" ||
+		condition.ObservedGeneration != 1 {
+		t.Fatalf("expected the level to be ready, got %+v", condition)
+	}
+	if p := level.For(12); p.Name() != "The Vault" {
+		t.Errorf("level 12 = %q, want The Vault", p.Name())
+	}
+	if config, ok := r.Presets.Get("vault"); !ok || config.Level != 12 {
+		t.Errorf("expected the board registered as a preset at level 12, got %+v (ok=%v)", config, ok)
+	}
+
+	if err := r.Delete(context.Background(), vault); err != nil {
+		t.Fatal(err)
+	}
+	reconcileLevel(t, r, "vault")
+	if p := level.For(12); p.Name() == "The Vault" {
+		t.Error("level 12 should be unregistered once deleted")
+	}
+	if _, ok := r.Presets.Get("vault"); ok {
+		t.Error("the preset of the level should be removed once deleted")
+	}
+}
+
+func TestLevelReconciler_Invalid(t *testing.T) {
+	r := newLevelReconciler(newPodSweeperLevel("broken", v1alpha1.PodSweeperLevelSpec{
+		Level:       11,
+		Protections: v1alpha1.LevelProtections{Leak: "printer"},
+	}))
+
+	_, condition := reconcileLevel(t, r, "broken")
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Invalid" {
+		t.Fatalf("expected the level to be invalid, got %+v", condition)
+	}
+	if p := level.For(11); p.Level() == 11 {
+		t.Error("invalid levels should not be registered")
+	}
+}
+
+func TestLevelReconciler_Conflict(t *testing.T) {
+	t.Cleanup(func() { level.Unregister(13) })
+	r := newLevelReconciler(
+		newPodSweeperLevel("first", v1alpha1.PodSweeperLevelSpec{Level: 13, DisplayName: "First"}),
+		newPodSweeperLevel("second", v1alpha1.PodSweeperLevelSpec{Level: 13, DisplayName: "Second"}),
+	)
+
+	reconcileLevel(t, r, "first")
+	result, condition := reconcileLevel(t, r, "second")
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Conflict" {
+		t.Fatalf("expected the second level to conflict, got %+v", condition)
+	}
+	if result.RequeueAfter != levelConflictRetry {
+		t.Errorf("expected conflicts to be retried after %v, got %v", levelConflictRetry, result.RequeueAfter)
+	}
+	if p := level.For(13); p.Name() != "First" {
+		t.Errorf("level 13 = %q, want the first level kept", p.Name())
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DebugState is the dump of the game state served by DebugStateHandler.
+type DebugState struct {
+	// ID identifies the game, whose seed may be redacted.
+	ID string `json:"id"`
+	// Redacted tells whether the secrets of the game were removed, see
+	// game.GameState.Redacted.
+	Redacted bool `json:"redacted"`
+	// State is the full game state.
+	State *game.GameState `json:"state"`
+}
+
+// DebugStateHandler dumps the full game state as indented JSON, to debug
+// stuck games without decoding the state Secret by hand. The mines are
+// redacted until the game is over, unless showMines is set.
+func DebugStateHandler(store game.Store, showMines bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		if state == nil {
+			http.Error(w, "no game running", http.StatusNotFound)
+			return
+		}
+
+		dump := DebugState{ID: state.ID(), State: state}
+		if !showMines && state.Status == game.StatusPlaying {
+			dump.State, dump.Redacted = state.Redacted(), true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(dump)
+	})
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestDebugStateHandler(t *testing.T) {
+	store := game.NewMemoryStore()
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.Reveal(1, 1)
+	_ = store.Save(context.Background(), state)
+
+	dump := func(showMines bool) DebugState {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		DebugStateHandler(store, showMines).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/state", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected the state, got %d", rec.Code)
+		}
+		var got DebugState
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("invalid dump: %v", err)
+		}
+		return got
+	}
+
+	got := dump(false)
+	if !got.Redacted || got.ID != state.ID() || got.State.IsMine(0, 0) || !got.State.IsRevealed(1, 1) {
+		t.Errorf("expected the mines of the game in progress to be redacted, got %+v", got)
+	}
+	if got := dump(true); got.Redacted || !got.State.IsMine(0, 0) {
+		t.Errorf("expected the mines with the cheat flag, got %+v", got)
+	}
+
+	state.HitMine(0, 0, "")
+	_ = store.Save(context.Background(), state)
+	if got := dump(false); got.Redacted || !got.State.IsMine(0, 0) {
+		t.Errorf("expected the mines once the game is over, got %+v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultHintDecayInterval is how often expired hints are removed in hint
+// decay mode.
+const DefaultHintDecayInterval = time.Second
+
+// HintDecayer removes the hints of games in hint decay mode once they
+// expire, so that players have to remember or note them. Hint pods stop on
+// their own when their hint expires, see spawnHintPod; the decayer deletes
+// them, or drops their value from the hint aggregator. The cell stays
+// revealed: deleting a hint pod is not a move. It runs as a manager
+// Runnable, only on the leader.
+type HintDecayer struct {
+	// Client deletes hint pods.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Handlers publish the hints to the hint aggregator, if enabled.
+	Handlers *GameHandlers
+
+	// Interval between passes. Defaults to DefaultHintDecayInterval.
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable.
+func (d *HintDecayer) Start(ctx context.Context) error {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = DefaultHintDecayInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("decay")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := d.Decay(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to remove decayed hints")
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (d *HintDecayer) NeedLeaderElection() bool {
+	return true
+}
+
+// Decay removes the hints expired as of now and returns the cells whose
+// hint pod it deleted.
+func (d *HintDecayer) Decay(ctx context.Context, now time.Time) ([]game.Coordinate, error) {
+	state, err := d.Store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || state.HintDecay <= 0 || !state.CurrentPhase().Playable() {
+		return nil, nil
+	}
+	if err := d.Handlers.syncHints(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to publish hints: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := d.Client.List(ctx, pods, client.InNamespace(d.Namespace),
+		client.MatchingLabels{LabelApp: "podsweeper", LabelComponent: "hint"}); err != nil {
+		return nil, fmt.Errorf("failed to list hint pods: %w", err)
+	}
+
+	logger := log.FromContext(ctx).WithName("decay")
+	var decayed []game.Coordinate
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		c, ok := ParseHintPodName(pod.Name)
+		if !ok || !pod.DeletionTimestamp.IsZero() || !state.HintDecayed(c.X, c.Y, now) {
+			continue
+		}
+		if err := d.Client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return decayed, fmt.Errorf("failed to delete %s: %w", pod.Name, err)
+		}
+		logger.Info("hint decayed", "coords", c)
+		decayed = append(decayed, c)
+	}
+	return decayed, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestHintDecayer_Decay(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	handlers := NewGameHandlers(c, store, testNamespace)
+	decayer := &HintDecayer{Client: c, Store: store, Namespace: testNamespace, Handlers: handlers}
+	key := types.NamespacedName{Name: "hint-0-0", Namespace: testNamespace}
+
+	state := createTestGameState(3)
+	state.HintDecay = time.Minute
+	_ = store.Save(ctx, state)
+	if _, err := handlers.HandleHintCell(ctx, state, game.Coordinate{X: 0, Y: 0}, 1); err != nil {
+		t.Fatalf("HandleHintCell failed: %v", err)
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, key, pod); err != nil {
+		t.Fatalf("expected a hint pod: %v", err)
+	}
+	if d := pod.Spec.ActiveDeadlineSeconds; d == nil || *d < 59 || *d > 60 {
+		t.Errorf("expected the hint pod to stop when its hint expires, got %v", d)
+	}
+
+	decayed, err := decayer.Decay(ctx, time.Now())
+	if err != nil || len(decayed) != 0 {
+		t.Fatalf("expected no hint to decay yet, got %v: %v", decayed, err)
+	}
+	decayed, err = decayer.Decay(ctx, time.Now().Add(time.Minute))
+	if err != nil || len(decayed) != 1 || decayed[0] != (game.Coordinate{X: 0, Y: 0}) {
+		t.Fatalf("expected the hint of (0,0) to decay, got %v: %v", decayed, err)
+	}
+	if err := c.Get(ctx, key, pod); !errors.IsNotFound(err) {
+		t.Errorf("expected the decayed hint pod to be deleted, got %v", err)
+	}
+
+	// The deletion of the hint pod is not a move
+	before, _ := store.Load(ctx)
+	r := NewGameController(c, GameControllerConfig{Namespace: testNamespace, Store: store})
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	after, _ := store.Load(ctx)
+	if !after.IsRevealed(0, 0) || after.Clicks != before.Clicks || after.Status != game.StatusPlaying {
+		t.Errorf("expected the game to be unchanged, got %+v", after.Stats())
+	}
+}
+
+func TestHintDecayer_DecayOff(t *testing.T) {
+	ctx := context.Background()
+	hint := createTestHintPod(game.Coordinate{X: 0, Y: 0}, "1")
+	hint.Labels[LabelComponent] = "hint"
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(hint).Build()
+	store := game.NewMemoryStore()
+	decayer := &HintDecayer{Client: c, Store: store, Namespace: testNamespace, Handlers: NewGameHandlers(c, store, testNamespace)}
+
+	state := createTestGameState(3)
+	state.RevealWith(0, 0, game.RevealInfo{At: time.Now().Add(-time.Hour)})
+	_ = store.Save(ctx, state)
+	if decayed, err := decayer.Decay(ctx, time.Now()); err != nil || len(decayed) != 0 {
+		t.Errorf("expected hints to last without hint decay, got %v: %v", decayed, err)
+	}
+}
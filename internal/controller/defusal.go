@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/defusal"
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/version"
+)
+
+// DefaultDefusalTimeout is how long players have to cut the right wire of a
+// bomb in defusal mode.
+const DefaultDefusalTimeout = time.Minute
+
+// startDefusal gives the player who hit a mine in defusal mode a chance to
+// defuse it: the mine only explodes if the challenge of its bomb pod is
+// failed.
+func (r *GameController) startDefusal(ctx context.Context, state *game.GameState, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	d, pending := state.PendingDefusal(coords.X, coords.Y)
+	if !pending {
+		rng := r.Rand
+		if rng == nil {
+			rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+		}
+		d = game.Defusal{
+			Coordinate: coords,
+			Wires:      defusal.New(rng).Wires,
+			By:         moverFrom(ctx),
+			Deadline:   time.Now().Add(r.defusalTimeout()),
+		}
+		if !state.StartDefusal(d) {
+			return r.Handlers.HandleMineHit(ctx, state, coords)
+		}
+		// A mine hit ends the safe streak, defused or not
+		state.PowerUps.Streak = 0
+		if err := r.Store.Save(ctx, state); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+		}
+	}
+
+	// Retried until the bomb pod exists, which then keeps the time
+	pod := r.buildBombPod(d)
+	if err := r.Create(ctx, pod); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to spawn bomb pod: %w", err)
+	}
+	logger.Info("mine hit, defusal started", "coords", coords, "by", d.By, "deadline", d.Deadline)
+	if r.Recorder != nil && !pending {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, "MineHit", "Defuse",
+			"You hit a mine! Defuse it before %s: kubectl logs -n %s %s",
+			d.Deadline.Format(time.TimeOnly), r.Namespace, pod.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileBomb settles the defusal challenge of a bomb pod: cutting the
+// right wire flags the mine and restores its cell pod; cutting a wrong
+// one, deleting the bomb pod or running out of time sets the mine off.
+func (r *GameController) reconcileBomb(ctx context.Context, req ctrl.Request, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		logger.Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to load game state")
+		return ctrl.Result{}, err
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return ctrl.Result{}, nil
+	}
+	d, ok := state.PendingDefusal(coords.X, coords.Y)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	answer, cut := "", false
+	pod := &corev1.Pod{}
+	err = r.Get(ctx, req.NamespacedName, pod)
+	switch {
+	case errors.IsNotFound(err):
+		logger.Info("bomb pod deleted", "coords", coords)
+	case err != nil:
+		return ctrl.Result{}, err
+	default:
+		answer, cut = cutWire(pod)
+		if !cut && !pod.DeletionTimestamp.IsZero() {
+			// Set off once it's gone
+			return ctrl.Result{}, nil
+		}
+		if wait := time.Until(d.Deadline); !cut && wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	defused := cut && defusal.Challenge{Wires: d.Wires}.Check(answer)
+	state.EndDefusal(coords.X, coords.Y, defused)
+	logger.Info("defusal ended", "coords", coords, "by", d.By, "cut", answer, "defused", defused)
+	if err := client.IgnoreNotFound(r.Delete(ctx, pod)); err != nil {
+		logger.Error(err, "failed to delete bomb pod", "coords", coords)
+	}
+	if !defused {
+		return r.Handlers.HandleMineHit(withMover(ctx, d.By), state, coords)
+	}
+
+	if err := r.Store.Save(ctx, state); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+	}
+	if err := r.createCellPod(ctx, state, coords); err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, "Defused", "Defuse",
+			"Mine at (%d, %d) defused and flagged", coords.X, coords.Y)
+	}
+	return ctrl.Result{}, nil
+}
+
+// defusalTimeout returns how long players have to defuse a bomb.
+func (r *GameController) defusalTimeout() time.Duration {
+	if r.DefusalTimeout > 0 {
+		return r.DefusalTimeout
+	}
+	return DefaultDefusalTimeout
+}
+
+// cutWire returns the wire cut in a bomb pod, once its container exited.
+func cutWire(pod *corev1.Pod) (string, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return status.State.Terminated.Message, true
+		}
+	}
+	return "", false
+}
+
+// buildBombPod builds the pod showing a defusal challenge. It waits for the
+// player to write the number of a wire to defusal.AnswerFile, and exits
+// with it as termination message.
+func (r *GameController) buildBombPod(d game.Defusal) *corev1.Pod {
+	name := d.BombPodName()
+	prompt := defusal.Challenge{Wires: d.Wires}.Prompt(r.Namespace, name, time.Until(d.Deadline).Round(time.Second))
+	script := fmt.Sprintf("cat <<'EOF'\n%sEOF\nuntil [ -s %[2]s ]; do sleep 1; done\ncat %[2]s > /dev/termination-log\n",
+		prompt, defusal.AnswerFile)
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				LabelApp:       "podsweeper",
+				LabelComponent: "bomb",
+				LabelCoordX:    strconv.Itoa(d.X),
+				LabelCoordY:    strconv.Itoa(d.Y),
+			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "bomb",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         []string{"sh", "-c", script},
+					// The root filesystem is read-only
+					VolumeMounts: []corev1.VolumeMount{{Name: "tmp", MountPath: "/tmp"}},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/defusal"
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// hitMineInDefusalMode deletes the mine of a 3x3 grid in defusal mode and
+// returns the pending defusal.
+func hitMineInDefusalMode(t *testing.T) (*GameController, client.Client, game.Defusal) {
+	t.Helper()
+	ctx := context.Background()
+	// Mine at (1,1)
+	state := createTestGameState(3)
+	state.Defusal = true
+	controller, c, _ := newTutorialController(t, state)
+
+	reconcilePod(t, controller, "pod-1-1")
+
+	saved, _ := controller.Store.Load(ctx)
+	d, ok := saved.PendingDefusal(1, 1)
+	if !ok || saved.Status != game.StatusPlaying {
+		t.Fatalf("expected a pending defusal, got %s", saved.Status)
+	}
+	bomb := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "bomb-1-1"}, bomb); err != nil {
+		t.Fatalf("expected a bomb pod: %v", err)
+	}
+	if script := bomb.Spec.Containers[0].Command[2]; !strings.Contains(script, "kubectl exec -n "+testNamespace+" bomb-1-1") {
+		t.Errorf("expected the bomb pod to tell how to cut a wire, got %q", script)
+	}
+	return controller, c, d
+}
+
+// cutBombWire simulates the bomb pod exiting after the player wrote answer.
+func cutBombWire(t *testing.T, c client.Client, answer string) {
+	t.Helper()
+	bomb := &corev1.Pod{}
+	_ = c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "bomb-1-1"}, bomb)
+	bomb.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "bomb",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: answer + "\n"}},
+	}}
+	if err := c.Status().Update(context.Background(), bomb); err != nil {
+		t.Fatalf("failed to update bomb pod: %v", err)
+	}
+}
+
+func TestGameController_Defusal(t *testing.T) {
+	tests := []struct {
+		name        string
+		act         func(t *testing.T, controller *GameController, c client.Client, d game.Defusal)
+		wantDefused bool
+	}{
+		{
+			name: "right wire",
+			act: func(t *testing.T, _ *GameController, c client.Client, d game.Defusal) {
+				cutBombWire(t, c, strconv.Itoa(defusal.Challenge{Wires: d.Wires}.Solution()))
+			},
+			wantDefused: true,
+		},
+		{
+			name: "wrong wire",
+			act: func(t *testing.T, _ *GameController, c client.Client, d game.Defusal) {
+				cutBombWire(t, c, strconv.Itoa(defusal.Challenge{Wires: d.Wires}.Solution()%len(d.Wires)+1))
+			},
+		},
+		{
+			name: "bomb pod deleted",
+			act: func(t *testing.T, _ *GameController, c client.Client, _ game.Defusal) {
+				deletePods(t, c, "bomb-1-1")
+			},
+		},
+		{
+			name: "out of time",
+			act: func(t *testing.T, controller *GameController, _ client.Client, _ game.Defusal) {
+				state, _ := controller.Store.Load(context.Background())
+				state.Defusals[0].Deadline = time.Now().Add(-time.Second)
+				_ = controller.Store.Save(context.Background(), state)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			controller, c, d := hitMineInDefusalMode(t)
+			tt.act(t, controller, c, d)
+
+			reconcilePod(t, controller, "bomb-1-1")
+
+			saved, _ := controller.Store.Load(ctx)
+			if _, ok := saved.PendingDefusal(1, 1); ok {
+				t.Error("expected the defusal ended")
+			}
+			if tt.wantDefused {
+				if saved.Status != game.StatusPlaying || !saved.IsFlagged(1, 1) {
+					t.Errorf("expected the mine flagged and the game going on, got %s", saved.Status)
+				}
+				if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, &corev1.Pod{}); err != nil {
+					t.Errorf("expected the cell pod restored: %v", err)
+				}
+			} else if saved.Status != game.StatusLost {
+				t.Errorf("expected the mine to explode, got %s", saved.Status)
+			}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "bomb-1-1"}, &corev1.Pod{}); err == nil {
+				t.Error("expected the bomb pod deleted")
+			}
+		})
+	}
+}
+
+func TestGameController_DefusalWaitsForTheDeadline(t *testing.T) {
+	controller, _, _ := hitMineInDefusalMode(t)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "bomb-1-1"}}
+	result, err := controller.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > DefaultDefusalTimeout {
+		t.Errorf("expected a requeue at the deadline, got %v", result.RequeueAfter)
+	}
+
+	// The cell pod isn't restored while the bomb ticks
+	saved, _ := controller.Store.Load(context.Background())
+	if drift := DetectDrift(saved, nil); len(drift.MissingCells) != 8 {
+		t.Errorf("expected the mine cell left out of the missing cells, got %v", drift.MissingCells)
+	}
+}
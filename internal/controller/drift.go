@@ -0,0 +1,286 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// DefaultDriftInterval is how often game pods are compared with the game state.
+const DefaultDriftInterval = 30 * time.Second
+
+// Drift lists the differences between the game pods and what the game
+// state implies: a cell pod per hidden cell, a hint pod per revealed cell
+// showing a hint that hasn't decayed, and a marker pod per defused mine.
+type Drift struct {
+	// MissingCells are hidden cells without a pod, other than mines
+	// being defused.
+	MissingCells []game.Coordinate
+
+	// MissingHints are revealed cells with adjacent mines without a hint pod.
+	MissingHints []game.Coordinate
+
+	// MissingDefused are defused mines without a marker pod.
+	MissingDefused []game.Coordinate
+
+	// Extra are the names of pods the game state doesn't imply, such as
+	// cell pods of revealed cells or hint pods showing a wrong value.
+	Extra []string
+}
+
+// IsEmpty reports whether the pods match the game state.
+func (d Drift) IsEmpty() bool {
+	return len(d.MissingCells) == 0 && len(d.MissingHints) == 0 && len(d.MissingDefused) == 0 && len(d.Extra) == 0
+}
+
+// DetectDrift compares game pods with the game state. Pods other than
+// cell, hint and defused marker pods are ignored. Decayed hints have no
+// hint pod.
+func DetectDrift(state *game.GameState, pods []corev1.Pod) Drift {
+	var drift Drift
+	now := time.Now()
+	cells := make(map[game.Coordinate]bool)
+	hintPods := make(map[game.Coordinate]bool)
+	defused := make(map[game.Coordinate]bool)
+
+	for _, pod := range pods {
+		// Pods being deleted are still there, but already on their way out
+		terminating := !pod.DeletionTimestamp.IsZero()
+		if c, ok := ParsePodName(pod.Name); ok {
+			cells[c] = true
+			cell, valid := state.Cell(c.X, c.Y)
+			if !terminating && (!valid || cell.Revealed || cell.Defused) {
+				drift.Extra = append(drift.Extra, pod.Name)
+			}
+		} else if c, ok := ParseHintPodName(pod.Name); ok {
+			hintPods[c] = true
+			cell, valid := state.Cell(c.X, c.Y)
+			hint, shown := state.HintAt(c.X, c.Y)
+			// Scrambled hints can't be checked without the scramble key
+			annotated := pod.Annotations[AnnotationHint]
+			wrongHint := annotated != hint && !hints.IsScrambled(annotated)
+			if !terminating && (!valid || !cell.Revealed || !shown || wrongHint || state.HintDecayed(c.X, c.Y, now)) {
+				drift.Extra = append(drift.Extra, pod.Name)
+			}
+		} else if c, ok := ParseDefusedPodName(pod.Name); ok {
+			defused[c] = true
+			if !terminating && !state.IsDefused(c.X, c.Y) {
+				drift.Extra = append(drift.Extra, pod.Name)
+			}
+		}
+	}
+
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			cell := state.Cells[x][y]
+			switch {
+			case cell.Defused:
+				if !defused[c] {
+					drift.MissingDefused = append(drift.MissingDefused, c)
+				}
+			case !cell.Revealed:
+				// A mine being defused gets its cell pod back only once defused
+				if _, defusing := state.PendingDefusal(x, y); !cells[c] && !defusing {
+					drift.MissingCells = append(drift.MissingCells, c)
+				}
+			case !cell.Mine:
+				if _, shown := state.HintAt(x, y); shown && !hintPods[c] && !state.HintDecayed(x, y, now) {
+					drift.MissingHints = append(drift.MissingHints, c)
+				}
+			}
+		}
+	}
+	return drift
+}
+
+// stalePodUIDs returns the UIDs of the pods of hidden cells that differ
+// from the ones recorded in state.
+func stalePodUIDs(state *game.GameState, pods []corev1.Pod) map[game.Coordinate]string {
+	uids := make(map[game.Coordinate]string)
+	for _, pod := range pods {
+		c, ok := ParsePodName(pod.Name)
+		if !ok || pod.UID == "" || !pod.DeletionTimestamp.IsZero() || state.IsRevealed(c.X, c.Y) {
+			continue
+		}
+		if uid := string(pod.UID); state.PodUID(c.X, c.Y) != uid {
+			uids[c] = uid
+		}
+	}
+	return uids
+}
+
+// DriftCorrector continuously converges the game pods toward what the game
+// state implies, so that pods deleted or edited by hand are restored.
+// A cell pod that disappears is usually a click the controller is about to
+// play, so it is only recreated if it's still missing on the next pass.
+// Hint pods showing a wrong value are deleted and recreated on the next
+// pass. It runs as a manager Runnable, only on the leader, and leaves ended
+// games alone.
+type DriftCorrector struct {
+	// Client reads and corrects game pods.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Handlers create hint and defused marker pods.
+	Handlers *GameHandlers
+
+	// Spawner creates cell pods.
+	Spawner *spawner.GridSpawner
+
+	// Protected lists pods that are never touched.
+	Protected ProtectionRules
+
+	// Interval between passes. Defaults to DefaultDriftInterval.
+	Interval time.Duration
+
+	// missing are the cells found without a pod on the previous pass.
+	missing map[game.Coordinate]bool
+}
+
+// Start implements manager.Runnable.
+func (d *DriftCorrector) Start(ctx context.Context) error {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = DefaultDriftInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("drift")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := d.Correct(ctx); err != nil {
+			logger.Error(err, "failed to correct drift")
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (d *DriftCorrector) NeedLeaderElection() bool {
+	return true
+}
+
+// savePodUIDs records the UIDs of the pods of hidden cells in the latest
+// game state, so moves played during the pass are kept.
+func (d *DriftCorrector) savePodUIDs(ctx context.Context, uids map[game.Coordinate]string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	state, err := d.Store.Load(ctx)
+	if err != nil || state == nil {
+		return err
+	}
+	for c, uid := range uids {
+		if !state.IsRevealed(c.X, c.Y) {
+			state.SetPodUID(c.X, c.Y, uid)
+		}
+	}
+	if err := d.Store.Save(ctx, state); err != nil {
+		return fmt.Errorf("failed to record pod UIDs: %w", err)
+	}
+	return nil
+}
+
+// Correct runs one pass and returns the drift it found.
+func (d *DriftCorrector) Correct(ctx context.Context) (Drift, error) {
+	logger := log.FromContext(ctx).WithName("drift")
+
+	state, err := d.Store.Load(ctx)
+	if err != nil {
+		return Drift{}, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || !state.CurrentPhase().Playable() {
+		d.missing = nil
+		return Drift{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := d.Client.List(ctx, podList, client.InNamespace(d.Namespace)); err != nil {
+		return Drift{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if !d.Protected.Protects(&pod) {
+			pods = append(pods, pod)
+		}
+	}
+
+	// Deletions are only played for the pods recorded in the game state,
+	// so pods spawned without recording their UID are recorded here
+	uids := stalePodUIDs(state, pods)
+	drift := DetectDrift(state, pods)
+	if d.Handlers.aggregator != nil {
+		// The aggregator serves the hints: republish them instead of hint pods
+		drift.MissingHints = nil
+		if err := d.Handlers.syncHints(ctx, state); err != nil {
+			return drift, fmt.Errorf("failed to publish hints: %w", err)
+		}
+	}
+	if drift.IsEmpty() {
+		d.missing = nil
+		return drift, d.savePodUIDs(ctx, uids)
+	}
+	logger.Info("correcting drift", "missingCells", len(drift.MissingCells),
+		"missingHints", len(drift.MissingHints), "missingDefused", len(drift.MissingDefused), "extra", len(drift.Extra))
+
+	var errs []error
+	for _, name := range drift.Extra {
+		pod := &corev1.Pod{}
+		pod.Name, pod.Namespace = name, d.Namespace
+		if err := d.Client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", name, err))
+		}
+	}
+	for _, c := range drift.MissingHints {
+		if err := d.Handlers.spawnHintPod(ctx, state, c, state.AdjacentMines(c.X, c.Y)); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", c.HintPodName(), err))
+		}
+	}
+	for _, c := range drift.MissingDefused {
+		if err := d.Handlers.spawnDefusedPod(ctx, c); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", c.DefusedPodName(), err))
+		}
+	}
+
+	missing := make(map[game.Coordinate]bool, len(drift.MissingCells))
+	for _, c := range drift.MissingCells {
+		if !d.missing[c] {
+			missing[c] = true
+			continue
+		}
+		if err := spawnCellPod(ctx, d.Client, d.Spawner, state, c); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", c.PodName(), err))
+			continue
+		}
+		uids[c] = state.PodUID(c.X, c.Y)
+	}
+	d.missing = missing
+	if err := d.savePodUIDs(ctx, uids); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return drift, fmt.Errorf("%d corrections failed, first: %w", len(errs), errs[0])
+	}
+	return drift, nil
+}
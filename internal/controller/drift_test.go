@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func newTestDriftCorrector(c client.Client, store game.Store) *DriftCorrector {
+	return &DriftCorrector{
+		Client:    c,
+		Store:     store,
+		Namespace: testNamespace,
+		Handlers:  NewGameHandlers(c, store, testNamespace),
+		Spawner:   spawner.NewGridSpawner(c, spawner.GridSpawnerConfig{Namespace: testNamespace}),
+	}
+}
+
+func createTestHintPod(c game.Coordinate, hint string) *corev1.Pod {
+	pod := createTestPod(c.HintPodName(), testNamespace)
+	pod.Annotations = map[string]string{AnnotationHint: hint}
+	return pod
+}
+
+func TestDetectDrift(t *testing.T) {
+	// Mine at (1,1): (0,0) has a hint of 1
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	origin := game.Coordinate{X: 0, Y: 0}
+
+	cellPods := func(skip ...string) []corev1.Pod {
+		skipped := make(map[string]bool)
+		for _, name := range skip {
+			skipped[name] = true
+		}
+		var pods []corev1.Pod
+		for x := 0; x < 3; x++ {
+			for y := 0; y < 3; y++ {
+				name := game.Coordinate{X: x, Y: y}.PodName()
+				if (x != 0 || y != 0) && !skipped[name] {
+					pods = append(pods, *createTestPod(name, testNamespace))
+				}
+			}
+		}
+		return pods
+	}
+	terminating := createTestPod("pod-0-0", testNamespace)
+	terminating.Finalizers = []string{"test"}
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want Drift
+	}{
+		{
+			name: "in sync",
+			pods: append(cellPods(), *createTestHintPod(origin, "1")),
+		},
+		{
+			name: "missing cell and hint",
+			pods: cellPods("pod-2-2"),
+			want: Drift{MissingCells: []game.Coordinate{{X: 2, Y: 2}}, MissingHints: []game.Coordinate{origin}},
+		},
+		{
+			name: "wrong hint value",
+			pods: append(cellPods(), *createTestHintPod(origin, "3")),
+			want: Drift{Extra: []string{"hint-0-0"}},
+		},
+		{
+			name: "stale cell pod and unknown hint",
+			pods: append(cellPods(), *createTestHintPod(origin, "1"),
+				*createTestPod("pod-0-0", testNamespace), *createTestHintPod(game.Coordinate{X: 2, Y: 2}, "1")),
+			want: Drift{Extra: []string{"pod-0-0", "hint-2-2"}},
+		},
+		{
+			name: "terminating pods are left alone",
+			pods: append(cellPods(), *createTestHintPod(origin, "1"), *terminating),
+		},
+		{
+			name: "other pods are ignored",
+			pods: append(cellPods(), *createTestHintPod(origin, "1"), *createTestPod("gamemaster", testNamespace)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectDrift(state, tt.pods)
+			if !equalCoords(got.MissingCells, tt.want.MissingCells) ||
+				!equalCoords(got.MissingHints, tt.want.MissingHints) ||
+				!equalCoords(got.MissingDefused, tt.want.MissingDefused) ||
+				!equalNames(got.Extra, tt.want.Extra) {
+				t.Errorf("DetectDrift() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDrift_DecayedHints(t *testing.T) {
+	state := createTestGameState(3)
+	state.HintDecay = time.Minute
+	state.RevealWith(0, 0, game.RevealInfo{At: time.Now().Add(-2 * time.Minute)})
+	state.RevealWith(2, 2, game.RevealInfo{At: time.Now()})
+
+	// The decayed hint is neither restored nor kept
+	drift := DetectDrift(state, []corev1.Pod{*createTestHintPod(game.Coordinate{X: 0, Y: 0}, "1")})
+	if !equalCoords(drift.MissingHints, []game.Coordinate{{X: 2, Y: 2}}) {
+		t.Errorf("expected only the hint of (2,2) to be missing, got %v", drift.MissingHints)
+	}
+	if !equalNames(drift.Extra, []string{"hint-0-0"}) {
+		t.Errorf("expected the decayed hint pod to be extra, got %v", drift.Extra)
+	}
+}
+
+func TestDetectDrift_DefusedMines(t *testing.T) {
+	state := createTestGameState(3)
+	state.Cells[1][1].Defused = true
+
+	drift := DetectDrift(state, nil)
+	if !equalCoords(drift.MissingDefused, []game.Coordinate{{X: 1, Y: 1}}) {
+		t.Errorf("expected the defused marker to be missing, got %+v", drift.MissingDefused)
+	}
+	for _, c := range drift.MissingCells {
+		if c.X == 1 && c.Y == 1 {
+			t.Error("a defused mine should not need a cell pod")
+		}
+	}
+}
+
+func TestDriftCorrector_RecreatesMissingCellOnSecondPass(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	var objs []client.Object
+	for _, obj := range spawnTestCells(state) {
+		if obj.GetName() != "pod-2-2" {
+			objs = append(objs, obj)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objs...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	d := newTestDriftCorrector(c, store)
+
+	// The first pass could race a click being played, so nothing is created
+	if _, err := d.Correct(ctx); err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	if remainingCells(t, c)["pod-2-2"] {
+		t.Fatal("expected pod-2-2 not to be recreated on the first pass")
+	}
+
+	if _, err := d.Correct(ctx); err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	if !remainingCells(t, c)["pod-2-2"] {
+		t.Fatal("expected pod-2-2 to be recreated on the second pass")
+	}
+}
+
+func TestDriftCorrector_RecordsPodUIDs(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	pod := createTestPod("pod-1-1", testNamespace)
+	pod.UID = "uid-repaired"
+	var objs []client.Object
+	for _, obj := range spawnTestCells(state) {
+		if obj.GetName() != pod.Name {
+			objs = append(objs, obj)
+		}
+	}
+	objs = append(objs, pod)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objs...).Build()
+	store := game.NewMemoryStore()
+	state.SetPodUID(1, 1, "uid-old")
+	_ = store.Save(ctx, state)
+
+	if _, err := newTestDriftCorrector(c, store).Correct(ctx); err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	saved, _ := store.Load(ctx)
+	if uid := saved.PodUID(1, 1); uid != "uid-repaired" {
+		t.Errorf("expected the UID of the live pod to be recorded, got %q", uid)
+	}
+}
+
+func TestDriftCorrector_RestoresHintPods(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	origin := game.Coordinate{X: 0, Y: 0}
+
+	var objs []client.Object
+	for _, obj := range spawnTestCells(state) {
+		if obj.GetName() != "pod-0-0" {
+			objs = append(objs, obj)
+		}
+	}
+	objs = append(objs, createTestHintPod(origin, "5"))
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objs...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	d := newTestDriftCorrector(c, store)
+
+	// The wrong hint is deleted first, then recreated with the right value
+	for i := 0; i < 2; i++ {
+		if _, err := d.Correct(ctx); err != nil {
+			t.Fatalf("Correct failed: %v", err)
+		}
+	}
+
+	hint := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "hint-0-0", Namespace: testNamespace}, hint); err != nil {
+		t.Fatalf("expected hint-0-0 to be restored: %v", err)
+	}
+	if got := hint.Annotations[AnnotationHint]; got != "1" {
+		t.Errorf("expected hint 1, got %q", got)
+	}
+
+	drift, err := d.Correct(ctx)
+	if err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	if !drift.IsEmpty() {
+		t.Errorf("expected no drift left, got %+v", drift)
+	}
+}
+
+func TestDriftCorrector_DeletesStaleCellPods(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	if _, err := newTestDriftCorrector(c, store).Correct(ctx); err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	pods := remainingCells(t, c)
+	if pods["pod-0-0"] {
+		t.Error("expected the pod of a revealed cell to be deleted")
+	}
+	if !pods["hint-0-0"] {
+		t.Error("expected the hint pod to be created")
+	}
+}
+
+func TestDriftCorrector_IgnoresEndedGames(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.SetLost()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	drift, err := newTestDriftCorrector(c, store).Correct(ctx)
+	if err != nil {
+		t.Fatalf("Correct failed: %v", err)
+	}
+	if !drift.IsEmpty() || len(remainingCells(t, c)) != 0 {
+		t.Error("expected ended games to be left alone")
+	}
+}
+
+func equalCoords(a, b []game.Coordinate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/render"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+// UIName names the Service and Ingress exposing the board to spectators.
+const UIName = rbac.UIName
+
+// UIPaths are the endpoints of the metrics server meant for spectators.
+// The Ingress only routes these: metrics, flag submission and the audit
+// webhook stay in the cluster.
+var UIPaths = []string{"/board", "/leaderboard", "/export", "/version"}
+
+// exposeRetryInterval is how long the Exposer waits after a failed apply.
+const exposeRetryInterval = time.Minute
+
+// ExposeConfig describes how the board is exposed.
+type ExposeConfig struct {
+	// Namespace is the namespace the Gamemaster pods run in.
+	Namespace string
+
+	// Port is the port of the metrics server serving the board.
+	Port int32
+
+	// IngressHost is the host of the Ingress. Empty creates no Ingress.
+	IngressHost string
+
+	// IngressClass is the IngressClass of the Ingress. Empty uses the
+	// cluster default.
+	IngressClass string
+
+	// TLSSecret holds the certificate of IngressHost. Empty serves HTTP only.
+	TLSSecret string
+}
+
+// UIService returns the Service selecting the Gamemaster pods, which must
+// be labelled app.kubernetes.io/name=podsweeper and
+// app.kubernetes.io/component=gamemaster.
+func UIService(cfg ExposeConfig) *corev1ac.ServiceApplyConfiguration {
+	return corev1ac.Service(UIName, cfg.Namespace).
+		WithLabels(map[string]string{LabelApp: "podsweeper", LabelComponent: "ui"}).
+		WithSpec(corev1ac.ServiceSpec().
+			WithSelector(map[string]string{LabelApp: "podsweeper", LabelComponent: "gamemaster"}).
+			WithPorts(corev1ac.ServicePort().
+				WithName("http").
+				WithPort(80).
+				WithTargetPort(intstr.FromInt32(cfg.Port))))
+}
+
+// UIIngress returns the Ingress routing UIPaths of IngressHost to the UI
+// Service, or nil without a host.
+func UIIngress(cfg ExposeConfig) *networkingv1ac.IngressApplyConfiguration {
+	if cfg.IngressHost == "" {
+		return nil
+	}
+
+	rule := networkingv1ac.HTTPIngressRuleValue()
+	for _, path := range UIPaths {
+		rule = rule.WithPaths(networkingv1ac.HTTPIngressPath().
+			WithPath(path).
+			WithPathType(networkingv1.PathTypePrefix).
+			WithBackend(networkingv1ac.IngressBackend().WithService(networkingv1ac.IngressServiceBackend().
+				WithName(UIName).
+				WithPort(networkingv1ac.ServiceBackendPort().WithName("http")))))
+	}
+	spec := networkingv1ac.IngressSpec().
+		WithRules(networkingv1ac.IngressRule().WithHost(cfg.IngressHost).WithHTTP(rule))
+	if cfg.IngressClass != "" {
+		spec = spec.WithIngressClassName(cfg.IngressClass)
+	}
+	if cfg.TLSSecret != "" {
+		spec = spec.WithTLS(networkingv1ac.IngressTLS().WithHosts(cfg.IngressHost).WithSecretName(cfg.TLSSecret))
+	}
+
+	return networkingv1ac.Ingress(UIName, cfg.Namespace).
+		WithLabels(map[string]string{LabelApp: "podsweeper", LabelComponent: "ui"}).
+		WithSpec(spec)
+}
+
+// Exposer creates the Service, and the Ingress when configured, through
+// which spectators reach the board without port-forwarding. It applies them
+// once on startup, retrying until it succeeds. It runs as a manager
+// Runnable, only on the leader.
+type Exposer struct {
+	// Client applies the Service and Ingress.
+	Client client.Client
+
+	// Config describes the Service and Ingress.
+	Config ExposeConfig
+}
+
+// Start implements manager.Runnable.
+func (e *Exposer) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("expose")
+	for {
+		err := e.Apply(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.Error(err, "failed to expose the board, retrying", "retryIn", exposeRetryInterval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(exposeRetryInterval):
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (e *Exposer) NeedLeaderElection() bool {
+	return true
+}
+
+// Apply creates or updates the Service and Ingress.
+func (e *Exposer) Apply(ctx context.Context) error {
+	if err := e.Client.Apply(ctx, UIService(e.Config), client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply UI service: %w", err)
+	}
+	if ingress := UIIngress(e.Config); ingress != nil {
+		if err := e.Client.Apply(ctx, ingress, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+			return fmt.Errorf("failed to apply UI ingress: %w", err)
+		}
+	}
+	log.FromContext(ctx).Info("exposed the board", "namespace", e.Config.Namespace, "host", e.Config.IngressHost)
+	return nil
+}
+
+// BoardHandler serves the board of the current game as players see it,
+// one row per line, without the position of hidden mines, in the theme of
+// the game. ?format= selects a render.Format: ansi colors it for
+// terminals, as ?color=true does, and unicode draws it with symbols.
+func BoardHandler(store game.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		if state == nil {
+			http.Error(w, "no game running", http.StatusNotFound)
+			return
+		}
+		if phase := state.CurrentPhase(); !phase.Spawned() {
+			writeSpawning(w, phase)
+			return
+		}
+
+		writeBoard(w, r, state.Stats(), state.ToBoardString(game.WithPlayerView()), state.Theme)
+	})
+}
+
+// writeSpawning answers that the board is not spawned yet, instead of
+// serving a half-rendered grid.
+func writeSpawning(w http.ResponseWriter, phase game.Phase) {
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, fmt.Sprintf("the board is spawning (%s)", phase), http.StatusServiceUnavailable)
+}
+
+// writeBoard writes the stats and player view of a board in a theme, in
+// the format requested by r.
+func writeBoard(w http.ResponseWriter, r *http.Request, stats game.GameStats, board, themeName string) {
+	format, err := render.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.URL.Query().Get("color") == "true" {
+		format = render.ANSI
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	board = render.Renderer{Format: format, Theme: theme.Of(themeName)}.RenderString(board)
+	fmt.Fprintf(w, "%s\n\n%s\n", stats, board)
+}
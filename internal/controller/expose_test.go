@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+func TestUIIngress(t *testing.T) {
+	if UIIngress(ExposeConfig{Namespace: testNamespace}) != nil {
+		t.Error("expected no ingress without a host")
+	}
+
+	ingress := UIIngress(ExposeConfig{Namespace: testNamespace, IngressHost: "board.example.com",
+		IngressClass: "nginx", TLSSecret: "board-tls"})
+	if ingress == nil {
+		t.Fatal("expected an ingress")
+	}
+	spec := ingress.Spec
+	if *spec.IngressClassName != "nginx" || len(spec.TLS) != 1 || *spec.TLS[0].SecretName != "board-tls" {
+		t.Errorf("expected class and TLS to be set, got %+v", spec)
+	}
+	var paths []string
+	for _, p := range spec.Rules[0].HTTP.Paths {
+		paths = append(paths, *p.Path)
+	}
+	if strings.Join(paths, ",") != strings.Join(UIPaths, ",") {
+		t.Errorf("expected only the spectator paths to be routed, got %v", paths)
+	}
+}
+
+func TestExposer_Apply(t *testing.T) {
+	ctx := context.Background()
+	c := newApplyClient()
+
+	e := &Exposer{Client: c, Config: ExposeConfig{Namespace: testNamespace, Port: 8080}}
+	if err := e.Apply(ctx); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Name: UIName, Namespace: testNamespace}, svc); err != nil {
+		t.Fatalf("expected the UI service to be created: %v", err)
+	}
+	if svc.Spec.Selector[LabelComponent] != "gamemaster" || svc.Spec.Ports[0].TargetPort.IntValue() != 8080 {
+		t.Errorf("expected the service to target the Gamemaster metrics port, got %+v", svc.Spec)
+	}
+	err := c.Get(ctx, types.NamespacedName{Name: UIName, Namespace: testNamespace}, &networkingv1.Ingress{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected no ingress without a host, got %v", err)
+	}
+
+	e.Config.IngressHost = "board.example.com"
+	if err := e.Apply(ctx); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: UIName, Namespace: testNamespace}, &networkingv1.Ingress{}); err != nil {
+		t.Errorf("expected the UI ingress to be created: %v", err)
+	}
+}
+
+func TestBoardHandler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	handler := BoardHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without a game, got %d", rec.Code)
+	}
+
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	_ = store.Save(ctx, state)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "1..\n...\n...") {
+		t.Errorf("unexpected board (%d): %q", rec.Code, body)
+	}
+	if strings.ContainsRune(body, game.BoardHiddenMine) {
+		t.Errorf("expected hidden mines not to be shown, got %q", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board?format=unicode", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "1■■\n■■■\n■■■") {
+		t.Errorf("expected a unicode board, got %q", body)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board?format=html", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown format to be refused, got %d", rec.Code)
+	}
+
+	state.Theme = string(theme.Emoji)
+	_ = store.Save(ctx, state)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board?color=true", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "1️⃣🟦🟦\n") {
+		t.Errorf("expected an emoji board, got %q", body)
+	}
+
+	state = createTestGameState(3)
+	state.Phase = game.PhaseSpawning
+	_ = store.Save(ctx, state)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 503 with Retry-After while spawning, got %d", rec.Code)
+	}
+}
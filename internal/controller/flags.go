@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// AnnotationFlag flags the cell of a cell pod when set to "true", and
+// unflags it when set to "false":
+//
+//	kubectl annotate pod pod-3-4 podsweeper.io/flag=true
+//
+// The deletion of the pod of a cell flagged this way is refused: the pod is
+// recreated, so a flagged mine can't be set off by a sweeping delete. Flags
+// set by power-ups don't guard their cells. Removing the annotation leaves
+// the flag as it is.
+const AnnotationFlag = "podsweeper.io/flag"
+
+// flagAnnotation returns whether the annotations of pod flag or unflag its
+// cell, and false for ok if they do neither.
+func flagAnnotation(pod *corev1.Pod) (flagged bool, ok bool) {
+	switch pod.Annotations[AnnotationFlag] {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// reconcileFlag flags or unflags the cell coords, as the annotation of its
+// pod tells.
+func (r *GameController) reconcileFlag(ctx context.Context, coords game.Coordinate, flagged bool) (ctrl.Result, error) {
+	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		log.FromContext(ctx).Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || state.Status != game.StatusPlaying || state.IsPlayerFlagged(coords.X, coords.Y) == flagged {
+		return ctrl.Result{}, nil
+	}
+	if !state.SetPlayerFlag(coords.X, coords.Y, flagged) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Store.Save(ctx, state); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+	}
+	log.FromContext(ctx).Info("cell flag set", "coords", coords, "flagged", flagged)
+	return ctrl.Result{}, nil
+}
+
+// refuseFlagged recreates the pod of the flagged cell coords instead of
+// playing its deletion, and saves the game.
+func (r *GameController) refuseFlagged(ctx context.Context, state *game.GameState, coords game.Coordinate) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("warning: refusing to play a flagged cell, unflag it first", "coords", coords)
+	if err := r.createCellPod(ctx, state, coords); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Store.Save(ctx, state); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+	}
+	if r.Recorder != nil {
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: coords.PodName()}, pod); err == nil {
+			r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, "FlaggedCell", "Delete",
+				"the cell is flagged, annotate the pod with %s=false to play it", AnnotationFlag)
+		}
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGameController_Flag(t *testing.T) {
+	ctx := context.Background()
+	// Mine at (1,1)
+	state := createTestGameState(3)
+	pod := createTestPod("pod-1-1", testNamespace)
+	pod.Annotations = map[string]string{AnnotationFlag: "true"}
+	controller, c, recorder := newTutorialController(t, state, pod)
+
+	reconcilePod(t, controller, "pod-1-1")
+	saved, _ := controller.Store.Load(ctx)
+	if !saved.IsPlayerFlagged(1, 1) {
+		t.Fatal("expected the cell flagged")
+	}
+
+	// Deleting the flagged mine is refused
+	deletePods(t, c, "pod-1-1")
+	reconcilePod(t, controller, "pod-1-1")
+	saved, _ = controller.Store.Load(ctx)
+	if saved.Status != game.StatusPlaying || saved.IsRevealed(1, 1) {
+		t.Fatalf("expected the deletion refused, got %s", saved.Status)
+	}
+	restored := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, restored); err != nil {
+		t.Fatalf("expected the pod recreated: %v", err)
+	}
+	if restored.Annotations[AnnotationFlag] != "true" {
+		t.Errorf("expected the recreated pod flagged, got %v", restored.Annotations)
+	}
+	if events := strings.Join(drainEvents(recorder), "\n"); !strings.Contains(events, "FlaggedCell") {
+		t.Errorf("expected a FlaggedCell event, got %q", events)
+	}
+
+	// Once unflagged, it is a regular move
+	restored.Annotations[AnnotationFlag] = "false"
+	if err := c.Update(ctx, restored); err != nil {
+		t.Fatalf("failed to annotate pod: %v", err)
+	}
+	reconcilePod(t, controller, "pod-1-1")
+	if saved, _ = controller.Store.Load(ctx); saved.IsFlagged(1, 1) {
+		t.Fatal("expected the cell unflagged")
+	}
+	deletePods(t, c, "pod-1-1")
+	reconcilePod(t, controller, "pod-1-1")
+	if saved, _ = controller.Store.Load(ctx); saved.Status != game.StatusLost {
+		t.Errorf("expected the mine to blow up, got %s", saved.Status)
+	}
+}
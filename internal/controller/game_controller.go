@@ -3,25 +3,35 @@ package controller
 
 import (
 	"context"
-	"fmt"
-	"regexp"
-	"strconv"
+	"math/rand/v2"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/powerup"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/spawner"
 )
 
-// PodNameRegex matches pod names in the format "pod-X-Y" where X and Y are integers.
-var PodNameRegex = regexp.MustCompile(`^pod-(\d+)-(\d+)$`)
-
-// HintPodNameRegex matches hint pod names in the format "hint-X-Y".
-var HintPodNameRegex = regexp.MustCompile(`^hint-(\d+)-(\d+)$`)
+// spawningRequeueDelay is how long moves played while the board is
+// spawning wait before being retried.
+const spawningRequeueDelay = time.Second
 
 // GameController reconciles Pod objects in the game namespace.
 type GameController struct {
@@ -29,22 +39,124 @@ type GameController struct {
 	Store     game.Store
 	Namespace string
 	Handlers  *GameHandlers
+	Protected ProtectionRules
+	// Tutor guides games in tutorial mode. Nil disables tutorial guidance.
+	Tutor *Tutor
+	// Claims attributes moves of in-process players such as the gremlin.
+	Claims *MoveClaims
+	// Levels applies the obstacles of higher levels. Nil disables them.
+	Levels *LevelManager
+	// Recorder emits the Events telling players about their power-ups. Optional.
+	Recorder events.EventRecorder
+	// Rand picks the cells of random power-ups. Defaults to a randomly seeded source.
+	Rand *rand.Rand
+	// Spawner restores the cell pods of mines flagged by power-ups or defused.
+	Spawner *spawner.GridSpawner
+	// DefusalTimeout is how long players have to defuse a bomb in defusal
+	// mode. Defaults to DefaultDefusalTimeout.
+	DefusalTimeout time.Duration
+	// Lock keeps other Gamemasters from playing the game. Nil disables it.
+	Lock *game.Lock
+	// RateLimiter delays the retries of failed reconciles. Defaults to
+	// the controller-runtime rate limiter.
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	// SelfCheck holds off new games while permissions are missing. Optional.
+	SelfCheck *SelfCheck
+	// Tombstones remember the UIDs of the deleted cell pods. Nil plays
+	// every deletion.
+	Tombstones *PodTombstones
+	// Webhook detects the deletions bypassing the attribution webhook.
+	// Nil when moves are not attributed by the webhook.
+	Webhook *WebhookHealth
 }
 
 // GameControllerConfig holds configuration for the GameController.
 type GameControllerConfig struct {
 	Namespace string
 	Store     game.Store
+	// Protected lists pods the controller must never touch.
+	Protected ProtectionRules
+	// Players resolves identities to display names. Optional.
+	Players *player.Registry
+	// Ratings records player ratings after each finished game. Optional.
+	Ratings *player.RatingStore
+	// Recorder emits the Events explaining moves in tutorial mode and power-ups. Optional.
+	Recorder events.EventRecorder
+	// Claims attributes moves of in-process players such as the gremlin. Optional.
+	Claims *MoveClaims
+	// NetworkPolicies enables the obstacles of the levels: the
+	// NetworkPolicies of higher levels and the cheat sheet of level 0.
+	NetworkPolicies bool
+	// HintAggregator serves hints from the hint aggregator Deployment
+	// instead of hint pods. Optional.
+	HintAggregator *HintAggregator
+	// DefusalTimeout is how long players have to defuse a bomb in defusal
+	// mode. Defaults to DefaultDefusalTimeout.
+	DefusalTimeout time.Duration
+	// Lock keeps other Gamemasters from playing the game. The Store
+	// should be locked with it too, see game.LockedStore. Optional.
+	Lock *game.Lock
+	// RateLimiter delays the retries of failed reconciles, see
+	// Tuning.RateLimiter. Optional.
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	// SelfCheck holds off new games while permissions are missing. Optional.
+	SelfCheck *SelfCheck
+	// Profile shapes the game pods to the cluster, see spawner.Profile.
+	// Defaults to spawner.ProfileDefault.
+	Profile spawner.Profile
+	// Archive keeps finished games browsable after the namespace moved on
+	// to other games. Optional.
+	Archive *results.Archive
+	// RevealURL is the Gamemaster URL cell agents reveal their cell with,
+	// see ExecReveal. Empty spawns cells players can only delete.
+	RevealURL string
+	// Hooks are notified of game events, see HTTPHooks. Optional.
+	Hooks Hooks
+	// Messages renders the messages of the game pods. Nil renders the
+	// built-in messages.
+	Messages *messages.Catalog
+	// Webhook detects the deletions bypassing the attribution webhook,
+	// shared with the MoveAttributor. Optional.
+	Webhook *WebhookHealth
 }
 
 // NewGameController creates a new GameController.
 func NewGameController(c client.Client, config GameControllerConfig) *GameController {
 	gc := &GameController{
-		Client:    c,
-		Store:     config.Store,
-		Namespace: config.Namespace,
+		Client:         c,
+		Store:          config.Store,
+		Namespace:      config.Namespace,
+		Protected:      config.Protected,
+		Claims:         config.Claims,
+		Recorder:       config.Recorder,
+		DefusalTimeout: config.DefusalTimeout,
+		Lock:           config.Lock,
+		RateLimiter:    config.RateLimiter,
+		SelfCheck:      config.SelfCheck,
+		Webhook:        config.Webhook,
+		Tombstones:     NewPodTombstones(),
+		Spawner: spawner.NewGridSpawner(c, spawner.GridSpawnerConfig{
+			Namespace: config.Namespace,
+			Profile:   config.Profile,
+			RevealURL: config.RevealURL,
+			Messages:  config.Messages,
+		}),
 	}
 	gc.Handlers = NewGameHandlers(c, config.Store, config.Namespace)
+	gc.Handlers.protected = config.Protected
+	gc.Handlers.players = config.Players
+	gc.Handlers.ratings = config.Ratings
+	gc.Handlers.aggregator = config.HintAggregator
+	gc.Handlers.profile = config.Profile
+	gc.Handlers.archive = config.Archive
+	gc.Handlers.hooks = config.Hooks
+	gc.Handlers.messages = config.Messages
+	if config.Recorder != nil {
+		gc.Tutor = NewTutor(c, config.Recorder, config.Namespace)
+	}
+	if config.NetworkPolicies {
+		gc.Levels = NewLevelManager(c, config.Namespace)
+	}
 	return gc
 }
 
@@ -57,6 +169,47 @@ func (r *GameController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	// Moves are left to the Gamemaster holding the game, and retried
+	// should it go away
+	if err := r.Lock.Acquire(ctx); game.IsLockHeld(err) {
+		logger.V(1).Info("game locked by another gamemaster", "name", req.Name, "reason", err.Error())
+		return ctrl.Result{RequeueAfter: game.DefaultLockDuration}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Timed games are lost once their time is up, whatever pod is
+	// reconciled, and each reconcile is retried by then
+	remaining, ended, err := r.enforceTimeLimit(ctx)
+	if ended || err != nil {
+		return ctrl.Result{}, err
+	}
+	result, err := r.reconcile(ctx, req)
+	if remaining > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > remaining) {
+		result.RequeueAfter = remaining
+	}
+	return result, err
+}
+
+// reconcile plays the event of the pod of req.
+func (r *GameController) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Deleting the resign pod concedes the game
+	if req.Name == spawner.ResignPodName {
+		return r.reconcileResign(ctx, req)
+	}
+
+	// Bomb pods hold the challenges of defusal mode
+	if coords, ok := ParseBombPodName(req.Name); ok && !r.Protected.ProtectsName(req.Name) {
+		return r.reconcileBomb(ctx, req, coords)
+	}
+
+	// Deleting the hint pod of a revealed cell chords it
+	if coords, ok := ParseHintPodName(req.Name); ok && !r.Protected.ProtectsName(req.Name) {
+		return r.reconcileChord(ctx, req, coords)
+	}
+
 	// Check if this is a game pod (pod-X-Y format)
 	coords, ok := ParsePodName(req.Name)
 	if !ok {
@@ -64,14 +217,21 @@ func (r *GameController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	// Protected pods are never interpreted as moves
+	if r.Protected.ProtectsName(req.Name) {
+		logger.V(1).Info("ignoring protected pod", "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
 	// Try to get the pod
+	uid := r.Tombstones.Take(req.Name)
 	pod := &corev1.Pod{}
 	err := r.Get(ctx, req.NamespacedName, pod)
 
 	if errors.IsNotFound(err) {
 		// Pod was deleted - this is the main game action
-		logger.Info("pod deleted", "name", req.Name, "x", coords.X, "y", coords.Y)
-		return r.handlePodDeletion(ctx, coords)
+		logger.Info("pod deleted", "name", req.Name, "x", coords.X, "y", coords.Y, "uid", uid)
+		return r.handlePodDeletion(ctx, coords, uid)
 	}
 
 	if err != nil {
@@ -86,16 +246,33 @@ func (r *GameController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	// Annotating a cell pod spends a charge on a power-up
+	if _, ok := pod.Annotations[powerup.AnnotationPowerUp]; ok {
+		return r.reconcilePowerUp(ctx, pod, coords)
+	}
+
+	// Annotating a cell pod flags its cell
+	if flagged, ok := flagAnnotation(pod); ok {
+		return r.reconcileFlag(ctx, coords, flagged)
+	}
+
 	// Pod exists and is not being deleted - nothing to do
 	return ctrl.Result{}, nil
 }
 
-// handlePodDeletion processes a pod deletion event (the "click").
-func (r *GameController) handlePodDeletion(ctx context.Context, coords game.Coordinate) (ctrl.Result, error) {
+// handlePodDeletion processes a pod deletion event (the "click"). uid is
+// the UID of the deleted pod, empty if unknown: the deletion of a pod other
+// than the one last spawned for the cell is not played.
+func (r *GameController) handlePodDeletion(ctx context.Context, coords game.Coordinate, uid types.UID) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	// Load current game state
 	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		// Retrying won't help: the state must be repaired or reset
+		logger.Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		logger.Error(err, "failed to load game state")
 		return ctrl.Result{}, err
@@ -112,24 +289,159 @@ func (r *GameController) handlePodDeletion(ctx context.Context, coords game.Coor
 		return ctrl.Result{}, nil
 	}
 
+	// Pods outside the board, such as those of a board of other
+	// dimensions, are not cells of this game
+	if !state.IsValidCoordinate(coords.X, coords.Y) {
+		width, height := state.Dimensions()
+		logger.Info("ignoring deletion of a pod outside the board", "coords", coords, "width", width, "height", height)
+		return ctrl.Result{}, nil
+	}
+
+	// Moves wait for the board to be spawned
+	phase := state.CurrentPhase()
+	if !phase.Spawned() {
+		logger.Info("board is spawning, deferring move", "coords", coords, "phase", phase)
+		return ctrl.Result{RequeueAfter: spawningRequeueDelay}, nil
+	}
+
+	// A game started without the permissions to play it would break
+	// halfway: its first move waits for them
+	if phase == game.PhaseReady && r.SelfCheck.Degraded() {
+		logger.Info("not starting the game, RBAC permissions are missing", "coords", coords)
+		return ctrl.Result{RequeueAfter: DefaultSelfCheckInterval}, nil
+	}
+
 	// Check if cell was already revealed
 	if state.IsRevealed(coords.X, coords.Y) {
 		logger.Info("cell already revealed", "coords", coords)
 		return ctrl.Result{}, nil
 	}
+	if state.IsDefused(coords.X, coords.Y) {
+		logger.Info("mine already defused", "coords", coords)
+		return ctrl.Result{}, nil
+	}
+	if recorded := state.PodUID(coords.X, coords.Y); uid != "" && recorded != "" && string(uid) != recorded {
+		logger.Info("ignoring deletion of a stale pod", "coords", coords, "uid", uid, "current", recorded)
+		return ctrl.Result{}, nil
+	}
+	// The pod is gone: a pod spawned for the cell again records its own UID
+	state.SetPodUID(coords.X, coords.Y, "")
+	if state.Unspare(coords.X, coords.Y) {
+		// Deleted along a sweep that flagged it
+		logger.Info("restoring spared mine", "coords", coords)
+		return r.restoreSpared(ctx, state, coords)
+	}
+	if state.IsPlayerFlagged(coords.X, coords.Y) {
+		return r.refuseFlagged(ctx, state, coords)
+	}
+
+	// Hint pods spawned by this move must already be guarded
+	if err := r.Levels.Apply(ctx, state); err != nil {
+		logger.Error(err, "failed to apply level obstacles")
+		return ctrl.Result{}, err
+	}
+
+	// With move attribution, a deletion the webhook never admitted
+	// bypassed it: its player can't be known
+	if !r.Webhook.Validate(coords) {
+		if r.Webhook.Frozen(state.Level) {
+			logger.Info("webhook degraded, freezing the game until it is healthy again",
+				"coords", coords, "level", state.Level)
+			return ctrl.Result{RequeueAfter: webhookFreezeRequeue}, nil
+		}
+		logger.Info("cell pod deleted without going through the attribution webhook, move unattributed",
+			"coords", coords)
+		ctx = withMover(ctx, game.UnattributedPlayer)
+	}
+	if mover, ok := r.Claims.Take(coords); ok {
+		ctx = withMover(ctx, mover)
+		logger.Info("move claimed", "coords", coords, "by", mover)
+	}
+
+	if err := state.SetPhase(game.PhasePlaying); err != nil {
+		logger.Error(err, "refusing to play the game")
+		return ctrl.Result{}, nil
+	}
+	if phase == game.PhaseReady {
+		r.Handlers.notify(ctx, HookGameStart, state, &coords, 0)
+	}
+
+	if state.PowerUps.Armed != "" {
+		if triggered, result, err := r.triggerArmed(ctx, state, coords); triggered || err != nil {
+			return result, err
+		}
+	}
+
+	// In shifting mines mode, a hidden mine moves every few moves, never
+	// from or to the cell being played
+	if state.ShiftDue() {
+		if _, _, ok := grid.ShiftMine(state, coords); ok {
+			logger.Info("mine shifted", "shifts", state.Shifts)
+		}
+	}
+
+	revealedBefore := state.Stats().RevealedCells
+	result, err := r.play(ctx, state, coords)
+	if err == nil && state.Tutorial && r.Tutor != nil {
+		r.Tutor.Explain(state, coords, state.Stats().RevealedCells-revealedBefore)
+		if err := r.Tutor.Guide(ctx, state); err != nil {
+			logger.Error(err, "failed to suggest the next tutorial step")
+		}
+	}
+	return result, err
+}
+
+// reconcileResign ends the game by resignation once the resign pod is gone.
+func (r *GameController) reconcileResign(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	err := r.Get(ctx, req.NamespacedName, &corev1.Pod{})
+	if err == nil || !errors.IsNotFound(err) {
+		// Still there, or terminating: resign once it is fully gone
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		logger.Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to load game state")
+		return ctrl.Result{}, err
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return ctrl.Result{}, nil
+	}
+
+	return r.Handlers.HandleResign(ctx, state)
+}
+
+// play applies a click on a hidden cell.
+func (r *GameController) play(ctx context.Context, state *game.GameState, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
 
 	// Determine what type of cell was clicked
 	if state.IsMine(coords.X, coords.Y) {
+		if state.Defusal {
+			// A chance to defuse it first
+			return r.startDefusal(ctx, state, coords)
+		}
 		// BOOM! Game over
 		logger.Info("mine hit!", "coords", coords)
 		return r.Handlers.HandleMineHit(ctx, state, coords)
 	}
 
-	// Safe cell - check adjacent mines
+	// Safe cell - long streaks earn power-up charges
+	if state.RecordSafeClick() {
+		logger.Info("power-up charge earned", "charges", state.PowerUps.Charges())
+	}
+
+	// Check adjacent mines
 	adjacentMines := state.AdjacentMines(coords.X, coords.Y)
 
-	if adjacentMines > 0 {
-		// Cell with adjacent mines - create hint pod
+	if !state.Propagates(coords.X, coords.Y) {
+		// Cell with adjacent mines, or any cell of a hot/cold game - create hint pod
 		logger.Info("safe cell with hints", "coords", coords, "adjacent", adjacentMines)
 		return r.Handlers.HandleHintCell(ctx, state, coords, adjacentMines)
 	}
@@ -141,64 +453,98 @@ func (r *GameController) handlePodDeletion(ctx context.Context, coords game.Coor
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GameController) SetupWithManager(mgr ctrl.Manager) error {
+	// Pods are watched with the tombstones handler to know which pod a
+	// deletion was for
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Pod{}).
+		Named("pod").
+		Watches(&corev1.Pod{}, r.Tombstones.Handler()).
 		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
-			// Only watch pods in our namespace
-			return object.GetNamespace() == r.Namespace
+			// Only watch pods in our namespace, and never react to protected pods
+			return object.GetNamespace() == r.Namespace && !r.Protected.Protects(object)
 		})).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter}).
 		Complete(r)
 }
 
-// ParsePodName extracts coordinates from a pod name like "pod-3-5".
-// Returns the coordinate and true if successful, or zero coordinate and false if not a game pod.
-func ParsePodName(name string) (game.Coordinate, bool) {
-	matches := PodNameRegex.FindStringSubmatch(name)
-	if matches == nil {
-		return game.Coordinate{}, false
+// NamespacedCacheOptions returns manager cache options restricting informers
+// to the given game namespaces. Scoping the cache avoids watching pods
+// cluster-wide and lets the Gamemaster run with a namespaced Role.
+func NamespacedCacheOptions(namespaces ...string) cache.Options {
+	defaults := make(map[string]cache.Config, len(namespaces))
+	for _, ns := range namespaces {
+		if ns == "" {
+			continue
+		}
+		defaults[ns] = cache.Config{}
 	}
+	return cache.Options{DefaultNamespaces: defaults}
+}
 
-	x, err1 := strconv.Atoi(matches[1])
-	y, err2 := strconv.Atoi(matches[2])
-	if err1 != nil || err2 != nil {
-		return game.Coordinate{}, false
+// StateSecretCacheOptions restricts the cached Secrets to the game state
+// Secret, so the Gamemaster can run with a Role naming that Secret instead
+// of one reading every Secret of the namespace.
+func StateSecretCacheOptions(opts cache.Options, secretName string) cache.Options {
+	if opts.ByObject == nil {
+		opts.ByObject = make(map[client.Object]cache.ByObject)
+	}
+	opts.ByObject[&corev1.Secret{}] = cache.ByObject{
+		Field: fields.OneTermEqualSelector("metadata.name", secretName),
 	}
+	return opts
+}
 
-	return game.Coordinate{X: x, Y: y}, true
+// ParsePodName extracts coordinates from a cell pod name like "pod-3-5",
+// in the naming scheme of the process.
+// Returns the coordinate and true if successful, or zero coordinate and false if not a game pod.
+func ParsePodName(name string) (game.Coordinate, bool) {
+	return game.ParseCellPodName(name)
 }
 
 // ParseHintPodName extracts coordinates from a hint pod name like "hint-3-5".
 func ParseHintPodName(name string) (game.Coordinate, bool) {
-	matches := HintPodNameRegex.FindStringSubmatch(name)
-	if matches == nil {
-		return game.Coordinate{}, false
-	}
+	return game.ParseHintPodName(name)
+}
 
-	x, err1 := strconv.Atoi(matches[1])
-	y, err2 := strconv.Atoi(matches[2])
-	if err1 != nil || err2 != nil {
-		return game.Coordinate{}, false
-	}
+// ParseDefusedPodName extracts coordinates from a defused marker pod name like "defused-3-5".
+func ParseDefusedPodName(name string) (game.Coordinate, bool) {
+	return game.ParseDefusedPodName(name)
+}
 
-	return game.Coordinate{X: x, Y: y}, true
+// ParseBombPodName extracts coordinates from a defusal challenge pod name like "bomb-3-5".
+func ParseBombPodName(name string) (game.Coordinate, bool) {
+	return game.ParseBombPodName(name)
 }
 
 // IsPodName checks if a name matches the game pod pattern.
 func IsPodName(name string) bool {
-	return PodNameRegex.MatchString(name)
+	_, ok := ParsePodName(name)
+	return ok
 }
 
 // IsHintPodName checks if a name matches the hint pod pattern.
 func IsHintPodName(name string) bool {
-	return HintPodNameRegex.MatchString(name)
+	_, ok := ParseHintPodName(name)
+	return ok
+}
+
+// IsDefusedPodName checks if a name matches the defused marker pod pattern.
+func IsDefusedPodName(name string) bool {
+	_, ok := ParseDefusedPodName(name)
+	return ok
+}
+
+// IsBombPodName checks if a name matches the defusal challenge pod pattern.
+func IsBombPodName(name string) bool {
+	_, ok := ParseBombPodName(name)
+	return ok
 }
 
 // GeneratePodName creates a pod name from coordinates.
 func GeneratePodName(x, y int) string {
-	return fmt.Sprintf("pod-%d-%d", x, y)
+	return game.Coordinate{X: x, Y: y}.PodName()
 }
 
 // GenerateHintPodName creates a hint pod name from coordinates.
 func GenerateHintPodName(x, y int) string {
-	return fmt.Sprintf("hint-%d-%d", x, y)
+	return game.Coordinate{X: x, Y: y}.HintPodName()
 }
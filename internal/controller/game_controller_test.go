@@ -2,9 +2,12 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -12,6 +15,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/scoring"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 const testNamespace = "podsweeper-game"
@@ -80,6 +89,33 @@ func TestParseHintPodName(t *testing.T) {
 	}
 }
 
+func TestParseDefusedPodName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantOK    bool
+		wantCoord game.Coordinate
+	}{
+		{"valid defused-0-0", "defused-0-0", true, game.Coordinate{X: 0, Y: 0}},
+		{"valid defused-3-5", "defused-3-5", true, game.Coordinate{X: 3, Y: 5}},
+		{"game pod", "pod-3-5", false, game.Coordinate{}},
+		{"partial match", "defused-3", false, game.Coordinate{}},
+		{"empty string", "", false, game.Coordinate{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coord, ok := ParseDefusedPodName(tt.input)
+			if ok != tt.wantOK {
+				t.Errorf("ParseDefusedPodName(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if ok && coord != tt.wantCoord {
+				t.Errorf("ParseDefusedPodName(%q) coord = %v, want %v", tt.input, coord, tt.wantCoord)
+			}
+		})
+	}
+}
+
 func TestIsPodName(t *testing.T) {
 	tests := []struct {
 		input string
@@ -122,6 +158,26 @@ func TestIsHintPodName(t *testing.T) {
 	}
 }
 
+func TestIsDefusedPodName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"defused-0-0", true},
+		{"defused-3-5", true},
+		{"pod-3-5", false},
+		{"defused", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IsDefusedPodName(tt.input); got != tt.want {
+				t.Errorf("IsDefusedPodName(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGeneratePodName(t *testing.T) {
 	tests := []struct {
 		x, y int
@@ -165,6 +221,7 @@ func TestGenerateHintPodName(t *testing.T) {
 func newTestScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
+	_ = networkingv1.AddToScheme(scheme)
 	return scheme
 }
 
@@ -391,6 +448,83 @@ func TestGameController_ReconcileIgnoresAlreadyRevealed(t *testing.T) {
 	}
 }
 
+func TestGameController_ReconcileShiftsMines(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.ShiftEvery = 1
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{Namespace: testNamespace, Store: store})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.Shifts != 1 || loaded.IsMine(1, 1) || loaded.MineCount != 1 {
+		t.Errorf("expected the mine of (1,1) to shift, got %d shifts:\n%s", loaded.Shifts, loaded.ToBoardString())
+	}
+	if !loaded.IsRevealed(0, 0) || loaded.Status == game.StatusLost {
+		t.Errorf("expected the played cell never to receive the mine, got %s", loaded.Status)
+	}
+}
+
+func TestGameController_ReconcileLives(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.Lives = 2
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{Namespace: testNamespace, Store: store})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1-1", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.Status != game.StatusPlaying || !loaded.IsDefused(1, 1) || loaded.Lives != 1 {
+		t.Errorf("expected a life lost and the game going on, got %s with %d lives", loaded.Status, loaded.Lives)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "defused-1-1", Namespace: testNamespace}, &corev1.Pod{}); err != nil {
+		t.Errorf("expected a defused marker pod: %v", err)
+	}
+}
+
+func TestGameController_ReconcileHotCold(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	state := createTestGameState(5)
+	state.HintType = game.HintHotCold
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{Namespace: testNamespace, Store: store})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-4-4", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	// (4,4) has no mine around, but reveals nothing else
+	loaded, _ := store.Load(ctx)
+	if got := loaded.Stats().RevealedCells; got != 1 {
+		t.Errorf("expected hot/cold cells not to propagate, %d cells revealed", got)
+	}
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "hint-4-4", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("expected a hint pod: %v", err)
+	}
+	if got := pod.Annotations[AnnotationHint]; got != game.Cold {
+		t.Errorf("expected the hint pod to show cold, got %q", got)
+	}
+	if drift := DetectDrift(loaded, []corev1.Pod{pod}); len(drift.MissingHints) != 0 || len(drift.Extra) != 0 {
+		t.Errorf("expected the hint pod to match the game, got %+v", drift)
+	}
+}
+
 func TestGameController_ReconcileIgnoresGameOver(t *testing.T) {
 	ctx := context.Background()
 	scheme := newTestScheme()
@@ -425,6 +559,197 @@ func TestGameController_ReconcileIgnoresGameOver(t *testing.T) {
 	}
 }
 
+func TestGameController_ReconcileRefusesCorruptState(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(8)
+	state.MineCount = 42 // Does not match the grid
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+	})
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "pod-3-5",
+			Namespace: testNamespace,
+		},
+	}
+
+	result, err := controller.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if result.Requeue {
+		t.Error("expected no requeue for a corrupted state")
+	}
+
+	// The explosion pod must not be spawned for a corrupted game
+	var podList corev1.PodList
+	if err := fakeClient.List(ctx, &podList); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(podList.Items) != 0 {
+		t.Errorf("expected no pods to be created, got %d", len(podList.Items))
+	}
+}
+
+func TestGameController_ReconcileWaitsForGameLock(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+	_ = coordinationv1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	// Another Gamemaster drives the game
+	other := &game.Lock{Client: fakeClient, Namespace: testNamespace, Identity: "other"}
+	if err := other.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, createTestGameState(8))
+	lock := &game.Lock{Client: fakeClient, Namespace: testNamespace, Identity: "self"}
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     game.NewLockedStore(store, lock),
+		Lock:      lock,
+	})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-3-5", Namespace: testNamespace}}
+	result, err := controller.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("expected the move to be retried while the game is locked")
+	}
+	if state, _ := store.Load(ctx); state.IsRevealed(3, 5) {
+		t.Error("expected the move to be left to the lock holder")
+	}
+
+	// Played once the lock is released
+	if err := other.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if state, _ := store.Load(ctx); !state.IsRevealed(3, 5) {
+		t.Error("expected the move to be played by the new lock holder")
+	}
+}
+
+func TestGameController_ReconcileWaitsForSpawning(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(8)
+	state.Phase = game.PhaseSpawning
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+	})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-3-5", Namespace: testNamespace}}
+	result, err := controller.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if result.RequeueAfter != spawningRequeueDelay {
+		t.Errorf("expected the move to be retried after %v, got %v", spawningRequeueDelay, result.RequeueAfter)
+	}
+	if state, _ := store.Load(ctx); state.IsRevealed(3, 5) {
+		t.Error("expected the move to wait for the board")
+	}
+
+	// Played once the board is ready, starting the game
+	state.Phase = game.PhaseReady
+	_ = store.Save(ctx, state)
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	state, _ = store.Load(ctx)
+	if !state.IsRevealed(3, 5) {
+		t.Error("expected the move to be played")
+	}
+	if state.Phase != game.PhasePlaying {
+		t.Errorf("expected the first move to start the game, got phase %s", state.Phase)
+	}
+}
+
+func TestGameController_ReconcileResign(t *testing.T) {
+	ctx := context.Background()
+	resignPod := createTestPod(spawner.ResignPodName, testNamespace)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(resignPod, createTestPod("pod-0-0", testNamespace)).
+		Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.Theme = string(theme.HighContrast)
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+	})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: spawner.ResignPodName, Namespace: testNamespace}}
+
+	// Nothing happens while the resign pod is there
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if loaded, _ := store.Load(ctx); loaded.Status != game.StatusPlaying {
+		t.Fatalf("expected the game to go on, got %s", loaded.Status)
+	}
+
+	if err := fakeClient.Delete(ctx, resignPod); err != nil {
+		t.Fatalf("failed to delete the resign pod: %v", err)
+	}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.Status != game.StatusLost || !loaded.Resigned {
+		t.Errorf("expected the game lost by resignation, got %s (resigned=%v)", loaded.Status, loaded.Resigned)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}, &corev1.Pod{}); err == nil {
+		t.Error("expected the cell pods to be wiped")
+	}
+
+	var summary corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: SummaryPodName, Namespace: testNamespace}, &summary); err != nil {
+		t.Fatalf("expected a summary pod: %v", err)
+	}
+	command := strings.Join(summary.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "*** RESIGNED ***") || !strings.Contains(command, "111\n1*1\n111") {
+		t.Errorf("expected the full board in the summary, got %q", command)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "explosion", Namespace: testNamespace}, &corev1.Pod{}); err == nil {
+		t.Error("expected no explosion pod for a resignation")
+	}
+}
+
 // --- Handler tests ---
 
 func TestGameHandlers_HandleMineHit(t *testing.T) {
@@ -526,6 +851,34 @@ func TestGameHandlers_HandleHintCell(t *testing.T) {
 	}
 }
 
+func TestGameHandlers_HandleHintCellLoseRule(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(8)
+	state.SetMine(1, 1)
+	state.Rules = &game.Rules{Lose: "clicks >= 1", Score: "score + 5"}
+	_ = store.Save(ctx, state)
+
+	handlers := NewGameHandlers(fakeClient, store, testNamespace)
+	if _, err := handlers.HandleHintCell(ctx, state, game.Coordinate{X: 0, Y: 0}, 1); err != nil {
+		t.Fatalf("HandleHintCell returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.Status != game.StatusLost || loaded.Resigned {
+		t.Errorf("expected the lose rule to end the game, got %s", loaded.Status)
+	}
+	if loaded.ScoreBonus != 5 {
+		t.Errorf("expected the score rule to add 5 points, got %d", loaded.ScoreBonus)
+	}
+	var summary corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: SummaryPodName, Namespace: testNamespace}, &summary); err != nil {
+		t.Errorf("expected a summary pod: %v", err)
+	}
+}
+
 func TestGameHandlers_HandleEmptyCell_BFSPropagation(t *testing.T) {
 	ctx := context.Background()
 	scheme := newTestScheme()
@@ -566,6 +919,14 @@ func TestGameHandlers_HandleEmptyCell_BFSPropagation(t *testing.T) {
 	if !loadedState.IsRevealed(0, 1) {
 		t.Error("expected (0,1) to be revealed")
 	}
+
+	// Only the clicked cell counts as a direct reveal
+	if cell, _ := loadedState.Cell(0, 0); cell.Propagated || cell.RevealedAt.IsZero() {
+		t.Errorf("expected (0,0) to be a timestamped direct reveal, got %+v", cell)
+	}
+	if cell, _ := loadedState.Cell(0, 1); !cell.Propagated {
+		t.Error("expected (0,1) to be revealed by propagation")
+	}
 }
 
 func TestGameHandlers_BFSPropagation(t *testing.T) {
@@ -651,6 +1012,70 @@ func TestGameHandlers_HandleVictory(t *testing.T) {
 	}
 }
 
+func TestGameHandlers_HandleVictoryRecordsRatings(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	ratings := player.NewRatingStore(fakeClient, testNamespace, player.DefaultRatingsConfigMap, nil)
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     game.NewMemoryStore(),
+		Ratings:   ratings,
+	})
+
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.SetMine(0, 1)
+	state.SetMine(1, 0)
+	state.RevealBy(1, 1, "alice@example.com")
+
+	if _, err := controller.Handlers.handleVictory(ctx, state); err != nil {
+		t.Fatalf("handleVictory returned error: %v", err)
+	}
+
+	loaded, err := ratings.Load(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load ratings: %v", err)
+	}
+	alice, ok := loaded.Players["alice@example.com"]
+	if !ok || alice.Games != 1 || alice.Wins != 1 {
+		t.Errorf("expected a rated win for alice, got %+v", loaded.Players)
+	}
+}
+
+func TestGameHandlers_HandleVictoryArchivesGame(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	archive := &results.Archive{Client: fakeClient, Namespace: testNamespace}
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     game.NewMemoryStore(),
+		Archive:   archive,
+	})
+
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.SetMine(0, 1)
+	state.SetMine(1, 0)
+	state.RevealBy(1, 1, "alice@example.com")
+
+	if _, err := controller.Handlers.handleVictory(ctx, state); err != nil {
+		t.Fatalf("handleVictory returned error: %v", err)
+	}
+
+	archived, err := archive.Game(ctx, state.ID())
+	if err != nil || archived == nil {
+		t.Fatalf("expected the game to be archived: %v", err)
+	}
+	if archived.Result.Status != game.StatusWon || len(archived.Moves) != 1 || archived.Moves[0].Player != "alice@example.com" {
+		t.Errorf("expected alice's win to be archived, got %+v", archived)
+	}
+}
+
 func TestGameHandlers_WipeGamePods(t *testing.T) {
 	ctx := context.Background()
 	scheme := newTestScheme()
@@ -750,7 +1175,7 @@ func TestGameHandlers_SpawnHintPod(t *testing.T) {
 	coords := game.Coordinate{X: 5, Y: 7}
 	hintValue := 3
 
-	err := handlers.spawnHintPod(ctx, coords, hintValue)
+	err := handlers.spawnHintPod(ctx, createTestGameState(8), coords, hintValue)
 	if err != nil {
 		t.Fatalf("spawnHintPod returned error: %v", err)
 	}
@@ -783,6 +1208,9 @@ func TestGameHandlers_SpawnHintPod(t *testing.T) {
 	if pod.Annotations[AnnotationPort] != "8080" {
 		t.Errorf("expected port annotation '8080', got %q", pod.Annotations[AnnotationPort])
 	}
+	if pod.Annotations[AnnotationVersion] != version.Version {
+		t.Errorf("expected version annotation %q, got %q", version.Version, pod.Annotations[AnnotationVersion])
+	}
 
 	// Check container
 	if len(pod.Spec.Containers) != 1 {
@@ -795,6 +1223,22 @@ func TestGameHandlers_SpawnHintPod(t *testing.T) {
 	if container.Image != HintAgentImage {
 		t.Errorf("expected image %q, got %q", HintAgentImage, container.Image)
 	}
+
+	// The agent is configured from the pod object with the Downward API
+	fields := map[string]string{}
+	for _, e := range container.Env {
+		if e.ValueFrom != nil && e.ValueFrom.FieldRef != nil {
+			fields[e.Name] = e.ValueFrom.FieldRef.FieldPath
+		}
+	}
+	if fields["HINT_VALUE"] != "metadata.annotations['"+AnnotationHint+"']" ||
+		fields[spawner.EnvCoordX] != "metadata.labels['"+LabelCoordX+"']" ||
+		fields[spawner.EnvGameID] != "metadata.labels['"+spawner.LabelGameID+"']" {
+		t.Errorf("expected the hint, coordinates and game from the pod, got %v", fields)
+	}
+	if pod.Labels[spawner.LabelGameID] == "" {
+		t.Error("expected the game ID label")
+	}
 }
 
 func TestGameHandlers_SpawnExplosionPod(t *testing.T) {
@@ -810,7 +1254,7 @@ func TestGameHandlers_SpawnExplosionPod(t *testing.T) {
 
 	coords := game.Coordinate{X: 3, Y: 5}
 
-	err := handlers.spawnExplosionPod(ctx, coords)
+	err := handlers.spawnExplosionPod(ctx, createTestGameState(10), coords)
 	if err != nil {
 		t.Fatalf("spawnExplosionPod returned error: %v", err)
 	}
@@ -829,6 +1273,48 @@ func TestGameHandlers_SpawnExplosionPod(t *testing.T) {
 	if pod.Labels[LabelComponent] != "explosion" {
 		t.Errorf("expected component label 'explosion', got %q", pod.Labels[LabelComponent])
 	}
+	if command := strings.Join(pod.Spec.Containers[0].Command, " "); !strings.Contains(command, "💥 BOOM! 💥") {
+		t.Errorf("expected the classic banner, got %q", command)
+	}
+}
+
+func TestGameHandlers_SpawnExplosionPodTinyProfile(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	handlers := NewGameHandlers(fakeClient, game.NewMemoryStore(), testNamespace)
+	handlers.profile = spawner.ProfileTiny
+
+	if err := handlers.spawnExplosionPod(ctx, createTestGameState(10), game.Coordinate{X: 3, Y: 5}); err != nil {
+		t.Fatalf("spawnExplosionPod returned error: %v", err)
+	}
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "explosion", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get explosion pod: %v", err)
+	}
+	if command := strings.Join(pod.Spec.Containers[0].Command, " "); strings.Contains(command, "sleep infinity") {
+		t.Errorf("expected the explosion pod to exit after its message, got %q", command)
+	}
+}
+
+func TestGameHandlers_SpawnExplosionPodHighContrast(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	handlers := NewGameHandlers(fakeClient, game.NewMemoryStore(), testNamespace)
+
+	state := createTestGameState(3)
+	state.Theme = string(theme.HighContrast)
+	if err := handlers.spawnExplosionPod(ctx, state, game.Coordinate{X: 1, Y: 1}); err != nil {
+		t.Fatalf("spawnExplosionPod returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "explosion", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get explosion pod: %v", err)
+	}
+	command := strings.Join(pod.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "*** BOOM! ***") || strings.Contains(command, "💥") {
+		t.Errorf("expected a plain text banner, got %q", command)
+	}
 }
 
 func TestGameHandlers_SpawnVictoryPod(t *testing.T) {
@@ -845,6 +1331,7 @@ func TestGameHandlers_SpawnVictoryPod(t *testing.T) {
 	state := createTestGameState(8)
 	state.Level = 5
 	state.Clicks = 42
+	state.ScoreCard = &game.ScoreCard{ThreeBV: 12, Clicks: 15, Efficiency: 80, ThreeBVPerSecond: 0.5, TimeScore: 500}
 
 	err := handlers.spawnVictoryPod(ctx, state)
 	if err != nil {
@@ -865,6 +1352,155 @@ func TestGameHandlers_SpawnVictoryPod(t *testing.T) {
 	if pod.Labels[LabelComponent] != "victory" {
 		t.Errorf("expected component label 'victory', got %q", pod.Labels[LabelComponent])
 	}
+
+	// Check the message embeds the game stats
+	command := strings.Join(pod.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "Level: 5") || !strings.Contains(command, "Score:") {
+		t.Errorf("expected victory message to include stats, got %q", command)
+	}
+	if !strings.Contains(command, "Analysis: 0 moves") {
+		t.Errorf("expected victory message to include the analysis, got %q", command)
+	}
+	if !strings.Contains(command, "3BV: 12 in 15 clicks, efficiency 80%") {
+		t.Errorf("expected victory message to include the score card, got %q", command)
+	}
+	if pod.Annotations[scoring.AnnotationThreeBV] != "12" || pod.Annotations[scoring.AnnotationEfficiency] != "80%" {
+		t.Errorf("expected the score card annotations, got %v", pod.Annotations)
+	}
+}
+
+func TestGameHandlers_SpawnedPodsAreRestricted(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	handlers := NewGameHandlers(fakeClient, game.NewMemoryStore(), testNamespace)
+	state := createTestGameState(3)
+	coords := game.Coordinate{X: 0, Y: 0}
+
+	if err := handlers.spawnHintPod(ctx, state, coords, 1); err != nil {
+		t.Fatalf("spawnHintPod returned error: %v", err)
+	}
+	if err := handlers.spawnDefusedPod(ctx, game.Coordinate{X: 1, Y: 1}); err != nil {
+		t.Fatalf("spawnDefusedPod returned error: %v", err)
+	}
+	if err := handlers.spawnExplosionPod(ctx, state, coords); err != nil {
+		t.Fatalf("spawnExplosionPod returned error: %v", err)
+	}
+	if err := handlers.spawnVictoryPod(ctx, state); err != nil {
+		t.Fatalf("spawnVictoryPod returned error: %v", err)
+	}
+
+	var pods corev1.PodList
+	if err := fakeClient.List(ctx, &pods); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) != 4 {
+		t.Fatalf("expected 4 pods, got %d", len(pods.Items))
+	}
+	for _, pod := range pods.Items {
+		if violations := spawner.RestrictedViolations(&pod.Spec); len(violations) > 0 {
+			t.Errorf("%s must meet the restricted profile: %v", pod.Name, violations)
+		}
+	}
+}
+
+func TestGameHandlers_SpawnVictoryPodListsPlayers(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	players := player.NewRegistry()
+	players.Set([]player.Player{{ID: "alice", Usernames: []string{"alice@example.com"}, DisplayName: "Alice O'Neil"}})
+
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     game.NewMemoryStore(),
+		Players:   players,
+	})
+
+	state := createTestGameState(4)
+	state.RevealBy(0, 0, "alice@example.com")
+	state.RevealBy(3, 3, "alice@example.com")
+	state.RevealBy(3, 0, "system:serviceaccount:team-b:bot")
+
+	if err := controller.Handlers.spawnVictoryPod(ctx, state); err != nil {
+		t.Fatalf("spawnVictoryPod returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "victory", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get victory pod: %v", err)
+	}
+	command := strings.Join(pod.Spec.Containers[0].Command, " ")
+	// The apostrophe is escaped, not stripped, from the single-quoted message
+	if !strings.Contains(command, `Players: Alice O'\''Neil (2), bot (team-b) (1)`) {
+		t.Errorf("expected players line in victory message, got %q", command)
+	}
+}
+
+func TestGameHandlers_SpawnVictoryPodDeclaresWinningTeam(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	handlers := NewGameHandlers(fakeClient, game.NewMemoryStore(), testNamespace)
+
+	state := createTestGameState(4)
+	_ = state.AddTeam("red", 3, "alice")
+	_ = state.AddTeam("blue", 3, "bob")
+	state.RevealBy(3, 3, "bob")
+
+	if err := handlers.spawnVictoryPod(ctx, state); err != nil {
+		t.Fatalf("spawnVictoryPod returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "victory", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("Failed to get victory pod: %v", err)
+	}
+	if command := strings.Join(pod.Spec.Containers[0].Command, " "); !strings.Contains(command, "Winning team: blue") {
+		t.Errorf("expected winning team in victory message, got %q", command)
+	}
+}
+
+func TestGameHandlers_HandleDefusedMine(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(4)
+	_ = state.AddTeam("red", 2, "alice")
+	state.HitMine(1, 1, "alice")
+	handlers := NewGameHandlers(fakeClient, store, testNamespace)
+
+	if _, err := handlers.handleDefusedMine(ctx, state, game.Coordinate{X: 1, Y: 1}, "alice"); err != nil {
+		t.Fatalf("handleDefusedMine returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "defused-1-1", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("defused pod was not created: %v", err)
+	}
+	if pod.Labels[LabelComponent] != "defused" {
+		t.Errorf("expected component label 'defused', got %q", pod.Labels[LabelComponent])
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if loaded.Status != game.StatusPlaying || !loaded.IsDefused(1, 1) {
+		t.Errorf("expected the game to continue with (1,1) defused, got status %s", loaded.Status)
+	}
 }
 
 func TestGameHandlers_DeletePod(t *testing.T) {
@@ -915,3 +1551,40 @@ func TestGameHandlers_DeletePodNotFound(t *testing.T) {
 		t.Fatalf("deletePod should not error for non-existent pod: %v", err)
 	}
 }
+
+func TestNamespacedCacheOptions(t *testing.T) {
+	opts := NamespacedCacheOptions(testNamespace, "", "other-game")
+
+	if len(opts.DefaultNamespaces) != 2 {
+		t.Fatalf("expected 2 cached namespaces, got %d", len(opts.DefaultNamespaces))
+	}
+	if _, ok := opts.DefaultNamespaces[testNamespace]; !ok {
+		t.Errorf("expected %q to be cached", testNamespace)
+	}
+	if _, ok := opts.DefaultNamespaces["other-game"]; !ok {
+		t.Error("expected other-game to be cached")
+	}
+	if _, ok := opts.DefaultNamespaces[""]; ok {
+		t.Error("empty namespace must not be added (it would mean cluster-wide)")
+	}
+}
+
+func TestStateSecretCacheOptions(t *testing.T) {
+	opts := StateSecretCacheOptions(NamespacedCacheOptions(testNamespace), game.DefaultSecretName)
+
+	if len(opts.DefaultNamespaces) != 1 {
+		t.Errorf("expected namespaces to be kept, got %v", opts.DefaultNamespaces)
+	}
+	for obj, byObject := range opts.ByObject {
+		if _, ok := obj.(*corev1.Secret); !ok {
+			t.Errorf("unexpected cache restriction for %T", obj)
+			continue
+		}
+		if got := byObject.Field.String(); got != "metadata.name="+game.DefaultSecretName {
+			t.Errorf("expected Secrets restricted to the state Secret, got %q", got)
+		}
+	}
+	if len(opts.ByObject) != 1 {
+		t.Errorf("expected one cache restriction, got %d", len(opts.ByObject))
+	}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+const (
+	// GremlinName is the identity reveals made by the gremlin are attributed to.
+	GremlinName = "gremlin"
+
+	// DefaultGremlinInterval is the pace of the gremlin.
+	DefaultGremlinInterval = 30 * time.Second
+)
+
+// Gremlin is a chaos opponent deleting a random unrevealed pod at a steady
+// pace. It doesn't know where mines are: players race to finish before it
+// blunders into one. Flagged cells are left alone. Its reveals are claimed
+// so that they are attributed to GremlinName. It runs as a manager Runnable,
+// only on the leader.
+type Gremlin struct {
+	// Client deletes the pods of the cells played.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Claims attributes the gremlin's moves to it.
+	Claims *MoveClaims
+
+	// Interval between moves. Defaults to DefaultGremlinInterval.
+	Interval time.Duration
+
+	// Rand picks cells. Defaults to a randomly seeded source.
+	Rand *rand.Rand
+}
+
+// Start implements manager.Runnable.
+func (g *Gremlin) Start(ctx context.Context) error {
+	interval := g.Interval
+	if interval <= 0 {
+		interval = DefaultGremlinInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("gremlin")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := g.move(ctx); err != nil {
+			logger.Error(err, "failed to play")
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (g *Gremlin) NeedLeaderElection() bool {
+	return true
+}
+
+// move deletes the pod of a random unrevealed cell of the running game.
+func (g *Gremlin) move(ctx context.Context) error {
+	state, err := g.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || !state.CurrentPhase().Playable() || state.IsPaused() {
+		return nil
+	}
+
+	var candidates []game.Coordinate
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if !state.IsRevealed(x, y) && !state.IsFlagged(x, y) && !state.IsDefused(x, y) {
+				candidates = append(candidates, game.Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	pick := rand.IntN
+	if g.Rand != nil {
+		pick = g.Rand.IntN
+	}
+	coords := candidates[pick(len(candidates))]
+
+	g.Claims.Claim(coords, GremlinName)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: coords.PodName(), Namespace: g.Namespace}}
+	if err := g.Client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil {
+		g.Claims.Release(coords)
+		if errors.IsNotFound(err) {
+			// The player was faster
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s: %w", pod.Name, err)
+	}
+	log.FromContext(ctx).WithName("gremlin").Info("gremlin struck", "coords", coords)
+	return nil
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGremlin_RevealsAreAttributed(t *testing.T) {
+	ctx := context.Background()
+
+	// Only (0,0) is left for the gremlin: everything else is revealed or flagged
+	state := createTestGameState(3)
+	state.SetFlag(1, 1, true)
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if (x != 0 || y != 0) && !state.IsMine(x, y) {
+				state.Reveal(x, y)
+			}
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	claims := NewMoveClaims()
+
+	gremlin := &Gremlin{Client: c, Store: store, Namespace: testNamespace, Claims: claims, Rand: rand.New(rand.NewPCG(1, 2))}
+	if err := gremlin.move(ctx); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if remainingCells(t, c)["pod-0-0"] {
+		t.Fatal("expected the gremlin to delete pod-0-0")
+	}
+
+	controller := NewGameController(c, GameControllerConfig{Namespace: testNamespace, Store: store, Claims: claims})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cell, _ := loaded.Cell(0, 0)
+	if !cell.Revealed || cell.RevealedBy != GremlinName {
+		t.Errorf("expected the reveal to be attributed to the gremlin, got %+v", cell)
+	}
+}
+
+func TestGremlin_PicksRandomUnrevealedCells(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(4)
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	gremlin := &Gremlin{Client: c, Store: store, Namespace: testNamespace, Rand: rand.New(rand.NewPCG(3, 4))}
+	for i := 0; i < 3; i++ {
+		if err := gremlin.move(ctx); err != nil {
+			t.Fatalf("move failed: %v", err)
+		}
+	}
+	// Moves are not processed by a controller here, so a cell may be picked twice
+	if left := len(remainingCells(t, c)); left < 13 || left > 15 {
+		t.Errorf("expected 1 to 3 pods deleted, %d left", left)
+	}
+}
+
+func TestGremlin_IgnoresEndedGames(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(4)
+	state.SetLost()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(spawnTestCells(state)...).Build()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	gremlin := &Gremlin{Client: c, Store: store, Namespace: testNamespace}
+	if err := gremlin.move(ctx); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if len(remainingCells(t, c)) != 16 {
+		t.Error("expected no pod deleted")
+	}
+}
@@ -3,7 +3,12 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,7 +16,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/zwindler/podsweeper/pkg/analysis"
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/render"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/rules"
+	"github.com/zwindler/podsweeper/pkg/scoring"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 const (
@@ -42,6 +57,20 @@ const (
 
 	// AnnotationPort is the annotation storing the hint port (for Level 7).
 	AnnotationPort = "podsweeper.io/port"
+
+	// AnnotationFlagFragment is the annotation revealing a CTF flag fragment
+	// on a hint pod, formatted as "index/total:text".
+	AnnotationFlagFragment = "podsweeper.io/flag-fragment"
+
+	// AnnotationVersion is the annotation storing the Gamemaster version that spawned the pod.
+	AnnotationVersion = "podsweeper.io/version"
+
+	// SummaryPodName names the pod showing the full board after a resignation.
+	SummaryPodName = "summary"
+
+	// TimeoutPodName names the pod showing the full board after a timed
+	// game ran out of time.
+	TimeoutPodName = "timeout"
 )
 
 // GameHandlers contains the logic for handling game events.
@@ -49,6 +78,20 @@ type GameHandlers struct {
 	client    client.Client
 	store     game.Store
 	namespace string
+	protected ProtectionRules
+	players   *player.Registry
+	ratings   *player.RatingStore
+	// aggregator serves hints instead of hint pods. Nil spawns hint pods.
+	aggregator *HintAggregator
+	// profile shapes the end-of-game pods to the cluster.
+	profile spawner.Profile
+	// archive keeps finished games browsable. Nil discards them.
+	archive *results.Archive
+	// hooks are notified of game events. Optional.
+	hooks Hooks
+	// messages renders the messages of the pods. Nil renders the built-in
+	// messages.
+	messages *messages.Catalog
 }
 
 // NewGameHandlers creates a new GameHandlers instance.
@@ -60,13 +103,18 @@ func NewGameHandlers(c client.Client, store game.Store, namespace string) *GameH
 	}
 }
 
-// HandleMineHit processes a mine being clicked - game over!
+// HandleMineHit processes a mine being clicked. In team and multi-life
+// modes, players with lives left only lose a life and the mine is replaced
+// by a defused marker; otherwise it's game over!
 func (h *GameHandlers) HandleMineHit(ctx context.Context, state *game.GameState, coords game.Coordinate) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// Mark game as lost
-	state.Reveal(coords.X, coords.Y)
-	state.SetLost()
+	mover := moverFrom(ctx)
+	if !state.HitMine(coords.X, coords.Y, mover) {
+		return h.handleDefusedMine(ctx, state, coords, mover)
+	}
+	h.applyRules(ctx, state)
+	scoring.Record(state)
 
 	// Save state
 	if err := h.store.Save(ctx, state); err != nil {
@@ -74,40 +122,81 @@ func (h *GameHandlers) HandleMineHit(ctx context.Context, state *game.GameState,
 		return ctrl.Result{}, err
 	}
 
+	h.recordRatings(ctx, state)
+	h.archiveGame(ctx, state)
+	h.notify(ctx, HookMineHit, state, &coords, 0)
+
 	// Wipe the namespace (delete all game pods)
 	if err := h.wipeGamePods(ctx); err != nil {
 		logger.Error(err, "failed to wipe game pods")
 		return ctrl.Result{}, err
 	}
 
+	// The hints of a lost game are wiped with its pods
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to clear hints")
+	}
+
 	// Spawn explosion pod
-	if err := h.spawnExplosionPod(ctx, coords); err != nil {
+	if err := h.spawnExplosionPod(ctx, state, coords); err != nil {
 		logger.Error(err, "failed to spawn explosion pod")
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("game over - mine hit", "coords", coords)
+	logger.Info("game over - mine hit", "coords", coords, "by", mover, "board", render.Renderer{RLE: true}.Render(state))
 	return ctrl.Result{}, nil
 }
 
-// HandleHintCell processes a safe cell with adjacent mines.
+// handleDefusedMine keeps the game going after a mine cost a life.
+func (h *GameHandlers) handleDefusedMine(ctx context.Context, state *game.GameState, coords game.Coordinate, mover string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := h.store.Save(ctx, state); err != nil {
+		logger.Error(err, "failed to save game state after defused mine")
+		return ctrl.Result{}, err
+	}
+	h.notify(ctx, HookMineHit, state, &coords, 0)
+
+	if err := h.spawnDefusedPod(ctx, coords); err != nil {
+		logger.Error(err, "failed to spawn defused pod")
+		return ctrl.Result{}, err
+	}
+
+	if team := state.TeamOf(mover); team != nil {
+		logger.Info("mine hit, life lost", "coords", coords, "team", team.Name, "livesLeft", team.Lives)
+	} else {
+		logger.Info("mine hit, life lost", "coords", coords, "livesLeft", state.Lives)
+	}
+	return ctrl.Result{}, nil
+}
+
+// HandleHintCell processes a safe cell with adjacent mines, or any safe cell
+// of a hot/cold game.
 func (h *GameHandlers) HandleHintCell(ctx context.Context, state *game.GameState, coords game.Coordinate, hintValue int) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	// Mark cell as revealed
-	state.Reveal(coords.X, coords.Y)
+	state.RevealWith(coords.X, coords.Y, game.RevealInfo{By: moverFrom(ctx), At: time.Now()})
 	state.AddHintCell(coords.X, coords.Y)
 
 	// Create hint pod
-	if err := h.spawnHintPod(ctx, coords, hintValue); err != nil {
+	if err := h.spawnHintPod(ctx, state, coords, hintValue); err != nil {
 		logger.Error(err, "failed to spawn hint pod")
 		return ctrl.Result{}, err
 	}
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to publish hint")
+		return ctrl.Result{}, err
+	}
+	h.notify(ctx, HookReveal, state, &coords, 1)
 
 	// Check for victory
 	if state.CheckVictory() {
 		return h.handleVictory(ctx, state)
 	}
+	if h.applyRules(ctx, state) {
+		return h.handleRuleLoss(ctx, state)
+	}
 
 	// Save state
 	if err := h.store.Save(ctx, state); err != nil {
@@ -129,9 +218,11 @@ func (h *GameHandlers) HandleEmptyCell(ctx context.Context, state *game.GameStat
 		"emptyCount", len(toReveal),
 		"boundaryCount", len(boundaryHints))
 
-	// Reveal all empty cells
+	// Reveal all empty cells; only the clicked one was revealed directly
+	now := time.Now()
+	mover := moverFrom(ctx)
 	for _, c := range toReveal {
-		state.Reveal(c.X, c.Y)
+		state.RevealWith(c.X, c.Y, game.RevealInfo{By: mover, At: now, Propagated: c != coords})
 	}
 
 	// Delete pods for empty cells (they don't get hint pods)
@@ -145,7 +236,7 @@ func (h *GameHandlers) HandleEmptyCell(ctx context.Context, state *game.GameStat
 	// Create hint pods for boundary cells
 	for _, c := range boundaryHints {
 		hintValue := state.AdjacentMines(c.X, c.Y)
-		state.Reveal(c.X, c.Y)
+		state.RevealWith(c.X, c.Y, game.RevealInfo{By: mover, At: now, Propagated: true})
 		state.AddHintCell(c.X, c.Y)
 
 		// Delete the original pod first
@@ -154,15 +245,23 @@ func (h *GameHandlers) HandleEmptyCell(ctx context.Context, state *game.GameStat
 		}
 
 		// Spawn hint pod
-		if err := h.spawnHintPod(ctx, c, hintValue); err != nil {
+		if err := h.spawnHintPod(ctx, state, c, hintValue); err != nil {
 			logger.Error(err, "failed to spawn hint pod", "coords", c)
 		}
 	}
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to publish hints")
+		return ctrl.Result{}, err
+	}
+	h.notify(ctx, HookReveal, state, &coords, len(toReveal)+len(boundaryHints))
 
 	// Check for victory
 	if state.CheckVictory() {
 		return h.handleVictory(ctx, state)
 	}
+	if h.applyRules(ctx, state) {
+		return h.handleRuleLoss(ctx, state)
+	}
 
 	// Save state
 	if err := h.store.Save(ctx, state); err != nil {
@@ -176,6 +275,12 @@ func (h *GameHandlers) HandleEmptyCell(ctx context.Context, state *game.GameStat
 // bfsPropagation performs BFS from the starting coordinate to find all connected
 // empty cells and the boundary cells that have adjacent mines.
 func (h *GameHandlers) bfsPropagation(state *game.GameState, start game.Coordinate) (empty []game.Coordinate, boundary []game.Coordinate) {
+	return floodFill(state, start)
+}
+
+// floodFill implements bfsPropagation without touching the state, so that
+// moves can also be simulated.
+func floodFill(state *game.GameState, start game.Coordinate) (empty []game.Coordinate, boundary []game.Coordinate) {
 	visited := make(map[string]bool)
 	queue := []game.Coordinate{start}
 
@@ -216,6 +321,8 @@ func (h *GameHandlers) handleVictory(ctx context.Context, state *game.GameState)
 	logger := log.FromContext(ctx)
 
 	state.SetWon()
+	h.applyRules(ctx, state)
+	scoring.Record(state)
 
 	// Save state
 	if err := h.store.Save(ctx, state); err != nil {
@@ -223,44 +330,213 @@ func (h *GameHandlers) handleVictory(ctx context.Context, state *game.GameState)
 		return ctrl.Result{}, err
 	}
 
+	h.recordRatings(ctx, state)
+	h.archiveGame(ctx, state)
+	h.notify(ctx, HookVictory, state, nil, 0)
+
 	// Spawn victory pod
 	if err := h.spawnVictoryPod(ctx, state); err != nil {
 		logger.Error(err, "failed to spawn victory pod")
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("victory!", "clicks", state.Clicks, "level", state.Level)
+	stats := state.Stats()
+	logger.Info("victory!", "clicks", stats.Clicks, "level", stats.Level,
+		"elapsed", stats.Elapsed().Round(time.Second).String(), "score", stats.Score)
+	return ctrl.Result{}, nil
+}
+
+// HandleResign ends the game lost by resignation: the board is wiped like
+// after a mine hit and a summary pod shows the full board.
+func (h *GameHandlers) HandleResign(ctx context.Context, state *game.GameState) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	mover := moverFrom(ctx)
+	if !state.Resign(mover, time.Now()) {
+		return ctrl.Result{}, nil
+	}
+	scoring.Record(state)
+
+	if err := h.store.Save(ctx, state); err != nil {
+		logger.Error(err, "failed to save game state after resignation")
+		return ctrl.Result{}, err
+	}
+
+	h.recordRatings(ctx, state)
+	h.archiveGame(ctx, state)
+
+	if err := h.wipeGamePods(ctx); err != nil {
+		logger.Error(err, "failed to wipe game pods")
+		return ctrl.Result{}, err
+	}
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to clear hints")
+	}
+
+	if err := h.spawnSummaryPod(ctx, state); err != nil {
+		logger.Error(err, "failed to spawn summary pod")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("game over - resigned", "by", mover, "board", render.Renderer{RLE: true}.Render(state))
+	return ctrl.Result{}, nil
+}
+
+// HandleTimeout ends the timed game lost by running out of time: the board
+// is wiped like after a mine hit and a timeout pod shows the full board.
+func (h *GameHandlers) HandleTimeout(ctx context.Context, state *game.GameState) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !state.TimeOut(time.Now()) {
+		return ctrl.Result{}, nil
+	}
+	scoring.Record(state)
+
+	if err := h.store.Save(ctx, state); err != nil {
+		logger.Error(err, "failed to save game state after time out")
+		return ctrl.Result{}, err
+	}
+
+	h.recordRatings(ctx, state)
+	h.archiveGame(ctx, state)
+
+	if err := h.wipeGamePods(ctx); err != nil {
+		logger.Error(err, "failed to wipe game pods")
+		return ctrl.Result{}, err
+	}
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to clear hints")
+	}
+
+	if err := h.spawnTimeoutPod(ctx, state); err != nil {
+		logger.Error(err, "failed to spawn timeout pod")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("game over - time is up", "limit", state.TimeLimit, "board", render.Renderer{RLE: true}.Render(state))
+	return ctrl.Result{}, nil
+}
+
+// applyRules evaluates the custom rules of the game after a move, see
+// package rules, and reports whether they end it lost. Broken rules are
+// logged but never fail the game.
+func (h *GameHandlers) applyRules(ctx context.Context, state *game.GameState) bool {
+	lost, err := rules.Apply(state)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to apply the rules of the game")
+	}
+	return lost
+}
+
+// handleRuleLoss ends the game lost by its lose rule: the board is wiped
+// like after a mine hit and a summary pod shows the full board.
+func (h *GameHandlers) handleRuleLoss(ctx context.Context, state *game.GameState) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	state.SetLost()
+	scoring.Record(state)
+	if err := h.store.Save(ctx, state); err != nil {
+		logger.Error(err, "failed to save game state after rule loss")
+		return ctrl.Result{}, err
+	}
+
+	h.recordRatings(ctx, state)
+	h.archiveGame(ctx, state)
+
+	if err := h.wipeGamePods(ctx); err != nil {
+		logger.Error(err, "failed to wipe game pods")
+		return ctrl.Result{}, err
+	}
+	if err := h.syncHints(ctx, state); err != nil {
+		logger.Error(err, "failed to clear hints")
+	}
+
+	if err := h.spawnSummaryPod(ctx, state); err != nil {
+		logger.Error(err, "failed to spawn summary pod")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("game over - lose rule", "rule", state.Rules.Lose, "board", render.Renderer{RLE: true}.Render(state))
 	return ctrl.Result{}, nil
 }
 
-// spawnHintPod creates a hint pod at the given coordinates.
-func (h *GameHandlers) spawnHintPod(ctx context.Context, coords game.Coordinate, hintValue int) error {
+// recordRatings updates player ratings after a finished game. Ratings are
+// a side feature, so failures are logged but never fail the game.
+func (h *GameHandlers) recordRatings(ctx context.Context, state *game.GameState) {
+	if h.ratings == nil {
+		return
+	}
+	if err := h.ratings.RecordGame(ctx, state); err != nil {
+		log.FromContext(ctx).Error(err, "failed to record player ratings")
+	}
+}
+
+// archiveGame archives a finished game with its board and moves. The
+// archive is a side feature, so failures are logged but never fail the
+// game.
+func (h *GameHandlers) archiveGame(ctx context.Context, state *game.GameState) {
+	if h.archive == nil {
+		return
+	}
+	if err := h.archive.ArchiveGame(ctx, results.Game{Namespace: h.archive.Namespace, State: state}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to archive the game")
+	}
+}
+
+// syncHints publishes the hints of state to the hint aggregator, if any.
+func (h *GameHandlers) syncHints(ctx context.Context, state *game.GameState) error {
+	if h.aggregator == nil {
+		return nil
+	}
+	return h.aggregator.Sync(ctx, state)
+}
+
+// spawnHintPod creates a hint pod at the given coordinates. With the hint
+// aggregator, hints are published by syncHints instead. Hot/cold games
+// show the temperature of the cell instead of hintValue. In hint decay
+// mode, the pod stops when its hint expires.
+func (h *GameHandlers) spawnHintPod(ctx context.Context, state *game.GameState, coords game.Coordinate, hintValue int) error {
+	if h.aggregator != nil {
+		return nil
+	}
+	hint := strconv.Itoa(hintValue)
+	if state.HotCold() {
+		hint = state.Temperature(coords.X, coords.Y)
+	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      coords.HintPodName(),
 			Namespace: h.namespace,
 			Labels: map[string]string{
-				LabelApp:       "podsweeper",
-				LabelComponent: "hint",
-				LabelCoordX:    strconv.Itoa(coords.X),
-				LabelCoordY:    strconv.Itoa(coords.Y),
+				LabelApp:            "podsweeper",
+				LabelComponent:      "hint",
+				LabelCoordX:         strconv.Itoa(coords.X),
+				LabelCoordY:         strconv.Itoa(coords.Y),
+				spawner.LabelGameID: state.ID(),
 			},
 			Annotations: map[string]string{
-				AnnotationHint: strconv.Itoa(hintValue),
-				AnnotationPort: "8080",
+				AnnotationHint:    hint,
+				AnnotationPort:    "8080",
+				AnnotationVersion: version.Version,
 			},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
 			Containers: []corev1.Container{
 				{
-					Name:  "hint",
-					Image: HintAgentImage,
+					Name:            "hint",
+					Image:           HintAgentImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					// The agent reads its hint from the pod annotations, so
+					// the pod object is the single source of truth
 					Env: []corev1.EnvVar{
-						{Name: "HINT_VALUE", Value: strconv.Itoa(hintValue)},
-						{Name: "POD_X", Value: strconv.Itoa(coords.X)},
-						{Name: "POD_Y", Value: strconv.Itoa(coords.Y)},
-						{Name: "PORT", Value: "8080"},
+						spawner.AnnotationEnv("HINT_VALUE", AnnotationHint),
+						spawner.LabelEnv(spawner.EnvCoordX, LabelCoordX),
+						spawner.LabelEnv(spawner.EnvCoordY, LabelCoordY),
+						spawner.LabelEnv(spawner.EnvGameID, spawner.LabelGameID),
+						spawner.AnnotationEnv("PORT", AnnotationPort),
+						{Name: "THEME", Value: string(theme.Of(state.Theme))},
 					},
 					Ports: []corev1.ContainerPort{
 						{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
@@ -270,28 +546,63 @@ func (h *GameHandlers) spawnHintPod(ctx context.Context, coords game.Coordinate,
 		},
 	}
 
+	if fragment, ok := state.FlagFragmentAt(coords.X, coords.Y); ok {
+		pod.Annotations[AnnotationFlagFragment] = fragment.String()
+	}
+	// Decaying hints stop serving their value on their own, even before
+	// the HintDecayer deletes them
+	if expiry := state.HintExpiry(coords.X, coords.Y); !expiry.IsZero() {
+		deadline := max(int64(math.Ceil(time.Until(expiry).Seconds())), 1)
+		pod.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	return h.client.Create(ctx, pod)
+}
+
+// spawnDefusedPod creates the marker pod standing in for a defused mine.
+func (h *GameHandlers) spawnDefusedPod(ctx context.Context, coords game.Coordinate) error {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      coords.DefusedPodName(),
+			Namespace: h.namespace,
+			Labels: map[string]string{
+				LabelApp:       "podsweeper",
+				LabelComponent: "defused",
+				LabelCoordX:    strconv.Itoa(coords.X),
+				LabelCoordY:    strconv.Itoa(coords.Y),
+			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "defused",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         spawner.EchoCommand(h.message(ctx, messages.Defused, messages.Data{Cell: coords})),
+				},
+			},
+		},
+	}
+
 	return h.client.Create(ctx, pod)
 }
 
 // spawnExplosionPod creates the explosion pod after a mine is hit.
-func (h *GameHandlers) spawnExplosionPod(ctx context.Context, coords game.Coordinate) error {
-	explosionASCII := `
-    _ ._  _ , _ ._
-  (_ ' ( \` + "`" + `)_  .__)
-( (  (    )   \` + "`" + `) ) _)
-(__ (_   (_ . _) _) ,__)
-    \` + "`" + `~~\` + "`" + `\ ' . /\` + "`" + `~~\` + "`" + `
-         ;   ;
-         /   \
-_________/_ __ \_________
-
-    💥 BOOM! 💥
-    
-  You hit a mine at (%d, %d)!
-  
-     GAME OVER
-`
-	message := fmt.Sprintf(explosionASCII, coords.X, coords.Y)
+func (h *GameHandlers) spawnExplosionPod(ctx context.Context, state *game.GameState, coords game.Coordinate) error {
+	t := theme.Of(state.Theme)
+	message := h.message(ctx, messages.Explosion, messages.Data{
+		Theme:    t,
+		Banner:   t.Banner("BOOM!", "💥"),
+		Cell:     coords,
+		Board:    render.Renderer{Theme: t, Cursor: &coords}.Render(state),
+		Stats:    state.Stats(),
+		Analysis: analysis.Analyze(state).Summary(),
+	})
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -301,14 +612,107 @@ _________/_ __ \_________
 				LabelApp:       "podsweeper",
 				LabelComponent: "explosion",
 			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "explosion",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         h.profile.EndCommand(message),
+				},
+			},
+		},
+	}
+
+	return h.client.Create(ctx, pod)
+}
+
+// spawnSummaryPod creates the summary pod after a resignation, showing the
+// full board: mines, and the hints of the cells left hidden.
+func (h *GameHandlers) spawnSummaryPod(ctx context.Context, state *game.GameState) error {
+	t := theme.Of(state.Theme)
+	resignedBy := ""
+	if state.ResignedBy != "" {
+		resignedBy = h.players.DisplayName(state.ResignedBy)
+	}
+	message := h.message(ctx, messages.Summary, messages.Data{
+		Theme:      t,
+		Banner:     t.Banner("RESIGNED", "🏳️"),
+		Board:      render.Renderer{Theme: t, View: render.ViewSolution}.Render(state),
+		Stats:      state.Stats(),
+		ResignedBy: resignedBy,
+		Analysis:   analysis.Analyze(state).Summary(),
+	})
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SummaryPodName,
+			Namespace: h.namespace,
+			Labels: map[string]string{
+				LabelApp:       "podsweeper",
+				LabelComponent: "summary",
+			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "summary",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         h.profile.EndCommand(message),
+				},
+			},
+		},
+	}
+
+	return h.client.Create(ctx, pod)
+}
+
+// spawnTimeoutPod creates the timeout pod after a timed game ran out of
+// time, showing the full board like the summary pod.
+func (h *GameHandlers) spawnTimeoutPod(ctx context.Context, state *game.GameState) error {
+	t := theme.Of(state.Theme)
+	message := h.message(ctx, messages.Timeout, messages.Data{
+		Theme:    t,
+		Banner:   t.Banner("TIME'S UP", "⏰"),
+		Board:    render.Renderer{Theme: t, View: render.ViewSolution}.Render(state),
+		Stats:    state.Stats(),
+		Elapsed:  state.Elapsed().Round(time.Second).String(),
+		Analysis: analysis.Analyze(state).Summary(),
+	})
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TimeoutPodName,
+			Namespace: h.namespace,
+			Labels: map[string]string{
+				LabelApp:       "podsweeper",
+				LabelComponent: "timeout",
+			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
 			Containers: []corev1.Container{
 				{
-					Name:    "explosion",
-					Image:   ExplosionImage,
-					Command: []string{"sh", "-c", fmt.Sprintf("echo '%s' && sleep infinity", message)},
+					Name:            "timeout",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         h.profile.EndCommand(message),
 				},
 			},
 		},
@@ -319,27 +723,24 @@ _________/_ __ \_________
 
 // spawnVictoryPod creates the victory pod after winning.
 func (h *GameHandlers) spawnVictoryPod(ctx context.Context, state *game.GameState) error {
-	victoryASCII := `
-    ___________
-   '._==_==_=_.'
-   .-\:      /-.
-  | (|:.     |) |
-   '-|:.     |-'
-     \::.    /
-      '::. .'
-        ) (
-      _.' '._
-     \` + "`" + `"""""""\` + "`" + `
-
-  🎉 VICTORY! 🎉
-  
-  Level: %d
-  Clicks: %d
-  Mines: %d
-  
-  Congratulations!
-`
-	message := fmt.Sprintf(victoryASCII, state.Level, state.Clicks, state.MineCount)
+	t := theme.Of(state.Theme)
+	stats := state.Stats()
+	team, _ := state.WinningTeam()
+	elapsed := stats.Elapsed().Round(time.Second).String()
+	if state.Speedrun {
+		elapsed = game.FormatSpeedrunTime(state.FinalTime)
+	}
+	message := h.message(ctx, messages.Victory, messages.Data{
+		Theme:     t,
+		Banner:    t.Banner("VICTORY!", "🎉"),
+		Stats:     stats,
+		Elapsed:   elapsed,
+		Team:      team.Name,
+		Players:   h.playersLine(state),
+		Flag:      flagLine(state),
+		Analysis:  analysis.Analyze(state).Summary(),
+		ScoreCard: state.ScoreCard,
+	})
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -349,22 +750,73 @@ func (h *GameHandlers) spawnVictoryPod(ctx context.Context, state *game.GameStat
 				LabelApp:       "podsweeper",
 				LabelComponent: "victory",
 			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
 			Containers: []corev1.Container{
 				{
-					Name:    "victory",
-					Image:   VictoryImage,
-					Command: []string{"sh", "-c", fmt.Sprintf("echo '%s' && sleep infinity", message)},
+					Name:            "victory",
+					Image:           VictoryImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         h.profile.EndCommand(message),
 				},
 			},
 		},
 	}
 
+	maps.Copy(pod.Annotations, scoring.Annotations(state.ScoreCard))
 	return h.client.Create(ctx, pod)
 }
 
+// message renders the message called name. Custom messages failing to
+// render are logged and fall back to the built-in ones.
+func (h *GameHandlers) message(ctx context.Context, name string, data messages.Data) string {
+	message, err := h.messages.Render(name, data)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to render custom message", "message", name)
+	}
+	return message
+}
+
+// flagLine shows the final CTF flag fragment, only given to winners.
+func flagLine(state *game.GameState) string {
+	fragment, ok := state.FinalFlagFragment()
+	if !ok {
+		return ""
+	}
+	return "Final flag fragment: " + fragment.String() + "\n"
+}
+
+// playersLine lists the players who revealed cells, best contributors first,
+// using their registered display names. Empty for anonymous games.
+func (h *GameHandlers) playersLine(state *game.GameState) string {
+	contributions := state.Contributions()
+	if len(contributions) == 0 {
+		return ""
+	}
+
+	usernames := make([]string, 0, len(contributions))
+	for u := range contributions {
+		usernames = append(usernames, u)
+	}
+	sort.Slice(usernames, func(i, j int) bool {
+		if contributions[usernames[i]] != contributions[usernames[j]] {
+			return contributions[usernames[i]] > contributions[usernames[j]]
+		}
+		return usernames[i] < usernames[j]
+	})
+
+	names := make([]string, len(usernames))
+	for i, u := range usernames {
+		names[i] = fmt.Sprintf("%s (%d)", h.players.DisplayName(u), contributions[u])
+	}
+	return "Players: " + strings.Join(names, ", ") + "\n"
+}
+
 // deletePod deletes a game pod at the given coordinates.
 func (h *GameHandlers) deletePod(ctx context.Context, coords game.Coordinate) error {
 	pod := &corev1.Pod{
@@ -385,8 +837,12 @@ func (h *GameHandlers) wipeGamePods(ctx context.Context) error {
 	}
 
 	for _, pod := range podList.Items {
-		// Only delete game pods (pod-X-Y or hint-X-Y)
-		if IsPodName(pod.Name) || IsHintPodName(pod.Name) {
+		// Only delete game pods (pod-X-Y, hint-X-Y, defused-X-Y, bomb-X-Y or resign) that are not protected
+		if h.protected.Protects(&pod) {
+			continue
+		}
+		if IsPodName(pod.Name) || IsHintPodName(pod.Name) || IsDefusedPodName(pod.Name) || IsBombPodName(pod.Name) ||
+			pod.Name == spawner.ResignPodName {
 			if err := h.client.Delete(ctx, &pod); err != nil {
 				// Log but continue with other deletions
 				log.FromContext(ctx).Error(err, "failed to delete pod", "name", pod.Name)
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultHeartbeatInterval is how often the Gamemaster records that it is
+// running the current game.
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// Heartbeat periodically stamps the game state so that time the Gamemaster
+// was down is excluded from the game clock. It runs as a manager Runnable,
+// only on the leader.
+type Heartbeat struct {
+	// Store persists the game state.
+	Store game.Store
+
+	// Interval between heartbeats. Defaults to DefaultHeartbeatInterval.
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable. A heartbeat is recorded immediately so
+// that downtime is accounted for as soon as the Gamemaster starts.
+func (h *Heartbeat) Start(ctx context.Context) error {
+	interval := h.interval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.beat(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (h *Heartbeat) NeedLeaderElection() bool {
+	return true
+}
+
+func (h *Heartbeat) interval() time.Duration {
+	if h.Interval <= 0 {
+		return DefaultHeartbeatInterval
+	}
+	return h.Interval
+}
+
+// beat records a heartbeat on the running game, if any.
+// Gaps longer than two intervals are counted as downtime.
+func (h *Heartbeat) beat(ctx context.Context, now time.Time) {
+	logger := log.FromContext(ctx).WithName("heartbeat")
+
+	state, err := h.Store.Load(ctx)
+	if err != nil {
+		logger.Error(err, "failed to load game state")
+		return
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return
+	}
+
+	if downtime := state.RecordHeartbeat(now, 2*h.interval()); downtime > 0 {
+		logger.Info("excluding Gamemaster downtime from game clock", "downtime", downtime.Round(time.Second).String())
+	}
+	if err := h.Store.Save(ctx, state); err != nil {
+		logger.Error(err, "failed to save heartbeat")
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestHeartbeat_ExcludesDowntime(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := createTestGameState(8)
+	state.StartedAt = start
+	state.HeartbeatAt = start.Add(time.Minute)
+	_ = store.Save(ctx, state)
+
+	h := &Heartbeat{Store: store, Interval: time.Minute}
+	// The Gamemaster comes back 10 minutes after its last heartbeat
+	h.beat(ctx, start.Add(11*time.Minute))
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.HeartbeatAt.Equal(start.Add(11 * time.Minute)) {
+		t.Errorf("expected heartbeat to be recorded, got %s", loaded.HeartbeatAt)
+	}
+	// 10 minute gap, of which 2 intervals count as play time
+	if loaded.PausedDuration != 8*time.Minute {
+		t.Errorf("expected 8m downtime, got %s", loaded.PausedDuration)
+	}
+}
+
+func TestHeartbeat_IgnoresEndedAndMissingGames(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	h := &Heartbeat{Store: store}
+
+	// No game: nothing to do
+	h.beat(ctx, time.Now())
+	if exists, _ := store.Exists(ctx); exists {
+		t.Error("heartbeat should not create a game state")
+	}
+
+	state := createTestGameState(8)
+	state.SetLost()
+	_ = store.Save(ctx, state)
+
+	h.beat(ctx, time.Now())
+	loaded, _ := store.Load(ctx)
+	if !loaded.HeartbeatAt.IsZero() {
+		t.Error("heartbeat should not touch an ended game")
+	}
+}
+
+func TestHeartbeat_StartStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, createTestGameState(8))
+
+	h := &Heartbeat{Store: store, Interval: time.Hour}
+	done := make(chan error)
+	go func() { done <- h.Start(ctx) }()
+
+	// The first heartbeat is recorded immediately
+	deadline := time.After(5 * time.Second)
+	for {
+		loaded, _ := store.Load(context.Background())
+		if !loaded.HeartbeatAt.IsZero() {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected an immediate heartbeat")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned error: %v", err)
+	}
+	if !h.NeedLeaderElection() {
+		t.Error("heartbeat must only run on the leader")
+	}
+}
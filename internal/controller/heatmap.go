@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// Values of podsweeper_cell_state. Revealed safe cells are exported as
+// their hint, 0 to 8.
+const (
+	CellStateHidden     = -1
+	CellStateFlagged    = -2
+	CellStateQuestioned = -3
+	CellStateDefused    = -4
+	CellStateExploded   = -5
+)
+
+// DefaultCellStateTimeout bounds loading the game state on a scrape.
+const DefaultCellStateTimeout = 5 * time.Second
+
+var cellStateDesc = prometheus.NewDesc(
+	"podsweeper_cell_state",
+	"State of each cell of the board as players see it: the hint of revealed cells (0-8), "+
+		"-1 hidden, -2 flagged, -3 questioned, -4 defused, -5 exploded mine.",
+	[]string{"x", "y"}, nil,
+)
+
+// CellStateCollector exports the board as one podsweeper_cell_state gauge
+// per cell, so that it can be rendered as a Grafana heatmap. Only what
+// players see is exported: hidden mines look like any hidden cell.
+type CellStateCollector struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Timeout bounds loading the game state. Defaults to
+	// DefaultCellStateTimeout.
+	Timeout time.Duration
+}
+
+// Register registers the collector with the controller-runtime metrics
+// registry, served by the metrics server of the Manager.
+func (c *CellStateCollector) Register() error {
+	return ctrlmetrics.Registry.Register(c)
+}
+
+// Describe implements prometheus.Collector.
+func (c *CellStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cellStateDesc
+}
+
+// Collect implements prometheus.Collector. Nothing is exported without a
+// game.
+func (c *CellStateCollector) Collect(ch chan<- prometheus.Metric) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCellStateTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	state, err := c.Store.Load(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(cellStateDesc, err)
+		return
+	}
+	if state == nil {
+		return
+	}
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			ch <- prometheus.MustNewConstMetric(cellStateDesc, prometheus.GaugeValue,
+				float64(CellState(state, x, y)), strconv.Itoa(x), strconv.Itoa(y))
+		}
+	}
+}
+
+// CellState returns the podsweeper_cell_state value of the cell at (x, y),
+// as players see it.
+func CellState(state *game.GameState, x, y int) int {
+	cell, ok := state.Cell(x, y)
+	switch {
+	case !ok:
+		return CellStateHidden
+	case cell.Revealed && cell.Mine:
+		return CellStateExploded
+	case cell.Defused:
+		return CellStateDefused
+	case cell.Revealed:
+		return cell.Hint
+	case cell.Flagged:
+		return CellStateFlagged
+	case cell.Question:
+		return CellStateQuestioned
+	default:
+		return CellStateHidden
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestCellState(t *testing.T) {
+	state := createTestGameState(3) // mine at (1,1)
+	state.SetMine(2, 2)
+	state.Reveal(0, 0)
+	state.SetFlag(2, 0, true)
+	state.SetQuestion(0, 2, true)
+	state.Reveal(2, 2)
+
+	tests := []struct {
+		name string
+		x, y int
+		want int
+	}{
+		{"revealed hint", 0, 0, 1},
+		{"hidden mine", 1, 1, CellStateHidden},
+		{"hidden safe", 1, 0, CellStateHidden},
+		{"flagged", 2, 0, CellStateFlagged},
+		{"questioned", 0, 2, CellStateQuestioned},
+		{"exploded", 2, 2, CellStateExploded},
+		{"out of the board", 5, 5, CellStateHidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CellState(state, tt.x, tt.y); got != tt.want {
+				t.Errorf("CellState(%d, %d) = %d, want %d", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCellStateCollector(t *testing.T) {
+	store := game.NewMemoryStore()
+	collector := &CellStateCollector{Store: store}
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("expected no metrics without a game, got %d", n)
+	}
+
+	state := game.NewGameState(2, 0)
+	state.SetMine(1, 1)
+	state.Reveal(0, 0)
+	if err := store.Save(context.Background(), state); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	expected := `
+# HELP podsweeper_cell_state State of each cell of the board as players see it: the hint of revealed cells (0-8), -1 hidden, -2 flagged, -3 questioned, -4 defused, -5 exploded mine.
+# TYPE podsweeper_cell_state gauge
+podsweeper_cell_state{x="0",y="0"} 1
+podsweeper_cell_state{x="0",y="1"} -1
+podsweeper_cell_state{x="1",y="0"} -1
+podsweeper_cell_state{x="1",y="1"} -1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCellStateCollectorLoadError(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(&CellStateCollector{Store: failingStore{}})
+	if _, err := registry.Gather(); err == nil {
+		t.Error("expected failing to load the game to fail the scrape")
+	}
+}
+
+// failingStore fails to load the game state.
+type failingStore struct {
+	game.Store
+}
+
+func (failingStore) Load(context.Context) (*game.GameState, error) {
+	return nil, errors.New("unavailable")
+}
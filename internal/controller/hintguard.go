@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/level"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// HintGuardLevel is the first level where the HintGuard refuses the
+	// deletion of hint pods.
+	HintGuardLevel = level.Firewall
+
+	// HintGuardPath is the path the HintGuard is served at by the webhook
+	// server.
+	HintGuardPath = "/validate-hint-pods"
+)
+
+// hintGuardComponents are the Kubernetes components deleting pods on their
+// own, such as when a node goes away or the namespace is deleted. Nodes
+// are allowed too, for evictions.
+var hintGuardComponents = []string{
+	"system:kube-controller-manager",
+	"system:kube-scheduler",
+	serviceAccountUsername("kube-system", "generic-garbage-collector"),
+	serviceAccountUsername("kube-system", "namespace-controller"),
+	serviceAccountUsername("kube-system", "node-controller"),
+	serviceAccountUsername("kube-system", "pod-garbage-collector"),
+}
+
+// HintGuard is a validating admission webhook refusing the deletion of hint
+// pods at the levels protecting hints, from HintGuardLevel, so players
+// can't erase the hints they revealed to confuse each other. Register it
+// for the DELETE of pods in the game namespace:
+//
+//	mgr.GetWebhookServer().Register(HintGuardPath, &webhook.Admission{Handler: guard})
+//
+// Deleting the hint pod of a cell that can be chorded is allowed, since
+// chording spawns it again. The Gamemaster, Kubernetes components and
+// ended games are never refused. The HintGuard changes nothing, so dry-run
+// deletes are validated like any other.
+type HintGuard struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Gamemaster is the username of the Gamemaster ServiceAccount.
+	// Defaults to the podsweeper-gamemaster ServiceAccount of Namespace.
+	Gamemaster string
+
+	// Allowed lists usernames always allowed to delete hint pods, in
+	// addition to the Gamemaster and Kubernetes components.
+	Allowed []string
+}
+
+// Handle validates an admission request.
+func (g *HintGuard) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete || req.Resource.Resource != "pods" || req.Namespace != g.Namespace {
+		return admission.Allowed("")
+	}
+	coords, ok := game.ParseHintPodName(req.Name)
+	if !ok || g.allowed(req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	state, err := g.Store.Load(ctx)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to load game state: %w", err))
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return admission.Allowed("")
+	}
+	// Custom levels may guard hints harder than their level
+	policy, err := level.ForState(state)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "falling back to the game level for obstacles")
+	}
+	if !policy.ProtectsHints() {
+		return admission.Allowed("")
+	}
+	if _, ok := state.Chord(coords.X, coords.Y); ok {
+		return admission.Allowed("chording")
+	}
+
+	log.FromContext(ctx).Info("refused a hint pod deletion", "coords", coords, "player", req.UserInfo.Username,
+		"dryRun", req.DryRun != nil && *req.DryRun)
+	return admission.Denied(fmt.Sprintf("hint pods can't be deleted at level %d (%s): %s stays",
+		policy.Level(), policy.Name(), req.Name))
+}
+
+// allowed reports whether username is the Gamemaster, a Kubernetes
+// component or explicitly allowed. Unlike CheatDetector.ignored, other
+// system: users are refused: ServiceAccounts of any namespace may belong
+// to players.
+func (g *HintGuard) allowed(username string) bool {
+	gamemaster := g.Gamemaster
+	if gamemaster == "" {
+		gamemaster = serviceAccountUsername(g.Namespace, rbac.GamemasterName)
+	}
+	return username == gamemaster || strings.HasPrefix(username, "system:node:") ||
+		slices.Contains(hintGuardComponents, username) || slices.Contains(g.Allowed, username)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func deleteRequest(user, name string, dryRun bool) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Delete,
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Namespace: testNamespace,
+		Name:      name,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+		DryRun:    &dryRun,
+	}}
+}
+
+func TestHintGuard(t *testing.T) {
+	ctx := context.Background()
+	hint := game.Coordinate{X: 0, Y: 0}.HintPodName()
+
+	tests := []struct {
+		name    string
+		level   int
+		status  game.GameStatus
+		flagged bool
+		req     admission.Request
+		allowed bool
+	}{
+		{"hint pod", HintGuardLevel, game.StatusPlaying, false, deleteRequest("bob", hint, false), false},
+		{"dry-run", HintGuardLevel, game.StatusPlaying, false, deleteRequest("bob", hint, true), false},
+		{"below the level", HintGuardLevel - 1, game.StatusPlaying, false, deleteRequest("bob", hint, false), true},
+		{"ended game", HintGuardLevel, game.StatusWon, false, deleteRequest("bob", hint, false), true},
+		{"cell pod", HintGuardLevel, game.StatusPlaying, false, deleteRequest("bob", "pod-0-1", false), true},
+		{"chording", HintGuardLevel, game.StatusPlaying, true, deleteRequest("bob", hint, false), true},
+		{"gamemaster", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:serviceaccount:"+testNamespace+":podsweeper-gamemaster", hint, false), true},
+		{"kubernetes component", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:serviceaccount:kube-system:namespace-controller", hint, false), true},
+		{"player service account", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:serviceaccount:"+testNamespace+":player", hint, false), false},
+		{"foreign service account", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:serviceaccount:sandbox:player", hint, false), false},
+		{"system user", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:anonymous", hint, false), false},
+		{"node", HintGuardLevel, game.StatusPlaying, false, deleteRequest("system:node:worker-1", hint, false), true},
+		{"allowed user", HintGuardLevel, game.StatusPlaying, false, deleteRequest("admin", hint, false), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Mine at (1,1), next to the revealed (0,0)
+			state := createTestGameState(3)
+			state.Level = tt.level
+			state.Reveal(0, 0)
+			state.Status = tt.status
+			if tt.flagged {
+				state.SetPlayerFlag(1, 1, true)
+			}
+			store := game.NewMemoryStore()
+			_ = store.Save(ctx, state)
+
+			guard := &HintGuard{Store: store, Namespace: testNamespace, Allowed: []string{"admin"}}
+			resp := guard.Handle(ctx, tt.req)
+			if resp.Allowed != tt.allowed {
+				t.Errorf("Handle() allowed = %v, want %v: %v", resp.Allowed, tt.allowed, resp.Result)
+			}
+		})
+	}
+
+	// The Gamemaster may run outside the game namespace
+	state := createTestGameState(3)
+	state.Level = HintGuardLevel
+	state.Reveal(0, 0)
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	gamemaster := "system:serviceaccount:podsweeper-system:podsweeper-gamemaster"
+	guard := &HintGuard{Store: store, Namespace: testNamespace, Gamemaster: gamemaster}
+	if resp := guard.Handle(ctx, deleteRequest(gamemaster, hint, false)); !resp.Allowed {
+		t.Errorf("expected the configured Gamemaster to be allowed, got %v", resp.Result)
+	}
+	if resp := guard.Handle(ctx, deleteRequest("system:serviceaccount:"+testNamespace+":podsweeper-gamemaster", hint, false)); resp.Allowed {
+		t.Error("expected only the configured Gamemaster to be allowed")
+	}
+
+	// Nothing is checked without a game
+	guard = &HintGuard{Store: game.NewMemoryStore(), Namespace: testNamespace}
+	if resp := guard.Handle(ctx, deleteRequest("bob", hint, false)); !resp.Allowed {
+		t.Errorf("expected deletes allowed without a game, got %v", resp.Result)
+	}
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+const (
+	// HintScrambleLevel is the first level where the HintScrambler
+	// scrambles the hints of hint pods.
+	HintScrambleLevel = level.PortHacking
+
+	// HintScramblerPath is the path the HintScrambler is served at by the
+	// webhook server.
+	HintScramblerPath = "/mutate-hint-pods"
+)
+
+// HintScrambler is a mutating admission webhook scrambling the hint
+// annotation of hint pods at the levels scrambling hints, from
+// HintScrambleLevel, before they are persisted. Players listing pods then
+// only see scrambled hints and must ask hint agents over HTTP, which
+// unscramble their hint with the key of hints.ScrambleKeySecret, given to
+// them by the kubelet. Register it for the CREATE of pods in the game
+// namespace:
+//
+//	mgr.GetWebhookServer().Register(HintScramblerPath, &webhook.Admission{Handler: scrambler})
+//
+// Dry-run creates are scrambled like any other: the HintScrambler only
+// mutates the pod.
+type HintScrambler struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Key scrambles the hints, see hints.LoadScrambleKey.
+	Key []byte
+}
+
+// Handle mutates an admission request.
+func (s *HintScrambler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create || req.Resource.Resource != "pods" || req.Namespace != s.Namespace {
+		return admission.Allowed("")
+	}
+	pod := &corev1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode pod: %w", err))
+	}
+	// Pods created with generateName have no name yet
+	name := pod.Name
+	if name == "" {
+		name = req.Name
+	}
+	hint, ok := pod.Annotations[AnnotationHint]
+	if _, isHint := ParseHintPodName(name); !isHint || !ok || hints.IsScrambled(hint) {
+		return admission.Allowed("")
+	}
+
+	state, err := s.Store.Load(ctx)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to load game state: %w", err))
+	}
+	if state == nil {
+		return admission.Allowed("")
+	}
+	// Custom levels may guard hints harder than their level
+	policy, err := level.ForState(state)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "falling back to the game level for obstacles")
+	}
+	if !policy.ScramblesHints() {
+		return admission.Allowed("")
+	}
+
+	scrambled, err := hints.Scramble(s.Key, hint)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	pod.Annotations[AnnotationHint] = scrambled
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == "hint" {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, hints.ScrambleKeyEnvVar())
+		}
+	}
+
+	mutated, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to encode pod: %w", err))
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+)
+
+func createRequest(t *testing.T, pod *corev1.Pod) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(pod)
+	if err != nil {
+		t.Fatalf("failed to encode pod: %v", err)
+	}
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Namespace: testNamespace,
+		Name:      pod.Name,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+// patched applies the patches of resp to pod.
+func patched(t *testing.T, pod *corev1.Pod, patches []jsonpatch.JsonPatchOperation) *corev1.Pod {
+	t.Helper()
+	// Only the annotation and env patches are expected
+	out := pod.DeepCopy()
+	for _, p := range patches {
+		switch p.Path {
+		case "/metadata/annotations/podsweeper.io~1hint":
+			out.Annotations[AnnotationHint] = p.Value.(string)
+		case "/spec/containers/0/env":
+			raw, _ := json.Marshal(p.Value)
+			_ = json.Unmarshal(raw, &out.Spec.Containers[0].Env)
+		default:
+			t.Fatalf("unexpected patch %+v", p)
+		}
+	}
+	return out
+}
+
+func TestHintScrambler(t *testing.T) {
+	ctx := context.Background()
+	key, _ := hints.NewScrambleKey()
+	hintPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        game.Coordinate{X: 0, Y: 0}.HintPodName(),
+			Namespace:   testNamespace,
+			Annotations: map[string]string{AnnotationHint: "1"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "hint", Image: HintAgentImage}}},
+	}
+	cellPod := createTestPod("pod-0-1", testNamespace)
+	cellPod.Annotations = map[string]string{AnnotationHint: "1"}
+
+	tests := []struct {
+		name      string
+		level     int
+		pod       *corev1.Pod
+		scrambled bool
+	}{
+		{"hint pod", HintScrambleLevel, hintPod, true},
+		{"below the level", HintScrambleLevel - 1, hintPod, false},
+		{"cell pod", HintScrambleLevel, cellPod, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := createTestGameState(3)
+			state.Level = tt.level
+			store := game.NewMemoryStore()
+			_ = store.Save(ctx, state)
+
+			scrambler := &HintScrambler{Store: store, Namespace: testNamespace, Key: key}
+			resp := scrambler.Handle(ctx, createRequest(t, tt.pod))
+			if !resp.Allowed {
+				t.Fatalf("expected the pod allowed, got %v", resp.Result)
+			}
+			if !tt.scrambled {
+				if len(resp.Patches) != 0 {
+					t.Errorf("expected no patch, got %+v", resp.Patches)
+				}
+				return
+			}
+
+			pod := patched(t, tt.pod, resp.Patches)
+			annotated := pod.Annotations[AnnotationHint]
+			if hint, err := hints.Unscramble(key, annotated); !hints.IsScrambled(annotated) || err != nil || hint != "1" {
+				t.Errorf("expected the hint scrambled, got %q: %v", annotated, err)
+			}
+			env := pod.Spec.Containers[0].Env
+			if len(env) != 1 || env[0].Name != hints.ScrambleKeyEnv || env[0].ValueFrom.SecretKeyRef.Name != hints.ScrambleKeySecret {
+				t.Errorf("expected the agent given the scramble key, got %+v", env)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultHookTimeout bounds each HTTP hook call, so a slow endpoint only
+// briefly delays the game.
+const DefaultHookTimeout = 2 * time.Second
+
+// HookKind identifies the game event a hook is notified of.
+type HookKind string
+
+const (
+	// HookGameStart is the first move of a game.
+	HookGameStart HookKind = "gameStart"
+	// HookReveal is a safe cell clicked, revealing one cell or more.
+	HookReveal HookKind = "reveal"
+	// HookMineHit is a mine clicked, ending the game or costing a team a life.
+	HookMineHit HookKind = "mineHit"
+	// HookVictory is the last safe cell revealed.
+	HookVictory HookKind = "victory"
+	// HookFlag is a mine flagged, such as by a power-up.
+	HookFlag HookKind = "flag"
+)
+
+// HookEvent describes a game event to hooks.
+type HookEvent struct {
+	Event  HookKind `json:"event"`
+	GameID string   `json:"gameID"`
+	Level  int      `json:"level"`
+	// Cell is the cell played, nil for a victory.
+	Cell *game.Coordinate `json:"cell,omitempty"`
+	// Player made the move, empty if unknown.
+	Player string `json:"player,omitempty"`
+	// Revealed counts the cells revealed by a reveal.
+	Revealed int `json:"revealed,omitempty"`
+	// Score is the score of a victory.
+	Score int       `json:"score,omitempty"`
+	At    time.Time `json:"at"`
+
+	// State is the game the event happened in. Hooks must not modify it.
+	State *game.GameState `json:"-"`
+}
+
+// Hooks are notified of game events, to bolt custom behavior such as
+// lights, buzzers or chat bots on the game without modifying the
+// controller. They are called as the moves are played: their errors are
+// logged but never fail the game.
+type Hooks interface {
+	OnGameStart(ctx context.Context, event HookEvent) error
+	OnReveal(ctx context.Context, event HookEvent) error
+	OnMineHit(ctx context.Context, event HookEvent) error
+	OnVictory(ctx context.Context, event HookEvent) error
+	OnFlag(ctx context.Context, event HookEvent) error
+}
+
+// HookList notifies every hook in turn.
+type HookList []Hooks
+
+// each calls fn on every hook, even after errors.
+func (l HookList) each(fn func(Hooks) error) error {
+	var errs []error
+	for _, h := range l {
+		errs = append(errs, fn(h))
+	}
+	return errors.Join(errs...)
+}
+
+// OnGameStart notifies every hook of the start of a game.
+func (l HookList) OnGameStart(ctx context.Context, event HookEvent) error {
+	return l.each(func(h Hooks) error { return h.OnGameStart(ctx, event) })
+}
+
+// OnReveal notifies every hook of a reveal.
+func (l HookList) OnReveal(ctx context.Context, event HookEvent) error {
+	return l.each(func(h Hooks) error { return h.OnReveal(ctx, event) })
+}
+
+// OnMineHit notifies every hook of a mine hit.
+func (l HookList) OnMineHit(ctx context.Context, event HookEvent) error {
+	return l.each(func(h Hooks) error { return h.OnMineHit(ctx, event) })
+}
+
+// OnVictory notifies every hook of a victory.
+func (l HookList) OnVictory(ctx context.Context, event HookEvent) error {
+	return l.each(func(h Hooks) error { return h.OnVictory(ctx, event) })
+}
+
+// OnFlag notifies every hook of a flag.
+func (l HookList) OnFlag(ctx context.Context, event HookEvent) error {
+	return l.each(func(h Hooks) error { return h.OnFlag(ctx, event) })
+}
+
+// HTTPHooks posts every event as JSON to each of its URLs.
+type HTTPHooks struct {
+	URLs []string
+
+	// Client posts the events. Defaults to a client timing out after
+	// DefaultHookTimeout.
+	Client *http.Client
+}
+
+// OnGameStart posts the start of a game.
+func (h *HTTPHooks) OnGameStart(ctx context.Context, event HookEvent) error {
+	return h.post(ctx, event)
+}
+
+// OnReveal posts a reveal.
+func (h *HTTPHooks) OnReveal(ctx context.Context, event HookEvent) error { return h.post(ctx, event) }
+
+// OnMineHit posts a mine hit.
+func (h *HTTPHooks) OnMineHit(ctx context.Context, event HookEvent) error { return h.post(ctx, event) }
+
+// OnVictory posts a victory.
+func (h *HTTPHooks) OnVictory(ctx context.Context, event HookEvent) error { return h.post(ctx, event) }
+
+// OnFlag posts a flag.
+func (h *HTTPHooks) OnFlag(ctx context.Context, event HookEvent) error { return h.post(ctx, event) }
+
+// post sends event to every URL, even after errors.
+func (h *HTTPHooks) post(ctx context.Context, event HookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Event, err)
+	}
+	httpClient := h.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultHookTimeout}
+	}
+
+	var errs []error
+	for _, url := range h.URLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid hook URL %s: %w", url, err))
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to post %s event to %s: %w", event.Event, url, err))
+			continue
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			errs = append(errs, fmt.Errorf("hook %s refused %s event: %s", url, event.Event, resp.Status))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notify calls the hook of kind with the move playing cell, if hooks are
+// set.
+func (h *GameHandlers) notify(ctx context.Context, kind HookKind, state *game.GameState, cell *game.Coordinate, revealed int) {
+	if h.hooks == nil {
+		return
+	}
+	event := HookEvent{
+		Event:    kind,
+		GameID:   state.ID(),
+		Level:    state.Level,
+		Cell:     cell,
+		Player:   moverFrom(ctx),
+		Revealed: revealed,
+		At:       time.Now(),
+		State:    state,
+	}
+	var err error
+	switch kind {
+	case HookGameStart:
+		err = h.hooks.OnGameStart(ctx, event)
+	case HookReveal:
+		err = h.hooks.OnReveal(ctx, event)
+	case HookMineHit:
+		err = h.hooks.OnMineHit(ctx, event)
+	case HookVictory:
+		event.Score = state.Stats().Score
+		err = h.hooks.OnVictory(ctx, event)
+	case HookFlag:
+		err = h.hooks.OnFlag(ctx, event)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "game hook failed", "event", kind)
+	}
+}
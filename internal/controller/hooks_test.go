@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// recordedHooks records the events it is notified of.
+type recordedHooks struct {
+	events []HookEvent
+	err    error
+}
+
+func (r *recordedHooks) record(event HookEvent) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func (r *recordedHooks) OnGameStart(_ context.Context, e HookEvent) error { return r.record(e) }
+func (r *recordedHooks) OnReveal(_ context.Context, e HookEvent) error    { return r.record(e) }
+func (r *recordedHooks) OnMineHit(_ context.Context, e HookEvent) error   { return r.record(e) }
+func (r *recordedHooks) OnVictory(_ context.Context, e HookEvent) error   { return r.record(e) }
+func (r *recordedHooks) OnFlag(_ context.Context, e HookEvent) error      { return r.record(e) }
+
+func TestGameHandlers_Hooks(t *testing.T) {
+	ctx := withMover(context.Background(), "alice")
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+
+	state := game.NewGameState(2, 12345)
+	state.SetMine(0, 0)
+	state.SetMine(0, 1)
+	state.SetMine(1, 0)
+	_ = store.Save(ctx, state)
+
+	// A failing hook never fails the game
+	hooks := &recordedHooks{err: errors.New("buzzer unplugged")}
+	handlers := NewGameHandlers(fakeClient, store, testNamespace)
+	handlers.hooks = hooks
+
+	if _, err := handlers.HandleHintCell(ctx, state, game.Coordinate{X: 1, Y: 1}, 3); err != nil {
+		t.Fatalf("HandleHintCell returned error: %v", err)
+	}
+	if len(hooks.events) != 2 {
+		t.Fatalf("expected a reveal and a victory, got %+v", hooks.events)
+	}
+	reveal, victory := hooks.events[0], hooks.events[1]
+	if reveal.Event != HookReveal || reveal.Player != "alice" || reveal.Revealed != 1 ||
+		reveal.Cell == nil || *reveal.Cell != (game.Coordinate{X: 1, Y: 1}) {
+		t.Errorf("expected alice's reveal of (1,1), got %+v", reveal)
+	}
+	if victory.Event != HookVictory || victory.GameID != state.ID() || victory.Cell != nil || victory.Score == 0 {
+		t.Errorf("expected the scored victory, got %+v", victory)
+	}
+}
+
+func TestHookList(t *testing.T) {
+	failing, ok := &recordedHooks{err: errors.New("down")}, &recordedHooks{}
+	err := HookList{failing, ok}.OnFlag(context.Background(), HookEvent{Event: HookFlag})
+	if err == nil || len(failing.events) != 1 || len(ok.events) != 1 {
+		t.Errorf("expected every hook to be notified and the error reported, got %v", err)
+	}
+}
+
+func TestHTTPHooks(t *testing.T) {
+	var got []HookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event HookEvent
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&event) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, event)
+	}))
+	defer server.Close()
+	refusing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer refusing.Close()
+
+	hooks := &HTTPHooks{URLs: []string{refusing.URL, server.URL}}
+	err := hooks.OnMineHit(context.Background(), HookEvent{Event: HookMineHit, GameID: "42", Cell: &game.Coordinate{X: 2, Y: 3}})
+	if err == nil {
+		t.Error("expected the refused event to be reported")
+	}
+	if len(got) != 1 || got[0].Event != HookMineHit || got[0].GameID != "42" || *got[0].Cell != (game.Coordinate{X: 2, Y: 3}) {
+		t.Errorf("expected the mine hit to be posted despite the refusal, got %+v", got)
+	}
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/level"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// NetworkPolicyLevel is the first level ("The Firewall") where hint pods
+	// can only be reached from player pods and the Gamemaster.
+	NetworkPolicyLevel = level.Firewall
+
+	// BlackoutLevel is the level where hint pods can't be reached at all,
+	// hints are only found in Kubernetes Events.
+	BlackoutLevel = level.Blackout
+
+	// HintNetworkPolicyName names the NetworkPolicy guarding hint pods.
+	HintNetworkPolicyName = rbac.HintNetworkPolicy
+
+	// LabelRole marks pods allowed to reach hint pods from level NetworkPolicyLevel.
+	LabelRole = "podsweeper.io/role"
+
+	// RolePlayer is the LabelRole value of player pods.
+	RolePlayer = "player"
+
+	// AnnotationLevel records the level an object was applied for.
+	AnnotationLevel = "podsweeper.io/level"
+
+	// fieldManager owns the fields the Gamemaster applies.
+	fieldManager = "podsweeper-gamemaster"
+
+	// cheatSheetKey is the key of the cheat ConfigMap leaking the mines.
+	cheatSheetKey = "mines"
+)
+
+// LevelManager applies the security obstacles of the level policy of the
+// game to the game namespace, see package level. It manages the
+// NetworkPolicy guarding hint pods: from NetworkPolicyLevel only pods
+// labelled podsweeper.io/role=player and the Gamemaster may reach them, and
+// at BlackoutLevel nothing may. It also leaks the mines in the cheat
+// ConfigMap at level 0, next to the game state a game.ConfigMapStore may
+// keep there.
+type LevelManager struct {
+	client    client.Client
+	namespace string
+
+	mu sync.Mutex
+	// applied identifies the game and level last applied, so that moves
+	// don't reapply the same policies.
+	applied string
+}
+
+// NewLevelManager creates a LevelManager for the game namespace.
+func NewLevelManager(c client.Client, namespace string) *LevelManager {
+	return &LevelManager{client: c, namespace: namespace}
+}
+
+// Apply converges the namespace to the level of the game. It's cheap to call
+// on every move: nothing is sent until the game or its level changes.
+func (m *LevelManager) Apply(ctx context.Context, state *game.GameState) error {
+	if m == nil || state == nil {
+		return nil
+	}
+	// Custom levels may guard hints harder than their level, or as the
+	// game goes on
+	policy, err := level.ForState(state)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "falling back to the game level for obstacles")
+	}
+	// Shifting mines change the cheat sheet
+	key := fmt.Sprintf("%s/%d/%d", state.ID(), policy.Level(), state.Shifts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.applied == key {
+		return nil
+	}
+
+	hintPolicy := hintNetworkPolicy(m.namespace, policy)
+	if hintPolicy == nil {
+		np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: HintNetworkPolicyName, Namespace: m.namespace}}
+		if err := client.IgnoreNotFound(m.client.Delete(ctx, np)); err != nil {
+			return fmt.Errorf("failed to delete network policy: %w", err)
+		}
+	} else if err := m.client.Apply(ctx, hintPolicy, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply network policy: %w", err)
+	}
+
+	cheatSheet := CheatSheet(m.namespace, state, policy)
+	if cheatSheet == nil {
+		if err := m.removeCheatSheet(ctx); err != nil {
+			return err
+		}
+	} else if err := m.client.Apply(ctx, cheatSheet, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply cheat sheet: %w", err)
+	}
+
+	log.FromContext(ctx).Info("applied level obstacles", "level", policy.Level(), "name", policy.Name(),
+		"hintAccess", policy.HintAccess(), "leak", policy.Leak())
+	m.applied = key
+	return nil
+}
+
+// removeCheatSheet deletes the cheat ConfigMap, or only its mines when a
+// game.ConfigMapStore keeps the game state in it.
+func (m *LevelManager) removeCheatSheet(ctx context.Context) error {
+	cm := &corev1.ConfigMap{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: game.CheatConfigMapName, Namespace: m.namespace}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get cheat sheet: %w", err)
+	}
+	if _, ok := cm.Data[game.StateKey]; !ok {
+		if err := client.IgnoreNotFound(m.client.Delete(ctx, cm)); err != nil {
+			return fmt.Errorf("failed to delete cheat sheet: %w", err)
+		}
+		return nil
+	}
+	if _, ok := cm.Data[cheatSheetKey]; !ok {
+		return nil
+	}
+	delete(cm.Data, cheatSheetKey)
+	delete(cm.Annotations, AnnotationLevel)
+	if err := m.client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to remove cheat sheet: %w", err)
+	}
+	return nil
+}
+
+// HintNetworkPolicy returns the NetworkPolicy guarding hint pods at a level,
+// or nil below NetworkPolicyLevel. Ports are not restricted since they are
+// randomized at higher levels.
+func HintNetworkPolicy(namespace string, lvl int) *networkingv1ac.NetworkPolicyApplyConfiguration {
+	return hintNetworkPolicy(namespace, level.For(lvl))
+}
+
+// hintNetworkPolicy returns the NetworkPolicy enforcing the hint access of
+// policy, or nil when hints are open.
+func hintNetworkPolicy(namespace string, policy level.Policy) *networkingv1ac.NetworkPolicyApplyConfiguration {
+	access := policy.HintAccess()
+	if access == level.HintsOpen {
+		return nil
+	}
+
+	spec := networkingv1ac.NetworkPolicySpec().
+		WithPodSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{
+			LabelApp:       "podsweeper",
+			LabelComponent: "hint",
+		})).
+		WithPolicyTypes(networkingv1.PolicyTypeIngress)
+	if access != level.HintsBlackout {
+		spec = spec.WithIngress(networkingv1ac.NetworkPolicyIngressRule().WithFrom(
+			networkingv1ac.NetworkPolicyPeer().WithPodSelector(metav1ac.LabelSelector().
+				WithMatchLabels(map[string]string{LabelRole: RolePlayer})),
+			networkingv1ac.NetworkPolicyPeer().WithPodSelector(metav1ac.LabelSelector().
+				WithMatchLabels(map[string]string{LabelApp: "podsweeper", LabelComponent: "gamemaster"})),
+		))
+	}
+
+	return networkingv1ac.NetworkPolicy(HintNetworkPolicyName, namespace).
+		WithLabels(map[string]string{LabelApp: "podsweeper"}).
+		WithAnnotations(map[string]string{AnnotationLevel: strconv.Itoa(policy.Level())}).
+		WithSpec(spec)
+}
+
+// CheatSheet returns the cheat ConfigMap leaking the mines of state when
+// policy leaks them there, nil otherwise. Its "mines" key shows the board
+// one row per line, "*" for mines and "." for safe cells.
+func CheatSheet(namespace string, state *game.GameState, policy level.Policy) *corev1ac.ConfigMapApplyConfiguration {
+	if policy.Leak() != level.LeakConfigMap {
+		return nil
+	}
+	var b strings.Builder
+	w, h := state.Dimensions()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if state.IsMine(x, y) {
+				b.WriteByte('*')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return corev1ac.ConfigMap(game.CheatConfigMapName, namespace).
+		WithLabels(map[string]string{LabelApp: "podsweeper"}).
+		WithAnnotations(map[string]string{AnnotationLevel: strconv.Itoa(policy.Level())}).
+		WithData(map[string]string{cheatSheetKey: b.String()})
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func getHintNetworkPolicy(t *testing.T, c client.Client) *networkingv1.NetworkPolicy {
+	t.Helper()
+	np := &networkingv1.NetworkPolicy{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: HintNetworkPolicyName, Namespace: testNamespace}, np)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("failed to get network policy: %v", err)
+	}
+	return np
+}
+
+// newApplyClient returns a fake client supporting server-side apply of
+// NetworkPolicies, which the default client-go type converter rejects.
+func newApplyClient() client.Client {
+	return fake.NewClientBuilder().WithScheme(newTestScheme()).
+		WithTypeConverters(managedfields.NewDeducedTypeConverter()).Build()
+}
+
+func TestHintNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		level     int
+		wantNil   bool
+		wantPeers int
+	}{
+		{level: 0, wantNil: true},
+		{level: NetworkPolicyLevel - 1, wantNil: true},
+		{level: NetworkPolicyLevel, wantPeers: 2},
+		{level: 8, wantPeers: 2},
+		{level: BlackoutLevel, wantPeers: 0},
+	}
+
+	for _, tt := range tests {
+		policy := HintNetworkPolicy(testNamespace, tt.level)
+		if (policy == nil) != tt.wantNil {
+			t.Errorf("level %d: expected nil policy %v, got %+v", tt.level, tt.wantNil, policy)
+			continue
+		}
+		if policy == nil {
+			continue
+		}
+		if got := policy.Spec.PodSelector.MatchLabels[LabelComponent]; got != "hint" {
+			t.Errorf("level %d: expected the policy to select hint pods, got %q", tt.level, got)
+		}
+		peers := 0
+		for _, rule := range policy.Spec.Ingress {
+			peers += len(rule.From)
+		}
+		if peers != tt.wantPeers {
+			t.Errorf("level %d: expected %d allowed peers, got %d", tt.level, tt.wantPeers, peers)
+		}
+	}
+}
+
+func TestLevelManager_Apply(t *testing.T) {
+	ctx := context.Background()
+	c := newApplyClient()
+	m := NewLevelManager(c, testNamespace)
+
+	state := createTestGameState(3)
+	state.Level = NetworkPolicyLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	np := getHintNetworkPolicy(t, c)
+	if np == nil {
+		t.Fatal("expected the hint network policy to be created")
+	}
+	if len(np.Spec.Ingress) != 1 || np.Spec.Ingress[0].From[0].PodSelector.MatchLabels[LabelRole] != RolePlayer {
+		t.Errorf("expected ingress from player pods only, got %+v", np.Spec.Ingress)
+	}
+
+	state.Level = BlackoutLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if np := getHintNetworkPolicy(t, c); np == nil || len(np.Spec.Ingress) != 0 {
+		t.Errorf("expected all ingress to hint pods to be denied at blackout, got %+v", np)
+	}
+
+	state.Level = 1
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if np := getHintNetworkPolicy(t, c); np != nil {
+		t.Error("expected the network policy to be removed below the firewall level")
+	}
+}
+
+func TestLevelManager_CheatSheet(t *testing.T) {
+	ctx := context.Background()
+	c := newApplyClient()
+	m := NewLevelManager(c, testNamespace)
+	key := types.NamespacedName{Name: game.CheatConfigMapName, Namespace: testNamespace}
+
+	// Mine at (1,1)
+	state := createTestGameState(3)
+	state.Level = CheatSheetLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		t.Fatalf("expected the cheat sheet to be created: %v", err)
+	}
+	if want := "...\n.*.\n...\n"; cm.Data["mines"] != want {
+		t.Errorf("expected the mines %q, got %q", want, cm.Data["mines"])
+	}
+
+	state.Level = SecretLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := c.Get(ctx, key, cm); !errors.IsNotFound(err) {
+		t.Errorf("expected the cheat sheet to be removed past level 0, got %v", err)
+	}
+}
+
+func TestLevelManager_CheatSheetKeepsStoredGame(t *testing.T) {
+	ctx := context.Background()
+	c := newApplyClient()
+	m := NewLevelManager(c, testNamespace)
+	store := game.NewConfigMapStore(c, testNamespace)
+	key := types.NamespacedName{Name: game.CheatConfigMapName, Namespace: testNamespace}
+
+	state := createTestGameState(3)
+	state.Level = CheatSheetLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := store.Save(ctx, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Only the mines are removed past level 0
+	state.Level = SecretLevel
+	if err := m.Apply(ctx, state); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		t.Fatalf("expected the ConfigMap of the store to be kept: %v", err)
+	}
+	if _, ok := cm.Data["mines"]; ok {
+		t.Error("expected the mines to be removed past level 0")
+	}
+	if loaded, err := store.Load(ctx); err != nil || loaded == nil || loaded.ID() != state.ID() {
+		t.Errorf("expected the stored game to be kept, got %v: %v", loaded, err)
+	}
+}
+
+func TestLevelManager_NilIsNoop(t *testing.T) {
+	var m *LevelManager
+	if err := m.Apply(context.Background(), createTestGameState(3)); err != nil {
+		t.Errorf("expected nil manager to do nothing, got %v", err)
+	}
+}
+
+func TestGameController_AppliesLevelBeforeMove(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.Level = NetworkPolicyLevel
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	c := newApplyClient()
+
+	controller := NewGameController(c, GameControllerConfig{Namespace: testNamespace, Store: store, NetworkPolicies: true})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if getHintNetworkPolicy(t, c) == nil {
+		t.Error("expected hint pods to be guarded once a level 5 game is played")
+	}
+}
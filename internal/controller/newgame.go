@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// AnnotationNewGame on the game namespace starts a new game of the
+// difficulty it holds, such as:
+//
+//	kubectl annotate ns podsweeper-game podsweeper.io/new-game=expert
+const AnnotationNewGame = "podsweeper.io/new-game"
+
+// NewGameReconciler starts a new game when the game namespace is annotated
+// with AnnotationNewGame, so games can be started from inside the cluster
+// with kubectl alone. The game it replaces, finished or not, is archived
+// first, and the new game is saved Pending for the BoardSpawner to clear
+// the previous board and spawn its own. The annotation is removed first,
+// also when its difficulty is unknown, so each request starts one game at
+// most: a request that fails must be annotated again.
+type NewGameReconciler struct {
+	client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Archive keeps the replaced games. Nil discards them.
+	Archive *results.Archive
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Presets resolves difficulties, custom and level presets included.
+	Presets *grid.PresetRegistry
+
+	// Profile sizes the boards of the games.
+	Profile spawner.Profile
+}
+
+// Reconcile starts the game the game namespace asks for, if any.
+func (r *NewGameReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, ns); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	difficulty, ok := ns.Annotations[AnnotationNewGame]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	// Cleared before the game is replaced, so a failed patch retried never
+	// replaces the game it started. The lock makes a stale namespace from
+	// the cache conflict instead of starting the game twice.
+	patch := client.MergeFromWithOptions(ns.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	ns.Annotations = maps.Clone(ns.Annotations)
+	delete(ns.Annotations, AnnotationNewGame)
+	if err := r.Patch(ctx, ns, patch); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	config, known := r.Presets.Get(grid.DifficultyPreset(difficulty))
+	if !known {
+		logger.Error(fmt.Errorf("unknown difficulty %q", difficulty), "ignoring new game request",
+			"presets", r.Presets.Names())
+		return ctrl.Result{}, nil
+	}
+	state, err := replaceGame(ctx, r.Store, r.Archive, r.Namespace, r.Profile.Board(config))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to start a new %s game, annotate the namespace again: %w", difficulty, err)
+	}
+	logger.Info("started a new game", "difficulty", difficulty, "level", state.Level,
+		"cells", state.TotalCells(), "mines", state.MineCount)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Its cache must
+// be restricted to the game namespace, see GameNamespaceCacheOptions.
+func (r *NewGameReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("newgame").
+		For(&corev1.Namespace{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, ok := object.GetAnnotations()[AnnotationNewGame]
+			return object.GetName() == r.Namespace && ok
+		})).
+		Complete(r)
+}
+
+// GameNamespaceCacheOptions restricts the cached Namespaces to the game
+// namespace, so the Gamemaster can watch it with a ClusterRole naming it
+// instead of one reading every namespace.
+func GameNamespaceCacheOptions(opts cache.Options, namespace string) cache.Options {
+	if opts.ByObject == nil {
+		opts.ByObject = make(map[client.Object]cache.ByObject)
+	}
+	opts.ByObject[&corev1.Namespace{}] = cache.ByObject{
+		Field: fields.OneTermEqualSelector("metadata.name", namespace),
+	}
+	return opts
+}
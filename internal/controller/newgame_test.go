@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+func newGameNamespace(difficulty string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        testNamespace,
+		Annotations: map[string]string{AnnotationNewGame: difficulty, "team": "blue"},
+	}}
+}
+
+func reconcileNewGame(t *testing.T, r *NewGameReconciler) *corev1.Namespace {
+	t.Helper()
+	key := types.NamespacedName{Name: testNamespace}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	ns := &corev1.Namespace{}
+	if err := r.Get(context.Background(), key, ns); err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
+func TestNewGameReconciler(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newGameNamespace("easy")).Build()
+	store := game.NewMemoryStore()
+	previous := createTestGameState(3)
+	_ = store.Save(ctx, previous)
+
+	r := &NewGameReconciler{
+		Client:    c,
+		Store:     store,
+		Archive:   &results.Archive{Client: c, Namespace: testNamespace},
+		Namespace: testNamespace,
+		Presets:   grid.NewPresetRegistry(),
+	}
+	ns := reconcileNewGame(t, r)
+
+	if _, ok := ns.Annotations[AnnotationNewGame]; ok || ns.Annotations["team"] != "blue" {
+		t.Errorf("expected only the new game annotation to be removed, got %v", ns.Annotations)
+	}
+	archived, err := r.Archive.Results(ctx)
+	if err != nil || len(archived) != 1 || archived[0].GameID != previous.ID() {
+		t.Fatalf("expected the previous game to be archived, got %+v: %v", archived, err)
+	}
+	state, _ := store.Load(ctx)
+	if state.ID() == previous.ID() || state.CurrentPhase() != game.PhasePending {
+		t.Fatalf("expected a new game waiting for the board spawner, got %s in phase %s", state.ID(), state.CurrentPhase())
+	}
+	easy := grid.GetDifficultyConfig(grid.DifficultyEasy)
+	if w, h := easy.Dimensions(); state.Width != w || state.Height != h {
+		t.Errorf("expected an easy %dx%d board, got %dx%d", w, h, state.Width, state.Height)
+	}
+
+	// Without the annotation, nothing happens
+	reconcileNewGame(t, r)
+	if again, _ := store.Load(ctx); again.ID() != state.ID() {
+		t.Error("expected no new game without the annotation")
+	}
+}
+
+func TestNewGameReconciler_LevelPreset(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newGameNamespace("vault")).Build()
+	presets := grid.NewPresetRegistry()
+	presets.Set("vault", grid.Config{Width: 12, Height: 6, MineDensity: 0.2, MinMineCount: 1, Level: 7})
+	store := game.NewMemoryStore()
+
+	reconcileNewGame(t, &NewGameReconciler{Client: c, Store: store, Namespace: testNamespace, Presets: presets})
+
+	state, _ := store.Load(ctx)
+	if state == nil || state.Width != 12 || state.Height != 6 || state.Level != 7 {
+		t.Fatalf("expected a 12x6 game at level 7, got %+v", state)
+	}
+}
+
+func TestNewGameReconciler_UnknownDifficulty(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newGameNamespace("impossible")).Build()
+	store := game.NewMemoryStore()
+	previous := createTestGameState(3)
+	_ = store.Save(ctx, previous)
+
+	ns := reconcileNewGame(t, &NewGameReconciler{Client: c, Store: store, Namespace: testNamespace,
+		Presets: grid.NewPresetRegistry()})
+
+	if _, ok := ns.Annotations[AnnotationNewGame]; ok {
+		t.Error("expected the unknown difficulty to be cleared")
+	}
+	if state, _ := store.Load(ctx); state.ID() != previous.ID() {
+		t.Error("expected the current game to be kept")
+	}
+}
+
+func TestNewGameReconciler_PatchFailure(t *testing.T) {
+	ctx := context.Background()
+	failPatch := true
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newGameNamespace("easy")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if failPatch {
+					return errors.New("apiserver unavailable")
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+	store := game.NewMemoryStore()
+	previous := createTestGameState(3)
+	_ = store.Save(ctx, previous)
+	r := &NewGameReconciler{
+		Client:    c,
+		Store:     store,
+		Archive:   &results.Archive{Client: c, Namespace: testNamespace},
+		Namespace: testNamespace,
+		Presets:   grid.NewPresetRegistry(),
+	}
+
+	key := types.NamespacedName{Name: testNamespace}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err == nil {
+		t.Fatal("expected the failed patch to be retried")
+	}
+	if state, _ := store.Load(ctx); state.ID() != previous.ID() {
+		t.Fatal("expected no new game before the request is cleared")
+	}
+
+	// The retry starts a single game
+	failPatch = false
+	reconcileNewGame(t, r)
+	state, _ := store.Load(ctx)
+	reconcileNewGame(t, r)
+	if again, _ := store.Load(ctx); state.ID() == previous.ID() || again.ID() != state.ID() {
+		t.Errorf("expected one new game, got %s then %s", state.ID(), again.ID())
+	}
+	if archived, _ := r.Archive.Results(ctx); len(archived) != 1 || archived[0].GameID != previous.ID() {
+		t.Errorf("expected only the previous game archived, got %+v", archived)
+	}
+}
+
+func TestGameNamespaceCacheOptions(t *testing.T) {
+	opts := GameNamespaceCacheOptions(NamespacedCacheOptions(testNamespace), testNamespace)
+	for obj, byObject := range opts.ByObject {
+		if _, ok := obj.(*corev1.Namespace); ok {
+			if byObject.Field.String() != "metadata.name="+testNamespace {
+				t.Errorf("expected Namespaces restricted to the game namespace, got %s", byObject.Field)
+			}
+			return
+		}
+	}
+	t.Errorf("expected a Namespace cache restriction, got %v", opts.ByObject)
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/scoring"
+)
+
+// DefaultNamespaceSummaryInterval is how often the summary of the game is
+// written on the game namespace.
+const DefaultNamespaceSummaryInterval = 10 * time.Second
+
+// The annotations of the game namespace summarizing the game.
+const (
+	// AnnotationStatus is the status of the game: playing, won, lost or
+	// resigned.
+	AnnotationStatus = "podsweeper.io/status"
+
+	// AnnotationProgress is the percentage of safe cells revealed, like "37%".
+	AnnotationProgress = "podsweeper.io/progress"
+
+	// AnnotationRemainingMines is the number of mines neither flagged nor
+	// defused.
+	AnnotationRemainingMines = "podsweeper.io/remaining-mines"
+
+	// AnnotationScore is the score of the game.
+	AnnotationScore = "podsweeper.io/score"
+
+	// AnnotationPlayTime is the play time of the game, like "2m41s".
+	AnnotationPlayTime = "podsweeper.io/play-time"
+
+	// AnnotationWebhookDegraded explains why the attribution webhook is
+	// degraded, only while it is.
+	AnnotationWebhookDegraded = "podsweeper.io/webhook-degraded"
+)
+
+// summaryAnnotations lists the annotations written by NamespaceSummary.
+var summaryAnnotations = append([]string{
+	AnnotationStatus, AnnotationProgress, AnnotationRemainingMines, AnnotationScore, AnnotationPlayTime,
+	AnnotationWebhookDegraded,
+}, scoring.AnnotationKeys...)
+
+// NamespaceSummary keeps a summary of the game in annotations of the game
+// namespace, so `kubectl describe ns` shows the score without any other
+// tool. The annotations are updated every interval and removed when there
+// is no game. While the attribution webhook is degraded, the namespace also
+// tells why, game or not. It runs as a manager Runnable, only on the leader.
+type NamespaceSummary struct {
+	// Client annotates the namespace. The game namespace is cluster-scoped,
+	// so an uncached client avoids watching every namespace.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Interval between updates. Defaults to DefaultNamespaceSummaryInterval.
+	Interval time.Duration
+
+	// Webhook reports the outages of the attribution webhook, if any.
+	Webhook *WebhookHealth
+}
+
+// Start implements manager.Runnable.
+func (s *NamespaceSummary) Start(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultNamespaceSummaryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("namespace-summary")
+	for {
+		if err := s.Update(ctx); err != nil {
+			logger.Error(err, "failed to update namespace summary")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *NamespaceSummary) NeedLeaderElection() bool {
+	return true
+}
+
+// Update writes the summary of the current game on the game namespace.
+func (s *NamespaceSummary) Update(ctx context.Context) error {
+	state, err := s.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := s.Client.Get(ctx, client.ObjectKey{Name: s.Namespace}, ns); err != nil {
+		return fmt.Errorf("failed to get game namespace: %w", err)
+	}
+
+	patch := client.MergeFrom(ns.DeepCopy())
+	annotations := maps.Clone(ns.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for _, key := range summaryAnnotations {
+		delete(annotations, key)
+	}
+	maps.Copy(annotations, SummaryAnnotations(state))
+	for _, condition := range s.Webhook.Conditions() {
+		if condition.Type == ConditionWebhookDegraded && condition.Status == metav1.ConditionTrue {
+			annotations[AnnotationWebhookDegraded] = condition.Message
+		}
+	}
+	if maps.Equal(annotations, ns.Annotations) {
+		return nil
+	}
+
+	ns.Annotations = annotations
+	if err := s.Client.Patch(ctx, ns, patch); err != nil {
+		return fmt.Errorf("failed to annotate game namespace: %w", err)
+	}
+	return nil
+}
+
+// SummaryAnnotations returns the annotations summarizing state, none when
+// there is no game.
+func SummaryAnnotations(state *game.GameState) map[string]string {
+	if state == nil {
+		return nil
+	}
+	stats := state.Stats()
+	status := string(stats.Status)
+	if stats.Resigned {
+		status = "resigned"
+	}
+	annotations := map[string]string{
+		AnnotationStatus:         status,
+		AnnotationProgress:       fmt.Sprintf("%.0f%%", stats.Progress),
+		AnnotationRemainingMines: strconv.Itoa(stats.RemainingMines),
+		AnnotationScore:          strconv.Itoa(stats.Score),
+		AnnotationPlayTime:       stats.Elapsed().Round(time.Second).String(),
+	}
+	// The score card of finished games
+	maps.Copy(annotations, scoring.Annotations(state.ScoreCard))
+	return annotations
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestNamespaceSummary_Update(t *testing.T) {
+	ctx := context.Background()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        testNamespace,
+		Annotations: map[string]string{"owner": "workshop"},
+	}}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(ns).Build()
+	store := game.NewMemoryStore()
+	summary := &NamespaceSummary{Client: c, Store: store, Namespace: testNamespace}
+
+	state := createTestGameState(3)
+	_ = store.Save(ctx, state)
+	if err := summary.Update(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKey{Name: testNamespace}, ns)
+	if ns.Annotations[AnnotationStatus] != "playing" || ns.Annotations[AnnotationProgress] != "0%" ||
+		ns.Annotations[AnnotationRemainingMines] != strconv.Itoa(state.MineCount) {
+		t.Errorf("expected the summary of %s, got %v", state.Stats(), ns.Annotations)
+	}
+	if ns.Annotations["owner"] != "workshop" {
+		t.Errorf("expected other annotations to be kept, got %v", ns.Annotations)
+	}
+
+	state.Resign("alice", time.Now())
+	_ = store.Save(ctx, state)
+	if err := summary.Update(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKey{Name: testNamespace}, ns)
+	if got := ns.Annotations[AnnotationStatus]; got != "resigned" {
+		t.Errorf("expected resigned, got %q", got)
+	}
+
+	// The summary goes away with the game
+	_ = store.Delete(ctx)
+	if err := summary.Update(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKey{Name: testNamespace}, ns)
+	if len(ns.Annotations) != 1 {
+		t.Errorf("expected only the other annotations left, got %v", ns.Annotations)
+	}
+}
+
+func TestSummaryAnnotations(t *testing.T) {
+	if SummaryAnnotations(nil) != nil {
+		t.Error("expected no summary without a game")
+	}
+	state := createTestGameState(3)
+	state.SetWon()
+	got := SummaryAnnotations(state)
+	if got[AnnotationStatus] != "won" || got[AnnotationPlayTime] == "" {
+		t.Errorf("expected the summary of a won game, got %v", got)
+	}
+}
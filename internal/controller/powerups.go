@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/powerup"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// reconcilePowerUp spends a charge on the power-up named by the annotation
+// of a cell pod, targeting its cell. The annotation is removed first, so
+// that a power-up is used at most once per annotation.
+func (r *GameController) reconcilePowerUp(ctx context.Context, pod *corev1.Pod, coords game.Coordinate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	name := pod.Annotations[powerup.AnnotationPowerUp]
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	delete(pod.Annotations, powerup.AnnotationPowerUp)
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to clear the power-up of %s: %w", pod.Name, err)
+	}
+
+	state, err := r.Store.Load(ctx)
+	if game.IsCorruptState(err) {
+		logger.Error(err, "refusing to play corrupted game state")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || state.Status != game.StatusPlaying {
+		return ctrl.Result{}, nil
+	}
+
+	p, err := powerup.Get(name)
+	if err != nil {
+		r.refusePowerUp(ctx, pod, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if state.PowerUps.Charges() == 0 {
+		r.refusePowerUp(ctx, pod, fmt.Sprintf("no power-up charge left, earn one every %d safe clicks in a row", game.StreakPerCharge))
+		return ctrl.Result{}, nil
+	}
+	if state.IsRevealed(coords.X, coords.Y) || state.IsDefused(coords.X, coords.Y) {
+		r.refusePowerUp(ctx, pod, "the targeted cell is no longer hidden")
+		return ctrl.Result{}, nil
+	}
+	rng := r.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	effect, err := p.Effect(state, coords, rng)
+	if err == nil && effect.IsEmpty() {
+		err = fmt.Errorf("%s would have no effect", name)
+	}
+	if err != nil {
+		r.refusePowerUp(ctx, pod, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	if effect.Arm {
+		if _, ok := p.(powerup.Armable); !ok {
+			r.refusePowerUp(ctx, pod, fmt.Sprintf("%s cannot be armed", name))
+			return ctrl.Result{}, nil
+		}
+		state.PowerUps.Armed = name
+	}
+
+	state.SpendCharge()
+	logger.Info("power-up used", "powerUp", name, "target", coords, "reveal", effect.Reveal,
+		"neutralize", effect.Neutralize, "armed", effect.Arm, "chargesLeft", state.PowerUps.Charges())
+	if r.Recorder != nil {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, "PowerUp", "Use", "%s %s, %d charge(s) left",
+			name, p.Description(), state.PowerUps.Charges())
+	}
+	return r.applyEffect(ctx, state, effect)
+}
+
+// applyEffect plays the effect of a power-up and saves the game.
+func (r *GameController) applyEffect(ctx context.Context, state *game.GameState, effect powerup.Effect) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	for _, c := range effect.Neutralize {
+		if !state.NeutralizeMine(c.X, c.Y) {
+			continue
+		}
+		// The state is saved before the deletion is reconciled, which is
+		// then ignored as the mine is defused
+		if err := r.Handlers.deletePod(ctx, c); err != nil {
+			logger.Error(err, "failed to delete neutralized pod", "coords", c)
+		}
+		if err := r.Handlers.spawnDefusedPod(ctx, c); err != nil && !errors.IsAlreadyExists(err) {
+			logger.Error(err, "failed to spawn defused pod", "coords", c)
+		}
+	}
+
+	for _, c := range effect.Flag {
+		if !state.IsMine(c.X, c.Y) || !state.SetFlag(c.X, c.Y, true) {
+			continue
+		}
+		if err := r.spareMine(ctx, state, c); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Handlers.notify(ctx, HookFlag, state, &c, 0)
+	}
+
+	var reveal []game.Coordinate
+	for _, c := range effect.Reveal {
+		if !state.IsMine(c.X, c.Y) && !state.IsRevealed(c.X, c.Y) {
+			reveal = append(reveal, c)
+		}
+	}
+	if len(reveal) == 0 {
+		if err := r.Store.Save(ctx, state); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// The handlers save the state after each reveal
+	for _, c := range reveal {
+		if state.IsRevealed(c.X, c.Y) {
+			// Revealed by the propagation of a previous one
+			continue
+		}
+		if !state.Propagates(c.X, c.Y) {
+			if result, err := r.Handlers.HandleHintCell(ctx, state, c, state.AdjacentMines(c.X, c.Y)); err != nil {
+				return result, err
+			}
+			if err := r.Handlers.deletePod(ctx, c); err != nil {
+				logger.Error(err, "failed to delete revealed pod", "coords", c)
+			}
+		} else if result, err := r.Handlers.HandleEmptyCell(ctx, state, c); err != nil {
+			return result, err
+		}
+		if state.Status != game.StatusPlaying {
+			break
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// triggerArmed offers the move at coords to the armed power-up. When it
+// triggers, its effect is applied instead of the move and the power-up is
+// disarmed.
+func (r *GameController) triggerArmed(ctx context.Context, state *game.GameState, coords game.Coordinate) (bool, ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	p, err := powerup.Get(state.PowerUps.Armed)
+	armable, ok := p.(powerup.Armable)
+	if err != nil || !ok {
+		// No longer registered: the move disarms it
+		logger.Info("disarming unknown power-up", "powerUp", state.PowerUps.Armed)
+		state.PowerUps.Armed = ""
+		return false, ctrl.Result{}, nil
+	}
+	deleted, err := r.deletedCells(ctx, state)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+	effect, ok := armable.Trigger(state, coords, deleted)
+	if !ok {
+		return false, ctrl.Result{}, nil
+	}
+
+	state.PowerUps.Armed = ""
+	logger.Info("power-up triggered", "powerUp", p.Name(), "move", coords, "reveal", effect.Reveal, "flag", effect.Flag)
+	result, err := r.applyEffect(ctx, state, effect)
+	return true, result, err
+}
+
+// deletedCells returns the hidden cells whose pods are gone or terminating.
+func (r *GameController) deletedCells(ctx context.Context, state *game.GameState) ([]game.Coordinate, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(r.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	live := map[game.Coordinate]bool{}
+	for _, pod := range pods.Items {
+		if c, ok := ParsePodName(pod.Name); ok && pod.DeletionTimestamp.IsZero() {
+			live[c] = true
+		}
+	}
+
+	var deleted []game.Coordinate
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			if !live[c] && !state.IsRevealed(x, y) && !state.IsDefused(x, y) {
+				deleted = append(deleted, c)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// spareMine makes sure deleting the pod of a mine flagged by a power-up
+// doesn't blow it up: a deleted pod is restored right away, a terminating
+// one once it's gone.
+func (r *GameController) spareMine(ctx context.Context, state *game.GameState, c game.Coordinate) error {
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: c.PodName()}, pod)
+	switch {
+	case errors.IsNotFound(err):
+		return r.createCellPod(ctx, state, c)
+	case err != nil:
+		return fmt.Errorf("failed to get pod of %v: %w", c, err)
+	case !pod.DeletionTimestamp.IsZero():
+		if !state.PowerUps.IsSpared(c.X, c.Y) {
+			state.PowerUps.Spared = append(state.PowerUps.Spared, c)
+		}
+	}
+	return nil
+}
+
+// restoreSpared restores the pod of a spared mine once its deletion went
+// through, and saves the game.
+func (r *GameController) restoreSpared(ctx context.Context, state *game.GameState, c game.Coordinate) (ctrl.Result, error) {
+	if err := r.createCellPod(ctx, state, c); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Store.Save(ctx, state); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to save game state: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// createCellPod creates the pod of the hidden cell c.
+func (r *GameController) createCellPod(ctx context.Context, state *game.GameState, c game.Coordinate) error {
+	if err := spawnCellPod(ctx, r.Client, r.Spawner, state, c); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to restore pod of %v: %w", c, err)
+	}
+	return nil
+}
+
+// spawnCellPod creates the pod of the hidden cell c with s, annotated if a
+// player flagged the cell, and records its UID in state. A pod already there is
+// kept, with its UID unknown.
+func spawnCellPod(ctx context.Context, cl client.Client, s *spawner.GridSpawner, state *game.GameState, c game.Coordinate) error {
+	pod := s.BuildCellPod(c, state.ID())
+	if state.IsPlayerFlagged(c.X, c.Y) {
+		pod.Annotations[AnnotationFlag] = "true"
+	}
+	if err := cl.Create(ctx, pod); err != nil {
+		if errors.IsAlreadyExists(err) {
+			state.SetPodUID(c.X, c.Y, "")
+		}
+		return err
+	}
+	state.SetPodUID(c.X, c.Y, string(pod.UID))
+	return nil
+}
+
+// refusePowerUp tells the player why a power-up was not used. The charge is
+// kept.
+func (r *GameController) refusePowerUp(ctx context.Context, pod *corev1.Pod, reason string) {
+	log.FromContext(ctx).Info("power-up refused", "pod", pod.Name, "reason", reason)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, "PowerUpRefused", "Use", "%s", reason)
+	}
+}
@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/powerup"
+)
+
+func annotatedPod(name, powerUp string) *corev1.Pod {
+	pod := createTestPod(name, testNamespace)
+	pod.Annotations = map[string]string{powerup.AnnotationPowerUp: powerUp}
+	return pod
+}
+
+func reconcilePod(t *testing.T, controller *GameController, name string) {
+	t.Helper()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}}
+	if _, err := controller.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+}
+
+func TestGameController_PowerUps(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        string
+		powerUp    string
+		charges    int
+		check      func(t *testing.T, state *game.GameState, c client.Client)
+		wantSpent  int
+		wantReason string
+	}{
+		{
+			name: "neutralize a mine", pod: "pod-1-1", powerUp: powerup.NameNeutralize, charges: 1, wantSpent: 1,
+			check: func(t *testing.T, state *game.GameState, c client.Client) {
+				if !state.IsDefused(1, 1) || state.Status != game.StatusPlaying {
+					t.Errorf("expected the mine defused and the game going on, got %s", state.Status)
+				}
+				if err := c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "defused-1-1"}, &corev1.Pod{}); err != nil {
+					t.Errorf("expected a defused marker pod: %v", err)
+				}
+			},
+		},
+		{
+			name: "neutralize a safe cell", pod: "pod-0-0", powerUp: powerup.NameNeutralize, charges: 1, wantSpent: 1,
+			check: func(t *testing.T, state *game.GameState, c client.Client) {
+				if !state.IsRevealed(0, 0) {
+					t.Error("expected the safe cell revealed")
+				}
+				if err := c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "hint-0-0"}, &corev1.Pod{}); err != nil {
+					t.Errorf("expected a hint pod: %v", err)
+				}
+			},
+		},
+		{
+			name: "reveal a safe cell", pod: "pod-1-1", powerUp: powerup.NameRevealSafe, charges: 2, wantSpent: 1,
+			check: func(t *testing.T, state *game.GameState, c client.Client) {
+				if state.Stats().RevealedCells != 1 || state.IsRevealed(1, 1) {
+					t.Errorf("expected one safe cell revealed, got %d", state.Stats().RevealedCells)
+				}
+			},
+		},
+		{name: "no charge", pod: "pod-1-1", powerUp: powerup.NameNeutralize, wantReason: "no power-up charge left"},
+		{name: "unknown power-up", pod: "pod-1-1", powerUp: "nuke", charges: 1, wantReason: "unknown power-up"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			// Mine at (1,1)
+			state := createTestGameState(3)
+			state.PowerUps.Earned = tt.charges
+			controller, c, recorder := newTutorialController(t, state, annotatedPod(tt.pod, tt.powerUp))
+			controller.Rand = rand.New(rand.NewPCG(1, 2))
+
+			reconcilePod(t, controller, tt.pod)
+
+			pod := &corev1.Pod{}
+			err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: tt.pod}, pod)
+			if err == nil && pod.Annotations[powerup.AnnotationPowerUp] != "" {
+				t.Error("expected the power-up annotation to be removed")
+			}
+			saved, _ := controller.Store.Load(ctx)
+			if saved.PowerUps.Spent != tt.wantSpent {
+				t.Errorf("expected %d charge(s) spent, got %d", tt.wantSpent, saved.PowerUps.Spent)
+			}
+			if tt.check != nil {
+				tt.check(t, saved, c)
+			}
+
+			events := strings.Join(drainEvents(recorder), "\n")
+			if tt.wantReason != "" && !strings.Contains(events, tt.wantReason) {
+				t.Errorf("expected an event telling %q, got %q", tt.wantReason, events)
+			}
+
+			// The annotation is gone: reconciling again does nothing more
+			reconcilePod(t, controller, tt.pod)
+			if again, _ := controller.Store.Load(ctx); again.PowerUps.Spent != tt.wantSpent {
+				t.Errorf("expected the power-up used once, got %d charges spent", again.PowerUps.Spent)
+			}
+		})
+	}
+}
+
+func TestGameController_SafeStreakEarnsCharges(t *testing.T) {
+	ctx := context.Background()
+	// Mine at (1,1) of a 4x4 grid: the other cells are safe
+	state := createTestGameState(4)
+	state.PowerUps.Streak = game.StreakPerCharge - 1
+	controller, _, _ := newTutorialController(t, state)
+
+	reconcilePod(t, controller, "pod-0-0")
+	saved, _ := controller.Store.Load(ctx)
+	if saved.PowerUps.Charges() != 1 || saved.PowerUps.Streak != 0 {
+		t.Errorf("expected a charge earned, got %+v", saved.PowerUps)
+	}
+}
+
+// sweepGame arms a sweep on a 4x4 grid with a mine at (1,1), and returns
+// its controller with a pod for every cell.
+func sweepGame(t *testing.T) (*GameController, client.Client) {
+	t.Helper()
+	state := createTestGameState(4)
+	state.PowerUps.Earned = 1
+	var pods []client.Object
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			pods = append(pods, createTestPod(game.Coordinate{X: x, Y: y}.PodName(), testNamespace))
+		}
+	}
+	controller, c, _ := newTutorialController(t, state, pods...)
+
+	pod := &corev1.Pod{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "pod-3-3"}, pod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	pod.Annotations = map[string]string{powerup.AnnotationPowerUp: powerup.NameSweep}
+	if err := c.Update(context.Background(), pod); err != nil {
+		t.Fatalf("failed to annotate pod: %v", err)
+	}
+	reconcilePod(t, controller, "pod-3-3")
+	if saved, _ := controller.Store.Load(context.Background()); saved.PowerUps.Armed != powerup.NameSweep {
+		t.Fatalf("expected the sweep armed, got %+v", saved.PowerUps)
+	}
+	return controller, c
+}
+
+func deletePods(t *testing.T, c client.Client, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		pod := &corev1.Pod{}
+		pod.Name, pod.Namespace = name, testNamespace
+		if err := c.Delete(context.Background(), pod); err != nil {
+			t.Fatalf("failed to delete %s: %v", name, err)
+		}
+	}
+}
+
+func TestGameController_Sweep(t *testing.T) {
+	ctx := context.Background()
+	controller, c := sweepGame(t)
+
+	// kubectl delete pods -l podsweeper.io/x=1
+	deletePods(t, c, "pod-1-0", "pod-1-1", "pod-1-2", "pod-1-3")
+	for _, name := range []string{"pod-1-0", "pod-1-1", "pod-1-2", "pod-1-3"} {
+		reconcilePod(t, controller, name)
+	}
+
+	saved, _ := controller.Store.Load(ctx)
+	if saved.Status != game.StatusPlaying {
+		t.Fatalf("expected the game going on, got %s", saved.Status)
+	}
+	for _, y := range []int{0, 2, 3} {
+		if !saved.IsRevealed(1, y) {
+			t.Errorf("expected (1, %d) revealed", y)
+		}
+	}
+	if !saved.IsFlagged(1, 1) || saved.IsRevealed(1, 1) {
+		t.Error("expected the mine flagged and hidden")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, &corev1.Pod{}); err != nil {
+		t.Errorf("expected the mine pod restored: %v", err)
+	}
+	if saved.PowerUps.Armed != "" || saved.PowerUps.Spent != 1 {
+		t.Errorf("expected the sweep spent, got %+v", saved.PowerUps)
+	}
+}
+
+func TestGameController_SweepSparesTerminatingMines(t *testing.T) {
+	ctx := context.Background()
+	controller, c := sweepGame(t)
+
+	// The mine pod takes a while to terminate
+	mine := &corev1.Pod{}
+	_ = c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, mine)
+	mine.Finalizers = []string{"test/slow"}
+	if err := c.Update(ctx, mine); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+
+	// kubectl delete pods -l podsweeper.io/y=1
+	deletePods(t, c, "pod-0-1", "pod-1-1", "pod-2-1", "pod-3-1")
+	reconcilePod(t, controller, "pod-0-1")
+	saved, _ := controller.Store.Load(ctx)
+	if !saved.IsRevealed(3, 1) || !saved.PowerUps.IsSpared(1, 1) {
+		t.Fatalf("expected the row swept and the mine spared, got %+v", saved.PowerUps)
+	}
+
+	_ = c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, mine)
+	mine.Finalizers = nil
+	if err := c.Update(ctx, mine); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+	reconcilePod(t, controller, "pod-1-1")
+
+	saved, _ = controller.Store.Load(ctx)
+	if saved.Status != game.StatusPlaying || saved.PowerUps.IsSpared(1, 1) {
+		t.Errorf("expected the mine deletion undone, got %s and %+v", saved.Status, saved.PowerUps)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-1"}, &corev1.Pod{}); err != nil {
+		t.Errorf("expected the mine pod restored: %v", err)
+	}
+
+	// Deleting it again is a regular move
+	deletePods(t, c, "pod-1-1")
+	reconcilePod(t, controller, "pod-1-1")
+	if saved, _ = controller.Store.Load(ctx); saved.Status != game.StatusLost {
+		t.Errorf("expected the mine to blow up, got %s", saved.Status)
+	}
+}
+
+func TestGameController_SweepWaitsForALine(t *testing.T) {
+	ctx := context.Background()
+	controller, c := sweepGame(t)
+
+	deletePods(t, c, "pod-0-0")
+	reconcilePod(t, controller, "pod-0-0")
+
+	saved, _ := controller.Store.Load(ctx)
+	if !saved.IsRevealed(0, 0) || saved.Stats().RevealedCells != 1 {
+		t.Errorf("expected a regular move, got %d cells revealed", saved.Stats().RevealedCells)
+	}
+	if saved.PowerUps.Armed != powerup.NameSweep {
+		t.Errorf("expected the sweep still armed, got %+v", saved.PowerUps)
+	}
+}
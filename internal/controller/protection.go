@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AnnotationProtected marks a pod the Gamemaster must never touch,
+// regardless of the configured protection rules.
+const AnnotationProtected = "podsweeper.io/protected"
+
+// ProtectionRules describes pods in the game namespace that the controller
+// must ignore: they are never deleted during a wipe and their deletion is
+// never interpreted as a move. This lets the game share a namespace with
+// other tooling (sidecars, debug pods, monitoring agents...).
+type ProtectionRules struct {
+	// Selectors match pods by label. A pod matching any selector is protected.
+	Selectors []labels.Selector
+
+	// NamePatterns match pods by name. A pod matching any pattern is protected.
+	NamePatterns []*regexp.Regexp
+}
+
+// NewProtectionRules parses label selectors and name regular expressions
+// into a ProtectionRules value.
+func NewProtectionRules(selectors, namePatterns []string) (ProtectionRules, error) {
+	var rules ProtectionRules
+
+	for _, s := range selectors {
+		sel, err := labels.Parse(s)
+		if err != nil {
+			return ProtectionRules{}, fmt.Errorf("invalid protected label selector %q: %w", s, err)
+		}
+		rules.Selectors = append(rules.Selectors, sel)
+	}
+
+	for _, p := range namePatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return ProtectionRules{}, fmt.Errorf("invalid protected name pattern %q: %w", p, err)
+		}
+		rules.NamePatterns = append(rules.NamePatterns, re)
+	}
+
+	return rules, nil
+}
+
+// IsEmpty returns true if no protection rule is configured.
+func (p ProtectionRules) IsEmpty() bool {
+	return len(p.Selectors) == 0 && len(p.NamePatterns) == 0
+}
+
+// ProtectsName checks if a pod name matches one of the name patterns.
+// This is the only check available once a pod is gone from the API server.
+func (p ProtectionRules) ProtectsName(name string) bool {
+	for _, re := range p.NamePatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Protects checks if the given object is protected by name, label or annotation.
+func (p ProtectionRules) Protects(obj metav1.Object) bool {
+	if obj.GetAnnotations()[AnnotationProtected] == "true" {
+		return true
+	}
+	if p.ProtectsName(obj.GetName()) {
+		return true
+	}
+	podLabels := labels.Set(obj.GetLabels())
+	for _, sel := range p.Selectors {
+		if sel.Matches(podLabels) {
+			return true
+		}
+	}
+	return false
+}
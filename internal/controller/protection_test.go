@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestNewProtectionRules(t *testing.T) {
+	rules, err := NewProtectionRules([]string{"team=infra", "tier in (debug)"}, []string{"^pod-0-.*$"})
+	if err != nil {
+		t.Fatalf("NewProtectionRules returned error: %v", err)
+	}
+	if len(rules.Selectors) != 2 {
+		t.Errorf("expected 2 selectors, got %d", len(rules.Selectors))
+	}
+	if len(rules.NamePatterns) != 1 {
+		t.Errorf("expected 1 name pattern, got %d", len(rules.NamePatterns))
+	}
+	if rules.IsEmpty() {
+		t.Error("expected rules not to be empty")
+	}
+
+	if _, err := NewProtectionRules([]string{"!!invalid"}, nil); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+	if _, err := NewProtectionRules(nil, []string{"pod-("}); err == nil {
+		t.Error("expected error for invalid name pattern")
+	}
+
+	empty, _ := NewProtectionRules(nil, nil)
+	if !empty.IsEmpty() {
+		t.Error("expected empty rules")
+	}
+}
+
+func TestProtectionRules_Protects(t *testing.T) {
+	rules, _ := NewProtectionRules([]string{"team=infra"}, []string{"^pod-9-9$"})
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{"plain game pod", createTestPod("pod-1-1", testNamespace), false},
+		{"name pattern", createTestPod("pod-9-9", testNamespace), true},
+		{"label selector", func() *corev1.Pod {
+			p := createTestPod("pod-2-2", testNamespace)
+			p.Labels["team"] = "infra"
+			return p
+		}(), true},
+		{"annotation", func() *corev1.Pod {
+			p := createTestPod("pod-3-3", testNamespace)
+			p.Annotations = map[string]string{AnnotationProtected: "true"}
+			return p
+		}(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Protects(tt.pod); got != tt.want {
+				t.Errorf("Protects(%s) = %v, want %v", tt.pod.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameHandlers_WipeGamePodsSkipsProtected(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	gamePod := createTestPod("pod-0-0", testNamespace)
+	protectedPod := createTestPod("pod-1-1", testNamespace)
+	protectedPod.Labels["team"] = "infra"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(gamePod, protectedPod).
+		Build()
+
+	rules, _ := NewProtectionRules([]string{"team=infra"}, nil)
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     game.NewMemoryStore(),
+		Protected: rules,
+	})
+
+	if err := controller.Handlers.wipeGamePods(ctx); err != nil {
+		t.Fatalf("wipeGamePods returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}, &pod); err == nil {
+		t.Error("expected pod-0-0 to be deleted")
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "pod-1-1", Namespace: testNamespace}, &pod); err != nil {
+		t.Error("expected protected pod-1-1 to still exist")
+	}
+}
+
+func TestGameController_ReconcileIgnoresProtectedNames(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(8)
+	_ = store.Save(ctx, state)
+
+	rules, _ := NewProtectionRules(nil, []string{"^pod-3-5$"})
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+		Protected: rules,
+	})
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "pod-3-5",
+			Namespace: testNamespace,
+		},
+	}
+
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.IsRevealed(3, 5) {
+		t.Error("deletion of a protected pod must not be treated as a move")
+	}
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func TestGameController_ReconcileRectangularBoard(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+
+	// A wide board: 6 columns, 2 rows, with a mine in the top right corner
+	state := game.NewRectGameState(6, 2, 12345)
+	state.SetMine(5, 0)
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+
+	if _, err := spawner.NewGridSpawner(c, spawner.GridSpawnerConfig{Namespace: testNamespace}).SpawnGrid(ctx, state); err != nil {
+		t.Fatalf("SpawnGrid returned error: %v", err)
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods); err != nil {
+		t.Fatal(err)
+	}
+	cells := 0
+	for _, pod := range pods.Items {
+		if coords, ok := ParsePodName(pod.Name); ok && state.IsValidCoordinate(coords.X, coords.Y) {
+			cells++
+		}
+	}
+	if cells != 12 {
+		t.Fatalf("expected a pod per cell of the 6x2 board, got %d", cells)
+	}
+
+	controller := NewGameController(c, GameControllerConfig{Namespace: testNamespace, Store: store})
+	reconcile := func(name string) {
+		t.Helper()
+		pod := &corev1.Pod{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, pod); err == nil {
+			_ = c.Delete(ctx, pod)
+		}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: testNamespace}}
+		if _, err := controller.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile(%s) returned error: %v", name, err)
+		}
+	}
+
+	// A transposed pod is outside the board
+	reconcile("pod-1-5")
+	if loaded, _ := store.Load(ctx); loaded.Clicks != 0 || loaded.CurrentPhase() != game.PhaseReady {
+		t.Errorf("expected the pod outside the board to be ignored, got %d clicks in phase %s",
+			loaded.Clicks, loaded.CurrentPhase())
+	}
+
+	// Revealing an empty corner propagates along the rows up to the mine
+	reconcile("pod-0-1")
+	loaded, _ := store.Load(ctx)
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 2; y++ {
+			if !loaded.IsRevealed(x, y) {
+				t.Errorf("expected (%d,%d) to be revealed by propagation", x, y)
+			}
+		}
+	}
+	if loaded.IsRevealed(5, 1) || loaded.Status != game.StatusPlaying {
+		t.Errorf("expected (5,1) to stay hidden in a game still playing, got status %s", loaded.Status)
+	}
+	if hint := loaded.AdjacentMines(4, 1); hint != 1 {
+		t.Errorf("expected a hint of 1 next to the mine, got %d", hint)
+	}
+
+	// Revealing the last safe cell wins the game
+	reconcile("pod-5-1")
+	if loaded, _ := store.Load(ctx); loaded.Status != game.StatusWon {
+		t.Errorf("expected the game to be won, got %s", loaded.Status)
+	}
+}
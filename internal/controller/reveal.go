@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// maxRevealRequest bounds the size of reveal requests.
+const maxRevealRequest = 1024
+
+var (
+	// ErrNotACell is returned when revealing a pod that isn't a cell.
+	ErrNotACell = errors.New("not a cell")
+
+	// ErrNotTheCell is returned when a cell agent reveals another cell
+	// than its own.
+	ErrNotTheCell = errors.New("cells can only reveal themselves")
+)
+
+// RevealRequest is the move the cell agent of a cell asks for, with its
+// own coordinates.
+type RevealRequest struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ExecReveal is the move API of cell agents, for clusters where players
+// may exec into pods but not delete them: the reveal command of a cell
+// agent asks the Gamemaster to delete its pod, which is then played like
+// any other deletion. Requests are only trusted from the pod of the cell
+// they reveal, so that players can't reveal cells they didn't exec into.
+type ExecReveal struct {
+	Client    client.Client
+	Namespace string
+}
+
+// Reveal deletes the pod of cell c on behalf of the cell agent calling
+// from ip, which must be the IP of that pod.
+func (e *ExecReveal) Reveal(ctx context.Context, c game.Coordinate, ip string) error {
+	pod := &corev1.Pod{}
+	if err := e.Client.Get(ctx, client.ObjectKey{Namespace: e.Namespace, Name: c.PodName()}, pod); err != nil {
+		return fmt.Errorf("failed to get %s: %w", c.PodName(), err)
+	}
+	if pod.Labels[LabelComponent] != "cell" {
+		return fmt.Errorf("%s: %w", pod.Name, ErrNotACell)
+	}
+	if pod.Status.PodIP == "" || pod.Status.PodIP != ip {
+		return fmt.Errorf("%s from %s: %w", pod.Name, ip, ErrNotTheCell)
+	}
+	if err := e.Client.Delete(ctx, pod); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", pod.Name, err)
+	}
+	log.FromContext(ctx).Info("cell revealed by its agent", "coords", c)
+	return nil
+}
+
+// Handler serves Reveal on POST requests of cell agents.
+func (e *ExecReveal) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "reveal with POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var req RevealRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRevealRequest)).Decode(&req); err != nil {
+			http.Error(w, "invalid reveal request", http.StatusBadRequest)
+			return
+		}
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		c := game.Coordinate{X: req.X, Y: req.Y}
+		err = e.Reveal(r.Context(), c, ip)
+		switch {
+		case errors.Is(err, ErrNotTheCell):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case errors.Is(err, ErrNotACell), apierrors.IsNotFound(err):
+			http.Error(w, fmt.Sprintf("%s is not a cell left to reveal", c.PodName()), http.StatusNotFound)
+			return
+		case err != nil:
+			log.FromContext(r.Context()).Error(err, "failed to reveal", "coords", c)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintf(w, "revealing %s\n", c.PodName())
+	})
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func newTestExecReveal() *ExecReveal {
+	cell := createTestPod("pod-2-3", testNamespace)
+	cell.Status.PodIP = "10.0.0.23"
+	other := createTestPod("pod-0-0", testNamespace)
+	other.Status.PodIP = "10.0.0.1"
+	hint := createTestPod("pod-4-4", testNamespace)
+	hint.Labels[LabelComponent] = "hint"
+	hint.Status.PodIP = "10.0.0.44"
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+		WithObjects(cell, other, hint).WithStatusSubresource(cell, other, hint).Build()
+	return &ExecReveal{Client: c, Namespace: testNamespace}
+}
+
+func TestExecReveal_Reveal(t *testing.T) {
+	tests := []struct {
+		name    string
+		coords  game.Coordinate
+		ip      string
+		want    error
+		deleted bool
+	}{
+		{"own cell", game.Coordinate{X: 2, Y: 3}, "10.0.0.23", nil, true},
+		{"another cell", game.Coordinate{X: 2, Y: 3}, "10.0.0.1", ErrNotTheCell, false},
+		{"not a cell", game.Coordinate{X: 4, Y: 4}, "10.0.0.44", ErrNotACell, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newTestExecReveal()
+			err := e.Reveal(ctx, tt.coords, tt.ip)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			getErr := e.Client.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: tt.coords.PodName()}, &corev1.Pod{})
+			if deleted := apierrors.IsNotFound(getErr); deleted != tt.deleted {
+				t.Errorf("expected deleted=%v, got %v", tt.deleted, deleted)
+			}
+		})
+	}
+}
+
+func TestExecReveal_Handler(t *testing.T) {
+	handler := newTestExecReveal().Handler()
+	post := func(body, remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/reveal", strings.NewReader(body))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"x":2,"y":3}`, "10.0.0.1:41000"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected a cell revealing another to be forbidden, got %d", rec.Code)
+	}
+	if rec := post(`{"x":2,"y":3}`, "10.0.0.23:41000"); rec.Code != http.StatusAccepted {
+		t.Errorf("expected the cell to reveal itself, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := post(`{"x":2,"y":3}`, "10.0.0.23:41000"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected a revealed cell not to be found, got %d", rec.Code)
+	}
+	if rec := post(`not json`, "10.0.0.23:41000"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid request to be refused, got %d", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reveal", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET to be refused, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+// DefaultBoardClearTimeout bounds the wait for the board pods to be gone
+// when a game is saved or loaded.
+const DefaultBoardClearTimeout = 2 * time.Minute
+
+// SaveSlots suspends the running game to a named save slot and loads saved
+// games back, so that long games on big boards can be resumed across days
+// or cluster restarts. A saved game is a copy of the game state with its
+// clock stopped, kept in its own Secret; the board pods are removed on
+// save and respawned on load.
+type SaveSlots struct {
+	// Client manages the board pods and the save slot Secrets.
+	Client client.Client
+
+	// Store holds the live game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Handlers create hint and defused marker pods.
+	Handlers *GameHandlers
+
+	// Spawner creates cell and resign pods.
+	Spawner *spawner.GridSpawner
+
+	// ClearTimeout bounds the wait for the board pods to be gone. Defaults
+	// to DefaultBoardClearTimeout.
+	ClearTimeout time.Duration
+}
+
+// NewSaveSlots creates the save slots of a game namespace.
+func NewSaveSlots(c client.Client, namespace string) *SaveSlots {
+	store := game.NewSecretStore(c, game.WithNamespace(namespace))
+	return &SaveSlots{
+		Client:    c,
+		Store:     store,
+		Namespace: namespace,
+		Handlers:  NewGameHandlers(c, store, namespace),
+		Spawner:   spawner.NewGridSpawner(c, spawner.GridSpawnerConfig{Namespace: namespace}),
+	}
+}
+
+// List returns the names of the save slots, sorted.
+func (s *SaveSlots) List(ctx context.Context) ([]string, error) {
+	return game.ListSlots(ctx, s.Client, s.Namespace)
+}
+
+// Save suspends the running game at now into slot name, overwriting a
+// previous save. The live state is deleted before the board pods, so that
+// the controller doesn't take their deletion for clicks.
+func (s *SaveSlots) Save(ctx context.Context, name string, now time.Time) (*game.GameState, error) {
+	if err := game.ValidateSlotName(name); err != nil {
+		return nil, err
+	}
+	state, err := s.Store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no game to save in namespace %s", s.Namespace)
+	}
+	if state.Status != game.StatusPlaying {
+		return nil, fmt.Errorf("the game has already ended (%s)", state.Status)
+	}
+
+	state.PauseAt(now)
+	if err := game.NewSlotStore(s.Client, s.Namespace, name).Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save slot %q: %w", name, err)
+	}
+	if err := s.Store.Delete(ctx); err != nil {
+		return nil, fmt.Errorf("failed to delete game state: %w", err)
+	}
+	if err := s.Handlers.syncHints(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to clear hints: %w", err)
+	}
+	if err := s.clearBoard(ctx); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Load resumes the game of slot name at now, respawning its board pods.
+// The pods of an ended game are removed first; a running game must be
+// saved or resigned before. The slot is kept, so a save can be loaded
+// again.
+func (s *SaveSlots) Load(ctx context.Context, name string, now time.Time) (*game.GameState, error) {
+	if err := game.ValidateSlotName(name); err != nil {
+		return nil, err
+	}
+	state, err := game.NewSlotStore(s.Client, s.Namespace, name).Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slot %q: %w", name, err)
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no saved game in slot %q", name)
+	}
+
+	live, err := s.Store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if live != nil {
+		if live.Status == game.StatusPlaying {
+			return nil, fmt.Errorf("a game is running in namespace %s, save or resign it first", s.Namespace)
+		}
+		// Spectators see the ended board being cleaned
+		if err := live.SetPhase(game.PhaseCleaning); err != nil {
+			return nil, err
+		}
+		if err := s.Store.Save(ctx, live); err != nil {
+			return nil, fmt.Errorf("failed to save game state: %w", err)
+		}
+		if err := s.clearBoard(ctx); err != nil {
+			return nil, err
+		}
+		if err := s.Store.Delete(ctx); err != nil {
+			return nil, fmt.Errorf("failed to delete game state: %w", err)
+		}
+	}
+
+	// The controller defers moves until the pods are respawned
+	resumed := state.CurrentPhase()
+	if err := state.SetPhase(game.PhaseSpawning); err != nil {
+		return nil, err
+	}
+	if err := s.Store.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save game state: %w", err)
+	}
+	if err := s.respawn(ctx, state); err != nil {
+		return nil, err
+	}
+	if err := state.SetPhase(resumed); err != nil {
+		return nil, err
+	}
+	state.ResumeAt(now)
+	// The time spent saved is not downtime
+	state.HeartbeatAt = now
+	if err := s.Store.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save game state: %w", err)
+	}
+	return state, nil
+}
+
+// Delete removes slot name. Deleting an empty slot is not an error.
+func (s *SaveSlots) Delete(ctx context.Context, name string) error {
+	if err := game.ValidateSlotName(name); err != nil {
+		return err
+	}
+	return game.NewSlotStore(s.Client, s.Namespace, name).Delete(ctx)
+}
+
+// respawn creates the board pods implied by state.
+func (s *SaveSlots) respawn(ctx context.Context, state *game.GameState) error {
+	return s.Handlers.SpawnBoard(ctx, s.Spawner, state)
+}
+
+// SpawnBoard creates the board pods implied by state: cell pods of hidden
+// cells, hint and defused marker pods, and the resign pod. Pods already
+// there are left alone.
+func (h *GameHandlers) SpawnBoard(ctx context.Context, s *spawner.GridSpawner, state *game.GameState) error {
+	drift := DetectDrift(state, nil)
+	var errs []error
+	for _, c := range drift.MissingCells {
+		if err := spawnCellPod(ctx, h.client, s, state, c); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to create %s: %w", c.PodName(), err))
+		}
+	}
+	for _, c := range drift.MissingHints {
+		if err := h.spawnHintPod(ctx, state, c, state.AdjacentMines(c.X, c.Y)); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to create %s: %w", c.HintPodName(), err))
+		}
+	}
+	for _, c := range drift.MissingDefused {
+		if err := h.spawnDefusedPod(ctx, c); err != nil && !errors.IsAlreadyExists(err) {
+			errs = append(errs, fmt.Errorf("failed to create %s: %w", c.DefusedPodName(), err))
+		}
+	}
+	if err := h.client.Create(ctx, s.BuildResignPod(state.ID())); err != nil && !errors.IsAlreadyExists(err) {
+		errs = append(errs, fmt.Errorf("failed to create %s: %w", spawner.ResignPodName, err))
+	}
+	if err := h.syncHints(ctx, state); err != nil {
+		errs = append(errs, fmt.Errorf("failed to publish hints: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d pods failed to respawn, first: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+// clearBoard deletes the pods of the game and waits for them to be gone.
+func (s *SaveSlots) clearBoard(ctx context.Context) error {
+	return s.Handlers.ClearBoard(ctx, s.ClearTimeout)
+}
+
+// ClearBoard deletes the pods of the game, except the Gamemaster and
+// protected pods, and waits for them to be gone, at most timeout. Zero
+// waits DefaultBoardClearTimeout.
+func (h *GameHandlers) ClearBoard(ctx context.Context, timeout time.Duration) error {
+	selector := labels.SelectorFromSet(labels.Set{LabelApp: "podsweeper"})
+	notGamemaster, _ := labels.NewRequirement(LabelComponent, selection.NotEquals, []string{"gamemaster"})
+	selector = selector.Add(*notGamemaster)
+
+	if timeout <= 0 {
+		timeout = DefaultBoardClearTimeout
+	}
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pods := &corev1.PodList{}
+		if err := h.client.List(ctx, pods, client.InNamespace(h.namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, fmt.Errorf("failed to list game pods: %w", err)
+		}
+		remaining := 0
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if h.protected.Protects(pod) {
+				continue
+			}
+			remaining++
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			// Cell pods ignore SIGTERM, so a graceful deletion would hold
+			// their names for the next board for the whole grace period
+			if err := h.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !errors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+			}
+		}
+		return remaining == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear the board: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func TestSaveSlots_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	slots := NewSaveSlots(c, testNamespace)
+	start := time.Date(2025, 3, 7, 18, 0, 0, 0, time.UTC)
+
+	// Mine at (1,1): (0,0) shows a hint and (2,2) is defused
+	state := createTestGameState(3)
+	state.StartedAt = start
+	state.Reveal(0, 0)
+	state.SetMine(2, 2)
+	state.Cells[2][2].Defused = true
+	if err := slots.Store.Save(ctx, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := slots.Spawner.SpawnGrid(ctx, state); err != nil {
+		t.Fatalf("SpawnGrid failed: %v", err)
+	}
+	gamemaster := createTestPod("gamemaster", testNamespace)
+	gamemaster.Labels[LabelComponent] = "gamemaster"
+	if err := c.Create(ctx, gamemaster); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	saveAt := start.Add(10 * time.Minute)
+	if _, err := slots.Save(ctx, "friday", saveAt); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if live, _ := slots.Store.Load(ctx); live != nil {
+		t.Error("expected the live game state to be deleted")
+	}
+	pods := &corev1.PodList{}
+	_ = c.List(ctx, pods, client.InNamespace(testNamespace))
+	if len(pods.Items) != 1 || pods.Items[0].Name != "gamemaster" {
+		t.Errorf("expected only the gamemaster pod left, got %d pods", len(pods.Items))
+	}
+	if names, _ := slots.List(ctx); len(names) != 1 || names[0] != "friday" {
+		t.Errorf("expected the friday slot, got %v", names)
+	}
+
+	// Three days later
+	loadAt := saveAt.Add(72 * time.Hour)
+	loaded, err := slots.Load(ctx, "friday", loadAt)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.IsPaused() || loaded.ElapsedAt(loadAt.Add(time.Minute)) != 11*time.Minute {
+		t.Errorf("expected the clock to resume at 10m, got paused=%v elapsed=%v", loaded.IsPaused(), loaded.ElapsedAt(loadAt.Add(time.Minute)))
+	}
+	if live, _ := slots.Store.Load(ctx); live == nil || !live.IsRevealed(0, 0) || !live.HeartbeatAt.Equal(loadAt) {
+		t.Errorf("expected the saved game to be live again, got %+v", live)
+	}
+
+	for _, name := range []string{"pod-0-1", "pod-1-1", "hint-0-0", "defused-2-2", spawner.ResignPodName} {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, &corev1.Pod{}); err != nil {
+			t.Errorf("expected %s to be respawned: %v", name, err)
+		}
+	}
+	for _, name := range []string{"pod-0-0", "pod-2-2"} {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, &corev1.Pod{}); err == nil {
+			t.Errorf("expected no pod for the revealed cell %s", name)
+		}
+	}
+
+	// A running game is not overwritten by a load
+	if _, err := slots.Load(ctx, "friday", loadAt); err == nil {
+		t.Error("expected loading over a running game to fail")
+	}
+}
+
+func TestSaveSlots_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	slots := NewSaveSlots(c, testNamespace)
+	now := time.Now()
+
+	if _, err := slots.Save(ctx, "friday", now); err == nil {
+		t.Error("expected saving without a game to fail")
+	}
+	if _, err := slots.Load(ctx, "friday", now); err == nil {
+		t.Error("expected loading an empty slot to fail")
+	}
+	if _, err := slots.Save(ctx, "Friday!", now); err == nil {
+		t.Error("expected an invalid slot name to fail")
+	}
+
+	state := createTestGameState(3)
+	state.Reveal(1, 1)
+	state.SetLost()
+	_ = slots.Store.Save(ctx, state)
+	if _, err := slots.Save(ctx, "friday", now); err == nil {
+		t.Error("expected saving an ended game to fail")
+	}
+
+	// An ended game is replaced by a load
+	saved := createTestGameState(3)
+	_ = game.NewSlotStore(c, testNamespace, "monday").Save(ctx, saved)
+	if _, err := slots.Load(ctx, "monday", now); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if live, _ := slots.Store.Load(ctx); live == nil || live.Status != game.StatusPlaying {
+		t.Errorf("expected the saved game to replace the ended one, got %+v", live)
+	}
+
+	if err := slots.Delete(ctx, "monday"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if names, _ := slots.List(ctx); len(names) != 0 {
+		t.Errorf("expected no slots left, got %v", names)
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/schedule"
+)
+
+// DefaultScheduleInterval is how often the Gamemaster checks whether a
+// scheduled game is due.
+const DefaultScheduleInterval = 30 * time.Second
+
+// GameScheduler starts a new game on a cron schedule, such as a new expert
+// game every Monday at 09:00 for a standing office competition. The game
+// it replaces, finished or not, is archived first. New games are saved
+// Pending, for the BoardSpawner to clear the previous board and spawn
+// theirs. Runs missed while the Gamemaster was down are skipped. It runs
+// as a manager Runnable, only on the leader.
+type GameScheduler struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Archive keeps the replaced games. Nil discards them.
+	Archive *results.Archive
+
+	// Namespace is the game namespace, recorded in archived results.
+	Namespace string
+
+	// Schedule tells when to start games.
+	Schedule *schedule.Schedule
+
+	// Location is the time zone of the schedule. Defaults to UTC.
+	Location *time.Location
+
+	// Config generates the boards of the scheduled games.
+	Config grid.Config
+
+	// Interval between checks. Defaults to DefaultScheduleInterval.
+	Interval time.Duration
+
+	// now returns the current time, for tests.
+	now func() time.Time
+}
+
+// Start implements manager.Runnable.
+func (s *GameScheduler) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("scheduler")
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultScheduleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	next := s.Next()
+	for {
+		if next.IsZero() {
+			logger.Info("schedule never runs, no game will be started", "schedule", s.Schedule)
+			return nil
+		}
+		if !s.clock().Before(next) {
+			if err := s.StartGame(ctx); err != nil {
+				logger.Error(err, "failed to start the scheduled game")
+			}
+			next = s.Next()
+			logger.Info("next scheduled game", "at", next)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *GameScheduler) NeedLeaderElection() bool {
+	return true
+}
+
+// Next returns when the next game starts, the zero time if never.
+func (s *GameScheduler) Next() time.Time {
+	return s.Schedule.Next(s.clock())
+}
+
+// StartGame archives the current game, if any, and replaces it with a new
+// Pending game.
+func (s *GameScheduler) StartGame(ctx context.Context) error {
+	next, err := replaceGame(ctx, s.Store, s.Archive, s.Namespace, s.Config)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).WithName("scheduler").Info("started a scheduled game",
+		"namespace", s.Namespace, "cells", next.TotalCells(), "mines", next.MineCount)
+	return nil
+}
+
+// replaceGame archives the current game, if any, and replaces it with a
+// new Pending game generated from config, for the BoardSpawner to spawn.
+func replaceGame(ctx context.Context, store game.Store, archive *results.Archive, namespace string,
+	config grid.Config) (*game.GameState, error) {
+	state, err := store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && archive != nil {
+		if err := archive.ArchiveGame(ctx, results.Game{Namespace: namespace, State: state}); err != nil {
+			return nil, err
+		}
+	}
+
+	config.SecureSeed = true
+	gen, err := grid.NewGenerator(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game config: %w", err)
+	}
+	next := gen.Generate()
+	if err := store.Save(ctx, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// clock returns the current time in the location of the schedule.
+func (s *GameScheduler) clock() time.Time {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return now().In(loc)
+}
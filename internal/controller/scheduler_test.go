@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/schedule"
+)
+
+func TestGameScheduler_StartGame(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	previous := createTestGameState(3)
+	previous.SetLost()
+	_ = store.Save(ctx, previous)
+
+	s := &GameScheduler{
+		Store:     store,
+		Archive:   &results.Archive{Client: c, Namespace: testNamespace},
+		Namespace: testNamespace,
+		Config:    grid.GetDifficultyConfig(grid.DifficultyEasy),
+	}
+	if err := s.StartGame(ctx); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+
+	archived, err := s.Archive.Results(ctx)
+	if err != nil || len(archived) != 1 {
+		t.Fatalf("expected the previous game to be archived, got %+v: %v", archived, err)
+	}
+	if archived[0].GameID != previous.ID() || archived[0].Status != game.StatusLost {
+		t.Errorf("expected the lost previous game, got %+v", archived[0])
+	}
+
+	state, _ := store.Load(ctx)
+	if state.ID() == previous.ID() || state.Status != game.StatusPlaying {
+		t.Errorf("expected a new game, got %s", state.ID())
+	}
+	if state.CurrentPhase() != game.PhasePending {
+		t.Errorf("expected the new game to wait for the board spawner, got %s", state.CurrentPhase())
+	}
+	if w, h := s.Config.Dimensions(); state.Width != w || state.Height != h {
+		t.Errorf("expected a %dx%d board, got %dx%d", w, h, state.Width, state.Height)
+	}
+}
+
+func TestGameScheduler_Next(t *testing.T) {
+	sched, err := schedule.Parse("0 9 * * 1")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	s := &GameScheduler{
+		Schedule: sched,
+		Location: paris,
+		now:      func() time.Time { return time.Date(2026, time.March, 9, 8, 30, 0, 0, time.UTC) },
+	}
+	// 09:30 in Paris, the game of the day already started
+	if got, want := s.Next(), time.Date(2026, time.March, 16, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
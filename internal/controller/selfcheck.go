@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// ConditionDegraded is set while the Gamemaster lacks permissions it
+	// needs to play.
+	ConditionDegraded = "Degraded"
+
+	// DefaultSelfCheckInterval is how often a degraded Gamemaster checks
+	// its permissions again.
+	DefaultSelfCheckInterval = 30 * time.Second
+)
+
+// SelfCheck verifies on boot, with SelfSubjectAccessReviews, that the
+// Gamemaster was granted every permission it needs in the game namespace.
+// While some are missing it is Degraded: it fails its readiness check and
+// new games are not started. Permissions are checked again until granted.
+type SelfCheck struct {
+	// Client creates the access reviews.
+	Client client.Client
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Required lists the permissions to check, see rbac.GamemasterRules.
+	Required []rbacv1.PolicyRule
+
+	// Interval between checks while degraded. Defaults to
+	// DefaultSelfCheckInterval.
+	Interval time.Duration
+
+	mu         sync.RWMutex
+	conditions []metav1.Condition
+	missing    []string
+}
+
+// Start implements manager.Runnable.
+func (s *SelfCheck) Start(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultSelfCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Check(ctx); err != nil {
+			log.FromContext(ctx).WithName("selfcheck").Error(err, "failed to check RBAC permissions")
+		} else if !s.Degraded() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: standby
+// replicas report their permissions too.
+func (s *SelfCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Check reviews the required permissions, logs the missing ones and
+// updates the Degraded condition.
+func (s *SelfCheck) Check(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("selfcheck")
+
+	missing, err := rbac.Review(ctx, s.Client, s.Namespace, s.Required)
+	if err != nil {
+		return err
+	}
+	condition := metav1.Condition{
+		Type:    ConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "PermissionsGranted",
+		Message: "every required permission is granted",
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MissingPermissions"
+		condition.Message = fmt.Sprintf("missing %d permissions in namespace %s: %s",
+			len(missing), s.Namespace, strings.Join(missing, ", "))
+		logger.Info("ERROR: missing RBAC permissions, no game will be started; see `gamemaster manifests`",
+			"namespace", s.Namespace, "missing", missing)
+	} else {
+		logger.Info("every required RBAC permission is granted", "namespace", s.Namespace)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta.SetStatusCondition(&s.conditions, condition)
+	s.missing = missing
+	return nil
+}
+
+// Degraded reports whether permissions were found missing. A nil
+// SelfCheck is never degraded.
+func (s *SelfCheck) Degraded() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return meta.IsStatusConditionTrue(s.conditions, ConditionDegraded)
+}
+
+// Conditions returns the conditions of the self-check.
+func (s *SelfCheck) Conditions() []metav1.Condition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]metav1.Condition(nil), s.conditions...)
+}
+
+// ReadyzCheck implements healthz.Checker, failing while degraded.
+func (s *SelfCheck) ReadyzCheck(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if c := meta.FindStatusCondition(s.conditions, ConditionDegraded); c != nil && c.Status == metav1.ConditionTrue {
+		return fmt.Errorf("%s: %s", c.Reason, c.Message)
+	}
+	return nil
+}
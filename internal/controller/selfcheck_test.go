@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+// accessClient answers access reviews, denying secrets unless granted.
+func accessClient(granted *bool) client.Client {
+	return interceptor.NewClient(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = *granted || review.Spec.ResourceAttributes.Resource != "secrets"
+			return nil
+		},
+	})
+}
+
+func TestSelfCheck(t *testing.T) {
+	ctx := context.Background()
+	granted := false
+	check := &SelfCheck{
+		Client:    accessClient(&granted),
+		Namespace: testNamespace,
+		Required:  rbac.GamemasterRules(rbac.Config{Namespace: testNamespace}),
+	}
+	if check.Degraded() || check.ReadyzCheck(nil) != nil {
+		t.Error("expected no degradation before the first check")
+	}
+
+	if err := check.Check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !check.Degraded() {
+		t.Fatal("expected missing permissions to degrade the Gamemaster")
+	}
+	if err := check.ReadyzCheck(nil); err == nil {
+		t.Error("expected a degraded Gamemaster not to be ready")
+	}
+	if c := check.Conditions(); len(c) != 1 || c[0].Type != ConditionDegraded || c[0].Reason != "MissingPermissions" {
+		t.Errorf("expected a MissingPermissions Degraded condition, got %+v", c)
+	}
+
+	granted = true
+	if err := check.Check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if check.Degraded() || check.ReadyzCheck(nil) != nil {
+		t.Error("expected granted permissions to clear the degradation")
+	}
+}
+
+func TestGameController_DegradedDoesNotStartGames(t *testing.T) {
+	ctx := context.Background()
+	granted := false
+	check := &SelfCheck{
+		Client:    accessClient(&granted),
+		Namespace: testNamespace,
+		Required:  rbac.GamemasterRules(rbac.Config{Namespace: testNamespace}),
+	}
+	if err := check.Check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, createTestGameState(8))
+	controller := NewGameController(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(), GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+		SelfCheck: check,
+	})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-3-5", Namespace: testNamespace}}
+	result, err := controller.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if state, _ := store.Load(ctx); state.IsRevealed(3, 5) || result.RequeueAfter == 0 {
+		t.Error("expected the first move to wait for permissions")
+	}
+
+	granted = true
+	if err := check.Check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if state, _ := store.Load(ctx); !state.IsRevealed(3, 5) {
+		t.Error("expected the game to start once permissions are granted")
+	}
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/shard"
+)
+
+// DefaultShardInterval is how often sharded Gamemasters refresh the games
+// they own.
+const DefaultShardInterval = 10 * time.Second
+
+// ShardedGames plays the games of many namespaces, sharing them with the
+// other members of its shard group: each game namespace matching Selector
+// is played by the member shard.Owner assigns it to, with its own
+// GameController. Games are released as soon as they are assigned
+// elsewhere, but only taken once assigned for two refreshes in a row, so
+// that the previous owner has let go. Pod deletions that happened while a
+// game had no owner are played on takeover.
+//
+// Only moves are sharded: drift correction, heartbeats and the other
+// per-game Runnables need a Gamemaster per namespace.
+type ShardedGames struct {
+	// Client reads and plays the games.
+	Client client.Client
+
+	// Membership tells the live members of the shard group.
+	Membership *shard.Membership
+
+	// Selector matches the game namespaces, such as those of a workshop.
+	Selector labels.Selector
+
+	// Config is the configuration of the GameController of each game;
+	// Namespace and Store are set per game.
+	Config GameControllerConfig
+
+	// Interval between refreshes. Defaults to DefaultShardInterval.
+	Interval time.Duration
+
+	mu       sync.RWMutex
+	owned    map[string]*GameController
+	assigned map[string]bool
+	catchUp  chan event.GenericEvent
+}
+
+// Reconcile implements reconcile.Reconciler, delegating to the controller
+// of the game of the namespace when this member owns it.
+func (s *ShardedGames) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	gc := s.game(req.Namespace)
+	if gc == nil {
+		return ctrl.Result{}, nil
+	}
+	return gc.Reconcile(ctx, req)
+}
+
+// Owned returns the namespaces of the games this member plays.
+func (s *ShardedGames) Owned() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	owned := make([]string, 0, len(s.owned))
+	for ns := range s.owned {
+		owned = append(owned, ns)
+	}
+	return owned
+}
+
+// Start implements manager.Runnable.
+func (s *ShardedGames) Start(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultShardInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx, time.Now()); err != nil {
+			log.FromContext(ctx).WithName("shard").Error(err, "failed to refresh owned games")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: members
+// are active-active.
+func (s *ShardedGames) NeedLeaderElection() bool {
+	return false
+}
+
+// Refresh takes and releases games to match their assignment to the live
+// members.
+func (s *ShardedGames) Refresh(ctx context.Context, now time.Time) error {
+	logger := log.FromContext(ctx).WithName("shard")
+
+	members, err := s.Membership.Members(ctx, now)
+	if err != nil {
+		return err
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := s.Client.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: s.Selector}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owned == nil {
+		s.owned = make(map[string]*GameController)
+	}
+	assigned := make(map[string]bool)
+	for _, ns := range namespaces.Items {
+		if !ns.DeletionTimestamp.IsZero() || shard.Owner(ns.Name, members) != s.Membership.Identity {
+			continue
+		}
+		assigned[ns.Name] = true
+		if s.owned[ns.Name] == nil && s.assigned[ns.Name] {
+			s.owned[ns.Name] = s.take(ctx, ns.Name)
+			logger.Info("game taken", "namespace", ns.Name, "members", len(members))
+		}
+	}
+	for ns := range s.owned {
+		if !assigned[ns] {
+			delete(s.owned, ns)
+			logger.Info("game released", "namespace", ns, "members", len(members))
+		}
+	}
+	s.assigned = assigned
+	return nil
+}
+
+// take builds the controller of the game of namespace, and replays the
+// deletions of its hidden cells that no one played.
+func (s *ShardedGames) take(ctx context.Context, namespace string) *GameController {
+	config := s.Config
+	config.Namespace = namespace
+	config.Store = game.NewSecretStore(s.Client, game.WithNamespace(namespace))
+	gc := NewGameController(s.Client, config)
+
+	if s.catchUp == nil {
+		return gc
+	}
+	go func() {
+		state, err := config.Store.Load(ctx)
+		if err != nil || state == nil || state.Status != game.StatusPlaying {
+			return
+		}
+		// Pods still there are left alone by their reconcile
+		var pods []string
+		w, h := state.Dimensions()
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if !state.IsRevealed(x, y) && !state.IsDefused(x, y) {
+					pods = append(pods, game.Coordinate{X: x, Y: y}.PodName())
+				}
+			}
+		}
+		for _, d := range state.Defusals {
+			pods = append(pods, d.BombPodName())
+		}
+		for _, name := range pods {
+			pod := &corev1.Pod{}
+			pod.Name, pod.Namespace = name, namespace
+			select {
+			case s.catchUp <- event.GenericEvent{Object: pod}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return gc
+}
+
+// game returns the controller of the game of namespace, nil if this
+// member doesn't own it.
+func (s *ShardedGames) game(namespace string) *GameController {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owned[namespace]
+}
+
+// SetupWithManager sets up the sharded controller and its refreshes with
+// the Manager.
+func (s *ShardedGames) SetupWithManager(mgr ctrl.Manager) error {
+	s.catchUp = make(chan event.GenericEvent)
+	if err := mgr.Add(s); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("sharded-games").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return s.game(object.GetNamespace()) != nil && !s.Config.Protected.Protects(object)
+		}))).
+		WatchesRawSource(source.Channel(s.catchUp, &handler.EnqueueRequestForObject{})).
+		WithOptions(controller.Options{RateLimiter: s.Config.RateLimiter}).
+		Complete(s)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/shard"
+)
+
+func TestShardedGames(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+	_ = coordinationv1.AddToScheme(scheme)
+	objs := []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}}}
+	var games []string
+	for i := 1; i <= 6; i++ {
+		ns := fmt.Sprintf("ws-%d", i)
+		games = append(games, ns)
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns, Labels: map[string]string{"workshop": "ws"}}})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	selector := labels.SelectorFromSet(labels.Set{"workshop": "ws"})
+	now := time.Now()
+
+	members := map[string]*ShardedGames{}
+	for _, id := range []string{"gm-a", "gm-b"} {
+		m := &shard.Membership{Client: c, Namespace: "system", Identity: id}
+		if err := m.Renew(ctx, now); err != nil {
+			t.Fatalf("Renew failed: %v", err)
+		}
+		members[id] = &ShardedGames{Client: c, Membership: m, Selector: selector}
+	}
+	refresh := func(s *ShardedGames) {
+		t.Helper()
+		if err := s.Refresh(ctx, now); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+	}
+
+	// Games are only taken once assigned twice in a row
+	for _, s := range members {
+		refresh(s)
+		if owned := s.Owned(); len(owned) != 0 {
+			t.Errorf("expected nothing taken on the first refresh, got %v", owned)
+		}
+	}
+	for _, s := range members {
+		refresh(s)
+	}
+	owned := append(members["gm-a"].Owned(), members["gm-b"].Owned()...)
+	sort.Strings(owned)
+	if fmt.Sprint(owned) != fmt.Sprint(games) {
+		t.Fatalf("expected every game owned once, got %v", owned)
+	}
+
+	// gm-b leaves: its games are handed over to gm-a
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: shard.LeasePrefix + "gm-b", Namespace: "system"}}
+	if err := c.Delete(ctx, lease); err != nil {
+		t.Fatalf("failed to delete lease: %v", err)
+	}
+	refresh(members["gm-b"])
+	if owned := members["gm-b"].Owned(); len(owned) != 0 {
+		t.Errorf("expected a member out of the group to release its games, got %v", owned)
+	}
+	refresh(members["gm-a"])
+	refresh(members["gm-a"])
+	if owned := members["gm-a"].Owned(); len(owned) != len(games) {
+		t.Fatalf("expected gm-a to take every game, got %v", owned)
+	}
+
+	// Moves are played by the owner only
+	state := createTestGameState(3)
+	if err := game.NewSecretStore(c, game.WithNamespace("ws-1")).Save(ctx, state); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ws-1", Name: "pod-0-0"}}
+	for _, s := range []*ShardedGames{members["gm-b"], members["gm-a"]} {
+		if _, err := s.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+	}
+	saved, _ := game.NewSecretStore(c, game.WithNamespace("ws-1")).Load(ctx)
+	if !saved.IsRevealed(0, 0) || saved.Clicks != 1 {
+		t.Errorf("expected the move played once, got %d clicks", saved.Clicks)
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultSpectatorSampleInterval is how often the delayed board records
+// the live board.
+const DefaultSpectatorSampleInterval = time.Second
+
+// boardSnapshot is the board as players saw it at some point.
+type boardSnapshot struct {
+	at    time.Time
+	stats game.GameStats
+	board string
+	theme string
+	phase game.Phase
+
+	// none is set when no game was running.
+	none bool
+}
+
+// DelayedBoard serves the board to spectators some time behind the live
+// game, so that audiences of competitive games can't help the players. It
+// records the board every sample interval and serves the last one recorded
+// before the delay. It runs as a manager Runnable on every replica, as
+// every replica serves the board.
+type DelayedBoard struct {
+	// Store holds the game state.
+	Store game.Store
+
+	// Delay is how far behind the live game the board is served.
+	Delay time.Duration
+
+	// SampleInterval between recordings. Defaults to
+	// DefaultSpectatorSampleInterval.
+	SampleInterval time.Duration
+
+	mu        sync.Mutex
+	snapshots []boardSnapshot
+}
+
+// Start implements manager.Runnable.
+func (d *DelayedBoard) Start(ctx context.Context) error {
+	interval := d.SampleInterval
+	if interval <= 0 {
+		interval = DefaultSpectatorSampleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("spectator")
+	for {
+		if err := d.Record(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to record the spectator board")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (d *DelayedBoard) NeedLeaderElection() bool {
+	return false
+}
+
+// Record records the live board at now, and forgets the boards that can
+// no longer be served.
+func (d *DelayedBoard) Record(ctx context.Context, now time.Time) error {
+	state, err := d.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+	snapshot := boardSnapshot{at: now, none: state == nil}
+	if state != nil {
+		snapshot.stats = state.Stats()
+		snapshot.board = state.ToBoardString(game.WithPlayerView())
+		snapshot.theme = state.Theme
+		snapshot.phase = state.CurrentPhase()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshots = append(d.snapshots, snapshot)
+	// Keep the last board recorded before the delay, which is served now
+	cutoff := now.Add(-d.Delay)
+	first := 0
+	for first+1 < len(d.snapshots) && !d.snapshots[first+1].at.After(cutoff) {
+		first++
+	}
+	d.snapshots = d.snapshots[first:]
+	return nil
+}
+
+// served returns the board served at now: the last one recorded at least
+// Delay before. It returns false while no board is old enough.
+func (d *DelayedBoard) served(now time.Time) (boardSnapshot, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cutoff := now.Add(-d.Delay)
+	for i := len(d.snapshots) - 1; i >= 0; i-- {
+		if !d.snapshots[i].at.After(cutoff) {
+			return d.snapshots[i], true
+		}
+	}
+	return boardSnapshot{}, false
+}
+
+// Handler serves the delayed board like BoardHandler serves the live one.
+// Until a board is old enough, typically right after the Gamemaster
+// started, it answers 503 with a Retry-After header.
+func (d *DelayedBoard) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		snapshot, ok := d.served(now)
+		if !ok {
+			wait := d.Delay
+			d.mu.Lock()
+			if len(d.snapshots) > 0 {
+				wait = d.snapshots[0].at.Add(d.Delay).Sub(now)
+			}
+			d.mu.Unlock()
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+			http.Error(w, "the spectator board is not available yet", http.StatusServiceUnavailable)
+			return
+		}
+		if snapshot.none {
+			http.Error(w, "no game running", http.StatusNotFound)
+			return
+		}
+		if !snapshot.phase.Spawned() {
+			writeSpawning(w, snapshot.phase)
+			return
+		}
+
+		w.Header().Set("X-Podsweeper-Delay", d.Delay.String())
+		writeBoard(w, r, snapshot.stats, snapshot.board, snapshot.theme)
+	})
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestDelayedBoard_Served(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	d := &DelayedBoard{Store: store, Delay: 30 * time.Second}
+	start := time.Now()
+
+	if err := d.Record(ctx, start); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	state := createTestGameState(3)
+	_ = store.Save(ctx, state)
+	if err := d.Record(ctx, start.Add(10*time.Second)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	state.Reveal(0, 0)
+	_ = store.Save(ctx, state)
+	if err := d.Record(ctx, start.Add(20*time.Second)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		after     time.Duration
+		wantOK    bool
+		wantNone  bool
+		wantBoard string
+	}{
+		{"too early", 29 * time.Second, false, false, ""},
+		{"before the game", 35 * time.Second, true, true, ""},
+		{"new game", 40 * time.Second, true, false, "...\n...\n..."},
+		{"after the reveal", 55 * time.Second, true, false, "1..\n...\n..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, ok := d.served(start.Add(tt.after))
+			if ok != tt.wantOK {
+				t.Fatalf("served() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if snapshot.none != tt.wantNone || snapshot.board != tt.wantBoard {
+				t.Errorf("served() = %+v, want none=%v board=%q", snapshot, tt.wantNone, tt.wantBoard)
+			}
+		})
+	}
+
+	// Boards older than the one being served are forgotten
+	if err := d.Record(ctx, start.Add(60*time.Second)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if len(d.snapshots) != 2 {
+		t.Errorf("expected 2 boards kept, got %d", len(d.snapshots))
+	}
+}
+
+func TestDelayedBoard_Handler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	d := &DelayedBoard{Store: store, Delay: time.Hour}
+	handler := d.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "3600" {
+		t.Errorf("expected 503 retrying in an hour, got %d (%q)", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	_ = store.Save(ctx, state)
+	if err := d.Record(ctx, time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	state.Reveal(2, 2)
+	_ = store.Save(ctx, state)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "1..\n...\n...") {
+		t.Errorf("expected the delayed board (%d): %q", rec.Code, body)
+	}
+	if rec.Header().Get("X-Podsweeper-Delay") != "1h0m0s" {
+		t.Errorf("expected the delay header, got %q", rec.Header().Get("X-Podsweeper-Delay"))
+	}
+}
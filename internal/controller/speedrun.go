@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/version"
+)
+
+const (
+	// DefaultSpeedrunTimerInterval is how often the speedrun timer is updated.
+	DefaultSpeedrunTimerInterval = 5 * time.Second
+
+	// SpeedrunTimerPodName names the pod showing the speedrun timer.
+	SpeedrunTimerPodName = "speedrun-timer"
+
+	// AnnotationElapsed is the annotation of the timer pod showing the play
+	// time, like "1:02.35", and the final time once the game ended.
+	AnnotationElapsed = "podsweeper.io/elapsed"
+
+	// AnnotationFinal is set on the timer pod once the time is final.
+	AnnotationFinal = "podsweeper.io/final"
+)
+
+// SpeedrunTimer shows the play time of speedruns on a timer pod, next to
+// the game pods: `kubectl get pod speedrun-timer -o yaml` shows the
+// elapsed time, updated every interval, and the final time to the
+// hundredth of a second once the game ended. The timer pod is removed
+// when the game is not a speedrun. It runs as a manager Runnable, only on
+// the leader.
+type SpeedrunTimer struct {
+	// Client manages the timer pod.
+	Client client.Client
+
+	// Store holds the game state.
+	Store game.Store
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Interval between updates. Defaults to DefaultSpeedrunTimerInterval.
+	Interval time.Duration
+
+	// Messages renders the message of the timer pod. Nil renders the
+	// built-in message.
+	Messages *messages.Catalog
+}
+
+// Start implements manager.Runnable.
+func (t *SpeedrunTimer) Start(ctx context.Context) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultSpeedrunTimerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx).WithName("speedrun")
+	for {
+		if err := t.Update(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to update speedrun timer")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (t *SpeedrunTimer) NeedLeaderElection() bool {
+	return true
+}
+
+// Update shows the play time as of now on the timer pod, creating it if
+// needed, or removes it if the game is not a speedrun.
+func (t *SpeedrunTimer) Update(ctx context.Context, now time.Time) error {
+	state, err := t.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+
+	pod := &corev1.Pod{}
+	err = t.Client.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: SpeedrunTimerPodName}, pod)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get timer pod: %w", err)
+	}
+	exists := err == nil
+
+	if state == nil || !state.Speedrun {
+		if exists {
+			return client.IgnoreNotFound(t.Client.Delete(ctx, pod))
+		}
+		return nil
+	}
+
+	elapsed := game.FormatSpeedrunTime(state.SpeedrunTime(now))
+	final := "false"
+	if state.Status != game.StatusPlaying {
+		final = "true"
+	}
+
+	if !exists {
+		if err := t.Client.Create(ctx, t.timerPod(state, elapsed, final)); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create timer pod: %w", err)
+		}
+		return nil
+	}
+	if pod.Labels[spawner.LabelGameID] == state.ID() &&
+		pod.Annotations[AnnotationElapsed] == elapsed && pod.Annotations[AnnotationFinal] == final {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Labels[spawner.LabelGameID] = state.ID()
+	pod.Annotations[AnnotationElapsed] = elapsed
+	pod.Annotations[AnnotationFinal] = final
+	if err := t.Client.Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("failed to update timer pod: %w", err)
+	}
+	return nil
+}
+
+// timerPod returns the timer pod of a speedrun.
+func (t *SpeedrunTimer) timerPod(state *game.GameState, elapsed, final string) *corev1.Pod {
+	// A custom message failing to render falls back to the built-in one
+	message, _ := t.Messages.Render(messages.Timer, messages.Data{Theme: theme.Of(state.Theme)})
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SpeedrunTimerPodName,
+			Namespace: t.Namespace,
+			Labels: map[string]string{
+				LabelApp:            "podsweeper",
+				LabelComponent:      "timer",
+				spawner.LabelGameID: state.ID(),
+			},
+			Annotations: map[string]string{
+				AnnotationElapsed: elapsed,
+				AnnotationFinal:   final,
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: spawner.RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "timer",
+					Image:           ExplosionImage,
+					SecurityContext: spawner.RestrictedSecurityContext(),
+					Command:         spawner.EchoCommand(message),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestSpeedrunTimer_Update(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := game.NewMemoryStore()
+	timer := &SpeedrunTimer{Client: c, Store: store, Namespace: testNamespace}
+	key := types.NamespacedName{Name: SpeedrunTimerPodName, Namespace: testNamespace}
+
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := createTestGameState(3)
+	state.Speedrun = true
+	state.StartedAt = start
+	_ = store.Save(ctx, state)
+
+	if err := timer.Update(ctx, start.Add(5*time.Second)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, key, pod); err != nil {
+		t.Fatalf("expected the timer pod to be created: %v", err)
+	}
+	if got := pod.Annotations[AnnotationElapsed]; got != "0:05.00" {
+		t.Errorf("expected 0:05.00, got %q", got)
+	}
+
+	state.FinalTime = 7*time.Second + 250*time.Millisecond
+	state.Status = game.StatusWon
+	_ = store.Save(ctx, state)
+	if err := timer.Update(ctx, start.Add(time.Minute)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, key, pod)
+	if pod.Annotations[AnnotationElapsed] != "0:07.25" || pod.Annotations[AnnotationFinal] != "true" {
+		t.Errorf("expected the final time, got %v", pod.Annotations)
+	}
+
+	// A regular game has no timer
+	_ = store.Save(ctx, createTestGameState(3))
+	if err := timer.Update(ctx, start); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := c.Get(ctx, key, pod); !errors.IsNotFound(err) {
+		t.Errorf("expected the timer pod to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// enforceTimeLimit ends the timed game once its time is up, and returns
+// the play time left otherwise, zero for games without a time limit.
+// Games that can't be loaded are left to the reconcile.
+func (r *GameController) enforceTimeLimit(ctx context.Context) (remaining time.Duration, ended bool, err error) {
+	state, err := r.Store.Load(ctx)
+	if err != nil || state == nil || state.Status != game.StatusPlaying {
+		return 0, false, nil
+	}
+	now := time.Now()
+	if !state.TimeUp(now) {
+		remaining, _ := state.RemainingAt(now)
+		return remaining, false, nil
+	}
+
+	log.FromContext(ctx).Info("time is up", "limit", state.TimeLimit)
+	_, err = r.Handlers.HandleTimeout(ctx, state)
+	return 0, true, err
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGameController_TimeLimit(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).
+		WithObjects(createTestPod("pod-2-2", testNamespace)).Build()
+	store := game.NewMemoryStore()
+	state := createTestGameState(3)
+	state.TimeLimit = time.Hour
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{Namespace: testNamespace, Store: store})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	result, err := controller.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if result.RequeueAfter <= 59*time.Minute || result.RequeueAfter > time.Hour {
+		t.Errorf("expected a requeue at the deadline, got %v", result.RequeueAfter)
+	}
+
+	// The deadline passes: reconciling any pod ends the game
+	state, _ = store.Load(ctx)
+	state.StartedAt = time.Now().Add(-2 * time.Hour)
+	_ = store.Save(ctx, state)
+	req.Name = "pod-2-2"
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	loaded, _ := store.Load(ctx)
+	if loaded.Status != game.StatusLost || !loaded.TimedOut {
+		t.Errorf("expected the game lost by time out, got %s", loaded.Status)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: TimeoutPodName, Namespace: testNamespace}, &corev1.Pod{}); err != nil {
+		t.Errorf("expected a timeout pod: %v", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "pod-2-2", Namespace: testNamespace}, &corev1.Pod{}); err == nil {
+		t.Error("expected the board wiped")
+	}
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PodTombstones remember the UIDs of the deleted cell pods until their
+// deletion is played, since reconciles only get the name of a pod gone.
+// Deletions of pods other than the one recorded in the game state, such
+// as a pod of the previous game or one repaired since, are then not played
+// twice. A nil PodTombstones remembers nothing.
+type PodTombstones struct {
+	mu   sync.Mutex
+	uids map[string]types.UID
+}
+
+// NewPodTombstones creates empty tombstones.
+func NewPodTombstones() *PodTombstones {
+	return &PodTombstones{uids: map[string]types.UID{}}
+}
+
+// Record remembers the UID of the deleted pod called name.
+func (t *PodTombstones) Record(name string, uid types.UID) {
+	if t == nil || !IsPodName(name) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.uids[name] = uid
+}
+
+// Take returns and forgets the UID of the last deleted pod called name,
+// empty if unknown.
+func (t *PodTombstones) Take(name string) types.UID {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	uid := t.uids[name]
+	delete(t.uids, name)
+	return uid
+}
+
+// Handler returns the event handler enqueuing pods, recording the UIDs of
+// the deleted ones first so reconciles find them.
+func (t *PodTombstones) Handler() handler.EventHandler {
+	return &tombstoneHandler{tombstones: t}
+}
+
+// tombstoneHandler enqueues pods like handler.EnqueueRequestForObject,
+// recording the deleted ones in tombstones.
+type tombstoneHandler struct {
+	handler.EnqueueRequestForObject
+	tombstones *PodTombstones
+}
+
+// Delete implements handler.EventHandler.
+func (h *tombstoneHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if e.Object != nil {
+		h.tombstones.Record(e.Object.GetName(), e.Object.GetUID())
+	}
+	h.EnqueueRequestForObject.Delete(ctx, e, q)
+}
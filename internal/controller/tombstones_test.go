@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestPodTombstones(t *testing.T) {
+	tombstones := NewPodTombstones()
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1-2", Namespace: testNamespace, UID: "uid-1"}}
+	hint := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "hint-1-2", Namespace: testNamespace, UID: "uid-2"}}
+	h := tombstones.Handler()
+	h.Delete(context.Background(), event.DeleteEvent{Object: pod}, q)
+	h.Delete(context.Background(), event.DeleteEvent{Object: hint}, q)
+
+	if q.Len() != 2 {
+		t.Errorf("expected both deletions to be enqueued, got %d", q.Len())
+	}
+	if uid := tombstones.Take("pod-1-2"); uid != "uid-1" {
+		t.Errorf("expected the UID of the deleted cell pod, got %q", uid)
+	}
+	if uid := tombstones.Take("pod-1-2"); uid != "" {
+		t.Errorf("expected the tombstone to be taken, got %q", uid)
+	}
+	if uid := tombstones.Take("hint-1-2"); uid != "" {
+		t.Errorf("expected only cell pods to be remembered, got %q", uid)
+	}
+	if uid := (*PodTombstones)(nil).Take("pod-1-2"); uid != "" {
+		t.Errorf("expected nil tombstones to remember nothing, got %q", uid)
+	}
+}
+
+func TestGameController_ReconcileIgnoresStalePodDeletion(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+
+	store := game.NewMemoryStore()
+	state := createTestGameState(8)
+	state.SetPodUID(3, 5, "uid-respawned")
+	_ = store.Save(ctx, state)
+
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+	})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-3-5", Namespace: testNamespace}}
+
+	// The deletion of the pod replaced since is no click
+	controller.Tombstones.Record("pod-3-5", "uid-replaced")
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	loaded, _ := store.Load(ctx)
+	if loaded.IsRevealed(3, 5) || loaded.Clicks != 0 {
+		t.Fatal("expected the stale deletion to be ignored")
+	}
+
+	// The deletion of the pod recorded is played
+	controller.Tombstones.Record("pod-3-5", "uid-respawned")
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	loaded, _ = store.Load(ctx)
+	if !loaded.IsRevealed(3, 5) {
+		t.Error("expected the deletion to be played")
+	}
+	if loaded.PodUID(3, 5) != "" {
+		t.Errorf("expected the pod UID to be forgotten once the pod is gone, got %q", loaded.PodUID(3, 5))
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"flag"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultQPS is the sustained rate of API requests of the Gamemaster.
+	// Revealing an empty area or spawning the grid of a big board takes
+	// hundreds of requests, which the controller-runtime default of 20
+	// spreads over many seconds.
+	DefaultQPS = 50
+
+	// DefaultBurst is the burst of API requests of the Gamemaster.
+	DefaultBurst = 100
+
+	// DefaultRateLimiterBaseDelay is the delay before retrying a failed
+	// reconcile the first time; it doubles on each failure.
+	DefaultRateLimiterBaseDelay = 5 * time.Millisecond
+
+	// DefaultRateLimiterMaxDelay caps the delay between retries of a
+	// failed reconcile. Games are short: waiting the controller-runtime
+	// default of over 16 minutes leaves moves unplayed for too long.
+	DefaultRateLimiterMaxDelay = 30 * time.Second
+)
+
+// Tuning holds the throughput settings of the Gamemaster: how fast it may
+// call the API server, how failed reconciles are retried and how often
+// the cache is resynced.
+type Tuning struct {
+	// QPS and Burst limit the API requests of the client.
+	QPS   float64
+	Burst int
+
+	// BaseDelay and MaxDelay bound the exponential backoff of failed
+	// reconciles.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// SyncPeriod is how often cached objects are reconciled again even
+	// if unchanged. Zero keeps the controller-runtime default.
+	SyncPeriod time.Duration
+}
+
+// DefaultTuning returns the default throughput settings.
+func DefaultTuning() Tuning {
+	return Tuning{
+		QPS:       DefaultQPS,
+		Burst:     DefaultBurst,
+		BaseDelay: DefaultRateLimiterBaseDelay,
+		MaxDelay:  DefaultRateLimiterMaxDelay,
+	}
+}
+
+// BindFlags registers the flags setting t on fs, defaulting to its current
+// values.
+func (t *Tuning) BindFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&t.QPS, "kube-api-qps", t.QPS, "Maximum sustained rate of requests to the API server.")
+	fs.IntVar(&t.Burst, "kube-api-burst", t.Burst, "Maximum burst of requests to the API server.")
+	fs.DurationVar(&t.BaseDelay, "rate-limiter-base-delay", t.BaseDelay,
+		"Delay before retrying a failed reconcile, doubled on each failure.")
+	fs.DurationVar(&t.MaxDelay, "rate-limiter-max-delay", t.MaxDelay, "Maximum delay between retries of a failed reconcile.")
+	fs.DurationVar(&t.SyncPeriod, "sync-period", t.SyncPeriod,
+		"How often watched objects are reconciled again even if unchanged. Zero keeps the controller-runtime default.")
+}
+
+// RestConfig returns a copy of config with the API request limits of t.
+func (t Tuning) RestConfig(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	if t.QPS > 0 {
+		config.QPS = float32(t.QPS)
+	}
+	if t.Burst > 0 {
+		config.Burst = t.Burst
+	}
+	return config
+}
+
+// CacheOptions returns opts with the sync period of t.
+func (t Tuning) CacheOptions(opts cache.Options) cache.Options {
+	if t.SyncPeriod > 0 {
+		opts.SyncPeriod = &t.SyncPeriod
+	}
+	return opts
+}
+
+// RateLimiter returns the rate limiter of the controller workqueues, nil
+// to keep the controller-runtime default when no delay is set.
+func (t Tuning) RateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	if t.BaseDelay <= 0 || t.MaxDelay <= 0 {
+		return nil
+	}
+	return workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](t.BaseDelay, max(t.MaxDelay, t.BaseDelay))
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestTuningFlags(t *testing.T) {
+	tuning := DefaultTuning()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	tuning.BindFlags(fs)
+	err := fs.Parse([]string{"--kube-api-qps=200", "--kube-api-burst=400", "--rate-limiter-max-delay=10s", "--sync-period=1h"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	original := &rest.Config{Host: "https://example.com"}
+	config := tuning.RestConfig(original)
+	if config.QPS != 200 || config.Burst != 400 || config.Host != original.Host {
+		t.Errorf("expected QPS 200 and burst 400, got %v and %d", config.QPS, config.Burst)
+	}
+	if original.QPS != 0 {
+		t.Error("expected the original config to be left untouched")
+	}
+
+	opts := tuning.CacheOptions(cache.Options{})
+	if opts.SyncPeriod == nil || *opts.SyncPeriod != time.Hour {
+		t.Errorf("expected a sync period of 1h, got %v", opts.SyncPeriod)
+	}
+	if opts := DefaultTuning().CacheOptions(cache.Options{}); opts.SyncPeriod != nil {
+		t.Errorf("expected the default sync period to be kept, got %v", *opts.SyncPeriod)
+	}
+}
+
+func TestTuningRateLimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		tuning   Tuning
+		failures int
+		want     time.Duration
+	}{
+		{"first retry", Tuning{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}, 1, 10 * time.Millisecond},
+		{"doubles", Tuning{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}, 4, 80 * time.Millisecond},
+		{"capped", Tuning{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}, 20, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := tt.tuning.RateLimiter()
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0"}}
+			var got time.Duration
+			for range tt.failures {
+				got = limiter.When(req)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v after %d failures, got %v", tt.want, tt.failures, got)
+			}
+		})
+	}
+
+	if limiter := (Tuning{}).RateLimiter(); limiter != nil {
+		t.Error("expected the controller-runtime rate limiter without delays")
+	}
+}
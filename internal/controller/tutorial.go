@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+// AnnotationNextStep is the annotation holding the command a tutorial
+// player should run next. Only the suggested pod carries it.
+const AnnotationNextStep = "podsweeper.io/next-step"
+
+// Tutor guides new Kubernetes users through games in tutorial mode: every
+// move is explained in an Event and the next command to run is suggested
+// on a pod. It only touches namespaced objects so that the Gamemaster keeps
+// running with a namespaced Role.
+type Tutor struct {
+	client    client.Client
+	recorder  events.EventRecorder
+	namespace string
+}
+
+// NewTutor creates a Tutor emitting Events with the given recorder.
+func NewTutor(c client.Client, recorder events.EventRecorder, namespace string) *Tutor {
+	return &Tutor{client: c, recorder: recorder, namespace: namespace}
+}
+
+// Explain emits an Event explaining what the move on coords just did.
+// revealed is the number of cells the move revealed.
+func (t *Tutor) Explain(state *game.GameState, coords game.Coordinate, revealed int) {
+	reason, note := explainMove(state, coords, revealed, t.namespace)
+	t.recorder.Eventf(t.cellRef(coords), nil, corev1.EventTypeNormal, reason, "Reveal", "%s", note)
+}
+
+// Guide suggests the next move on a pod and in an Event. Suggestions left
+// on other pods by previous moves are removed.
+func (t *Tutor) Guide(ctx context.Context, state *game.GameState) error {
+	suggestion, ok := grid.SuggestMove(state)
+	if state.Status != game.StatusPlaying {
+		ok = false
+	}
+
+	pods := &corev1.PodList{}
+	if err := t.client.List(ctx, pods,
+		client.InNamespace(t.namespace),
+		client.MatchingLabels{LabelComponent: "cell"},
+	); err != nil {
+		return fmt.Errorf("failed to list cell pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, annotated := pod.Annotations[AnnotationNextStep]; !annotated {
+			continue
+		}
+		if ok && pod.Name == suggestion.Cell.PodName() {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		delete(pod.Annotations, AnnotationNextStep)
+		if err := t.client.Patch(ctx, pod, patch); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to clear next step from %s: %w", pod.Name, err)
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	command := suggestion.Cell.DeleteCommand(t.namespace)
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: t.namespace, Name: suggestion.Cell.PodName()}
+	if err := t.client.Get(ctx, key, pod); err != nil {
+		return fmt.Errorf("failed to get suggested pod: %w", err)
+	}
+	if pod.Annotations[AnnotationNextStep] != "try: "+command {
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[AnnotationNextStep] = "try: " + command
+		if err := t.client.Patch(ctx, pod, patch); err != nil {
+			return fmt.Errorf("failed to annotate suggested pod: %w", err)
+		}
+	}
+
+	t.recorder.Eventf(pod, nil, corev1.EventTypeNormal, "NextStep", "Suggest", "%s",
+		nextStepNote(suggestion, command))
+	log.FromContext(ctx).V(1).Info("tutorial next step", "command", command)
+	return nil
+}
+
+// cellRef references the pod of a cell, which is usually deleted by the time
+// the move is explained.
+func (t *Tutor) cellRef(coords game.Coordinate) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: coords.PodName(), Namespace: t.namespace}}
+}
+
+// explainMove returns the Event reason and note explaining a move.
+func explainMove(state *game.GameState, coords game.Coordinate, revealed int, namespace string) (reason, note string) {
+	pod := coords.PodName()
+	switch {
+	case state.Status == game.StatusLost:
+		return "MineHit", fmt.Sprintf("%s was a mine! Deleting it ended the game: the Gamemaster removed every game pod "+
+			"and created an explosion pod. See it with: kubectl logs explosion -n %s", pod, namespace)
+	case state.IsDefused(coords.X, coords.Y):
+		return "MineDefused", fmt.Sprintf("%s was a mine, but your team had a life left. It cost one life, and the pod %s "+
+			"now marks the mine. See it with: kubectl get pod %s -n %s", pod, coords.DefusedPodName(), coords.DefusedPodName(), namespace)
+	case state.Status == game.StatusWon:
+		return "GameWon", fmt.Sprintf("%s was the last safe cell: you cleared the board! "+
+			"Read your score with: kubectl logs victory -n %s", pod, namespace)
+	}
+
+	if hint, shown := state.HintAt(coords.X, coords.Y); shown && state.HotCold() {
+		return "HintRevealed", fmt.Sprintf("%s was safe. The Gamemaster replaced it with the pod %s, telling that "+
+			"the nearest mine is %s: hot is next to it, warm two cells away, cold farther. Read the hint with: "+
+			"kubectl get pod %s -n %s -o jsonpath='{.metadata.annotations.%s}'",
+			pod, coords.HintPodName(), hint, coords.HintPodName(), namespace, strings.ReplaceAll(AnnotationHint, ".", `\.`))
+	}
+	if hint := state.AdjacentMines(coords.X, coords.Y); hint > 0 {
+		return "HintRevealed", fmt.Sprintf("%s was safe. The Gamemaster replaced it with the pod %s, telling that "+
+			"%d of its neighbors are mines. Read the hint with: kubectl get pod %s -n %s -o jsonpath='{.metadata.annotations.%s}'",
+			pod, coords.HintPodName(), hint, coords.HintPodName(), namespace, strings.ReplaceAll(AnnotationHint, ".", `\.`))
+	}
+	return "AreaCleared", fmt.Sprintf("%s was safe with no mine around it, so the Gamemaster deleted its neighbors "+
+		"for you, revealing %d cells in total. See what is left with: kubectl get pods -n %s", pod, revealed, namespace)
+}
+
+// nextStepNote explains a suggested move.
+func nextStepNote(s grid.Suggestion, command string) string {
+	var b strings.Builder
+	switch {
+	case s.Opening:
+		fmt.Fprintf(&b, "Each pod is a cell of the board, and deleting a pod reveals it. Start with: %s", command)
+	case s.Proven:
+		fmt.Fprintf(&b, "The hints prove this pod is safe. Try: %s", command)
+	default:
+		fmt.Fprintf(&b, "No pod can be proven safe from the hints, so you would have to guess; "+
+			"tutorial mode picked a safe one. Try: %s", command)
+	}
+	if len(s.Mines) > 0 {
+		names := make([]string, len(s.Mines))
+		for i, c := range s.Mines {
+			names[i] = c.PodName()
+		}
+		fmt.Fprintf(&b, ". The hints also prove these pods hide mines, do not delete them: %s", strings.Join(names, ", "))
+	}
+	return b.String()
+}
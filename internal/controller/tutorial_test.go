@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+func newTutorialController(t *testing.T, state *game.GameState, objects ...client.Object) (*GameController, client.Client, *events.FakeRecorder) {
+	t.Helper()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(objects...).
+		Build()
+
+	store := game.NewMemoryStore()
+	if err := store.Save(context.Background(), state); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	recorder := events.NewFakeRecorder(10)
+	controller := NewGameController(fakeClient, GameControllerConfig{
+		Namespace: testNamespace,
+		Store:     store,
+		Recorder:  recorder,
+	})
+	return controller, fakeClient, recorder
+}
+
+func drainEvents(recorder *events.FakeRecorder) []string {
+	var got []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			got = append(got, e)
+		default:
+			return got
+		}
+	}
+}
+
+func TestTutorialGuidesNextStep(t *testing.T) {
+	ctx := context.Background()
+
+	state := createTestGameState(4)
+	state.Tutorial = true
+
+	stale := createTestPod("pod-3-3", testNamespace)
+	stale.Annotations = map[string]string{AnnotationNextStep: "try: kubectl delete pod pod-3-3 -n podsweeper-game"}
+	controller, c, recorder := newTutorialController(t, state, stale, createTestPod("pod-0-1", testNamespace))
+
+	// (0,0) shows 1 and nothing can be proven: the first hidden safe cell is suggested
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := c.Get(ctx, types.NamespacedName{Name: "pod-0-1", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("failed to get suggested pod: %v", err)
+	}
+	if got := pod.Annotations[AnnotationNextStep]; got != "try: kubectl delete pod pod-0-1 -n podsweeper-game" {
+		t.Errorf("unexpected next step annotation %q", got)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "pod-3-3", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("failed to get stale pod: %v", err)
+	}
+	if _, ok := pod.Annotations[AnnotationNextStep]; ok {
+		t.Error("expected the stale suggestion to be removed")
+	}
+
+	got := drainEvents(recorder)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %v", got)
+	}
+	if !strings.HasPrefix(got[0], "Normal HintRevealed pod-0-0 was safe") {
+		t.Errorf("unexpected explanation event %q", got[0])
+	}
+	if !strings.HasPrefix(got[1], "Normal NextStep No pod can be proven safe") {
+		t.Errorf("unexpected next step event %q", got[1])
+	}
+}
+
+func TestTutorialDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	controller, c, recorder := newTutorialController(t, createTestGameState(4), createTestPod("pod-0-1", testNamespace))
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+	if _, err := controller.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := c.Get(ctx, types.NamespacedName{Name: "pod-0-1", Namespace: testNamespace}, &pod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if _, ok := pod.Annotations[AnnotationNextStep]; ok {
+		t.Error("expected no suggestion outside tutorial mode")
+	}
+	if got := drainEvents(recorder); len(got) != 0 {
+		t.Errorf("expected no events outside tutorial mode, got %v", got)
+	}
+}
+
+func TestExplainMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*game.GameState) game.Coordinate
+		revealed int
+		reason   string
+		contains string
+	}{
+		{
+			name: "hint",
+			setup: func(s *game.GameState) game.Coordinate {
+				s.Reveal(0, 0)
+				return game.Coordinate{X: 0, Y: 0}
+			},
+			revealed: 1,
+			reason:   "HintRevealed",
+			contains: "kubectl get pod hint-0-0 -n podsweeper-game -o jsonpath='{.metadata.annotations.podsweeper\\.io/hint}'",
+		},
+		{
+			name: "area",
+			setup: func(s *game.GameState) game.Coordinate {
+				s.Reveal(3, 3)
+				return game.Coordinate{X: 3, Y: 3}
+			},
+			revealed: 7,
+			reason:   "AreaCleared",
+			contains: "revealing 7 cells",
+		},
+		{
+			name: "mine",
+			setup: func(s *game.GameState) game.Coordinate {
+				s.SetLost()
+				return game.Coordinate{X: 1, Y: 1}
+			},
+			reason:   "MineHit",
+			contains: "kubectl logs explosion -n podsweeper-game",
+		},
+		{
+			name: "victory",
+			setup: func(s *game.GameState) game.Coordinate {
+				s.SetWon()
+				return game.Coordinate{X: 3, Y: 3}
+			},
+			reason:   "GameWon",
+			contains: "kubectl logs victory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := createTestGameState(4)
+			coords := tt.setup(state)
+			reason, note := explainMove(state, coords, tt.revealed, testNamespace)
+			if reason != tt.reason {
+				t.Errorf("reason = %q, want %q", reason, tt.reason)
+			}
+			if !strings.Contains(note, tt.contains) {
+				t.Errorf("expected note to contain %q, got %q", tt.contains, note)
+			}
+		})
+	}
+}
+
+func TestNextStepNoteListsProvenMines(t *testing.T) {
+	note := nextStepNote(grid.Suggestion{
+		Cell:   game.Coordinate{X: 0, Y: 1},
+		Proven: true,
+		Mines:  []game.Coordinate{{X: 1, Y: 1}},
+	}, "kubectl delete pod pod-0-1 -n podsweeper-game")
+
+	if !strings.Contains(note, "Try: kubectl delete pod pod-0-1") || !strings.Contains(note, "do not delete them: pod-1-1") {
+		t.Errorf("unexpected note %q", note)
+	}
+}
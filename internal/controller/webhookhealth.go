@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+const (
+	// ConditionWebhookDegraded is set while cell pods are deleted without
+	// going through the attribution webhook.
+	ConditionWebhookDegraded = "WebhookDegraded"
+
+	// DefaultWebhookOutageWindow is how long an unvalidated deletion keeps
+	// the webhook degraded, unless the webhook admits a deletion again.
+	DefaultWebhookOutageWindow = 2 * time.Minute
+
+	// webhookFreezeRequeue is how often the frozen move of a game waits
+	// for the webhook to be healthy again.
+	webhookFreezeRequeue = 10 * time.Second
+)
+
+var (
+	// unvalidatedDeletions counts the cell pod deletions that bypassed the
+	// attribution webhook.
+	unvalidatedDeletions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "podsweeper_webhook_unvalidated_deletions_total",
+		Help: "Cell pod deletions played without being admitted by the attribution webhook.",
+	})
+
+	// webhookDegraded is 1 while the attribution webhook is degraded.
+	webhookDegraded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "podsweeper_webhook_degraded",
+		Help: "Whether cell pods were recently deleted without going through the attribution webhook.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(unvalidatedDeletions, webhookDegraded)
+}
+
+// WebhookHealth detects the outages of the attribution webhook. Under
+// failurePolicy Ignore, an unreachable webhook lets players delete cell
+// pods unattributed: the MoveAttributor records every deletion it admits,
+// and the GameController reports those it plays without a record as
+// unvalidated. While deletions are unvalidated the webhook is degraded:
+// their moves are recorded for game.UnattributedPlayer, and games from
+// FreezeLevel wait until the webhook admits a deletion again, or until no
+// deletion was unvalidated for Window. A nil WebhookHealth is never
+// degraded.
+//
+// Admissions are recorded in memory, so with several Gamemaster replicas
+// the deletions admitted by standby replicas look unvalidated to the
+// leader.
+type WebhookHealth struct {
+	// Window is how long an unvalidated deletion keeps the webhook
+	// degraded. Defaults to DefaultWebhookOutageWindow.
+	Window time.Duration
+
+	// FreezeLevel freezes the games of this level and higher while the
+	// webhook is degraded. Zero never freezes games.
+	FreezeLevel int
+
+	mu sync.Mutex
+	// admitted holds the cell pod deletions admitted and not played yet.
+	admitted map[game.Coordinate]time.Time
+	// unvalidated holds the recent deletions played without admission.
+	unvalidated map[game.Coordinate]time.Time
+	// lastAdmitted is when the webhook last admitted a deletion.
+	lastAdmitted time.Time
+
+	// now returns the current time, for tests.
+	now func() time.Time
+}
+
+// Admitted records the admission of the deletion of the pod of a cell.
+func (h *WebhookHealth) Admitted(c game.Coordinate) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.admitted == nil {
+		h.admitted = map[game.Coordinate]time.Time{}
+	}
+	now := h.clock()
+	h.admitted[c] = now
+	h.lastAdmitted = now
+	h.prune(now)
+	h.outage(now)
+}
+
+// Validate reports whether the deletion of the pod of a cell being played
+// was admitted by the webhook, and records it as unvalidated otherwise.
+// Deletions played again, such as frozen ones, are only counted once.
+func (h *WebhookHealth) Validate(c game.Coordinate) bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.clock()
+	if at, ok := h.admitted[c]; ok && now.Sub(at) <= h.window() {
+		delete(h.admitted, c)
+		return true
+	}
+	if h.unvalidated == nil {
+		h.unvalidated = map[game.Coordinate]time.Time{}
+	}
+	if _, ok := h.unvalidated[c]; !ok {
+		h.unvalidated[c] = now
+		unvalidatedDeletions.Inc()
+	}
+	h.prune(now)
+	h.outage(now)
+	return false
+}
+
+// Degraded reports whether deletions were recently played without being
+// admitted by the webhook.
+func (h *WebhookHealth) Degraded() bool {
+	if h == nil {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, degraded := h.outage(h.clock())
+	return degraded
+}
+
+// Frozen reports whether the games of a level wait for the webhook.
+func (h *WebhookHealth) Frozen(level int) bool {
+	return h != nil && h.FreezeLevel > 0 && level >= h.FreezeLevel && h.Degraded()
+}
+
+// Conditions returns the WebhookDegraded condition, none for a nil
+// WebhookHealth. NamespaceSummary publishes it on the game namespace.
+func (h *WebhookHealth) Conditions() []metav1.Condition {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	condition := metav1.Condition{
+		Type:    ConditionWebhookDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "DeletionsAdmitted",
+		Message: "cell pod deletions go through the attribution webhook",
+	}
+	if count, degraded := h.outage(h.clock()); degraded {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "UnvalidatedDeletions"
+		condition.Message = fmt.Sprintf("%d cell pods were deleted without going through the attribution webhook "+
+			"in the last %s, their moves are unattributed", count, h.window())
+	}
+	return []metav1.Condition{condition}
+}
+
+// outage returns the number of deletions unvalidated since the webhook
+// last admitted one, within the window, and whether there are any.
+func (h *WebhookHealth) outage(now time.Time) (int, bool) {
+	count := 0
+	for _, at := range h.unvalidated {
+		if at.After(h.lastAdmitted) && now.Sub(at) <= h.window() {
+			count++
+		}
+	}
+	if count > 0 {
+		webhookDegraded.Set(1)
+	} else {
+		webhookDegraded.Set(0)
+	}
+	return count, count > 0
+}
+
+// prune forgets the deletions older than the window.
+func (h *WebhookHealth) prune(now time.Time) {
+	for _, records := range []map[game.Coordinate]time.Time{h.admitted, h.unvalidated} {
+		for c, at := range records {
+			if now.Sub(at) > h.window() {
+				delete(records, c)
+			}
+		}
+	}
+}
+
+func (h *WebhookHealth) window() time.Duration {
+	if h.Window <= 0 {
+		return DefaultWebhookOutageWindow
+	}
+	return h.Window
+}
+
+func (h *WebhookHealth) clock() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestWebhookHealth(t *testing.T) {
+	now := time.Now()
+	h := &WebhookHealth{Window: time.Minute, FreezeLevel: 5, now: func() time.Time { return now }}
+	cell := game.Coordinate{X: 1, Y: 2}
+
+	h.Admitted(cell)
+	if !h.Validate(cell) || h.Degraded() {
+		t.Fatal("expected an admitted deletion to be validated")
+	}
+
+	// The webhook was bypassed
+	now = now.Add(time.Second)
+	if h.Validate(cell) {
+		t.Fatal("expected a deletion never admitted to be unvalidated")
+	}
+	if got := testutil.ToFloat64(webhookDegraded); got != 1 {
+		t.Errorf("expected the degraded gauge to be set by the deletion, got %v", got)
+	}
+	if !h.Degraded() {
+		t.Fatal("expected a deletion never admitted to degrade the webhook")
+	}
+	if !h.Frozen(5) || h.Frozen(4) {
+		t.Error("expected only the games from the freeze level to be frozen")
+	}
+	if c := meta.FindStatusCondition(h.Conditions(), ConditionWebhookDegraded); c == nil || c.Reason != "UnvalidatedDeletions" {
+		t.Errorf("expected the WebhookDegraded condition, got %+v", c)
+	}
+
+	// Played again while frozen, the deletion is only counted once
+	now = now.Add(50 * time.Second)
+	h.Validate(cell)
+	now = now.Add(20 * time.Second)
+	if h.Degraded() {
+		t.Error("expected the outage to end after the window")
+	}
+
+	// An admission ends the outage
+	other := game.Coordinate{X: 0, Y: 0}
+	h.Validate(other)
+	now = now.Add(time.Second)
+	h.Admitted(cell)
+	if got := testutil.ToFloat64(webhookDegraded); got != 0 {
+		t.Errorf("expected the degraded gauge to be cleared by the admission, got %v", got)
+	}
+	if h.Degraded() {
+		t.Error("expected an admission to end the outage")
+	}
+
+	var nilHealth *WebhookHealth
+	if !nilHealth.Validate(cell) || nilHealth.Degraded() || nilHealth.Frozen(9) || nilHealth.Conditions() != nil {
+		t.Error("a nil WebhookHealth should validate everything")
+	}
+}
+
+func TestNamespaceSummary_UpdateWebhookDegraded(t *testing.T) {
+	ctx := context.Background()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(ns).Build()
+	now := time.Now()
+	health := &WebhookHealth{Window: time.Minute, now: func() time.Time { return now }}
+	summary := &NamespaceSummary{Client: c, Store: game.NewMemoryStore(), Namespace: testNamespace, Webhook: health}
+
+	// Published even without a game
+	health.Validate(game.Coordinate{X: 1, Y: 2})
+	if err := summary.Update(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKey{Name: testNamespace}, ns)
+	if got := ns.Annotations[AnnotationWebhookDegraded]; got == "" {
+		t.Errorf("expected the webhook to be reported degraded, got %v", ns.Annotations)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := summary.Update(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	_ = c.Get(ctx, client.ObjectKey{Name: testNamespace}, ns)
+	if _, ok := ns.Annotations[AnnotationWebhookDegraded]; ok {
+		t.Errorf("expected the annotation to go away with the outage, got %v", ns.Annotations)
+	}
+}
+
+func TestMoveAttributor_RecordsAdmissions(t *testing.T) {
+	health := &WebhookHealth{}
+	attributor := &MoveAttributor{Claims: NewMoveClaims(), Namespace: testNamespace, Health: health}
+	gamemaster := "system:serviceaccount:" + testNamespace + ":podsweeper-gamemaster"
+	cell := game.Coordinate{X: 1, Y: 2}
+
+	attributor.Handle(context.Background(), deleteRequest(gamemaster, cell.PodName(), false))
+	if !health.Validate(cell) {
+		t.Error("expected the deletions of the Gamemaster to be admitted too")
+	}
+}
+
+func TestGameController_ReconcileUnvalidatedDeletion(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-0-0", Namespace: testNamespace}}
+
+	t.Run("unattributed", func(t *testing.T) {
+		store := game.NewMemoryStore()
+		_ = store.Save(ctx, createTestGameState(3))
+		controller := NewGameController(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(),
+			GameControllerConfig{Namespace: testNamespace, Store: store, Webhook: &WebhookHealth{}})
+
+		if _, err := controller.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile returned error: %v", err)
+		}
+		loaded, _ := store.Load(ctx)
+		if len(loaded.Moves) != 1 || loaded.Moves[0].By != game.UnattributedPlayer {
+			t.Errorf("expected the move recorded unattributed, got %+v", loaded.Moves)
+		}
+		if !controller.Webhook.Degraded() {
+			t.Error("expected the webhook to be degraded")
+		}
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		store := game.NewMemoryStore()
+		state := createTestGameState(3)
+		state.Level = 7
+		_ = store.Save(ctx, state)
+		controller := NewGameController(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(),
+			GameControllerConfig{Namespace: testNamespace, Store: store, Webhook: &WebhookHealth{FreezeLevel: 5}})
+
+		result, err := controller.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("Reconcile returned error: %v", err)
+		}
+		if result.RequeueAfter != webhookFreezeRequeue {
+			t.Errorf("expected the move to wait for the webhook, got %+v", result)
+		}
+		if loaded, _ := store.Load(ctx); loaded.IsRevealed(0, 0) {
+			t.Error("expected the frozen move not to be played")
+		}
+	})
+
+	t.Run("admitted", func(t *testing.T) {
+		store := game.NewMemoryStore()
+		_ = store.Save(ctx, createTestGameState(3))
+		health := &WebhookHealth{FreezeLevel: 1}
+		health.Admitted(game.Coordinate{X: 0, Y: 0})
+		controller := NewGameController(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(),
+			GameControllerConfig{Namespace: testNamespace, Store: store, Webhook: health})
+
+		if _, err := controller.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile returned error: %v", err)
+		}
+		loaded, _ := store.Load(ctx)
+		if len(loaded.Moves) != 1 || loaded.Moves[0].By == game.UnattributedPlayer || health.Degraded() {
+			t.Errorf("expected an admitted move, got %+v", loaded.Moves)
+		}
+	})
+}
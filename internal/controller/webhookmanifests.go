@@ -0,0 +1,167 @@
+package controller
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/certs"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+// AnnotationInjectCAFrom asks the cert-manager CA injector to keep the
+// caBundle of a webhook configuration in sync with the CA of the
+// Certificate it names, as <namespace>/<name>.
+const AnnotationInjectCAFrom = "cert-manager.io/inject-ca-from"
+
+// CertManagerGroupVersion is the API of the cert-manager Issuer and
+// Certificate of the webhook server.
+var CertManagerGroupVersion = schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
+
+// WebhookManifests configures the webhook server objects of
+// CertManagerWebhookObjects.
+type WebhookManifests struct {
+	// Namespace is the game namespace, whose pods the webhooks admit.
+	Namespace string
+
+	// ServiceNamespace is the namespace of the webhook Service and of the
+	// Certificate, the namespace the Gamemaster runs in.
+	ServiceNamespace string
+
+	// Service is the webhook Service the certificate is issued for.
+	// Defaults to rbac.WebhookName.
+	Service string
+
+	// ClusterIssuer issues the certificate. Empty issues it with a
+	// self-signed Issuer in ServiceNamespace.
+	ClusterIssuer string
+
+	// MoveAttribution, HintGuard and HintScrambler register the webhooks
+	// of the Gamemaster flags of the same name.
+	MoveAttribution bool
+	HintGuard       bool
+	HintScrambler   bool
+}
+
+// CertManagerWebhookObjects returns the cert-manager Certificate of the
+// webhook server, issuing its serving certificate into the
+// rbac.WebhookName Secret to mount in --webhook-cert-dir, its self-signed
+// Issuer unless cfg.ClusterIssuer is set, and the Validating and
+// MutatingWebhookConfigurations of the enabled webhooks, named
+// rbac.WebhookName, whose caBundle the cert-manager CA injector fills. The
+// webhook server reloads the certificates cert-manager renews, see
+// --webhook-cert-dir. Configurations without webhooks are omitted.
+func CertManagerWebhookObjects(cfg WebhookManifests, labels map[string]string) []client.Object {
+	service := cfg.Service
+	if service == "" {
+		service = rbac.WebhookName
+	}
+
+	issuerRef := map[string]any{"kind": "Issuer", "name": rbac.WebhookName}
+	var objs []client.Object
+	if cfg.ClusterIssuer == "" {
+		issuer := certManagerObject("Issuer", cfg.ServiceNamespace, labels)
+		_ = unstructured.SetNestedMap(issuer.Object, map[string]any{}, "spec", "selfSigned")
+		objs = append(objs, issuer)
+	} else {
+		issuerRef = map[string]any{"kind": "ClusterIssuer", "name": cfg.ClusterIssuer}
+	}
+	certificate := certManagerObject("Certificate", cfg.ServiceNamespace, labels)
+	dnsNames := []any{}
+	for _, name := range certs.ServiceDNSNames(service, cfg.ServiceNamespace) {
+		dnsNames = append(dnsNames, name)
+	}
+	_ = unstructured.SetNestedMap(certificate.Object, map[string]any{
+		"secretName": rbac.WebhookName,
+		"dnsNames":   dnsNames,
+		"issuerRef":  issuerRef,
+	}, "spec")
+	objs = append(objs, certificate)
+
+	meta := metav1.ObjectMeta{
+		Name:        rbac.WebhookName,
+		Labels:      labels,
+		Annotations: map[string]string{AnnotationInjectCAFrom: cfg.ServiceNamespace + "/" + rbac.WebhookName},
+	}
+	clientConfig := func(path string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{
+			Namespace: cfg.ServiceNamespace,
+			Name:      service,
+			Path:      ptr.To(path),
+		}}
+	}
+	namespaceSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{corev1.LabelMetadataName: cfg.Namespace},
+	}
+	podRules := func(operation admissionregistrationv1.OperationType) []admissionregistrationv1.RuleWithOperations {
+		return []admissionregistrationv1.RuleWithOperations{{
+			Operations: []admissionregistrationv1.OperationType{operation},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"pods"},
+				Scope:       ptr.To(admissionregistrationv1.NamespacedScope),
+			},
+		}}
+	}
+
+	var validating []admissionregistrationv1.ValidatingWebhook
+	if cfg.MoveAttribution {
+		// Moves are only attributed: a missing Gamemaster never blocks
+		// players, and the outage is detected by the WebhookHealth
+		validating = append(validating, admissionregistrationv1.ValidatingWebhook{
+			Name:                    "attribute-moves.podsweeper.io",
+			ClientConfig:            clientConfig(MoveAttributorPath),
+			Rules:                   podRules(admissionregistrationv1.Delete),
+			NamespaceSelector:       namespaceSelector,
+			FailurePolicy:           ptr.To(admissionregistrationv1.Ignore),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNoneOnDryRun),
+			AdmissionReviewVersions: []string{"v1"},
+		})
+	}
+	if cfg.HintGuard {
+		validating = append(validating, admissionregistrationv1.ValidatingWebhook{
+			Name:                    "validate-hint-pods.podsweeper.io",
+			ClientConfig:            clientConfig(HintGuardPath),
+			Rules:                   podRules(admissionregistrationv1.Delete),
+			NamespaceSelector:       namespaceSelector,
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		})
+	}
+	if len(validating) > 0 {
+		objs = append(objs, &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: meta, Webhooks: validating})
+	}
+	if cfg.HintScrambler {
+		objs = append(objs, &admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: meta,
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{
+				Name:                    "mutate-hint-pods.podsweeper.io",
+				ClientConfig:            clientConfig(HintScramblerPath),
+				Rules:                   podRules(admissionregistrationv1.Create),
+				NamespaceSelector:       namespaceSelector,
+				FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+				SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+				AdmissionReviewVersions: []string{"v1"},
+			}},
+		})
+	}
+	return objs
+}
+
+// certManagerObject returns an empty cert-manager object of kind named
+// rbac.WebhookName. cert-manager types are not vendored, the objects are
+// unstructured.
+func certManagerObject(kind, namespace string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(CertManagerGroupVersion.WithKind(kind))
+	obj.SetName(rbac.WebhookName)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(labels)
+	return obj
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+func TestCertManagerWebhookObjects(t *testing.T) {
+	objs := CertManagerWebhookObjects(WebhookManifests{
+		Namespace:        testNamespace,
+		ServiceNamespace: "podsweeper-system",
+		MoveAttribution:  true,
+		HintGuard:        true,
+	}, nil)
+	if len(objs) != 3 {
+		t.Fatalf("expected an Issuer, a Certificate and a ValidatingWebhookConfiguration, got %d objects", len(objs))
+	}
+
+	issuer := objs[0].(*unstructured.Unstructured)
+	if issuer.GetKind() != "Issuer" || issuer.GetNamespace() != "podsweeper-system" {
+		t.Errorf("expected a self-signed Issuer in the Gamemaster namespace, got %v", issuer.Object)
+	}
+	certificate := objs[1].(*unstructured.Unstructured)
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	if certificate.GetKind() != "Certificate" || secretName != rbac.WebhookName ||
+		len(dnsNames) == 0 || dnsNames[0] != rbac.WebhookName+".podsweeper-system.svc" {
+		t.Errorf("expected a Certificate for the webhook Service, got %v", certificate.Object)
+	}
+
+	validating := objs[2].(*admissionregistrationv1.ValidatingWebhookConfiguration)
+	if got := validating.Annotations[AnnotationInjectCAFrom]; got != "podsweeper-system/"+rbac.WebhookName {
+		t.Errorf("expected the CA injected from the Certificate, got %q", got)
+	}
+	if len(validating.Webhooks) != 2 {
+		t.Fatalf("expected the attribution and hint guard webhooks, got %d", len(validating.Webhooks))
+	}
+	attribution := validating.Webhooks[0]
+	if *attribution.ClientConfig.Service.Path != MoveAttributorPath ||
+		*attribution.FailurePolicy != admissionregistrationv1.Ignore {
+		t.Errorf("expected the attribution webhook to ignore failures, got %+v", attribution)
+	}
+	if got := attribution.NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"]; got != testNamespace {
+		t.Errorf("expected the webhooks restricted to the game namespace, got %q", got)
+	}
+}
+
+func TestCertManagerWebhookObjects_ClusterIssuer(t *testing.T) {
+	objs := CertManagerWebhookObjects(WebhookManifests{
+		Namespace:        testNamespace,
+		ServiceNamespace: "podsweeper-system",
+		ClusterIssuer:    "internal-ca",
+		HintScrambler:    true,
+	}, nil)
+	if len(objs) != 2 {
+		t.Fatalf("expected a Certificate and a MutatingWebhookConfiguration, got %d objects", len(objs))
+	}
+	certificate := objs[0].(*unstructured.Unstructured)
+	if kind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind"); kind != "ClusterIssuer" {
+		t.Errorf("expected the Certificate issued by the ClusterIssuer, got %v", certificate.Object)
+	}
+	mutating := objs[1].(*admissionregistrationv1.MutatingWebhookConfiguration)
+	if *mutating.Webhooks[0].ClientConfig.Service.Path != HintScramblerPath ||
+		mutating.Annotations[AnnotationInjectCAFrom] == "" {
+		t.Errorf("expected the hint scrambler webhook with its CA injected, got %+v", mutating)
+	}
+}
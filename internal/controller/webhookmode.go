@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WebhookMode is whether the admission webhooks enforce their decisions.
+type WebhookMode string
+
+const (
+	// WebhookEnforce denies and mutates admission requests.
+	WebhookEnforce WebhookMode = "enforce"
+
+	// WebhookShadow only logs and counts what the webhooks would have
+	// denied or mutated, and allows requests unchanged, so operators can
+	// try the obstacles of higher levels on an existing cluster.
+	WebhookShadow WebhookMode = "shadow"
+)
+
+// The decisions counted by podsweeper_webhook_shadow_decisions_total.
+const (
+	ShadowDenied  = "denied"
+	ShadowMutated = "mutated"
+	ShadowErrored = "errored"
+)
+
+// shadowDecisions counts the decisions the webhooks only logged in shadow
+// mode.
+var shadowDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "podsweeper_webhook_shadow_decisions_total",
+	Help: "Admission decisions the webhooks would have enforced outside of shadow mode, by webhook and decision.",
+}, []string{"webhook", "decision"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(shadowDecisions)
+}
+
+// ParseWebhookMode parses a WebhookMode.
+func ParseWebhookMode(s string) (WebhookMode, error) {
+	switch mode := WebhookMode(s); mode {
+	case WebhookEnforce, WebhookShadow:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid webhook mode %q: must be %s or %s", s, WebhookEnforce, WebhookShadow)
+	}
+}
+
+// Handler returns handler, served as the webhook name, in the mode.
+func (m WebhookMode) Handler(name string, handler admission.Handler) admission.Handler {
+	if m == WebhookShadow {
+		return &ShadowHandler{Name: name, Handler: handler}
+	}
+	return handler
+}
+
+// ShadowHandler runs a webhook in shadow mode: the decisions of Handler
+// are logged and counted in podsweeper_webhook_shadow_decisions_total,
+// but every request is allowed unchanged, with a warning for kubectl
+// users when it would have been denied.
+type ShadowHandler struct {
+	// Name names the webhook in logs and metrics, such as its path.
+	Name string
+
+	// Handler is the webhook run in shadow mode.
+	Handler admission.Handler
+}
+
+// Handle allows an admission request, after logging the decision of
+// Handler.
+func (h *ShadowHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := h.Handler.Handle(ctx, req)
+	logger := log.FromContext(ctx).WithValues("webhook", h.Name, "operation", req.Operation,
+		"namespace", req.Namespace, "name", req.Name, "user", req.UserInfo.Username)
+
+	var decision, reason string
+	switch {
+	case resp.Allowed && len(resp.Patches) == 0 && resp.Patch == nil:
+		return resp
+	case resp.Allowed:
+		decision = ShadowMutated
+	case resp.Result != nil && resp.Result.Code >= 500:
+		decision, reason = ShadowErrored, resp.Result.Message
+	default:
+		decision = ShadowDenied
+		if resp.Result != nil {
+			reason = resp.Result.Message
+		}
+	}
+	shadowDecisions.WithLabelValues(h.Name, decision).Inc()
+	logger.Info("shadow mode: allowed a request the webhook would have "+decision, "reason", reason)
+
+	allowed := admission.Allowed("shadow mode")
+	if decision == ShadowDenied {
+		allowed = allowed.WithWarnings("shadow mode: would have been denied: " + reason)
+	}
+	return allowed
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestParseWebhookMode(t *testing.T) {
+	for _, s := range []string{"enforce", "shadow"} {
+		if mode, err := ParseWebhookMode(s); err != nil || string(mode) != s {
+			t.Errorf("ParseWebhookMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseWebhookMode("audit"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestShadowHandler(t *testing.T) {
+	ctx := context.Background()
+	state := createTestGameState(3)
+	state.Level = HintGuardLevel
+	state.Reveal(0, 0)
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, state)
+	guard := &HintGuard{Store: store, Namespace: testNamespace}
+	req := deleteRequest("bob", game.Coordinate{X: 0, Y: 0}.HintPodName(), false)
+
+	if resp := WebhookEnforce.Handler(HintGuardPath, guard).Handle(ctx, req); resp.Allowed {
+		t.Fatal("expected the hint pod deletion denied in enforce mode")
+	}
+
+	denied := shadowDecisions.WithLabelValues(HintGuardPath, ShadowDenied)
+	before := testutil.ToFloat64(denied)
+	resp := WebhookShadow.Handler(HintGuardPath, guard).Handle(ctx, req)
+	if !resp.Allowed || len(resp.Warnings) != 1 {
+		t.Errorf("expected the deletion allowed with a warning in shadow mode, got %+v", resp)
+	}
+	if got := testutil.ToFloat64(denied) - before; got != 1 {
+		t.Errorf("expected one shadow denial counted, got %v", got)
+	}
+
+	// Allowed requests are passed through without being counted
+	allowed := deleteRequest("bob", "pod-0-1", false)
+	if resp := WebhookShadow.Handler(HintGuardPath, guard).Handle(ctx, allowed); !resp.Allowed || len(resp.Warnings) != 0 {
+		t.Errorf("expected the cell pod deletion allowed without warning, got %+v", resp)
+	}
+	if got := testutil.ToFloat64(denied) - before; got != 1 {
+		t.Errorf("expected allowed requests not counted, got %v denials", got)
+	}
+
+	// Mutations are dropped
+	mutating := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.PatchResponseFromRaw([]byte(`{"a":1}`), []byte(`{"a":2}`))
+	})
+	if resp := WebhookShadow.Handler(HintScramblerPath, mutating).Handle(ctx, req); !resp.Allowed || len(resp.Patches) != 0 {
+		t.Errorf("expected the request allowed unchanged in shadow mode, got %+v", resp)
+	}
+}
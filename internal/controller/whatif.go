@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// WhatIf outcomes, only disclosed once the game has ended.
+const (
+	WhatIfMine  = "mine"
+	WhatIfHint  = "hint"
+	WhatIfEmpty = "empty"
+)
+
+// WhatIfCell is a cell a simulated move would reveal.
+type WhatIfCell struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	// Hint is the value of the cell, only disclosed once the game has ended.
+	Hint *int `json:"hint,omitempty"`
+}
+
+// WhatIf is what deleting a cell pod would do.
+type WhatIf struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+
+	// Reason explains why the move would do nothing, such as a cell
+	// already revealed. Empty for a playable move.
+	Reason string `json:"reason,omitempty"`
+
+	// Revealed are the cells the move would reveal. A mine or a hint only
+	// reveal the clicked cell, so while playing the shape of the
+	// propagation never tells them apart.
+	Revealed []WhatIfCell `json:"revealed,omitempty"`
+
+	// Disclosed reports whether the outcome, hints and victory are shown,
+	// which only happens once the game has ended.
+	Disclosed bool `json:"disclosed"`
+
+	// Outcome is WhatIfMine, WhatIfHint or WhatIfEmpty when disclosed.
+	Outcome string `json:"outcome,omitempty"`
+
+	// Victory reports whether the move would win the game, when disclosed.
+	Victory bool `json:"victory,omitempty"`
+}
+
+// Simulate answers what deleting the pod of cell c would do, running the
+// reveal logic against a clone of state. state is never modified. While
+// the game is playing, mines and hint values are not disclosed: only the
+// shape of the propagation is.
+func Simulate(state *game.GameState, c game.Coordinate) WhatIf {
+	result := WhatIf{X: c.X, Y: c.Y, Disclosed: state.Status != game.StatusPlaying}
+	switch {
+	case !state.IsValidCoordinate(c.X, c.Y):
+		result.Reason = "out of bounds"
+		return result
+	case state.IsRevealed(c.X, c.Y):
+		result.Reason = "already revealed"
+		return result
+	case state.IsDefused(c.X, c.Y):
+		result.Reason = "mine already defused"
+		return result
+	}
+
+	clone := state.Clone()
+	var revealed []game.Coordinate
+	switch {
+	case clone.IsMine(c.X, c.Y):
+		result.Outcome = WhatIfMine
+		revealed = []game.Coordinate{c}
+	case clone.AdjacentMines(c.X, c.Y) > 0:
+		result.Outcome = WhatIfHint
+		revealed = []game.Coordinate{c}
+	default:
+		result.Outcome = WhatIfEmpty
+		empty, boundary := floodFill(clone, c)
+		revealed = append(empty, boundary...)
+	}
+	sort.Slice(revealed, func(i, j int) bool {
+		if revealed[i].Y != revealed[j].Y {
+			return revealed[i].Y < revealed[j].Y
+		}
+		return revealed[i].X < revealed[j].X
+	})
+
+	for _, rc := range revealed {
+		cell := WhatIfCell{X: rc.X, Y: rc.Y}
+		if result.Disclosed && !clone.IsMine(rc.X, rc.Y) {
+			hint := clone.AdjacentMines(rc.X, rc.Y)
+			cell.Hint = &hint
+		}
+		result.Revealed = append(result.Revealed, cell)
+		clone.Reveal(rc.X, rc.Y)
+	}
+	result.Victory = result.Outcome != WhatIfMine && clone.CheckVictory()
+
+	if !result.Disclosed {
+		result.Outcome = ""
+		result.Victory = false
+	}
+	return result
+}
+
+// WhatIfHandler serves Simulate for the current game at ?x=X&y=Y. It is
+// read-only.
+func WhatIfHandler(store game.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		x, errX := strconv.Atoi(r.URL.Query().Get("x"))
+		y, errY := strconv.Atoi(r.URL.Query().Get("y"))
+		if errX != nil || errY != nil {
+			http.Error(w, "x and y must be integers", http.StatusBadRequest)
+			return
+		}
+
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		if state == nil {
+			http.Error(w, "no game running", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Simulate(state, game.Coordinate{X: x, Y: y}))
+	})
+}
+
+// String describes the simulated move, one line per revealed cell.
+func (w WhatIf) String() string {
+	pod := game.Coordinate{X: w.X, Y: w.Y}.PodName()
+	if w.Reason != "" {
+		return fmt.Sprintf("deleting %s would do nothing: %s", pod, w.Reason)
+	}
+	s := fmt.Sprintf("deleting %s would reveal %d cell(s)", pod, len(w.Revealed))
+	if w.Disclosed {
+		s += fmt.Sprintf(" (%s", w.Outcome)
+		if w.Victory {
+			s += ", victory"
+		}
+		s += ")"
+	}
+	for _, c := range w.Revealed {
+		s += fmt.Sprintf("\n  (%d, %d)", c.X, c.Y)
+		if c.Hint != nil {
+			s += fmt.Sprintf(": %d", *c.Hint)
+		}
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestSimulate(t *testing.T) {
+	// Mine at (3,0) on a 4x4 board: (0,3) floods most of the board
+	newState := func() *game.GameState {
+		state := game.NewGameState(4, 1)
+		state.SetMine(3, 0)
+		return state
+	}
+
+	tests := []struct {
+		name         string
+		coords       game.Coordinate
+		ended        bool
+		wantRevealed int
+		wantOutcome  string
+		wantVictory  bool
+		wantReason   bool
+	}{
+		{name: "mine while playing", coords: game.Coordinate{X: 3, Y: 0}, wantRevealed: 1},
+		{name: "hint while playing", coords: game.Coordinate{X: 2, Y: 0}, wantRevealed: 1},
+		{name: "flood while playing", coords: game.Coordinate{X: 0, Y: 3}, wantRevealed: 15},
+		{name: "out of bounds", coords: game.Coordinate{X: 4, Y: 0}, wantReason: true},
+		{name: "mine once ended", coords: game.Coordinate{X: 3, Y: 0}, ended: true, wantRevealed: 1, wantOutcome: WhatIfMine},
+		{name: "hint once ended", coords: game.Coordinate{X: 2, Y: 0}, ended: true, wantRevealed: 1, wantOutcome: WhatIfHint},
+		{name: "flood once ended", coords: game.Coordinate{X: 0, Y: 3}, ended: true, wantRevealed: 15,
+			wantOutcome: WhatIfEmpty, wantVictory: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newState()
+			if tt.ended {
+				state.SetLost()
+			}
+			before := state.ToBoardString()
+
+			got := Simulate(state, tt.coords)
+			if (got.Reason != "") != tt.wantReason {
+				t.Errorf("expected a reason %v, got %q", tt.wantReason, got.Reason)
+			}
+			if len(got.Revealed) != tt.wantRevealed {
+				t.Errorf("expected %d revealed cells, got %d", tt.wantRevealed, len(got.Revealed))
+			}
+			if got.Outcome != tt.wantOutcome || got.Victory != tt.wantVictory {
+				t.Errorf("expected outcome %q victory %v, got %q %v", tt.wantOutcome, tt.wantVictory, got.Outcome, got.Victory)
+			}
+			for _, c := range got.Revealed {
+				if (c.Hint != nil) != (tt.ended && tt.wantOutcome != WhatIfMine) {
+					t.Errorf("expected hints to be disclosed only once ended, got %+v", c)
+				}
+			}
+			if state.ToBoardString() != before {
+				t.Error("expected the simulation not to modify the game")
+			}
+		})
+	}
+}
+
+func TestSimulate_AlreadyRevealed(t *testing.T) {
+	state := createTestGameState(3)
+	state.Reveal(0, 0)
+	if got := Simulate(state, game.Coordinate{X: 0, Y: 0}); got.Reason == "" || len(got.Revealed) != 0 {
+		t.Errorf("expected a revealed cell to do nothing, got %+v", got)
+	}
+}
+
+func TestWhatIfHandler(t *testing.T) {
+	store := game.NewMemoryStore()
+	_ = store.Save(context.Background(), createTestGameState(3))
+	handler := WhatIfHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/whatif?x=a&y=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid coordinates, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/whatif?x=1&y=1", nil))
+	var got WhatIf
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	if got.Disclosed || got.Outcome != "" || len(got.Revealed) != 1 {
+		t.Errorf("expected the mine not to be disclosed, got %+v", got)
+	}
+}
@@ -0,0 +1,248 @@
+// Package analysis reviews finished games with the solver: it replays the
+// moves of a game on its pristine board and tells, for each move, whether
+// the cell could be proven safe from the hints revealed so far, or was a
+// guess, and whether guessing was needed at all. Games played with shifting
+// mines are replayed on their final layout, so their analysis is
+// approximate.
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+// Kind classifies a move.
+type Kind string
+
+const (
+	// KindOpening is the first move, which nothing can be deduced for.
+	KindOpening Kind = "opening"
+	// KindDeduced is a move on a cell proven safe by the revealed hints.
+	KindDeduced Kind = "deduced"
+	// KindForcedGuess is a guess made when no cell could be proven safe.
+	KindForcedGuess Kind = "forced-guess"
+	// KindUnnecessaryGuess is a guess made while other cells were proven
+	// safe.
+	KindUnnecessaryGuess Kind = "unnecessary-guess"
+	// KindBlunder is a move on a cell proven to be a mine.
+	KindBlunder Kind = "blunder"
+)
+
+// Move is the analysis of a move of the game.
+type Move struct {
+	Seq     int       `json:"seq"`
+	X       int       `json:"x"`
+	Y       int       `json:"y"`
+	Player  string    `json:"player,omitempty"`
+	At      time.Time `json:"at,omitzero"`
+	Outcome string    `json:"outcome"`
+	Kind    Kind      `json:"kind"`
+
+	// FiftyFifty is true for a guess on one of two cells sharing a single
+	// mine, which no reasoning could help with.
+	FiftyFifty bool `json:"fiftyFifty,omitempty"`
+
+	// ProvenSafe is the number of cells that could be proven safe before
+	// the move.
+	ProvenSafe int `json:"provenSafe"`
+
+	// Revealed is the number of cells the move revealed, flood fill
+	// included.
+	Revealed int `json:"revealed"`
+}
+
+// Report is the analysis of a finished game.
+type Report struct {
+	GameID string          `json:"gameId"`
+	Status game.GameStatus `json:"status"`
+
+	// Moves is the number of moves played.
+	Moves int `json:"moves"`
+
+	Deduced            int `json:"deduced"`
+	ForcedGuesses      int `json:"forcedGuesses"`
+	UnnecessaryGuesses int `json:"unnecessaryGuesses"`
+	FiftyFifties       int `json:"fiftyFifties"`
+	Blunders           int `json:"blunders"`
+
+	// ThreeBV is the minimum number of clicks clearing the board, see
+	// grid.ThreeBV.
+	ThreeBV int `json:"threeBV"`
+
+	// Efficiency is the 3BV of the board per move, as a percentage, for
+	// won games only: 100% is a perfect game.
+	Efficiency float64 `json:"efficiency,omitempty"`
+
+	// Timeline is the analysis of each move, in the order they were played.
+	Timeline []Move `json:"timeline"`
+}
+
+// Analyze replays the moves of state on its pristine board with the
+// solver. It reads the mines, so it must only be run on finished games.
+func Analyze(state *game.GameState) Report {
+	pristine := state.Clone()
+	w, h := pristine.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			pristine.Cells[x][y] = game.Cell{Mine: pristine.Cells[x][y].Mine}
+		}
+	}
+
+	report := Report{
+		GameID:   state.ID(),
+		Status:   state.Status,
+		ThreeBV:  grid.ThreeBV(pristine),
+		Timeline: []Move{},
+	}
+	solver := grid.NewSolver(pristine)
+	for i, m := range results.MovesOf(results.Game{State: state}) {
+		c := game.Coordinate{X: m.X, Y: m.Y}
+		move := Move{Seq: m.Seq, X: m.X, Y: m.Y, Player: m.Player, At: m.At, Outcome: m.Outcome}
+
+		safe := provenSafe(solver)
+		move.ProvenSafe = len(safe)
+		switch {
+		case i == 0:
+			move.Kind = KindOpening
+		case safe[c]:
+			move.Kind = KindDeduced
+			report.Deduced++
+		case solver.IsFlagged(c.X, c.Y):
+			move.Kind = KindBlunder
+			report.Blunders++
+		case len(safe) == 0:
+			move.Kind = KindForcedGuess
+			report.ForcedGuesses++
+		default:
+			move.Kind = KindUnnecessaryGuess
+			report.UnnecessaryGuesses++
+		}
+		if move.Kind != KindOpening && move.Kind != KindDeduced && solver.FiftyFifty(c) {
+			move.FiftyFifty = true
+			report.FiftyFifties++
+		}
+
+		if m.Outcome == results.OutcomeSafe {
+			before := revealed(solver.State())
+			solver.Reveal(c)
+			move.Revealed = revealed(solver.State()) - before
+		} else {
+			solver.Flag(c)
+		}
+		report.Timeline = append(report.Timeline, move)
+	}
+
+	report.Moves = len(report.Timeline)
+	if state.Status == game.StatusWon && report.Moves > 0 {
+		report.Efficiency = float64(100*report.ThreeBV) / float64(report.Moves)
+	}
+	return report
+}
+
+// provenSafe returns the hidden cells the solver can prove safe, deducing
+// mines until nothing new is found.
+func provenSafe(solver *grid.Solver) map[game.Coordinate]bool {
+	safe := make(map[game.Coordinate]bool)
+	for {
+		cells, mines := solver.Deduce()
+		for _, c := range cells {
+			safe[c] = true
+		}
+		if len(mines) == 0 {
+			return safe
+		}
+	}
+}
+
+// revealed counts the revealed cells of state.
+func revealed(state *game.GameState) int {
+	n := 0
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if state.IsRevealed(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Summary sums the report up in a line, such as "12 moves: 9 deduced, 1
+// forced guess, 1 unnecessary guess, 0 blunders, 1 50/50. 3BV 10,
+// efficiency 83%".
+func (r Report) Summary() string {
+	summary := fmt.Sprintf("%d moves: %d deduced, %d forced %s, %d unnecessary %s, %d %s, %d 50/50. 3BV %d",
+		r.Moves, r.Deduced, r.ForcedGuesses, plural(r.ForcedGuesses, "guess", "guesses"),
+		r.UnnecessaryGuesses, plural(r.UnnecessaryGuesses, "guess", "guesses"),
+		r.Blunders, plural(r.Blunders, "blunder", "blunders"), r.FiftyFifties, r.ThreeBV)
+	if r.Efficiency > 0 {
+		summary += fmt.Sprintf(", efficiency %.0f%%", r.Efficiency)
+	}
+	return summary
+}
+
+// String shows the summary and the timeline, one move per line.
+func (r Report) String() string {
+	var b strings.Builder
+	b.WriteString(r.Summary())
+	b.WriteString("\n")
+	for _, m := range r.Timeline {
+		fmt.Fprintf(&b, "%3d. %s %s", m.Seq, game.Coordinate{X: m.X, Y: m.Y}, m.Kind)
+		if m.FiftyFifty {
+			b.WriteString(" (50/50)")
+		}
+		if m.Outcome != results.OutcomeSafe {
+			fmt.Fprintf(&b, ", %s", m.Outcome)
+		} else {
+			fmt.Fprintf(&b, ", revealed %d", m.Revealed)
+		}
+		if m.Player != "" {
+			fmt.Fprintf(&b, " by %s", m.Player)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Handler serves the analysis of the game of store as JSON once it ended.
+// Games in progress are refused, since the analysis tells where the mines
+// are.
+func Handler(store game.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, err := store.Load(r.Context())
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to load game state")
+			http.Error(w, "failed to load game", http.StatusInternalServerError)
+			return
+		}
+		if state == nil {
+			http.Error(w, "no game running", http.StatusNotFound)
+			return
+		}
+		if state.Status == game.StatusPlaying {
+			http.Error(w, "the game is still in progress", http.StatusConflict)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Analyze(state))
+	})
+}
+
+// plural returns one if n is 1, many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
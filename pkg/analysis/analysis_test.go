@@ -0,0 +1,128 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// play reveals the cells of state in order, one second apart.
+func play(state *game.GameState, moves ...game.Coordinate) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i, c := range moves {
+		state.RevealWith(c.X, c.Y, game.RevealInfo{By: "alice", At: start.Add(time.Duration(i) * time.Second)})
+	}
+}
+
+// newTestBoard returns a 4x2 board with mines at (0,0), (1,0) and (3,1):
+//
+//	M M 2 1
+//	2 2 2 M
+func newTestBoard() *game.GameState {
+	state := game.NewRectGameState(4, 2, 1)
+	state.SetMine(0, 0)
+	state.SetMine(1, 0)
+	state.SetMine(3, 1)
+	return state
+}
+
+func kinds(r Report) []Kind {
+	var kinds []Kind
+	for _, m := range r.Timeline {
+		kinds = append(kinds, m.Kind)
+	}
+	return kinds
+}
+
+func TestAnalyze(t *testing.T) {
+	state := newTestBoard()
+	// (1,1) can't be proven safe from the opening, then (2,0) and (2,1)
+	// can, but (3,0) is played first
+	play(state,
+		game.Coordinate{X: 0, Y: 1}, game.Coordinate{X: 1, Y: 1}, game.Coordinate{X: 3, Y: 0},
+		game.Coordinate{X: 2, Y: 0}, game.Coordinate{X: 2, Y: 1})
+	state.SetWon()
+
+	report := Analyze(state)
+	want := []Kind{KindOpening, KindForcedGuess, KindUnnecessaryGuess, KindDeduced, KindDeduced}
+	if got := kinds(report); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if report.Moves != 5 || report.Deduced != 2 || report.ForcedGuesses != 1 || report.UnnecessaryGuesses != 1 {
+		t.Errorf("unexpected counts: %s", report.Summary())
+	}
+	if report.ThreeBV != 5 || report.Efficiency != 100 {
+		t.Errorf("expected 3BV 5 and 100%% efficiency, got %d and %.0f%%", report.ThreeBV, report.Efficiency)
+	}
+	if report.Timeline[2].ProvenSafe != 2 {
+		t.Errorf("expected 2 cells proven safe before (3,0), got %d", report.Timeline[2].ProvenSafe)
+	}
+}
+
+func TestAnalyze_Blunder(t *testing.T) {
+	state := newTestBoard()
+	play(state, game.Coordinate{X: 0, Y: 1}, game.Coordinate{X: 1, Y: 1}, game.Coordinate{X: 0, Y: 0})
+	state.SetLost()
+
+	report := Analyze(state)
+	if report.Blunders != 1 || report.Timeline[2].Kind != KindBlunder || report.Timeline[2].Outcome != "mine" {
+		t.Errorf("expected clicking a proven mine to be a blunder, got %+v", report.Timeline)
+	}
+	if report.Efficiency != 0 {
+		t.Errorf("expected no efficiency for a lost game, got %.0f%%", report.Efficiency)
+	}
+}
+
+func TestAnalyze_FiftyFifty(t *testing.T) {
+	// Once the opening cleared the bottom, one of (0,0) and (1,0) is a mine
+	state := game.NewRectGameState(2, 4, 1)
+	state.SetMine(0, 0)
+	play(state, game.Coordinate{X: 1, Y: 3}, game.Coordinate{X: 1, Y: 0})
+	state.SetWon()
+
+	report := Analyze(state)
+	if report.FiftyFifties != 1 || !report.Timeline[1].FiftyFifty || report.Timeline[1].Kind != KindForcedGuess {
+		t.Errorf("expected a forced 50/50, got %+v", report.Timeline)
+	}
+	if report.Timeline[0].Revealed != 6 {
+		t.Errorf("expected the opening to reveal 6 cells, got %d", report.Timeline[0].Revealed)
+	}
+	if !strings.Contains(report.String(), "(1,0) forced-guess (50/50), revealed 1 by alice") {
+		t.Errorf("expected the 50/50 in the timeline, got:\n%s", report)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	state := newTestBoard()
+	play(state, game.Coordinate{X: 0, Y: 1})
+	_ = store.Save(ctx, state)
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		Handler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/analysis", nil))
+		return rec
+	}
+	if rec := get(); rec.Code != http.StatusConflict {
+		t.Errorf("expected games in progress to be refused, got %d", rec.Code)
+	}
+
+	state.SetLost()
+	_ = store.Save(ctx, state)
+	rec := get()
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected the analysis, got %d: %s", rec.Code, rec.Body)
+	}
+	if report.Moves != 1 || report.GameID != state.ID() {
+		t.Errorf("unexpected analysis: %+v", report)
+	}
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+// Custom returns the level described by s.
+func (s PodSweeperLevelSpec) Custom() level.Custom {
+	basedOn := min(s.Level, level.Max)
+	if s.BasedOn != nil {
+		basedOn = *s.BasedOn
+	}
+	c := level.Custom{
+		Level:         s.Level,
+		Name:          s.DisplayName,
+		BasedOn:       basedOn,
+		Leak:          level.Leak(s.Protections.Leak),
+		HintAccess:    level.HintAccess(s.Protections.HintAccess),
+		ProtectHints:  s.Protections.ProtectHints,
+		ScrambleHints: s.Protections.ScrambleHints,
+		RestrictRBAC:  s.Protections.RestrictRBAC,
+	}
+	if s.AllowedTools != nil {
+		c.Tools = make([]level.Tool, len(s.AllowedTools))
+		for i, tool := range s.AllowedTools {
+			c.Tools[i] = level.Tool(tool)
+		}
+	}
+	return c
+}
+
+// Config returns the validated generator config of the boards of s, and
+// false when s keeps the board of the difficulty.
+func (s PodSweeperLevelSpec) Config() (grid.Config, bool, error) {
+	if s.Board == nil {
+		return grid.Config{}, false, nil
+	}
+	config, err := grid.PresetSpec{
+		Width:           s.Board.Width,
+		Height:          s.Board.Height,
+		MineDensity:     s.Board.MineDensity,
+		RequireSolvable: s.Board.RequireSolvable,
+		Level:           s.Level,
+	}.Config()
+	return config, err == nil, err
+}
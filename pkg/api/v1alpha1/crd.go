@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+// LevelResource is the plural resource of PodSweeperLevels.
+const LevelResource = "podsweeperlevels"
+
+// LevelCRD returns the CustomResourceDefinition of PodSweeperLevel.
+func LevelCRD(labels map[string]string) *apiextensionsv1.CustomResourceDefinition {
+	str := func(description string, enum ...string) apiextensionsv1.JSONSchemaProps {
+		props := apiextensionsv1.JSONSchemaProps{Type: "string", Description: description}
+		for _, value := range enum {
+			props.Enum = append(props.Enum, apiextensionsv1.JSON{Raw: []byte(`"` + value + `"`)})
+		}
+		return props
+	}
+	integer := func(description string, minimum float64) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "integer", Description: description, Minimum: &minimum}
+	}
+	boolean := func(description string) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "boolean", Description: description}
+	}
+	spec := apiextensionsv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"level"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"level":       integer("Level number: 0 to 9 replace the built-in level, higher ones add custom levels.", 0),
+			"displayName": str("Name of the level."),
+			"basedOn": func() apiextensionsv1.JSONSchemaProps {
+				props := integer("Built-in level whose obstacles the level starts from.", 0)
+				props.Maximum = ptr.To(float64(level.Max))
+				return props
+			}(),
+			"board": {
+				Type:        "object",
+				Description: "Size and mine density of the boards of the level.",
+				Required:    []string{"width", "height", "mineDensity"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"width":           integer("Number of columns.", 1),
+					"height":          integer("Number of rows.", 1),
+					"mineDensity":     {Type: "number", Description: "Fraction of cells that are mines.", Maximum: ptr.To(float64(grid.MaxMineDensity))},
+					"requireSolvable": boolean("Only generate boards that can be cleared without guessing."),
+				},
+			},
+			"protections": {
+				Type:        "object",
+				Description: "Obstacles overriding those of the level it is based on.",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"leak": str("Where the board leaks.",
+						"configmap", "secret", "env", "filesystem", "none", "admin-port", "hints", "events"),
+					"hintAccess":    str("Who may reach hint pods.", "open", "players-only", "blackout"),
+					"protectHints":  boolean("Refuse the deletion of hint pods."),
+					"scrambleHints": boolean("Scramble the hint annotations of hint pods."),
+					"restrictRBAC":  boolean("Leave players the minimal RBAC, without tools."),
+				},
+			},
+			"allowedTools": {
+				Type:        "array",
+				Description: "What players may do on top of listing and deleting pods.",
+				Items:       &apiextensionsv1.JSONSchemaPropsOrArray{Schema: ptr.To(str("Tool.", "exec", "logs"))},
+			},
+		},
+	}
+	status := apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"observedGeneration": {Type: "integer", Format: "int64"},
+			"conditions": {
+				Type: "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+					Type: "object", XPreserveUnknownFields: ptr.To(true),
+				}},
+			},
+		},
+	}
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: LevelResource + "." + GroupVersion.Group, Labels: labels},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: GroupVersion.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       "PodSweeperLevel",
+				ListKind:   "PodSweeperLevelList",
+				Plural:     LevelResource,
+				Singular:   "podsweeperlevel",
+				ShortNames: []string{"psl"},
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:         GroupVersion.Version,
+				Served:       true,
+				Storage:      true,
+				Subresources: &apiextensionsv1.CustomResourceSubresources{Status: &apiextensionsv1.CustomResourceSubresourceStatus{}},
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"apiVersion": {Type: "string"},
+						"kind":       {Type: "string"},
+						"metadata":   {Type: "object"},
+						"spec":       spec,
+						"status":     status,
+					},
+					Required: []string{"spec"},
+				}},
+				AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+					{Name: "Level", Type: "integer", JSONPath: ".spec.level"},
+					{Name: "Name", Type: "string", JSONPath: ".spec.displayName"},
+					{Name: "Ready", Type: "string", JSONPath: `.status.conditions[?(@.type=="Ready")].status`},
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies l into out.
+func (l *PodSweeperLevel) DeepCopyInto(out *PodSweeperLevel) {
+	*out = *l
+	out.TypeMeta = l.TypeMeta
+	l.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	l.Spec.DeepCopyInto(&out.Spec)
+	l.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a copy of l.
+func (l *PodSweeperLevel) DeepCopy() *PodSweeperLevel {
+	if l == nil {
+		return nil
+	}
+	out := new(PodSweeperLevel)
+	l.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (l *PodSweeperLevel) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// DeepCopyInto copies s into out.
+func (s *PodSweeperLevelSpec) DeepCopyInto(out *PodSweeperLevelSpec) {
+	*out = *s
+	if s.BasedOn != nil {
+		out.BasedOn = new(int)
+		*out.BasedOn = *s.BasedOn
+	}
+	if s.Board != nil {
+		out.Board = new(LevelBoard)
+		*out.Board = *s.Board
+	}
+	s.Protections.DeepCopyInto(&out.Protections)
+	if s.AllowedTools != nil {
+		out.AllowedTools = append([]string(nil), s.AllowedTools...)
+	}
+}
+
+// DeepCopyInto copies p into out.
+func (p *LevelProtections) DeepCopyInto(out *LevelProtections) {
+	*out = *p
+	out.ProtectHints = copyBool(p.ProtectHints)
+	out.ScrambleHints = copyBool(p.ScrambleHints)
+	out.RestrictRBAC = copyBool(p.RestrictRBAC)
+}
+
+// DeepCopyInto copies s into out.
+func (s *PodSweeperLevelStatus) DeepCopyInto(out *PodSweeperLevelStatus) {
+	*out = *s
+	if s.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(s.Conditions))
+		for i := range s.Conditions {
+			s.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies l into out.
+func (l *PodSweeperLevelList) DeepCopyInto(out *PodSweeperLevelList) {
+	*out = *l
+	out.TypeMeta = l.TypeMeta
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	if l.Items != nil {
+		out.Items = make([]PodSweeperLevel, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a copy of l.
+func (l *PodSweeperLevelList) DeepCopy() *PodSweeperLevelList {
+	if l == nil {
+		return nil
+	}
+	out := new(PodSweeperLevelList)
+	l.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (l *PodSweeperLevelList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	return &c
+}
@@ -0,0 +1,133 @@
+// Package v1alpha1 is the podsweeper.io/v1alpha1 API: PodSweeperLevel
+// lets game organizers define levels declaratively, their board and the
+// obstacles players face, instead of recompiling the Gamemaster:
+//
+//	apiVersion: podsweeper.io/v1alpha1
+//	kind: PodSweeperLevel
+//	metadata:
+//	  name: vault
+//	  namespace: podsweeper-game
+//	spec:
+//	  level: 10
+//	  displayName: The Vault
+//	  basedOn: 7
+//	  board: {width: 20, height: 12, mineDensity: 0.2}
+//	  protections: {leak: none, restrictRBAC: true}
+//
+// Print the CustomResourceDefinition with `gamemaster manifests --crds`.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// GroupVersion is the group and version of the API.
+var GroupVersion = schema.GroupVersion{Group: "podsweeper.io", Version: "v1alpha1"}
+
+var (
+	// SchemeBuilder registers the types of the API.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types of the API to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(&PodSweeperLevel{}, &PodSweeperLevelList{})
+}
+
+// PodSweeperLevel defines a level of the game namespace it is created in.
+// Levels 0 to 9 replace the built-in level of that number, higher ones add
+// custom levels.
+type PodSweeperLevel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodSweeperLevelSpec   `json:"spec"`
+	Status PodSweeperLevelStatus `json:"status,omitempty"`
+}
+
+// PodSweeperLevelSpec describes a level.
+type PodSweeperLevelSpec struct {
+	// Level is the level number.
+	Level int `json:"level"`
+
+	// DisplayName is the name of the level, such as "The Vault". Defaults
+	// to the name of BasedOn.
+	DisplayName string `json:"displayName,omitempty"`
+
+	// BasedOn is the built-in level whose obstacles the level starts from.
+	// Defaults to Level, or the highest built-in level for custom levels.
+	BasedOn *int `json:"basedOn,omitempty"`
+
+	// Board is the size and mine density of the boards of the level.
+	// Unset, games keep the board of their difficulty.
+	Board *LevelBoard `json:"board,omitempty"`
+
+	// Protections override the obstacles of BasedOn.
+	Protections LevelProtections `json:"protections,omitempty"`
+
+	// AllowedTools lists what players may do on top of listing and
+	// deleting pods: exec, logs. Unset, the tools of BasedOn are allowed.
+	AllowedTools []string `json:"allowedTools,omitempty"`
+}
+
+// LevelBoard is the board of a level.
+type LevelBoard struct {
+	// Width is the number of columns.
+	Width int `json:"width"`
+
+	// Height is the number of rows.
+	Height int `json:"height"`
+
+	// MineDensity is the fraction of cells that are mines, such as 0.15.
+	MineDensity float64 `json:"mineDensity"`
+
+	// RequireSolvable only generates boards that can be cleared without
+	// guessing.
+	RequireSolvable bool `json:"requireSolvable,omitempty"`
+}
+
+// LevelProtections are the obstacles of a level. Unset fields keep those
+// of the level it is based on.
+type LevelProtections struct {
+	// Leak is where the board leaks: configmap, secret, env, filesystem,
+	// none, admin-port, hints or events.
+	Leak string `json:"leak,omitempty"`
+
+	// HintAccess is who may reach hint pods: open, players-only or
+	// blackout.
+	HintAccess string `json:"hintAccess,omitempty"`
+
+	// ProtectHints refuses the deletion of hint pods.
+	ProtectHints *bool `json:"protectHints,omitempty"`
+
+	// ScrambleHints scrambles the hint annotations of hint pods.
+	ScrambleHints *bool `json:"scrambleHints,omitempty"`
+
+	// RestrictRBAC leaves players the minimal RBAC, without tools.
+	RestrictRBAC *bool `json:"restrictRBAC,omitempty"`
+}
+
+// PodSweeperLevelStatus is the state of a level.
+type PodSweeperLevelStatus struct {
+	// ObservedGeneration is the generation of the spec last reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the Ready condition, false when the level is
+	// invalid or conflicts with another one.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ConditionReady is the condition of levels the Gamemaster plays.
+const ConditionReady = "Ready"
+
+// PodSweeperLevelList is a list of PodSweeperLevels.
+type PodSweeperLevelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PodSweeperLevel `json:"items"`
+}
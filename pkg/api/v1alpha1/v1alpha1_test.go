@@ -0,0 +1,115 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/zwindler/podsweeper/pkg/level"
+)
+
+func TestPodSweeperLevelSpec_Custom(t *testing.T) {
+	c := PodSweeperLevelSpec{
+		Level:        12,
+		DisplayName:  "The Vault",
+		Protections:  LevelProtections{Leak: "none", ProtectHints: ptr.To(false)},
+		AllowedTools: []string{"logs"},
+	}.Custom()
+	if c.Level != 12 || c.BasedOn != level.Max || c.Name != "The Vault" {
+		t.Errorf("Custom() = %+v, want level 12 based on the highest built-in level", c)
+	}
+	if c.Leak != level.LeakNone || c.ProtectHints == nil || *c.ProtectHints || len(c.Tools) != 1 || c.Tools[0] != level.ToolLogs {
+		t.Errorf("Custom() = %+v, want the protections and tools of the spec", c)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	if c := (PodSweeperLevelSpec{Level: 3, BasedOn: ptr.To(7)}).Custom(); c.BasedOn != 7 || c.Tools != nil {
+		t.Errorf("Custom() = %+v, want based on level 7 with its tools", c)
+	}
+	if c := (PodSweeperLevelSpec{Level: 3}).Custom(); c.BasedOn != 3 {
+		t.Errorf("Custom() = %+v, want built-in levels based on themselves", c)
+	}
+}
+
+func TestPodSweeperLevelSpec_Config(t *testing.T) {
+	if _, ok, err := (PodSweeperLevelSpec{Level: 10}).Config(); ok || err != nil {
+		t.Errorf("Config() = %v, %v, want no board", ok, err)
+	}
+
+	config, ok, err := PodSweeperLevelSpec{Level: 10, Board: &LevelBoard{Width: 20, Height: 12, MineDensity: 0.2}}.Config()
+	if err != nil || !ok {
+		t.Fatalf("Config() = %v, %v, want a board", ok, err)
+	}
+	if w, h := config.Dimensions(); w != 20 || h != 12 || config.Level != 10 {
+		t.Errorf("Config() = %dx%d at level %d, want 20x12 at level 10", w, h, config.Level)
+	}
+
+	if _, ok, err := (PodSweeperLevelSpec{Level: 10, Board: &LevelBoard{Width: 20, Height: 12, MineDensity: 2}}).Config(); ok || err == nil {
+		t.Errorf("Config() = %v, %v, want an invalid board", ok, err)
+	}
+}
+
+func TestPodSweeperLevel_DeepCopy(t *testing.T) {
+	l := &PodSweeperLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Labels: map[string]string{"a": "b"}},
+		Spec: PodSweeperLevelSpec{
+			Level:        10,
+			BasedOn:      ptr.To(7),
+			Board:        &LevelBoard{Width: 5, Height: 5, MineDensity: 0.1},
+			Protections:  LevelProtections{RestrictRBAC: ptr.To(true)},
+			AllowedTools: []string{"exec"},
+		},
+		Status: PodSweeperLevelStatus{Conditions: []metav1.Condition{{Type: ConditionReady}}},
+	}
+
+	c := l.DeepCopy()
+	*c.Spec.BasedOn = 1
+	c.Spec.Board.Width = 9
+	*c.Spec.Protections.RestrictRBAC = false
+	c.Spec.AllowedTools[0] = "logs"
+	c.Status.Conditions[0].Type = "Other"
+	c.Labels["a"] = "c"
+
+	if *l.Spec.BasedOn != 7 || l.Spec.Board.Width != 5 || !*l.Spec.Protections.RestrictRBAC ||
+		l.Spec.AllowedTools[0] != "exec" || l.Status.Conditions[0].Type != ConditionReady || l.Labels["a"] != "b" {
+		t.Errorf("DeepCopy() shares memory with the original: %+v", l)
+	}
+
+	list := &PodSweeperLevelList{Items: []PodSweeperLevel{*l}}
+	if copied := list.DeepCopyObject().(*PodSweeperLevelList); copied.Items[0].Spec.Board == l.Spec.Board {
+		t.Error("DeepCopyObject() shares the items of the list")
+	}
+}
+
+func TestAddToScheme(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	for _, kind := range []string{"PodSweeperLevel", "PodSweeperLevelList"} {
+		if !s.Recognizes(GroupVersion.WithKind(kind)) {
+			t.Errorf("scheme should recognize %s", kind)
+		}
+	}
+}
+
+func TestLevelCRD(t *testing.T) {
+	crd := LevelCRD(map[string]string{"app": "podsweeper"})
+	if crd.Name != "podsweeperlevels.podsweeper.io" || crd.Spec.Names.Kind != "PodSweeperLevel" {
+		t.Errorf("unexpected CRD %s of kind %s", crd.Name, crd.Spec.Names.Kind)
+	}
+	if len(crd.Spec.Versions) != 1 || crd.Spec.Versions[0].Name != GroupVersion.Version ||
+		crd.Spec.Versions[0].Subresources == nil || crd.Spec.Versions[0].Subresources.Status == nil {
+		t.Errorf("expected the served %s version with a status subresource, got %+v", GroupVersion.Version, crd.Spec.Versions)
+	}
+	spec := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	for _, field := range []string{"level", "displayName", "basedOn", "board", "protections", "allowedTools"} {
+		if _, ok := spec.Properties[field]; !ok {
+			t.Errorf("spec schema lacks %s", field)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package certs generates the self-signed CA and serving certificates of
+// the webhook server, for clusters without cert-manager:
+//
+//	ca, err := certs.NewCA(now, 365*24*time.Hour)
+//	serving, err := ca.Issue(certs.ServiceDNSNames("podsweeper-webhook", "podsweeper-system"), now, 30*24*time.Hour)
+//
+// Certificates and keys are PEM encoded, as kept in kubernetes.io/tls
+// Secrets and caBundle fields.
+package certs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// clockSkew backdates certificates, so that API servers whose clock is
+// slightly behind accept them.
+const clockSkew = 5 * time.Minute
+
+// Pair is a PEM encoded certificate and its private key.
+type Pair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// NewCA returns a self-signed CA valid for validity from now.
+func NewCA(now time.Time, validity time.Duration) (Pair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Pair{}, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	template, err := newTemplate("podsweeper-webhook-ca", now, validity)
+	if err != nil {
+		return Pair{}, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	return encode(template, template, key, key)
+}
+
+// Issue returns a serving certificate for dnsNames signed by the CA p,
+// valid for validity from now.
+func (p Pair) Issue(dnsNames []string, now time.Time, validity time.Duration) (Pair, error) {
+	if len(dnsNames) == 0 {
+		return Pair{}, errors.New("missing DNS names")
+	}
+	ca, err := p.Parse()
+	if err != nil {
+		return Pair{}, err
+	}
+	caKey, err := parseKey(p.Key)
+	if err != nil {
+		return Pair{}, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Pair{}, fmt.Errorf("failed to generate serving key: %w", err)
+	}
+	template, err := newTemplate(dnsNames[0], now, validity)
+	if err != nil {
+		return Pair{}, err
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	// Never outlive the CA
+	if template.NotAfter.After(ca.NotAfter) {
+		template.NotAfter = ca.NotAfter
+	}
+	return encode(template, ca, key, caKey)
+}
+
+// Parse returns the first certificate of p.
+func (p Pair) Parse() (*x509.Certificate, error) {
+	block, _ := pem.Decode(p.Cert)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// Expiring reports whether the certificate of p is invalid or expires
+// within renewBefore of now.
+func (p Pair) Expiring(now time.Time, renewBefore time.Duration) bool {
+	cert, err := p.Parse()
+	return err != nil || !now.Add(renewBefore).Before(cert.NotAfter)
+}
+
+// Covers reports whether the certificate of p is valid for every one of
+// dnsNames.
+func (p Pair) Covers(dnsNames []string) bool {
+	cert, err := p.Parse()
+	if err != nil {
+		return false
+	}
+	for _, name := range dnsNames {
+		if !slices.Contains(cert.DNSNames, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bundle concatenates PEM certificates into a caBundle, skipping those
+// that are invalid or expired at now and duplicates.
+func Bundle(now time.Time, certs ...[]byte) []byte {
+	var bundle bytes.Buffer
+	seen := map[string]bool{}
+	for _, data := range certs {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil || !now.Before(cert.NotAfter) || seen[string(block.Bytes)] {
+				continue
+			}
+			seen[string(block.Bytes)] = true
+			_ = pem.Encode(&bundle, block)
+		}
+	}
+	return bundle.Bytes()
+}
+
+// ServiceDNSNames returns the DNS names the API server may reach a Service
+// at.
+func ServiceDNSNames(service, namespace string) []string {
+	return []string{
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc.cluster.local",
+		service + "." + namespace,
+		service,
+	}
+}
+
+// newTemplate returns the template of a certificate for commonName, valid
+// for validity from now.
+func newTemplate(commonName string, now time.Time, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"PodSweeper"}},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+// encode signs template with the parent certificate and key, and returns
+// the PEM encoded certificate and key.
+func encode(template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) (Pair, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return Pair{}, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return Pair{}, fmt.Errorf("failed to encode key: %w", err)
+	}
+	return Pair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// parseKey decodes a PEM encoded EC private key.
+func parseKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, errors.New("invalid PEM key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return key, nil
+}
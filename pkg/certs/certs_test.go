@@ -0,0 +1,76 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func TestIssue(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA(now, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA() error = %v", err)
+	}
+	names := ServiceDNSNames("podsweeper-webhook", "podsweeper-system")
+	serving, err := ca.Issue(names, now, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	cert, err := serving.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.Cert)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "podsweeper-webhook.podsweeper-system.svc"}); err != nil {
+		t.Errorf("expected the serving certificate trusted by the CA, got %v", err)
+	}
+	if !serving.Covers(names) || serving.Covers([]string{"other.svc"}) {
+		t.Errorf("expected the serving certificate to only cover %v, got %v", names, cert.DNSNames)
+	}
+	caCert, _ := ca.Parse()
+	if cert.NotAfter.After(caCert.NotAfter) {
+		t.Errorf("expected the serving certificate not to outlive the CA, got %v after %v", cert.NotAfter, caCert.NotAfter)
+	}
+
+	if _, err := ca.Issue(nil, now, time.Hour); err == nil {
+		t.Error("expected an error without DNS names")
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA(now, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA() error = %v", err)
+	}
+	if ca.Expiring(now, time.Hour) {
+		t.Error("expected a fresh CA not to be expiring")
+	}
+	if !ca.Expiring(now, 25*time.Hour) {
+		t.Error("expected the CA expiring within 25h")
+	}
+	if !(Pair{}).Expiring(now, 0) {
+		t.Error("expected a missing certificate to be expiring")
+	}
+}
+
+func TestBundle(t *testing.T) {
+	now := time.Now()
+	old, _ := NewCA(now.Add(-2*time.Hour), time.Hour)
+	current, _ := NewCA(now, time.Hour)
+	next, _ := NewCA(now, 2*time.Hour)
+
+	bundle := Bundle(now, next.Cert, append(append([]byte{}, current.Cert...), old.Cert...), next.Cert)
+	var got [][]byte
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		got = append(got, pem.EncodeToMemory(block))
+	}
+	if len(got) != 2 || !bytes.Equal(got[0], next.Cert) || !bytes.Equal(got[1], current.Cert) {
+		t.Errorf("expected the next then current CA, without the expired one nor duplicates, got %d certificates", len(got))
+	}
+}
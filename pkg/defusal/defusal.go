@@ -0,0 +1,147 @@
+// Package defusal generates the wire-cutting challenges of defusal mode.
+// A mine hit in defusal mode doesn't explode right away: its bomb pod shows
+// a row of colored wires and the manual below, and the player who hit it
+// execs into the pod to cut the wire the manual designates before the time
+// runs out. The first rule of the manual that applies designates the wire.
+package defusal
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Wire colors.
+const (
+	Red    = "red"
+	Blue   = "blue"
+	Yellow = "yellow"
+	White  = "white"
+	Black  = "black"
+)
+
+// Colors are the colors wires are drawn from.
+var Colors = []string{Red, Blue, Yellow, White, Black}
+
+const (
+	// MinWires is the fewest wires of a challenge.
+	MinWires = 3
+
+	// MaxWires is the most wires of a challenge.
+	MaxWires = 6
+)
+
+// AnswerFile is where the bomb pod expects the number of the wire to cut.
+const AnswerFile = "/tmp/cut"
+
+// rule designates a wire, 1-based, if it applies.
+type rule struct {
+	text  string
+	apply func(wires []string) (int, bool)
+}
+
+var manual = []rule{
+	{"If there is exactly one red wire, cut the red wire.", func(w []string) (int, bool) {
+		if count(w, Red) == 1 {
+			return slices.Index(w, Red) + 1, true
+		}
+		return 0, false
+	}},
+	{"Otherwise, if the last wire is yellow, cut the first wire.", func(w []string) (int, bool) {
+		return 1, w[len(w)-1] == Yellow
+	}},
+	{"Otherwise, if there is more than one blue wire, cut the last blue wire.", func(w []string) (int, bool) {
+		if count(w, Blue) > 1 {
+			return lastIndex(w, Blue) + 1, true
+		}
+		return 0, false
+	}},
+	{"Otherwise, if there is no black wire, cut the second wire.", func(w []string) (int, bool) {
+		return 2, count(w, Black) == 0
+	}},
+	{"Otherwise, cut the last wire.", func(w []string) (int, bool) {
+		return len(w), true
+	}},
+}
+
+// Manual returns the rules designating the wire to cut, in order.
+func Manual() []string {
+	texts := make([]string, len(manual))
+	for i, r := range manual {
+		texts[i] = r.text
+	}
+	return texts
+}
+
+// Challenge is a row of wires, one of which defuses the bomb.
+type Challenge struct {
+	Wires []string
+}
+
+// New generates a challenge of MinWires to MaxWires random wires.
+func New(rng *rand.Rand) Challenge {
+	wires := make([]string, MinWires+rng.IntN(MaxWires-MinWires+1))
+	for i := range wires {
+		wires[i] = Colors[rng.IntN(len(Colors))]
+	}
+	return Challenge{Wires: wires}
+}
+
+// Solution returns the number of the wire to cut, counting from 1. It is 0
+// for a challenge without wires.
+func (c Challenge) Solution() int {
+	if len(c.Wires) == 0 {
+		return 0
+	}
+	for _, r := range manual {
+		if wire, ok := r.apply(c.Wires); ok {
+			return wire
+		}
+	}
+	return 0
+}
+
+// Check reports whether the answer, a wire number, defuses the bomb.
+func (c Challenge) Check(answer string) bool {
+	wire, err := strconv.Atoi(strings.TrimSpace(answer))
+	return err == nil && wire > 0 && wire == c.Solution()
+}
+
+// Prompt returns what the bomb pod of podName shows the player: the
+// wires, the manual and how to cut a wire within limit.
+func (c Challenge) Prompt(namespace, podName string, limit time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BOMB! You have %s to cut the right wire.\n\n", limit)
+	for i, wire := range c.Wires {
+		fmt.Fprintf(&b, "  %d. ====[ %s ]====\n", i+1, wire)
+	}
+	b.WriteString("\nManual:\n")
+	for _, text := range Manual() {
+		fmt.Fprintf(&b, "  - %s\n", text)
+	}
+	fmt.Fprintf(&b, "\nCut wire N with:\n  kubectl exec -n %s %s -- sh -c 'echo N > %s'\n", namespace, podName, AnswerFile)
+	b.WriteString("Deleting this pod sets the bomb off.\n")
+	return b.String()
+}
+
+func count(wires []string, color string) int {
+	n := 0
+	for _, w := range wires {
+		if w == color {
+			n++
+		}
+	}
+	return n
+}
+
+func lastIndex(wires []string, color string) int {
+	for i := len(wires) - 1; i >= 0; i-- {
+		if wires[i] == color {
+			return i
+		}
+	}
+	return -1
+}
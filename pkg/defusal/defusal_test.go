@@ -0,0 +1,62 @@
+package defusal
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChallenge_Solution(t *testing.T) {
+	tests := []struct {
+		name  string
+		wires []string
+		want  int
+	}{
+		{"one red wire", []string{Blue, Red, Yellow}, 2},
+		{"last wire yellow", []string{Red, Red, Yellow}, 1},
+		{"several blue wires", []string{Blue, White, Blue, Black}, 3},
+		{"no black wire", []string{White, Blue, White}, 2},
+		{"otherwise the last wire", []string{Black, White, White, Black}, 4},
+		{"no wire", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Challenge{Wires: tt.wires}
+			if got := c.Solution(); got != tt.want {
+				t.Errorf("Solution() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChallenge_Check(t *testing.T) {
+	c := Challenge{Wires: []string{Blue, Red, Yellow}}
+	for answer, want := range map[string]bool{"2": true, " 2\n": true, "1": false, "red": false, "": false} {
+		if got := c.Check(answer); got != want {
+			t.Errorf("Check(%q) = %v, want %v", answer, got, want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		c := New(rng)
+		if len(c.Wires) < MinWires || len(c.Wires) > MaxWires {
+			t.Fatalf("unexpected %d wires", len(c.Wires))
+		}
+		if s := c.Solution(); s < 1 || s > len(c.Wires) {
+			t.Fatalf("solution %d out of %v", s, c.Wires)
+		}
+	}
+}
+
+func TestChallenge_Prompt(t *testing.T) {
+	prompt := Challenge{Wires: []string{Blue, Red, Yellow}}.Prompt("game", "bomb-1-1", time.Minute)
+	for _, want := range []string{"1m0s", "3. ====[ yellow ]====", Manual()[0], "kubectl exec -n game bomb-1-1", AnswerFile} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected the prompt to hold %q, got:\n%s", want, prompt)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Board string characters. Unrevealed cells use lowercase/symbols for safe
+// cells and uppercase/symbols for mines so the string round-trips the layout.
+const (
+	BoardHiddenSafe     = '.'
+	BoardHiddenMine     = '*'
+	BoardFlaggedSafe    = 'f'
+	BoardFlaggedMine    = 'F'
+	BoardQuestionedSafe = 'q'
+	BoardQuestionedMine = 'Q'
+	BoardRevealedMine   = 'X'
+	BoardDefusedMine    = 'D'
+	// Revealed safe cells are written as their hint digit ('0'-'8'), or
+	// as their temperature in the player view of hot/cold games.
+	BoardHot  = 'H'
+	BoardWarm = 'W'
+	BoardCold = 'C'
+)
+
+// temperatureChars maps temperatures to their board characters.
+var temperatureChars = map[string]byte{Hot: BoardHot, Warm: BoardWarm, Cold: BoardCold}
+
+// boardOptions configures board string encoding.
+type boardOptions struct {
+	rle        bool
+	playerView bool
+	solution   bool
+}
+
+// BoardStringOption configures ToBoardString.
+type BoardStringOption func(*boardOptions)
+
+// WithRLE compresses runs of 3 or more identical cells as c{n},
+// e.g. ".{12}" for twelve hidden safe cells.
+func WithRLE() BoardStringOption {
+	return func(o *boardOptions) {
+		o.rle = true
+	}
+}
+
+// WithPlayerView hides unrevealed mines, encoding the board as players see
+// it, with the hints of hot/cold games written as temperatures. Such
+// strings are meant for display and cannot be decoded back.
+func WithPlayerView() BoardStringOption {
+	return func(o *boardOptions) {
+		o.playerView = true
+	}
+}
+
+// WithSolution shows every hidden safe cell with its hint, as the board
+// looks once cleared, next to the mines. Such strings are meant for display
+// and cannot be decoded back.
+func WithSolution() BoardStringOption {
+	return func(o *boardOptions) {
+		o.solution = true
+	}
+}
+
+// ToBoardString encodes the board as one line of characters per row (Y),
+// one character per cell (X). Game metadata such as status or level is
+// not included.
+func (g *GameState) ToBoardString(opts ...BoardStringOption) string {
+	var o boardOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w, h := g.Dimensions()
+	rows := make([]string, h)
+	for y := 0; y < h; y++ {
+		row := make([]byte, w)
+		for x := 0; x < w; x++ {
+			cell := g.Cells[x][y]
+			if o.playerView && !cell.Revealed {
+				cell.Mine = false
+			}
+			if o.solution && !cell.Revealed && !cell.Mine {
+				cell.Revealed = true
+				cell.Hint = g.AdjacentMines(x, y)
+			}
+			row[x] = cellChar(cell)
+			if o.playerView && g.HotCold() && cell.Revealed && !cell.Mine {
+				row[x] = temperatureChars[g.Temperature(x, y)]
+			}
+		}
+		if o.rle {
+			rows[y] = encodeRLE(row)
+		} else {
+			rows[y] = string(row)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// cellChar returns the board character of a cell.
+func cellChar(c Cell) byte {
+	switch {
+	case c.Revealed && c.Mine:
+		return BoardRevealedMine
+	case c.Defused:
+		return BoardDefusedMine
+	case c.Revealed:
+		return byte('0' + c.Hint)
+	case c.Flagged && c.Mine:
+		return BoardFlaggedMine
+	case c.Flagged:
+		return BoardFlaggedSafe
+	case c.Question && c.Mine:
+		return BoardQuestionedMine
+	case c.Question:
+		return BoardQuestionedSafe
+	case c.Mine:
+		return BoardHiddenMine
+	default:
+		return BoardHiddenSafe
+	}
+}
+
+// encodeRLE compresses runs of 3 or more identical characters.
+func encodeRLE(row []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n >= 3 {
+			fmt.Fprintf(&b, "%c{%d}", row[i], n)
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// decodeRLE expands c{n} runs. Rows without runs are returned unchanged.
+func decodeRLE(row string) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(row); i++ {
+		c := row[i]
+		if c == '{' || c == '}' {
+			return nil, fmt.Errorf("unexpected %q at column %d", c, i)
+		}
+		if i+1 < len(row) && row[i+1] == '{' {
+			end := strings.IndexByte(row[i+1:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated run at column %d", i)
+			}
+			n, err := strconv.Atoi(row[i+2 : i+1+end])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid run length %q at column %d", row[i+2:i+1+end], i)
+			}
+			for k := 0; k < n; k++ {
+				out = append(out, c)
+			}
+			i += end + 1
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// FromBoardString decodes a board string produced by ToBoardString, with or
+// without RLE. Revealed hint digits are checked against the mine layout.
+// The returned state is playing unless a mine is revealed, in which case
+// it is lost.
+func FromBoardString(board string) (*GameState, error) {
+	lines := strings.Split(strings.TrimSpace(board), "\n")
+
+	rows := make([][]byte, 0, len(lines))
+	for y, line := range lines {
+		row, err := decodeRLE(strings.TrimSpace(line))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", y, err)
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", y, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("board is empty")
+	}
+
+	state := NewRectGameState(len(rows[0]), len(rows), 0)
+	type hint struct {
+		c     Coordinate
+		value int
+	}
+	var hints []hint
+
+	// Place mines first so revealed hints can be checked afterwards
+	for y, row := range rows {
+		for x, ch := range row {
+			cell := &state.Cells[x][y]
+			switch {
+			case ch == BoardHiddenSafe:
+			case ch == BoardHiddenMine:
+				cell.Mine = true
+			case ch == BoardFlaggedSafe:
+				cell.Flagged = true
+			case ch == BoardFlaggedMine:
+				cell.Mine, cell.Flagged = true, true
+			case ch == BoardQuestionedSafe:
+				cell.Question = true
+			case ch == BoardQuestionedMine:
+				cell.Mine, cell.Question = true, true
+			case ch == BoardDefusedMine:
+				cell.Mine, cell.Defused = true, true
+			case ch == BoardRevealedMine:
+				cell.Mine, cell.Revealed = true, true
+				state.Status = StatusLost
+			case ch >= '0' && ch <= '8':
+				cell.Revealed = true
+				hints = append(hints, hint{Coordinate{X: x, Y: y}, int(ch - '0')})
+			default:
+				return nil, fmt.Errorf("invalid character %q at (%d,%d)", ch, x, y)
+			}
+			if cell.Mine {
+				state.MineCount++
+			}
+			if cell.Revealed {
+				state.Clicks++
+			}
+		}
+	}
+
+	for _, h := range hints {
+		actual := state.AdjacentMines(h.c.X, h.c.Y)
+		if actual != h.value {
+			return nil, fmt.Errorf("hint at %s is %d but %d mines are adjacent", h.c, h.value, actual)
+		}
+		state.Cells[h.c.X][h.c.Y].Hint = actual
+	}
+
+	return state, nil
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func newBoardTestState() *GameState {
+	state := NewRectGameState(5, 3, 0)
+	state.SetMine(0, 0)
+	state.SetMine(4, 2)
+	state.Reveal(2, 1)
+	state.Reveal(1, 1)
+	state.SetFlag(0, 0, true)
+	state.SetQuestion(3, 2, true)
+	return state
+}
+
+func TestToBoardString(t *testing.T) {
+	state := newBoardTestState()
+
+	expected := "F....\n.10..\n...q*"
+	if got := state.ToBoardString(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+
+	expectedRLE := "F.{4}\n.10..\n.{3}q*"
+	if got := state.ToBoardString(WithRLE()); got != expectedRLE {
+		t.Errorf("expected RLE\n%s\ngot\n%s", expectedRLE, got)
+	}
+}
+
+func TestToBoardStringPlayerView(t *testing.T) {
+	state := newBoardTestState()
+	state.Reveal(4, 2)
+
+	// Hidden mines look like safe cells, revealed ones stay visible
+	expected := "f....\n.10..\n...qX"
+	if got := state.ToBoardString(WithPlayerView()); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestToBoardStringSolution(t *testing.T) {
+	state := newBoardTestState()
+
+	// Hidden safe cells show their hint, marks included
+	expected := "F1000\n11011\n0001*"
+	if got := state.ToBoardString(WithSolution()); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestBoardStringRoundTrip(t *testing.T) {
+	state := newBoardTestState()
+
+	for _, opts := range [][]BoardStringOption{nil, {WithRLE()}} {
+		board := state.ToBoardString(opts...)
+		restored, err := FromBoardString(board)
+		if err != nil {
+			t.Fatalf("FromBoardString(%q) failed: %v", board, err)
+		}
+
+		if diff := state.Diff(restored); len(diff) != 0 {
+			t.Errorf("round trip of %q changed the board: %v", board, diff)
+		}
+		if restored.MineCount != state.MineCount {
+			t.Errorf("expected %d mines, got %d", state.MineCount, restored.MineCount)
+		}
+		if err := restored.Validate(); err != nil {
+			t.Errorf("restored state is invalid: %v", err)
+		}
+	}
+}
+
+func TestFromBoardStringLostGame(t *testing.T) {
+	state, err := FromBoardString("X1\n11")
+	if err != nil {
+		t.Fatalf("FromBoardString failed: %v", err)
+	}
+	if state.Status != StatusLost {
+		t.Errorf("expected lost status, got %s", state.Status)
+	}
+}
+
+func TestFromBoardStringErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		err   string
+	}{
+		{"empty", "", "empty"},
+		{"ragged rows", "...\n..", "row 1"},
+		{"invalid character", ".?.", "invalid character"},
+		{"wrong hint", "*2\n..", "hint at"},
+		{"bad run length", ".{x}", "invalid run length"},
+		{"unterminated run", ".{3", "unterminated"},
+		{"stray brace", "}..", "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromBoardString(tt.board)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestBoardStringDefusedMine(t *testing.T) {
+	state := NewGameState(2, 0)
+	state.SetMine(0, 0)
+	_ = state.AddTeam("red", 2, "alice")
+	state.HitMine(0, 0, "alice")
+
+	board := state.ToBoardString()
+	if board != "D.\n.." {
+		t.Errorf("unexpected board %q", board)
+	}
+	restored, err := FromBoardString(board)
+	if err != nil {
+		t.Fatalf("FromBoardString failed: %v", err)
+	}
+	if !restored.IsDefused(0, 0) || !restored.IsMine(0, 0) {
+		t.Error("expected defused mine to round trip")
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import "time"
+
+// Cell holds everything known about a single grid cell.
+// Zero-valued fields are omitted from JSON to keep the state Secret small.
+type Cell struct {
+	// Mine is true if the cell contains a mine.
+	Mine bool `json:"mine,omitempty"`
+
+	// Revealed is true once the cell has been clicked/deleted.
+	Revealed bool `json:"revealed,omitempty"`
+
+	// Flagged is true if the player marked the cell as a mine.
+	Flagged bool `json:"flagged,omitempty"`
+
+	// PlayerFlagged is true for a flag a player set by annotating the pod
+	// of the cell, whose deletion is then refused until it is unflagged.
+	PlayerFlagged bool `json:"playerFlagged,omitempty"`
+
+	// Question is true if the player marked the cell as uncertain.
+	Question bool `json:"question,omitempty"`
+
+	// Defused is true for a mine that was hit without ending the game,
+	// costing a life instead.
+	Defused bool `json:"defused,omitempty"`
+
+	// Hint caches the number of adjacent mines. It is only meaningful
+	// for revealed cells and is refreshed when neighboring mines change.
+	Hint int `json:"hint,omitempty"`
+
+	// RevealedBy identifies who revealed the cell, if known.
+	RevealedBy string `json:"revealedBy,omitempty"`
+
+	// RevealedAt is when the cell was revealed.
+	RevealedAt time.Time `json:"revealedAt,omitzero"`
+
+	// Propagated is true if the cell was revealed by the flood fill of an
+	// empty cell rather than clicked directly.
+	Propagated bool `json:"propagated,omitempty"`
+
+	// PodUID is the UID of the pod last spawned for the hidden cell, so
+	// deletions of older pods of the same name are not played. Empty when
+	// unknown.
+	PodUID string `json:"podUID,omitempty"`
+}
+
+// RevealInfo describes how a cell was revealed.
+type RevealInfo struct {
+	// By identifies the player, empty if unknown.
+	By string
+
+	// At is the reveal time. Defaults to now.
+	At time.Time
+
+	// Propagated marks reveals caused by flood fill.
+	Propagated bool
+}
+
+// newCells allocates a width x height grid of empty cells, indexed [x][y].
+func newCells(width, height int) [][]Cell {
+	cells := make([][]Cell, width)
+	for x := 0; x < width; x++ {
+		cells[x] = make([]Cell, height)
+	}
+	return cells
+}
+
+// cellsFromLegacy converts the MineMap/Revealed boolean grids used by
+// states persisted before the Cell grid was introduced.
+func cellsFromLegacy(mineMap, revealed [][]bool) [][]Cell {
+	cells := make([][]Cell, len(mineMap))
+	for x := range mineMap {
+		cells[x] = make([]Cell, len(mineMap[x]))
+		for y := range mineMap[x] {
+			cells[x][y].Mine = mineMap[x][y]
+			if x < len(revealed) && y < len(revealed[x]) {
+				cells[x][y].Revealed = revealed[x][y]
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCellJSONOmitsEmptyFields(t *testing.T) {
+	data, err := json.Marshal(Cell{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("expected empty cell to encode as {}, got %s", data)
+	}
+}
+
+func TestCellsFromLegacy(t *testing.T) {
+	mineMap := [][]bool{{false, true}, {false, false}}
+	revealed := [][]bool{{true, false}, {false, true}}
+
+	cells := cellsFromLegacy(mineMap, revealed)
+
+	if !cells[0][1].Mine || cells[0][0].Mine {
+		t.Error("mines not converted")
+	}
+	if !cells[0][0].Revealed || !cells[1][1].Revealed || cells[1][0].Revealed {
+		t.Error("revealed cells not converted")
+	}
+
+	// A missing Revealed grid must not panic
+	cells = cellsFromLegacy(mineMap, nil)
+	if len(cells) != 2 || cells[0][0].Revealed {
+		t.Error("expected mines only when Revealed is missing")
+	}
+}
+
+func TestFromJSONLegacyRevealedCells(t *testing.T) {
+	data := []byte(`{"size":2,"seed":1,"level":0,"status":"playing",` +
+		`"mineMap":[[false,true],[false,false]],` +
+		`"revealed":[[true,false],[false,false]],` +
+		`"mineCount":1,"startedAt":"2024-01-01T00:00:00Z","clicks":1}`)
+
+	state, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	cell, ok := state.Cell(0, 0)
+	if !ok || !cell.Revealed {
+		t.Fatal("expected (0,0) to be revealed")
+	}
+	if cell.Hint != 1 {
+		t.Errorf("expected cached hint 1, got %d", cell.Hint)
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// Cheat records a player caught reading what the level hides, such as the
+// state Secret outside of level 1.
+type Cheat struct {
+	// Player is the Kubernetes username of the cheater.
+	Player string `json:"player"`
+
+	// Resource is what they read, like "secrets/podsweeper-state".
+	Resource string `json:"resource"`
+
+	// At is when they first read it.
+	At time.Time `json:"at"`
+}
+
+// RecordCheat records that player read resource, and reports whether it is
+// new. Repeated reads of the same resource keep the first one.
+func (g *GameState) RecordCheat(player, resource string, at time.Time) bool {
+	for _, c := range g.Cheats {
+		if c.Player == player && c.Resource == resource {
+			return false
+		}
+	}
+	g.Cheats = append(g.Cheats, Cheat{Player: player, Resource: resource, At: at})
+	return true
+}
+
+// IsCheater reports whether player was caught cheating.
+func (g *GameState) IsCheater(player string) bool {
+	for _, c := range g.Cheats {
+		if c.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// Cheaters returns the sorted usernames of players caught cheating.
+func (g *GameState) Cheaters() []string {
+	seen := make(map[string]bool)
+	var cheaters []string
+	for _, c := range g.Cheats {
+		if !seen[c.Player] {
+			seen[c.Player] = true
+			cheaters = append(cheaters, c.Player)
+		}
+	}
+	sort.Strings(cheaters)
+	return cheaters
+}
+
+// mergeCheats combines two versions of the recorded cheats, keeping the
+// earliest time of each.
+func mergeCheats(a, b []Cheat) []Cheat {
+	merged := append([]Cheat(nil), a...)
+	for _, bc := range b {
+		found := false
+		for i := range merged {
+			if merged[i].Player == bc.Player && merged[i].Resource == bc.Resource {
+				found = true
+				if bc.At.Before(merged[i].At) {
+					merged[i].At = bc.At
+				}
+			}
+		}
+		if !found {
+			merged = append(merged, bc)
+		}
+	}
+	return merged
+}
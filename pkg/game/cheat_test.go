@@ -0,0 +1,64 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordCheat(t *testing.T) {
+	state := NewGameState(4, 42)
+	now := time.Now()
+
+	if !state.RecordCheat("bob", "secrets/podsweeper-state", now) {
+		t.Fatal("expected the first cheat to be recorded")
+	}
+	if state.RecordCheat("bob", "secrets/podsweeper-state", now.Add(time.Minute)) {
+		t.Error("expected the same read not to be recorded twice")
+	}
+	state.RecordCheat("alice", "configmaps/map", now)
+	state.RecordCheat("bob", "configmaps/map", now)
+
+	if !state.IsCheater("bob") || state.IsCheater("carol") {
+		t.Error("IsCheater reports the wrong players")
+	}
+	if got := state.Cheaters(); !slices.Equal(got, []string{"alice", "bob"}) {
+		t.Errorf("Cheaters() = %v, want [alice bob]", got)
+	}
+
+	stats := state.Stats()
+	if !stats.Tainted() {
+		t.Error("expected the score to be tainted")
+	}
+	if got := stats.String(); !strings.HasSuffix(got, "(tainted)") {
+		t.Errorf("expected the summary to mention the taint, got %q", got)
+	}
+}
+
+func TestCheatsCloneAndMerge(t *testing.T) {
+	now := time.Now()
+	a := NewGameState(4, 42)
+	a.RecordCheat("bob", "secrets/podsweeper-state", now)
+
+	clone := a.Clone()
+	clone.RecordCheat("alice", "configmaps/map", now)
+	if a.IsCheater("alice") {
+		t.Error("expected the clone to be independent")
+	}
+
+	b := a.Clone()
+	b.Cheats[0].At = now.Add(-time.Minute)
+	b.RecordCheat("carol", "configmaps/map", now)
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(merged.Cheats) != 2 {
+		t.Fatalf("expected the union of cheats, got %+v", merged.Cheats)
+	}
+	if !merged.Cheats[0].At.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected the earliest read to be kept, got %v", merged.Cheats[0].At)
+	}
+}
@@ -0,0 +1,24 @@
+package game
+
+// Chord returns the neighbors of the revealed cell at (x, y) that chording
+// it reveals, the classic Minesweeper shortcut: once as many of its
+// neighbors are flagged as it has adjacent mines, its other hidden
+// neighbors are safe, unless a flag is wrong. Returns false if the cell
+// can't be chorded: it is hidden, shows no mine count, or the number of
+// flags around it doesn't match its hint.
+func (g *GameState) Chord(x, y int) ([]Coordinate, bool) {
+	if !g.IsRevealed(x, y) || g.HotCold() {
+		return nil, false
+	}
+	hint := g.AdjacentMines(x, y)
+	if hint == 0 || g.AdjacentFlags(x, y) != hint {
+		return nil, false
+	}
+	var cells []Coordinate
+	for _, n := range g.GetNeighbors(x, y) {
+		if cell := g.Cells[n.X][n.Y]; !cell.Revealed && !cell.Flagged && !cell.Defused {
+			cells = append(cells, n)
+		}
+	}
+	return cells, true
+}
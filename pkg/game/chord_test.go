@@ -0,0 +1,39 @@
+package game
+
+import "testing"
+
+func TestChord(t *testing.T) {
+	// Mines at (0,0) and (2,0): the center shows 2
+	state := NewGameState(3, 1)
+	state.SetMine(0, 0)
+	state.SetMine(2, 0)
+
+	if _, ok := state.Chord(1, 1); ok {
+		t.Error("hidden cells cannot be chorded")
+	}
+	state.Reveal(1, 1)
+	state.Reveal(1, 2)
+	state.SetFlag(0, 0, true)
+	if _, ok := state.Chord(1, 1); ok {
+		t.Error("expected a cell with too few flags not to be chorded")
+	}
+
+	state.SetFlag(2, 0, true)
+	if got := state.AdjacentFlags(1, 1); got != 2 {
+		t.Errorf("expected 2 adjacent flags, got %d", got)
+	}
+	cells, ok := state.Chord(1, 1)
+	if !ok || len(cells) != 5 {
+		t.Fatalf("expected the 5 other hidden neighbors, got %v", cells)
+	}
+	for _, c := range cells {
+		if state.IsMine(c.X, c.Y) || state.IsRevealed(c.X, c.Y) {
+			t.Errorf("unexpected chorded cell %s", c)
+		}
+	}
+
+	state.HintType = HintHotCold
+	if _, ok := state.Chord(1, 1); ok {
+		t.Error("hot/cold cells show no count to chord")
+	}
+}
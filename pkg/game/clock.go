@@ -0,0 +1,85 @@
+package game
+
+import "time"
+
+// Elapsed returns how long the game has been played, or lasted if it ended,
+// excluding time spent paused or with the Gamemaster down.
+func (g *GameState) Elapsed() time.Duration {
+	return g.ElapsedAt(time.Now())
+}
+
+// ElapsedAt returns the play time as of now.
+func (g *GameState) ElapsedAt(now time.Time) time.Duration {
+	if g.StartedAt.IsZero() {
+		return 0
+	}
+	end := now
+	switch {
+	case !g.EndedAt.IsZero():
+		end = g.EndedAt
+	case !g.PausedAt.IsZero():
+		end = g.PausedAt
+	}
+
+	elapsed := end.Sub(g.StartedAt) - g.PausedDuration
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// IsPaused reports whether the game clock is stopped.
+func (g *GameState) IsPaused() bool {
+	return !g.PausedAt.IsZero()
+}
+
+// Pause stops the game clock.
+// Returns false if the game is already paused or has ended.
+func (g *GameState) Pause() bool {
+	return g.PauseAt(time.Now())
+}
+
+// PauseAt stops the game clock at the given time.
+// Returns false if the game is already paused or has ended.
+func (g *GameState) PauseAt(t time.Time) bool {
+	if g.IsPaused() || g.Status != StatusPlaying {
+		return false
+	}
+	g.PausedAt = t
+	return true
+}
+
+// Resume restarts the game clock, adding the pause to PausedDuration.
+// Returns false if the game is not paused.
+func (g *GameState) Resume() bool {
+	return g.ResumeAt(time.Now())
+}
+
+// ResumeAt restarts the game clock at the given time.
+// Returns false if the game is not paused.
+func (g *GameState) ResumeAt(t time.Time) bool {
+	if !g.IsPaused() {
+		return false
+	}
+	if d := t.Sub(g.PausedAt); d > 0 {
+		g.PausedDuration += d
+	}
+	g.PausedAt = time.Time{}
+	return true
+}
+
+// RecordHeartbeat notes that the Gamemaster is running the game at now.
+// If the previous heartbeat is older than maxGap, the Gamemaster was down:
+// the silence beyond maxGap is added to PausedDuration and returned.
+// Time already spent paused is not counted twice.
+func (g *GameState) RecordHeartbeat(now time.Time, maxGap time.Duration) time.Duration {
+	var downtime time.Duration
+	if !g.HeartbeatAt.IsZero() && !g.IsPaused() && g.Status == StatusPlaying {
+		if gap := now.Sub(g.HeartbeatAt); gap > maxGap {
+			downtime = gap - maxGap
+			g.PausedDuration += downtime
+		}
+	}
+	g.HeartbeatAt = now
+	return downtime
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestElapsedExcludesPauses(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.StartedAt = clockStart
+
+	if !state.PauseAt(clockStart.Add(time.Minute)) {
+		t.Fatal("expected PauseAt to succeed")
+	}
+	if state.PauseAt(clockStart.Add(2 * time.Minute)) {
+		t.Error("pausing twice should fail")
+	}
+
+	// The clock is stopped while paused
+	if got := state.ElapsedAt(clockStart.Add(time.Hour)); got != time.Minute {
+		t.Errorf("expected 1m elapsed while paused, got %s", got)
+	}
+
+	if !state.ResumeAt(clockStart.Add(11 * time.Minute)) {
+		t.Fatal("expected ResumeAt to succeed")
+	}
+	if state.ResumeAt(clockStart.Add(12 * time.Minute)) {
+		t.Error("resuming a running game should fail")
+	}
+	if state.PausedDuration != 10*time.Minute {
+		t.Errorf("expected 10m paused, got %s", state.PausedDuration)
+	}
+	if got := state.ElapsedAt(clockStart.Add(15 * time.Minute)); got != 5*time.Minute {
+		t.Errorf("expected 5m elapsed, got %s", got)
+	}
+}
+
+func TestEndingClosesPause(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.StartedAt = time.Now().Add(-time.Hour)
+	state.PauseAt(time.Now().Add(-30 * time.Minute))
+
+	state.SetWon()
+
+	if state.IsPaused() {
+		t.Error("ended game should not stay paused")
+	}
+	if got := state.Elapsed(); got < 29*time.Minute || got > 31*time.Minute {
+		t.Errorf("expected about 30m elapsed, got %s", got)
+	}
+	if state.Pause() {
+		t.Error("ended game cannot be paused")
+	}
+}
+
+func TestRecordHeartbeat(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.StartedAt = clockStart
+	maxGap := time.Minute
+
+	tests := []struct {
+		name     string
+		at       time.Duration
+		downtime time.Duration
+	}{
+		{"first heartbeat", 0, 0},
+		{"regular heartbeat", 30 * time.Second, 0},
+		{"controller down for 10 minutes", 10*time.Minute + 30*time.Second, 9 * time.Minute},
+		{"back to normal", 11 * time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		if got := state.RecordHeartbeat(clockStart.Add(tt.at), maxGap); got != tt.downtime {
+			t.Errorf("%s: expected downtime %s, got %s", tt.name, tt.downtime, got)
+		}
+	}
+	if got := state.ElapsedAt(clockStart.Add(11 * time.Minute)); got != 2*time.Minute {
+		t.Errorf("expected 2m elapsed, got %s", got)
+	}
+
+	// Paused games don't accumulate downtime twice
+	state.PauseAt(clockStart.Add(11 * time.Minute))
+	if got := state.RecordHeartbeat(clockStart.Add(time.Hour), maxGap); got != 0 {
+		t.Errorf("expected no downtime while paused, got %s", got)
+	}
+}
@@ -0,0 +1,213 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConfigMapStore persists game state in the Level 0 cheat ConfigMap, in
+// plain text: players who think of reading it find the mines under the
+// "state" key and the board under the "board" key, see ConfigMapData.
+// Only use it for Level 0 games, where reading the ConfigMap is the
+// intended cheat. Other keys of the ConfigMap, such as the mines of the
+// cheat sheet, are kept.
+type ConfigMapStore struct {
+	client          client.Client
+	namespace       string
+	name            string
+	conflictRetries int
+}
+
+// NewConfigMapStore creates a new ConfigMapStore keeping the state in the
+// cheat ConfigMap of namespace.
+func NewConfigMapStore(c client.Client, namespace string) *ConfigMapStore {
+	return &ConfigMapStore{
+		client:          c,
+		namespace:       namespace,
+		name:            CheatConfigMapName,
+		conflictRetries: DefaultConflictRetries,
+	}
+}
+
+// Load retrieves the game state from the ConfigMap.
+func (s *ConfigMapStore) Load(ctx context.Context) (*GameState, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key(), cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil // No game state exists
+		}
+		return nil, fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	data, ok := cm.Data[StateKey]
+	if !ok {
+		// The cheat sheet of another store
+		return nil, nil
+	}
+
+	state, err := FromJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse game state: %w", err)
+	}
+
+	// Refuse to play a corrupted state
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Save persists the game state to the ConfigMap. Like SecretStore.Save,
+// states saved concurrently are merged and the update retried. The given
+// state is not modified.
+func (s *ConfigMapStore) Save(ctx context.Context, state *GameState) error {
+	data, err := ConfigMapData(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize game state: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key(), cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get configmap: %w", err)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":      "podsweeper",
+					"app.kubernetes.io/component": "game-state",
+				},
+			},
+			Data: data,
+		}
+		err := s.client.Create(ctx, cm)
+		if err == nil {
+			return nil
+		}
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create configmap: %w", err)
+		}
+		// Created concurrently, such as by the cheat sheet: save into it
+		cm = &corev1.ConfigMap{}
+		if err := s.mergeLatest(ctx, cm, state); err != nil {
+			return err
+		}
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		maps.Copy(cm.Data, data)
+	}
+
+	err = s.client.Update(ctx, cm)
+	for attempt := 0; errors.IsConflict(err) && attempt < s.conflictRetries; attempt++ {
+		if mergeErr := s.mergeLatest(ctx, cm, state); mergeErr != nil {
+			return mergeErr
+		}
+		err = s.client.Update(ctx, cm)
+	}
+	if err != nil {
+		if errors.IsConflict(err) {
+			return fmt.Errorf("conflict updating configmap (concurrent modification): %w", err)
+		}
+		return fmt.Errorf("failed to update configmap: %w", err)
+	}
+
+	return nil
+}
+
+// mergeLatest refreshes cm with the latest stored version and sets its
+// data to the merge of the stored state and the state being saved.
+func (s *ConfigMapStore) mergeLatest(ctx context.Context, cm *corev1.ConfigMap, state *GameState) error {
+	if err := s.client.Get(ctx, s.key(), cm); err != nil {
+		return fmt.Errorf("failed to get configmap after conflict: %w", err)
+	}
+
+	merged := state
+	if stored, ok := cm.Data[StateKey]; ok {
+		latest, err := FromJSON([]byte(stored))
+		if err != nil {
+			return fmt.Errorf("failed to parse concurrently saved game state: %w", err)
+		}
+		if merged, err = state.Merge(latest); err != nil {
+			return fmt.Errorf("conflict updating configmap (concurrent modification): %w", err)
+		}
+	}
+
+	data, err := ConfigMapData(merged)
+	if err != nil {
+		return fmt.Errorf("failed to serialize game state: %w", err)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	maps.Copy(cm.Data, data)
+	return nil
+}
+
+// Delete removes the game state and board from the ConfigMap. The
+// ConfigMap and its other keys, such as the mines of the cheat sheet, are
+// kept.
+func (s *ConfigMapStore) Delete(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		cm := &corev1.ConfigMap{}
+		if err := s.client.Get(ctx, s.key(), cm); err != nil {
+			if errors.IsNotFound(err) {
+				return nil // Already deleted
+			}
+			return fmt.Errorf("failed to get configmap: %w", err)
+		}
+		_, hasState := cm.Data[StateKey]
+		_, hasBoard := cm.Data[BoardKey]
+		if !hasState && !hasBoard {
+			return nil
+		}
+		delete(cm.Data, StateKey)
+		delete(cm.Data, BoardKey)
+
+		err := s.client.Update(ctx, cm)
+		if err == nil {
+			return nil
+		}
+		if !errors.IsConflict(err) || attempt >= s.conflictRetries {
+			return fmt.Errorf("failed to delete game state from configmap: %w", err)
+		}
+	}
+}
+
+// Exists checks if the ConfigMap holds a game state.
+func (s *ConfigMapStore) Exists(ctx context.Context) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key(), cm); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check configmap: %w", err)
+	}
+
+	_, ok := cm.Data[StateKey]
+	return ok, nil
+}
+
+// Namespace returns the namespace where the ConfigMap is stored.
+func (s *ConfigMapStore) Namespace() string {
+	return s.namespace
+}
+
+// ConfigMapName returns the name of the ConfigMap.
+func (s *ConfigMapStore) ConfigMapName() string {
+	return s.name
+}
+
+func (s *ConfigMapStore) key() client.ObjectKey {
+	return client.ObjectKey{Namespace: s.namespace, Name: s.name}
+}
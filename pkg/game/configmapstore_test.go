@@ -0,0 +1,170 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestConfigMapStore_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	store := NewConfigMapStore(c, DefaultNamespace)
+
+	if state, err := store.Load(ctx); err != nil || state != nil {
+		t.Fatalf("expected no state in an empty namespace, got %v, %v", state, err)
+	}
+	if exists, err := store.Exists(ctx); err != nil || exists {
+		t.Fatalf("expected no state to exist, got %v, %v", exists, err)
+	}
+
+	state := NewGameState(3, 42)
+	state.SetMine(1, 1)
+	if err := store.Save(ctx, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Players can read the state and the board in plain text
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: DefaultNamespace, Name: CheatConfigMapName}, cm); err != nil {
+		t.Fatalf("expected the cheat ConfigMap, got %v", err)
+	}
+	if cm.Data[BoardKey] != "...\n.*.\n..." {
+		t.Errorf("unexpected board: %q", cm.Data[BoardKey])
+	}
+
+	state.Reveal(0, 0)
+	if err := store.Save(ctx, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := store.Load(ctx)
+	if err != nil || loaded == nil {
+		t.Fatalf("Load failed: %v, %v", loaded, err)
+	}
+	if loaded.Seed != 42 || !loaded.IsMine(1, 1) || !loaded.IsRevealed(0, 0) {
+		t.Error("expected the saved state to be loaded")
+	}
+	if exists, _ := store.Exists(ctx); !exists {
+		t.Error("expected the state to exist")
+	}
+
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Delete(ctx); err != nil {
+		t.Errorf("Delete of a missing state failed: %v", err)
+	}
+}
+
+func TestConfigMapStore_KeepsCheatSheet(t *testing.T) {
+	ctx := context.Background()
+	cheatSheet := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: CheatConfigMapName, Namespace: DefaultNamespace},
+		Data:       map[string]string{"mines": "*.\n.."},
+	}
+	c := fake.NewClientBuilder().WithObjects(cheatSheet).Build()
+	store := NewConfigMapStore(c, DefaultNamespace)
+
+	if state, err := store.Load(ctx); err != nil || state != nil {
+		t.Fatalf("expected a cheat sheet alone to hold no state, got %v, %v", state, err)
+	}
+	if err := store.Save(ctx, NewGameState(2, 0)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(cheatSheet), cm)
+	if cm.Data["mines"] != "*.\n.." || cm.Data[StateKey] == "" {
+		t.Errorf("expected the state saved next to the cheat sheet, got %v", cm.Data)
+	}
+
+	// Deleting the state keeps the cheat sheet
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	cm = &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cheatSheet), cm); err != nil {
+		t.Fatalf("expected the cheat sheet to be kept, got %v", err)
+	}
+	_, hasState := cm.Data[StateKey]
+	_, hasBoard := cm.Data[BoardKey]
+	if hasState || hasBoard || cm.Data["mines"] != "*.\n.." {
+		t.Errorf("expected only the state and board to be deleted, got %v", cm.Data)
+	}
+	if exists, _ := store.Exists(ctx); exists {
+		t.Error("expected no state to exist after Delete")
+	}
+}
+
+func TestConfigMapStore_SaveIntoConcurrentCheatSheet(t *testing.T) {
+	ctx := context.Background()
+	cheatSheet := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: CheatConfigMapName, Namespace: DefaultNamespace},
+		Data:       map[string]string{"mines": "*.\n.."},
+	}
+	c := fake.NewClientBuilder().Build()
+	// The cheat sheet is applied between the Get and the Create of Save
+	racy := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := cl.Create(ctx, cheatSheet.DeepCopy()); err != nil {
+				return err
+			}
+			return cl.Create(ctx, obj, opts...)
+		},
+	})
+
+	if err := NewConfigMapStore(racy, DefaultNamespace).Save(ctx, NewGameState(2, 0)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cm := &corev1.ConfigMap{}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(cheatSheet), cm)
+	if cm.Data["mines"] != "*.\n.." || cm.Data[StateKey] == "" {
+		t.Errorf("expected the state saved into the cheat sheet, got %v", cm.Data)
+	}
+}
+
+func TestConfigMapStore_SaveMergesConcurrentReveals(t *testing.T) {
+	ctx := context.Background()
+
+	base := NewGameState(3, 0)
+	base.SetMine(2, 2)
+	c := fake.NewClientBuilder().Build()
+	store := NewConfigMapStore(c, DefaultNamespace)
+	if err := store.Save(ctx, base); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	concurrent := base.Clone()
+	concurrent.Reveal(0, 0)
+	racing := true
+	racy := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if racing {
+				racing = false
+				if err := store.Save(ctx, concurrent); err != nil {
+					return err
+				}
+			}
+			return cl.Update(ctx, obj, opts...)
+		},
+	})
+
+	ours := base.Clone()
+	ours.Reveal(1, 1)
+	if err := NewConfigMapStore(racy, DefaultNamespace).Save(ctx, ours); err != nil {
+		t.Fatalf("Save with conflict failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.IsRevealed(0, 0) || !loaded.IsRevealed(1, 1) {
+		t.Error("expected both concurrent reveals to be kept")
+	}
+}
@@ -0,0 +1,195 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FlagPrefix starts every CTF flag.
+	FlagPrefix = "PODSWEEPER{"
+
+	// FlagSuffix ends every CTF flag.
+	FlagSuffix = "}"
+
+	// DefaultFlagFragments is the number of fragments hidden in hint pods.
+	DefaultFlagFragments = 4
+
+	// MaxFlagFragments bounds the number of fragments hidden in hint pods.
+	MaxFlagFragments = 16
+
+	// flagSecretBytes is the size of the random part of a flag.
+	flagSecretBytes = 24
+)
+
+// ErrNoFragmentCells is returned by EnableCTF when the board has no hint
+// cell to hide fragments in.
+var ErrNoFragmentCells = errors.New("no hint cell to hide flag fragments in")
+
+// FlagFragment is a piece of the CTF flag.
+type FlagFragment struct {
+	// Index is the 1-based position of the fragment in the flag.
+	Index int `json:"index"`
+
+	// Total is the number of fragments of the flag.
+	Total int `json:"total"`
+
+	// Cell is the hint cell whose pod reveals the fragment. Nil for the
+	// final fragment, which only appears in the victory pod.
+	Cell *Coordinate `json:"cell,omitempty"`
+
+	// Text is the fragment content.
+	Text string `json:"text"`
+}
+
+// String formats the fragment as "index/total:text", the form expected
+// by AssembleFlag.
+func (f FlagFragment) String() string {
+	return fmt.Sprintf("%d/%d:%s", f.Index, f.Total, f.Text)
+}
+
+// CTF holds the secret flag of a capture-the-flag game. Players assemble it
+// from fragments found in hint pods, the last one requiring a win.
+type CTF struct {
+	// Flag is the full flag, including FlagPrefix and FlagSuffix.
+	Flag string `json:"flag"`
+
+	// Fragments split the flag content, in order. The last one is final.
+	Fragments []FlagFragment `json:"fragments"`
+}
+
+// EnableCTF turns the game into a capture-the-flag game: a random flag is
+// split into hidden fragments spread over random hint cells, plus a final
+// fragment only shown on victory. Fewer fragments are hidden if the board
+// has fewer hint cells.
+func (g *GameState) EnableCTF(hidden int) error {
+	if hidden < 1 || hidden > MaxFlagFragments {
+		return fmt.Errorf("flag fragments must be between 1 and %d, got %d", MaxFlagFragments, hidden)
+	}
+
+	var cells []Coordinate
+	w, h := g.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if !g.IsMine(x, y) && g.AdjacentMines(x, y) > 0 {
+				cells = append(cells, Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	if len(cells) == 0 {
+		return ErrNoFragmentCells
+	}
+	mathrand.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	cells = cells[:min(hidden, len(cells))]
+
+	secret := make([]byte, flagSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate flag: %w", err)
+	}
+	content := hex.EncodeToString(secret)
+
+	total := len(cells) + 1
+	ctf := &CTF{Flag: FlagPrefix + content + FlagSuffix}
+	for i := 0; i < total; i++ {
+		fragment := FlagFragment{
+			Index: i + 1,
+			Total: total,
+			Text:  content[i*len(content)/total : (i+1)*len(content)/total],
+		}
+		if i < len(cells) {
+			cell := cells[i]
+			fragment.Cell = &cell
+		}
+		ctf.Fragments = append(ctf.Fragments, fragment)
+	}
+	g.CTF = ctf
+	return nil
+}
+
+// FlagFragmentAt returns the fragment hidden in the hint pod of a cell.
+func (g *GameState) FlagFragmentAt(x, y int) (FlagFragment, bool) {
+	if g.CTF == nil {
+		return FlagFragment{}, false
+	}
+	for _, f := range g.CTF.Fragments {
+		if f.Cell != nil && f.Cell.X == x && f.Cell.Y == y {
+			return f, true
+		}
+	}
+	return FlagFragment{}, false
+}
+
+// FinalFlagFragment returns the fragment revealed on victory.
+func (g *GameState) FinalFlagFragment() (FlagFragment, bool) {
+	if g.CTF == nil || len(g.CTF.Fragments) == 0 {
+		return FlagFragment{}, false
+	}
+	return g.CTF.Fragments[len(g.CTF.Fragments)-1], true
+}
+
+// ValidateFlag reports whether a submitted flag is the game's flag.
+func (g *GameState) ValidateFlag(flag string) bool {
+	if g.CTF == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(flag)), []byte(g.CTF.Flag)) == 1
+}
+
+// AssembleFlag builds a flag from fragments formatted as "index/total:text",
+// in any order. Every fragment is required.
+func AssembleFlag(fragments []string) (string, error) {
+	var parsed []FlagFragment
+	for _, s := range fragments {
+		position, text, ok := strings.Cut(strings.TrimSpace(s), ":")
+		index, total, ok2 := strings.Cut(position, "/")
+		if !ok || !ok2 {
+			return "", fmt.Errorf("invalid fragment %q: expected index/total:text", s)
+		}
+		i, err1 := strconv.Atoi(index)
+		n, err2 := strconv.Atoi(total)
+		if err1 != nil || err2 != nil || i < 1 || i > n {
+			return "", fmt.Errorf("invalid fragment position %q", position)
+		}
+		parsed = append(parsed, FlagFragment{Index: i, Total: n, Text: text})
+	}
+	if len(parsed) == 0 {
+		return "", fmt.Errorf("no fragment")
+	}
+
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].Index < parsed[j].Index })
+	total := parsed[0].Total
+	if len(parsed) != total {
+		return "", fmt.Errorf("got %d fragments, need %d", len(parsed), total)
+	}
+	var b strings.Builder
+	for i, f := range parsed {
+		if f.Total != total || f.Index != i+1 {
+			return "", fmt.Errorf("fragments do not belong to the same flag or are duplicated")
+		}
+		b.WriteString(f.Text)
+	}
+	return FlagPrefix + b.String() + FlagSuffix, nil
+}
+
+// cloneCTF deep copies a CTF.
+func cloneCTF(ctf *CTF) *CTF {
+	if ctf == nil {
+		return nil
+	}
+	clone := &CTF{Flag: ctf.Flag, Fragments: make([]FlagFragment, len(ctf.Fragments))}
+	for i, f := range ctf.Fragments {
+		clone.Fragments[i] = f
+		if f.Cell != nil {
+			cell := *f.Cell
+			clone.Fragments[i].Cell = &cell
+		}
+	}
+	return clone
+}
@@ -0,0 +1,124 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func newCTFTestState(t *testing.T, hidden int) *GameState {
+	t.Helper()
+	state := NewGameState(6, 1)
+	state.SetMine(1, 1)
+	state.SetMine(4, 4)
+	if err := state.EnableCTF(hidden); err != nil {
+		t.Fatalf("EnableCTF failed: %v", err)
+	}
+	return state
+}
+
+func TestEnableCTF(t *testing.T) {
+	state := newCTFTestState(t, 4)
+
+	if !strings.HasPrefix(state.CTF.Flag, FlagPrefix) || !strings.HasSuffix(state.CTF.Flag, FlagSuffix) {
+		t.Fatalf("unexpected flag format %q", state.CTF.Flag)
+	}
+	if len(state.CTF.Fragments) != 5 {
+		t.Fatalf("expected 4 hidden fragments and a final one, got %d", len(state.CTF.Fragments))
+	}
+
+	seen := make(map[Coordinate]bool)
+	for _, f := range state.CTF.Fragments[:4] {
+		if f.Cell == nil {
+			t.Fatal("expected hidden fragments to be placed on a cell")
+		}
+		if state.IsMine(f.Cell.X, f.Cell.Y) || state.AdjacentMines(f.Cell.X, f.Cell.Y) == 0 {
+			t.Errorf("fragment placed on %v, which has no hint pod", *f.Cell)
+		}
+		if seen[*f.Cell] {
+			t.Errorf("two fragments placed on %v", *f.Cell)
+		}
+		seen[*f.Cell] = true
+	}
+
+	final, ok := state.FinalFlagFragment()
+	if !ok || final.Cell != nil || final.Index != 5 {
+		t.Errorf("unexpected final fragment %+v", final)
+	}
+}
+
+func TestEnableCTFLimits(t *testing.T) {
+	state := NewGameState(3, 1)
+	if err := state.EnableCTF(0); err == nil {
+		t.Error("expected an error for no fragment")
+	}
+	if err := state.EnableCTF(MaxFlagFragments + 1); err == nil {
+		t.Error("expected an error for too many fragments")
+	}
+	if err := state.EnableCTF(2); err != ErrNoFragmentCells {
+		t.Errorf("expected ErrNoFragmentCells on a board without mines, got %v", err)
+	}
+
+	// A single mine in a 2x2 board leaves 3 hint cells
+	state = NewGameState(2, 1)
+	state.SetMine(0, 0)
+	if err := state.EnableCTF(MaxFlagFragments); err != nil {
+		t.Fatalf("EnableCTF failed: %v", err)
+	}
+	if len(state.CTF.Fragments) != 4 {
+		t.Errorf("expected fragments capped to hint cells, got %d", len(state.CTF.Fragments))
+	}
+}
+
+func TestAssembleAndValidateFlag(t *testing.T) {
+	state := newCTFTestState(t, 3)
+
+	var fragments []string
+	for i := len(state.CTF.Fragments) - 1; i >= 0; i-- {
+		fragments = append(fragments, state.CTF.Fragments[i].String())
+	}
+	flag, err := AssembleFlag(fragments)
+	if err != nil {
+		t.Fatalf("AssembleFlag failed: %v", err)
+	}
+	if !state.ValidateFlag(flag) || !state.ValidateFlag(flag+"\n") {
+		t.Errorf("expected assembled flag %q to be valid", flag)
+	}
+	if state.ValidateFlag(FlagPrefix + "guess" + FlagSuffix) {
+		t.Error("expected a wrong flag to be rejected")
+	}
+
+	if _, err := AssembleFlag(fragments[1:]); err == nil {
+		t.Error("expected an error without the final fragment")
+	}
+	if _, err := AssembleFlag([]string{"1/2:ab", "1/2:cd"}); err == nil {
+		t.Error("expected an error for duplicated fragments")
+	}
+	if _, err := AssembleFlag([]string{"nope"}); err == nil {
+		t.Error("expected an error for a malformed fragment")
+	}
+}
+
+func TestFlagFragmentAt(t *testing.T) {
+	state := newCTFTestState(t, 2)
+	cell := *state.CTF.Fragments[0].Cell
+
+	f, ok := state.FlagFragmentAt(cell.X, cell.Y)
+	if !ok || f.Index != 1 {
+		t.Errorf("expected fragment 1 at %v, got %+v", cell, f)
+	}
+	if _, ok := state.FlagFragmentAt(1, 1); ok {
+		t.Error("expected no fragment on a mine")
+	}
+	if _, ok := NewGameState(3, 1).FlagFragmentAt(0, 0); ok {
+		t.Error("expected no fragment outside CTF mode")
+	}
+}
+
+func TestCloneCopiesCTF(t *testing.T) {
+	state := newCTFTestState(t, 2)
+	clone := state.Clone()
+	clone.CTF.Fragments[0].Cell.X = 99
+	if state.CTF.Fragments[0].Cell.X == 99 {
+		t.Error("expected Clone to deep copy the CTF")
+	}
+}
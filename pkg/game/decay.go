@@ -0,0 +1,37 @@
+package game
+
+import "time"
+
+// HintExpiry returns when the hint of the cell at (x, y) expires in hint
+// decay mode, or the zero time if it never does. Cells without a hint, and
+// cells revealed before their reveal time was recorded, never expire.
+func (g *GameState) HintExpiry(x, y int) time.Time {
+	cell, ok := g.Cell(x, y)
+	if _, shown := g.HintAt(x, y); !ok || !shown || g.HintDecay <= 0 || !cell.Revealed || cell.RevealedAt.IsZero() {
+		return time.Time{}
+	}
+	return cell.RevealedAt.Add(g.HintDecay)
+}
+
+// HintDecayed reports whether the hint of the cell at (x, y) expired as of now.
+func (g *GameState) HintDecayed(x, y int, now time.Time) bool {
+	expiry := g.HintExpiry(x, y)
+	return !expiry.IsZero() && !now.Before(expiry)
+}
+
+// DecayedHints returns the cells whose hint expired as of now.
+func (g *GameState) DecayedHints(now time.Time) []Coordinate {
+	if g.HintDecay <= 0 {
+		return nil
+	}
+	var decayed []Coordinate
+	w, h := g.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if g.HintDecayed(x, y, now) {
+				decayed = append(decayed, Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	return decayed
+}
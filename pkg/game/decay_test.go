@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHintDecay(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	// M 1 .
+	// 1 1 .
+	// . . .
+	state := NewGameState(3, 1)
+	state.SetMine(0, 0)
+	state.RevealWith(1, 0, RevealInfo{At: start})
+	state.RevealWith(0, 1, RevealInfo{At: start.Add(time.Minute)})
+	state.RevealWith(2, 2, RevealInfo{At: start, Propagated: true})
+
+	if got := state.DecayedHints(start.Add(time.Hour)); got != nil {
+		t.Errorf("expected hints to last the whole game without decay, got %v", got)
+	}
+
+	state.HintDecay = 30 * time.Second
+	if !state.HintExpiry(1, 0).Equal(start.Add(30 * time.Second)) {
+		t.Errorf("unexpected expiry %v", state.HintExpiry(1, 0))
+	}
+	if !state.HintExpiry(2, 2).IsZero() {
+		t.Error("expected cells without a hint never to expire")
+	}
+
+	tests := []struct {
+		at   time.Duration
+		want []Coordinate
+	}{
+		{29 * time.Second, nil},
+		{30 * time.Second, []Coordinate{{X: 1, Y: 0}}},
+		{2 * time.Minute, []Coordinate{{X: 0, Y: 1}, {X: 1, Y: 0}}},
+	}
+	for _, tt := range tests {
+		got := state.DecayedHints(start.Add(tt.at))
+		if len(got) != len(tt.want) {
+			t.Errorf("at %v: expected %v to have decayed, got %v", tt.at, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("at %v: expected %v to have decayed, got %v", tt.at, tt.want, got)
+			}
+		}
+	}
+
+	if clone := state.Clone(); clone.HintDecay != state.HintDecay {
+		t.Error("expected Clone to keep the hint decay")
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import "time"
+
+// Defusal is a mine hit in defusal mode, waiting for the player who hit it
+// to cut the right wire of its bomb pod.
+type Defusal struct {
+	Coordinate
+
+	// Wires are the colors of the wires of the challenge, see package
+	// defusal.
+	Wires []string `json:"wires"`
+
+	// By identifies who hit the mine, if known.
+	By string `json:"by,omitempty"`
+
+	// Deadline is when the bomb explodes if no wire was cut.
+	Deadline time.Time `json:"deadline"`
+}
+
+// StartDefusal records a defusal challenge for the hidden mine it targets.
+// Returns false outside defusal mode, if there is no hidden mine there, or
+// if a challenge is already pending for it.
+func (g *GameState) StartDefusal(d Defusal) bool {
+	if !g.Defusal || g.Status != StatusPlaying || !g.IsMine(d.X, d.Y) || g.IsRevealed(d.X, d.Y) || g.IsDefused(d.X, d.Y) {
+		return false
+	}
+	if _, ok := g.PendingDefusal(d.X, d.Y); ok {
+		return false
+	}
+	d.Wires = append([]string(nil), d.Wires...)
+	g.Defusals = append(g.Defusals, d)
+	return true
+}
+
+// PendingDefusal returns the defusal challenge pending for the mine at
+// (x, y).
+func (g *GameState) PendingDefusal(x, y int) (Defusal, bool) {
+	for _, d := range g.Defusals {
+		if d.X == x && d.Y == y {
+			return d, true
+		}
+	}
+	return Defusal{}, false
+}
+
+// EndDefusal ends the challenge pending for the mine at (x, y). A defused
+// mine is flagged; otherwise the caller sets it off with HitMine.
+// Returns false if no challenge was pending.
+func (g *GameState) EndDefusal(x, y int, defused bool) bool {
+	for i, d := range g.Defusals {
+		if d.X == x && d.Y == y {
+			g.Defusals = append(g.Defusals[:i:i], g.Defusals[i+1:]...)
+			if defused {
+				g.SetFlag(x, y, true)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// cloneDefusals deep copies pending defusal challenges.
+func cloneDefusals(defusals []Defusal) []Defusal {
+	if defusals == nil {
+		return nil
+	}
+	clone := make([]Defusal, len(defusals))
+	for i, d := range defusals {
+		d.Wires = append([]string(nil), d.Wires...)
+		clone[i] = d
+	}
+	return clone
+}
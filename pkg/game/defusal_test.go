@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefusal(t *testing.T) {
+	state := NewGameState(3, 1)
+	state.SetMine(1, 1)
+	d := Defusal{Coordinate: Coordinate{X: 1, Y: 1}, Wires: []string{"red", "blue", "white"}, Deadline: time.Now()}
+
+	if state.StartDefusal(d) {
+		t.Fatal("expected no defusal outside defusal mode")
+	}
+	state.Defusal = true
+	if state.StartDefusal(Defusal{Coordinate: Coordinate{X: 0, Y: 0}}) {
+		t.Error("expected no defusal of a safe cell")
+	}
+	if !state.StartDefusal(d) {
+		t.Fatal("expected the defusal started")
+	}
+	if state.StartDefusal(d) {
+		t.Error("expected a single defusal per mine")
+	}
+
+	clone := state.Clone()
+	clone.Defusals[0].Wires[0] = "black"
+	if got, ok := state.PendingDefusal(1, 1); !ok || got.Wires[0] != "red" {
+		t.Errorf("expected the clone independent, got %+v", got)
+	}
+
+	if !state.EndDefusal(1, 1, true) || !state.IsFlagged(1, 1) || state.IsRevealed(1, 1) {
+		t.Error("expected the defused mine flagged and hidden")
+	}
+	if _, ok := state.PendingDefusal(1, 1); ok || state.EndDefusal(1, 1, true) {
+		t.Error("expected the defusal ended once")
+	}
+
+	if !clone.EndDefusal(1, 1, false) || clone.IsFlagged(1, 1) || clone.Status != StatusPlaying {
+		t.Error("expected a failed defusal to leave the mine to the caller")
+	}
+}
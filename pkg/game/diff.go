@@ -0,0 +1,128 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ChangeKind identifies what changed between two states.
+type ChangeKind string
+
+const (
+	// ChangeDimensions reports grids of different sizes; cells are not compared.
+	ChangeDimensions ChangeKind = "dimensions"
+	// ChangeStatus reports a game status change.
+	ChangeStatus ChangeKind = "status"
+	// ChangeLevel reports a level change.
+	ChangeLevel ChangeKind = "level"
+	// ChangeRevealed reports a cell that became revealed.
+	ChangeRevealed ChangeKind = "revealed"
+	// ChangeHidden reports a revealed cell that is no longer revealed.
+	ChangeHidden ChangeKind = "hidden"
+	// ChangeFlagged reports a cell that became flagged.
+	ChangeFlagged ChangeKind = "flagged"
+	// ChangeUnflagged reports a cell whose flag was removed.
+	ChangeUnflagged ChangeKind = "unflagged"
+	// ChangeQuestioned reports a cell that became question-marked.
+	ChangeQuestioned ChangeKind = "questioned"
+	// ChangeUnquestioned reports a cell whose question mark was removed.
+	ChangeUnquestioned ChangeKind = "unquestioned"
+	// ChangeMineAdded reports a mine placed on a cell.
+	ChangeMineAdded ChangeKind = "mineAdded"
+	// ChangeMineRemoved reports a mine removed from a cell.
+	ChangeMineRemoved ChangeKind = "mineRemoved"
+	// ChangeDefused reports a mine hit that cost a life.
+	ChangeDefused ChangeKind = "defused"
+)
+
+// Change is a single difference between two states.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Cell is set for cell-level changes.
+	Cell *Coordinate `json:"cell,omitempty"`
+
+	// From and To describe the old and new values of state-level changes.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// String returns a human-readable representation of the change.
+func (c Change) String() string {
+	if c.Cell != nil {
+		return fmt.Sprintf("%s %s", c.Kind, c.Cell)
+	}
+	return fmt.Sprintf("%s %s -> %s", c.Kind, c.From, c.To)
+}
+
+// Diff returns the changes needed to go from g to other, in a stable order:
+// state-level changes first, then cell changes by column and row.
+// An empty result means both states show the same board.
+func (g *GameState) Diff(other *GameState) []Change {
+	var changes []Change
+
+	w, h := g.Dimensions()
+	ow, oh := other.Dimensions()
+	if w != ow || h != oh {
+		return append(changes, Change{
+			Kind: ChangeDimensions,
+			From: fmt.Sprintf("%dx%d", w, h),
+			To:   fmt.Sprintf("%dx%d", ow, oh),
+		})
+	}
+
+	if g.Status != other.Status {
+		changes = append(changes, Change{Kind: ChangeStatus, From: string(g.Status), To: string(other.Status)})
+	}
+	if g.Level != other.Level {
+		changes = append(changes, Change{Kind: ChangeLevel, From: strconv.Itoa(g.Level), To: strconv.Itoa(other.Level)})
+	}
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			changes = append(changes, diffCell(x, y, g.Cells[x][y], other.Cells[x][y])...)
+		}
+	}
+	return changes
+}
+
+// diffCell compares the player-visible and mine fields of two cells.
+func diffCell(x, y int, a, b Cell) []Change {
+	var changes []Change
+	add := func(kind ChangeKind) {
+		changes = append(changes, Change{Kind: kind, Cell: &Coordinate{X: x, Y: y}})
+	}
+
+	if a.Mine != b.Mine {
+		if b.Mine {
+			add(ChangeMineAdded)
+		} else {
+			add(ChangeMineRemoved)
+		}
+	}
+	if !a.Defused && b.Defused {
+		add(ChangeDefused)
+	}
+	if a.Revealed != b.Revealed {
+		if b.Revealed {
+			add(ChangeRevealed)
+		} else {
+			add(ChangeHidden)
+		}
+	}
+	if a.Flagged != b.Flagged {
+		if b.Flagged {
+			add(ChangeFlagged)
+		} else {
+			add(ChangeUnflagged)
+		}
+	}
+	if a.Question != b.Question {
+		if b.Question {
+			add(ChangeQuestioned)
+		} else {
+			add(ChangeUnquestioned)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*GameState)
+		want   []Change
+	}{
+		{"identical", func(*GameState) {}, nil},
+		{"reveal", func(g *GameState) { g.Reveal(1, 2) }, []Change{
+			{Kind: ChangeRevealed, Cell: &Coordinate{X: 1, Y: 2}},
+		}},
+		{"flag and question", func(g *GameState) {
+			g.SetFlag(0, 1, true)
+			g.SetQuestion(2, 2, true)
+		}, []Change{
+			{Kind: ChangeFlagged, Cell: &Coordinate{X: 0, Y: 1}},
+			{Kind: ChangeQuestioned, Cell: &Coordinate{X: 2, Y: 2}},
+		}},
+		{"unflag", func(g *GameState) { g.SetFlag(2, 0, false) }, []Change{
+			{Kind: ChangeUnflagged, Cell: &Coordinate{X: 2, Y: 0}},
+		}},
+		{"mine moved", func(g *GameState) {
+			g.ClearMine(0, 0)
+			g.SetMine(2, 1)
+		}, []Change{
+			{Kind: ChangeMineRemoved, Cell: &Coordinate{X: 0, Y: 0}},
+			{Kind: ChangeMineAdded, Cell: &Coordinate{X: 2, Y: 1}},
+		}},
+		{"status and level", func(g *GameState) {
+			g.Level = 2
+			g.SetLost()
+		}, []Change{
+			{Kind: ChangeStatus, From: "playing", To: "lost"},
+			{Kind: ChangeLevel, From: "0", To: "2"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewGameState(3, 0)
+			base.SetMine(0, 0)
+			base.SetFlag(2, 0, true)
+
+			other := base.Clone()
+			tt.change(other)
+
+			got := base.Diff(other)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d changes, got %v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i].String() != tt.want[i].String() {
+					t.Errorf("change %d: expected %s, got %s", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffDimensions(t *testing.T) {
+	changes := NewGameState(3, 0).Diff(NewRectGameState(4, 3, 0))
+	if len(changes) != 1 || changes[0].Kind != ChangeDimensions {
+		t.Fatalf("expected a single dimensions change, got %v", changes)
+	}
+	if changes[0].From != "3x3" || changes[0].To != "4x3" {
+		t.Errorf("unexpected dimensions change: %s", changes[0])
+	}
+}
+
+func TestDiffIsReversible(t *testing.T) {
+	a := NewGameState(4, 0)
+	b := a.Clone()
+	b.Reveal(1, 1)
+	b.SetFlag(3, 3, true)
+
+	forward := a.Diff(b)
+	backward := b.Diff(a)
+	if len(forward) != len(backward) {
+		t.Fatalf("expected symmetric change counts, got %d and %d", len(forward), len(backward))
+	}
+	if backward[0].Kind != ChangeHidden || backward[1].Kind != ChangeUnflagged {
+		t.Errorf("unexpected backward changes: %v", backward)
+	}
+}
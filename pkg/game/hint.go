@@ -0,0 +1,83 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// HintType is what revealed safe cells tell players.
+type HintType string
+
+const (
+	// HintCount shows the number of adjacent mines, the classic hints.
+	// Empty is HintCount.
+	HintCount HintType = "count"
+
+	// HintHotCold is the blind variant: hints only tell how close the
+	// nearest mine is, see Temperature. Every safe cell shows one, so
+	// revealing a cell never reveals its neighbors.
+	HintHotCold HintType = "hot-cold"
+)
+
+// Temperatures shown by the hints of hot/cold games.
+const (
+	// Hot is a cell next to a mine.
+	Hot = "hot"
+	// Warm is a cell two cells away from the nearest mine.
+	Warm = "warm"
+	// Cold is a cell farther away from every mine.
+	Cold = "cold"
+)
+
+// ParseHintType returns the hint type named s. Empty is HintCount.
+func ParseHintType(s string) (HintType, error) {
+	switch HintType(s) {
+	case "", HintCount:
+		return HintCount, nil
+	case HintHotCold:
+		return HintHotCold, nil
+	}
+	return "", fmt.Errorf("unknown hint type %q, use %s or %s", s, HintCount, HintHotCold)
+}
+
+// HotCold reports whether the game shows temperatures instead of counts.
+func (g *GameState) HotCold() bool {
+	return g.HintType == HintHotCold
+}
+
+// HintAt returns the hint the safe cell at (x, y) shows once revealed: the
+// number of adjacent mines, or its temperature in hot/cold games. Returns
+// false for cells showing no hint: mines, cells out of bounds and, in
+// classic games, cells without adjacent mines.
+func (g *GameState) HintAt(x, y int) (string, bool) {
+	if !g.IsValidCoordinate(x, y) || g.Cells[x][y].Mine {
+		return "", false
+	}
+	if g.HotCold() {
+		return g.Temperature(x, y), true
+	}
+	adjacent := g.AdjacentMines(x, y)
+	return strconv.Itoa(adjacent), adjacent > 0
+}
+
+// Temperature returns how close the nearest mine is to the cell at (x, y).
+func (g *GameState) Temperature(x, y int) string {
+	if g.AdjacentMines(x, y) > 0 {
+		return Hot
+	}
+	for dx := -2; dx <= 2; dx++ {
+		for dy := -2; dy <= 2; dy++ {
+			if g.IsMine(x+dx, y+dy) {
+				return Warm
+			}
+		}
+	}
+	return Cold
+}
+
+// Propagates reports whether revealing the safe cell at (x, y) reveals its
+// neighbors too: classic cells without adjacent mines do, cells of hot/cold
+// games never do.
+func (g *GameState) Propagates(x, y int) bool {
+	return !g.HotCold() && g.AdjacentMines(x, y) == 0
+}
@@ -0,0 +1,68 @@
+package game
+
+import "testing"
+
+func TestHintAt(t *testing.T) {
+	// M . . . .
+	state := NewRectGameState(5, 1, 1)
+	state.SetMine(0, 0)
+
+	tests := []struct {
+		x         int
+		count     string
+		countOK   bool
+		hotCold   string
+		propagate bool
+	}{
+		{0, "", false, "", false},
+		{1, "1", true, Hot, false},
+		{2, "0", false, Warm, true},
+		{3, "0", false, Cold, true},
+	}
+	for _, tt := range tests {
+		state.HintType = ""
+		if got, ok := state.HintAt(tt.x, 0); got != tt.count || ok != tt.countOK {
+			t.Errorf("HintAt(%d) = %q, %v, want %q, %v", tt.x, got, ok, tt.count, tt.countOK)
+		}
+		// Only safe cells are revealed
+		if got := state.Propagates(tt.x, 0); tt.x > 0 && got != tt.propagate {
+			t.Errorf("Propagates(%d) = %v, want %v", tt.x, got, tt.propagate)
+		}
+
+		state.HintType = HintHotCold
+		if got, ok := state.HintAt(tt.x, 0); got != tt.hotCold || ok != (tt.x > 0) {
+			t.Errorf("hot/cold HintAt(%d) = %q, %v, want %q", tt.x, got, ok, tt.hotCold)
+		}
+		if state.Propagates(tt.x, 0) {
+			t.Errorf("expected hot/cold cells never to propagate, (%d,0) does", tt.x)
+		}
+	}
+}
+
+func TestParseHintType(t *testing.T) {
+	for in, want := range map[string]HintType{"": HintCount, "count": HintCount, "hot-cold": HintHotCold} {
+		if got, err := ParseHintType(in); err != nil || got != want {
+			t.Errorf("ParseHintType(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseHintType("lukewarm"); err == nil {
+		t.Error("expected an unknown hint type to be refused")
+	}
+}
+
+func TestToBoardString_HotCold(t *testing.T) {
+	state := NewRectGameState(5, 1, 1)
+	state.SetMine(0, 0)
+	state.HintType = HintHotCold
+	for x := 1; x < 4; x++ {
+		state.Reveal(x, 0)
+	}
+
+	if got := state.ToBoardString(WithPlayerView()); got != ".HWC." {
+		t.Errorf("expected temperatures in the player view, got %q", got)
+	}
+	// The full board still round-trips
+	if got := state.ToBoardString(); got != "*100." {
+		t.Errorf("expected hint digits in the full board, got %q", got)
+	}
+}
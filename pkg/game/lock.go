@@ -0,0 +1,237 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultLockName is the name of the Lease locking a game.
+	DefaultLockName = "podsweeper-game-lock"
+
+	// DefaultLockDuration is how long a game stays locked by a Gamemaster
+	// that stopped renewing its Lease.
+	DefaultLockDuration = 15 * time.Second
+)
+
+// ErrLockHeld is returned when the game is locked by another Gamemaster.
+var ErrLockHeld = errors.New("game locked by another gamemaster")
+
+// IsLockHeld reports whether err comes from a game locked by another
+// Gamemaster.
+func IsLockHeld(err error) bool {
+	return errors.Is(err, ErrLockHeld)
+}
+
+// Lock keeps a single Gamemaster driving a game, even when it is deployed
+// twice by accident: the state may only be changed by the holder of the
+// Lease of the game namespace. The Lease is renewed as the game is played
+// and by Start, and taken over once its holder stopped renewing it for
+// Duration.
+type Lock struct {
+	// Client manages the Lease.
+	Client client.Client
+
+	// Reader reads the Lease. Defaults to Client; use an uncached reader
+	// to avoid caching Leases.
+	Reader client.Reader
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	// Name of the Lease. Defaults to DefaultLockName.
+	Name string
+
+	// Identity names this Gamemaster, uniquely among its replicas.
+	Identity string
+
+	// Duration defaults to DefaultLockDuration. The Lease is renewed three
+	// times per duration.
+	Duration time.Duration
+
+	mu      sync.Mutex
+	renewed time.Time
+}
+
+// Acquire takes or renews the lock. Returns an error wrapping ErrLockHeld
+// if another Gamemaster holds it. A nil Lock is always acquired.
+func (l *Lock) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	return l.acquire(ctx, time.Now())
+}
+
+func (l *Lock) acquire(ctx context.Context, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// No one else can take it before it expires
+	if !l.renewed.IsZero() && now.Sub(l.renewed) < l.duration()/3 {
+		return nil
+	}
+	l.renewed = time.Time{}
+
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: l.Namespace, Name: l.name()}
+	err := l.reader().Get(ctx, key, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+		l.hold(lease, now)
+		if err := l.Client.Create(ctx, lease); apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("%w: lost the race for the lease", ErrLockHeld)
+		} else if err != nil {
+			return fmt.Errorf("failed to create game lock: %w", err)
+		}
+		l.renewed = now
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get game lock: %w", err)
+	}
+
+	if holder := ptr.Deref(lease.Spec.HolderIdentity, ""); holder != "" && holder != l.Identity && !expired(lease, now) {
+		return fmt.Errorf("%w: held by %s", ErrLockHeld, holder)
+	}
+	l.hold(lease, now)
+	if err := l.Client.Update(ctx, lease); apierrors.IsConflict(err) {
+		return fmt.Errorf("%w: lost the race for the lease", ErrLockHeld)
+	} else if err != nil {
+		return fmt.Errorf("failed to renew game lock: %w", err)
+	}
+	l.renewed = now
+	return nil
+}
+
+// hold sets this Gamemaster as holder of lease.
+func (l *Lock) hold(lease *coordinationv1.Lease, now time.Time) {
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != l.Identity {
+		lease.Spec.HolderIdentity = ptr.To(l.Identity)
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		if lease.ResourceVersion != "" {
+			lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		}
+	}
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(l.duration().Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+}
+
+// Release gives the lock up, if held, letting another Gamemaster take the
+// game right away.
+func (l *Lock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.renewed = time.Time{}
+
+	lease := &coordinationv1.Lease{}
+	err := l.reader().Get(ctx, client.ObjectKey{Namespace: l.Namespace, Name: l.name()}, lease)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != l.Identity {
+		return nil
+	}
+	err = l.Client.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return fmt.Errorf("failed to release game lock: %w", err)
+	}
+	return nil
+}
+
+// Start implements manager.Runnable, keeping the lock while the
+// Gamemaster runs and releasing it on shutdown.
+func (l *Lock) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("lock")
+	ticker := time.NewTicker(l.duration() / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := l.Acquire(ctx); IsLockHeld(err) {
+			logger.Info("game locked by another gamemaster, standing by", "reason", err.Error())
+		} else if err != nil {
+			logger.Error(err, "failed to acquire game lock")
+		}
+		select {
+		case <-ctx.Done():
+			if err := l.Release(context.Background()); err != nil {
+				logger.Error(err, "failed to release game lock")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: only the
+// leader plays, so standby replicas must not hold the lock.
+func (l *Lock) NeedLeaderElection() bool {
+	return true
+}
+
+func (l *Lock) reader() client.Reader {
+	if l.Reader == nil {
+		return l.Client
+	}
+	return l.Reader
+}
+
+func (l *Lock) name() string {
+	if l.Name == "" {
+		return DefaultLockName
+	}
+	return l.Name
+}
+
+func (l *Lock) duration() time.Duration {
+	if l.Duration <= 0 {
+		return DefaultLockDuration
+	}
+	return l.Duration
+}
+
+// expired reports whether the holder of lease stopped renewing it.
+func expired(lease *coordinationv1.Lease, now time.Time) bool {
+	spec := lease.Spec
+	if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return !now.Before(spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second))
+}
+
+// LockedStore only changes the game state while holding Lock, so that two
+// Gamemasters can't both drive the game. Loading is not locked.
+type LockedStore struct {
+	Store
+	Lock *Lock
+}
+
+// NewLockedStore wraps store with lock.
+func NewLockedStore(store Store, lock *Lock) *LockedStore {
+	return &LockedStore{Store: store, Lock: lock}
+}
+
+// Save implements Store, once the lock is acquired.
+func (s *LockedStore) Save(ctx context.Context, state *GameState) error {
+	if err := s.Lock.Acquire(ctx); err != nil {
+		return err
+	}
+	return s.Store.Save(ctx, state)
+}
+
+// Delete implements Store, once the lock is acquired.
+func (s *LockedStore) Delete(ctx context.Context) error {
+	if err := s.Lock.Acquire(ctx); err != nil {
+		return err
+	}
+	return s.Store.Delete(ctx)
+}
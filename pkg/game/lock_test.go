@@ -0,0 +1,98 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	a := &Lock{Client: c, Namespace: "game", Identity: "gm-a", Duration: 30 * time.Second}
+	b := &Lock{Client: c, Namespace: "game", Identity: "gm-b", Duration: 30 * time.Second}
+	now := time.Now()
+
+	if err := a.acquire(ctx, now); err != nil {
+		t.Fatalf("expected a free lock to be acquired, got %v", err)
+	}
+	if err := b.acquire(ctx, now); !IsLockHeld(err) {
+		t.Fatalf("expected a held lock to be refused, got %v", err)
+	}
+	if err := a.acquire(ctx, now.Add(20*time.Second)); err != nil {
+		t.Fatalf("expected the holder to renew the lock, got %v", err)
+	}
+	if err := b.acquire(ctx, now.Add(40*time.Second)); !IsLockHeld(err) {
+		t.Fatalf("expected a renewed lock to be refused, got %v", err)
+	}
+
+	// Taken over once the holder stopped renewing it
+	if err := b.acquire(ctx, now.Add(51*time.Second)); err != nil {
+		t.Fatalf("expected an expired lock to be taken over, got %v", err)
+	}
+	lease := &coordinationv1.Lease{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "game", Name: DefaultLockName}, lease); err != nil {
+		t.Fatalf("failed to get lease: %v", err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != "gm-b" || ptr.Deref(lease.Spec.LeaseTransitions, 0) != 1 {
+		t.Errorf("expected gm-b to hold the lock after one transition, got %+v", lease.Spec)
+	}
+	if err := a.acquire(ctx, now.Add(52*time.Second)); !IsLockHeld(err) {
+		t.Errorf("expected the previous holder to be refused, got %v", err)
+	}
+
+	// Releasing hands the game over right away
+	if err := a.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := a.acquire(ctx, now.Add(53*time.Second)); !IsLockHeld(err) {
+		t.Errorf("expected releasing a lock held by another to do nothing, got %v", err)
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := a.acquire(ctx, now.Add(54*time.Second)); err != nil {
+		t.Errorf("expected a released lock to be acquired, got %v", err)
+	}
+}
+
+func TestLockedStore(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	holder := &Lock{Client: c, Namespace: "game", Identity: "gm-a"}
+	if err := holder.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	store := NewMemoryStore()
+	locked := NewLockedStore(store, &Lock{Client: c, Namespace: "game", Identity: "gm-b"})
+	if err := locked.Save(ctx, NewGameState(3, 0)); !IsLockHeld(err) {
+		t.Fatalf("expected Save to be refused without the lock, got %v", err)
+	}
+	if err := locked.Delete(ctx); !IsLockHeld(err) {
+		t.Errorf("expected Delete to be refused without the lock, got %v", err)
+	}
+	if state, err := locked.Load(ctx); err != nil || state != nil {
+		t.Errorf("expected Load to be unlocked, got %v, %v", state, err)
+	}
+
+	if err := holder.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := locked.Save(ctx, NewGameState(3, 0)); err != nil {
+		t.Fatalf("expected Save once the lock is free, got %v", err)
+	}
+	if exists, _ := store.Exists(ctx); !exists {
+		t.Error("expected the state to be saved")
+	}
+
+	// Without a lock, the store is unlocked
+	if err := NewLockedStore(store, nil).Delete(ctx); err != nil {
+		t.Errorf("expected a nil lock to be acquired, got %v", err)
+	}
+}
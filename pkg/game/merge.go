@@ -0,0 +1,187 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrIncompatibleStates is returned by Merge when the two states are not
+// versions of the same game (different grid, or mine layout without a
+// mine shift to explain it).
+var ErrIncompatibleStates = errors.New("states are not versions of the same game")
+
+// ErrShiftConflict is returned by Merge when a cell was revealed
+// concurrently with a mine shift that changed its mine or its hint: the
+// move must be played again on the shifted layout.
+var ErrShiftConflict = errors.New("a cell was revealed concurrently with a mine shift changing it")
+
+// statusRank orders statuses so that merging keeps the most final outcome.
+var statusRank = map[GameStatus]int{
+	StatusPlaying: 0,
+	StatusWon:     1,
+	StatusLost:    2,
+}
+
+// phaseRank orders phases so that merging keeps the furthest along.
+var phaseRank = map[Phase]int{
+	PhasePending:  0,
+	PhaseSpawning: 1,
+	PhaseReady:    2,
+	PhasePlaying:  3,
+	PhaseFinished: 4,
+	PhaseCleaning: 5,
+}
+
+// Merge combines two diverged versions of the same game, typically the state
+// a reconcile modified and the one concurrently saved by another, so that
+// simultaneous reveals converge instead of one overwriting the other:
+//   - revealed cells are the union, keeping the earliest reveal metadata
+//   - flags and question marks are the union, dropped on revealed cells
+//   - pod UIDs of hidden cells are those of g, or of other when unknown to g
+//   - clicks and paused duration are the maximum of both
+//   - power-up charges earned and spent are the maximum of both, the armed
+//     power-up and spared mines are those of g
+//   - pending defusal challenges are those of g
+//   - defused mines are the union, and team lives and lives the minimum of
+//     both
+//   - recorded cheats are the union, and so are the move logs, in the order
+//     the moves were played
+//   - in shifting mines mode, the mine layout is that of the state with the
+//     most shifts, and shifts the maximum of both; cells the other state
+//     revealed whose mine or hint the shift changed fail with
+//     ErrShiftConflict
+//   - status is the most final of both (lost > won > playing), with its
+//     end and speedrun times, and phase the furthest along of both
+//
+// Neither input is modified.
+func (g *GameState) Merge(other *GameState) (*GameState, error) {
+	w, h := g.Dimensions()
+	ow, oh := other.Dimensions()
+	if w != ow || h != oh {
+		return nil, fmt.Errorf("%w: grid %dx%d vs %dx%d", ErrIncompatibleStates, w, h, ow, oh)
+	}
+	if g.Level != other.Level {
+		return nil, fmt.Errorf("%w: level %d vs %d", ErrIncompatibleStates, g.Level, other.Level)
+	}
+	// A mine shift changes the layout: the most shifted state has the
+	// latest one, and the cells the other revealed must still be what they
+	// were revealed as
+	layout, stale := g, other
+	if other.Shifts > g.Shifts {
+		layout, stale = other, g
+	}
+	for x := 0; x < w && g.Shifts != other.Shifts; x++ {
+		for y := 0; y < h; y++ {
+			cell := stale.Cells[x][y]
+			if !cell.Revealed || layout.Cells[x][y].Revealed {
+				continue
+			}
+			if cell.Mine != layout.Cells[x][y].Mine || (!cell.Mine && cell.Hint != layout.AdjacentMines(x, y)) {
+				return nil, fmt.Errorf("%w at (%d,%d)", ErrShiftConflict, x, y)
+			}
+		}
+	}
+	for x := 0; x < w && g.Shifts == other.Shifts; x++ {
+		for y := 0; y < h; y++ {
+			if g.Cells[x][y].Mine != other.Cells[x][y].Mine {
+				return nil, fmt.Errorf("%w: mine layout differs at (%d,%d)", ErrIncompatibleStates, x, y)
+			}
+		}
+	}
+
+	merged := g.Clone()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			merged.Cells[x][y] = mergeCell(g.Cells[x][y], other.Cells[x][y])
+			merged.Cells[x][y].Mine = layout.Cells[x][y].Mine
+		}
+	}
+	if g.Shifts != other.Shifts {
+		merged.Shifts = layout.Shifts
+		merged.MineCount = layout.MineCount
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if layout.Cells[x][y].Revealed {
+					merged.Cells[x][y].Hint = layout.Cells[x][y].Hint
+				}
+			}
+		}
+	}
+
+	merged.Clicks = max(g.Clicks, other.Clicks)
+	merged.PowerUps = mergePowerUps(g.PowerUps, other.PowerUps)
+	merged.Teams = mergeTeams(g.Teams, other.Teams)
+	merged.Lives = min(g.Lives, other.Lives)
+	merged.Cheats = mergeCheats(g.Cheats, other.Cheats)
+	merged.Moves = mergeMoves(g.Moves, other.Moves)
+	if merged.CTF == nil {
+		merged.CTF = cloneCTF(other.CTF)
+	}
+	if merged.ScoreCard == nil && other.ScoreCard != nil {
+		card := *other.ScoreCard
+		merged.ScoreCard = &card
+	}
+	merged.PausedDuration = max(g.PausedDuration, other.PausedDuration)
+	if other.HeartbeatAt.After(g.HeartbeatAt) {
+		merged.HeartbeatAt = other.HeartbeatAt
+	}
+
+	if statusRank[other.Status] > statusRank[g.Status] {
+		merged.Status = other.Status
+		merged.EndedAt = other.EndedAt
+		merged.FinalTime = other.FinalTime
+		merged.Resigned, merged.ResignedBy = other.Resigned, other.ResignedBy
+		merged.TimedOut = other.TimedOut
+	} else if other.Status == g.Status && !other.EndedAt.IsZero() &&
+		(g.EndedAt.IsZero() || other.EndedAt.Before(g.EndedAt)) {
+		merged.EndedAt = other.EndedAt
+		merged.FinalTime = other.FinalTime
+		merged.Resigned, merged.ResignedBy = other.Resigned, other.ResignedBy
+		merged.TimedOut = other.TimedOut
+	}
+	if g.Phase != "" || other.Phase != "" {
+		merged.Phase = g.CurrentPhase()
+		if phase := other.CurrentPhase(); phaseRank[phase] > phaseRank[merged.Phase] {
+			merged.Phase = phase
+		}
+	}
+
+	seen := make(map[Coordinate]bool, len(merged.HintCells))
+	for _, c := range merged.HintCells {
+		seen[c] = true
+	}
+	for _, c := range other.HintCells {
+		if !seen[c] {
+			seen[c] = true
+			merged.HintCells = append(merged.HintCells, c)
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeCell combines two versions of the same cell.
+func mergeCell(a, b Cell) Cell {
+	merged := a
+	switch {
+	case a.Revealed && b.Revealed:
+		// Both revealed it: keep the earliest attribution
+		if !b.RevealedAt.IsZero() && (a.RevealedAt.IsZero() || b.RevealedAt.Before(a.RevealedAt)) {
+			merged = b
+		}
+	case b.Revealed:
+		merged = b
+	case !a.Revealed:
+		if b.Defused && !a.Defused {
+			merged = b
+			break
+		}
+		merged.Flagged = a.Flagged || b.Flagged
+		merged.PlayerFlagged = a.PlayerFlagged || b.PlayerFlagged
+		merged.Question = (a.Question || b.Question) && !merged.Flagged
+		if merged.PodUID == "" {
+			merged.PodUID = b.PodUID
+		}
+	}
+	return merged
+}
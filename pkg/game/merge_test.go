@@ -0,0 +1,206 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMergeUnionOfReveals(t *testing.T) {
+	base := NewGameState(4, 0)
+	base.SetMine(3, 3)
+
+	a := base.Clone()
+	a.RevealBy(0, 0, "alice")
+	a.SetFlag(3, 3, true)
+	a.AddHintCell(0, 0)
+
+	b := base.Clone()
+	b.RevealBy(1, 0, "bob")
+	b.RevealBy(2, 0, "bob")
+	b.SetQuestion(0, 3, true)
+	b.AddHintCell(1, 0)
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	for _, c := range []Coordinate{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}} {
+		if !merged.IsRevealed(c.X, c.Y) {
+			t.Errorf("expected %s to be revealed", c)
+		}
+	}
+	if cell, _ := merged.Cell(1, 0); cell.RevealedBy != "bob" {
+		t.Errorf("expected bob's attribution on (1,0), got %q", cell.RevealedBy)
+	}
+	if !merged.IsFlagged(3, 3) || !merged.IsQuestioned(0, 3) {
+		t.Error("expected marks from both sides to be kept")
+	}
+	if merged.Clicks != 2 {
+		t.Errorf("expected max clicks 2, got %d", merged.Clicks)
+	}
+	if len(merged.HintCells) != 2 {
+		t.Errorf("expected 2 hint cells, got %d", len(merged.HintCells))
+	}
+	if len(merged.Moves) != 3 {
+		t.Errorf("expected the moves of both sides, got %+v", merged.Moves)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("merged state is invalid: %v", err)
+	}
+
+	// Inputs are untouched
+	if a.IsRevealed(1, 0) || b.IsRevealed(0, 0) {
+		t.Error("Merge must not modify its inputs")
+	}
+}
+
+func TestMergeKeepsEarliestReveal(t *testing.T) {
+	base := NewGameState(3, 0)
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := base.Clone()
+	a.RevealWith(1, 1, RevealInfo{By: "late", At: early.Add(time.Second)})
+	b := base.Clone()
+	b.RevealWith(1, 1, RevealInfo{By: "early", At: early})
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if cell, _ := merged.Cell(1, 1); cell.RevealedBy != "early" {
+		t.Errorf("expected the earliest reveal to win, got %q", cell.RevealedBy)
+	}
+}
+
+func TestMergeStatus(t *testing.T) {
+	tests := []struct {
+		a, b     GameStatus
+		expected GameStatus
+	}{
+		{StatusPlaying, StatusPlaying, StatusPlaying},
+		{StatusPlaying, StatusWon, StatusWon},
+		{StatusWon, StatusPlaying, StatusWon},
+		{StatusWon, StatusLost, StatusLost},
+		{StatusLost, StatusPlaying, StatusLost},
+	}
+
+	for _, tt := range tests {
+		a := NewGameState(2, 0)
+		a.Status = tt.a
+		b := a.Clone()
+		b.Status = tt.b
+
+		merged, err := a.Merge(b)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if merged.Status != tt.expected {
+			t.Errorf("merge(%s, %s): expected %s, got %s", tt.a, tt.b, tt.expected, merged.Status)
+		}
+	}
+}
+
+func TestMergeIncompatible(t *testing.T) {
+	a := NewGameState(3, 0)
+	a.SetMine(0, 0)
+
+	tests := []struct {
+		name  string
+		other *GameState
+	}{
+		{"different size", NewGameState(4, 0)},
+		{"different mines", NewGameState(3, 0)},
+		{"different level", func() *GameState {
+			g := a.Clone()
+			g.Level = 1
+			return g
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Merge(tt.other); !errors.Is(err, ErrIncompatibleStates) {
+				t.Errorf("expected ErrIncompatibleStates, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeShiftedMine(t *testing.T) {
+	base := NewGameState(4, 0)
+	base.SetMine(0, 0)
+	base.SetMine(3, 3)
+	base.Reveal(1, 1)
+	base.ShiftEvery = 1
+
+	// A reconcile shifts the mine at (3,3) to (3,0) while the flag writer
+	// concurrently flags (0,0)
+	shifted := base.Clone()
+	shifted.ClearMine(3, 3)
+	shifted.SetMine(3, 0)
+	shifted.Shifts++
+	shifted.Reveal(2, 2)
+	flagged := base.Clone()
+	flagged.Cells[0][0].Flagged = true
+
+	for _, pair := range [][2]*GameState{{shifted, flagged}, {flagged, shifted}} {
+		merged, err := pair[0].Merge(pair[1])
+		if err != nil {
+			t.Fatalf("expected a shift to merge with a flag, got %v", err)
+		}
+		if merged.Shifts != 1 || merged.IsMine(3, 3) || !merged.IsMine(3, 0) || merged.MineCount != 2 {
+			t.Errorf("expected the shifted layout, got shifts %d, mines at (3,3) %v and (3,0) %v",
+				merged.Shifts, merged.IsMine(3, 3), merged.IsMine(3, 0))
+		}
+		if !merged.Cells[0][0].Flagged || !merged.IsRevealed(2, 2) {
+			t.Error("expected the flag and the reveal to be kept")
+		}
+		if merged.Cells[2][2].Hint != 0 || merged.Cells[1][1].Hint != 1 {
+			t.Errorf("expected hints of the shifted layout, got %d and %d",
+				merged.Cells[2][2].Hint, merged.Cells[1][1].Hint)
+		}
+	}
+}
+
+func TestMergeShiftConflict(t *testing.T) {
+	base := NewGameState(4, 0)
+	base.SetMine(0, 0)
+	base.SetMine(3, 3)
+	base.ShiftEvery = 1
+
+	// A reconcile shifts the mine at (3,3) to (3,0)
+	shifted := base.Clone()
+	shifted.ClearMine(3, 3)
+	shifted.SetMine(3, 0)
+	shifted.Shifts++
+
+	reveal := func(x, y int) *GameState {
+		concurrent := base.Clone()
+		concurrent.Cells[x][y].Revealed = true
+		concurrent.Cells[x][y].Hint = concurrent.AdjacentMines(x, y)
+		return concurrent
+	}
+	tests := []struct {
+		name       string
+		concurrent *GameState
+	}{
+		{"revealed cell becoming a mine", reveal(3, 0)},
+		{"revealed hint changed by the shift", reveal(2, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, pair := range [][2]*GameState{{shifted, tt.concurrent}, {tt.concurrent, shifted}} {
+				if _, err := pair[0].Merge(pair[1]); !errors.Is(err, ErrShiftConflict) {
+					t.Errorf("expected a shift conflict, got %v", err)
+				}
+			}
+		})
+	}
+
+	// A concurrent reveal the shift doesn't change merges
+	if _, err := shifted.Merge(reveal(1, 3)); err != nil {
+		t.Errorf("expected a reveal away from the shift to merge, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MoveResult is what a move revealed.
+type MoveResult string
+
+const (
+	// MoveSafe revealed a safe cell, and its neighbors if it propagates.
+	MoveSafe MoveResult = "safe"
+	// MoveMine set a mine off.
+	MoveMine MoveResult = "mine"
+	// MoveDefused hit a mine that cost a life, see HitMine.
+	MoveDefused MoveResult = "defused"
+)
+
+// UnattributedPlayer plays the moves whose player is unknown although it
+// should be, such as the cell pods deleted while the attribution webhook
+// was unreachable. It is not counted in PlayerStats.
+const UnattributedPlayer = "(unattributed)"
+
+// Move is a move of the move log of a game.
+type Move struct {
+	Coordinate
+	Result MoveResult `json:"result"`
+	At     time.Time  `json:"at"`
+
+	// By identifies who played the move, if known.
+	By string `json:"by,omitempty"`
+}
+
+// MoveLog lists the moves of a game in the order they were played: the
+// cells revealed directly, not by propagation, and the mines hit.
+type MoveLog []Move
+
+// record appends a move to the move log.
+func (g *GameState) record(x, y int, result MoveResult, by string, at time.Time) {
+	g.Moves = append(g.Moves, Move{Coordinate: Coordinate{X: x, Y: y}, Result: result, At: at, By: by})
+}
+
+// ReplayFrom replays log on the pristine board of the game, its mines with
+// nothing revealed, and returns the board after the last move. Replaying a
+// prefix of the move log of the game reconstructs the board as it was at
+// that point. Games played with shifting mines are replayed on their final
+// layout. Returns an error for moves out of the board or contradicting it.
+func (g *GameState) ReplayFrom(log MoveLog) (*GameState, error) {
+	replay := g.Clone()
+	w, h := replay.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			replay.Cells[x][y] = Cell{Mine: replay.Cells[x][y].Mine}
+		}
+	}
+	replay.Status = StatusPlaying
+	replay.EndedAt, replay.FinalTime = time.Time{}, 0
+	replay.Resigned, replay.ResignedBy, replay.TimedOut = false, "", false
+	replay.Clicks = 0
+	replay.HintCells = []Coordinate{}
+	replay.Defusals = nil
+	replay.Moves = nil
+
+	for i, m := range log {
+		if !replay.IsValidCoordinate(m.X, m.Y) {
+			return nil, fmt.Errorf("move %d: %s is out of the %dx%d board", i+1, m.Coordinate, w, h)
+		}
+		if replay.Status != StatusPlaying {
+			return nil, fmt.Errorf("move %d: the game already ended", i+1)
+		}
+		if mine := replay.IsMine(m.X, m.Y); mine != (m.Result != MoveSafe) {
+			return nil, fmt.Errorf("move %d: %s on %s, which is not one", i+1, m.Result, m.Coordinate)
+		}
+		switch m.Result {
+		case MoveSafe:
+			replay.replayReveal(m)
+			if replay.CheckVictory() {
+				replay.end(StatusWon, m.At)
+			}
+		case MoveMine:
+			replay.RevealWith(m.X, m.Y, RevealInfo{By: m.By, At: m.At})
+			replay.end(StatusLost, m.At)
+		case MoveDefused:
+			replay.defuse(m.X, m.Y, m.By, m.At)
+		default:
+			return nil, fmt.Errorf("move %d: unknown result %q", i+1, m.Result)
+		}
+	}
+	return replay, nil
+}
+
+// replayReveal reveals the safe cell of m and, like the Gamemaster, the
+// cells it propagates to.
+func (g *GameState) replayReveal(m Move) {
+	g.RevealWith(m.X, m.Y, RevealInfo{By: m.By, At: m.At})
+	if !g.Propagates(m.X, m.Y) {
+		g.AddHintCell(m.X, m.Y)
+		return
+	}
+	queue := []Coordinate{m.Coordinate}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range g.GetNeighbors(c.X, c.Y) {
+			if g.IsMine(n.X, n.Y) || !g.RevealWith(n.X, n.Y, RevealInfo{By: m.By, At: m.At, Propagated: true}) {
+				continue
+			}
+			if g.Propagates(n.X, n.Y) {
+				queue = append(queue, n)
+			} else {
+				g.AddHintCell(n.X, n.Y)
+			}
+		}
+	}
+}
+
+// mergeMoves combines two versions of the same move log, keeping the moves
+// of both in the order they were played. A cell is only played once.
+func mergeMoves(a, b MoveLog) MoveLog {
+	if len(b) == 0 {
+		return append(MoveLog(nil), a...)
+	}
+	played := make(map[Coordinate]bool, len(a))
+	merged := append(MoveLog(nil), a...)
+	for _, m := range a {
+		played[m.Coordinate] = true
+	}
+	for _, m := range b {
+		if !played[m.Coordinate] {
+			played[m.Coordinate] = true
+			merged = append(merged, m)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].At.Before(merged[j].At)
+	})
+	return merged
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoveLog_Records(t *testing.T) {
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := NewGameState(3, 1)
+	state.SetMine(1, 1)
+	state.Lives = 2
+
+	state.RevealWith(0, 0, RevealInfo{By: "alice", At: at})
+	state.RevealWith(0, 1, RevealInfo{By: "alice", At: at, Propagated: true})
+	state.HitMine(1, 1, "bob")
+
+	if len(state.Moves) != 2 {
+		t.Fatalf("expected 2 moves recorded, got %+v", state.Moves)
+	}
+	if m := state.Moves[0]; m.Coordinate != (Coordinate{X: 0, Y: 0}) || m.Result != MoveSafe || m.By != "alice" || !m.At.Equal(at) {
+		t.Errorf("unexpected first move %+v", m)
+	}
+	if m := state.Moves[1]; m.Coordinate != (Coordinate{X: 1, Y: 1}) || m.Result != MoveDefused || m.By != "bob" {
+		t.Errorf("unexpected second move %+v", m)
+	}
+}
+
+func TestReplayFrom(t *testing.T) {
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Mines at (0,3) and (3,3): (0,0) opens the two top rows and the
+	// hints of the third one
+	state := NewGameState(4, 1)
+	state.SetMine(0, 3)
+	state.SetMine(3, 3)
+	log := MoveLog{
+		{Coordinate: Coordinate{X: 0, Y: 0}, Result: MoveSafe, At: at, By: "alice"},
+		{Coordinate: Coordinate{X: 1, Y: 3}, Result: MoveSafe, At: at.Add(time.Second), By: "bob"},
+		{Coordinate: Coordinate{X: 3, Y: 3}, Result: MoveMine, At: at.Add(2 * time.Second), By: "bob"},
+	}
+
+	tests := []struct {
+		moves    int
+		revealed int
+		status   GameStatus
+	}{
+		{0, 0, StatusPlaying},
+		{1, 12, StatusPlaying},
+		{2, 13, StatusPlaying},
+		{3, 14, StatusLost},
+	}
+	for _, tt := range tests {
+		replay, err := state.ReplayFrom(log[:tt.moves])
+		if err != nil {
+			t.Fatalf("ReplayFrom(%d moves) failed: %v", tt.moves, err)
+		}
+		if got := replay.Stats().RevealedCells; got != tt.revealed || replay.Status != tt.status {
+			t.Errorf("after %d moves: expected %d cells revealed and %s, got %d and %s",
+				tt.moves, tt.revealed, tt.status, got, replay.Status)
+		}
+		if len(replay.Moves) != tt.moves {
+			t.Errorf("after %d moves: expected the replay to log them, got %+v", tt.moves, replay.Moves)
+		}
+	}
+	if state.Stats().RevealedCells != 0 {
+		t.Error("ReplayFrom must not modify the game")
+	}
+
+	bad := MoveLog{{Coordinate: Coordinate{X: 0, Y: 3}, Result: MoveSafe}}
+	if _, err := state.ReplayFrom(bad); err == nil {
+		t.Error("expected a safe move on a mine to be refused")
+	}
+	if _, err := state.ReplayFrom(MoveLog{{Coordinate: Coordinate{X: 9, Y: 9}, Result: MoveSafe}}); err == nil {
+		t.Error("expected a move out of the board to be refused")
+	}
+}
@@ -0,0 +1,230 @@
+package game
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Placeholders of a NamingScheme format.
+const (
+	NamePrefix = "{prefix}"
+	NameX      = "{x}"
+	NameY      = "{y}"
+)
+
+// NamingScheme names the pods of the cells of a game. The name of the pod
+// of a cell is its format with the placeholders replaced by the prefix of
+// the pod kind and the coordinates. Deployments in the same namespace, or
+// wishing for localized names, use different schemes.
+type NamingScheme struct {
+	// CellPrefix names the pods players delete to reveal cells.
+	CellPrefix string
+
+	// HintPrefix names the pods showing hints.
+	HintPrefix string
+
+	// DefusedPrefix names the marker pods of defused mines.
+	DefusedPrefix string
+
+	// BombPrefix names the challenge pods of mines being defused in
+	// defusal mode.
+	BombPrefix string
+
+	// Format of the names, holding NamePrefix, NameX and NameY once each.
+	Format string
+}
+
+// DefaultNamingScheme names pods like pod-3-5, hint-3-5, defused-3-5 and
+// bomb-3-5.
+var DefaultNamingScheme = NamingScheme{
+	CellPrefix:    "pod",
+	HintPrefix:    "hint",
+	DefusedPrefix: "defused",
+	BombPrefix:    "bomb",
+	Format:        NamePrefix + "-" + NameX + "-" + NameY,
+}
+
+// maxNamedCoordinate is the largest coordinate Validate names, well beyond
+// the largest grids.
+const maxNamedCoordinate = 9999
+
+// podNamer is a validated NamingScheme with its parsing regexes.
+type podNamer struct {
+	scheme                    NamingScheme
+	cell, hint, defused, bomb *regexp.Regexp
+	xFirst                    bool
+}
+
+var (
+	namingMu sync.RWMutex
+	naming   = mustPodNamer(DefaultNamingScheme)
+)
+
+// Validate checks the scheme produces valid and unambiguous pod names.
+func (n NamingScheme) Validate() error {
+	for _, p := range []string{NamePrefix, NameX, NameY} {
+		if c := strings.Count(n.Format, p); c != 1 {
+			return fmt.Errorf("pod name format %q must hold %s once, not %d times", n.Format, p, c)
+		}
+	}
+	if strings.Contains(n.Format, NameX+NameY) || strings.Contains(n.Format, NameY+NameX) {
+		return fmt.Errorf("pod name format %q must separate %s and %s", n.Format, NameX, NameY)
+	}
+
+	prefixes := map[string]string{"cell": n.CellPrefix, "hint": n.HintPrefix, "defused": n.DefusedPrefix, "bomb": n.BombPrefix}
+	seen := make(map[string]string, len(prefixes))
+	for kind, prefix := range prefixes {
+		if prefix == "" || strings.ContainsAny(prefix, "0123456789") {
+			return fmt.Errorf("%s pod prefix %q must be non-empty and hold no digits", kind, prefix)
+		}
+		if other, ok := seen[prefix]; ok {
+			return fmt.Errorf("%s and %s pods can't share the prefix %q", kind, other, prefix)
+		}
+		seen[prefix] = kind
+		// The longest names must be valid pod names too
+		name := n.format(prefix, maxNamedCoordinate, maxNamedCoordinate)
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			return fmt.Errorf("invalid %s pod name %q: %s", kind, name, strings.Join(msgs, ", "))
+		}
+	}
+	return nil
+}
+
+// BindFlags registers the flags setting the scheme on fs, defaulting to
+// its current values.
+func (n *NamingScheme) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&n.CellPrefix, "cell-pod-prefix", n.CellPrefix, "Prefix of the cell pod names.")
+	fs.StringVar(&n.HintPrefix, "hint-pod-prefix", n.HintPrefix, "Prefix of the hint pod names.")
+	fs.StringVar(&n.DefusedPrefix, "defused-pod-prefix", n.DefusedPrefix, "Prefix of the defused mine marker pod names.")
+	fs.StringVar(&n.BombPrefix, "bomb-pod-prefix", n.BombPrefix, "Prefix of the defusal challenge pod names.")
+	fs.StringVar(&n.Format, "pod-name-format", n.Format,
+		"Format of the game pod names, holding "+NamePrefix+", "+NameX+" and "+NameY+" once each.")
+}
+
+// Args returns the flags registered by BindFlags that differ from
+// DefaultNamingScheme, to pass the scheme on to another process.
+func (n NamingScheme) Args() []string {
+	var args []string
+	for _, f := range []struct{ name, value, def string }{
+		{"cell-pod-prefix", n.CellPrefix, DefaultNamingScheme.CellPrefix},
+		{"hint-pod-prefix", n.HintPrefix, DefaultNamingScheme.HintPrefix},
+		{"defused-pod-prefix", n.DefusedPrefix, DefaultNamingScheme.DefusedPrefix},
+		{"bomb-pod-prefix", n.BombPrefix, DefaultNamingScheme.BombPrefix},
+		{"pod-name-format", n.Format, DefaultNamingScheme.Format},
+	} {
+		if f.value != f.def {
+			args = append(args, "--"+f.name+"="+f.value)
+		}
+	}
+	return args
+}
+
+// format names the pod of prefix at (x, y).
+func (n NamingScheme) format(prefix string, x, y int) string {
+	return strings.NewReplacer(NamePrefix, prefix, NameX, strconv.Itoa(x), NameY, strconv.Itoa(y)).Replace(n.Format)
+}
+
+// regex matches the names of the pods of prefix, capturing the coordinates.
+func (n NamingScheme) regex(prefix string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(n.Format)
+	pattern = strings.NewReplacer(
+		regexp.QuoteMeta(NamePrefix), regexp.QuoteMeta(prefix),
+		regexp.QuoteMeta(NameX), `(\d+)`,
+		regexp.QuoteMeta(NameY), `(\d+)`,
+	).Replace(pattern)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+// newPodNamer validates a scheme and generates its regexes.
+func newPodNamer(n NamingScheme) (*podNamer, error) {
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+	return &podNamer{
+		scheme:  n,
+		cell:    n.regex(n.CellPrefix),
+		hint:    n.regex(n.HintPrefix),
+		defused: n.regex(n.DefusedPrefix),
+		bomb:    n.regex(n.BombPrefix),
+		xFirst:  strings.Index(n.Format, NameX) < strings.Index(n.Format, NameY),
+	}, nil
+}
+
+func mustPodNamer(n NamingScheme) *podNamer {
+	namer, err := newPodNamer(n)
+	if err != nil {
+		panic(err)
+	}
+	return namer
+}
+
+// parse extracts the coordinates of a name matched by re.
+func (p *podNamer) parse(re *regexp.Regexp, name string) (Coordinate, bool) {
+	matches := re.FindStringSubmatch(name)
+	if matches == nil {
+		return Coordinate{}, false
+	}
+	a, err1 := strconv.Atoi(matches[1])
+	b, err2 := strconv.Atoi(matches[2])
+	if err1 != nil || err2 != nil {
+		return Coordinate{}, false
+	}
+	if !p.xFirst {
+		a, b = b, a
+	}
+	return Coordinate{X: a, Y: b}, true
+}
+
+// SetNamingScheme makes n the scheme naming the pods of this process. It
+// is meant to be called once at startup, before any pod is named.
+func SetNamingScheme(n NamingScheme) error {
+	namer, err := newPodNamer(n)
+	if err != nil {
+		return err
+	}
+	namingMu.Lock()
+	defer namingMu.Unlock()
+	naming = namer
+	return nil
+}
+
+// Naming returns the scheme naming the pods of this process.
+func Naming() NamingScheme {
+	return currentNamer().scheme
+}
+
+func currentNamer() *podNamer {
+	namingMu.RLock()
+	defer namingMu.RUnlock()
+	return naming
+}
+
+// ParseCellPodName returns the coordinate of a cell pod name.
+func ParseCellPodName(name string) (Coordinate, bool) {
+	namer := currentNamer()
+	return namer.parse(namer.cell, name)
+}
+
+// ParseHintPodName returns the coordinate of a hint pod name.
+func ParseHintPodName(name string) (Coordinate, bool) {
+	namer := currentNamer()
+	return namer.parse(namer.hint, name)
+}
+
+// ParseDefusedPodName returns the coordinate of a defused marker pod name.
+func ParseDefusedPodName(name string) (Coordinate, bool) {
+	namer := currentNamer()
+	return namer.parse(namer.defused, name)
+}
+
+// ParseBombPodName returns the coordinate of a defusal challenge pod name.
+func ParseBombPodName(name string) (Coordinate, bool) {
+	namer := currentNamer()
+	return namer.parse(namer.bomb, name)
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestNamingScheme_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(n *NamingScheme)
+		wantErr bool
+	}{
+		{"default", func(n *NamingScheme) {}, false},
+		{"custom prefixes", func(n *NamingScheme) { n.CellPrefix, n.HintPrefix = "tile", "indice" }, false},
+		{"dotted", func(n *NamingScheme) { n.Format = "{prefix}.{x}.{y}" }, false},
+		{"y first", func(n *NamingScheme) { n.Format = "{prefix}-r{y}-c{x}" }, false},
+		{"missing y", func(n *NamingScheme) { n.Format = "{prefix}-{x}" }, true},
+		{"twice x", func(n *NamingScheme) { n.Format = "{prefix}-{x}-{x}-{y}" }, true},
+		{"adjacent coordinates", func(n *NamingScheme) { n.Format = "{prefix}-{x}{y}" }, true},
+		{"shared prefix", func(n *NamingScheme) { n.HintPrefix = "pod" }, true},
+		{"empty prefix", func(n *NamingScheme) { n.DefusedPrefix = "" }, true},
+		{"digit in prefix", func(n *NamingScheme) { n.CellPrefix = "c3ll" }, true},
+		{"invalid characters", func(n *NamingScheme) { n.Format = "{prefix}_{x}_{y}" }, true},
+		{"uppercase", func(n *NamingScheme) { n.CellPrefix = "Cell" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := DefaultNamingScheme
+			tt.modify(&n)
+			if err := n.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetNamingScheme(t *testing.T) {
+	t.Cleanup(func() { _ = SetNamingScheme(DefaultNamingScheme) })
+	c := Coordinate{X: 3, Y: 12}
+
+	if c.PodName() != "pod-3-12" || c.HintPodName() != "hint-3-12" || c.DefusedPodName() != "defused-3-12" {
+		t.Fatalf("unexpected default names %s %s %s", c.PodName(), c.HintPodName(), c.DefusedPodName())
+	}
+
+	if err := SetNamingScheme(NamingScheme{Format: "{prefix}-{x}"}); err == nil {
+		t.Fatal("expected an invalid scheme to be rejected")
+	}
+	if Naming() != DefaultNamingScheme {
+		t.Fatal("expected an invalid scheme to leave the current one")
+	}
+
+	scheme := NamingScheme{CellPrefix: "tile", HintPrefix: "indice", DefusedPrefix: "desamorce", BombPrefix: "bombe", Format: "{prefix}.r{y}.c{x}"}
+	if err := SetNamingScheme(scheme); err != nil {
+		t.Fatalf("SetNamingScheme failed: %v", err)
+	}
+	if got := c.PodName(); got != "tile.r12.c3" {
+		t.Errorf("PodName() = %q", got)
+	}
+	if got := c.HintPodName(); got != "indice.r12.c3" {
+		t.Errorf("HintPodName() = %q", got)
+	}
+
+	tests := []struct {
+		name      string
+		parse     func(string) (Coordinate, bool)
+		pod       string
+		wantOK    bool
+		wantCoord Coordinate
+	}{
+		{"cell", ParseCellPodName, "tile.r12.c3", true, c},
+		{"hint", ParseHintPodName, "indice.r0.c7", true, Coordinate{X: 7, Y: 0}},
+		{"defused", ParseDefusedPodName, "desamorce.r1.c2", true, Coordinate{X: 2, Y: 1}},
+		{"bomb", ParseBombPodName, "bombe.r4.c0", true, Coordinate{X: 0, Y: 4}},
+		{"old scheme", ParseCellPodName, "pod-3-12", false, Coordinate{}},
+		{"other kind", ParseCellPodName, "indice.r12.c3", false, Coordinate{}},
+		{"dot is literal", ParseCellPodName, "tile-r12-c3", false, Coordinate{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.parse(tt.pod)
+			if ok != tt.wantOK || got != tt.wantCoord {
+				t.Errorf("parse(%q) = %v, %v, want %v, %v", tt.pod, got, ok, tt.wantCoord, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNamingScheme_Flags(t *testing.T) {
+	n := DefaultNamingScheme
+	if args := n.Args(); len(args) != 0 {
+		t.Errorf("expected no args for the default scheme, got %v", args)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	n.BindFlags(fs)
+	if err := fs.Parse([]string{"--cell-pod-prefix=cell", "--pod-name-format={prefix}.{x}.{y}"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []string{"--cell-pod-prefix=cell", "--pod-name-format={prefix}.{x}.{y}"}
+	if args := n.Args(); !reflect.DeepEqual(args, want) {
+		t.Errorf("Args() = %v, want %v", args, want)
+	}
+	if n.HintPrefix != DefaultNamingScheme.HintPrefix {
+		t.Errorf("expected the hint prefix to keep its default, got %q", n.HintPrefix)
+	}
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Phase is the lifecycle step of a game, telling what its board pods are
+// doing. Status holds the outcome of the game, Phase whether it can be
+// played.
+type Phase string
+
+const (
+	// PhasePending is a generated game whose board pods don't exist yet.
+	PhasePending Phase = "Pending"
+	// PhaseSpawning is a game whose board pods are being created. Moves
+	// wait for the board to be ready.
+	PhaseSpawning Phase = "Spawning"
+	// PhaseReady is a spawned game waiting for its first move.
+	PhaseReady Phase = "Ready"
+	// PhasePlaying is a game being played.
+	PhasePlaying Phase = "Playing"
+	// PhaseFinished is a won or lost game.
+	PhaseFinished Phase = "Finished"
+	// PhaseCleaning is an ended game whose board pods are being removed.
+	PhaseCleaning Phase = "Cleaning"
+)
+
+// ErrInvalidPhaseTransition is returned by SetPhase when the game can't go
+// from its phase to the requested one.
+var ErrInvalidPhaseTransition = errors.New("invalid game phase transition")
+
+// phaseTransitions lists the phases each phase may go to. Respawning the
+// board of a game, such as when it is loaded from a save slot, goes back
+// to Spawning. Games end with Finished whatever their phase, see end.
+var phaseTransitions = map[Phase][]Phase{
+	PhasePending:  {PhaseSpawning},
+	PhaseSpawning: {PhaseReady, PhasePlaying},
+	PhaseReady:    {PhasePlaying, PhaseSpawning, PhaseFinished},
+	PhasePlaying:  {PhaseSpawning, PhaseFinished},
+	PhaseFinished: {PhaseCleaning},
+	PhaseCleaning: nil,
+}
+
+// IsValid reports whether p is a known phase.
+func (p Phase) IsValid() bool {
+	_, ok := phaseTransitions[p]
+	return ok
+}
+
+// CanTransitionTo reports whether a game may go from phase p to next.
+func (p Phase) CanTransitionTo(next Phase) bool {
+	return slices.Contains(phaseTransitions[p], next)
+}
+
+// Spawned reports whether the board of phase p is complete, ready to be
+// played or shown.
+func (p Phase) Spawned() bool {
+	return p != PhasePending && p != PhaseSpawning
+}
+
+// Playable reports whether moves are played in phase p: once the board is
+// spawned and until the game ends.
+func (p Phase) Playable() bool {
+	return p == PhaseReady || p == PhasePlaying
+}
+
+// CurrentPhase returns the phase of the game. States without one, saved
+// before phases or built by hand, are inferred from their status and
+// moves, with their board assumed spawned.
+func (g *GameState) CurrentPhase() Phase {
+	switch {
+	case g.Phase != "":
+		return g.Phase
+	case g.Status != StatusPlaying:
+		return PhaseFinished
+	case g.Clicks > 0 || g.Stats().RevealedCells > 0:
+		return PhasePlaying
+	default:
+		return PhaseReady
+	}
+}
+
+// SetPhase moves the game to phase next. Staying in the current phase is
+// allowed; other transitions must be listed in phaseTransitions.
+func (g *GameState) SetPhase(next Phase) error {
+	current := g.CurrentPhase()
+	if current != next && !current.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidPhaseTransition, current, next)
+	}
+	g.Phase = next
+	return nil
+}
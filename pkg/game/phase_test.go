@@ -0,0 +1,110 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetPhase(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    Phase
+		to      Phase
+		wantErr bool
+	}{
+		{"pending to spawning", PhasePending, PhaseSpawning, false},
+		{"spawning to ready", PhaseSpawning, PhaseReady, false},
+		{"ready to playing", PhaseReady, PhasePlaying, false},
+		{"playing to respawning", PhasePlaying, PhaseSpawning, false},
+		{"spawning back to playing", PhaseSpawning, PhasePlaying, false},
+		{"playing to finished", PhasePlaying, PhaseFinished, false},
+		{"finished to cleaning", PhaseFinished, PhaseCleaning, false},
+		{"same phase", PhasePlaying, PhasePlaying, false},
+		{"pending to playing", PhasePending, PhasePlaying, true},
+		{"spawning to finished", PhaseSpawning, PhaseFinished, true},
+		{"finished to playing", PhaseFinished, PhasePlaying, true},
+		{"cleaning to spawning", PhaseCleaning, PhaseSpawning, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewGameState(3, 0)
+			state.Phase = tt.from
+
+			err := state.SetPhase(tt.to)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPhaseTransition) {
+					t.Fatalf("expected ErrInvalidPhaseTransition, got %v", err)
+				}
+				if state.Phase != tt.from {
+					t.Errorf("expected phase to stay %s, got %s", tt.from, state.Phase)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetPhase failed: %v", err)
+			}
+			if state.Phase != tt.to {
+				t.Errorf("expected phase %s, got %s", tt.to, state.Phase)
+			}
+		})
+	}
+}
+
+func TestCurrentPhase(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*GameState)
+		want   Phase
+	}{
+		{"untouched", func(*GameState) {}, PhaseReady},
+		{"revealed cell", func(g *GameState) { g.Reveal(1, 1) }, PhasePlaying},
+		{"lost", func(g *GameState) { g.SetLost() }, PhaseFinished},
+		{"explicit", func(g *GameState) { g.Phase = PhaseSpawning }, PhaseSpawning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewGameState(3, 0)
+			state.SetMine(0, 0)
+			tt.modify(state)
+			if got := state.CurrentPhase(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEndFinishesPhase(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.Phase = PhasePlaying
+	state.SetWon()
+	if state.Phase != PhaseFinished {
+		t.Errorf("expected a won game to be Finished, got %s", state.Phase)
+	}
+}
+
+func TestMergePhase(t *testing.T) {
+	base := NewGameState(3, 0)
+	base.Phase = PhaseReady
+
+	a := base.Clone()
+	a.Phase = PhasePlaying
+	merged, err := a.Merge(base)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if merged.Phase != PhasePlaying {
+		t.Errorf("expected the furthest phase Playing, got %s", merged.Phase)
+	}
+
+	// States without a phase stay without one
+	legacy := NewGameState(3, 0)
+	merged, err = legacy.Merge(legacy.Clone())
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if merged.Phase != "" {
+		t.Errorf("expected no phase, got %s", merged.Phase)
+	}
+}
@@ -0,0 +1,108 @@
+package game
+
+// StreakPerCharge is the number of safe clicks in a row earning a power-up
+// charge.
+const StreakPerCharge = 10
+
+// PowerUps tracks the power-up charges of a game. Charges are earned by
+// long streaks of safe clicks and spent on power-ups; both are counted so
+// that concurrent versions of a game merge without losing any.
+type PowerUps struct {
+	// Streak is the number of safe clicks since the last mine hit or
+	// earned charge.
+	Streak int `json:"streak,omitempty"`
+
+	// Earned is the number of charges earned since the game started.
+	Earned int `json:"earned,omitempty"`
+
+	// Spent is the number of charges spent since the game started.
+	Spent int `json:"spent,omitempty"`
+
+	// Armed names the power-up waiting for the next move to take effect.
+	Armed string `json:"armed,omitempty"`
+
+	// Spared are mines flagged by a power-up while players were deleting
+	// their pods. Those deletions are not moves: the pods are restored.
+	Spared []Coordinate `json:"spared,omitempty"`
+}
+
+// Charges returns the number of charges left to spend.
+func (p PowerUps) Charges() int {
+	return max(p.Earned-p.Spent, 0)
+}
+
+// RecordSafeClick extends the safe streak after a player revealed a safe
+// cell, and reports whether it earned a charge.
+func (g *GameState) RecordSafeClick() bool {
+	g.PowerUps.Streak++
+	if g.PowerUps.Streak < StreakPerCharge {
+		return false
+	}
+	g.PowerUps.Streak = 0
+	g.PowerUps.Earned++
+	return true
+}
+
+// SpendCharge spends a power-up charge. Returns false if none is left or
+// the game has ended.
+func (g *GameState) SpendCharge() bool {
+	if g.Status != StatusPlaying || g.PowerUps.Charges() == 0 {
+		return false
+	}
+	g.PowerUps.Spent++
+	return true
+}
+
+// IsSpared reports whether the pod deletion of the cell at (x, y) is to be
+// undone rather than played.
+func (p PowerUps) IsSpared(x, y int) bool {
+	for _, c := range p.Spared {
+		if c.X == x && c.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// Unspare forgets the spared mine at (x, y) once its pod is restored.
+// Returns false if it wasn't spared.
+func (g *GameState) Unspare(x, y int) bool {
+	for i, c := range g.PowerUps.Spared {
+		if c.X == x && c.Y == y {
+			g.PowerUps.Spared = append(g.PowerUps.Spared[:i:i], g.PowerUps.Spared[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// clone deep copies the power-up state.
+func (p PowerUps) clone() PowerUps {
+	p.Spared = append([]Coordinate(nil), p.Spared...)
+	return p
+}
+
+// mergePowerUps combines two versions of the power-up state. The armed
+// power-up and spared mines of a win.
+func mergePowerUps(a, b PowerUps) PowerUps {
+	return PowerUps{
+		Streak: max(a.Streak, b.Streak),
+		Earned: max(a.Earned, b.Earned),
+		Spent:  max(a.Spent, b.Spent),
+		Armed:  a.Armed,
+		Spared: append([]Coordinate(nil), a.Spared...),
+	}
+}
+
+// NeutralizeMine defuses the hidden mine at (x, y) without costing anyone a
+// life. Returns false if there is no hidden mine there.
+func (g *GameState) NeutralizeMine(x, y int) bool {
+	if !g.IsMine(x, y) || g.IsRevealed(x, y) || g.IsDefused(x, y) {
+		return false
+	}
+	cell := &g.Cells[x][y]
+	cell.Defused = true
+	cell.Flagged = false
+	cell.Question = false
+	return true
+}
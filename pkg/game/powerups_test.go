@@ -0,0 +1,93 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPowerUps_Charges(t *testing.T) {
+	state := NewGameState(4, 1)
+	state.SetMine(1, 1)
+	state.SetMine(2, 2)
+
+	for i := 1; i < StreakPerCharge; i++ {
+		if state.RecordSafeClick() {
+			t.Fatalf("expected no charge after %d safe clicks", i)
+		}
+	}
+	if !state.RecordSafeClick() || state.PowerUps.Charges() != 1 || state.PowerUps.Streak != 0 {
+		t.Fatalf("expected a charge after %d safe clicks, got %+v", StreakPerCharge, state.PowerUps)
+	}
+	if !strings.Contains(state.Stats().String(), "1 power-up charge(s)") {
+		t.Errorf("expected the stats to show the charge, got %q", state.Stats())
+	}
+
+	// Mine hits break the streak
+	state.RecordSafeClick()
+	_ = state.AddTeam("red", 2, "alice")
+	state.HitMine(1, 1, "alice")
+	if state.PowerUps.Streak != 0 {
+		t.Errorf("expected a mine hit to break the streak, got %d", state.PowerUps.Streak)
+	}
+
+	if !state.SpendCharge() || state.PowerUps.Charges() != 0 {
+		t.Fatalf("expected the charge spent, got %+v", state.PowerUps)
+	}
+	if state.SpendCharge() {
+		t.Error("expected no charge left to spend")
+	}
+}
+
+func TestNeutralizeMine(t *testing.T) {
+	state := NewGameState(3, 1)
+	state.SetMine(1, 1)
+	state.SetFlag(1, 1, true)
+
+	if state.NeutralizeMine(0, 0) {
+		t.Error("expected a safe cell not to be neutralized")
+	}
+	if !state.NeutralizeMine(1, 1) || !state.IsDefused(1, 1) || state.IsFlagged(1, 1) {
+		t.Error("expected the mine defused and unflagged")
+	}
+	if state.NeutralizeMine(1, 1) {
+		t.Error("expected a defused mine not to be neutralized again")
+	}
+	if state.Status != StatusPlaying || state.Stats().RemainingMines != 0 {
+		t.Errorf("expected the game going on with no mine left, got %s", state.Stats())
+	}
+}
+
+func TestMergePowerUps(t *testing.T) {
+	base := NewGameState(3, 1)
+	base.PowerUps = PowerUps{Earned: 2, Spent: 1}
+	a, b := base.Clone(), base.Clone()
+	a.SpendCharge()
+	a.PowerUps.Armed = "sweep"
+	b.PowerUps.Earned++
+	b.PowerUps.Streak = 4
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if want := (PowerUps{Streak: 4, Earned: 3, Spent: 2, Armed: "sweep"}); !reflect.DeepEqual(merged.PowerUps, want) {
+		t.Errorf("merged power-ups = %+v, want %+v", merged.PowerUps, want)
+	}
+}
+
+func TestUnspare(t *testing.T) {
+	state := NewGameState(3, 1)
+	state.PowerUps.Spared = []Coordinate{{X: 1, Y: 1}, {X: 2, Y: 1}}
+	clone := state.Clone()
+
+	if state.Unspare(0, 0) {
+		t.Error("expected a cell never spared not to be unspared")
+	}
+	if !state.Unspare(1, 1) || state.PowerUps.IsSpared(1, 1) || !state.PowerUps.IsSpared(2, 1) {
+		t.Errorf("expected only (1, 1) unspared, got %v", state.PowerUps.Spared)
+	}
+	if !clone.PowerUps.IsSpared(1, 1) {
+		t.Error("expected the clone unaffected")
+	}
+}
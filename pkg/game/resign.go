@@ -0,0 +1,16 @@
+package game
+
+import "time"
+
+// Resign ends the game as lost by resignation of player, who may be empty
+// if unknown. It is recorded apart from a mine hit. Returns false if the
+// game has already ended.
+func (g *GameState) Resign(player string, now time.Time) bool {
+	if g.Status != StatusPlaying {
+		return false
+	}
+	g.Resigned = true
+	g.ResignedBy = player
+	g.end(StatusLost, now)
+	return true
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResign(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := NewGameState(3, 1)
+	state.SetMine(1, 1)
+
+	if !state.Resign("alice", now) {
+		t.Fatal("expected a running game to be resigned")
+	}
+	if state.Status != StatusLost || !state.Resigned || state.ResignedBy != "alice" || !state.EndedAt.Equal(now) {
+		t.Errorf("unexpected resigned state: status=%s resigned=%v by=%q ended=%v",
+			state.Status, state.Resigned, state.ResignedBy, state.EndedAt)
+	}
+	if state.IsRevealed(1, 1) {
+		t.Error("resigning must not reveal a mine like a mine hit")
+	}
+	if state.Resign("bob", now) {
+		t.Error("expected an ended game not to be resigned again")
+	}
+
+	stats := state.Stats()
+	if !stats.Resigned || !strings.Contains(stats.String(), "lost (resigned)") {
+		t.Errorf("expected the stats to tell the resignation, got %q", stats)
+	}
+	if clone := state.Clone(); !clone.Resigned || clone.ResignedBy != "alice" {
+		t.Error("expected Clone to copy the resignation")
+	}
+}
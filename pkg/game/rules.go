@@ -0,0 +1,18 @@
+package game
+
+// Rules are the custom rules of a level or variant, declared with presets
+// instead of written in Go. Each rule is a CEL expression over the stats of
+// the game, evaluated after every move by package rules. Empty rules are
+// not evaluated.
+type Rules struct {
+	// Lose ends the game lost when true, such as "clicks > 2 * mines".
+	Lose string `json:"lose,omitempty"`
+
+	// Score is the score of the game given the built-in one as score, such
+	// as "score * 2" or "score - int(elapsedSeconds)".
+	Score string `json:"score,omitempty"`
+
+	// Obstacles is the level of the security obstacles, such as the hint
+	// NetworkPolicy, given the game level as level, such as "level + 4".
+	Obstacles string `json:"obstacles,omitempty"`
+}
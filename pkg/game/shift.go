@@ -0,0 +1,15 @@
+package game
+
+// ShiftDue counts a move in shifting mines mode and reports whether a mine
+// shifts before it is played, once every ShiftEvery moves.
+func (g *GameState) ShiftDue() bool {
+	if g.ShiftEvery <= 0 {
+		return false
+	}
+	g.ShiftMoves++
+	if g.ShiftMoves < g.ShiftEvery {
+		return false
+	}
+	g.ShiftMoves = 0
+	return true
+}
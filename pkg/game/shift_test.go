@@ -0,0 +1,27 @@
+package game
+
+import "testing"
+
+func TestShiftDue(t *testing.T) {
+	state := NewGameState(3, 1)
+	for range 5 {
+		if state.ShiftDue() {
+			t.Fatal("expected mines to stay in place outside shifting mines mode")
+		}
+	}
+
+	state.ShiftEvery = 3
+	var due []bool
+	for range 7 {
+		due = append(due, state.ShiftDue())
+	}
+	want := []bool{false, false, true, false, false, true, false}
+	for i := range want {
+		if due[i] != want[i] {
+			t.Fatalf("expected a shift every 3 moves, got %v", due)
+		}
+	}
+	if clone := state.Clone(); clone.ShiftEvery != 3 || clone.ShiftMoves != 1 {
+		t.Errorf("expected Clone to keep the shift counters, got %d/%d", clone.ShiftMoves, clone.ShiftEvery)
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SaveSlotPrefix prefixes the names of the Secrets holding saved games,
+// one per save slot.
+const SaveSlotPrefix = "podsweeper-save-"
+
+// ValidateSlotName checks that a save slot name is a DNS label short enough
+// to name its Secret.
+func ValidateSlotName(name string) error {
+	if len(validation.IsDNS1123Label(name)) > 0 || len(validation.IsDNS1123Label(SaveSlotPrefix+name)) > 0 {
+		return fmt.Errorf("invalid save slot name %q: must be a lowercase DNS label of at most %d characters",
+			name, validation.DNS1123LabelMaxLength-len(SaveSlotPrefix))
+	}
+	return nil
+}
+
+// NewSlotStore returns the store of the save slot name in namespace.
+func NewSlotStore(c client.Client, namespace, name string) *SecretStore {
+	return NewSecretStore(c, WithNamespace(namespace), WithSecretName(SaveSlotPrefix+name))
+}
+
+// ListSlots returns the names of the save slots of namespace, sorted.
+func ListSlots(ctx context.Context, c client.Client, namespace string) ([]string, error) {
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	var names []string
+	for _, secret := range secrets.Items {
+		if name, ok := strings.CutPrefix(secret.Name, SaveSlotPrefix); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
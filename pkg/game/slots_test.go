@@ -0,0 +1,66 @@
+package game
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateSlotName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"friday", false},
+		{"big-board-2", false},
+		{"", true},
+		{"Friday", true},
+		{"my_game", true},
+		{"-friday", true},
+		{strings.Repeat("a", 63-len(SaveSlotPrefix)), false},
+		{strings.Repeat("a", 64-len(SaveSlotPrefix)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSlotName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSlotName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSlotStores(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	live := NewSecretStore(c)
+	if err := live.Save(ctx, NewGameState(3, 1)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	for _, name := range []string{"monday", "friday"} {
+		state := NewGameState(5, 2)
+		state.Level = 3
+		if err := NewSlotStore(c, DefaultNamespace, name).Save(ctx, state); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	names, err := ListSlots(ctx, c, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("ListSlots failed: %v", err)
+	}
+	if want := []string{"friday", "monday"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListSlots() = %v, want %v", names, want)
+	}
+
+	saved, err := NewSlotStore(c, DefaultNamespace, "friday").Load(ctx)
+	if err != nil || saved == nil || saved.Level != 3 {
+		t.Fatalf("expected the saved game back, got %+v (%v)", saved, err)
+	}
+	if state, _ := live.Load(ctx); state == nil || state.Size != 3 {
+		t.Errorf("expected the live game to be left alone, got %+v", state)
+	}
+}
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// SpeedrunResolution is the precision of recorded speedrun times.
+const SpeedrunResolution = 10 * time.Millisecond
+
+// FormatSpeedrunTime formats a play time as minutes, seconds and
+// hundredths, like "1:02.35".
+func FormatSpeedrunTime(d time.Duration) string {
+	d = d.Round(SpeedrunResolution)
+	centis := d / SpeedrunResolution
+	return fmt.Sprintf("%d:%02d.%02d", centis/6000, centis/100%60, centis%100)
+}
+
+// SpeedrunTime returns the play time of a speedrun: the recorded final
+// time once it ended, the live play time as of now otherwise.
+func (g *GameState) SpeedrunTime(now time.Time) time.Duration {
+	if g.FinalTime > 0 {
+		return g.FinalTime
+	}
+	return g.ElapsedAt(now).Round(SpeedrunResolution)
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatSpeedrunTime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00.00"},
+		{1234 * time.Millisecond, "0:01.23"},
+		{62*time.Second + 346*time.Millisecond, "1:02.35"},
+		{61 * time.Minute, "61:00.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatSpeedrunTime(tt.d); got != tt.want {
+			t.Errorf("FormatSpeedrunTime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestSpeedrunFinalTime(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := NewGameState(3, 1)
+	state.Speedrun = true
+	state.StartedAt = start
+	state.PausedDuration = 2 * time.Second
+
+	if got := state.SpeedrunTime(start.Add(10*time.Second + 5*time.Millisecond)); got != 8010*time.Millisecond {
+		t.Errorf("expected a live time of 8.01s, got %v", got)
+	}
+
+	state.end(StatusWon, start.Add(12*time.Second+3456*time.Microsecond))
+	if state.FinalTime != 10*time.Second {
+		t.Errorf("expected a final time rounded to the hundredth, got %v", state.FinalTime)
+	}
+	if got := state.SpeedrunTime(start.Add(time.Hour)); got != state.FinalTime {
+		t.Errorf("expected the final time once ended, got %v", got)
+	}
+	if clone := state.Clone(); clone.FinalTime != state.FinalTime || !clone.Speedrun {
+		t.Error("expected the speedrun to be cloned")
+	}
+
+	normal := NewGameState(3, 1)
+	normal.StartedAt = start
+	normal.end(StatusWon, start.Add(time.Second))
+	if normal.FinalTime != 0 {
+		t.Errorf("expected no final time outside of speedruns, got %v", normal.FinalTime)
+	}
+}
@@ -30,39 +30,154 @@ func (c Coordinate) String() string {
 	return fmt.Sprintf("(%d,%d)", c.X, c.Y)
 }
 
-// PodName returns the Kubernetes pod name for this coordinate.
+// PodName returns the Kubernetes pod name for this coordinate, in the
+// naming scheme of the process.
 func (c Coordinate) PodName() string {
-	return fmt.Sprintf("pod-%d-%d", c.X, c.Y)
+	scheme := Naming()
+	return scheme.format(scheme.CellPrefix, c.X, c.Y)
 }
 
 // HintPodName returns the hint pod name for this coordinate.
 func (c Coordinate) HintPodName() string {
-	return fmt.Sprintf("hint-%d-%d", c.X, c.Y)
+	scheme := Naming()
+	return scheme.format(scheme.HintPrefix, c.X, c.Y)
+}
+
+// DefusedPodName returns the marker pod name of a defused mine at this coordinate.
+func (c Coordinate) DefusedPodName() string {
+	scheme := Naming()
+	return scheme.format(scheme.DefusedPrefix, c.X, c.Y)
+}
+
+// BombPodName returns the defusal challenge pod name of a mine at this
+// coordinate.
+func (c Coordinate) BombPodName() string {
+	scheme := Naming()
+	return scheme.format(scheme.BombPrefix, c.X, c.Y)
+}
+
+// DeleteCommand returns the kubectl command clicking this cell.
+func (c Coordinate) DeleteCommand(namespace string) string {
+	return fmt.Sprintf("kubectl delete pod %s -n %s", c.PodName(), namespace)
 }
 
 // GameState holds the complete state of a PodSweeper game.
 // This is serialized to JSON and stored in a Kubernetes Secret.
 type GameState struct {
-	// Size is the dimension of the grid (Size x Size).
+	// Size is the dimension of square grids (Size x Size).
+	// It is 0 for rectangular grids; use Width and Height instead.
 	Size int `json:"size"`
 
+	// Width is the number of columns (X axis) of the grid.
+	Width int `json:"width,omitempty"`
+
+	// Height is the number of rows (Y axis) of the grid.
+	Height int `json:"height,omitempty"`
+
 	// Seed is the random seed used to generate the mine placement.
 	// Using the same seed produces the same mine layout.
 	Seed int64 `json:"seed"`
 
+	// CampaignSeed is the master seed of the campaign this board belongs to.
+	// The board seed is derived from it and the level. Zero for standalone games.
+	CampaignSeed int64 `json:"campaignSeed,omitempty"`
+
+	// Placement is the mine placement strategy used to generate the grid.
+	// Together with Seed, it makes the mine layout reproducible.
+	Placement string `json:"placement,omitempty"`
+
+	// Opening is a coordinate guaranteed to be safe (and usually a zero
+	// cell) when the grid was generated with an opening area.
+	Opening *Coordinate `json:"opening,omitempty"`
+
 	// Level is the current difficulty/hardening level (0-9).
 	Level int `json:"level"`
 
+	// Tutorial enables guidance for new Kubernetes users: the next command
+	// to run is suggested on a pod and every move is explained in Events.
+	Tutorial bool `json:"tutorial,omitempty"`
+
+	// Defusal gives mines a second chance: hitting one starts a challenge
+	// on its bomb pod, and the mine only explodes if the challenge is
+	// failed. See package defusal.
+	Defusal bool `json:"defusal,omitempty"`
+
+	// Theme is how the game is rendered, such as "high-contrast". See
+	// package theme. Empty is the classic theme.
+	Theme string `json:"theme,omitempty"`
+
+	// Speedrun shows a live timer and records the final time to the
+	// hundredth of a second, for time-based rankings per seed.
+	Speedrun bool `json:"speedrun,omitempty"`
+
+	// HintDecay is how long hints show their value once revealed, forcing
+	// players to remember them. Zero keeps hints for the whole game.
+	HintDecay time.Duration `json:"hintDecay,omitempty"`
+
+	// HintType is what revealed safe cells tell players. Empty is
+	// HintCount.
+	HintType HintType `json:"hintType,omitempty"`
+
+	// ShiftEvery relocates a hidden mine every that many moves in shifting
+	// mines mode, see ShiftDue. Zero keeps the mines in place.
+	ShiftEvery int `json:"shiftEvery,omitempty"`
+
+	// ShiftMoves counts the moves played since the last mine shift.
+	ShiftMoves int `json:"shiftMoves,omitempty"`
+
+	// Shifts counts the mines relocated in shifting mines mode.
+	Shifts int `json:"shifts,omitempty"`
+
+	// Lives is the number of mines players without a team can still hit
+	// in multi-life mode, see HitMine: the game is lost with the last one.
+	// Zero plays with a single life.
+	Lives int `json:"lives,omitempty"`
+
+	// TimeLimit is how long the game may be played in timed mode, see
+	// TimeUp: the game is lost once it runs out. Zero plays without limit.
+	TimeLimit time.Duration `json:"timeLimit,omitempty"`
+
+	// TimedOut is true if the game was lost by running out of time.
+	TimedOut bool `json:"timedOut,omitempty"`
+
+	// Rules are the custom rules of the level or variant, see Rules. Nil
+	// plays by the built-in rules only.
+	Rules *Rules `json:"rules,omitempty"`
+
+	// ScoreBonus is added to the score by the score rule, negative for a
+	// penalty.
+	ScoreBonus int `json:"scoreBonus,omitempty"`
+
+	// ScoreCard is the scoring of the game once it ended, see package
+	// scoring. Nil while the game is in progress.
+	ScoreCard *ScoreCard `json:"scoreCard,omitempty"`
+
 	// Status is the current game status (playing, won, lost).
 	Status GameStatus `json:"status"`
 
-	// MineMap is a 2D boolean array where true indicates a mine.
-	// MineMap[x][y] corresponds to pod-x-y.
-	MineMap [][]bool `json:"mineMap"`
+	// Phase is the lifecycle step of the game, see CurrentPhase. Empty in
+	// states saved before phases.
+	Phase Phase `json:"phase,omitempty"`
+
+	// Cells is the grid of cells. Cells[x][y] corresponds to pod-x-y.
+	// Prefer the accessors (IsMine, Reveal, ...) over direct access.
+	Cells [][]Cell `json:"cells"`
 
-	// Revealed is a 2D boolean array tracking which cells have been revealed.
-	// Revealed[x][y] is true if the cell has been clicked/deleted.
-	Revealed [][]bool `json:"revealed"`
+	// CTF holds the flag of a capture-the-flag game. Nil otherwise.
+	CTF *CTF `json:"ctf,omitempty"`
+
+	// Teams lists the teams sharing the board in team mode.
+	Teams []Team `json:"teams,omitempty"`
+
+	// Cheats lists players caught reading what the level hides. Their
+	// score is tainted.
+	Cheats []Cheat `json:"cheats,omitempty"`
+
+	// Defusals are the defusal challenges pending in defusal mode.
+	Defusals []Defusal `json:"defusals,omitempty"`
+
+	// Moves is the move log of the game, see ReplayFrom.
+	Moves MoveLog `json:"moves,omitempty"`
 
 	// HintCells tracks cells that have been converted to hint pods.
 	// These are cells adjacent to mines that show a number.
@@ -77,36 +192,97 @@ type GameState struct {
 	// EndedAt is when the game ended (won or lost). Zero if still playing.
 	EndedAt time.Time `json:"endedAt,omitempty"`
 
+	// Resigned is true if the game was lost by resignation rather than by
+	// hitting a mine.
+	Resigned bool `json:"resigned,omitempty"`
+
+	// ResignedBy identifies who resigned, if known.
+	ResignedBy string `json:"resignedBy,omitempty"`
+
+	// FinalTime is the play time of an ended speedrun, rounded to
+	// SpeedrunResolution. Zero otherwise.
+	FinalTime time.Duration `json:"finalTime,omitempty"`
+
+	// PausedAt is when the game was paused. Zero while running.
+	PausedAt time.Time `json:"pausedAt,omitzero"`
+
+	// PausedDuration is the accumulated time spent paused or with the
+	// Gamemaster down. It is excluded from Elapsed.
+	PausedDuration time.Duration `json:"pausedDuration,omitempty"`
+
+	// HeartbeatAt is the last time the Gamemaster recorded it was running
+	// this game. Used to detect downtime.
+	HeartbeatAt time.Time `json:"heartbeatAt,omitzero"`
+
 	// Clicks is the number of cells the player has clicked/deleted.
 	Clicks int `json:"clicks"`
+
+	// PowerUps tracks the power-up charges earned and spent, and the armed
+	// power-up.
+	PowerUps PowerUps `json:"powerUps,omitzero"`
 }
 
-// NewGameState creates a new empty GameState with the given size.
-// The MineMap and Revealed grids are initialized but empty (no mines placed).
-// Use a grid generator to populate the MineMap.
+// NewGameState creates a new empty square GameState with the given size.
+// The cell grid is initialized but empty (no mines placed).
+// Use a grid generator to place mines.
 func NewGameState(size int, seed int64) *GameState {
-	mineMap := make([][]bool, size)
-	revealed := make([][]bool, size)
-	for i := 0; i < size; i++ {
-		mineMap[i] = make([]bool, size)
-		revealed[i] = make([]bool, size)
+	return NewRectGameState(size, size, seed)
+}
+
+// NewRectGameState creates a new empty GameState of width x height cells.
+// Square dimensions also set Size for compatibility.
+func NewRectGameState(width, height int, seed int64) *GameState {
+	size := 0
+	if width == height {
+		size = width
 	}
 
 	return &GameState{
 		Size:      size,
+		Width:     width,
+		Height:    height,
 		Seed:      seed,
 		Level:     0,
 		Status:    StatusPlaying,
-		MineMap:   mineMap,
-		Revealed:  revealed,
+		Cells:     newCells(width, height),
 		HintCells: []Coordinate{},
 		StartedAt: time.Now(),
 	}
 }
 
+// ID identifies a game from its seed and start time.
+func (g *GameState) ID() string {
+	return fmt.Sprintf("%d-%d", g.Seed, g.StartedAt.Unix())
+}
+
+// Dimensions returns the width and height of the grid.
+// States persisted before rectangular support only have Size set.
+func (g *GameState) Dimensions() (width, height int) {
+	if g.Width > 0 && g.Height > 0 {
+		return g.Width, g.Height
+	}
+	return g.Size, g.Size
+}
+
+// TotalCells returns the number of cells of the grid.
+func (g *GameState) TotalCells() int {
+	w, h := g.Dimensions()
+	return w * h
+}
+
 // IsValidCoordinate checks if the given coordinate is within the grid bounds.
 func (g *GameState) IsValidCoordinate(x, y int) bool {
-	return x >= 0 && x < g.Size && y >= 0 && y < g.Size
+	w, h := g.Dimensions()
+	return x >= 0 && x < w && y >= 0 && y < h
+}
+
+// Cell returns a copy of the cell at (x, y).
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) Cell(x, y int) (Cell, bool) {
+	if !g.IsValidCoordinate(x, y) {
+		return Cell{}, false
+	}
+	return g.Cells[x][y], true
 }
 
 // IsMine checks if the cell at (x, y) contains a mine.
@@ -115,7 +291,7 @@ func (g *GameState) IsMine(x, y int) bool {
 	if !g.IsValidCoordinate(x, y) {
 		return false
 	}
-	return g.MineMap[x][y]
+	return g.Cells[x][y].Mine
 }
 
 // IsRevealed checks if the cell at (x, y) has been revealed.
@@ -124,17 +300,137 @@ func (g *GameState) IsRevealed(x, y int) bool {
 	if !g.IsValidCoordinate(x, y) {
 		return false
 	}
-	return g.Revealed[x][y]
+	return g.Cells[x][y].Revealed
+}
+
+// PodUID returns the UID of the pod last spawned for the cell at (x, y),
+// empty if unknown or out of bounds.
+func (g *GameState) PodUID(x, y int) string {
+	if !g.IsValidCoordinate(x, y) {
+		return ""
+	}
+	return g.Cells[x][y].PodUID
+}
+
+// SetPodUID records the UID of the pod spawned for the cell at (x, y).
+// An empty uid forgets it, when the pod is gone or its UID unknown.
+func (g *GameState) SetPodUID(x, y int, uid string) {
+	if !g.IsValidCoordinate(x, y) {
+		return
+	}
+	g.Cells[x][y].PodUID = uid
+}
+
+// IsFlagged checks if the cell at (x, y) is flagged as a mine.
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) IsFlagged(x, y int) bool {
+	if !g.IsValidCoordinate(x, y) {
+		return false
+	}
+	return g.Cells[x][y].Flagged
+}
+
+// IsPlayerFlagged checks if the cell at (x, y) was flagged by a player
+// annotating its pod, see SetPlayerFlag.
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) IsPlayerFlagged(x, y int) bool {
+	if !g.IsValidCoordinate(x, y) {
+		return false
+	}
+	return g.Cells[x][y].PlayerFlagged
+}
+
+// IsQuestioned checks if the cell at (x, y) is marked as uncertain.
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) IsQuestioned(x, y int) bool {
+	if !g.IsValidCoordinate(x, y) {
+		return false
+	}
+	return g.Cells[x][y].Question
 }
 
 // Reveal marks the cell at (x, y) as revealed.
 // Returns false if the coordinate is out of bounds or already revealed.
 func (g *GameState) Reveal(x, y int) bool {
-	if !g.IsValidCoordinate(x, y) || g.Revealed[x][y] {
+	return g.RevealBy(x, y, "")
+}
+
+// RevealBy marks the cell at (x, y) as directly revealed by the given player.
+// Returns false if the coordinate is out of bounds or already revealed.
+func (g *GameState) RevealBy(x, y int, player string) bool {
+	return g.RevealWith(x, y, RevealInfo{By: player})
+}
+
+// RevealWith marks the cell at (x, y) as revealed, recording who revealed it,
+// when, and whether it was clicked directly or reached by propagation. Cells
+// clicked directly are recorded in the move log.
+// Returns false if the coordinate is out of bounds or already revealed.
+func (g *GameState) RevealWith(x, y int, info RevealInfo) bool {
+	if !g.IsValidCoordinate(x, y) || g.Cells[x][y].Revealed {
 		return false
 	}
-	g.Revealed[x][y] = true
+	if info.At.IsZero() {
+		info.At = time.Now()
+	}
+	cell := &g.Cells[x][y]
+	cell.Revealed = true
+	cell.Flagged = false
+	cell.PlayerFlagged = false
+	cell.Question = false
+	cell.Hint = g.AdjacentMines(x, y)
+	cell.RevealedBy = info.By
+	cell.RevealedAt = info.At
+	cell.Propagated = info.Propagated
 	g.Clicks++
+	if !info.Propagated {
+		result := MoveSafe
+		if cell.Mine {
+			result = MoveMine
+		}
+		g.record(x, y, result, info.By, info.At)
+	}
+	return true
+}
+
+// SetFlag flags or unflags the cell at (x, y). Revealed cells cannot be
+// flagged. Flagging clears any question mark.
+// Returns false if the coordinate is out of bounds or the cell is revealed.
+func (g *GameState) SetFlag(x, y int, flagged bool) bool {
+	if !g.IsValidCoordinate(x, y) || g.Cells[x][y].Revealed {
+		return false
+	}
+	g.Cells[x][y].Flagged = flagged
+	if flagged {
+		g.Cells[x][y].Question = false
+	} else {
+		g.Cells[x][y].PlayerFlagged = false
+	}
+	return true
+}
+
+// SetPlayerFlag flags or unflags the cell at (x, y) on behalf of a player,
+// who must unflag it before deleting its pod.
+// Returns false if the coordinate is out of bounds or the cell is revealed.
+func (g *GameState) SetPlayerFlag(x, y int, flagged bool) bool {
+	if !g.SetFlag(x, y, flagged) {
+		return false
+	}
+	g.Cells[x][y].PlayerFlagged = flagged
+	return true
+}
+
+// SetQuestion marks or unmarks the cell at (x, y) as uncertain.
+// Marking clears any flag.
+// Returns false if the coordinate is out of bounds or the cell is revealed.
+func (g *GameState) SetQuestion(x, y int, question bool) bool {
+	if !g.IsValidCoordinate(x, y) || g.Cells[x][y].Revealed {
+		return false
+	}
+	g.Cells[x][y].Question = question
+	if question {
+		g.Cells[x][y].Flagged = false
+		g.Cells[x][y].PlayerFlagged = false
+	}
 	return true
 }
 
@@ -144,13 +440,38 @@ func (g *GameState) SetMine(x, y int) bool {
 	if !g.IsValidCoordinate(x, y) {
 		return false
 	}
-	if !g.MineMap[x][y] {
-		g.MineMap[x][y] = true
+	if !g.Cells[x][y].Mine {
+		g.Cells[x][y].Mine = true
 		g.MineCount++
+		g.refreshHints(x, y)
+	}
+	return true
+}
+
+// ClearMine removes a mine from the given coordinate.
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) ClearMine(x, y int) bool {
+	if !g.IsValidCoordinate(x, y) {
+		return false
+	}
+	if g.Cells[x][y].Mine {
+		g.Cells[x][y].Mine = false
+		g.MineCount--
+		g.refreshHints(x, y)
 	}
 	return true
 }
 
+// refreshHints recomputes the cached hints of revealed neighbors of (x, y)
+// after its mine changed.
+func (g *GameState) refreshHints(x, y int) {
+	for _, n := range g.GetNeighbors(x, y) {
+		if cell := &g.Cells[n.X][n.Y]; cell.Revealed {
+			cell.Hint = g.AdjacentMines(n.X, n.Y)
+		}
+	}
+}
+
 // AdjacentMines returns the count of mines adjacent to the cell at (x, y).
 // This includes all 8 neighboring cells (diagonals included).
 func (g *GameState) AdjacentMines(x, y int) int {
@@ -168,6 +489,18 @@ func (g *GameState) AdjacentMines(x, y int) int {
 	return count
 }
 
+// AdjacentFlags returns the count of flagged cells adjacent to the cell at
+// (x, y), defused mines included.
+func (g *GameState) AdjacentFlags(x, y int) int {
+	count := 0
+	for _, n := range g.GetNeighbors(x, y) {
+		if g.Cells[n.X][n.Y].Flagged {
+			count++
+		}
+	}
+	return count
+}
+
 // GetNeighbors returns all valid neighboring coordinates for the cell at (x, y).
 func (g *GameState) GetNeighbors(x, y int) []Coordinate {
 	neighbors := make([]Coordinate, 0, 8)
@@ -187,10 +520,11 @@ func (g *GameState) GetNeighbors(x, y int) []Coordinate {
 
 // UnrevealedSafeCells returns the count of cells that are not mines and not revealed.
 func (g *GameState) UnrevealedSafeCells() int {
+	w, h := g.Dimensions()
 	count := 0
-	for x := 0; x < g.Size; x++ {
-		for y := 0; y < g.Size; y++ {
-			if !g.MineMap[x][y] && !g.Revealed[x][y] {
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if cell := g.Cells[x][y]; !cell.Mine && !cell.Revealed {
 				count++
 			}
 		}
@@ -206,14 +540,23 @@ func (g *GameState) CheckVictory() bool {
 
 // SetWon marks the game as won and records the end time.
 func (g *GameState) SetWon() {
-	g.Status = StatusWon
-	g.EndedAt = time.Now()
+	g.end(StatusWon, time.Now())
 }
 
 // SetLost marks the game as lost and records the end time.
 func (g *GameState) SetLost() {
-	g.Status = StatusLost
-	g.EndedAt = time.Now()
+	g.end(StatusLost, time.Now())
+}
+
+// end sets the final status, closing any pause first.
+func (g *GameState) end(status GameStatus, now time.Time) {
+	g.ResumeAt(now)
+	g.Status = status
+	g.Phase = PhaseFinished
+	g.EndedAt = now
+	if g.Speedrun {
+		g.FinalTime = g.ElapsedAt(now).Round(SpeedrunResolution)
+	}
 }
 
 // AddHintCell records that a hint pod was created at the given coordinate.
@@ -231,6 +574,37 @@ func (g *GameState) ToJSONPretty() ([]byte, error) {
 	return json.MarshalIndent(g, "", "  ")
 }
 
+// UnmarshalJSON decodes a GameState, converting the MineMap/Revealed grids of
+// states persisted before the Cell grid was introduced.
+func (g *GameState) UnmarshalJSON(data []byte) error {
+	type plain GameState
+	aux := struct {
+		*plain
+		MineMap  [][]bool `json:"mineMap,omitempty"`
+		Revealed [][]bool `json:"revealed,omitempty"`
+	}{plain: (*plain)(g)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// States saved before rectangular support only have Size
+	if g.Width == 0 && g.Height == 0 {
+		g.Width, g.Height = g.Size, g.Size
+	}
+
+	if g.Cells == nil && aux.MineMap != nil {
+		g.Cells = cellsFromLegacy(aux.MineMap, aux.Revealed)
+		for x := range g.Cells {
+			for y := range g.Cells[x] {
+				if g.Cells[x][y].Revealed {
+					g.Cells[x][y].Hint = g.AdjacentMines(x, y)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // FromJSON deserializes a GameState from JSON bytes.
 func FromJSON(data []byte) (*GameState, error) {
 	var state GameState
@@ -243,28 +617,66 @@ func FromJSON(data []byte) (*GameState, error) {
 // Clone creates a deep copy of the GameState.
 func (g *GameState) Clone() *GameState {
 	clone := &GameState{
-		Size:      g.Size,
-		Seed:      g.Seed,
-		Level:     g.Level,
-		Status:    g.Status,
-		MineCount: g.MineCount,
-		StartedAt: g.StartedAt,
-		EndedAt:   g.EndedAt,
-		Clicks:    g.Clicks,
+		Size:           g.Size,
+		Width:          g.Width,
+		Height:         g.Height,
+		Seed:           g.Seed,
+		CampaignSeed:   g.CampaignSeed,
+		Placement:      g.Placement,
+		Level:          g.Level,
+		Tutorial:       g.Tutorial,
+		Defusal:        g.Defusal,
+		Theme:          g.Theme,
+		Speedrun:       g.Speedrun,
+		HintDecay:      g.HintDecay,
+		HintType:       g.HintType,
+		ShiftEvery:     g.ShiftEvery,
+		ShiftMoves:     g.ShiftMoves,
+		Shifts:         g.Shifts,
+		Lives:          g.Lives,
+		TimeLimit:      g.TimeLimit,
+		TimedOut:       g.TimedOut,
+		ScoreBonus:     g.ScoreBonus,
+		Status:         g.Status,
+		Phase:          g.Phase,
+		MineCount:      g.MineCount,
+		StartedAt:      g.StartedAt,
+		EndedAt:        g.EndedAt,
+		Resigned:       g.Resigned,
+		ResignedBy:     g.ResignedBy,
+		FinalTime:      g.FinalTime,
+		PausedAt:       g.PausedAt,
+		PausedDuration: g.PausedDuration,
+		HeartbeatAt:    g.HeartbeatAt,
+		Clicks:         g.Clicks,
+		PowerUps:       g.PowerUps.clone(),
+		Defusals:       cloneDefusals(g.Defusals),
+	}
+
+	// Deep copy Cells
+	clone.Cells = make([][]Cell, len(g.Cells))
+	for i := range g.Cells {
+		clone.Cells[i] = make([]Cell, len(g.Cells[i]))
+		copy(clone.Cells[i], g.Cells[i])
 	}
 
-	// Deep copy MineMap
-	clone.MineMap = make([][]bool, g.Size)
-	for i := 0; i < g.Size; i++ {
-		clone.MineMap[i] = make([]bool, g.Size)
-		copy(clone.MineMap[i], g.MineMap[i])
+	if g.Rules != nil {
+		rules := *g.Rules
+		clone.Rules = &rules
 	}
 
-	// Deep copy Revealed
-	clone.Revealed = make([][]bool, g.Size)
-	for i := 0; i < g.Size; i++ {
-		clone.Revealed[i] = make([]bool, g.Size)
-		copy(clone.Revealed[i], g.Revealed[i])
+	if g.Opening != nil {
+		opening := *g.Opening
+		clone.Opening = &opening
+	}
+
+	clone.Teams = cloneTeams(g.Teams)
+	clone.CTF = cloneCTF(g.CTF)
+	clone.Cheats = append([]Cheat(nil), g.Cheats...)
+	clone.Moves = append(MoveLog(nil), g.Moves...)
+	if g.ScoreCard != nil {
+		card := *g.ScoreCard
+		clone.ScoreCard = &card
 	}
 
 	// Deep copy HintCells
@@ -274,27 +686,28 @@ func (g *GameState) Clone() *GameState {
 	return clone
 }
 
-// Stats returns a summary of the current game state.
-func (g *GameState) Stats() map[string]interface{} {
-	totalCells := g.Size * g.Size
-	revealedCount := 0
-	for x := 0; x < g.Size; x++ {
-		for y := 0; y < g.Size; y++ {
-			if g.Revealed[x][y] {
-				revealedCount++
+// Redacted returns a deep copy of the state without its secrets, for
+// debugging games in progress: the mines and hints of the hidden cells, the
+// seeds the mines could be generated again from, and the CTF flag.
+// Defused mines were hit, so they are kept.
+func (g *GameState) Redacted() *GameState {
+	redacted := g.Clone()
+	for y := range redacted.Cells {
+		for x := range redacted.Cells[y] {
+			cell := &redacted.Cells[y][x]
+			if !cell.Revealed && !cell.Defused {
+				cell.Mine = false
+				cell.Hint = 0
 			}
 		}
 	}
-
-	return map[string]interface{}{
-		"size":           g.Size,
-		"level":          g.Level,
-		"status":         g.Status,
-		"mines":          g.MineCount,
-		"totalCells":     totalCells,
-		"revealedCells":  revealedCount,
-		"remainingSafe":  g.UnrevealedSafeCells(),
-		"clicks":         g.Clicks,
-		"hintPodsPlaced": len(g.HintCells),
+	redacted.Seed = 0
+	redacted.CampaignSeed = 0
+	if redacted.CTF != nil {
+		redacted.CTF.Flag = ""
+		for i := range redacted.CTF.Fragments {
+			redacted.CTF.Fragments[i].Text = ""
+		}
 	}
+	return redacted
 }
@@ -27,11 +27,13 @@ func TestNewGameState(t *testing.T) {
 	if state.MineCount != 0 {
 		t.Errorf("expected mine count 0, got %d", state.MineCount)
 	}
-	if len(state.MineMap) != size {
-		t.Errorf("expected MineMap length %d, got %d", size, len(state.MineMap))
+	if len(state.Cells) != size {
+		t.Errorf("expected Cells length %d, got %d", size, len(state.Cells))
 	}
-	if len(state.Revealed) != size {
-		t.Errorf("expected Revealed length %d, got %d", size, len(state.Revealed))
+	for x := range state.Cells {
+		if len(state.Cells[x]) != size {
+			t.Errorf("expected column %d length %d, got %d", x, size, len(state.Cells[x]))
+		}
 	}
 	if state.StartedAt.IsZero() {
 		t.Error("expected StartedAt to be set")
@@ -388,33 +390,27 @@ func TestClone(t *testing.T) {
 	}
 }
 
-func TestStats(t *testing.T) {
-	state := NewGameState(5, 0)
-	state.SetMine(0, 0)
-	state.SetMine(1, 1)
-	state.Reveal(2, 2)
-	state.Reveal(3, 3)
-	state.AddHintCell(2, 2)
-
-	stats := state.Stats()
-
-	if stats["size"] != 5 {
-		t.Errorf("stats size mismatch")
-	}
-	if stats["mines"] != 2 {
-		t.Errorf("stats mines mismatch")
+func TestRedacted(t *testing.T) {
+	state := NewGameState(5, 12345)
+	state.SetMine(1, 2)
+	state.SetMine(4, 4)
+	state.Reveal(0, 0)
+	if err := state.EnableCTF(1); err != nil {
+		t.Fatalf("EnableCTF failed: %v", err)
 	}
-	if stats["totalCells"] != 25 {
-		t.Errorf("stats totalCells mismatch")
+
+	redacted := state.Redacted()
+	if redacted.IsMine(1, 2) || redacted.IsMine(4, 4) || redacted.Seed != 0 {
+		t.Error("expected the mines and the seed to be redacted")
 	}
-	if stats["revealedCells"] != 2 {
-		t.Errorf("stats revealedCells mismatch")
+	if !redacted.IsRevealed(0, 0) || redacted.MineCount != state.MineCount {
+		t.Error("expected the progress to be kept")
 	}
-	if stats["clicks"] != 2 {
-		t.Errorf("stats clicks mismatch")
+	if redacted.CTF.Flag != "" || redacted.CTF.Fragments[0].Text != "" {
+		t.Error("expected the flag to be redacted")
 	}
-	if stats["hintPodsPlaced"] != 1 {
-		t.Errorf("stats hintPodsPlaced mismatch")
+	if !state.IsMine(1, 2) || state.Seed != 12345 || state.CTF.Flag == "" {
+		t.Error("expected the original state to be left alone")
 	}
 }
 
@@ -425,8 +421,8 @@ func TestGameStateZeroValues(t *testing.T) {
 	if state.Size != 0 {
 		t.Errorf("expected size 0, got %d", state.Size)
 	}
-	if len(state.MineMap) != 0 {
-		t.Errorf("expected empty MineMap, got length %d", len(state.MineMap))
+	if len(state.Cells) != 0 {
+		t.Errorf("expected empty Cells, got length %d", len(state.Cells))
 	}
 
 	// These should not panic
@@ -496,3 +492,192 @@ func TestTimeFields(t *testing.T) {
 		t.Error("EndedAt should be set when game ends")
 	}
 }
+
+func TestNewRectGameState(t *testing.T) {
+	state := NewRectGameState(8, 3, 42)
+
+	w, h := state.Dimensions()
+	if w != 8 || h != 3 {
+		t.Errorf("expected 8x3, got %dx%d", w, h)
+	}
+	if state.Size != 0 {
+		t.Errorf("expected Size 0 for rectangular grid, got %d", state.Size)
+	}
+	if state.TotalCells() != 24 {
+		t.Errorf("expected 24 cells, got %d", state.TotalCells())
+	}
+	if !state.IsValidCoordinate(7, 2) {
+		t.Error("(7,2) should be valid on an 8x3 grid")
+	}
+	if state.IsValidCoordinate(2, 7) {
+		t.Error("(2,7) should be invalid on an 8x3 grid")
+	}
+	if state.UnrevealedSafeCells() != 24 {
+		t.Errorf("expected 24 unrevealed safe cells, got %d", state.UnrevealedSafeCells())
+	}
+
+	clone := state.Clone()
+	if cw, ch := clone.Dimensions(); cw != 8 || ch != 3 {
+		t.Errorf("clone dimensions = %dx%d, want 8x3", cw, ch)
+	}
+}
+
+func TestFromJSONLegacySquareState(t *testing.T) {
+	data := []byte(`{"size":3,"seed":1,"level":0,"status":"playing",` +
+		`"mineMap":[[false,false,false],[false,true,false],[false,false,false]],` +
+		`"revealed":[[false,false,false],[false,false,false],[false,false,false]],` +
+		`"mineCount":1,"startedAt":"2024-01-01T00:00:00Z","clicks":0}`)
+
+	state, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if state.Width != 3 || state.Height != 3 {
+		t.Errorf("expected legacy state to be normalized to 3x3, got %dx%d", state.Width, state.Height)
+	}
+	if !state.IsMine(1, 1) {
+		t.Error("expected mine at (1,1)")
+	}
+}
+
+func TestClearMine(t *testing.T) {
+	state := NewGameState(5, 0)
+	state.SetMine(2, 2)
+
+	if !state.ClearMine(2, 2) {
+		t.Error("ClearMine should return true for valid coordinate")
+	}
+	if state.IsMine(2, 2) || state.MineCount != 0 {
+		t.Errorf("expected mine to be cleared, MineCount=%d", state.MineCount)
+	}
+
+	// Clearing an empty cell does not change the count
+	state.ClearMine(1, 1)
+	if state.MineCount != 0 {
+		t.Errorf("expected MineCount 0, got %d", state.MineCount)
+	}
+	if state.ClearMine(-1, 0) {
+		t.Error("ClearMine should return false for invalid coordinate")
+	}
+}
+
+func TestRevealByCachesHint(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.SetMine(0, 0)
+	state.SetFlag(1, 1, true)
+
+	if !state.RevealBy(1, 1, "alice") {
+		t.Fatal("RevealBy should succeed on an unrevealed cell")
+	}
+	cell, _ := state.Cell(1, 1)
+	if cell.Hint != 1 {
+		t.Errorf("expected hint 1, got %d", cell.Hint)
+	}
+	if cell.RevealedBy != "alice" {
+		t.Errorf("expected revealedBy alice, got %q", cell.RevealedBy)
+	}
+	if cell.Flagged {
+		t.Error("revealing should clear the flag")
+	}
+
+	// Changing a neighboring mine refreshes the cached hint
+	state.SetMine(2, 2)
+	if cell, _ := state.Cell(1, 1); cell.Hint != 2 {
+		t.Errorf("expected hint 2 after adding a mine, got %d", cell.Hint)
+	}
+	state.ClearMine(0, 0)
+	if cell, _ := state.Cell(1, 1); cell.Hint != 1 {
+		t.Errorf("expected hint 1 after clearing a mine, got %d", cell.Hint)
+	}
+}
+
+func TestFlagAndQuestion(t *testing.T) {
+	state := NewGameState(3, 0)
+
+	if !state.SetFlag(0, 0, true) || !state.IsFlagged(0, 0) {
+		t.Error("expected (0,0) to be flagged")
+	}
+	if !state.SetQuestion(0, 0, true) || !state.IsQuestioned(0, 0) {
+		t.Error("expected (0,0) to be questioned")
+	}
+	if state.IsFlagged(0, 0) {
+		t.Error("question mark should clear the flag")
+	}
+	if !state.SetFlag(0, 0, true) || state.IsQuestioned(0, 0) {
+		t.Error("flag should clear the question mark")
+	}
+
+	state.Reveal(1, 1)
+	if state.SetFlag(1, 1, true) {
+		t.Error("revealed cells cannot be flagged")
+	}
+	if state.SetQuestion(1, 1, true) {
+		t.Error("revealed cells cannot be questioned")
+	}
+	if state.SetFlag(5, 5, true) || state.IsFlagged(5, 5) {
+		t.Error("out of bounds cells cannot be flagged")
+	}
+	if _, ok := state.Cell(-1, 0); ok {
+		t.Error("Cell should report out of bounds coordinates")
+	}
+}
+
+func TestSetPlayerFlag(t *testing.T) {
+	state := NewGameState(3, 0)
+
+	if !state.SetPlayerFlag(0, 0, true) || !state.IsFlagged(0, 0) || !state.IsPlayerFlagged(0, 0) {
+		t.Error("expected (0,0) to be flagged by a player")
+	}
+	if !state.SetFlag(0, 0, false) || state.IsPlayerFlagged(0, 0) {
+		t.Error("unflagging should clear the player flag")
+	}
+	if !state.SetFlag(1, 0, true) || state.IsPlayerFlagged(1, 0) {
+		t.Error("other flags are not player flags")
+	}
+
+	state.SetPlayerFlag(2, 2, true)
+	state.Reveal(2, 2)
+	if state.IsFlagged(2, 2) || state.IsPlayerFlagged(2, 2) {
+		t.Error("revealing should clear the player flag")
+	}
+	if state.SetPlayerFlag(2, 2, true) {
+		t.Error("revealed cells cannot be flagged")
+	}
+}
+
+func TestRevealWithRecordsMetadata(t *testing.T) {
+	state := NewGameState(3, 0)
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !state.RevealWith(0, 0, RevealInfo{By: "bob", At: at, Propagated: true}) {
+		t.Fatal("RevealWith should succeed on an unrevealed cell")
+	}
+	cell, _ := state.Cell(0, 0)
+	if cell.RevealedBy != "bob" || !cell.RevealedAt.Equal(at) || !cell.Propagated {
+		t.Errorf("unexpected reveal metadata: %+v", cell)
+	}
+
+	// Defaults: now, direct, anonymous
+	before := time.Now()
+	state.Reveal(1, 1)
+	cell, _ = state.Cell(1, 1)
+	if cell.RevealedAt.Before(before) || cell.Propagated || cell.RevealedBy != "" {
+		t.Errorf("unexpected default reveal metadata: %+v", cell)
+	}
+
+	// Metadata survives serialization; unrevealed cells stay compact
+	data, err := state.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if cell, _ := restored.Cell(0, 0); cell.RevealedBy != "bob" || !cell.RevealedAt.Equal(at) || !cell.Propagated {
+		t.Errorf("reveal metadata not preserved: %+v", cell)
+	}
+	if cell, _ := restored.Cell(2, 2); !cell.RevealedAt.IsZero() {
+		t.Errorf("expected zero RevealedAt for unrevealed cell, got %s", cell.RevealedAt)
+	}
+}
@@ -0,0 +1,267 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// PointsPerCell is the base number of points awarded per revealed safe cell.
+const PointsPerCell = 10
+
+// GameStats is a summary of a game, shared by API responses, logs and the
+// victory pod.
+type GameStats struct {
+	Size           int        `json:"size"`
+	Width          int        `json:"width"`
+	Height         int        `json:"height"`
+	Level          int        `json:"level"`
+	Status         GameStatus `json:"status"`
+	Mines          int        `json:"mines"`
+	TotalCells     int        `json:"totalCells"`
+	RevealedCells  int        `json:"revealedCells"`
+	FlaggedCells   int        `json:"flaggedCells"`
+	RemainingSafe  int        `json:"remainingSafe"`
+	RemainingMines int        `json:"remainingMines"`
+	Clicks         int        `json:"clicks"`
+	HintPodsPlaced int        `json:"hintPodsPlaced"`
+
+	// Progress is the percentage (0-100) of safe cells revealed.
+	Progress float64 `json:"progress"`
+
+	// ElapsedSeconds is the game duration so far, in seconds.
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+
+	// Teams ranks the teams in team mode.
+	Teams []TeamStats `json:"teams,omitempty"`
+
+	// Players ranks the players moves are attributed to.
+	Players []PlayerStats `json:"players,omitempty"`
+
+	// Score is PointsPerCell per revealed safe cell, multiplied by the
+	// hardening level (level 0 counts as 1), plus the ScoreBonus of the
+	// score rule.
+	Score int `json:"score"`
+
+	// Cheaters lists players caught cheating. A score with cheaters is
+	// tainted and isn't rated.
+	Cheaters []string `json:"cheaters,omitempty"`
+
+	// Resigned is true if the game was lost by resignation.
+	Resigned bool `json:"resigned,omitempty"`
+
+	// Charges is the number of power-up charges left to spend.
+	Charges int `json:"charges,omitempty"`
+
+	// Lives is the number of lives left in multi-life mode.
+	Lives int `json:"lives,omitempty"`
+
+	// TimeLimited is true for games played in timed mode.
+	TimeLimited bool `json:"timeLimited,omitempty"`
+
+	// RemainingSeconds is the play time left in timed mode, in seconds.
+	RemainingSeconds float64 `json:"remainingSeconds,omitempty"`
+
+	// TimedOut is true if the game was lost by running out of time.
+	TimedOut bool `json:"timedOut,omitempty"`
+}
+
+// Tainted reports whether someone cheated during the game.
+func (s GameStats) Tainted() bool {
+	return len(s.Cheaters) > 0
+}
+
+// Elapsed returns the game duration as a time.Duration.
+func (s GameStats) Elapsed() time.Duration {
+	return time.Duration(s.ElapsedSeconds * float64(time.Second))
+}
+
+// Remaining returns the play time left in timed mode as a time.Duration.
+func (s GameStats) Remaining() time.Duration {
+	return time.Duration(s.RemainingSeconds * float64(time.Second))
+}
+
+// String returns a one-line human-readable summary.
+func (s GameStats) String() string {
+	status := string(s.Status)
+	if s.Resigned {
+		status += " (resigned)"
+	}
+	if s.TimedOut {
+		status += " (timed out)"
+	}
+	summary := fmt.Sprintf("%dx%d level %d %s: %.0f%% revealed, %d clicks, %d mines left, %s, score %d",
+		s.Width, s.Height, s.Level, status, s.Progress, s.Clicks, s.RemainingMines,
+		s.Elapsed().Round(time.Second), s.Score)
+	if s.Charges > 0 {
+		summary += fmt.Sprintf(", %d power-up charge(s)", s.Charges)
+	}
+	if s.TimeLimited && !s.TimedOut {
+		summary += fmt.Sprintf(", %s left", s.Remaining().Round(time.Second))
+	}
+	if s.Lives > 0 {
+		summary += fmt.Sprintf(", lives left: %d", s.Lives)
+	}
+	if s.Tainted() {
+		summary += " (tainted)"
+	}
+	return summary
+}
+
+// Contributions counts the cells each player revealed directly, keyed by
+// the RevealedBy identity. Anonymous and propagated reveals are not counted.
+func (g *GameState) Contributions() map[string]int {
+	contributions := make(map[string]int)
+	for x := range g.Cells {
+		for _, cell := range g.Cells[x] {
+			if cell.Revealed && !cell.Propagated && cell.RevealedBy != "" {
+				contributions[cell.RevealedBy]++
+			}
+		}
+	}
+	return contributions
+}
+
+// PlayerStats summarizes the moves of a player.
+type PlayerStats struct {
+	// Player is the identity moves are attributed to, the username of
+	// players.
+	Player string `json:"player"`
+
+	// Moves counts the moves of the player in the move log.
+	Moves int `json:"moves"`
+
+	// CellsRevealed counts the safe cells the moves of the player
+	// revealed, propagation included.
+	CellsRevealed int `json:"cellsRevealed"`
+
+	// MinesHit counts the mines the player hit.
+	MinesHit int `json:"minesHit"`
+}
+
+// PlayerStats ranks the players of the move log by the safe cells they
+// revealed, then by the mines they hit. Anonymous moves are not counted.
+func (g *GameState) PlayerStats() []PlayerStats {
+	byPlayer := map[string]*PlayerStats{}
+	statsOf := func(player string) *PlayerStats {
+		if byPlayer[player] == nil {
+			byPlayer[player] = &PlayerStats{Player: player}
+		}
+		return byPlayer[player]
+	}
+	for _, m := range g.Moves {
+		if m.By == "" || m.By == UnattributedPlayer {
+			continue
+		}
+		s := statsOf(m.By)
+		s.Moves++
+		if m.Result != MoveSafe {
+			s.MinesHit++
+		}
+	}
+	for x := range g.Cells {
+		for _, cell := range g.Cells[x] {
+			if cell.Revealed && !cell.Mine && cell.RevealedBy != "" && cell.RevealedBy != UnattributedPlayer {
+				statsOf(cell.RevealedBy).CellsRevealed++
+			}
+		}
+	}
+
+	stats := make([]PlayerStats, 0, len(byPlayer))
+	for _, s := range byPlayer {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.CellsRevealed != b.CellsRevealed {
+			return a.CellsRevealed > b.CellsRevealed
+		}
+		if a.MinesHit != b.MinesHit {
+			return a.MinesHit < b.MinesHit
+		}
+		return a.Player < b.Player
+	})
+	return stats
+}
+
+// Stats returns a summary of the current game state.
+func (g *GameState) Stats() GameStats {
+	w, h := g.Dimensions()
+	stats := GameStats{
+		Size:           g.Size,
+		Width:          w,
+		Height:         h,
+		Level:          g.Level,
+		Status:         g.Status,
+		Mines:          g.MineCount,
+		TotalCells:     w * h,
+		Clicks:         g.Clicks,
+		HintPodsPlaced: len(g.HintCells),
+		ElapsedSeconds: g.Elapsed().Seconds(),
+		Lives:          g.Lives,
+	}
+
+	revealedSafe, defused := 0, 0
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			cell := g.Cells[x][y]
+			if cell.Revealed {
+				stats.RevealedCells++
+				if !cell.Mine {
+					revealedSafe++
+				}
+			} else if !cell.Mine {
+				stats.RemainingSafe++
+			}
+			if cell.Flagged {
+				stats.FlaggedCells++
+			}
+			if cell.Defused {
+				defused++
+			}
+		}
+	}
+
+	stats.RemainingMines = g.MineCount - stats.FlaggedCells - defused
+	if safe := stats.TotalCells - g.MineCount; safe > 0 {
+		stats.Progress = math.Round(1000*float64(revealedSafe)/float64(safe)) / 10
+	}
+	stats.Score = revealedSafe*PointsPerCell*max(g.Level, 1) + g.ScoreBonus
+	stats.Teams = g.TeamStats()
+	stats.Players = g.PlayerStats()
+	stats.Cheaters = g.Cheaters()
+	stats.Resigned = g.Resigned
+	stats.TimedOut = g.TimedOut
+	if remaining, ok := g.RemainingAt(time.Now()); ok {
+		stats.TimeLimited = true
+		stats.RemainingSeconds = remaining.Seconds()
+	}
+	if g.Status == StatusPlaying {
+		stats.Charges = g.PowerUps.Charges()
+	}
+
+	return stats
+}
+
+// ScoreCard scores a finished game on the classic Minesweeper metrics, see
+// package scoring.
+type ScoreCard struct {
+	// ThreeBV is the minimum number of clicks clearing the board.
+	ThreeBV int `json:"threeBV"`
+
+	// Clicks is the number of moves played.
+	Clicks int `json:"clicks"`
+
+	// Efficiency is the 3BV of the board per click, as a percentage, for
+	// won games only: 100% is a perfect game.
+	Efficiency float64 `json:"efficiency,omitempty"`
+
+	// ThreeBVPerSecond is the 3BV of the board per second of play time,
+	// for won games only.
+	ThreeBVPerSecond float64 `json:"threeBVPerSecond,omitempty"`
+
+	// TimeScore is ThreeBVPerSecond in thousandths, the time-based score
+	// comparing won games across board sizes: the faster the higher.
+	TimeScore int `json:"timeScore,omitempty"`
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	state := NewGameState(5, 0)
+	state.SetMine(0, 0)
+	state.SetMine(1, 1)
+	state.Reveal(2, 2)
+	state.Reveal(3, 3)
+	state.AddHintCell(2, 2)
+	state.SetFlag(0, 0, true)
+
+	stats := state.Stats()
+
+	tests := []struct {
+		name     string
+		got      int
+		expected int
+	}{
+		{"size", stats.Size, 5},
+		{"mines", stats.Mines, 2},
+		{"totalCells", stats.TotalCells, 25},
+		{"revealedCells", stats.RevealedCells, 2},
+		{"flaggedCells", stats.FlaggedCells, 1},
+		{"remainingSafe", stats.RemainingSafe, 21},
+		{"remainingMines", stats.RemainingMines, 1},
+		{"clicks", stats.Clicks, 2},
+		{"hintPodsPlaced", stats.HintPodsPlaced, 1},
+		{"score", stats.Score, 2 * PointsPerCell},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("stats %s: expected %d, got %d", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	// 2 of 23 safe cells
+	if stats.Progress != 8.7 {
+		t.Errorf("expected progress 8.7, got %v", stats.Progress)
+	}
+}
+
+func TestStatsElapsedAndScore(t *testing.T) {
+	state := NewGameState(2, 0)
+	state.SetMine(0, 0)
+	state.Level = 3
+	state.StartedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state.EndedAt = state.StartedAt.Add(90 * time.Second)
+	state.Reveal(1, 1)
+
+	stats := state.Stats()
+	if stats.Elapsed() != 90*time.Second {
+		t.Errorf("expected 90s elapsed, got %s", stats.Elapsed())
+	}
+	if stats.Score != PointsPerCell*3 {
+		t.Errorf("expected score %d, got %d", PointsPerCell*3, stats.Score)
+	}
+	if !strings.Contains(stats.String(), "score 30") {
+		t.Errorf("expected score in summary, got %q", stats.String())
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	data, err := json.Marshal(NewGameState(3, 0).Stats())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	for _, key := range []string{"size", "width", "height", "progress", "elapsedSeconds", "remainingMines", "score"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected key %q in JSON", key)
+		}
+	}
+}
+
+func TestContributions(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.RevealBy(0, 0, "alice")
+	state.RevealBy(1, 0, "alice")
+	state.RevealBy(2, 0, "bob")
+	state.RevealWith(0, 1, RevealInfo{By: "bob", Propagated: true})
+	state.Reveal(1, 1)
+
+	got := state.Contributions()
+	if len(got) != 2 || got["alice"] != 2 || got["bob"] != 1 {
+		t.Errorf("unexpected contributions: %v", got)
+	}
+}
+
+func TestPlayerStats(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.SetMine(2, 2)
+	state.Lives = 2
+	state.RevealBy(0, 0, "alice")
+	state.RevealWith(0, 1, RevealInfo{By: "alice", Propagated: true})
+	state.RevealBy(2, 0, "bob")
+	state.HitMine(2, 2, "bob")
+	state.Reveal(1, 1)
+
+	got := state.PlayerStats()
+	want := []PlayerStats{
+		{Player: "alice", Moves: 1, CellsRevealed: 2},
+		{Player: "bob", Moves: 2, CellsRevealed: 1, MinesHit: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PlayerStats() = %+v, want %+v", got, want)
+	}
+	if stats := state.Stats(); !reflect.DeepEqual(stats.Players, want) {
+		t.Errorf("expected the player stats in Stats(), got %+v", stats.Players)
+	}
+}
@@ -20,14 +20,26 @@ const (
 	// DefaultNamespace is the default game namespace.
 	DefaultNamespace = "podsweeper-game"
 
+	// DefaultConflictRetries is how many times Save merges and retries when
+	// the Secret was modified concurrently.
+	DefaultConflictRetries = 3
+
 	// StateKey is the key in the Secret data map for the game state JSON.
 	StateKey = "state"
+
+	// BoardKey is the key holding the human-readable board string in the
+	// cheat ConfigMap.
+	BoardKey = "board"
+
+	// CheatConfigMapName is the name of the Level 0 cheat ConfigMap.
+	CheatConfigMapName = "map"
 )
 
 // Store defines the interface for persisting game state.
 type Store interface {
 	// Load retrieves the current game state.
-	// Returns nil, nil if no game state exists.
+	// Returns nil, nil if no game state exists, and an error wrapping
+	// ErrCorruptState if the stored state fails validation.
 	Load(ctx context.Context) (*GameState, error)
 
 	// Save persists the game state.
@@ -44,9 +56,10 @@ type Store interface {
 
 // SecretStore persists game state in a Kubernetes Secret.
 type SecretStore struct {
-	client    client.Client
-	namespace string
-	name      string
+	client          client.Client
+	namespace       string
+	name            string
+	conflictRetries int
 }
 
 // SecretStoreOption configures a SecretStore.
@@ -66,12 +79,21 @@ func WithSecretName(name string) SecretStoreOption {
 	}
 }
 
+// WithConflictRetries sets how many times Save merges with a concurrently
+// saved state and retries. Zero fails on the first conflict.
+func WithConflictRetries(n int) SecretStoreOption {
+	return func(s *SecretStore) {
+		s.conflictRetries = n
+	}
+}
+
 // NewSecretStore creates a new SecretStore.
 func NewSecretStore(c client.Client, opts ...SecretStoreOption) *SecretStore {
 	store := &SecretStore{
-		client:    c,
-		namespace: DefaultNamespace,
-		name:      DefaultSecretName,
+		client:          c,
+		namespace:       DefaultNamespace,
+		name:            DefaultSecretName,
+		conflictRetries: DefaultConflictRetries,
 	}
 
 	for _, opt := range opts {
@@ -106,10 +128,18 @@ func (s *SecretStore) Load(ctx context.Context) (*GameState, error) {
 		return nil, fmt.Errorf("failed to parse game state: %w", err)
 	}
 
+	// Refuse to play a corrupted state
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+
 	return state, nil
 }
 
 // Save persists the game state to the Secret.
+// If the Secret was updated concurrently, the stored state is merged with
+// the given one (see GameState.Merge) and the update retried, so that
+// simultaneous reveals are not lost. The given state is not modified.
 func (s *SecretStore) Save(ctx context.Context, state *GameState) error {
 	data, err := state.ToJSON()
 	if err != nil {
@@ -149,8 +179,18 @@ func (s *SecretStore) Save(ctx context.Context, state *GameState) error {
 	}
 
 	// Update existing secret
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
 	secret.Data[StateKey] = data
-	if err := s.client.Update(ctx, secret); err != nil {
+	err = s.client.Update(ctx, secret)
+	for attempt := 0; errors.IsConflict(err) && attempt < s.conflictRetries; attempt++ {
+		if mergeErr := s.mergeLatest(ctx, key, secret, state); mergeErr != nil {
+			return mergeErr
+		}
+		err = s.client.Update(ctx, secret)
+	}
+	if err != nil {
 		if errors.IsConflict(err) {
 			return fmt.Errorf("conflict updating secret (concurrent modification): %w", err)
 		}
@@ -160,6 +200,35 @@ func (s *SecretStore) Save(ctx context.Context, state *GameState) error {
 	return nil
 }
 
+// mergeLatest refreshes secret with the latest stored version and sets its
+// data to the merge of the stored state and the state being saved.
+func (s *SecretStore) mergeLatest(ctx context.Context, key client.ObjectKey, secret *corev1.Secret, state *GameState) error {
+	if err := s.client.Get(ctx, key, secret); err != nil {
+		return fmt.Errorf("failed to get secret after conflict: %w", err)
+	}
+
+	merged := state
+	if stored, ok := secret.Data[StateKey]; ok {
+		latest, err := FromJSON(stored)
+		if err != nil {
+			return fmt.Errorf("failed to parse concurrently saved game state: %w", err)
+		}
+		if merged, err = state.Merge(latest); err != nil {
+			return fmt.Errorf("conflict updating secret (concurrent modification): %w", err)
+		}
+	}
+
+	data, err := merged.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize game state: %w", err)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[StateKey] = data
+	return nil
+}
+
 // Delete removes the game state Secret.
 func (s *SecretStore) Delete(ctx context.Context) error {
 	secret := &corev1.Secret{
@@ -227,6 +296,10 @@ func (m *MemoryStore) Load(ctx context.Context) (*GameState, error) {
 		return nil, nil
 	}
 
+	if err := m.state.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Return a clone to prevent external modification
 	return m.state.Clone(), nil
 }
@@ -275,6 +348,19 @@ func EncodeForConfigMap(state *GameState) (string, error) {
 	return string(data), nil
 }
 
+// ConfigMapData returns the data of the Level 0 cheat ConfigMap: the full
+// state JSON and the board as a readable string.
+func ConfigMapData(state *GameState) (map[string]string, error) {
+	encoded, err := EncodeForConfigMap(state)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		StateKey: encoded,
+		BoardKey: state.ToBoardString(),
+	}, nil
+}
+
 // EncodeForSecret encodes the game state for storage in a Secret.
 // The data is base64-encoded (standard Secret behavior).
 func EncodeForSecret(state *GameState) (string, error) {
@@ -4,6 +4,12 @@ import (
 	"context"
 	"sync"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestMemoryStore_LoadEmpty(t *testing.T) {
@@ -282,6 +288,7 @@ func TestStoreInterface(t *testing.T) {
 	// Verify MemoryStore implements Store interface
 	var _ Store = (*MemoryStore)(nil)
 	var _ Store = (*SecretStore)(nil)
+	var _ Store = (*ConfigMapStore)(nil)
 }
 
 func TestSecretStoreOptions(t *testing.T) {
@@ -323,3 +330,172 @@ func TestConstants(t *testing.T) {
 		t.Errorf("unexpected state key: %s", StateKey)
 	}
 }
+
+func TestSecretStore_LoadRejectsCorruptState(t *testing.T) {
+	ctx := context.Background()
+
+	state := NewGameState(3, 0)
+	state.SetMine(1, 1)
+	state.MineCount = 7
+	data, err := state.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultSecretName, Namespace: DefaultNamespace},
+		Data:       map[string][]byte{StateKey: data},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	store := NewSecretStore(c)
+
+	loaded, err := store.Load(ctx)
+	if !IsCorruptState(err) {
+		t.Fatalf("expected corrupt state error, got %v", err)
+	}
+	if loaded != nil {
+		t.Error("expected no state to be returned for a corrupted Secret")
+	}
+
+	// A sound state loads normally
+	state.MineCount = 1
+	if err := store.Save(ctx, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := store.Load(ctx); err != nil {
+		t.Errorf("expected valid state to load, got %v", err)
+	}
+}
+
+func TestConfigMapData(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.SetMine(1, 1)
+
+	data, err := ConfigMapData(state)
+	if err != nil {
+		t.Fatalf("ConfigMapData failed: %v", err)
+	}
+	if data[BoardKey] != "...\n.*.\n..." {
+		t.Errorf("unexpected board: %q", data[BoardKey])
+	}
+	if _, err := FromJSON([]byte(data[StateKey])); err != nil {
+		t.Errorf("state key does not hold valid JSON: %v", err)
+	}
+}
+
+func TestSecretStore_SaveMergesConcurrentReveals(t *testing.T) {
+	ctx := context.Background()
+
+	base := NewGameState(3, 0)
+	base.SetMine(2, 2)
+	c := fake.NewClientBuilder().Build()
+	store := NewSecretStore(c)
+	if err := store.Save(ctx, base); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Another reconcile saves (0,0) while ours reveals (1,1)
+	concurrent := base.Clone()
+	concurrent.Reveal(0, 0)
+	racing := true
+	racy := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if racing {
+				racing = false
+				if err := store.Save(ctx, concurrent); err != nil {
+					return err
+				}
+			}
+			return cl.Update(ctx, obj, opts...)
+		},
+	})
+
+	ours := base.Clone()
+	ours.Reveal(1, 1)
+	if err := NewSecretStore(racy).Save(ctx, ours); err != nil {
+		t.Fatalf("Save with conflict failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.IsRevealed(0, 0) || !loaded.IsRevealed(1, 1) {
+		t.Error("expected both concurrent reveals to be kept")
+	}
+	if ours.IsRevealed(0, 0) {
+		t.Error("Save must not modify the given state")
+	}
+}
+
+func TestSecretStore_SaveConflictWithoutRetries(t *testing.T) {
+	ctx := context.Background()
+
+	base := NewGameState(3, 0)
+	c := fake.NewClientBuilder().Build()
+	store := NewSecretStore(c)
+	if err := store.Save(ctx, base); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	racing := true
+	racy := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if racing {
+				racing = false
+				if err := store.Save(ctx, base); err != nil {
+					return err
+				}
+			}
+			return cl.Update(ctx, obj, opts...)
+		},
+	})
+
+	if err := NewSecretStore(racy, WithConflictRetries(0)).Save(ctx, base); err == nil {
+		t.Error("expected conflict error when retries are disabled")
+	}
+}
+
+func TestSecretStore_SaveShiftAgainstConcurrentFlag(t *testing.T) {
+	ctx := context.Background()
+
+	base := NewGameState(3, 0)
+	base.SetMine(2, 2)
+	c := fake.NewClientBuilder().Build()
+	store := NewSecretStore(c)
+	if err := store.Save(ctx, base); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The flag writer saves a flag while a reconcile shifts the mine
+	flagged := base.Clone()
+	flagged.Cells[2][2].Flagged = true
+	racing := true
+	racy := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if racing {
+				racing = false
+				if err := store.Save(ctx, flagged); err != nil {
+					return err
+				}
+			}
+			return cl.Update(ctx, obj, opts...)
+		},
+	})
+
+	shifted := base.Clone()
+	shifted.ClearMine(2, 2)
+	shifted.SetMine(0, 2)
+	shifted.Shifts++
+	if err := NewSecretStore(racy).Save(ctx, shifted); err != nil {
+		t.Fatalf("Save of a shift against a concurrent flag failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Shifts != 1 || !loaded.IsMine(0, 2) || loaded.IsMine(2, 2) || !loaded.Cells[2][2].Flagged {
+		t.Error("expected the shifted layout with the concurrent flag")
+	}
+}
@@ -0,0 +1,222 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultTeamLives is the shared number of lives of a team.
+const DefaultTeamLives = 3
+
+// Team is a group of players sharing the board and a pool of lives.
+type Team struct {
+	// Name identifies the team.
+	Name string `json:"name"`
+
+	// Members are the usernames playing for the team.
+	Members []string `json:"members"`
+
+	// Lives is the number of mines the team can still hit. A team with no
+	// lives left is eliminated.
+	Lives int `json:"lives"`
+
+	// MinesHit counts the mines hit by team members.
+	MinesHit int `json:"minesHit,omitempty"`
+}
+
+// Eliminated reports whether the team has no lives left.
+func (t Team) Eliminated() bool {
+	return t.Lives <= 0
+}
+
+// TeamStats summarizes the contribution of a team.
+type TeamStats struct {
+	Name          string   `json:"name"`
+	Members       []string `json:"members"`
+	Lives         int      `json:"lives"`
+	MinesHit      int      `json:"minesHit"`
+	CellsRevealed int      `json:"cellsRevealed"`
+	Eliminated    bool     `json:"eliminated"`
+}
+
+// AddTeam registers a team with the given shared lives and members.
+// A player can only belong to one team.
+func (g *GameState) AddTeam(name string, lives int, members ...string) error {
+	if name == "" {
+		return fmt.Errorf("team name is required")
+	}
+	if lives < 1 {
+		return fmt.Errorf("team %q must have at least one life, got %d", name, lives)
+	}
+	for _, t := range g.Teams {
+		if t.Name == name {
+			return fmt.Errorf("team %q already exists", name)
+		}
+	}
+	for _, m := range members {
+		if t := g.TeamOf(m); t != nil {
+			return fmt.Errorf("player %q already belongs to team %q", m, t.Name)
+		}
+	}
+
+	g.Teams = append(g.Teams, Team{Name: name, Members: append([]string(nil), members...), Lives: lives})
+	return nil
+}
+
+// TeamOf returns the team of a player, or nil if the player has no team.
+// The returned team can be modified in place.
+func (g *GameState) TeamOf(username string) *Team {
+	if username == "" {
+		return nil
+	}
+	for i := range g.Teams {
+		for _, m := range g.Teams[i].Members {
+			if m == username {
+				return &g.Teams[i]
+			}
+		}
+	}
+	return nil
+}
+
+// IsDefused checks if the cell at (x, y) is a mine hit that cost a life.
+// Returns false if the coordinate is out of bounds.
+func (g *GameState) IsDefused(x, y int) bool {
+	if !g.IsValidCoordinate(x, y) {
+		return false
+	}
+	return g.Cells[x][y].Defused
+}
+
+// HitMine resolves a player hitting the mine at (x, y) and reports whether
+// the game is over. A team member with lives left costs the team a life and
+// the mine is marked defused; play continues while any team has lives
+// left. Members of already eliminated teams have nothing left to lose and
+// just defuse the mine. Players without a team lose a life of the game in
+// multi-life mode, the mine being marked defused as well, and lose the game
+// with the last one, or outright without lives. Any mine hit ends the safe
+// streak earning power-up charges.
+func (g *GameState) HitMine(x, y int, username string) (gameOver bool) {
+	if !g.IsMine(x, y) || g.IsRevealed(x, y) || g.IsDefused(x, y) {
+		return g.Status != StatusPlaying
+	}
+	g.PowerUps.Streak = 0
+
+	team := g.TeamOf(username)
+	if team == nil {
+		if g.Lives > 1 {
+			g.Lives--
+			g.defuse(x, y, username, time.Now())
+			return false
+		}
+		g.Lives = 0
+		g.RevealBy(x, y, username)
+		g.SetLost()
+		return true
+	}
+
+	g.defuse(x, y, username, time.Now())
+	if !team.Eliminated() {
+		team.Lives--
+		team.MinesHit++
+	}
+
+	for _, t := range g.Teams {
+		if !t.Eliminated() {
+			return false
+		}
+	}
+	g.SetLost()
+	return true
+}
+
+// defuse marks the mine at (x, y) hit by username at without ending the
+// game, and records the move.
+func (g *GameState) defuse(x, y int, username string, at time.Time) {
+	cell := &g.Cells[x][y]
+	cell.Defused = true
+	cell.Flagged = false
+	cell.PlayerFlagged = false
+	cell.Question = false
+	cell.RevealedBy = username
+	g.Clicks++
+	g.record(x, y, MoveDefused, username, at)
+}
+
+// TeamStats returns the stats of every team, ranked: teams still in play
+// first, then by cells revealed, then by fewest mines hit.
+func (g *GameState) TeamStats() []TeamStats {
+	contributions := g.Contributions()
+
+	stats := make([]TeamStats, len(g.Teams))
+	for i, t := range g.Teams {
+		stats[i] = TeamStats{
+			Name:       t.Name,
+			Members:    append([]string(nil), t.Members...),
+			Lives:      t.Lives,
+			MinesHit:   t.MinesHit,
+			Eliminated: t.Eliminated(),
+		}
+		for _, m := range t.Members {
+			stats[i].CellsRevealed += contributions[m]
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if a.CellsRevealed != b.CellsRevealed {
+			return a.CellsRevealed > b.CellsRevealed
+		}
+		if a.MinesHit != b.MinesHit {
+			return a.MinesHit < b.MinesHit
+		}
+		return a.Name < b.Name
+	})
+	return stats
+}
+
+// WinningTeam returns the best ranked team, if the game has teams.
+func (g *GameState) WinningTeam() (TeamStats, bool) {
+	stats := g.TeamStats()
+	if len(stats) == 0 {
+		return TeamStats{}, false
+	}
+	return stats[0], true
+}
+
+// cloneTeams deep copies teams.
+func cloneTeams(teams []Team) []Team {
+	if teams == nil {
+		return nil
+	}
+	clone := make([]Team, len(teams))
+	for i, t := range teams {
+		clone[i] = t
+		clone[i].Members = append([]string(nil), t.Members...)
+	}
+	return clone
+}
+
+// mergeTeams combines two versions of the same teams, keeping the most
+// lives lost. Teams only known to one side are kept.
+func mergeTeams(a, b []Team) []Team {
+	merged := cloneTeams(a)
+	for _, bt := range b {
+		found := false
+		for i := range merged {
+			if merged[i].Name == bt.Name {
+				found = true
+				merged[i].Lives = min(merged[i].Lives, bt.Lives)
+				merged[i].MinesHit = max(merged[i].MinesHit, bt.MinesHit)
+			}
+		}
+		if !found {
+			merged = append(merged, cloneTeams([]Team{bt})...)
+		}
+	}
+	return merged
+}
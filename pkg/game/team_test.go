@@ -0,0 +1,162 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTeamTestState(t *testing.T) *GameState {
+	t.Helper()
+	state := NewGameState(4, 0)
+	state.SetMine(0, 0)
+	state.SetMine(3, 3)
+	state.SetMine(0, 3)
+	if err := state.AddTeam("red", 1, "alice", "bob"); err != nil {
+		t.Fatalf("AddTeam failed: %v", err)
+	}
+	if err := state.AddTeam("blue", 2, "carol"); err != nil {
+		t.Fatalf("AddTeam failed: %v", err)
+	}
+	return state
+}
+
+func TestAddTeamErrors(t *testing.T) {
+	state := newTeamTestState(t)
+
+	tests := []struct {
+		name    string
+		team    string
+		lives   int
+		members []string
+		err     string
+	}{
+		{"empty name", "", 1, nil, "required"},
+		{"no lives", "green", 0, nil, "at least one life"},
+		{"duplicate team", "red", 1, nil, "already exists"},
+		{"member in two teams", "green", 1, []string{"bob"}, "already belongs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := state.AddTeam(tt.team, tt.lives, tt.members...)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestHitMineSharedLives(t *testing.T) {
+	state := newTeamTestState(t)
+
+	// Red's only life: red is eliminated but blue keeps playing
+	if state.HitMine(0, 0, "bob") {
+		t.Fatal("game should continue while blue has lives")
+	}
+	if !state.IsDefused(0, 0) || state.IsRevealed(0, 0) {
+		t.Error("expected (0,0) to be defused, not revealed")
+	}
+	if red := state.TeamOf("alice"); !red.Eliminated() || red.MinesHit != 1 {
+		t.Errorf("expected red to be eliminated, got %+v", red)
+	}
+	if err := state.Validate(); err != nil {
+		t.Errorf("defused mine should keep the state valid: %v", err)
+	}
+
+	// Hitting a defused mine again does nothing
+	if state.HitMine(0, 0, "carol") {
+		t.Error("defused mine should not end the game")
+	}
+	if state.TeamOf("carol").Lives != 2 {
+		t.Error("defused mine should not cost another life")
+	}
+
+	if state.HitMine(3, 3, "carol") {
+		t.Fatal("blue still had a life left")
+	}
+	if !state.HitMine(0, 3, "carol") {
+		t.Fatal("game should be over once every team is eliminated")
+	}
+	if state.Status != StatusLost {
+		t.Errorf("expected lost status, got %s", state.Status)
+	}
+}
+
+func TestHitMineWithoutTeam(t *testing.T) {
+	state := newTeamTestState(t)
+
+	if !state.HitMine(0, 0, "mallory") {
+		t.Fatal("players without a team lose on the first mine")
+	}
+	if !state.IsRevealed(0, 0) || state.Status != StatusLost {
+		t.Error("expected the mine to be revealed and the game lost")
+	}
+}
+
+func TestHitMineLives(t *testing.T) {
+	state := newTeamTestState(t)
+	state.Lives = 2
+
+	if state.HitMine(0, 0, "mallory") {
+		t.Fatal("expected a life lost, not the game")
+	}
+	if !state.IsDefused(0, 0) || state.IsRevealed(0, 0) || state.Lives != 1 {
+		t.Errorf("expected (0,0) defused and a life left, got %d", state.Lives)
+	}
+	if stats := state.Stats(); stats.RemainingMines != 2 || !strings.Contains(stats.String(), "lives left: 1") {
+		t.Errorf("unexpected stats: %s", stats)
+	}
+
+	if !state.HitMine(3, 3, "mallory") {
+		t.Fatal("expected the game lost with the last life")
+	}
+	if !state.IsRevealed(3, 3) || state.Status != StatusLost || state.Lives != 0 {
+		t.Errorf("expected the mine revealed and the game lost, got %s", state.Status)
+	}
+}
+
+func TestTeamStatsRanking(t *testing.T) {
+	state := newTeamTestState(t)
+	state.RevealBy(1, 0, "alice")
+	state.RevealBy(2, 0, "bob")
+	state.RevealBy(1, 1, "carol")
+
+	stats := state.TeamStats()
+	if stats[0].Name != "red" || stats[0].CellsRevealed != 2 {
+		t.Errorf("expected red to lead with 2 cells, got %+v", stats[0])
+	}
+
+	// An eliminated team drops below teams still in play
+	state.HitMine(0, 0, "alice")
+	winner, ok := state.WinningTeam()
+	if !ok || winner.Name != "blue" {
+		t.Errorf("expected blue to win, got %+v", winner)
+	}
+
+	if _, ok := NewGameState(3, 0).WinningTeam(); ok {
+		t.Error("games without teams have no winning team")
+	}
+}
+
+func TestTeamsSurviveCloneAndMerge(t *testing.T) {
+	state := newTeamTestState(t)
+
+	clone := state.Clone()
+	clone.TeamOf("alice").Members[0] = "eve"
+	if state.TeamOf("alice") == nil {
+		t.Fatal("Clone must deep copy team members")
+	}
+
+	other := state.Clone()
+	other.HitMine(3, 3, "carol")
+	merged, err := state.Merge(other)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if merged.TeamOf("carol").Lives != 1 || !merged.IsDefused(3, 3) {
+		t.Error("expected the concurrent life loss to be merged")
+	}
+	if got := merged.Stats().RemainingMines; got != 2 {
+		t.Errorf("expected defused mines to count as found, got %d remaining", got)
+	}
+}
@@ -0,0 +1,30 @@
+package game
+
+import "time"
+
+// RemainingAt returns the play time left as of now in timed mode, and false
+// for games without a time limit. Time spent paused doesn't count, see
+// ElapsedAt.
+func (g *GameState) RemainingAt(now time.Time) (time.Duration, bool) {
+	if g.TimeLimit <= 0 {
+		return 0, false
+	}
+	return max(g.TimeLimit-g.ElapsedAt(now), 0), true
+}
+
+// TimeUp reports whether the game in progress ran out of time at now.
+func (g *GameState) TimeUp(now time.Time) bool {
+	remaining, ok := g.RemainingAt(now)
+	return ok && remaining == 0 && g.Status == StatusPlaying
+}
+
+// TimeOut ends the game as lost by running out of time. Returns false if
+// the game has already ended.
+func (g *GameState) TimeOut(now time.Time) bool {
+	if g.Status != StatusPlaying {
+		return false
+	}
+	g.TimedOut = true
+	g.end(StatusLost, now)
+	return true
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeLimit(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := NewGameState(3, 1)
+	state.StartedAt = start
+
+	if _, ok := state.RemainingAt(start); ok || state.TimeUp(start.Add(time.Hour)) {
+		t.Error("games without a time limit never run out of time")
+	}
+
+	state.TimeLimit = 5 * time.Minute
+	if remaining, ok := state.RemainingAt(start.Add(2 * time.Minute)); !ok || remaining != 3*time.Minute {
+		t.Errorf("expected 3m left, got %v", remaining)
+	}
+
+	// Time spent paused doesn't count
+	state.PauseAt(start.Add(4 * time.Minute))
+	state.ResumeAt(start.Add(6 * time.Minute))
+	if state.TimeUp(start.Add(6 * time.Minute)) {
+		t.Error("expected the pause not to count")
+	}
+	if !state.TimeUp(start.Add(7 * time.Minute)) {
+		t.Fatal("expected the time to be up")
+	}
+
+	if !state.TimeOut(start.Add(7*time.Minute)) || state.Status != StatusLost || !state.TimedOut {
+		t.Fatalf("expected the game lost by time out, got %s", state.Status)
+	}
+	if state.TimeOut(start.Add(8 * time.Minute)) {
+		t.Error("expected an ended game not to time out again")
+	}
+	if stats := state.Stats(); !stats.TimedOut || !strings.Contains(stats.String(), "lost (timed out)") {
+		t.Errorf("expected the stats to tell the time out, got %q", stats)
+	}
+	if clone := state.Clone(); !clone.TimedOut || clone.TimeLimit != state.TimeLimit {
+		t.Error("expected Clone to copy the time limit")
+	}
+}
@@ -0,0 +1,153 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCorruptState is wrapped by validation errors so callers can detect
+// corrupted states with errors.Is.
+var ErrCorruptState = errors.New("corrupt game state")
+
+// IsCorruptState reports whether err is caused by a state failing validation.
+func IsCorruptState(err error) bool {
+	return errors.Is(err, ErrCorruptState)
+}
+
+// ValidationCode identifies the kind of inconsistency found by Validate.
+type ValidationCode string
+
+const (
+	// CodeInvalidDimensions reports negative or inconsistent grid sizes.
+	CodeInvalidDimensions ValidationCode = "InvalidDimensions"
+	// CodeGridMismatch reports a cell grid not matching Width x Height.
+	CodeGridMismatch ValidationCode = "GridMismatch"
+	// CodeMineCountMismatch reports a MineCount not matching the grid.
+	CodeMineCountMismatch ValidationCode = "MineCountMismatch"
+	// CodeInvalidStatus reports an unknown game status.
+	CodeInvalidStatus ValidationCode = "InvalidStatus"
+	// CodeInvalidPhase reports an unknown game phase, or one contradicting
+	// the status.
+	CodeInvalidPhase ValidationCode = "InvalidPhase"
+	// CodeRevealedMine reports a revealed mine in a game still playing.
+	CodeRevealedMine ValidationCode = "RevealedMine"
+	// CodeOutOfBounds reports a hint cell or opening outside the grid.
+	CodeOutOfBounds ValidationCode = "OutOfBounds"
+)
+
+// ValidationFinding describes a single inconsistency.
+type ValidationFinding struct {
+	Code    ValidationCode `json:"code"`
+	Message string         `json:"message"`
+
+	// Cell is the offending cell, if the finding is about one.
+	Cell *Coordinate `json:"cell,omitempty"`
+}
+
+// String returns a human-readable representation of the finding.
+func (f ValidationFinding) String() string {
+	if f.Cell != nil {
+		return fmt.Sprintf("%s at %s: %s", f.Code, f.Cell, f.Message)
+	}
+	return fmt.Sprintf("%s: %s", f.Code, f.Message)
+}
+
+// ValidationError lists every inconsistency found in a state.
+type ValidationError struct {
+	Findings []ValidationFinding
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		msgs[i] = f.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrCorruptState, strings.Join(msgs, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrCorruptState).
+func (e *ValidationError) Unwrap() error {
+	return ErrCorruptState
+}
+
+// Validate checks the state for corruption or inconsistencies and returns
+// a *ValidationError listing all findings, or nil if the state is sound.
+func (g *GameState) Validate() error {
+	var findings []ValidationFinding
+	add := func(code ValidationCode, cell *Coordinate, format string, args ...interface{}) {
+		findings = append(findings, ValidationFinding{Code: code, Message: fmt.Sprintf(format, args...), Cell: cell})
+	}
+
+	w, h := g.Dimensions()
+	if w < 0 || h < 0 {
+		add(CodeInvalidDimensions, nil, "grid is %dx%d", w, h)
+		return &ValidationError{Findings: findings}
+	}
+	if g.Size != 0 && (g.Size != w || g.Size != h) {
+		add(CodeInvalidDimensions, nil, "size %d does not match %dx%d grid", g.Size, w, h)
+	}
+
+	// The cell grid must match the dimensions before cells can be inspected
+	if len(g.Cells) != w {
+		add(CodeGridMismatch, nil, "grid has %d columns, expected %d", len(g.Cells), w)
+		return &ValidationError{Findings: findings}
+	}
+	for x := range g.Cells {
+		if len(g.Cells[x]) != h {
+			add(CodeGridMismatch, nil, "column %d has %d cells, expected %d", x, len(g.Cells[x]), h)
+		}
+	}
+	if len(findings) > 0 {
+		return &ValidationError{Findings: findings}
+	}
+
+	switch g.Status {
+	case StatusPlaying, StatusWon, StatusLost:
+	default:
+		add(CodeInvalidStatus, nil, "unknown status %q", g.Status)
+	}
+	if g.Phase != "" {
+		ended := g.Phase == PhaseFinished || g.Phase == PhaseCleaning
+		switch {
+		case !g.Phase.IsValid():
+			add(CodeInvalidPhase, nil, "unknown phase %q", g.Phase)
+		case ended != (g.Status != StatusPlaying):
+			add(CodeInvalidPhase, nil, "phase %s contradicts status %s", g.Phase, g.Status)
+		}
+	}
+
+	mines := 0
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			cell := g.Cells[x][y]
+			if !cell.Mine {
+				continue
+			}
+			mines++
+			if cell.Revealed && g.Status == StatusPlaying {
+				add(CodeRevealedMine, &Coordinate{X: x, Y: y}, "mine revealed while game is still playing")
+			}
+		}
+	}
+	if mines != g.MineCount {
+		add(CodeMineCountMismatch, nil, "mineCount is %d but grid has %d mines", g.MineCount, mines)
+	}
+
+	for _, c := range g.HintCells {
+		if !g.IsValidCoordinate(c.X, c.Y) {
+			c := c
+			add(CodeOutOfBounds, &c, "hint cell outside the %dx%d grid", w, h)
+		}
+	}
+	if g.Opening != nil && !g.IsValidCoordinate(g.Opening.X, g.Opening.Y) {
+		opening := *g.Opening
+		add(CodeOutOfBounds, &opening, "opening outside the %dx%d grid", w, h)
+	}
+
+	if len(findings) > 0 {
+		return &ValidationError{Findings: findings}
+	}
+	return nil
+}
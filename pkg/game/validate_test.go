@@ -0,0 +1,80 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*GameState)
+		codes   []ValidationCode
+	}{
+		{"valid", func(*GameState) {}, nil},
+		{"valid lost game with revealed mine", func(g *GameState) {
+			g.Reveal(0, 0)
+			g.SetLost()
+		}, nil},
+		{"mine count mismatch", func(g *GameState) { g.MineCount = 5 }, []ValidationCode{CodeMineCountMismatch}},
+		{"revealed mine while playing", func(g *GameState) { g.Reveal(0, 0) }, []ValidationCode{CodeRevealedMine}},
+		{"missing column", func(g *GameState) { g.Cells = g.Cells[:2] }, []ValidationCode{CodeGridMismatch}},
+		{"short column", func(g *GameState) { g.Cells[1] = g.Cells[1][:1] }, []ValidationCode{CodeGridMismatch}},
+		{"size mismatch", func(g *GameState) { g.Size = 4 }, []ValidationCode{CodeInvalidDimensions}},
+		{"unknown status", func(g *GameState) { g.Status = "paused?" }, []ValidationCode{CodeInvalidStatus}},
+		{"hint out of bounds", func(g *GameState) { g.AddHintCell(3, 0) }, []ValidationCode{CodeOutOfBounds}},
+		{"opening out of bounds", func(g *GameState) { g.Opening = &Coordinate{X: -1, Y: 0} }, []ValidationCode{CodeOutOfBounds}},
+		{"unknown phase", func(g *GameState) { g.Phase = "Paused" }, []ValidationCode{CodeInvalidPhase}},
+		{"finished phase while playing", func(g *GameState) { g.Phase = PhaseFinished }, []ValidationCode{CodeInvalidPhase}},
+		{"several findings", func(g *GameState) {
+			g.MineCount = 0
+			g.AddHintCell(9, 9)
+		}, []ValidationCode{CodeMineCountMismatch, CodeOutOfBounds}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewGameState(3, 0)
+			state.SetMine(0, 0)
+			tt.corrupt(state)
+
+			err := state.Validate()
+			if tt.codes == nil {
+				if err != nil {
+					t.Fatalf("expected valid state, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrCorruptState) {
+				t.Fatalf("expected ErrCorruptState, got %v", err)
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ValidationError, got %T", err)
+			}
+			if len(verr.Findings) != len(tt.codes) {
+				t.Fatalf("expected %d findings, got %v", len(tt.codes), verr.Findings)
+			}
+			for i, code := range tt.codes {
+				if verr.Findings[i].Code != code {
+					t.Errorf("finding %d: expected %s, got %s", i, code, verr.Findings[i].Code)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRevealedMineReportsCell(t *testing.T) {
+	state := NewGameState(3, 0)
+	state.SetMine(2, 1)
+	state.Reveal(2, 1)
+
+	var verr *ValidationError
+	if !errors.As(state.Validate(), &verr) {
+		t.Fatal("expected a validation error")
+	}
+	if c := verr.Findings[0].Cell; c == nil || *c != (Coordinate{X: 2, Y: 1}) {
+		t.Errorf("expected finding at (2,1), got %v", c)
+	}
+}
@@ -0,0 +1,64 @@
+package gametest
+
+import (
+	"strings"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// AssertInvariants checks the game state is sound and, while the game is
+// played, that the board pods match it: a cell pod per hidden cell, a hint
+// pod per revealed cell next to mines, and nothing the state doesn't imply.
+func (g *Game) AssertInvariants() {
+	g.t.Helper()
+	state := g.State()
+	if err := state.Validate(); err != nil {
+		g.t.Errorf("invalid game state: %v", err)
+	}
+	if state.Status != game.StatusPlaying {
+		return
+	}
+	_, pods := g.pods()
+	drift := controller.DetectDrift(state, pods)
+	if len(drift.MissingCells) > 0 {
+		g.t.Errorf("hidden cells without a pod: %v", drift.MissingCells)
+	}
+	if len(drift.MissingHints) > 0 {
+		g.t.Errorf("revealed cells without a hint pod: %v", drift.MissingHints)
+	}
+	if len(drift.MissingDefused) > 0 {
+		g.t.Errorf("defused mines without a marker pod: %v", drift.MissingDefused)
+	}
+	if len(drift.Extra) > 0 {
+		g.t.Errorf("pods the game state doesn't imply: %v", drift.Extra)
+	}
+}
+
+// AssertStatus checks the game status.
+func (g *Game) AssertStatus(want game.GameStatus) {
+	g.t.Helper()
+	if got := g.State().Status; got != want {
+		g.t.Errorf("game status = %s, want %s", got, want)
+	}
+}
+
+// AssertBoard checks the board, written as by game.GameState.ToBoardString.
+// Leading and trailing blank space of the board and its rows is ignored,
+// so boards can be indented raw strings.
+func (g *Game) AssertBoard(want string) {
+	g.t.Helper()
+	got := g.State().ToBoardString()
+	if got != trimBoard(want) {
+		g.t.Errorf("board:\n%s\nwant:\n%s", got, trimBoard(want))
+	}
+}
+
+// trimBoard trims the blank space around a board and its rows.
+func trimBoard(board string) string {
+	rows := strings.Split(strings.TrimSpace(board), "\n")
+	for i, row := range rows {
+		rows[i] = strings.TrimSpace(row)
+	}
+	return strings.Join(rows, "\n")
+}
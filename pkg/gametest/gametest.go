@@ -0,0 +1,286 @@
+// Package gametest plays PodSweeper games in tests: it sets up a game with
+// its board pods and Gamemaster controller, plays moves the way players do
+// by deleting pods, and checks the board stays consistent. Level authors
+// and contributors use it to write behavioral tests without a cluster:
+//
+//	g := gametest.NewFromBoard(t, `
+//	..*
+//	...
+//	...`)
+//	g.Run(
+//		gametest.Click(0, 2),
+//		gametest.ExpectRevealed(0, 0),
+//		gametest.Click(2, 0),
+//		gametest.ExpectStatus(game.StatusLost),
+//	)
+//
+// Games run against a fake client by default; WithClient runs them against
+// another one, such as the client of an envtest API server.
+package gametest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/internal/controller"
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// eventBuffer is how many Events are kept until read with Events.
+const eventBuffer = 1000
+
+// Scheme returns a scheme with the kinds the Gamemaster manages.
+func Scheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = networkingv1.AddToScheme(scheme)
+	_ = coordinationv1.AddToScheme(scheme)
+	return scheme
+}
+
+// options configures a Game.
+type options struct {
+	namespace string
+	client    client.Client
+	objects   []client.Object
+	configure []func(*controller.GameControllerConfig)
+}
+
+// Option configures a Game.
+type Option func(*options)
+
+// WithNamespace plays the game in namespace instead of
+// game.DefaultNamespace.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithObjects adds objects to the fake client, such as protected pods.
+// Ignored with WithClient.
+func WithObjects(objects ...client.Object) Option {
+	return func(o *options) {
+		o.objects = append(o.objects, objects...)
+	}
+}
+
+// WithClient plays the game with c instead of a fake client, such as the
+// client of an envtest API server. The game namespace must exist.
+func WithClient(c client.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// WithConfig changes the configuration of the Gamemaster controller,
+// such as to enable NetworkPolicies or set protected pods.
+func WithConfig(configure func(*controller.GameControllerConfig)) Option {
+	return func(o *options) {
+		o.configure = append(o.configure, configure)
+	}
+}
+
+// Game is a game played in a test.
+type Game struct {
+	// Client holds the board pods.
+	Client client.Client
+
+	// Store holds the game state, in its Secret.
+	Store game.Store
+
+	// Controller is the Gamemaster controller playing the moves.
+	Controller *controller.GameController
+
+	// Recorder records the Events emitted to players.
+	Recorder *events.FakeRecorder
+
+	// Namespace is the game namespace.
+	Namespace string
+
+	t testing.TB
+}
+
+// New saves state and spawns its board pods, ready to be played.
+func New(t testing.TB, state *game.GameState, opts ...Option) *Game {
+	t.Helper()
+	o := options{namespace: game.DefaultNamespace}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := o.client
+	if c == nil {
+		c = fake.NewClientBuilder().WithScheme(Scheme()).WithObjects(o.objects...).Build()
+	}
+
+	g := &Game{
+		Client:    c,
+		Store:     game.NewSecretStore(c, game.WithNamespace(o.namespace)),
+		Recorder:  events.NewFakeRecorder(eventBuffer),
+		Namespace: o.namespace,
+		t:         t,
+	}
+	config := controller.GameControllerConfig{
+		Namespace: o.namespace,
+		Store:     g.Store,
+		Recorder:  g.Recorder,
+		Claims:    controller.NewMoveClaims(),
+	}
+	for _, configure := range o.configure {
+		configure(&config)
+	}
+	g.Controller = controller.NewGameController(c, config)
+
+	g.spawn(state)
+	return g
+}
+
+// spawn saves state and spawns its board as the workshop does, going
+// through the Spawning phase. Ended games are only saved.
+func (g *Game) spawn(state *game.GameState) {
+	g.t.Helper()
+	ctx := context.Background()
+	ready := state.CurrentPhase()
+	if ready == game.PhasePending {
+		ready = game.PhaseReady
+	}
+	if ready.Playable() {
+		if err := state.SetPhase(game.PhaseSpawning); err != nil {
+			g.t.Fatalf("failed to spawn board: %v", err)
+		}
+	}
+	if err := g.Store.Save(ctx, state); err != nil {
+		g.t.Fatalf("failed to save game state: %v", err)
+	}
+	if !ready.Playable() {
+		return
+	}
+	if err := g.Controller.Handlers.SpawnBoard(ctx, g.Controller.Spawner, state); err != nil {
+		g.t.Fatalf("failed to spawn board: %v", err)
+	}
+	if err := state.SetPhase(ready); err != nil {
+		g.t.Fatalf("failed to spawn board: %v", err)
+	}
+	if err := g.Store.Save(ctx, state); err != nil {
+		g.t.Fatalf("failed to save game state: %v", err)
+	}
+}
+
+// NewFromBoard starts the game of a board string, as written by
+// game.GameState.ToBoardString: '.' is a hidden cell, '*' a hidden mine,
+// digits revealed cells, and so on.
+func NewFromBoard(t testing.TB, board string, opts ...Option) *Game {
+	t.Helper()
+	state, err := game.FromBoardString(board)
+	if err != nil {
+		t.Fatalf("invalid board: %v", err)
+	}
+	return New(t, state, opts...)
+}
+
+// State returns the saved game state.
+func (g *Game) State() *game.GameState {
+	g.t.Helper()
+	state, err := g.Store.Load(context.Background())
+	if err != nil {
+		g.t.Fatalf("failed to load game state: %v", err)
+	}
+	if state == nil {
+		g.t.Fatal("no game state")
+	}
+	return state
+}
+
+// Click plays the cell at (x, y): its pod is deleted and the deletion
+// reconciled. The cell pod must exist.
+func (g *Game) Click(x, y int) ctrl.Result {
+	g.t.Helper()
+	return g.Delete(game.Coordinate{X: x, Y: y}.PodName())
+}
+
+// ClickAs plays the cell at (x, y) as player, as attributed by the
+// Kubernetes audit log or a webhook.
+func (g *Game) ClickAs(player string, x, y int) ctrl.Result {
+	g.t.Helper()
+	g.Controller.Claims.Claim(game.Coordinate{X: x, Y: y}, player)
+	return g.Click(x, y)
+}
+
+// Delete deletes the pod name and reconciles the deletion. The pod must
+// exist.
+func (g *Game) Delete(name string) ctrl.Result {
+	g.t.Helper()
+	pod := &corev1.Pod{}
+	pod.Name, pod.Namespace = name, g.Namespace
+	if err := g.Client.Delete(context.Background(), pod); errors.IsNotFound(err) {
+		g.t.Fatalf("no pod %s to delete", name)
+	} else if err != nil {
+		g.t.Fatalf("failed to delete pod %s: %v", name, err)
+	}
+	return g.Reconcile(name)
+}
+
+// Reconcile reconciles the pod name, existing or not.
+func (g *Game) Reconcile(name string) ctrl.Result {
+	g.t.Helper()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: g.Namespace, Name: name}}
+	result, err := g.Controller.Reconcile(context.Background(), req)
+	if err != nil {
+		g.t.Fatalf("failed to reconcile %s: %v", name, err)
+	}
+	return result
+}
+
+// Pods returns the names of the pods of the game namespace, sorted.
+func (g *Game) Pods() []string {
+	g.t.Helper()
+	names, _ := g.pods()
+	return names
+}
+
+// HasPod reports whether the pod name exists.
+func (g *Game) HasPod(name string) bool {
+	g.t.Helper()
+	return slices.Contains(g.Pods(), name)
+}
+
+// Events returns the Events emitted since the last call, as "type reason
+// message".
+func (g *Game) Events() []string {
+	var got []string
+	for {
+		select {
+		case e := <-g.Recorder.Events:
+			got = append(got, e)
+		default:
+			return got
+		}
+	}
+}
+
+// pods lists the pods of the game namespace.
+func (g *Game) pods() ([]string, []corev1.Pod) {
+	g.t.Helper()
+	list := &corev1.PodList{}
+	if err := g.Client.List(context.Background(), list, client.InNamespace(g.Namespace)); err != nil {
+		g.t.Fatalf("failed to list pods: %v", err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, pod := range list.Items {
+		names = append(names, pod.Name)
+	}
+	slices.Sort(names)
+	return names, list.Items
+}
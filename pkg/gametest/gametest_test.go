@@ -0,0 +1,99 @@
+package gametest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/gametest"
+)
+
+const board = `
+	.*..
+	....
+	...*`
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []gametest.Step
+	}{
+		{
+			name: "hint",
+			steps: []gametest.Step{
+				gametest.Click(0, 0),
+				gametest.ExpectBoard(`
+					1*..
+					....
+					...*`),
+				gametest.ExpectPod(game.Coordinate{X: 0, Y: 0}.HintPodName(), true),
+				gametest.ExpectStatus(game.StatusPlaying),
+			},
+		},
+		{
+			name: "propagation",
+			steps: []gametest.Step{
+				gametest.Click(3, 0),
+				gametest.ExpectRevealed(2, 1),
+				gametest.ExpectHidden(0, 0),
+				gametest.ExpectPod(game.Coordinate{X: 3, Y: 1}.PodName(), false),
+			},
+		},
+		{
+			name: "mine",
+			steps: []gametest.Step{
+				gametest.ClickAs("alice", 0, 0),
+				gametest.ClickAs("bob", 1, 0),
+				gametest.ExpectStatus(game.StatusLost),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gametest.NewFromBoard(t, board).Run(tt.steps...)
+		})
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	g := gametest.NewFromBoard(t, board, gametest.WithNamespace("arena"))
+	g.Click(0, 0)
+	if g.Namespace != "arena" || !g.State().IsRevealed(0, 0) {
+		t.Errorf("expected the game played in arena")
+	}
+}
+
+// recorder records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertInvariants(t *testing.T) {
+	r := &recorder{TB: t}
+	g := gametest.NewFromBoard(r, board)
+	g.AssertInvariants()
+	if len(r.errors) != 0 {
+		t.Fatalf("expected a new game to be consistent, got %v", r.errors)
+	}
+
+	// A cell pod deleted behind the Gamemaster's back
+	pod := &corev1.Pod{}
+	pod.Name, pod.Namespace = game.Coordinate{X: 2, Y: 2}.PodName(), g.Namespace
+	if err := g.Client.Delete(context.Background(), pod); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	g.AssertInvariants()
+	if len(r.errors) != 1 {
+		t.Errorf("expected the missing cell pod to be reported, got %v", r.errors)
+	}
+}
@@ -0,0 +1,105 @@
+package gametest
+
+import (
+	"fmt"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// Step is a step of a scenario.
+type Step struct {
+	// Name describes the step in failures.
+	Name string
+
+	// Do plays or checks the step.
+	Do func(g *Game)
+
+	// move is set on steps playing moves, after which the invariants are
+	// checked.
+	move bool
+}
+
+// Run plays the steps of a scenario in order, checking the invariants
+// after each move. It stops at the first failing step.
+func (g *Game) Run(steps ...Step) {
+	g.t.Helper()
+	for i, step := range steps {
+		step.Do(g)
+		if step.move {
+			g.AssertInvariants()
+		}
+		if g.t.Failed() {
+			g.t.Fatalf("scenario failed at step %d: %s", i+1, step.Name)
+		}
+	}
+}
+
+// Click plays the cell at (x, y).
+func Click(x, y int) Step {
+	return Step{Name: fmt.Sprintf("click (%d, %d)", x, y), move: true, Do: func(g *Game) {
+		g.t.Helper()
+		g.Click(x, y)
+	}}
+}
+
+// ClickAs plays the cell at (x, y) as player.
+func ClickAs(player string, x, y int) Step {
+	return Step{Name: fmt.Sprintf("%s clicks (%d, %d)", player, x, y), move: true, Do: func(g *Game) {
+		g.t.Helper()
+		g.ClickAs(player, x, y)
+	}}
+}
+
+// Delete deletes the pod name.
+func Delete(name string) Step {
+	return Step{Name: "delete " + name, move: true, Do: func(g *Game) {
+		g.t.Helper()
+		g.Delete(name)
+	}}
+}
+
+// ExpectStatus checks the game status.
+func ExpectStatus(status game.GameStatus) Step {
+	return Step{Name: "expect " + string(status), Do: func(g *Game) {
+		g.t.Helper()
+		g.AssertStatus(status)
+	}}
+}
+
+// ExpectRevealed checks the cell at (x, y) is revealed.
+func ExpectRevealed(x, y int) Step {
+	return Step{Name: fmt.Sprintf("expect (%d, %d) revealed", x, y), Do: func(g *Game) {
+		g.t.Helper()
+		if !g.State().IsRevealed(x, y) {
+			g.t.Errorf("cell (%d, %d) is hidden", x, y)
+		}
+	}}
+}
+
+// ExpectHidden checks the cell at (x, y) is hidden.
+func ExpectHidden(x, y int) Step {
+	return Step{Name: fmt.Sprintf("expect (%d, %d) hidden", x, y), Do: func(g *Game) {
+		g.t.Helper()
+		if g.State().IsRevealed(x, y) {
+			g.t.Errorf("cell (%d, %d) is revealed", x, y)
+		}
+	}}
+}
+
+// ExpectBoard checks the board, see Game.AssertBoard.
+func ExpectBoard(board string) Step {
+	return Step{Name: "expect board", Do: func(g *Game) {
+		g.t.Helper()
+		g.AssertBoard(board)
+	}}
+}
+
+// ExpectPod checks whether the pod name exists.
+func ExpectPod(name string, exists bool) Step {
+	return Step{Name: fmt.Sprintf("expect pod %s exists: %v", name, exists), Do: func(g *Game) {
+		g.t.Helper()
+		if got := g.HasPod(name); got != exists {
+			g.t.Errorf("pod %s exists: %v, want %v", name, got, exists)
+		}
+	}}
+}
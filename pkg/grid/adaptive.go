@@ -0,0 +1,113 @@
+package grid
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// AdaptiveWindow is the number of recent games a handicap is computed
+	// from.
+	AdaptiveWindow = 5
+
+	// ParPerCell is the expected time to reveal a safe cell. Wins faster
+	// than par make the next board harder, slower ones easier.
+	ParPerCell = 2 * time.Second
+
+	// maxDensityAdjust is how much the mine density changes at the
+	// strongest handicap, relative to the preset.
+	maxDensityAdjust = 0.35
+
+	// maxSizeAdjust is how much the width and height change at the
+	// strongest handicap, relative to the preset.
+	maxSizeAdjust = 0.25
+)
+
+// RecentGame is a finished game of a player, as far as the handicap is
+// concerned.
+type RecentGame struct {
+	Won bool
+
+	// Progress is the percentage of safe cells revealed.
+	Progress float64
+
+	// Elapsed is the play time.
+	Elapsed time.Duration
+
+	// SafeCells is the number of safe cells of the board.
+	SafeCells int
+}
+
+// Handicap rates how the next board should compare to the preset, from -1
+// (much easier) to 1 (much harder), based on the last AdaptiveWindow games,
+// oldest first. Wins push it up, losses down, less so when most of the
+// board was cleared, and the pace of wins against ParPerCell nudges it
+// further. Without recent games it is 0.
+func Handicap(recent []RecentGame) float64 {
+	if len(recent) > AdaptiveWindow {
+		recent = recent[len(recent)-AdaptiveWindow:]
+	}
+	if len(recent) == 0 {
+		return 0
+	}
+
+	var score, pace float64
+	timed := 0
+	for _, g := range recent {
+		if !g.Won {
+			score += 0.5 * g.Progress / 100
+			continue
+		}
+		score++
+		if g.SafeCells > 0 && g.Elapsed > 0 {
+			par := ParPerCell * time.Duration(g.SafeCells)
+			pace += clamp(1-float64(g.Elapsed)/float64(par), -1, 1)
+			timed++
+		}
+	}
+
+	h := 2*score/float64(len(recent)) - 1
+	if timed > 0 {
+		h = 0.7*h + 0.3*pace/float64(timed)
+	}
+	return clamp(h, -1, 1)
+}
+
+// Adapt returns config tuned by a handicap: the mine density and the
+// dimensions grow with positive handicaps and shrink with negative ones,
+// within the limits of Validate.
+func Adapt(config Config, handicap float64) Config {
+	handicap = clamp(handicap, -1, 1)
+	if handicap == 0 {
+		return config
+	}
+
+	density := clamp(config.MineDensity*(1+maxDensityAdjust*handicap), MinMineDensity, MaxMineDensity)
+	w, h := config.Dimensions()
+	scale := 1 + maxSizeAdjust*handicap
+	adaptedW, adaptedH := adaptDimension(w, scale), adaptDimension(h, scale)
+
+	if config.MaxMineCount > 0 && config.MineDensity > 0 {
+		ratio := float64(adaptedW*adaptedH) * density / (float64(w*h) * config.MineDensity)
+		config.MaxMineCount = max(int(math.Ceil(float64(config.MaxMineCount)*ratio)), 1)
+		config.MinMineCount = min(config.MinMineCount, config.MaxMineCount)
+	}
+	if config.Width > 0 && config.Height > 0 {
+		config.Width, config.Height = adaptedW, adaptedH
+	} else {
+		config.Size = adaptedW
+	}
+	config.MineDensity = density
+	return config
+}
+
+// adaptDimension scales a width or height, never below 3 cells unless the
+// preset is smaller.
+func adaptDimension(d int, scale float64) int {
+	return int(clamp(math.Round(float64(d)*scale), float64(min(d, 3)), MaxDimension))
+}
+
+// clamp bounds v to [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
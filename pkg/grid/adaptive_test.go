@@ -0,0 +1,76 @@
+package grid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHandicap(t *testing.T) {
+	fastWin := RecentGame{Won: true, Progress: 100, Elapsed: 30 * time.Second, SafeCells: 60}
+	slowWin := RecentGame{Won: true, Progress: 100, Elapsed: 10 * time.Minute, SafeCells: 60}
+	earlyLoss := RecentGame{Progress: 0}
+	lateLoss := RecentGame{Progress: 90}
+
+	tests := []struct {
+		name   string
+		recent []RecentGame
+		want   float64
+	}{
+		{"no games", nil, 0},
+		{"fast wins", []RecentGame{fastWin, fastWin}, 0.7 + 0.3*0.75},
+		{"slow wins", []RecentGame{slowWin}, 0.7 - 0.3},
+		{"early losses", []RecentGame{earlyLoss, earlyLoss}, -1},
+		{"late loss", []RecentGame{lateLoss}, 2*0.45 - 1},
+		{"only the window counts", []RecentGame{earlyLoss, earlyLoss, earlyLoss, earlyLoss, earlyLoss, fastWin, fastWin, fastWin, fastWin, fastWin}, 0.7 + 0.3*0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Handicap(tt.recent); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Handicap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdapt(t *testing.T) {
+	easy := GetDifficultyConfig(DifficultyEasy)
+	wide := GetDifficultyConfig(DifficultyWide)
+
+	tests := []struct {
+		name        string
+		config      Config
+		handicap    float64
+		wantW       int
+		wantH       int
+		wantDensity float64
+	}{
+		{"neutral", easy, 0, 8, 8, easy.MineDensity},
+		{"harder", easy, 1, 10, 10, easy.MineDensity * 1.35},
+		{"easier", easy, -1, 6, 6, easy.MineDensity * 0.65},
+		{"rectangular", wide, 0.4, int(math.Round(float64(wide.Width) * 1.1)), int(math.Round(float64(wide.Height) * 1.1)), wide.MineDensity * 1.14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Adapt(tt.config, tt.handicap)
+			if w, h := got.Dimensions(); w != tt.wantW || h != tt.wantH {
+				t.Errorf("Adapt() dimensions = %dx%d, want %dx%d", w, h, tt.wantW, tt.wantH)
+			}
+			if math.Abs(got.MineDensity-tt.wantDensity) > 1e-9 {
+				t.Errorf("Adapt() density = %v, want %v", got.MineDensity, tt.wantDensity)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Adapt() returned an invalid config: %v", err)
+			}
+		})
+	}
+
+	// Harder boards get more mines, not just a higher density
+	if harder := Adapt(easy, 1); harder.MaxMineCount <= easy.MaxMineCount {
+		t.Errorf("expected the mine cap to grow, got %d", harder.MaxMineCount)
+	}
+	// Limits of Validate are kept
+	if got := Adapt(Config{Size: 2, MineDensity: MinMineDensity}, -1); got.Size != 2 || got.MineDensity != MinMineDensity {
+		t.Errorf("expected the minimum size and density to be kept, got %+v", got)
+	}
+}
@@ -0,0 +1,189 @@
+package grid
+
+import (
+	"fmt"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DifficultyRating is a coarse difficulty label for a board.
+type DifficultyRating string
+
+const (
+	// RatingTrivial boards are mostly cleared by the first click.
+	RatingTrivial DifficultyRating = "trivial"
+	// RatingEasy boards need few clicks and no guessing.
+	RatingEasy DifficultyRating = "easy"
+	// RatingMedium boards need some thinking.
+	RatingMedium DifficultyRating = "medium"
+	// RatingHard boards need many clicks or some guessing.
+	RatingHard DifficultyRating = "hard"
+	// RatingGuessy boards rely heavily on luck.
+	RatingGuessy DifficultyRating = "guess-heavy"
+)
+
+// GuessHeavyThreshold is the number of forced guesses from which a board is
+// rated guess-heavy regardless of its score.
+const GuessHeavyThreshold = 3
+
+// BoardAnalysis summarizes how hard a board is to play.
+type BoardAnalysis struct {
+	// ThreeBV is the Bechtel's Board Benchmark Value: the minimum number of
+	// clicks needed to clear the board without flagging.
+	ThreeBV int `json:"threeBV"`
+
+	// Openings is the number of connected zero-cell regions.
+	Openings int `json:"openings"`
+
+	// SafeCells is the number of cells without a mine.
+	SafeCells int `json:"safeCells"`
+
+	// ForcedGuesses is the number of times a perfect logical player must guess.
+	ForcedGuesses int `json:"forcedGuesses"`
+
+	// FrontierComplexity is the largest frontier the solver got stuck on.
+	FrontierComplexity int `json:"frontierComplexity"`
+
+	// Score is a size-independent difficulty score: 3BV per 100 cells,
+	// plus a penalty for each forced guess.
+	Score float64 `json:"score"`
+
+	// Rating is the label derived from Score and ForcedGuesses.
+	Rating DifficultyRating `json:"rating"`
+}
+
+// DifficultyBand is a range of acceptable board difficulty.
+type DifficultyBand struct {
+	// MinScore rejects boards scoring below it (too trivial).
+	MinScore float64
+
+	// MaxScore rejects boards scoring above it. 0 means no maximum.
+	MaxScore float64
+
+	// MaxGuesses rejects boards needing more forced guesses. -1 means no maximum.
+	MaxGuesses int
+}
+
+// Contains checks if the analysis falls within the band.
+func (b DifficultyBand) Contains(a BoardAnalysis) bool {
+	if a.Score < b.MinScore {
+		return false
+	}
+	if b.MaxScore > 0 && a.Score > b.MaxScore {
+		return false
+	}
+	if b.MaxGuesses >= 0 && a.ForcedGuesses > b.MaxGuesses {
+		return false
+	}
+	return true
+}
+
+// ThreeBV computes the 3BV of a board: one click per zero-cell region, plus
+// one click per safe numbered cell not bordering any zero cell.
+func ThreeBV(state *game.GameState) int {
+	bv, _ := threeBV(state)
+	return bv
+}
+
+// threeBV returns the 3BV and the number of openings of a board.
+func threeBV(state *game.GameState) (bv int, openings int) {
+	w, h := state.Dimensions()
+	seen := make(map[game.Coordinate]bool)
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			if seen[c] || state.IsMine(x, y) || state.AdjacentMines(x, y) != 0 {
+				continue
+			}
+			// Mark the region and its numbered border as cleared by one click
+			for _, z := range zeroRegion(state, c, seen) {
+				for _, n := range state.GetNeighbors(z.X, z.Y) {
+					seen[n] = true
+				}
+			}
+			openings++
+			bv++
+		}
+	}
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			if !seen[c] && !state.IsMine(x, y) {
+				bv++
+			}
+		}
+	}
+	return bv, openings
+}
+
+// AnalyzeBoard scores the difficulty of a board by computing its 3BV and
+// running the solver from the natural first move.
+func AnalyzeBoard(state *game.GameState) BoardAnalysis {
+	var a BoardAnalysis
+	a.ThreeBV, a.Openings = threeBV(state)
+	a.SafeCells = state.TotalCells() - state.MineCount
+
+	if start, ok := FirstMove(state); ok {
+		// Analyze the pristine board, ignoring any progress in the state
+		result := NewSolver(pristineBoard(state)).Run(start)
+		a.ForcedGuesses = result.Guesses
+		a.FrontierComplexity = result.MaxFrontier
+	}
+
+	if total := state.TotalCells(); total > 0 {
+		a.Score = 100*float64(a.ThreeBV)/float64(total) + 10*float64(a.ForcedGuesses)
+	}
+	a.Rating = rate(a)
+	return a
+}
+
+// pristineBoard returns a copy of state with its mines only, as it was
+// before the first move.
+func pristineBoard(state *game.GameState) *game.GameState {
+	pristine := state.Clone()
+	w, h := pristine.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			pristine.Cells[x][y] = game.Cell{Mine: pristine.Cells[x][y].Mine}
+		}
+	}
+	return pristine
+}
+
+// rate derives a DifficultyRating from an analysis.
+func rate(a BoardAnalysis) DifficultyRating {
+	switch {
+	case a.ForcedGuesses >= GuessHeavyThreshold:
+		return RatingGuessy
+	case a.Score < 10:
+		return RatingTrivial
+	case a.Score < 25:
+		return RatingEasy
+	case a.Score < 40:
+		return RatingMedium
+	default:
+		return RatingHard
+	}
+}
+
+// GenerateInBand generates boards starting from seed, trying consecutive
+// seeds until one falls within the band. It returns the board, its analysis
+// and an error if no board matched within maxAttempts.
+func (g *Generator) GenerateInBand(seed int64, band DifficultyBand, maxAttempts int) (*game.GameState, BoardAnalysis, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	for i := 0; i < maxAttempts; i++ {
+		state := g.generateWithSeed(seed + int64(i))
+		if g.config.RequireSolvable && !Solvable(state) {
+			continue
+		}
+		analysis := AnalyzeBoard(state)
+		if band.Contains(analysis) {
+			return state, analysis, nil
+		}
+	}
+	return nil, BoardAnalysis{}, fmt.Errorf("no board within difficulty band after %d attempts", maxAttempts)
+}
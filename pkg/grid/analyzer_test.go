@@ -0,0 +1,128 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestThreeBV(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func() *game.GameState
+		want  int
+	}{
+		{
+			name: "single opening clears everything",
+			setup: func() *game.GameState {
+				s := game.NewGameState(5, 1)
+				s.SetMine(4, 4)
+				return s
+			},
+			want: 1,
+		},
+		{
+			name: "no zero cell means one click per safe cell",
+			setup: func() *game.GameState {
+				// 2x2 with 3 mines leaves a single numbered cell
+				s := game.NewGameState(2, 1)
+				s.SetMine(0, 0)
+				s.SetMine(0, 1)
+				s.SetMine(1, 0)
+				return s
+			},
+			want: 1,
+		},
+		{
+			name: "isolated numbered cells",
+			setup: func() *game.GameState {
+				// Row of 3: M . M -> the middle cell is isolated
+				s := game.NewRectGameState(3, 1, 1)
+				s.SetMine(0, 0)
+				s.SetMine(2, 0)
+				return s
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThreeBV(tt.setup()); got != tt.want {
+				t.Errorf("ThreeBV() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBoard(t *testing.T) {
+	state := game.NewGameState(10, 1)
+	state.SetMine(9, 9)
+
+	a := AnalyzeBoard(state)
+	if a.ThreeBV != 1 || a.Openings != 1 {
+		t.Errorf("expected 3BV 1 and 1 opening, got %d and %d", a.ThreeBV, a.Openings)
+	}
+	if a.SafeCells != 99 {
+		t.Errorf("expected 99 safe cells, got %d", a.SafeCells)
+	}
+	if a.ForcedGuesses != 0 {
+		t.Errorf("expected no forced guesses, got %d", a.ForcedGuesses)
+	}
+	if a.Rating != RatingTrivial {
+		t.Errorf("expected trivial rating, got %s", a.Rating)
+	}
+}
+
+func TestAnalyzeBoardIgnoresProgress(t *testing.T) {
+	state, _ := GenerateGrid(10, 42, 0.15)
+	before := AnalyzeBoard(state)
+
+	start, _ := FirstMove(state)
+	state.Reveal(start.X, start.Y)
+	after := AnalyzeBoard(state)
+
+	if before != after {
+		t.Errorf("analysis changed after revealing a cell: %+v vs %+v", before, after)
+	}
+}
+
+func TestDifficultyBandContains(t *testing.T) {
+	band := DifficultyBand{MinScore: 10, MaxScore: 30, MaxGuesses: 1}
+
+	tests := []struct {
+		name string
+		a    BoardAnalysis
+		want bool
+	}{
+		{"within", BoardAnalysis{Score: 20}, true},
+		{"too trivial", BoardAnalysis{Score: 5}, false},
+		{"too hard", BoardAnalysis{Score: 35}, false},
+		{"too guessy", BoardAnalysis{Score: 20, ForcedGuesses: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := band.Contains(tt.a); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateInBand(t *testing.T) {
+	gen, _ := NewGenerator(Config{Size: 10, MineDensity: 0.15, MinMineCount: 1})
+	band := DifficultyBand{MinScore: 15, MaxGuesses: 0}
+
+	state, analysis, err := gen.GenerateInBand(1, band, 200)
+	if err != nil {
+		t.Fatalf("GenerateInBand failed: %v", err)
+	}
+	if state == nil || !band.Contains(analysis) {
+		t.Errorf("expected a board within the band, got %+v", analysis)
+	}
+
+	impossible := DifficultyBand{MinScore: 1000, MaxGuesses: -1}
+	if _, _, err := gen.GenerateInBand(1, impossible, 3); err == nil {
+		t.Error("expected error for an impossible band")
+	}
+}
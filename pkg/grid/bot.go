@@ -0,0 +1,52 @@
+package grid
+
+import (
+	"math/rand"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// BotMove picks the next cell of a bot playing fair: unlike SuggestMove,
+// it never looks at the mines, so it can lose. Skill, from 0 to 1, is the
+// chance the bot spots a cell the revealed hints prove safe; otherwise,
+// and whenever no cell can be proven safe, it guesses among the cells not
+// proven to be mines, preferring the frontier. The bot starts from the
+// opening of the board if any. Temperatures prove nothing to the solver:
+// in hot/cold games, the bot always guesses. Returns false if no cell is
+// left to play.
+func BotMove(state *game.GameState, skill float64, rng *rand.Rand) (game.Coordinate, bool) {
+	if state.Stats().RevealedCells == 0 && state.Opening != nil {
+		return *state.Opening, true
+	}
+
+	solver := NewSolver(state)
+	if rng.Float64() < skill && !state.HotCold() {
+		for round := 0; round < maxSuggestRounds; round++ {
+			safe, mines := solver.Deduce()
+			if len(safe) > 0 {
+				return safe[rng.Intn(len(safe))], true
+			}
+			if len(mines) == 0 {
+				break
+			}
+		}
+	}
+
+	// Guess, the way a player without a deduction would
+	if frontier := solver.Frontier(); len(frontier) > 0 {
+		return frontier[rng.Intn(len(frontier))], true
+	}
+	var hidden []game.Coordinate
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if !state.IsRevealed(x, y) && !solver.IsFlagged(x, y) {
+				hidden = append(hidden, game.Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	if len(hidden) == 0 {
+		return game.Coordinate{}, false
+	}
+	return hidden[rng.Intn(len(hidden))], true
+}
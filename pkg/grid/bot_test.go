@@ -0,0 +1,60 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestBotMoveOpening(t *testing.T) {
+	state := game.NewGameState(5, 1)
+	state.SetMine(4, 4)
+	state.Opening = &game.Coordinate{X: 1, Y: 2}
+
+	c, ok := BotMove(state, 1, rand.New(rand.NewSource(1)))
+	if !ok || c != *state.Opening {
+		t.Errorf("expected the bot to start from the opening, got %v", c)
+	}
+}
+
+func TestBotMoveSkill(t *testing.T) {
+	// . . M with (0,0) revealed as a zero: (1,0) is proven safe
+	state := game.NewRectGameState(3, 1, 1)
+	state.SetMine(2, 0)
+	state.Reveal(0, 0)
+
+	rng := rand.New(rand.NewSource(1))
+	for range 20 {
+		if c, ok := BotMove(state, 1, rng); !ok || c != (game.Coordinate{X: 1, Y: 0}) {
+			t.Fatalf("expected a skilled bot to play proven safe (1,0), got %v", c)
+		}
+	}
+
+	// (1,1) shows 1 with three hidden neighbors: nothing can be proven
+	state = game.NewGameState(2, 1)
+	state.SetMine(0, 0)
+	state.Reveal(1, 1)
+	mines := 0
+	for range 50 {
+		c, ok := BotMove(state, 1, rng)
+		if !ok {
+			t.Fatal("expected a move")
+		}
+		if state.IsMine(c.X, c.Y) {
+			mines++
+		}
+	}
+	if mines == 0 {
+		t.Error("expected the bot to guess without knowing the mines")
+	}
+}
+
+func TestBotMoveNoCellLeft(t *testing.T) {
+	state := game.NewRectGameState(2, 1, 1)
+	state.Reveal(0, 0)
+	state.Reveal(1, 0)
+	if c, ok := BotMove(state, 1, rand.New(rand.NewSource(1))); ok {
+		t.Errorf("expected no move on a fully revealed board, got %v", c)
+	}
+}
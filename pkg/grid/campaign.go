@@ -0,0 +1,110 @@
+package grid
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// CampaignLevels is the number of levels of a full campaign (0-9).
+const CampaignLevels = 10
+
+// campaignSalt domain-separates campaign seeds from other uses of the master seed.
+const campaignSalt = "podsweeper-campaign-v1"
+
+// DefaultCampaignPresets is the difficulty used for each level of a campaign.
+var DefaultCampaignPresets = [CampaignLevels]DifficultyPreset{
+	DifficultyEasy,
+	DifficultyEasy,
+	DifficultyMedium,
+	DifficultyMedium,
+	DifficultyMedium,
+	DifficultyHard,
+	DifficultyHard,
+	DifficultyHard,
+	DifficultyExpert,
+	DifficultyExpert,
+}
+
+// DeriveLevelSeed derives the seed of a campaign level from a master seed
+// using HKDF-SHA256. Seeds of different levels are independent, and the same
+// master seed always yields the same seeds, so a whole run can be shared.
+func DeriveLevelSeed(master int64, level int) (int64, error) {
+	var secret [8]byte
+	binary.BigEndian.PutUint64(secret[:], uint64(master))
+
+	info := fmt.Sprintf("level/%d", level)
+	key, err := hkdf.Key(sha256.New, secret[:], []byte(campaignSalt), info, 8)
+	if err != nil {
+		return 0, fmt.Errorf("failed to derive seed for level %d: %w", level, err)
+	}
+
+	seed := int64(binary.BigEndian.Uint64(key) & math.MaxInt64)
+	if seed == 0 {
+		// Seed 0 means "random" for the generator
+		seed = 1
+	}
+	return seed, nil
+}
+
+// Campaign is a reproducible set of boards, one per level, derived from a
+// single master seed.
+type Campaign struct {
+	// MasterSeed is the seed every level seed is derived from.
+	MasterSeed int64
+
+	// Presets is the difficulty preset of each level.
+	Presets [CampaignLevels]DifficultyPreset
+
+	// Registry resolves preset names. Defaults to the built-in presets.
+	Registry *PresetRegistry
+}
+
+// NewCampaign creates a campaign with the default presets.
+func NewCampaign(master int64) *Campaign {
+	return &Campaign{
+		MasterSeed: master,
+		Presets:    DefaultCampaignPresets,
+		Registry:   NewPresetRegistry(),
+	}
+}
+
+// LevelSeed returns the seed of the given level.
+func (c *Campaign) LevelSeed(level int) (int64, error) {
+	if level < 0 || level >= CampaignLevels {
+		return 0, fmt.Errorf("level must be between 0 and %d, got %d", CampaignLevels-1, level)
+	}
+	return DeriveLevelSeed(c.MasterSeed, level)
+}
+
+// GenerateLevel generates the board of the given level.
+func (c *Campaign) GenerateLevel(level int) (*game.GameState, error) {
+	seed, err := c.LevelSeed(level)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := c.Registry
+	if registry == nil {
+		registry = NewPresetRegistry()
+	}
+	config, ok := registry.Get(c.Presets[level])
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q for level %d", c.Presets[level], level)
+	}
+	config.Seed = seed
+
+	gen, err := NewGenerator(config)
+	if err != nil {
+		return nil, err
+	}
+
+	state := gen.GenerateWithSeed(seed)
+	state.Level = level
+	state.CampaignSeed = c.MasterSeed
+	return state, nil
+}
@@ -0,0 +1,74 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestDeriveLevelSeed(t *testing.T) {
+	seeds := make(map[int64]bool)
+	for level := 0; level < CampaignLevels; level++ {
+		a, err := DeriveLevelSeed(42, level)
+		if err != nil {
+			t.Fatalf("DeriveLevelSeed failed: %v", err)
+		}
+		b, _ := DeriveLevelSeed(42, level)
+		if a != b {
+			t.Errorf("level %d: derivation is not deterministic (%d vs %d)", level, a, b)
+		}
+		if a <= 0 {
+			t.Errorf("level %d: expected a positive seed, got %d", level, a)
+		}
+		seeds[a] = true
+	}
+	if len(seeds) != CampaignLevels {
+		t.Errorf("expected %d distinct level seeds, got %d", CampaignLevels, len(seeds))
+	}
+
+	other, _ := DeriveLevelSeed(43, 0)
+	same, _ := DeriveLevelSeed(42, 0)
+	if other == same {
+		t.Error("different master seeds should yield different level seeds")
+	}
+}
+
+func TestCampaignGenerateLevel(t *testing.T) {
+	c := NewCampaign(2024)
+
+	for level := 0; level < CampaignLevels; level++ {
+		a, err := c.GenerateLevel(level)
+		if err != nil {
+			t.Fatalf("GenerateLevel(%d) failed: %v", level, err)
+		}
+		b, _ := NewCampaign(2024).GenerateLevel(level)
+
+		if a.Level != level {
+			t.Errorf("expected level %d, got %d", level, a.Level)
+		}
+		if a.CampaignSeed != 2024 {
+			t.Errorf("expected campaign seed 2024, got %d", a.CampaignSeed)
+		}
+		w, h := a.Dimensions()
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if a.IsMine(x, y) != b.IsMine(x, y) {
+					t.Fatalf("level %d: boards differ at (%d,%d)", level, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestCampaignInvalidLevel(t *testing.T) {
+	c := NewCampaign(1)
+	if _, err := c.GenerateLevel(-1); err == nil {
+		t.Error("expected error for negative level")
+	}
+	if _, err := c.GenerateLevel(CampaignLevels); err == nil {
+		t.Error("expected error for level beyond the campaign")
+	}
+
+	c.Presets[3] = "does-not-exist"
+	if _, err := c.GenerateLevel(3); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
@@ -0,0 +1,114 @@
+package grid
+
+import (
+	"fmt"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// MirrorAxis is the axis a board is reflected across.
+type MirrorAxis string
+
+const (
+	// MirrorVertical reflects columns: (x, y) becomes (width-1-x, y).
+	MirrorVertical MirrorAxis = "vertical"
+	// MirrorHorizontal reflects rows: (x, y) becomes (x, height-1-y).
+	MirrorHorizontal MirrorAxis = "horizontal"
+)
+
+// reflect returns the mirror image of c on a width x height grid.
+func (a MirrorAxis) reflect(c game.Coordinate, width, height int) game.Coordinate {
+	if a == MirrorHorizontal {
+		return game.Coordinate{X: c.X, Y: height - 1 - c.Y}
+	}
+	return game.Coordinate{X: width - 1 - c.X, Y: c.Y}
+}
+
+// Mirror returns a fresh board whose mines and opening are the mirror image
+// of the given state's. Progress (revealed cells, hints) is not copied.
+func Mirror(state *game.GameState, axis MirrorAxis) *game.GameState {
+	width, height := state.Dimensions()
+	mirrored := game.NewRectGameState(width, height, state.Seed)
+	mirrored.Size = state.Size
+	mirrored.Level = state.Level
+	mirrored.CampaignSeed = state.CampaignSeed
+	mirrored.Placement = state.Placement
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if state.IsMine(x, y) {
+				m := axis.reflect(game.Coordinate{X: x, Y: y}, width, height)
+				mirrored.SetMine(m.X, m.Y)
+			}
+		}
+	}
+	if state.Opening != nil {
+		opening := axis.reflect(*state.Opening, width, height)
+		mirrored.Opening = &opening
+	}
+	return mirrored
+}
+
+// IsSymmetric reports whether the mine layout is its own mirror image.
+func IsSymmetric(state *game.GameState, axis MirrorAxis) bool {
+	width, height := state.Dimensions()
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			m := axis.reflect(game.Coordinate{X: x, Y: y}, width, height)
+			if state.IsMine(x, y) != state.IsMine(m.X, m.Y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Halves splits a board along the axis into two independent boards.
+// The center column (or row) of odd-sized grids belongs to neither half.
+// On a symmetric board both halves are mirror images of each other.
+func Halves(state *game.GameState, axis MirrorAxis) (first, second *game.GameState) {
+	width, height := state.Dimensions()
+	if axis == MirrorHorizontal {
+		half := height / 2
+		return subBoard(state, 0, 0, width, half), subBoard(state, 0, height-half, width, half)
+	}
+	half := width / 2
+	return subBoard(state, 0, 0, half, height), subBoard(state, width-half, 0, half, height)
+}
+
+// subBoard copies the mines of a w x h window starting at (x0, y0).
+func subBoard(state *game.GameState, x0, y0, w, h int) *game.GameState {
+	sub := game.NewRectGameState(w, h, state.Seed)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if state.IsMine(x0+x, y0+y) {
+				sub.SetMine(x, y)
+			}
+		}
+	}
+	return sub
+}
+
+// DuelBoards is a pair of boards for head-to-head play. Second is the mirror
+// image of First, so both players face the same puzzle without being able
+// to copy each other's moves cell for cell.
+type DuelBoards struct {
+	Axis   MirrorAxis
+	First  *game.GameState
+	Second *game.GameState
+}
+
+// GenerateDuel generates a board from the seed and pairs it with its mirror
+// image across the given axis.
+func (g *Generator) GenerateDuel(seed int64, axis MirrorAxis) (*DuelBoards, error) {
+	if axis != MirrorVertical && axis != MirrorHorizontal {
+		return nil, fmt.Errorf("unknown mirror axis %q", axis)
+	}
+
+	first := g.GenerateWithSeed(seed)
+	return &DuelBoards{
+		Axis:   axis,
+		First:  first,
+		Second: Mirror(first, axis),
+	}, nil
+}
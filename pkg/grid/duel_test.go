@@ -0,0 +1,116 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestMirror(t *testing.T) {
+	state := game.NewRectGameState(5, 3, 1)
+	state.SetMine(0, 0)
+	state.SetMine(1, 2)
+	start := game.Coordinate{X: 4, Y: 1}
+	state.Opening = &start
+
+	tests := []struct {
+		axis    MirrorAxis
+		mines   []game.Coordinate
+		opening game.Coordinate
+	}{
+		{MirrorVertical, []game.Coordinate{{X: 4, Y: 0}, {X: 3, Y: 2}}, game.Coordinate{X: 0, Y: 1}},
+		{MirrorHorizontal, []game.Coordinate{{X: 0, Y: 2}, {X: 1, Y: 0}}, game.Coordinate{X: 4, Y: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.axis), func(t *testing.T) {
+			m := Mirror(state, tt.axis)
+			if m.MineCount != 2 {
+				t.Errorf("expected 2 mines, got %d", m.MineCount)
+			}
+			for _, c := range tt.mines {
+				if !m.IsMine(c.X, c.Y) {
+					t.Errorf("expected mine at %s", c)
+				}
+			}
+			if m.Opening == nil || *m.Opening != tt.opening {
+				t.Errorf("expected opening %s, got %v", tt.opening, m.Opening)
+			}
+		})
+	}
+}
+
+func TestGenerateDuel(t *testing.T) {
+	gen, err := NewGenerator(Config{Width: 16, Height: 9, MineDensity: 0.18, MinMineCount: 1})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+
+	for _, axis := range []MirrorAxis{MirrorVertical, MirrorHorizontal} {
+		for seed := int64(1); seed <= 10; seed++ {
+			duel, err := gen.GenerateDuel(seed, axis)
+			if err != nil {
+				t.Fatalf("GenerateDuel failed: %v", err)
+			}
+			if duel.First.MineCount != duel.Second.MineCount {
+				t.Errorf("mine counts differ: %d vs %d", duel.First.MineCount, duel.Second.MineCount)
+			}
+			if a, b := ThreeBV(duel.First), ThreeBV(duel.Second); a != b {
+				t.Errorf("axis %s seed %d: 3BV differs: %d vs %d", axis, seed, a, b)
+			}
+
+			back := Mirror(duel.Second, axis)
+			w, h := back.Dimensions()
+			for x := 0; x < w; x++ {
+				for y := 0; y < h; y++ {
+					if back.IsMine(x, y) != duel.First.IsMine(x, y) {
+						t.Fatalf("mirroring twice should restore the board, differs at (%d,%d)", x, y)
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := gen.GenerateDuel(1, "diagonal"); err == nil {
+		t.Error("expected error for unknown axis")
+	}
+}
+
+func TestSymmetricBoardHalves(t *testing.T) {
+	for _, width := range []int{10, 11} {
+		gen, err := NewGenerator(Config{Width: width, Height: 8, MineDensity: 0.2, MinMineCount: 1, Placement: PlacementSymmetric})
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %v", err)
+		}
+
+		for seed := int64(1); seed <= 10; seed++ {
+			state := gen.GenerateWithSeed(seed)
+			if !IsSymmetric(state, MirrorVertical) {
+				t.Fatalf("width %d seed %d: board is not symmetric", width, seed)
+			}
+
+			left, right := Halves(state, MirrorVertical)
+			if left.MineCount != right.MineCount {
+				t.Errorf("width %d seed %d: halves have %d and %d mines", width, seed, left.MineCount, right.MineCount)
+			}
+			if a, b := ThreeBV(left), ThreeBV(right); a != b {
+				t.Errorf("width %d seed %d: halves have 3BV %d and %d", width, seed, a, b)
+			}
+		}
+	}
+}
+
+func TestIsSymmetric(t *testing.T) {
+	state := game.NewGameState(4, 1)
+	state.SetMine(0, 1)
+	if IsSymmetric(state, MirrorVertical) {
+		t.Error("single off-center mine should not be symmetric")
+	}
+	state.SetMine(3, 1)
+	if !IsSymmetric(state, MirrorVertical) {
+		t.Error("mirrored pair should be symmetric")
+	}
+	if IsSymmetric(state, MirrorHorizontal) {
+		t.Error("pair on row 1 of 4 should not be horizontally symmetric")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/rules"
 )
 
 // DefaultSize is the default grid dimension.
@@ -20,16 +21,32 @@ const MinMineDensity = 0.05 // 5%
 // MaxMineDensity is the maximum allowed mine density.
 const MaxMineDensity = 0.50 // 50%
 
+// MaxDimension is the maximum allowed width or height of a grid.
+const MaxDimension = 100
+
 // Config holds the configuration for grid generation.
 type Config struct {
 	// Size is the grid dimension (Size x Size).
+	// It is a shorthand for square grids, ignored when Width and Height are set.
 	// Default: 10
 	Size int
 
+	// Width is the number of columns of a rectangular grid.
+	// Must be set together with Height.
+	Width int
+
+	// Height is the number of rows of a rectangular grid.
+	// Must be set together with Width.
+	Height int
+
 	// Seed is the random seed for reproducible mine placement.
 	// If 0, a random seed will be used.
 	Seed int64
 
+	// SecureSeed draws the random seed from crypto/rand when Seed is 0,
+	// so players cannot predict the layout. See SecureSeedForLevel.
+	SecureSeed bool
+
 	// MineDensity is the percentage of cells that should be mines (0.0 to 1.0).
 	// Default: 0.15 (15%)
 	MineDensity float64
@@ -41,6 +58,33 @@ type Config struct {
 	// MaxMineCount is the maximum number of mines regardless of density.
 	// If 0, no maximum is enforced.
 	MaxMineCount int
+
+	// Placement selects the mine placement strategy.
+	// Default: uniform
+	Placement PlacementStrategy
+
+	// Opening, when set, is a coordinate around which no mine is placed.
+	// It gives games a guaranteed opening and is recorded in the state.
+	Opening *game.Coordinate
+
+	// OpeningRadius is the radius (in cells, diagonals included) kept
+	// mine-free around Opening. Use DefaultOpeningRadius (1) to guarantee
+	// a zero cell; 0 only protects the opening cell itself.
+	OpeningRadius int
+
+	// Rules are the custom rules of the games generated, see package
+	// rules. Nil plays by the built-in rules only.
+	Rules *game.Rules
+
+	// Level is the level of the games generated, whose policy sets the
+	// obstacles players face, see package level.
+	// Default: 0
+	Level int
+
+	// RequireSolvable only generates boards that can be cleared from the
+	// opening cell without guessing, retrying consecutive seeds. See
+	// Solvable and GenerateSolvable.
+	RequireSolvable bool
 }
 
 // DefaultConfig returns a Config with default values.
@@ -54,13 +98,40 @@ func DefaultConfig() Config {
 	}
 }
 
+// Dimensions returns the width and height of the grid described by the config.
+// Width and Height take precedence over Size when both are set.
+func (c *Config) Dimensions() (width, height int) {
+	if c.Width > 0 && c.Height > 0 {
+		return c.Width, c.Height
+	}
+	return c.Size, c.Size
+}
+
+// IsRectangular returns true if the config describes a non-square grid.
+func (c *Config) IsRectangular() bool {
+	w, h := c.Dimensions()
+	return w != h
+}
+
 // Validate checks if the config values are valid and returns an error if not.
 func (c *Config) Validate() error {
-	if c.Size < 1 {
-		return fmt.Errorf("size must be at least 1, got %d", c.Size)
+	if (c.Width > 0) != (c.Height > 0) || c.Width < 0 || c.Height < 0 {
+		return fmt.Errorf("width and height must be set together, got %dx%d", c.Width, c.Height)
 	}
-	if c.Size > 100 {
-		return fmt.Errorf("size must be at most 100, got %d", c.Size)
+	if c.Width > 0 {
+		if c.Width > MaxDimension || c.Height > MaxDimension {
+			return fmt.Errorf("width and height must be at most %d, got %dx%d", MaxDimension, c.Width, c.Height)
+		}
+		if c.Width*c.Height < 2 {
+			return fmt.Errorf("grid must have at least 2 cells, got %dx%d", c.Width, c.Height)
+		}
+	} else {
+		if c.Size < 1 {
+			return fmt.Errorf("size must be at least 1, got %d", c.Size)
+		}
+		if c.Size > MaxDimension {
+			return fmt.Errorf("size must be at most %d, got %d", MaxDimension, c.Size)
+		}
 	}
 	if c.MineDensity < MinMineDensity {
 		return fmt.Errorf("mine density must be at least %.2f, got %.2f", MinMineDensity, c.MineDensity)
@@ -77,12 +148,33 @@ func (c *Config) Validate() error {
 	if c.MaxMineCount > 0 && c.MinMineCount > c.MaxMineCount {
 		return fmt.Errorf("min mine count (%d) cannot exceed max mine count (%d)", c.MinMineCount, c.MaxMineCount)
 	}
+	if _, err := GetPlacer(c.Placement); err != nil {
+		return err
+	}
+	if c.OpeningRadius < 0 {
+		return fmt.Errorf("opening radius cannot be negative, got %d", c.OpeningRadius)
+	}
+	if c.Level < 0 {
+		return fmt.Errorf("level cannot be negative, got %d", c.Level)
+	}
+	if c.Rules != nil {
+		if _, err := rules.Compile(*c.Rules); err != nil {
+			return err
+		}
+	}
+	if c.Opening != nil {
+		w, h := c.Dimensions()
+		if err := validateOpening(w, h, *c.Opening, c.OpeningRadius, c.CalculateMineCount()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // CalculateMineCount returns the number of mines based on config.
 func (c *Config) CalculateMineCount() int {
-	totalCells := c.Size * c.Size
+	w, h := c.Dimensions()
+	totalCells := w * h
 	mineCount := int(float64(totalCells) * c.MineDensity)
 
 	// Enforce minimum
@@ -107,7 +199,9 @@ func (c *Config) CalculateMineCount() int {
 // Generator creates game grids with randomly placed mines.
 type Generator struct {
 	config Config
+	seed   int64
 	rng    *rand.Rand
+	placer MinePlacer
 }
 
 // NewGenerator creates a new grid generator with the given config.
@@ -116,15 +210,25 @@ func NewGenerator(config Config) (*Generator, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	placer, err := GetPlacer(config.Placement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	// Use provided seed or generate one
 	seed := config.Seed
 	if seed == 0 {
-		seed = rand.Int63()
+		seed, err = NewSeed(config.SecureSeed)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &Generator{
 		config: config,
+		seed:   seed,
 		rng:    rand.New(rand.NewSource(seed)),
+		placer: placer,
 	}, nil
 }
 
@@ -135,22 +239,56 @@ func NewDefaultGenerator() *Generator {
 }
 
 // Generate creates a new GameState with mines randomly placed.
+// With RequireSolvable, it generates from the generator's seed as
+// GenerateWithSeed does.
 func (g *Generator) Generate() *game.GameState {
-	state := game.NewGameState(g.config.Size, g.config.Seed)
+	if g.config.RequireSolvable {
+		return g.GenerateWithSeed(g.seed)
+	}
+	state := g.newState(g.seed)
 	g.placeMines(state)
 	return state
 }
 
 // GenerateWithSeed creates a new GameState using a specific seed.
 // This is useful for reproducible game generation.
+//
+// With RequireSolvable, the seeds following seed are tried until a board is
+// solvable, and the state records the seed of that board. If none is within
+// DefaultSolvableAttempts, the board of seed is returned as is.
 func (g *Generator) GenerateWithSeed(seed int64) *game.GameState {
+	if g.config.RequireSolvable {
+		if state, err := g.GenerateSolvable(seed, DefaultSolvableAttempts); err == nil {
+			return state
+		}
+	}
+	return g.generateWithSeed(seed)
+}
+
+// generateWithSeed creates a new GameState using a specific seed, solvable
+// or not.
+func (g *Generator) generateWithSeed(seed int64) *game.GameState {
 	// Create a new RNG with the specific seed
 	rng := rand.New(rand.NewSource(seed))
-	state := game.NewGameState(g.config.Size, seed)
+	state := g.newState(seed)
 	g.placeMinesWithRNG(state, rng)
 	return state
 }
 
+// newState creates an empty GameState matching the configured dimensions.
+func (g *Generator) newState(seed int64) *game.GameState {
+	w, h := g.config.Dimensions()
+	state := game.NewRectGameState(w, h, seed)
+	state.Placement = string(g.placer.Strategy())
+	state.Level = g.config.Level
+	if g.config.Rules != nil {
+		r := *g.config.Rules
+		state.Rules = &r
+	}
+	state.Phase = game.PhasePending
+	return state
+}
+
 // placeMines randomly places mines on the grid using the generator's RNG.
 func (g *Generator) placeMines(state *game.GameState) {
 	g.placeMinesWithRNG(state, g.rng)
@@ -158,28 +296,19 @@ func (g *Generator) placeMines(state *game.GameState) {
 
 // placeMinesWithRNG places mines using a specific RNG instance.
 func (g *Generator) placeMinesWithRNG(state *game.GameState, rng *rand.Rand) {
-	mineCount := g.config.CalculateMineCount()
-	totalCells := g.config.Size * g.config.Size
-
-	// Create a slice of all possible positions
-	positions := make([]int, totalCells)
-	for i := 0; i < totalCells; i++ {
-		positions[i] = i
-	}
+	g.placer.Place(state, g.config.CalculateMineCount(), rng)
 
-	// Fisher-Yates shuffle
-	for i := len(positions) - 1; i > 0; i-- {
-		j := rng.Intn(i + 1)
-		positions[i], positions[j] = positions[j], positions[i]
+	if g.config.Opening != nil {
+		clearOpening(state, *g.config.Opening, g.config.OpeningRadius, rng)
+		opening := *g.config.Opening
+		state.Opening = &opening
 	}
+}
 
-	// Place mines at the first mineCount positions
-	for i := 0; i < mineCount; i++ {
-		pos := positions[i]
-		x := pos / g.config.Size
-		y := pos % g.config.Size
-		state.SetMine(x, y)
-	}
+// Seed returns the seed of the generator's RNG, either the configured one
+// or the one drawn when Seed was 0.
+func (g *Generator) Seed() int64 {
+	return g.seed
 }
 
 // Config returns the generator's configuration.
@@ -223,6 +352,10 @@ const (
 	DifficultyHard DifficultyPreset = "hard"
 	// DifficultyExpert is 20x20 with 25% mines (100 mines).
 	DifficultyExpert DifficultyPreset = "expert"
+	// DifficultyWide is 16x8 with 15% mines (19 mines), a short board fitting small clusters.
+	DifficultyWide DifficultyPreset = "wide"
+	// DifficultyPanorama is 30x10 with 18% mines (54 mines).
+	DifficultyPanorama DifficultyPreset = "panorama"
 )
 
 // GetDifficultyConfig returns a Config for the given difficulty preset.
@@ -256,6 +389,22 @@ func GetDifficultyConfig(preset DifficultyPreset) Config {
 			MinMineCount: 80,
 			MaxMineCount: 120,
 		}
+	case DifficultyWide:
+		return Config{
+			Width:        16,
+			Height:       8,
+			MineDensity:  0.15,
+			MinMineCount: 10,
+			MaxMineCount: 25,
+		}
+	case DifficultyPanorama:
+		return Config{
+			Width:        30,
+			Height:       10,
+			MineDensity:  0.18,
+			MinMineCount: 40,
+			MaxMineCount: 70,
+		}
 	default:
 		return DefaultConfig()
 	}
@@ -478,3 +478,123 @@ func TestGeneratorConfig(t *testing.T) {
 		t.Error("Config() should return the original config")
 	}
 }
+
+func TestConfigDimensions(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		wantW, wantH int
+		wantRect     bool
+	}{
+		{"square shorthand", Config{Size: 10}, 10, 10, false},
+		{"rectangular", Config{Width: 16, Height: 8}, 16, 8, true},
+		{"width and height override size", Config{Size: 10, Width: 12, Height: 6}, 12, 6, true},
+		{"explicit square", Config{Width: 7, Height: 7}, 7, 7, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := tt.config.Dimensions()
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("Dimensions() = %dx%d, want %dx%d", w, h, tt.wantW, tt.wantH)
+			}
+			if got := tt.config.IsRectangular(); got != tt.wantRect {
+				t.Errorf("IsRectangular() = %v, want %v", got, tt.wantRect)
+			}
+		})
+	}
+}
+
+func TestConfigValidateRectangular(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"valid rectangle", Config{Width: 20, Height: 5, MineDensity: 0.15}, false},
+		{"width without height", Config{Width: 20, MineDensity: 0.15}, true},
+		{"height without width", Config{Height: 20, MineDensity: 0.15}, true},
+		{"width too large", Config{Width: 101, Height: 5, MineDensity: 0.15}, true},
+		{"single cell", Config{Width: 1, Height: 1, MineDensity: 0.15}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculateMineCountRectangular(t *testing.T) {
+	config := Config{Width: 20, Height: 5, MineDensity: 0.20, MinMineCount: 1}
+
+	if got := config.CalculateMineCount(); got != 20 {
+		t.Errorf("expected 20 mines (100 cells * 20%%), got %d", got)
+	}
+}
+
+func TestGenerateRectangular(t *testing.T) {
+	config := Config{Width: 12, Height: 4, MineDensity: 0.25, MinMineCount: 1}
+	gen, err := NewGenerator(config)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+
+	state := gen.GenerateWithSeed(42)
+
+	w, h := state.Dimensions()
+	if w != 12 || h != 4 {
+		t.Fatalf("expected 12x4 grid, got %dx%d", w, h)
+	}
+	if state.MineCount != config.CalculateMineCount() {
+		t.Errorf("expected %d mines, got %d", config.CalculateMineCount(), state.MineCount)
+	}
+
+	// All mines must be within bounds and counted
+	mines := 0
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if state.IsMine(x, y) {
+				mines++
+			}
+		}
+	}
+	if mines != state.MineCount {
+		t.Errorf("counted %d mines but MineCount is %d", mines, state.MineCount)
+	}
+
+	// Same seed must produce the same board
+	again := gen.GenerateWithSeed(42)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if state.IsMine(x, y) != again.IsMine(x, y) {
+				t.Fatalf("mine layout differs at (%d,%d) for the same seed", x, y)
+			}
+		}
+	}
+}
+
+func TestRectangularPresets(t *testing.T) {
+	for _, preset := range []DifficultyPreset{DifficultyWide, DifficultyPanorama} {
+		t.Run(string(preset), func(t *testing.T) {
+			config := GetDifficultyConfig(preset)
+			if !config.IsRectangular() {
+				t.Errorf("expected %s preset to be rectangular", preset)
+			}
+
+			state, err := GenerateWithDifficulty(preset, 12345)
+			if err != nil {
+				t.Fatalf("GenerateWithDifficulty failed: %v", err)
+			}
+
+			w, h := state.Dimensions()
+			cw, ch := config.Dimensions()
+			if w != cw || h != ch {
+				t.Errorf("expected %dx%d grid, got %dx%d", cw, ch, w, h)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package grid
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// GradientOrigin is the point of the grid where mine density peaks.
+type GradientOrigin string
+
+const (
+	// GradientCenter peaks at the center of the grid.
+	GradientCenter GradientOrigin = "center"
+	// GradientCorner peaks at the top-left corner (0,0).
+	GradientCorner GradientOrigin = "corner"
+)
+
+// DefaultGradientFloor is the weight kept by cells farthest from the origin,
+// relative to the origin itself, so that no cell is guaranteed safe.
+const DefaultGradientFloor = 0.05
+
+// GradientFunc maps the closeness of a cell to the origin, from 0 (farthest
+// cell) to 1 (the origin), to a density factor in [0, 1].
+type GradientFunc func(t float64) float64
+
+// LinearGradient makes density grow linearly toward the origin.
+func LinearGradient(t float64) float64 { return t }
+
+// QuadraticGradient concentrates mines more sharply around the origin.
+func QuadraticGradient(t float64) float64 { return t * t }
+
+// ExponentialGradient keeps most of the board sparse and packs the origin.
+func ExponentialGradient(t float64) float64 {
+	const k = 4.0
+	return (math.Exp(k*t) - 1) / (math.Exp(k) - 1)
+}
+
+// GradientPlacer places mines with a per-cell probability that increases
+// toward an origin, following a configurable gradient function.
+type GradientPlacer struct {
+	// Origin is where density peaks. Defaults to GradientCenter.
+	Origin GradientOrigin
+
+	// Gradient shapes the density curve. Defaults to LinearGradient.
+	Gradient GradientFunc
+
+	// Floor is the relative weight of the farthest cells, in (0, 1].
+	// Defaults to DefaultGradientFloor.
+	Floor float64
+
+	// Name overrides the registered strategy name, allowing several
+	// gradient placers to be registered with different functions.
+	Name PlacementStrategy
+}
+
+// Strategy implements MinePlacer.
+func (p GradientPlacer) Strategy() PlacementStrategy {
+	if p.Name != "" {
+		return p.Name
+	}
+	if p.Origin == GradientCorner {
+		return PlacementCornerGradient
+	}
+	return PlacementCenterGradient
+}
+
+// Place implements MinePlacer.
+func (p GradientPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	width, height := state.Dimensions()
+	weight := p.Weights(width, height)
+	PlaceWeighted(state, mineCount, rng, func(x, y int) float64 {
+		return weight[x][y]
+	})
+}
+
+// Weights returns the relative mine weight of every cell of a width x height
+// grid, indexed [x][y]. The origin has weight 1 and the farthest cells Floor.
+func (p GradientPlacer) Weights(width, height int) [][]float64 {
+	gradient := p.Gradient
+	if gradient == nil {
+		gradient = LinearGradient
+	}
+	floor := p.Floor
+	if floor <= 0 || floor > 1 {
+		floor = DefaultGradientFloor
+	}
+
+	ox, oy := 0.0, 0.0
+	if p.Origin != GradientCorner {
+		ox, oy = float64(width-1)/2, float64(height-1)/2
+	}
+	maxDist := math.Hypot(math.Max(ox, float64(width-1)-ox), math.Max(oy, float64(height-1)-oy))
+
+	weights := make([][]float64, width)
+	for x := 0; x < width; x++ {
+		weights[x] = make([]float64, height)
+		for y := 0; y < height; y++ {
+			t := 1.0
+			if maxDist > 0 {
+				t = 1 - math.Hypot(float64(x)-ox, float64(y)-oy)/maxDist
+			}
+			f := math.Min(math.Max(gradient(t), 0), 1)
+			weights[x][y] = floor + (1-floor)*f
+		}
+	}
+	return weights
+}
@@ -0,0 +1,100 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGradientPlacerStrategy(t *testing.T) {
+	tests := []struct {
+		placer   GradientPlacer
+		expected PlacementStrategy
+	}{
+		{GradientPlacer{}, PlacementCenterGradient},
+		{GradientPlacer{Origin: GradientCenter}, PlacementCenterGradient},
+		{GradientPlacer{Origin: GradientCorner}, PlacementCornerGradient},
+		{GradientPlacer{Name: "steep"}, "steep"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.placer.Strategy(); got != tt.expected {
+			t.Errorf("expected strategy %q, got %q", tt.expected, got)
+		}
+	}
+
+	for _, s := range []PlacementStrategy{PlacementCenterGradient, PlacementCornerGradient} {
+		if _, err := GetPlacer(s); err != nil {
+			t.Errorf("GetPlacer(%q) returned error: %v", s, err)
+		}
+	}
+}
+
+func TestGradientPlacerWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		placer   GradientPlacer
+		peak     game.Coordinate
+		farthest game.Coordinate
+	}{
+		{"center linear", GradientPlacer{Origin: GradientCenter}, game.Coordinate{X: 4, Y: 4}, game.Coordinate{X: 0, Y: 0}},
+		{"corner quadratic", GradientPlacer{Origin: GradientCorner, Gradient: QuadraticGradient}, game.Coordinate{X: 0, Y: 0}, game.Coordinate{X: 8, Y: 8}},
+		{"center exponential", GradientPlacer{Gradient: ExponentialGradient, Floor: 0.2}, game.Coordinate{X: 4, Y: 4}, game.Coordinate{X: 8, Y: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.placer.Weights(9, 9)
+			if w[tt.peak.X][tt.peak.Y] != 1 {
+				t.Errorf("expected weight 1 at origin, got %f", w[tt.peak.X][tt.peak.Y])
+			}
+			floor := tt.placer.Floor
+			if floor == 0 {
+				floor = DefaultGradientFloor
+			}
+			if got := w[tt.farthest.X][tt.farthest.Y]; got < floor-1e-9 || got > floor+1e-9 {
+				t.Errorf("expected floor weight %f at farthest cell, got %f", floor, got)
+			}
+			for x := range w {
+				for y := range w[x] {
+					if w[x][y] <= 0 || w[x][y] > 1 {
+						t.Errorf("weight at (%d,%d) out of range: %f", x, y, w[x][y])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGradientPlacerFavorsOrigin(t *testing.T) {
+	const size = 15
+	inner, outer := 0, 0
+	for seed := int64(0); seed < 20; seed++ {
+		state := game.NewGameState(size, seed)
+		GradientPlacer{Origin: GradientCenter}.Place(state, 40, rand.New(rand.NewSource(seed)))
+
+		if state.MineCount != 40 {
+			t.Fatalf("expected 40 mines, got %d", state.MineCount)
+		}
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				if !state.IsMine(x, y) {
+					continue
+				}
+				if abs(x-size/2) <= 3 && abs(y-size/2) <= 3 {
+					inner++
+				} else if x < 3 || y < 3 || x >= size-3 || y >= size-3 {
+					outer++
+				}
+			}
+		}
+	}
+
+	// Compare per-cell densities: 49 inner cells vs 144 outer-ring cells
+	innerDensity := float64(inner) / 49
+	outerDensity := float64(outer) / 144
+	if innerDensity <= 1.5*outerDensity {
+		t.Errorf("expected denser center, got inner=%.2f outer=%.2f mines per cell", innerDensity, outerDensity)
+	}
+}
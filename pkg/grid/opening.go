@@ -0,0 +1,89 @@
+package grid
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultOpeningRadius keeps the start cell and its 8 neighbors mine-free,
+// which guarantees the start cell is a zero cell triggering propagation.
+const DefaultOpeningRadius = 1
+
+// inOpening checks if (x, y) lies within the square of the given radius
+// (Chebyshev distance) around the start coordinate.
+func inOpening(start game.Coordinate, radius, x, y int) bool {
+	return abs(x-start.X) <= radius && abs(y-start.Y) <= radius
+}
+
+// openingCellCount returns the number of grid cells inside the opening area.
+func openingCellCount(width, height int, start game.Coordinate, radius int) int {
+	w := min(start.X+radius, width-1) - max(start.X-radius, 0) + 1
+	h := min(start.Y+radius, height-1) - max(start.Y-radius, 0) + 1
+	return w * h
+}
+
+// validateOpening checks that an opening area fits on the grid and leaves
+// enough room for all the mines.
+func validateOpening(width, height int, start game.Coordinate, radius, mineCount int) error {
+	if radius < 0 {
+		return fmt.Errorf("opening radius cannot be negative, got %d", radius)
+	}
+	if start.X < 0 || start.X >= width || start.Y < 0 || start.Y >= height {
+		return fmt.Errorf("opening %s is outside the %dx%d grid", start, width, height)
+	}
+	free := width*height - openingCellCount(width, height, start, radius)
+	if free < mineCount {
+		return fmt.Errorf("opening of radius %d around %s leaves %d cells for %d mines", radius, start, free, mineCount)
+	}
+	return nil
+}
+
+// clearOpening moves every mine inside the opening area to a random
+// mine-free cell outside of it, keeping the total mine count unchanged.
+func clearOpening(state *game.GameState, start game.Coordinate, radius int, rng *rand.Rand) {
+	width, height := state.Dimensions()
+
+	var displaced int
+	var candidates []game.Coordinate
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			inside := inOpening(start, radius, x, y)
+			switch {
+			case inside && state.IsMine(x, y):
+				state.ClearMine(x, y)
+				displaced++
+			case !inside && !state.IsMine(x, y):
+				candidates = append(candidates, game.Coordinate{X: x, Y: y})
+			}
+		}
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	for i := 0; i < displaced && i < len(candidates); i++ {
+		state.SetMine(candidates[i].X, candidates[i].Y)
+	}
+}
+
+// GenerateWithOpening creates a GameState using the given seed where every
+// cell within the configured OpeningRadius of start is mine-free.
+// The start coordinate is recorded in the state as the suggested first move.
+func (g *Generator) GenerateWithOpening(seed int64, start game.Coordinate) (*game.GameState, error) {
+	w, h := g.config.Dimensions()
+	if err := validateOpening(w, h, start, g.config.OpeningRadius, g.config.CalculateMineCount()); err != nil {
+		return nil, err
+	}
+
+	config := g.config
+	config.Opening = &start
+	gen := &Generator{config: config, seed: g.seed, rng: g.rng, placer: g.placer}
+	return gen.GenerateWithSeed(seed), nil
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
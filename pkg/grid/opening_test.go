@@ -0,0 +1,87 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGenerateWithOpening(t *testing.T) {
+	strategies := []PlacementStrategy{PlacementUniform, PlacementClustered, PlacementEdgeHeavy}
+
+	for _, s := range strategies {
+		t.Run(string(s), func(t *testing.T) {
+			config := Config{Size: 10, MineDensity: 0.30, MinMineCount: 1, Placement: s, OpeningRadius: DefaultOpeningRadius}
+			gen, err := NewGenerator(config)
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %v", err)
+			}
+
+			start := game.Coordinate{X: 4, Y: 6}
+			for seed := int64(1); seed <= 20; seed++ {
+				state, err := gen.GenerateWithOpening(seed, start)
+				if err != nil {
+					t.Fatalf("GenerateWithOpening failed: %v", err)
+				}
+				if state.AdjacentMines(start.X, start.Y) != 0 || state.IsMine(start.X, start.Y) {
+					t.Fatalf("seed %d: start cell is not a zero cell", seed)
+				}
+				if state.MineCount != config.CalculateMineCount() {
+					t.Fatalf("seed %d: expected %d mines, got %d", seed, config.CalculateMineCount(), state.MineCount)
+				}
+				if state.Opening == nil || *state.Opening != start {
+					t.Fatalf("seed %d: expected opening %s recorded in state", seed, start)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateWithOpeningLargerRadius(t *testing.T) {
+	config := Config{Size: 12, MineDensity: 0.20, MinMineCount: 1, OpeningRadius: 2}
+	gen, _ := NewGenerator(config)
+
+	start := game.Coordinate{X: 0, Y: 0}
+	state, err := gen.GenerateWithOpening(7, start)
+	if err != nil {
+		t.Fatalf("GenerateWithOpening failed: %v", err)
+	}
+	for x := 0; x <= 2; x++ {
+		for y := 0; y <= 2; y++ {
+			if state.IsMine(x, y) {
+				t.Errorf("unexpected mine at (%d,%d) inside the opening", x, y)
+			}
+		}
+	}
+}
+
+func TestGenerateWithOpeningErrors(t *testing.T) {
+	gen, _ := NewGenerator(Config{Size: 4, MineDensity: 0.50, MinMineCount: 1, OpeningRadius: 2})
+
+	if _, err := gen.GenerateWithOpening(1, game.Coordinate{X: 9, Y: 9}); err == nil {
+		t.Error("expected error for opening outside the grid")
+	}
+	// A 5x5 opening on a 4x4 board leaves no room for the 8 mines
+	if _, err := gen.GenerateWithOpening(1, game.Coordinate{X: 2, Y: 2}); err == nil {
+		t.Error("expected error when the opening leaves no room for mines")
+	}
+}
+
+func TestConfigOpeningIsApplied(t *testing.T) {
+	start := game.Coordinate{X: 5, Y: 5}
+	config := Config{Size: 10, MineDensity: 0.25, MinMineCount: 1, Opening: &start, OpeningRadius: 1}
+	gen, err := NewGenerator(config)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+
+	state := gen.Generate()
+	if state.AdjacentMines(5, 5) != 0 || state.IsMine(5, 5) {
+		t.Error("expected (5,5) to be a zero cell")
+	}
+
+	bad := Config{Size: 10, MineDensity: 0.25, OpeningRadius: -1}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative opening radius")
+	}
+}
@@ -0,0 +1,101 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	pattern := `
+		# two mines and an opening
+		*...
+		..o.
+		...*
+	`
+
+	state, err := ParsePattern(pattern)
+	if err != nil {
+		t.Fatalf("ParsePattern failed: %v", err)
+	}
+
+	w, h := state.Dimensions()
+	if w != 4 || h != 3 {
+		t.Fatalf("expected 4x3 board, got %dx%d", w, h)
+	}
+	if state.MineCount != 2 {
+		t.Errorf("expected 2 mines, got %d", state.MineCount)
+	}
+	if !state.IsMine(0, 0) || !state.IsMine(3, 2) {
+		t.Error("expected mines at (0,0) and (3,2)")
+	}
+	if state.Opening == nil || state.Opening.X != 2 || state.Opening.Y != 1 {
+		t.Errorf("expected opening at (2,1), got %v", state.Opening)
+	}
+	if state.Placement != string(PlacementPattern) {
+		t.Errorf("expected placement %q, got %q", PlacementPattern, state.Placement)
+	}
+}
+
+func TestParsePatternErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{"empty", "\n  \n# only a comment\n"},
+		{"ragged rows", "...\n..\n"},
+		{"invalid character", "..x\n...\n"},
+		{"two openings", "o.\n.o\n"},
+		{"no safe cell", "**\n**\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePattern(tt.pattern); err == nil {
+				t.Errorf("expected error for pattern %q", tt.pattern)
+			}
+		})
+	}
+}
+
+func TestToPatternRoundTrip(t *testing.T) {
+	pattern := "*..*\n.o..\n..*.\n"
+
+	state, err := ParsePattern(pattern)
+	if err != nil {
+		t.Fatalf("ParsePattern failed: %v", err)
+	}
+	if got := ToPattern(state); got != pattern {
+		t.Errorf("ToPattern() = %q, want %q", got, pattern)
+	}
+
+	generated, _ := GenerateGrid(8, 42, 0.2)
+	again, err := ParsePattern(ToPattern(generated))
+	if err != nil {
+		t.Fatalf("ParsePattern(ToPattern()) failed: %v", err)
+	}
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if generated.IsMine(x, y) != again.IsMine(x, y) {
+				t.Fatalf("round trip differs at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestGenerateFromPattern(t *testing.T) {
+	gen, _ := NewGenerator(Config{Size: 5, MineDensity: 0.15, MinMineCount: 1, MaxMineCount: 2})
+
+	state, err := gen.GenerateFromPattern("*..\n...\n..*\n", 77)
+	if err != nil {
+		t.Fatalf("GenerateFromPattern failed: %v", err)
+	}
+	if state.Seed != 77 {
+		t.Errorf("expected seed 77, got %d", state.Seed)
+	}
+
+	if _, err := gen.GenerateFromPattern("***\n...\n", 1); err == nil {
+		t.Error("expected error when exceeding the max mine count")
+	}
+	if _, err := gen.GenerateFromPattern("...\n...\n", 1); err == nil {
+		t.Error("expected error when below the min mine count")
+	}
+}
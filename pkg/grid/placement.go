@@ -0,0 +1,267 @@
+package grid
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// PlacementStrategy identifies a mine placement algorithm.
+type PlacementStrategy string
+
+const (
+	// PlacementUniform spreads mines uniformly at random (Fisher-Yates).
+	PlacementUniform PlacementStrategy = "uniform"
+	// PlacementClustered groups mines around a few random cluster centers.
+	PlacementClustered PlacementStrategy = "clustered"
+	// PlacementEdgeHeavy favors cells close to the borders of the grid.
+	PlacementEdgeHeavy PlacementStrategy = "edge-heavy"
+	// PlacementDiagonal concentrates mines on diagonal bands.
+	PlacementDiagonal PlacementStrategy = "diagonal"
+	// PlacementSymmetric mirrors mines across the vertical axis of the grid.
+	PlacementSymmetric PlacementStrategy = "symmetric"
+	// PlacementCenterGradient increases mine density toward the center.
+	PlacementCenterGradient PlacementStrategy = "center-gradient"
+	// PlacementCornerGradient increases mine density toward the top-left corner.
+	PlacementCornerGradient PlacementStrategy = "corner-gradient"
+)
+
+// MinePlacer places mines on an empty GameState.
+// Implementations must only use the provided RNG so that the same seed
+// always produces the same layout.
+type MinePlacer interface {
+	// Strategy returns the identifier recorded in the GameState.
+	Strategy() PlacementStrategy
+
+	// Place puts mineCount mines on the state.
+	Place(state *game.GameState, mineCount int, rng *rand.Rand)
+}
+
+var (
+	placersMu sync.RWMutex
+	placers   = map[PlacementStrategy]MinePlacer{}
+)
+
+func init() {
+	RegisterPlacer(UniformPlacer{})
+	RegisterPlacer(ClusteredPlacer{Clusters: 3})
+	RegisterPlacer(EdgeHeavyPlacer{})
+	RegisterPlacer(DiagonalPlacer{})
+	RegisterPlacer(SymmetricPlacer{})
+	RegisterPlacer(GradientPlacer{Origin: GradientCenter})
+	RegisterPlacer(GradientPlacer{Origin: GradientCorner})
+}
+
+// RegisterPlacer makes a placer available by its strategy name.
+// Registering a placer with an existing name replaces it.
+func RegisterPlacer(p MinePlacer) {
+	placersMu.Lock()
+	defer placersMu.Unlock()
+	placers[p.Strategy()] = p
+}
+
+// GetPlacer returns the placer registered for the given strategy.
+// An empty strategy selects the uniform placer.
+func GetPlacer(strategy PlacementStrategy) (MinePlacer, error) {
+	if strategy == "" {
+		strategy = PlacementUniform
+	}
+	placersMu.RLock()
+	defer placersMu.RUnlock()
+	p, ok := placers[strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown placement strategy %q", strategy)
+	}
+	return p, nil
+}
+
+// UniformPlacer places mines uniformly at random using a Fisher-Yates shuffle.
+type UniformPlacer struct{}
+
+// Strategy implements MinePlacer.
+func (UniformPlacer) Strategy() PlacementStrategy { return PlacementUniform }
+
+// Place implements MinePlacer.
+func (UniformPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	_, height := state.Dimensions()
+	totalCells := state.TotalCells()
+
+	// Create a slice of all possible positions
+	positions := make([]int, totalCells)
+	for i := 0; i < totalCells; i++ {
+		positions[i] = i
+	}
+
+	// Fisher-Yates shuffle
+	for i := len(positions) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		positions[i], positions[j] = positions[j], positions[i]
+	}
+
+	// Place mines at the first mineCount positions
+	for i := 0; i < mineCount && i < totalCells; i++ {
+		pos := positions[i]
+		state.SetMine(pos/height, pos%height)
+	}
+}
+
+// ClusteredPlacer groups mines around randomly chosen cluster centers.
+type ClusteredPlacer struct {
+	// Clusters is the number of cluster centers. Defaults to 3.
+	Clusters int
+}
+
+// Strategy implements MinePlacer.
+func (ClusteredPlacer) Strategy() PlacementStrategy { return PlacementClustered }
+
+// Place implements MinePlacer.
+func (p ClusteredPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	width, height := state.Dimensions()
+	clusters := p.Clusters
+	if clusters <= 0 {
+		clusters = 3
+	}
+
+	centers := make([]game.Coordinate, clusters)
+	for i := range centers {
+		centers[i] = game.Coordinate{X: rng.Intn(width), Y: rng.Intn(height)}
+	}
+
+	PlaceWeighted(state, mineCount, rng, func(x, y int) float64 {
+		nearest := math.MaxFloat64
+		for _, c := range centers {
+			d := math.Hypot(float64(x-c.X), float64(y-c.Y))
+			nearest = math.Min(nearest, d)
+		}
+		return 1 / (1 + nearest*nearest)
+	})
+}
+
+// EdgeHeavyPlacer favors cells close to the borders of the grid.
+type EdgeHeavyPlacer struct{}
+
+// Strategy implements MinePlacer.
+func (EdgeHeavyPlacer) Strategy() PlacementStrategy { return PlacementEdgeHeavy }
+
+// Place implements MinePlacer.
+func (EdgeHeavyPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	width, height := state.Dimensions()
+	PlaceWeighted(state, mineCount, rng, func(x, y int) float64 {
+		border := min(x, y, width-1-x, height-1-y)
+		return 1 / float64(1+border)
+	})
+}
+
+// DiagonalPlacer concentrates mines on bands parallel to the main diagonal.
+type DiagonalPlacer struct {
+	// Spacing is the distance between bands. Defaults to 4.
+	Spacing int
+}
+
+// Strategy implements MinePlacer.
+func (DiagonalPlacer) Strategy() PlacementStrategy { return PlacementDiagonal }
+
+// Place implements MinePlacer.
+func (p DiagonalPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	spacing := p.Spacing
+	if spacing <= 1 {
+		spacing = 4
+	}
+	PlaceWeighted(state, mineCount, rng, func(x, y int) float64 {
+		offset := (x - y) % spacing
+		if offset < 0 {
+			offset += spacing
+		}
+		// Distance to the nearest band
+		d := min(offset, spacing-offset)
+		return 1 / float64(1+3*d)
+	})
+}
+
+// SymmetricPlacer mirrors mines across the vertical axis (x -> width-1-x).
+// When the grid has no center column, an odd mine count is rounded down
+// to keep the layout symmetric.
+type SymmetricPlacer struct{}
+
+// Strategy implements MinePlacer.
+func (SymmetricPlacer) Strategy() PlacementStrategy { return PlacementSymmetric }
+
+// Place implements MinePlacer.
+func (SymmetricPlacer) Place(state *game.GameState, mineCount int, rng *rand.Rand) {
+	width, height := state.Dimensions()
+	half := width / 2
+
+	// Candidate slots: each left-half cell stands for a mirrored pair,
+	// center-column cells (odd widths) stand for a single mine.
+	var pairs, singles []game.Coordinate
+	for x := 0; x < half; x++ {
+		for y := 0; y < height; y++ {
+			pairs = append(pairs, game.Coordinate{X: x, Y: y})
+		}
+	}
+	if width%2 == 1 {
+		for y := 0; y < height; y++ {
+			singles = append(singles, game.Coordinate{X: half, Y: y})
+		}
+	}
+	rng.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	rng.Shuffle(len(singles), func(i, j int) { singles[i], singles[j] = singles[j], singles[i] })
+
+	remaining := mineCount
+	for _, c := range pairs {
+		if remaining < 2 {
+			break
+		}
+		state.SetMine(c.X, c.Y)
+		state.SetMine(width-1-c.X, c.Y)
+		remaining -= 2
+	}
+	for _, c := range singles {
+		if remaining < 1 {
+			break
+		}
+		state.SetMine(c.X, c.Y)
+		remaining--
+	}
+}
+
+// CellWeight returns the relative chance of the cell at (x, y) receiving a
+// mine. Weights are relative to each other and do not need to sum to 1.
+type CellWeight func(x, y int) float64
+
+// PlaceWeighted draws mineCount distinct cells, each with a probability
+// proportional to weight(x, y), using weighted sampling without replacement
+// (Efraimidis-Spirakis). Weights must be strictly positive.
+func PlaceWeighted(state *game.GameState, mineCount int, rng *rand.Rand, weight CellWeight) {
+	width, height := state.Dimensions()
+
+	type keyed struct {
+		c   game.Coordinate
+		key float64
+	}
+	cells := make([]keyed, 0, width*height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			w := weight(x, y)
+			if w <= 0 {
+				w = math.SmallestNonzeroFloat64
+			}
+			// Smallest -ln(u)/w wins, equivalent to the largest u^(1/w)
+			u := rng.Float64()
+			for u == 0 {
+				u = rng.Float64()
+			}
+			cells = append(cells, keyed{game.Coordinate{X: x, Y: y}, -math.Log(u) / w})
+		}
+	}
+
+	sort.SliceStable(cells, func(i, j int) bool { return cells[i].key < cells[j].key })
+
+	for i := 0; i < mineCount && i < len(cells); i++ {
+		state.SetMine(cells[i].c.X, cells[i].c.Y)
+	}
+}
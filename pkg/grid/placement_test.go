@@ -0,0 +1,148 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestGetPlacer(t *testing.T) {
+	strategies := []PlacementStrategy{
+		"",
+		PlacementUniform,
+		PlacementClustered,
+		PlacementEdgeHeavy,
+		PlacementDiagonal,
+		PlacementSymmetric,
+		PlacementCenterGradient,
+		PlacementCornerGradient,
+	}
+
+	for _, s := range strategies {
+		t.Run(string(s), func(t *testing.T) {
+			p, err := GetPlacer(s)
+			if err != nil {
+				t.Fatalf("GetPlacer(%q) returned error: %v", s, err)
+			}
+			if s != "" && p.Strategy() != s {
+				t.Errorf("expected strategy %q, got %q", s, p.Strategy())
+			}
+		})
+	}
+
+	if _, err := GetPlacer("bogus"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestPlacersPlaceRequestedMines(t *testing.T) {
+	strategies := []PlacementStrategy{
+		PlacementUniform,
+		PlacementClustered,
+		PlacementEdgeHeavy,
+		PlacementDiagonal,
+		PlacementSymmetric,
+		PlacementCenterGradient,
+		PlacementCornerGradient,
+	}
+
+	for _, s := range strategies {
+		t.Run(string(s), func(t *testing.T) {
+			p, _ := GetPlacer(s)
+			state := game.NewRectGameState(11, 8, 1)
+			p.Place(state, 20, rand.New(rand.NewSource(7)))
+
+			if state.MineCount != 20 {
+				t.Errorf("expected 20 mines, got %d", state.MineCount)
+			}
+		})
+	}
+}
+
+func TestPlacersAreReproducible(t *testing.T) {
+	for _, s := range []PlacementStrategy{PlacementClustered, PlacementEdgeHeavy, PlacementDiagonal, PlacementSymmetric} {
+		t.Run(string(s), func(t *testing.T) {
+			config := Config{Size: 12, MineDensity: 0.2, MinMineCount: 1, Placement: s}
+			gen, err := NewGenerator(config)
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %v", err)
+			}
+
+			a := gen.GenerateWithSeed(99)
+			b := gen.GenerateWithSeed(99)
+			for x := 0; x < 12; x++ {
+				for y := 0; y < 12; y++ {
+					if a.IsMine(x, y) != b.IsMine(x, y) {
+						t.Fatalf("layouts differ at (%d,%d)", x, y)
+					}
+				}
+			}
+			if a.Placement != string(s) {
+				t.Errorf("expected placement %q recorded in state, got %q", s, a.Placement)
+			}
+		})
+	}
+}
+
+func TestUniformPlacerMatchesDefaultGenerator(t *testing.T) {
+	// The default generator must keep producing the historical layouts
+	defaultGen, _ := NewGenerator(Config{Size: 10, MineDensity: 0.15, MinMineCount: 1})
+	uniformGen, _ := NewGenerator(Config{Size: 10, MineDensity: 0.15, MinMineCount: 1, Placement: PlacementUniform})
+
+	a := defaultGen.GenerateWithSeed(12345)
+	b := uniformGen.GenerateWithSeed(12345)
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if a.IsMine(x, y) != b.IsMine(x, y) {
+				t.Fatalf("layouts differ at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestSymmetricPlacerIsMirrored(t *testing.T) {
+	for _, width := range []int{10, 9} {
+		state := game.NewRectGameState(width, 6, 1)
+		SymmetricPlacer{}.Place(state, 13, rand.New(rand.NewSource(3)))
+
+		for x := 0; x < width; x++ {
+			for y := 0; y < 6; y++ {
+				if state.IsMine(x, y) != state.IsMine(width-1-x, y) {
+					t.Fatalf("width %d: mine at (%d,%d) is not mirrored", width, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgeHeavyPlacerFavorsBorders(t *testing.T) {
+	state := game.NewGameState(20, 1)
+	EdgeHeavyPlacer{}.Place(state, 60, rand.New(rand.NewSource(5)))
+
+	border, inner := 0, 0
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			if !state.IsMine(x, y) {
+				continue
+			}
+			if x < 3 || y < 3 || x > 16 || y > 16 {
+				border++
+			} else {
+				inner++
+			}
+		}
+	}
+
+	// The 3-cell border band holds 51% of the cells
+	if border <= inner {
+		t.Errorf("expected more mines near the border, got border=%d inner=%d", border, inner)
+	}
+}
+
+func TestConfigValidateUnknownPlacement(t *testing.T) {
+	config := Config{Size: 10, MineDensity: 0.15, Placement: "bogus"}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for unknown placement strategy")
+	}
+}
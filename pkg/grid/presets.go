@@ -0,0 +1,192 @@
+package grid
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultPresetsConfigMap is the name of the ConfigMap holding custom presets.
+const DefaultPresetsConfigMap = "podsweeper-presets"
+
+// builtinPresets lists the presets compiled into the Gamemaster.
+var builtinPresets = []DifficultyPreset{
+	DifficultyEasy,
+	DifficultyMedium,
+	DifficultyHard,
+	DifficultyExpert,
+	DifficultyWide,
+	DifficultyPanorama,
+}
+
+// PresetSpec is the serialized form of a preset, as stored in a ConfigMap.
+// Each key of the ConfigMap is a preset name and each value a YAML or JSON
+// document such as:
+//
+//	width: 24
+//	height: 12
+//	mineDensity: 0.18
+//	minMines: 30
+//	maxMines: 60
+//	placement: clustered
+//
+// Presets may also declare custom levels and variants with rules, see
+// package rules.
+type PresetSpec struct {
+	Size            int               `json:"size,omitempty"`
+	Width           int               `json:"width,omitempty"`
+	Height          int               `json:"height,omitempty"`
+	MineDensity     float64           `json:"mineDensity"`
+	MinMines        int               `json:"minMines,omitempty"`
+	MaxMines        int               `json:"maxMines,omitempty"`
+	Placement       PlacementStrategy `json:"placement,omitempty"`
+	OpeningRadius   int               `json:"openingRadius,omitempty"`
+	Rules           *game.Rules       `json:"rules,omitempty"`
+	RequireSolvable bool              `json:"requireSolvable,omitempty"`
+	Level           int               `json:"level,omitempty"`
+}
+
+// Config converts the spec to a validated generator Config.
+func (p PresetSpec) Config() (Config, error) {
+	config := Config{
+		Size:            p.Size,
+		Width:           p.Width,
+		Height:          p.Height,
+		MineDensity:     p.MineDensity,
+		MinMineCount:    p.MinMines,
+		MaxMineCount:    p.MaxMines,
+		Placement:       p.Placement,
+		OpeningRadius:   p.OpeningRadius,
+		Rules:           p.Rules,
+		RequireSolvable: p.RequireSolvable,
+		Level:           p.Level,
+	}
+	if config.MinMineCount == 0 {
+		config.MinMineCount = 1
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// ParsePresets parses ConfigMap data into preset configs.
+func ParsePresets(data map[string]string) (map[DifficultyPreset]Config, error) {
+	presets := make(map[DifficultyPreset]Config, len(data))
+	for name, raw := range data {
+		var spec PresetSpec
+		if err := yaml.UnmarshalStrict([]byte(raw), &spec); err != nil {
+			return nil, fmt.Errorf("preset %q: %w", name, err)
+		}
+		config, err := spec.Config()
+		if err != nil {
+			return nil, fmt.Errorf("preset %q: %w", name, err)
+		}
+		presets[DifficultyPreset(name)] = config
+	}
+	return presets, nil
+}
+
+// PresetRegistry resolves preset names to generator configs. It holds the
+// built-in presets and custom presets loaded at runtime, custom presets
+// taking precedence so operators can also tune the built-in ones.
+type PresetRegistry struct {
+	mu     sync.RWMutex
+	custom map[DifficultyPreset]Config
+}
+
+// NewPresetRegistry creates a registry with the built-in presets only.
+func NewPresetRegistry() *PresetRegistry {
+	return &PresetRegistry{custom: map[DifficultyPreset]Config{}}
+}
+
+// Get returns the config of a preset and whether it exists.
+func (r *PresetRegistry) Get(preset DifficultyPreset) (Config, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if config, ok := r.custom[preset]; ok {
+		return config, true
+	}
+	for _, p := range builtinPresets {
+		if p == preset {
+			return GetDifficultyConfig(preset), true
+		}
+	}
+	return Config{}, false
+}
+
+// Names returns all known preset names, sorted.
+func (r *PresetRegistry) Names() []DifficultyPreset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[DifficultyPreset]bool)
+	var names []DifficultyPreset
+	for _, p := range builtinPresets {
+		seen[p] = true
+		names = append(names, p)
+	}
+	for p := range r.custom {
+		if !seen[p] {
+			names = append(names, p)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// SetCustom replaces the custom presets.
+func (r *PresetRegistry) SetCustom(presets map[DifficultyPreset]Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.custom = make(map[DifficultyPreset]Config, len(presets))
+	for name, config := range presets {
+		r.custom[name] = config
+	}
+}
+
+// Set adds or replaces a custom preset.
+func (r *PresetRegistry) Set(preset DifficultyPreset, config Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.custom[preset] = config
+}
+
+// Delete removes a custom preset, restoring the built-in preset of that
+// name if any.
+func (r *PresetRegistry) Delete(preset DifficultyPreset) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.custom, preset)
+}
+
+// LoadFromConfigMap replaces the custom presets with the ones defined in the
+// given ConfigMap. A missing ConfigMap clears the custom presets. On parse
+// errors, the previous presets are kept.
+func (r *PresetRegistry) LoadFromConfigMap(ctx context.Context, c client.Reader, key client.ObjectKey) error {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			r.SetCustom(nil)
+			return nil
+		}
+		return fmt.Errorf("failed to get presets configmap: %w", err)
+	}
+
+	presets, err := ParsePresets(cm.Data)
+	if err != nil {
+		return fmt.Errorf("invalid presets configmap %s: %w", key, err)
+	}
+	r.SetCustom(presets)
+	return nil
+}
@@ -0,0 +1,143 @@
+package grid
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParsePresets(t *testing.T) {
+	presets, err := ParsePresets(map[string]string{
+		"workshop-small":  "size: 6\nmineDensity: 0.1\n",
+		"conference-huge": `{"width": 40, "height": 20, "mineDensity": 0.2, "placement": "clustered"}`,
+		"blitz":           "size: 8\nmineDensity: 0.1\nrules:\n  lose: clicks > 20\n  score: score * 2\n",
+	})
+	if err != nil {
+		t.Fatalf("ParsePresets failed: %v", err)
+	}
+
+	small := presets["workshop-small"]
+	if small.Size != 6 || small.MinMineCount != 1 {
+		t.Errorf("unexpected workshop-small config: %+v", small)
+	}
+	huge := presets["conference-huge"]
+	if w, h := huge.Dimensions(); w != 40 || h != 20 {
+		t.Errorf("expected 40x20, got %dx%d", w, h)
+	}
+	if huge.Placement != PlacementClustered {
+		t.Errorf("expected clustered placement, got %q", huge.Placement)
+	}
+	blitz := presets["blitz"]
+	if blitz.Rules == nil || blitz.Rules.Lose != "clicks > 20" || blitz.Rules.Score != "score * 2" {
+		t.Errorf("expected the blitz rules, got %+v", blitz.Rules)
+	}
+	gen, err := NewGenerator(blitz)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+	if state := gen.Generate(); state.Rules == nil || *state.Rules != *blitz.Rules {
+		t.Errorf("expected games to carry the rules, got %+v", state.Rules)
+	}
+}
+
+func TestParsePresetsErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid yaml":    "size: [",
+		"unknown field":   "size: 8\nmineDensity: 0.1\nbogus: 1\n",
+		"invalid density": "size: 8\nmineDensity: 0.9\n",
+		"invalid rule":    "size: 8\nmineDensity: 0.1\nrules:\n  lose: clicks +\n",
+		"mistyped rule":   "size: 8\nmineDensity: 0.1\nrules:\n  score: clicks > 3\n",
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePresets(map[string]string{"p": raw}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestPresetRegistry(t *testing.T) {
+	r := NewPresetRegistry()
+
+	if config, ok := r.Get(DifficultyHard); !ok || config.Size != 16 {
+		t.Errorf("expected built-in hard preset, got %+v (ok=%v)", config, ok)
+	}
+	if _, ok := r.Get("workshop-small"); ok {
+		t.Error("custom preset should not exist yet")
+	}
+
+	r.SetCustom(map[DifficultyPreset]Config{
+		"workshop-small": {Size: 6, MineDensity: 0.1, MinMineCount: 1},
+		DifficultyHard:   {Size: 12, MineDensity: 0.2, MinMineCount: 1},
+	})
+
+	if config, ok := r.Get("workshop-small"); !ok || config.Size != 6 {
+		t.Errorf("expected custom preset, got %+v (ok=%v)", config, ok)
+	}
+	if config, _ := r.Get(DifficultyHard); config.Size != 12 {
+		t.Errorf("expected custom override of hard preset, got size %d", config.Size)
+	}
+	if n := len(r.Names()); n != len(builtinPresets)+1 {
+		t.Errorf("expected %d preset names, got %d", len(builtinPresets)+1, n)
+	}
+
+	r.Set("vault", Config{Size: 20, MineDensity: 0.2, MinMineCount: 1, Level: 10})
+	if config, ok := r.Get("vault"); !ok || config.Level != 10 {
+		t.Errorf("expected the preset set, got %+v (ok=%v)", config, ok)
+	}
+	r.Delete("vault")
+	r.Delete(DifficultyHard)
+	if _, ok := r.Get("vault"); ok {
+		t.Error("deleted preset should not exist")
+	}
+	if config, _ := r.Get(DifficultyHard); config.Size != 16 {
+		t.Errorf("deleting an override should restore the built-in preset, got size %d", config.Size)
+	}
+}
+
+func TestPresetRegistry_LoadFromConfigMap(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultPresetsConfigMap, Namespace: "podsweeper-game"},
+		Data:       map[string]string{"tiny": "size: 4\nmineDensity: 0.2\n"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	key := client.ObjectKey{Namespace: "podsweeper-game", Name: DefaultPresetsConfigMap}
+
+	r := NewPresetRegistry()
+	if err := r.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if _, ok := r.Get("tiny"); !ok {
+		t.Error("expected tiny preset to be loaded")
+	}
+
+	// Invalid content keeps the previous presets
+	cm.Data["broken"] = "mineDensity: 2"
+	_ = c.Update(ctx, cm)
+	if err := r.LoadFromConfigMap(ctx, c, key); err == nil {
+		t.Error("expected error for invalid preset")
+	}
+	if _, ok := r.Get("tiny"); !ok {
+		t.Error("expected previous presets to be kept on error")
+	}
+
+	// A missing ConfigMap clears custom presets
+	_ = c.Delete(ctx, cm)
+	if err := r.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if _, ok := r.Get("tiny"); ok {
+		t.Error("expected custom presets to be cleared")
+	}
+}
@@ -0,0 +1,46 @@
+package grid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+)
+
+// MinSecureSeedLevel is the first level where seeds are drawn from
+// crypto/rand by default. From this level on, players are not supposed to
+// know the mine layout, so the seed must not be guessable from the
+// controller start time or previous games.
+const MinSecureSeedLevel = 1
+
+// SecureSeedForLevel returns whether seeds should come from crypto/rand
+// for the given level.
+func SecureSeedForLevel(level int) bool {
+	return level >= MinSecureSeedLevel
+}
+
+// NewSeed returns a new non-zero random seed. Secure seeds come from
+// crypto/rand; others come from the math/rand global source, which is fine
+// for casual games but predictable.
+func NewSeed(secure bool) (int64, error) {
+	if !secure {
+		for {
+			if seed := mathrand.Int63(); seed != 0 {
+				return seed, nil
+			}
+		}
+	}
+
+	var buf [8]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to read random seed: %w", err)
+		}
+		// Keep seeds positive so they survive JSON and flag round trips
+		seed := int64(binary.BigEndian.Uint64(buf[:]) & math.MaxInt64)
+		if seed != 0 {
+			return seed, nil
+		}
+	}
+}
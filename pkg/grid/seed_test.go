@@ -0,0 +1,60 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestNewSeed(t *testing.T) {
+	for _, secure := range []bool{false, true} {
+		seen := make(map[int64]bool)
+		for i := 0; i < 50; i++ {
+			seed, err := NewSeed(secure)
+			if err != nil {
+				t.Fatalf("NewSeed(%v) returned error: %v", secure, err)
+			}
+			if seed <= 0 {
+				t.Errorf("NewSeed(%v) = %d, want a positive seed", secure, seed)
+			}
+			seen[seed] = true
+		}
+		if len(seen) < 45 {
+			t.Errorf("NewSeed(%v) produced too many duplicates: %d unique of 50", secure, len(seen))
+		}
+	}
+}
+
+func TestSecureSeedForLevel(t *testing.T) {
+	if SecureSeedForLevel(0) {
+		t.Error("level 0 should not require secure seeds")
+	}
+	for level := 1; level <= 9; level++ {
+		if !SecureSeedForLevel(level) {
+			t.Errorf("level %d should require secure seeds", level)
+		}
+	}
+}
+
+func TestGeneratorRecordsResolvedSeed(t *testing.T) {
+	gen, err := NewGenerator(Config{Size: 8, MineDensity: 0.15, MinMineCount: 1, SecureSeed: true})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+	if gen.Seed() == 0 {
+		t.Fatal("expected a random seed to be drawn")
+	}
+
+	state := gen.Generate()
+	if state.Seed != gen.Seed() {
+		t.Errorf("expected state seed %d, got %d", gen.Seed(), state.Seed)
+	}
+
+	// The recorded seed reproduces the board
+	replay := gen.GenerateWithSeed(state.Seed)
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if state.IsMine(x, y) != replay.IsMine(x, y) {
+				t.Fatalf("replayed board differs at (%d,%d)", x, y)
+			}
+		}
+	}
+}
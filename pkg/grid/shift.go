@@ -0,0 +1,83 @@
+package grid
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// ShiftMine relocates a random hidden mine to a random hidden safe cell in
+// shifting mines mode. Both cells touch exactly the same revealed cells,
+// or lie at the same distance of the revealed cells up to two cells away
+// in hot/cold games, so every hint already shown stays true. Flagged cells, defused mines,
+// mines being defused, cells hiding a flag fragment and the kept cells
+// never change. The shift is drawn from the board seed and the shifts so
+// far, so that replays shift the same mines. Returns false if no mine can
+// move.
+func ShiftMine(state *game.GameState, keep ...game.Coordinate) (from, to game.Coordinate, ok bool) {
+	kept := make(map[game.Coordinate]bool, len(keep))
+	for _, c := range keep {
+		kept[c] = true
+	}
+
+	// Safe cells a mine can move to, by the revealed cells they touch.
+	// Cells are visited in grid order for the draw to be reproducible.
+	free := make(map[string][]game.Coordinate)
+	var mines []game.Coordinate
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			cell := state.Cells[x][y]
+			if kept[c] || cell.Revealed || cell.Flagged || cell.Defused {
+				continue
+			}
+			if !cell.Mine {
+				if _, fragment := state.FlagFragmentAt(x, y); !fragment {
+					key := revealedNeighbors(state, c)
+					free[key] = append(free[key], c)
+				}
+			} else if _, defusing := state.PendingDefusal(x, y); !defusing {
+				mines = append(mines, c)
+			}
+		}
+	}
+	var movable []game.Coordinate
+	for _, c := range mines {
+		if len(free[revealedNeighbors(state, c)]) > 0 {
+			movable = append(movable, c)
+		}
+	}
+	if len(movable) == 0 {
+		return game.Coordinate{}, game.Coordinate{}, false
+	}
+
+	rng := rand.New(rand.NewSource(state.Seed + int64(state.Shifts) + 1))
+	from = movable[rng.Intn(len(movable))]
+	targets := free[revealedNeighbors(state, from)]
+	to = targets[rng.Intn(len(targets))]
+	state.ClearMine(from.X, from.Y)
+	state.SetMine(to.X, to.Y)
+	state.Shifts++
+	return from, to, true
+}
+
+// revealedNeighbors returns a key identifying the revealed cells whose hint
+// depends on a mine at c, and their distance to c.
+func revealedNeighbors(state *game.GameState, c game.Coordinate) string {
+	radius := 1
+	if state.HotCold() {
+		radius = 2
+	}
+	var b strings.Builder
+	for dx := -radius; dx <= radius; dx++ {
+		for dy := -radius; dy <= radius; dy++ {
+			if (dx != 0 || dy != 0) && state.IsRevealed(c.X+dx, c.Y+dy) {
+				fmt.Fprintf(&b, "%d,%d@%d;", c.X+dx, c.Y+dy, max(abs(dx), abs(dy)))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestShiftMineKeepsHints(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	state := game.NewGameState(8, 42)
+	for state.MineCount < 10 {
+		state.SetMine(rng.Intn(8), rng.Intn(8))
+	}
+	// Reveal the safe cells of the left half
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 8; y++ {
+			if !state.IsMine(x, y) {
+				state.Reveal(x, y)
+			}
+		}
+	}
+	hints := state.Clone()
+	keep := game.Coordinate{X: 7, Y: 7}
+	keptMine := state.IsMine(keep.X, keep.Y)
+
+	shifted := 0
+	for range 50 {
+		from, to, ok := ShiftMine(state, keep)
+		if !ok {
+			break
+		}
+		shifted++
+		if state.IsMine(from.X, from.Y) || !state.IsMine(to.X, to.Y) {
+			t.Fatalf("expected the mine of %v to move to %v", from, to)
+		}
+		if from == keep || to == keep || state.IsMine(keep.X, keep.Y) != keptMine {
+			t.Fatalf("expected the kept cell to stay as it was, shifted %v to %v", from, to)
+		}
+		if state.MineCount != 10 {
+			t.Fatalf("expected 10 mines, got %d", state.MineCount)
+		}
+		for x := 0; x < 8; x++ {
+			for y := 0; y < 8; y++ {
+				if state.IsRevealed(x, y) && state.AdjacentMines(x, y) != hints.Cells[x][y].Hint {
+					t.Fatalf("shifting %v to %v changed the hint shown at (%d,%d)", from, to, x, y)
+				}
+			}
+		}
+	}
+	if shifted == 0 || state.Shifts != shifted {
+		t.Errorf("expected mines to shift, got %d shifts counted as %d", shifted, state.Shifts)
+	}
+}
+
+func TestShiftMineKeepsTemperatures(t *testing.T) {
+	state := game.NewGameState(9, 3)
+	state.HintType = game.HintHotCold
+	state.SetMine(8, 0)
+	state.SetMine(8, 8)
+	state.SetMine(4, 8)
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 9; y++ {
+			state.Reveal(x, y)
+		}
+	}
+	before := state.ToBoardString(game.WithPlayerView())
+	for range 20 {
+		if _, _, ok := ShiftMine(state); !ok {
+			break
+		}
+		if got := state.ToBoardString(game.WithPlayerView()); got != before {
+			t.Fatalf("expected the temperatures shown to stay the same, got\n%s\nwant\n%s", got, before)
+		}
+	}
+	if state.Shifts == 0 {
+		t.Error("expected mines to shift")
+	}
+}
+
+func TestShiftMineReproducible(t *testing.T) {
+	state := game.NewGameState(5, 9)
+	state.SetMine(3, 3)
+	state.SetMine(4, 0)
+	other := state.Clone()
+
+	for range 5 {
+		from1, to1, ok1 := ShiftMine(state)
+		from2, to2, ok2 := ShiftMine(other)
+		if !ok1 || !ok2 || from1 != from2 || to1 != to2 {
+			t.Fatalf("expected replays to shift the same mines, got %v>%v and %v>%v", from1, to1, from2, to2)
+		}
+	}
+}
+
+func TestShiftMineStuck(t *testing.T) {
+	// M .   no safe cell left to move to
+	full := game.NewRectGameState(2, 1, 1)
+	full.SetMine(0, 0)
+	full.Reveal(1, 0)
+
+	// F . . the only mine is flagged
+	flagged := game.NewRectGameState(3, 1, 1)
+	flagged.SetMine(0, 0)
+	flagged.SetFlag(0, 0, true)
+
+	// . M 1 . . the mine shows in the hint of (2,0), the safe cells don't
+	shown := game.NewRectGameState(5, 1, 1)
+	shown.SetMine(1, 0)
+	shown.Reveal(2, 0)
+	shown.Reveal(0, 0)
+
+	tests := []struct {
+		name  string
+		state *game.GameState
+	}{
+		{"no safe cell left", full},
+		{"flagged mine", flagged},
+		{"hints would change", shown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if from, to, ok := ShiftMine(tt.state); ok {
+				t.Errorf("expected no mine to move, shifted %v to %v", from, to)
+			}
+		})
+	}
+}
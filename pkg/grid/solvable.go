@@ -0,0 +1,39 @@
+package grid
+
+import (
+	"fmt"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// DefaultSolvableAttempts is the number of seeds GenerateWithSeed tries
+// before giving up on a solvable board.
+const DefaultSolvableAttempts = 1000
+
+// Solvable checks if the board of state can be cleared without guessing,
+// starting from FirstMove: its recorded opening if any. Progress in the
+// state is ignored.
+func Solvable(state *game.GameState) bool {
+	start, ok := FirstMove(state)
+	if !ok {
+		return false
+	}
+	result := NewSolver(pristineBoard(state)).Run(start)
+	return result.Solved && result.Guesses == 0
+}
+
+// GenerateSolvable generates boards starting from seed, trying consecutive
+// seeds until one is solvable. It returns an error if no board was within
+// maxAttempts.
+func (g *Generator) GenerateSolvable(seed int64, maxAttempts int) (*game.GameState, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	for i := 0; i < maxAttempts; i++ {
+		state := g.generateWithSeed(seed + int64(i))
+		if Solvable(state) {
+			return state, nil
+		}
+	}
+	return nil, fmt.Errorf("no solvable board after %d attempts", maxAttempts)
+}
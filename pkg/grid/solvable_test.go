@@ -0,0 +1,67 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestSolvable(t *testing.T) {
+	// A single mine in the corner is cleared by the opening
+	state := game.NewGameState(5, 1)
+	state.SetMine(4, 4)
+	if !Solvable(state) {
+		t.Error("expected a single corner mine to be solvable")
+	}
+
+	// Once the bottom is cleared, one of (0,0) and (1,0) is a mine
+	fiftyFifty := game.NewRectGameState(2, 4, 1)
+	fiftyFifty.SetMine(0, 0)
+	if Solvable(fiftyFifty) {
+		t.Error("expected a 50/50 not to be solvable")
+	}
+}
+
+func TestGenerateSolvable(t *testing.T) {
+	opening := game.Coordinate{X: 4, Y: 4}
+	config := Config{Size: 9, MineDensity: 0.2, MinMineCount: 1, Opening: &opening, OpeningRadius: 1}
+	gen, _ := NewGenerator(config)
+
+	state, err := gen.GenerateSolvable(1, DefaultSolvableAttempts)
+	if err != nil {
+		t.Fatalf("GenerateSolvable failed: %v", err)
+	}
+	if !Solvable(state) {
+		t.Error("expected a solvable board")
+	}
+
+	config.RequireSolvable = true
+	gen, _ = NewGenerator(config)
+	for seed := int64(1); seed <= 20; seed++ {
+		state := gen.GenerateWithSeed(seed)
+		if !Solvable(state) {
+			t.Fatalf("seed %d: expected a solvable board", seed)
+		}
+		// The recorded seed reproduces the board
+		again := gen.GenerateWithSeed(state.Seed)
+		for x := 0; x < state.Size; x++ {
+			for y := 0; y < state.Size; y++ {
+				if again.IsMine(x, y) != state.IsMine(x, y) {
+					t.Fatalf("seed %d: expected seed %d to reproduce the board", seed, state.Seed)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSolvableGivesUp(t *testing.T) {
+	// Opening a corner of a 2x2 board with a mine always leaves a guess
+	opening := game.Coordinate{X: 0, Y: 0}
+	gen, err := NewGenerator(Config{Size: 2, MineDensity: 0.25, MinMineCount: 1, Opening: &opening})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+	if _, err := gen.GenerateSolvable(1, 5); err == nil {
+		t.Error("expected no solvable board")
+	}
+}
@@ -0,0 +1,359 @@
+package grid
+
+import (
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// Solver deduces safe cells and mines from the revealed part of a board,
+// the way a careful player would. It only reads hint values of revealed
+// cells; mine positions are never consulted during deductions.
+type Solver struct {
+	state   *game.GameState
+	width   int
+	height  int
+	flagged [][]bool
+}
+
+// SolveResult describes a full solver run on a board.
+type SolveResult struct {
+	// Solved is true if every safe cell was revealed without hitting a mine.
+	Solved bool `json:"solved"`
+
+	// Guesses is the number of times the solver was stuck and had to
+	// reveal a cell it could not prove safe (the first click excluded).
+	Guesses int `json:"guesses"`
+
+	// GuessCells lists the cells revealed by guessing.
+	GuessCells []game.Coordinate `json:"guessCells,omitempty"`
+
+	// Steps is the number of deduction rounds performed.
+	Steps int `json:"steps"`
+
+	// MaxFrontier is the largest number of unrevealed cells bordering
+	// revealed hints observed while the solver was stuck.
+	MaxFrontier int `json:"maxFrontier"`
+}
+
+// constraint states that exactly mines of the cells are mines.
+type constraint struct {
+	cells []game.Coordinate
+	mines int
+}
+
+// NewSolver creates a solver working on a copy of the given state.
+// Cells already revealed in the state are used as starting knowledge.
+func NewSolver(state *game.GameState) *Solver {
+	clone := state.Clone()
+	w, h := clone.Dimensions()
+	flagged := make([][]bool, w)
+	for i := range flagged {
+		flagged[i] = make([]bool, h)
+	}
+	return &Solver{state: clone, width: w, height: h, flagged: flagged}
+}
+
+// State returns the solver's working copy of the board.
+func (s *Solver) State() *game.GameState {
+	return s.state
+}
+
+// IsFlagged returns true if the solver proved the cell is a mine.
+func (s *Solver) IsFlagged(x, y int) bool {
+	return s.state.IsValidCoordinate(x, y) && s.flagged[x][y]
+}
+
+// Reveal reveals a cell on the working copy, propagating through zero
+// cells like the Gamemaster does. It returns false if the cell is a mine.
+func (s *Solver) Reveal(c game.Coordinate) bool {
+	if s.state.IsMine(c.X, c.Y) {
+		s.state.Reveal(c.X, c.Y)
+		return false
+	}
+
+	queue := []game.Coordinate{c}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if !s.state.Reveal(cur.X, cur.Y) {
+			continue
+		}
+		if s.state.AdjacentMines(cur.X, cur.Y) == 0 {
+			for _, n := range s.state.GetNeighbors(cur.X, cur.Y) {
+				if !s.state.IsRevealed(n.X, n.Y) && !s.state.IsMine(n.X, n.Y) {
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Flag marks a cell known to be a mine, such as a mine hit, so later
+// deductions count it.
+func (s *Solver) Flag(c game.Coordinate) {
+	if s.state.IsValidCoordinate(c.X, c.Y) {
+		s.flagged[c.X][c.Y] = true
+	}
+}
+
+// FiftyFifty reports whether the unrevealed cell c is one of two cells
+// holding exactly one mine according to a revealed hint, the classic 50/50.
+// Other hints may still resolve it.
+func (s *Solver) FiftyFifty(c game.Coordinate) bool {
+	for _, cons := range s.constraints() {
+		if cons.mines == 1 && len(cons.cells) == 2 && (cons.cells[0] == c || cons.cells[1] == c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deduce returns the unrevealed cells that can be proven safe and the
+// cells that can be proven to be mines from the revealed hints.
+// Proven mines are also remembered as flags for later deductions.
+func (s *Solver) Deduce() (safe []game.Coordinate, mines []game.Coordinate) {
+	constraints := s.constraints()
+
+	safeSet := make(map[game.Coordinate]bool)
+	mineSet := make(map[game.Coordinate]bool)
+
+	// Single-point rules
+	for _, c := range constraints {
+		switch {
+		case c.mines == 0:
+			for _, cell := range c.cells {
+				safeSet[cell] = true
+			}
+		case c.mines == len(c.cells):
+			for _, cell := range c.cells {
+				mineSet[cell] = true
+			}
+		}
+	}
+
+	// Subset rules: if A's cells are included in B's, B\A holds B.mines-A.mines mines
+	if len(safeSet) == 0 && len(mineSet) == 0 {
+		for i, a := range constraints {
+			for j, b := range constraints {
+				if i == j || len(a.cells) >= len(b.cells) || !isSubset(a.cells, b.cells) {
+					continue
+				}
+				rest := difference(b.cells, a.cells)
+				diff := b.mines - a.mines
+				switch {
+				case diff == 0:
+					for _, cell := range rest {
+						safeSet[cell] = true
+					}
+				case diff == len(rest):
+					for _, cell := range rest {
+						mineSet[cell] = true
+					}
+				}
+			}
+		}
+	}
+
+	for x := 0; x < s.width; x++ {
+		for y := 0; y < s.height; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			if safeSet[c] {
+				safe = append(safe, c)
+			}
+			if mineSet[c] {
+				mines = append(mines, c)
+				s.flagged[x][y] = true
+			}
+		}
+	}
+	return safe, mines
+}
+
+// Frontier returns the unrevealed, unflagged cells adjacent to a revealed cell.
+func (s *Solver) Frontier() []game.Coordinate {
+	var frontier []game.Coordinate
+	for x := 0; x < s.width; x++ {
+		for y := 0; y < s.height; y++ {
+			if s.state.IsRevealed(x, y) || s.flagged[x][y] {
+				continue
+			}
+			for _, n := range s.state.GetNeighbors(x, y) {
+				if s.state.IsRevealed(n.X, n.Y) {
+					frontier = append(frontier, game.Coordinate{X: x, Y: y})
+					break
+				}
+			}
+		}
+	}
+	return frontier
+}
+
+// Run plays the whole board starting from start. When no deduction is
+// possible, it reveals a safe cell using knowledge of the mine positions and
+// counts it as a forced guess, so the result measures how much luck a human
+// player would need.
+func (s *Solver) Run(start game.Coordinate) SolveResult {
+	var result SolveResult
+
+	if !s.Reveal(start) {
+		return result
+	}
+
+	for !s.state.CheckVictory() {
+		result.Steps++
+		safe, mines := s.Deduce()
+		if len(safe) > 0 {
+			for _, c := range safe {
+				s.Reveal(c)
+			}
+			continue
+		}
+		if len(mines) > 0 {
+			continue
+		}
+
+		// Stuck: a player would have to guess
+		frontier := s.Frontier()
+		if len(frontier) > result.MaxFrontier {
+			result.MaxFrontier = len(frontier)
+		}
+		guess, ok := s.guess(frontier)
+		if !ok {
+			return result
+		}
+		result.Guesses++
+		result.GuessCells = append(result.GuessCells, guess)
+		s.Reveal(guess)
+	}
+
+	result.Solved = true
+	return result
+}
+
+// guess picks a safe cell to reveal when stuck, preferring the frontier.
+func (s *Solver) guess(frontier []game.Coordinate) (game.Coordinate, bool) {
+	for _, c := range frontier {
+		if !s.state.IsMine(c.X, c.Y) {
+			return c, true
+		}
+	}
+	for x := 0; x < s.width; x++ {
+		for y := 0; y < s.height; y++ {
+			if !s.state.IsRevealed(x, y) && !s.state.IsMine(x, y) {
+				return game.Coordinate{X: x, Y: y}, true
+			}
+		}
+	}
+	return game.Coordinate{}, false
+}
+
+// constraints builds one constraint per revealed hint cell bordering
+// unknown cells, with already flagged mines subtracted.
+func (s *Solver) constraints() []constraint {
+	var constraints []constraint
+	for x := 0; x < s.width; x++ {
+		for y := 0; y < s.height; y++ {
+			if !s.state.IsRevealed(x, y) || s.state.IsMine(x, y) {
+				continue
+			}
+			c := constraint{mines: s.state.AdjacentMines(x, y)}
+			for _, n := range s.state.GetNeighbors(x, y) {
+				switch {
+				case s.flagged[n.X][n.Y]:
+					c.mines--
+				case !s.state.IsRevealed(n.X, n.Y):
+					c.cells = append(c.cells, n)
+				}
+			}
+			if len(c.cells) > 0 {
+				constraints = append(constraints, c)
+			}
+		}
+	}
+	return constraints
+}
+
+// FirstMove returns the cell a solver starts from: the recorded opening if
+// any, otherwise the zero cell opening the largest area, otherwise the first
+// safe cell.
+func FirstMove(state *game.GameState) (game.Coordinate, bool) {
+	if state.Opening != nil {
+		return *state.Opening, true
+	}
+
+	w, h := state.Dimensions()
+	best, bestSize := game.Coordinate{}, 0
+	var firstSafe *game.Coordinate
+	seen := make(map[game.Coordinate]bool)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := game.Coordinate{X: x, Y: y}
+			if state.IsMine(x, y) {
+				continue
+			}
+			if firstSafe == nil {
+				firstSafe = &c
+			}
+			if seen[c] || state.AdjacentMines(x, y) != 0 {
+				continue
+			}
+			size := len(zeroRegion(state, c, seen))
+			if size > bestSize {
+				best, bestSize = c, size
+			}
+		}
+	}
+	if bestSize > 0 {
+		return best, true
+	}
+	if firstSafe != nil {
+		return *firstSafe, true
+	}
+	return game.Coordinate{}, false
+}
+
+// zeroRegion returns the connected zero cells containing start, marking them in seen.
+func zeroRegion(state *game.GameState, start game.Coordinate, seen map[game.Coordinate]bool) []game.Coordinate {
+	var region []game.Coordinate
+	queue := []game.Coordinate{start}
+	seen[start] = true
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		region = append(region, cur)
+		for _, n := range state.GetNeighbors(cur.X, cur.Y) {
+			if !seen[n] && !state.IsMine(n.X, n.Y) && state.AdjacentMines(n.X, n.Y) == 0 {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return region
+}
+
+func isSubset(a, b []game.Coordinate) bool {
+	set := make(map[game.Coordinate]bool, len(b))
+	for _, c := range b {
+		set[c] = true
+	}
+	for _, c := range a {
+		if !set[c] {
+			return false
+		}
+	}
+	return true
+}
+
+func difference(b, a []game.Coordinate) []game.Coordinate {
+	set := make(map[game.Coordinate]bool, len(a))
+	for _, c := range a {
+		set[c] = true
+	}
+	var rest []game.Coordinate
+	for _, c := range b {
+		if !set[c] {
+			rest = append(rest, c)
+		}
+	}
+	return rest
+}
@@ -0,0 +1,135 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestSolverDeduceSinglePoint(t *testing.T) {
+	// 3x3 with a single mine in the corner:
+	// . . .
+	// . . .
+	// . . M
+	state := game.NewGameState(3, 1)
+	state.SetMine(2, 2)
+
+	solver := NewSolver(state)
+	solver.Reveal(game.Coordinate{X: 0, Y: 0})
+
+	safe, _ := solver.Deduce()
+	if len(safe) != 0 {
+		// (0,0) is a zero cell: propagation already revealed all safe cells
+		t.Errorf("expected no remaining safe cells, got %v", safe)
+	}
+	if !solver.State().CheckVictory() {
+		t.Error("expected board to be cleared by the opening")
+	}
+}
+
+func TestSolverDeduceMines(t *testing.T) {
+	// 2x2 with 3 mines: revealing (1,1) shows 3, so all neighbors are mines
+	state := game.NewGameState(2, 1)
+	state.SetMine(0, 0)
+	state.SetMine(0, 1)
+	state.SetMine(1, 0)
+
+	solver := NewSolver(state)
+	solver.Reveal(game.Coordinate{X: 1, Y: 1})
+
+	_, mines := solver.Deduce()
+	if len(mines) != 3 {
+		t.Errorf("expected 3 proven mines, got %v", mines)
+	}
+	if !solver.IsFlagged(0, 0) {
+		t.Error("expected (0,0) to be flagged")
+	}
+}
+
+func TestSolverRunSolvesOpenBoard(t *testing.T) {
+	state := game.NewGameState(5, 1)
+	state.SetMine(4, 4)
+
+	result := NewSolver(state).Run(game.Coordinate{X: 0, Y: 0})
+	if !result.Solved {
+		t.Fatal("expected board to be solved")
+	}
+	if result.Guesses != 0 {
+		t.Errorf("expected no guesses, got %d", result.Guesses)
+	}
+}
+
+func TestSolverRunCountsFiftyFifty(t *testing.T) {
+	// Classic 50/50 on a 2x4 board: once the bottom is cleared, the
+	// hints only tell that one of (0,0) and (1,0) is a mine.
+	state := game.NewRectGameState(2, 4, 1)
+	state.SetMine(0, 0)
+
+	result := NewSolver(state).Run(game.Coordinate{X: 1, Y: 3})
+	if !result.Solved {
+		t.Fatal("expected board to be solved with guessing")
+	}
+	if result.Guesses == 0 {
+		t.Error("expected at least one forced guess for a 50/50")
+	}
+}
+
+func TestSolverFiftyFifty(t *testing.T) {
+	state := game.NewRectGameState(2, 4, 1)
+	state.SetMine(0, 0)
+
+	solver := NewSolver(state)
+	solver.Reveal(game.Coordinate{X: 1, Y: 3})
+	if !solver.FiftyFifty(game.Coordinate{X: 0, Y: 0}) || !solver.FiftyFifty(game.Coordinate{X: 1, Y: 0}) {
+		t.Error("expected (0,0) and (1,0) to be a 50/50")
+	}
+	if solver.FiftyFifty(game.Coordinate{X: 1, Y: 3}) {
+		t.Error("expected a revealed cell not to be a 50/50")
+	}
+}
+
+func TestSolverFlag(t *testing.T) {
+	// M 1 .: the hint can't tell which side holds the mine until it is hit
+	state := game.NewRectGameState(3, 1, 1)
+	state.SetMine(0, 0)
+
+	solver := NewSolver(state)
+	solver.Reveal(game.Coordinate{X: 1, Y: 0})
+	if safe, _ := solver.Deduce(); len(safe) != 0 {
+		t.Fatalf("expected nothing proven safe yet, got %v", safe)
+	}
+	solver.Flag(game.Coordinate{X: 0, Y: 0})
+	safe, _ := solver.Deduce()
+	if len(safe) != 1 || safe[0] != (game.Coordinate{X: 2, Y: 0}) {
+		t.Errorf("expected (2,0) proven safe once (0,0) is flagged, got %v", safe)
+	}
+}
+
+func TestSolverDoesNotMutateInput(t *testing.T) {
+	state := game.NewGameState(4, 1)
+	state.SetMine(3, 3)
+
+	NewSolver(state).Run(game.Coordinate{X: 0, Y: 0})
+	if state.IsRevealed(0, 0) {
+		t.Error("solver must work on a copy of the state")
+	}
+}
+
+func TestFirstMove(t *testing.T) {
+	state := game.NewGameState(5, 1)
+	state.SetMine(0, 0)
+
+	start, ok := FirstMove(state)
+	if !ok {
+		t.Fatal("expected a first move")
+	}
+	if state.AdjacentMines(start.X, start.Y) != 0 {
+		t.Errorf("expected first move %s to be a zero cell", start)
+	}
+
+	opening := game.Coordinate{X: 3, Y: 3}
+	state.Opening = &opening
+	if start, _ := FirstMove(state); start != opening {
+		t.Errorf("expected recorded opening %s, got %s", opening, start)
+	}
+}
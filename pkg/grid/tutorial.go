@@ -0,0 +1,59 @@
+package grid
+
+import (
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// maxSuggestRounds bounds the deduction rounds looking for a safe cell.
+// Proving mines in a round can unlock safe cells in the next one.
+const maxSuggestRounds = 3
+
+// Suggestion is a move proposed to a tutorial player.
+type Suggestion struct {
+	// Cell is the suggested cell to reveal. It is always safe.
+	Cell game.Coordinate
+
+	// Opening is true if nothing is revealed yet.
+	Opening bool
+
+	// Proven is true if the revealed hints prove the cell safe. Otherwise
+	// a careful player would have had to guess.
+	Proven bool
+
+	// Mines lists the cells the revealed hints prove to be mines.
+	Mines []game.Coordinate
+}
+
+// SuggestMove proposes the next cell for a tutorial player: the opening if
+// nothing is revealed yet, then a cell proven safe by the hints, and when
+// none can be proven, a safe cell anyway so that beginners are not stuck.
+// Returns false if the game has no safe cell left.
+func SuggestMove(state *game.GameState) (Suggestion, bool) {
+	if state.Stats().RevealedCells == 0 {
+		c, ok := FirstMove(state)
+		return Suggestion{Cell: c, Opening: true}, ok
+	}
+
+	solver := NewSolver(state)
+	var mines []game.Coordinate
+	for round := 0; round < maxSuggestRounds; round++ {
+		safe, proven := solver.Deduce()
+		mines = append(mines, proven...)
+		if len(safe) > 0 {
+			return Suggestion{Cell: safe[0], Proven: true, Mines: mines}, true
+		}
+		if len(proven) == 0 {
+			break
+		}
+	}
+
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if !state.IsMine(x, y) && !state.IsRevealed(x, y) {
+				return Suggestion{Cell: game.Coordinate{X: x, Y: y}, Mines: mines}, true
+			}
+		}
+	}
+	return Suggestion{}, false
+}
@@ -0,0 +1,65 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestSuggestMoveOpening(t *testing.T) {
+	state := game.NewGameState(5, 1)
+	state.SetMine(4, 4)
+
+	s, ok := SuggestMove(state)
+	if !ok || !s.Opening {
+		t.Fatalf("expected an opening suggestion, got %+v", s)
+	}
+	if state.IsMine(s.Cell.X, s.Cell.Y) {
+		t.Errorf("suggested opening %v is a mine", s.Cell)
+	}
+}
+
+func TestSuggestMoveProven(t *testing.T) {
+	// . . M with (0,0) revealed as a zero: (1,0) is proven safe
+	state := game.NewRectGameState(3, 1, 1)
+	state.SetMine(2, 0)
+	state.Reveal(0, 0)
+
+	s, ok := SuggestMove(state)
+	if !ok {
+		t.Fatal("expected a suggestion")
+	}
+	if !s.Proven || s.Cell != (game.Coordinate{X: 1, Y: 0}) {
+		t.Errorf("expected proven safe (1,0), got %+v", s)
+	}
+}
+
+func TestSuggestMoveGuess(t *testing.T) {
+	// (1,1) shows 1 with three hidden neighbors: nothing can be proven
+	state := game.NewGameState(2, 1)
+	state.SetMine(0, 0)
+	state.Reveal(1, 1)
+
+	s, ok := SuggestMove(state)
+	if !ok {
+		t.Fatal("expected a suggestion")
+	}
+	if s.Proven || s.Opening {
+		t.Errorf("expected an unproven suggestion, got %+v", s)
+	}
+	if state.IsMine(s.Cell.X, s.Cell.Y) || state.IsRevealed(s.Cell.X, s.Cell.Y) {
+		t.Errorf("expected a hidden safe cell, got %v", s.Cell)
+	}
+}
+
+func TestSuggestMoveCleared(t *testing.T) {
+	state := game.NewGameState(2, 1)
+	state.SetMine(0, 0)
+	state.Reveal(0, 1)
+	state.Reveal(1, 0)
+	state.Reveal(1, 1)
+
+	if s, ok := SuggestMove(state); ok {
+		t.Errorf("expected no suggestion on a cleared board, got %+v", s)
+	}
+}
@@ -0,0 +1,168 @@
+// Package hints implements the hint aggregator: instead of one hint pod per
+// revealed cell, a single Deployment serves every hint over HTTP at
+// /hint/X/Y, from a ConfigMap the Gamemaster keeps up to date. It keeps
+// the pod count of very large boards down to one pod per hidden cell. It
+// also scrambles the hints of hint pods at higher levels, see Scramble.
+package hints
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+const (
+	// Name names the aggregator Deployment, its Service and the ConfigMap
+	// holding the hints.
+	Name = "podsweeper-hint-aggregator"
+
+	// MountPath is where the hints ConfigMap is mounted in the aggregator.
+	MountPath = "/etc/podsweeper/hints"
+
+	// Port is the port the aggregator listens on.
+	Port = 8080
+
+	// HeaderFlagFragment carries the CTF flag fragment hidden in a cell,
+	// which hint pods show as an annotation.
+	HeaderFlagFragment = "X-Podsweeper-Flag-Fragment"
+
+	// flagSuffix marks the ConfigMap keys of flag fragments.
+	flagSuffix = ".flag"
+)
+
+// Key returns the ConfigMap key of the hint of cell (x, y).
+func Key(x, y int) string {
+	return fmt.Sprintf("%d-%d", x, y)
+}
+
+// Values returns the ConfigMap data serving the hints of state: the hint
+// of every revealed cell showing one, like the hint pods it
+// replaces, in the theme of the game, and the flag fragments they would
+// show. Lost games have no hints, as their hint pods are wiped, and hints
+// decayed as of now are dropped, like their hint pods.
+func Values(state *game.GameState, now time.Time) map[string]string {
+	data := make(map[string]string)
+	if state == nil || state.Status == game.StatusLost {
+		return data
+	}
+	t := theme.Of(state.Theme)
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			hint, shown := state.HintAt(x, y)
+			if !state.Cells[x][y].Revealed || !shown || state.HintDecayed(x, y, now) {
+				continue
+			}
+			data[Key(x, y)] = t.Hint(hint)
+			if fragment, ok := state.FlagFragmentAt(x, y); ok {
+				data[Key(x, y)+flagSuffix] = fragment.String()
+			}
+		}
+	}
+	return data
+}
+
+// Handler serves the hints mounted in dir at /hint/X/Y. Cells without a
+// hint, hidden or empty, are not found.
+func Handler(dir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hint/{x}/{y}", func(w http.ResponseWriter, r *http.Request) {
+		x, errX := strconv.Atoi(r.PathValue("x"))
+		y, errY := strconv.Atoi(r.PathValue("y"))
+		if errX != nil || errY != nil || x < 0 || y < 0 {
+			http.Error(w, "coordinates must be non-negative integers", http.StatusBadRequest)
+			return
+		}
+
+		value, err := os.ReadFile(filepath.Join(dir, Key(x, y)))
+		if errors.Is(err, fs.ErrNotExist) {
+			http.Error(w, "no hint here", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read hint", http.StatusInternalServerError)
+			return
+		}
+		if fragment, err := os.ReadFile(filepath.Join(dir, Key(x, y)+flagSuffix)); err == nil {
+			w.Header().Set(HeaderFlagFragment, strings.TrimSpace(string(fragment)))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "%s\n", strings.TrimSpace(string(value)))
+	})
+	return mux
+}
+
+// Objects returns the aggregator Deployment and Service. The Gamemaster
+// creates the ConfigMap on the first move; until then the volume is empty.
+// Aggregator pods are hint pods, so the NetworkPolicies of higher levels
+// guard them too.
+func Objects(namespace, image string, labels map[string]string) []client.Object {
+	podLabels := map[string]string{
+		spawner.LabelApp:       "podsweeper",
+		spawner.LabelComponent: "hint",
+	}
+	meta := metav1.ObjectMeta{Name: Name, Namespace: namespace, Labels: labels}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					SecurityContext: spawner.RestrictedPodSecurityContext(),
+					Containers: []corev1.Container{{
+						Name:            "hint-aggregator",
+						Image:           image,
+						SecurityContext: spawner.RestrictedSecurityContext(),
+						Env: []corev1.EnvVar{
+							{Name: "HINTS_DIR", Value: MountPath},
+							{Name: "PORT", Value: strconv.Itoa(Port)},
+						},
+						Ports:        []corev1.ContainerPort{{ContainerPort: Port, Protocol: corev1.ProtocolTCP}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "hints", MountPath: MountPath, ReadOnly: true}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "hints",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: Name},
+							Optional:             ptr.To(true),
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Selector: podLabels,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt32(Port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	return []client.Object{deployment, service}
+}
@@ -0,0 +1,99 @@
+package hints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func TestValues(t *testing.T) {
+	state := game.NewGameState(3, 1)
+	state.SetMine(1, 1)
+	state.Reveal(0, 0)
+	state.Reveal(1, 1)
+
+	data := Values(state, time.Now())
+	if len(data) != 1 || data[Key(0, 0)] != "1" {
+		t.Errorf("expected only the hint of (0,0), got %v", data)
+	}
+
+	state.Theme = "emoji"
+	if data := Values(state, time.Now()); data[Key(0, 0)] != "1️⃣" {
+		t.Errorf("expected the hint in the game theme, got %v", data)
+	}
+
+	state.HintDecay = time.Minute
+	if data := Values(state, time.Now().Add(time.Minute)); len(data) != 0 {
+		t.Errorf("expected decayed hints to be dropped, got %v", data)
+	}
+
+	state.SetLost()
+	if data := Values(state, time.Now()); len(data) != 0 {
+		t.Errorf("expected no hints for a lost game, got %v", data)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, Key(0, 0)), []byte("1"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, Key(2, 3)), []byte("2"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, Key(2, 3)+flagSuffix), []byte("1/3:abc"), 0o644)
+	handler := Handler(dir)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+		wantFlag string
+	}{
+		{"/hint/0/0", http.StatusOK, "1", ""},
+		{"/hint/2/3", http.StatusOK, "2", "1/3:abc"},
+		{"/hint/1/1", http.StatusNotFound, "", ""},
+		{"/hint/x/0", http.StatusBadRequest, "", ""},
+		{"/hint/-1/0", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.wantCode, rec.Code)
+			continue
+		}
+		if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.wantBody, rec.Body.String())
+		}
+		if got := rec.Header().Get(HeaderFlagFragment); got != tt.wantFlag {
+			t.Errorf("%s: expected flag fragment %q, got %q", tt.path, tt.wantFlag, got)
+		}
+	}
+}
+
+func TestObjects(t *testing.T) {
+	objs := Objects("game", "hint-agent:test", nil)
+	if len(objs) != 2 {
+		t.Fatalf("expected a Deployment and a Service, got %d objects", len(objs))
+	}
+	deployment, ok := objs[0].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("expected a Deployment, got %T", objs[0])
+	}
+	spec := deployment.Spec.Template.Spec
+	if v := spawner.RestrictedViolations(&spec); len(v) > 0 {
+		t.Errorf("expected the aggregator to be restricted, got %v", v)
+	}
+	if got := deployment.Spec.Template.Labels[spawner.LabelComponent]; got != "hint" {
+		t.Errorf("expected aggregator pods to be hint pods for the level NetworkPolicies, got %q", got)
+	}
+	if spec.Volumes[0].ConfigMap.Name != Name {
+		t.Errorf("expected the hints ConfigMap to be mounted, got %+v", spec.Volumes)
+	}
+}
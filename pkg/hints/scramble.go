@@ -0,0 +1,148 @@
+package hints
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ScrambleKeySecret names the Secret holding the key hints are
+	// scrambled with. Players can't read Secrets, so only hint agents,
+	// given the key by the kubelet, can unscramble hints.
+	ScrambleKeySecret = "podsweeper-hint-key"
+
+	// ScrambleKeyName is the key of the scramble key in ScrambleKeySecret.
+	ScrambleKeyName = "key"
+
+	// ScrambleKeyEnv is the environment variable giving hint agents the
+	// scramble key.
+	ScrambleKeyEnv = "HINT_KEY"
+
+	// scrambledPrefix marks scrambled hints.
+	scrambledPrefix = "scrambled:"
+
+	// scrambleKeySize is the number of random bytes of scramble keys.
+	scrambleKeySize = 32
+)
+
+// NewScrambleKey returns a random scramble key, hex encoded so that it can
+// be passed in an environment variable.
+func NewScrambleKey() ([]byte, error) {
+	key := make([]byte, scrambleKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate scramble key: %w", err)
+	}
+	return []byte(hex.EncodeToString(key)), nil
+}
+
+// Scramble encrypts hint with key. The same hint scrambles differently
+// every time, so players can't tell hints apart by comparing them.
+func Scramble(key []byte, hint string) (string, error) {
+	gcm, err := scrambleCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to scramble hint: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(hint), nil)
+	return scrambledPrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Unscramble decrypts a hint scrambled with key. Hints that aren't
+// scrambled are returned as is.
+func Unscramble(key []byte, value string) (string, error) {
+	data, ok := strings.CutPrefix(value, scrambledPrefix)
+	if !ok {
+		return value, nil
+	}
+	gcm, err := scrambleCipher(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid scrambled hint")
+	}
+	hint, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to unscramble hint: %w", err)
+	}
+	return string(hint), nil
+}
+
+// IsScrambled reports whether value is a scrambled hint.
+func IsScrambled(value string) bool {
+	return strings.HasPrefix(value, scrambledPrefix)
+}
+
+// scrambleCipher returns the AES-256-GCM cipher of key.
+func scrambleCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, errors.New("missing scramble key")
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid scramble key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// LoadScrambleKey returns the scramble key of the namespace, creating its
+// Secret with a new key if missing.
+func LoadScrambleKey(ctx context.Context, c client.Client, namespace string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ScrambleKeySecret}, secret)
+	if err == nil {
+		if key := secret.Data[ScrambleKeyName]; len(key) > 0 {
+			return key, nil
+		}
+		return nil, fmt.Errorf("secret %s has no %q key", ScrambleKeySecret, ScrambleKeyName)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get secret %s: %w", ScrambleKeySecret, err)
+	}
+
+	key, err := NewScrambleKey()
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ScrambleKeySecret, Namespace: namespace},
+		Data:       map[string][]byte{ScrambleKeyName: key},
+	}
+	if err := c.Create(ctx, secret); apierrors.IsAlreadyExists(err) {
+		// Created by another Gamemaster in the meantime
+		return LoadScrambleKey(ctx, c, namespace)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create secret %s: %w", ScrambleKeySecret, err)
+	}
+	return key, nil
+}
+
+// ScrambleKeyEnvVar returns the ScrambleKeyEnv environment variable of hint
+// agents, read by the kubelet from ScrambleKeySecret.
+func ScrambleKeyEnvVar() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: ScrambleKeyEnv,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: ScrambleKeySecret},
+			Key:                  ScrambleKeyName,
+		}},
+	}
+}
@@ -0,0 +1,53 @@
+package hints
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScramble(t *testing.T) {
+	key, err := NewScrambleKey()
+	if err != nil {
+		t.Fatalf("NewScrambleKey failed: %v", err)
+	}
+
+	first, err := Scramble(key, "3")
+	if err != nil {
+		t.Fatalf("Scramble failed: %v", err)
+	}
+	second, _ := Scramble(key, "3")
+	if !IsScrambled(first) || first == second {
+		t.Errorf("expected the hint scrambled differently every time, got %q and %q", first, second)
+	}
+	if hint, err := Unscramble(key, first); err != nil || hint != "3" {
+		t.Errorf("expected the hint back, got %q: %v", hint, err)
+	}
+
+	other, _ := NewScrambleKey()
+	if _, err := Unscramble(other, first); err == nil {
+		t.Error("expected another key to fail")
+	}
+	if _, err := Unscramble(nil, first); err == nil {
+		t.Error("expected a missing key to fail")
+	}
+	if hint, err := Unscramble(nil, "warm"); err != nil || hint != "warm" {
+		t.Errorf("expected plain hints as is, got %q: %v", hint, err)
+	}
+}
+
+func TestLoadScrambleKey(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	key, err := LoadScrambleKey(ctx, c, "game")
+	if err != nil || len(key) == 0 {
+		t.Fatalf("expected a new key, got %q: %v", key, err)
+	}
+	again, err := LoadScrambleKey(ctx, c, "game")
+	if err != nil || !bytes.Equal(again, key) {
+		t.Errorf("expected the same key, got %q: %v", again, err)
+	}
+}
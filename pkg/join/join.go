@@ -0,0 +1,412 @@
+// Package join lets players join a game with a code handed out by its
+// owner, instead of waiting for an admin to edit the player registry and
+// bind their ServiceAccount:
+//
+//	gamemaster join-code --namespace podsweeper-game --uses 5
+//	gamemaster join --url http://gamemaster:8080 --code K7QM-2XHD \
+//	    --player alice --username system:serviceaccount:team-a:alice
+//
+// Codes are kept in a Secret of the game namespace. Redeeming one adds the
+// player to the registry ConfigMap, last in the turn order, and binds the
+// player Role to their ServiceAccount.
+package join
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const (
+	// DefaultSecret is the Secret of the game namespace holding join codes.
+	DefaultSecret = "podsweeper-join-codes"
+
+	// DefaultTTL is how long a join code can be redeemed.
+	DefaultTTL = 24 * time.Hour
+
+	// RoleBindingPrefix prefixes the names of the RoleBindings of joined
+	// players, followed by their ID.
+	RoleBindingPrefix = "podsweeper-player-"
+
+	// codeAlphabet leaves out characters easily mistaken for one another.
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	// codeLength is the number of characters of a code, dash excluded.
+	codeLength = 8
+
+	// maxRequest bounds the size of join requests.
+	maxRequest = 4096
+
+	// serviceAccountPrefix prefixes the usernames of ServiceAccounts.
+	serviceAccountPrefix = "system:serviceaccount:"
+)
+
+var (
+	// ErrInvalidCode is returned when redeeming an unknown, expired or used
+	// up code.
+	ErrInvalidCode = errors.New("invalid or expired join code")
+
+	// ErrPlayerExists is returned when joining as a registered player.
+	ErrPlayerExists = errors.New("player already registered")
+)
+
+// Code is a join code and what is left of it.
+type Code struct {
+	Code string `json:"code"`
+
+	// Owner generated the code.
+	Owner string `json:"owner,omitempty"`
+
+	// ExpiresAt is when the code can no longer be redeemed.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// UsesLeft is how many more players can join with the code.
+	UsesLeft int `json:"usesLeft"`
+}
+
+// Request joins a game as a player.
+type Request struct {
+	Code string `json:"code"`
+
+	// Player is the ID of the new player, a ConfigMap key.
+	Player string `json:"player"`
+
+	// DisplayName defaults to the player ID.
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Username is the Kubernetes username the player plays as, such as
+	// system:serviceaccount:team-a:alice.
+	Username string `json:"username"`
+}
+
+// Invitations hands out and redeems the join codes of a game namespace.
+type Invitations struct {
+	Client    client.Client
+	Namespace string
+
+	// Secret holds the codes. Defaults to DefaultSecret.
+	Secret string
+
+	// PlayersConfigMap holds the player registry. Defaults to
+	// player.DefaultPlayersConfigMap.
+	PlayersConfigMap string
+
+	// Registry is updated with the joined players when set, such as the
+	// registry of the running Gamemaster.
+	Registry *player.Registry
+
+	// now returns the current time, for tests.
+	now func() time.Time
+}
+
+// Create generates a code letting uses players join until ttl elapses.
+func (i *Invitations) Create(ctx context.Context, owner string, ttl time.Duration, uses int) (Code, error) {
+	if uses <= 0 {
+		return Code{}, fmt.Errorf("a join code needs at least one use, got %d", uses)
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	value, err := newCode()
+	if err != nil {
+		return Code{}, err
+	}
+	code := Code{Code: value, Owner: owner, ExpiresAt: i.clock().Add(ttl).UTC(), UsesLeft: uses}
+
+	secret, err := i.secret(ctx)
+	if err != nil {
+		return Code{}, err
+	}
+	// Expired codes are dropped along the way
+	for key, raw := range secret.Data {
+		if c, err := decodeCode(raw); err != nil || !i.valid(c) {
+			delete(secret.Data, key)
+		}
+	}
+	raw, err := json.Marshal(code)
+	if err != nil {
+		return Code{}, fmt.Errorf("failed to encode join code: %w", err)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[value] = raw
+	if err := i.saveSecret(ctx, secret); err != nil {
+		return Code{}, err
+	}
+	return code, nil
+}
+
+// Codes returns the codes that can still be redeemed, by expiry.
+func (i *Invitations) Codes(ctx context.Context) ([]Code, error) {
+	secret, err := i.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var codes []Code
+	for _, raw := range secret.Data {
+		if c, err := decodeCode(raw); err == nil && i.valid(c) {
+			codes = append(codes, c)
+		}
+	}
+	sort.Slice(codes, func(a, b int) bool { return codes[a].ExpiresAt.Before(codes[b].ExpiresAt) })
+	return codes, nil
+}
+
+// Redeem uses a code to add the player of req to the registry, last in the
+// turn order, and binds the player Role to their ServiceAccount.
+func (i *Invitations) Redeem(ctx context.Context, req Request) (player.Player, error) {
+	if errs := validation.IsConfigMapKey(req.Player); len(errs) > 0 {
+		return player.Player{}, fmt.Errorf("invalid player %q: %s", req.Player, strings.Join(errs, ", "))
+	}
+	if req.Username == "" {
+		return player.Player{}, errors.New("a username is required")
+	}
+
+	cm, players, err := i.players(ctx)
+	if err != nil {
+		return player.Player{}, err
+	}
+	turn := 1
+	for _, p := range players {
+		if p.ID == req.Player {
+			return player.Player{}, fmt.Errorf("%w: %s", ErrPlayerExists, req.Player)
+		}
+		turn = max(turn, p.Turn+1)
+	}
+	spec, err := yaml.Marshal(player.Spec{DisplayName: req.DisplayName, Usernames: []string{req.Username}, Turn: turn})
+	if err != nil {
+		return player.Player{}, fmt.Errorf("failed to encode player: %w", err)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[req.Player] = string(spec)
+	// Usernames must stay unique
+	players, err = player.ParsePlayers(cm.Data)
+	if err != nil {
+		return player.Player{}, err
+	}
+
+	// The code is used up first, so that it can't be redeemed twice
+	if err := i.consume(ctx, req.Code); err != nil {
+		return player.Player{}, err
+	}
+	if err := i.savePlayers(ctx, cm); err != nil {
+		return player.Player{}, err
+	}
+	if err := i.bind(ctx, req); err != nil {
+		return player.Player{}, err
+	}
+	if i.Registry != nil {
+		i.Registry.Set(players)
+	}
+
+	for _, p := range players {
+		if p.ID == req.Player {
+			return p, nil
+		}
+	}
+	return player.Player{}, fmt.Errorf("player %s not registered", req.Player)
+}
+
+// Handler redeems the join requests POSTed as JSON, answering with the
+// joined player.
+func (i *Invitations) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "join with POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequest)).Decode(&req); err != nil {
+			http.Error(w, "invalid join request", http.StatusBadRequest)
+			return
+		}
+
+		p, err := i.Redeem(r.Context(), req)
+		switch {
+		case errors.Is(err, ErrInvalidCode):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case errors.Is(err, ErrPlayerExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case apierrors.IsConflict(err):
+			http.Error(w, "the game is busy, try again", http.StatusConflict)
+			return
+		case err != nil:
+			log.FromContext(r.Context()).Error(err, "failed to join", "player", req.Player)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(p)
+	})
+}
+
+// consume uses the code once, deleting it when used up.
+func (i *Invitations) consume(ctx context.Context, value string) error {
+	secret, err := i.secret(ctx)
+	if err != nil {
+		return err
+	}
+	value = strings.ToUpper(strings.TrimSpace(value))
+	c, err := decodeCode(secret.Data[value])
+	if err != nil || !i.valid(c) {
+		return ErrInvalidCode
+	}
+	c.UsesLeft--
+	if c.UsesLeft == 0 {
+		delete(secret.Data, value)
+	} else {
+		raw, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("failed to encode join code: %w", err)
+		}
+		secret.Data[value] = raw
+	}
+	// Updates conflict when another player redeems a code meanwhile
+	return i.saveSecret(ctx, secret)
+}
+
+// bind grants the player Role to the ServiceAccount of req. Other
+// usernames are bound by the admin of their identity provider.
+func (i *Invitations) bind(ctx context.Context, req Request) error {
+	rest, ok := strings.CutPrefix(req.Username, serviceAccountPrefix)
+	if !ok {
+		return nil
+	}
+	ns, name, ok := strings.Cut(rest, ":")
+	if !ok {
+		return fmt.Errorf("invalid ServiceAccount username %q", req.Username)
+	}
+	meta := metav1.ObjectMeta{Name: RoleBindingPrefix + req.Player, Namespace: i.Namespace}
+	binding := rbac.RoleBinding(meta, rbac.PlayerRole, name, ns)
+	if err := i.Client.Create(ctx, binding); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to bind the player role: %w", err)
+	}
+	return nil
+}
+
+// secret returns the Secret of the codes, a new one if missing.
+func (i *Invitations) secret(ctx context.Context) (*corev1.Secret, error) {
+	key := client.ObjectKey{Namespace: i.Namespace, Name: i.Secret}
+	if key.Name == "" {
+		key.Name = DefaultSecret
+	}
+	secret := &corev1.Secret{}
+	err := i.Client.Get(ctx, key, secret)
+	if apierrors.IsNotFound(err) {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join codes: %w", err)
+	}
+	return secret, nil
+}
+
+func (i *Invitations) saveSecret(ctx context.Context, secret *corev1.Secret) error {
+	if secret.ResourceVersion == "" {
+		if err := i.Client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("failed to create join codes: %w", err)
+		}
+		return nil
+	}
+	if err := i.Client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update join codes: %w", err)
+	}
+	return nil
+}
+
+// players returns the registry ConfigMap, a new one if missing, and its
+// players.
+func (i *Invitations) players(ctx context.Context) (*corev1.ConfigMap, []player.Player, error) {
+	key := client.ObjectKey{Namespace: i.Namespace, Name: i.PlayersConfigMap}
+	if key.Name == "" {
+		key.Name = player.DefaultPlayersConfigMap
+	}
+	cm := &corev1.ConfigMap{}
+	err := i.Client.Get(ctx, key, cm)
+	if apierrors.IsNotFound(err) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get players configmap: %w", err)
+	}
+	players, err := player.ParsePlayers(cm.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid players configmap %s: %w", key, err)
+	}
+	return cm, players, nil
+}
+
+func (i *Invitations) savePlayers(ctx context.Context, cm *corev1.ConfigMap) error {
+	if cm.ResourceVersion == "" {
+		if err := i.Client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create players configmap: %w", err)
+		}
+		return nil
+	}
+	if err := i.Client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update players configmap: %w", err)
+	}
+	return nil
+}
+
+// valid reports whether c can still be redeemed.
+func (i *Invitations) valid(c Code) bool {
+	return c.UsesLeft > 0 && i.clock().Before(c.ExpiresAt)
+}
+
+func (i *Invitations) clock() time.Time {
+	if i.now != nil {
+		return i.now()
+	}
+	return time.Now()
+}
+
+func decodeCode(raw []byte) (Code, error) {
+	var c Code
+	if len(raw) == 0 {
+		return c, ErrInvalidCode
+	}
+	err := json.Unmarshal(raw, &c)
+	return c, err
+}
+
+// newCode draws a random code, such as K7QM-2XHD.
+func newCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate join code: %w", err)
+	}
+	var b strings.Builder
+	for n, v := range buf {
+		if n == codeLength/2 {
+			b.WriteByte('-')
+		}
+		// The alphabet has 32 characters, so every byte maps evenly
+		b.WriteByte(codeAlphabet[int(v)%len(codeAlphabet)])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,179 @@
+package join
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+const testNamespace = "game"
+
+func newTestInvitations(objs ...client.Object) *Invitations {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	return &Invitations{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Namespace: testNamespace,
+		Registry:  player.NewRegistry(),
+	}
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInvitations()
+	code, err := i.Create(ctx, "alice", time.Hour, 2)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !regexp.MustCompile(`^[A-Z2-9]{4}-[A-Z2-9]{4}$`).MatchString(code.Code) {
+		t.Errorf("unexpected code format %q", code.Code)
+	}
+
+	codes, err := i.Codes(ctx)
+	if err != nil || len(codes) != 1 || codes[0].Code != code.Code || codes[0].UsesLeft != 2 {
+		t.Errorf("expected the new code to be listed, got %+v: %v", codes, err)
+	}
+
+	// Expired codes are neither listed nor kept
+	i.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if codes, _ := i.Codes(ctx); len(codes) != 0 {
+		t.Errorf("expected the expired code not to be listed, got %+v", codes)
+	}
+	if _, err := i.Create(ctx, "alice", time.Hour, 1); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	secret, _ := i.secret(ctx)
+	if _, ok := secret.Data[code.Code]; ok || len(secret.Data) != 1 {
+		t.Errorf("expected the expired code to be dropped, got %d codes", len(secret.Data))
+	}
+
+	if _, err := i.Create(ctx, "alice", time.Hour, 0); err == nil {
+		t.Error("expected a code without uses to be refused")
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	ctx := context.Background()
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: player.DefaultPlayersConfigMap, Namespace: testNamespace},
+		Data:       map[string]string{"owner": "usernames: [owner@example.com]\nturn: 1\n"},
+	}
+	i := newTestInvitations(existing)
+	code, _ := i.Create(ctx, "owner", time.Hour, 1)
+
+	p, err := i.Redeem(ctx, Request{
+		Code:     code.Code,
+		Player:   "alice",
+		Username: "system:serviceaccount:team-a:alice",
+	})
+	if err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+	if p.ID != "alice" || p.Turn != 2 || p.DisplayName != "alice" {
+		t.Errorf("expected alice second in the turn order, got %+v", p)
+	}
+
+	// The registry, its ConfigMap and the RoleBinding know the player
+	if got := i.Registry.Lookup("system:serviceaccount:team-a:alice"); got.ID != "alice" {
+		t.Errorf("expected the live registry to know alice, got %+v", got)
+	}
+	cm := &corev1.ConfigMap{}
+	_ = i.Client.Get(ctx, client.ObjectKeyFromObject(existing), cm)
+	players, err := player.ParsePlayers(cm.Data)
+	if err != nil || len(players) != 2 {
+		t.Errorf("expected alice in the players ConfigMap, got %+v: %v", cm.Data, err)
+	}
+	binding := &rbacv1.RoleBinding{}
+	key := client.ObjectKey{Namespace: testNamespace, Name: RoleBindingPrefix + "alice"}
+	if err := i.Client.Get(ctx, key, binding); err != nil {
+		t.Fatalf("expected a RoleBinding for alice: %v", err)
+	}
+	if binding.RoleRef.Name != rbac.PlayerRole || binding.Subjects[0].Namespace != "team-a" || binding.Subjects[0].Name != "alice" {
+		t.Errorf("expected the player Role bound to team-a/alice, got %+v", binding)
+	}
+
+	// The code was used up
+	_, err = i.Redeem(ctx, Request{Code: code.Code, Player: "bob", Username: "bob@example.com"})
+	if !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("expected a used up code to be refused, got %v", err)
+	}
+}
+
+func TestRedeem_Invalid(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInvitations()
+	code, _ := i.Create(ctx, "owner", time.Hour, 5)
+	if _, err := i.Redeem(ctx, Request{Code: code.Code, Player: "alice", Username: "alice"}); err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  Request
+		want error
+	}{
+		{"unknown code", Request{Code: "AAAA-AAAA", Player: "bob", Username: "bob"}, ErrInvalidCode},
+		{"registered player", Request{Code: code.Code, Player: "alice", Username: "alice2"}, ErrPlayerExists},
+		{"taken username", Request{Code: code.Code, Player: "bob", Username: "alice"}, nil},
+		{"invalid player", Request{Code: code.Code, Player: "bob smith", Username: "bob"}, nil},
+		{"no username", Request{Code: code.Code, Player: "bob"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := i.Redeem(ctx, tt.req)
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	// Refused requests don't use the code
+	if codes, _ := i.Codes(ctx); len(codes) != 1 || codes[0].UsesLeft != 4 {
+		t.Errorf("expected 4 uses left, got %+v", codes)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInvitations()
+	code, _ := i.Create(ctx, "owner", time.Hour, 1)
+	handler := i.Handler()
+
+	post := func(req Request) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/join", bytes.NewReader(body)))
+		return rec
+	}
+
+	rec := post(Request{Code: code.Code, Player: "alice", Username: "alice"})
+	var p player.Player
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &p) != nil || p.Turn != 1 {
+		t.Fatalf("expected alice to join first, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := post(Request{Code: code.Code, Player: "bob", Username: "bob"}); rec.Code != http.StatusForbidden {
+		t.Errorf("expected a used up code to be forbidden, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/join", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET to be refused, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,205 @@
+package level
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+// Tool is a tool players may use on top of listing and deleting pods.
+type Tool string
+
+const (
+	// ToolExec lets players read and exec into pods.
+	ToolExec Tool = "exec"
+	// ToolLogs lets players read pods and their logs.
+	ToolLogs Tool = "logs"
+)
+
+// Custom describes a level defined at runtime, such as by a
+// PodSweeperLevel, on top of the obstacles of a built-in level. Unset
+// obstacles are those of BasedOn.
+type Custom struct {
+	// Level is the level number. Levels up to Max replace the built-in
+	// level, higher ones add levels.
+	Level int
+
+	// Name is the name of the level. Defaults to the name of BasedOn.
+	Name string
+
+	// BasedOn is the built-in level the obstacles start from.
+	BasedOn int
+
+	// Leak is where the board leaks.
+	Leak Leak
+
+	// HintAccess is who may reach hint pods.
+	HintAccess HintAccess
+
+	// ProtectHints, ScrambleHints and RestrictRBAC override the obstacles
+	// of BasedOn when set.
+	ProtectHints  *bool
+	ScrambleHints *bool
+	RestrictRBAC  *bool
+
+	// Tools lists the tools of players, replacing those of BasedOn when
+	// set. Players can't exec into pods without ToolExec.
+	Tools []Tool
+}
+
+// Validate checks the values of c.
+func (c Custom) Validate() error {
+	if c.Level < Intern {
+		return fmt.Errorf("level cannot be negative, got %d", c.Level)
+	}
+	if c.BasedOn < Intern || c.BasedOn > Max {
+		return fmt.Errorf("based-on level must be between %d and %d, got %d", Intern, Max, c.BasedOn)
+	}
+	switch c.Leak {
+	case "", LeakConfigMap, LeakSecret, LeakEnv, LeakFilesystem, LeakNone, LeakAdminPort, LeakHints, LeakEvents:
+	default:
+		return fmt.Errorf("unknown leak %q", c.Leak)
+	}
+	switch c.HintAccess {
+	case "", HintsOpen, HintsPlayersOnly, HintsBlackout:
+	default:
+		return fmt.Errorf("unknown hint access %q", c.HintAccess)
+	}
+	for _, tool := range c.Tools {
+		if tool != ToolExec && tool != ToolLogs {
+			return fmt.Errorf("unknown tool %q", tool)
+		}
+	}
+	if c.RestrictRBAC != nil && *c.RestrictRBAC && len(c.Tools) > 0 {
+		return fmt.Errorf("levels restricting RBAC can't allow tools, got %v", c.Tools)
+	}
+	return nil
+}
+
+// Policy returns the policy of the custom level.
+func (c Custom) Policy() Policy {
+	return custom{Policy: policies[min(max(c.BasedOn, Intern), Max)], spec: c}
+}
+
+// custom is the policy of a Custom level.
+type custom struct {
+	Policy
+	spec Custom
+}
+
+func (c custom) Level() int { return c.spec.Level }
+
+func (c custom) Name() string {
+	if c.spec.Name != "" {
+		return c.spec.Name
+	}
+	return c.Policy.Name()
+}
+
+func (c custom) Leak() Leak {
+	if c.spec.Leak != "" {
+		return c.spec.Leak
+	}
+	return c.Policy.Leak()
+}
+
+func (c custom) HintAccess() HintAccess {
+	if c.spec.HintAccess != "" {
+		return c.spec.HintAccess
+	}
+	return c.Policy.HintAccess()
+}
+
+func (c custom) ProtectsHints() bool {
+	if c.spec.ProtectHints != nil {
+		return *c.spec.ProtectHints
+	}
+	return c.Policy.ProtectsHints()
+}
+
+func (c custom) ScramblesHints() bool {
+	if c.spec.ScrambleHints != nil {
+		return *c.spec.ScrambleHints
+	}
+	return c.Policy.ScramblesHints()
+}
+
+func (c custom) RestrictsRBAC() bool {
+	if c.spec.RestrictRBAC != nil {
+		return *c.spec.RestrictRBAC
+	}
+	if c.spec.Tools != nil {
+		return len(c.spec.Tools) == 0
+	}
+	return c.Policy.RestrictsRBAC()
+}
+
+func (c custom) PlayerRules(exec bool) []rbacv1.PolicyRule {
+	if c.RestrictsRBAC() {
+		return rbac.PlayerRules()
+	}
+	if c.spec.Tools == nil {
+		return c.Policy.PlayerRules(exec)
+	}
+	rules := rbac.PlayerRules()
+	if slices.Contains(c.spec.Tools, ToolExec) {
+		return append(rules, rbac.DefusalRules()...)
+	}
+	if slices.Contains(c.spec.Tools, ToolLogs) {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"},
+			Verbs: []string{"get"}})
+	}
+	return rules
+}
+
+// customs are the policies of the levels defined at runtime, by level.
+var customs = struct {
+	sync.RWMutex
+	byLevel map[int]Policy
+}{byLevel: map[int]Policy{}}
+
+// Register defines a level at runtime, replacing the built-in or custom
+// level of the same number for For, ForState and All.
+func Register(c Custom) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	customs.Lock()
+	defer customs.Unlock()
+	customs.byLevel[c.Level] = c.Policy()
+	return nil
+}
+
+// Unregister removes the level defined at runtime, restoring the built-in
+// level of that number if any.
+func Unregister(level int) {
+	customs.Lock()
+	defer customs.Unlock()
+	delete(customs.byLevel, level)
+}
+
+// registered returns the policy of the level defined at runtime.
+func registered(level int) (Policy, bool) {
+	customs.RLock()
+	defer customs.RUnlock()
+	p, ok := customs.byLevel[level]
+	return p, ok
+}
+
+// registeredAll returns the policies of the levels defined at runtime,
+// in order.
+func registeredAll() []Policy {
+	customs.RLock()
+	defer customs.RUnlock()
+	all := make([]Policy, 0, len(customs.byLevel))
+	for _, p := range customs.byLevel {
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Level() < all[j].Level() })
+	return all
+}
@@ -0,0 +1,110 @@
+package level
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() {
+		Unregister(Amnesia)
+		Unregister(12)
+	})
+
+	if err := Register(Custom{Level: Amnesia, BasedOn: Amnesia, Name: "Forgetful", Leak: LeakSecret}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := Register(Custom{Level: 12, BasedOn: Blackout, Name: "The Vault", HintAccess: HintsPlayersOnly}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if p := For(Amnesia); p.Name() != "Forgetful" || p.Leak() != LeakSecret || p.HintAccess() != HintsOpen {
+		t.Errorf("For(%d) = %q leaking %s, want the registered level", Amnesia, p.Name(), p.Leak())
+	}
+	vault := For(12)
+	if vault.Level() != 12 || vault.Name() != "The Vault" || vault.HintAccess() != HintsPlayersOnly ||
+		vault.Leak() != LeakEvents || !vault.RestrictsRBAC() {
+		t.Errorf("For(12) = level %d %q, want The Vault based on Blackout", vault.Level(), vault.Name())
+	}
+
+	all := All()
+	if len(all) != Max+2 || all[Amnesia].Name() != "Forgetful" || all[Max+1].Level() != 12 {
+		t.Errorf("All() should overlay and append the custom levels, got %d levels", len(all))
+	}
+
+	Unregister(Amnesia)
+	if p := For(Amnesia); p.Name() != "Amnesia" {
+		t.Errorf("For(%d) = %q after Unregister, want the built-in level", Amnesia, p.Name())
+	}
+}
+
+func TestCustom_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		custom  Custom
+		wantErr bool
+	}{
+		{"valid", Custom{Level: 10, BasedOn: Firewall, Tools: []Tool{ToolLogs}}, false},
+		{"negative level", Custom{Level: -1}, true},
+		{"unknown base", Custom{Level: 10, BasedOn: Max + 1}, true},
+		{"unknown leak", Custom{Level: 10, Leak: "printer"}, true},
+		{"unknown hint access", Custom{Level: 10, HintAccess: "vip"}, true},
+		{"unknown tool", Custom{Level: 10, Tools: []Tool{"port-forward"}}, true},
+		{"tools with restricted RBAC", Custom{Level: 10, RestrictRBAC: ptr.To(true), Tools: []Tool{ToolExec}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.custom.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustom_Policy(t *testing.T) {
+	p := Custom{Level: 3, BasedOn: Window, ProtectHints: ptr.To(false), ScrambleHints: ptr.To(false),
+		RestrictRBAC: ptr.To(false)}.Policy()
+	if p.Level() != 3 || p.Name() != "The Window" || p.ProtectsHints() || p.ScramblesHints() || p.RestrictsRBAC() {
+		t.Errorf("overrides not applied: level %d %q, protects %v, scrambles %v, restricts %v",
+			p.Level(), p.Name(), p.ProtectsHints(), p.ScramblesHints(), p.RestrictsRBAC())
+	}
+	if p.Leak() != LeakHints || p.HintAccess() != HintsPlayersOnly {
+		t.Errorf("unset obstacles should be those of the base, got %s and %s", p.Leak(), p.HintAccess())
+	}
+}
+
+func TestCustom_PlayerRules(t *testing.T) {
+	player := len(rbac.PlayerRules())
+	tests := []struct {
+		name  string
+		tools []Tool
+		exec  bool
+		want  int
+	}{
+		{"tools of the base", nil, true, player + len(rbac.DefusalRules())},
+		{"no tools", []Tool{}, true, player},
+		{"logs", []Tool{ToolLogs}, true, player + 1},
+		{"exec", []Tool{ToolExec}, false, player + len(rbac.DefusalRules())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Custom{Level: 10, BasedOn: Intern, Tools: tt.tools}.Policy()
+			rules := p.PlayerRules(tt.exec)
+			if len(rules) != tt.want {
+				t.Errorf("PlayerRules() = %d rules, want %d", len(rules), tt.want)
+			}
+			if tt.tools != nil && len(tt.tools) == 0 && !p.RestrictsRBAC() {
+				t.Error("a level without tools should restrict RBAC")
+			}
+			if slices.Equal(tt.tools, []Tool{ToolLogs}) && !slices.Contains(rules[len(rules)-1].Resources, "pods/log") {
+				t.Errorf("expected players to read logs, got %+v", rules[len(rules)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Package level maps the level of a game to the hardening of the cluster,
+// from the board leaked in a ConfigMap at level 0 to the blackout of level
+// 9, where hints are only found in Kubernetes Events:
+//
+//	policy, err := level.ForState(state)
+//	if policy.ProtectsHints() { ... }
+//
+// Each level is a Policy. Higher levels build on the obstacles of the
+// previous ones, and the Gamemaster consults the policy of the game on
+// every move rather than comparing level numbers. Game organizers may
+// replace levels or add their own at runtime, see Register.
+package level
+
+import (
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/rules"
+)
+
+// The levels, as named in the specification.
+const (
+	Intern      = 0
+	Junior      = 1
+	Infiltrator = 2
+	Heart       = 3
+	Amnesia     = 4
+	Firewall    = 5
+	SandGrain   = 6
+	PortHacking = 7
+	Window      = 8
+	Blackout    = 9
+
+	// Max is the highest level.
+	Max = Blackout
+)
+
+// Leak is where the board leaks at a level, for players to find.
+type Leak string
+
+const (
+	// LeakConfigMap leaks the mines in the cheat ConfigMap,
+	// game.CheatConfigMapName.
+	LeakConfigMap Leak = "configmap"
+	// LeakSecret leaks the game state in its Secret.
+	LeakSecret Leak = "secret"
+	// LeakEnv leaks the board in the environment variables of pods.
+	LeakEnv Leak = "env"
+	// LeakFilesystem leaks the board in the filesystem of the Gamemaster.
+	LeakFilesystem Leak = "filesystem"
+	// LeakNone leaks nothing: the board only lives in memory.
+	LeakNone Leak = "none"
+	// LeakAdminPort leaks the board on an HTTP admin port.
+	LeakAdminPort Leak = "admin-port"
+	// LeakHints only shows the hints, served over HTTP by hint pods.
+	LeakHints Leak = "hints"
+	// LeakEvents only shows the hints, in Kubernetes Events.
+	LeakEvents Leak = "events"
+)
+
+// HintAccess is who may reach hint pods over the network.
+type HintAccess string
+
+const (
+	// HintsOpen lets any pod reach hint pods.
+	HintsOpen HintAccess = "open"
+	// HintsPlayersOnly only lets player pods and the Gamemaster reach hint
+	// pods.
+	HintsPlayersOnly HintAccess = "players-only"
+	// HintsBlackout lets nothing reach hint pods.
+	HintsBlackout HintAccess = "blackout"
+)
+
+// Policy is the hardening of a level.
+type Policy interface {
+	// Level returns the level number.
+	Level() int
+
+	// Name returns the name of the level, such as "The Firewall".
+	Name() string
+
+	// Leak returns where the board leaks.
+	Leak() Leak
+
+	// HintAccess returns who may reach hint pods, enforced with
+	// NetworkPolicies.
+	HintAccess() HintAccess
+
+	// ProtectsHints reports whether the admission webhook refuses the
+	// deletion of hint pods.
+	ProtectsHints() bool
+
+	// ScramblesHints reports whether the admission webhook scrambles the
+	// hint annotations of hint pods, so hints can only be read from their
+	// agent over HTTP.
+	ScramblesHints() bool
+
+	// RestrictsRBAC reports whether players are left with the minimal
+	// RBAC: no exec, no reading pods or their logs.
+	RestrictsRBAC() bool
+
+	// PlayerRules returns the RBAC rules of players, with exec into pods
+	// when exec is set and the level allows it, for defusal mode and exec
+	// reveals.
+	PlayerRules(exec bool) []rbacv1.PolicyRule
+}
+
+// base is the policy of a level without obstacles.
+type base struct {
+	level int
+	name  string
+}
+
+func (b base) Level() int             { return b.level }
+func (b base) Name() string           { return b.name }
+func (b base) Leak() Leak             { return LeakNone }
+func (b base) HintAccess() HintAccess { return HintsOpen }
+func (b base) ProtectsHints() bool    { return false }
+func (b base) ScramblesHints() bool   { return false }
+func (b base) RestrictsRBAC() bool    { return false }
+
+func (b base) PlayerRules(exec bool) []rbacv1.PolicyRule {
+	rules := rbac.PlayerRules()
+	if exec {
+		rules = append(rules, rbac.DefusalRules()...)
+	}
+	return rules
+}
+
+// intern leaks the mines in a ConfigMap.
+type intern struct{ base }
+
+func (intern) Leak() Leak { return LeakConfigMap }
+
+// junior leaks the game state Secret.
+type junior struct{ base }
+
+func (junior) Leak() Leak { return LeakSecret }
+
+// infiltrator leaks the board in environment variables.
+type infiltrator struct{ base }
+
+func (infiltrator) Leak() Leak { return LeakEnv }
+
+// heart leaks the board in the Gamemaster filesystem.
+type heart struct{ base }
+
+func (heart) Leak() Leak { return LeakFilesystem }
+
+// amnesia leaks nothing.
+type amnesia struct{ base }
+
+// firewall guards hint pods with NetworkPolicies and the admission
+// webhook, and leaks the board on an admin port.
+type firewall struct{ base }
+
+func (firewall) Leak() Leak             { return LeakAdminPort }
+func (firewall) HintAccess() HintAccess { return HintsPlayersOnly }
+func (firewall) ProtectsHints() bool    { return true }
+
+// sandGrain only shows the hints.
+type sandGrain struct{ firewall }
+
+func (sandGrain) Leak() Leak { return LeakHints }
+
+// portHacking serves hints on random ports, and scrambles their
+// annotations.
+type portHacking struct{ sandGrain }
+
+func (portHacking) ScramblesHints() bool { return true }
+
+// window leaves players the minimal RBAC.
+type window struct{ portHacking }
+
+func (window) RestrictsRBAC() bool { return true }
+
+func (window) PlayerRules(bool) []rbacv1.PolicyRule { return rbac.PlayerRules() }
+
+// blackout lets nothing reach hint pods, hints are only found in Events.
+type blackout struct{ window }
+
+func (blackout) Leak() Leak             { return LeakEvents }
+func (blackout) HintAccess() HintAccess { return HintsBlackout }
+
+// policies are the policies of the levels, by level.
+var policies = [...]Policy{
+	intern{base{Intern, "The Intern"}},
+	junior{base{Junior, "The Junior"}},
+	infiltrator{base{Infiltrator, "The Infiltrator"}},
+	heart{base{Heart, "The Heart"}},
+	amnesia{base{Amnesia, "Amnesia"}},
+	firewall{base{Firewall, "The Firewall"}},
+	sandGrain{firewall{base{SandGrain, "The Sand Grain"}}},
+	portHacking{sandGrain{firewall{base{PortHacking, "Port-Hacking"}}}},
+	window{portHacking{sandGrain{firewall{base{Window, "The Window"}}}}},
+	blackout{window{portHacking{sandGrain{firewall{base{Blackout, "Blackout"}}}}}},
+}
+
+// For returns the policy of level: the custom level of that number if
+// one is registered, else the built-in level clamped to the levels from
+// Intern to Max.
+func For(level int) Policy {
+	if p, ok := registered(level); ok {
+		return p
+	}
+	return policies[min(max(level, Intern), Max)]
+}
+
+// ForState returns the policy of the obstacles of state, see
+// rules.ObstacleLevel. Custom levels may guard hints harder than their
+// level. On an error evaluating their rules, the policy of the game level
+// is returned with the error.
+func ForState(state *game.GameState) (Policy, error) {
+	level, err := rules.ObstacleLevel(state)
+	return For(level), err
+}
+
+// All returns the policies of every level, in order, custom levels
+// included.
+func All() []Policy {
+	all := slices.Clone(policies[:])
+	for _, p := range registeredAll() {
+		if p.Level() <= Max {
+			all[p.Level()] = p
+		} else {
+			all = append(all, p)
+		}
+	}
+	return all
+}
@@ -0,0 +1,87 @@
+package level
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		level      int
+		name       string
+		leak       Leak
+		hintAccess HintAccess
+		protects   bool
+		scrambles  bool
+		restricts  bool
+	}{
+		{Intern, "The Intern", LeakConfigMap, HintsOpen, false, false, false},
+		{Junior, "The Junior", LeakSecret, HintsOpen, false, false, false},
+		{Infiltrator, "The Infiltrator", LeakEnv, HintsOpen, false, false, false},
+		{Heart, "The Heart", LeakFilesystem, HintsOpen, false, false, false},
+		{Amnesia, "Amnesia", LeakNone, HintsOpen, false, false, false},
+		{Firewall, "The Firewall", LeakAdminPort, HintsPlayersOnly, true, false, false},
+		{SandGrain, "The Sand Grain", LeakHints, HintsPlayersOnly, true, false, false},
+		{PortHacking, "Port-Hacking", LeakHints, HintsPlayersOnly, true, true, false},
+		{Window, "The Window", LeakHints, HintsPlayersOnly, true, true, true},
+		{Blackout, "Blackout", LeakEvents, HintsBlackout, true, true, true},
+	}
+
+	for _, tt := range tests {
+		p := For(tt.level)
+		if p.Level() != tt.level || p.Name() != tt.name {
+			t.Errorf("level %d: got level %d %q, want %q", tt.level, p.Level(), p.Name(), tt.name)
+		}
+		if p.Leak() != tt.leak || p.HintAccess() != tt.hintAccess {
+			t.Errorf("level %d: got leak %s and hint access %s, want %s and %s",
+				tt.level, p.Leak(), p.HintAccess(), tt.leak, tt.hintAccess)
+		}
+		if p.ProtectsHints() != tt.protects || p.ScramblesHints() != tt.scrambles || p.RestrictsRBAC() != tt.restricts {
+			t.Errorf("level %d: got protects hints %v, scrambles hints %v and restricts RBAC %v, want %v, %v and %v",
+				tt.level, p.ProtectsHints(), p.ScramblesHints(), p.RestrictsRBAC(), tt.protects, tt.scrambles, tt.restricts)
+		}
+	}
+
+	if len(All()) != Max+1 {
+		t.Errorf("expected %d levels, got %d", Max+1, len(All()))
+	}
+	if For(-1).Level() != Intern || For(42).Level() != Max {
+		t.Error("expected out of range levels to be clamped")
+	}
+}
+
+func TestPlayerRules(t *testing.T) {
+	player := len(rbac.PlayerRules())
+	withExec := player + len(rbac.DefusalRules())
+
+	if got := len(For(Junior).PlayerRules(true)); got != withExec {
+		t.Errorf("expected exec below the window, got %d rules", got)
+	}
+	if got := len(For(Junior).PlayerRules(false)); got != player {
+		t.Errorf("expected the player rules only without exec, got %d rules", got)
+	}
+	if got := len(For(Window).PlayerRules(true)); got != player {
+		t.Errorf("expected exec withheld from the window, got %d rules", got)
+	}
+}
+
+func TestForState(t *testing.T) {
+	state := game.NewGameState(3, 1)
+	state.Level = Junior
+	if p, err := ForState(state); err != nil || p.Level() != Junior {
+		t.Errorf("expected the game level, got %d: %v", p.Level(), err)
+	}
+
+	// Custom levels may guard hints harder than their level
+	state.Rules = &game.Rules{Obstacles: "level + 4"}
+	if p, err := ForState(state); err != nil || !p.ProtectsHints() {
+		t.Errorf("expected the obstacles of level %d, got %d: %v", Firewall, p.Level(), err)
+	}
+
+	state.Rules = &game.Rules{Obstacles: "level + true"}
+	if p, err := ForState(state); err == nil || p.Level() != Junior {
+		t.Errorf("expected the game level with an error, got %d: %v", p.Level(), err)
+	}
+}
@@ -0,0 +1,25 @@
+package level
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// NewStore returns the store of the games of a Gamemaster playing level
+// lvl. At the levels leaking the mines in the cheat ConfigMap, games are
+// kept there by a game.ConfigMapStore, so reading their state is the
+// intended cheat. Other levels, and a negative lvl for games of any
+// level, keep them in the state Secret.
+func NewStore(c client.Client, namespace string, lvl int) game.Store {
+	if ConfigMapStored(lvl) {
+		return game.NewConfigMapStore(c, namespace)
+	}
+	return game.NewSecretStore(c, game.WithNamespace(namespace))
+}
+
+// ConfigMapStored reports whether NewStore keeps the games of level lvl in
+// the cheat ConfigMap.
+func ConfigMapStored(lvl int) bool {
+	return lvl >= 0 && For(lvl).Leak() == LeakConfigMap
+}
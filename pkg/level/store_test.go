@@ -0,0 +1,31 @@
+package level
+
+import (
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestNewStore(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	tests := []struct {
+		level     int
+		configMap bool
+	}{
+		{Intern, true},
+		{Junior, false},
+		{Blackout, false},
+		{-1, false},
+	}
+	for _, tt := range tests {
+		store := NewStore(c, game.DefaultNamespace, tt.level)
+		if _, ok := store.(*game.ConfigMapStore); ok != tt.configMap || ConfigMapStored(tt.level) != tt.configMap {
+			t.Errorf("NewStore(%d) = %T, want a ConfigMapStore: %v", tt.level, store, tt.configMap)
+		}
+		if cm, ok := store.(*game.ConfigMapStore); ok && cm.ConfigMapName() != game.CheatConfigMapName {
+			t.Errorf("expected level %d games in the cheat ConfigMap, got %s", tt.level, cm.ConfigMapName())
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Package messages renders the messages shown by the pods of the game: the
+// end-of-game banners and the cells. Each message is a Go template, and the
+// built-in English ones can be replaced from a ConfigMap so events can brand
+// or translate the game output. Each key of the ConfigMap is a message name,
+// optionally suffixed with a locale, and each value a template:
+//
+//	victory: |
+//	  {{.Banner}}
+//	  Well done {{.Players}}! Score: {{.Stats.Score}}
+//	victory.fr: |
+//	  {{.Theme.Banner "VICTOIRE !" "🎉"}}
+//	  Bravo ! Score : {{.Stats.Score}}
+//
+// Templates are executed with Data.
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+// DefaultMessagesConfigMap is the name of the ConfigMap holding custom messages.
+const DefaultMessagesConfigMap = "podsweeper-messages"
+
+// The names of the messages.
+const (
+	// Explosion is shown by the explosion pod after a mine is hit.
+	Explosion = "explosion"
+	// Victory is shown by the victory pod.
+	Victory = "victory"
+	// Summary is shown by the summary pod after a resignation.
+	Summary = "summary"
+	// Timeout is shown by the timeout pod after a timed game ran out of
+	// time.
+	Timeout = "timeout"
+	// Defused is shown by the marker pod of a defused mine.
+	Defused = "defused"
+	// Cell is shown by the cell pods.
+	Cell = "cell"
+	// Resign is shown by the resign pod.
+	Resign = "resign"
+	// Timer is shown by the timer pod of a speedrun.
+	Timer = "timer"
+)
+
+// Data holds the variables of the templates. Fields that don't apply to a
+// message are left empty, such as Cell for the victory message.
+type Data struct {
+	// Theme is the theme of the game, so templates can draw their own
+	// banners with {{.Theme.Banner "text" "emoji"}}.
+	Theme theme.Theme
+	// Banner is the built-in banner of the message, such as "💥 BOOM! 💥".
+	Banner string
+	// Cell is the cell the message is about: the mine hit, or the cell pod.
+	Cell game.Coordinate
+	// Board is the rendered board.
+	Board string
+	// Stats are the stats of the game.
+	Stats game.GameStats
+	// Elapsed is the formatted duration of the game.
+	Elapsed string
+	// Team is the name of the winning team.
+	Team string
+	// Players lists the players who revealed cells, such as
+	// "Players: Alice (12), Bob (3)".
+	Players string
+	// Flag is the final CTF flag fragment line, only given to winners.
+	Flag string
+	// ResignedBy is the player who resigned the game.
+	ResignedBy string
+	// Analysis sums up the post-game analysis of the moves, such as
+	// "12 moves: 9 deduced, ...". See package analysis.
+	Analysis string
+	// ScoreCard scores the game on the classic metrics, see package
+	// scoring. Nil if the game wasn't scored.
+	ScoreCard *game.ScoreCard
+}
+
+// defaults are the built-in English messages.
+var defaults = map[string]string{
+	Explosion: `
+    _ ._  _ , _ ._
+  (_ ' ( \` + "`" + `)_  .__)
+( (  (    )   \` + "`" + `) ) _)
+(__ (_   (_ . _) _) ,__)
+    \` + "`" + `~~\` + "`" + `\ ' . /\` + "`" + `~~\` + "`" + `
+         ;   ;
+         /   \
+_________/_ __ \_________
+
+    {{.Banner}}
+
+  You hit a mine at ({{.Cell.X}}, {{.Cell.Y}})!
+
+     GAME OVER
+
+{{.Board}}
+{{with .Analysis}}
+  Analysis: {{.}}
+{{end}}`,
+	Victory: `
+    ___________
+   '._==_==_=_.'
+   .-\:      /-.
+  | (|:.     |) |
+   '-|:.     |-'
+     \::.    /
+      '::. .'
+        ) (
+      _.' '._
+     \` + "`" + `"""""""\` + "`" + `
+
+  {{.Banner}}
+
+{{with .Team}}  Winning team: {{.}}
+{{end}}  Level: {{.Stats.Level}}
+  Clicks: {{.Stats.Clicks}}
+  Mines: {{.Stats.Mines}}
+  Time: {{.Elapsed}}
+  Score: {{.Stats.Score}}
+  {{.Players}}{{if and .Players .Flag}}  {{end}}{{.Flag}}
+{{with .ScoreCard}}  3BV: {{.ThreeBV}} in {{.Clicks}} clicks, efficiency {{printf "%.0f" .Efficiency}}%, {{printf "%.2f" .ThreeBVPerSecond}} 3BV/s, time score {{.TimeScore}}
+{{end}}{{with .Analysis}}  Analysis: {{.}}
+{{end}}  Congratulations!
+`,
+	Summary: `
+  {{.Banner}}
+
+  Game resigned{{with .ResignedBy}} by {{.}}{{end}} after {{.Stats.Clicks}} clicks ({{printf "%.0f" .Stats.Progress}}% revealed).
+
+{{.Board}}
+{{with .Analysis}}
+  Analysis: {{.}}
+{{end}}`,
+	Timeout: `
+  {{.Banner}}
+
+  Time's up after {{.Elapsed}} and {{.Stats.Clicks}} clicks ({{printf "%.0f" .Stats.Progress}}% revealed).
+
+{{.Board}}
+{{with .Analysis}}
+  Analysis: {{.}}
+{{end}}`,
+	Defused: `Mine defused at ({{.Cell.X}}, {{.Cell.Y}})`,
+	Cell:    `PodSweeper cell ready`,
+	Resign:  `Delete this pod to resign the game`,
+	Timer:   `Speedrun! The time is on the podsweeper.io/elapsed annotation`,
+}
+
+// builtin holds the parsed built-in messages.
+var builtin = func() map[string]*template.Template {
+	templates := make(map[string]*template.Template, len(defaults))
+	for name, text := range defaults {
+		templates[name] = template.Must(template.New(name).Parse(text))
+	}
+	return templates
+}()
+
+// Names returns the names of the messages, sorted.
+func Names() []string {
+	return []string{Cell, Defused, Explosion, Resign, Summary, Timeout, Timer, Victory}
+}
+
+// ParseMessages parses ConfigMap data into templates, by key. Keys are
+// message names, optionally suffixed with a locale such as "victory.fr".
+func ParseMessages(data map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(data))
+	for key, text := range data {
+		name, _, _ := strings.Cut(key, ".")
+		if _, ok := defaults[name]; !ok {
+			return nil, fmt.Errorf("message %q: unknown message %q, expected one of %s", key, name, strings.Join(Names(), ", "))
+		}
+		t, err := template.New(key).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", key, err)
+		}
+		// Check the template against the variables it is executed with
+		if err := t.Execute(&strings.Builder{}, Data{}); err != nil {
+			return nil, fmt.Errorf("message %q: %w", key, err)
+		}
+		templates[key] = t
+	}
+	return templates, nil
+}
+
+// Catalog renders messages, from the custom templates loaded at runtime in
+// its locale or else the built-in ones. A nil Catalog renders the built-in
+// messages.
+type Catalog struct {
+	mu     sync.RWMutex
+	locale string
+	custom map[string]*template.Template
+}
+
+// NewCatalog creates a catalog with the built-in messages only, rendering
+// custom messages of locale first when set.
+func NewCatalog(locale string) *Catalog {
+	return &Catalog{locale: locale, custom: map[string]*template.Template{}}
+}
+
+// SetCustom replaces the custom messages.
+func (c *Catalog) SetCustom(templates map[string]*template.Template) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.custom = make(map[string]*template.Template, len(templates))
+	for key, t := range templates {
+		c.custom[key] = t
+	}
+}
+
+// template returns the template of the message called name: the custom one
+// in the locale, the custom one, or the built-in one.
+func (c *Catalog) template(name string) *template.Template {
+	if c == nil {
+		return builtin[name]
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.locale != "" {
+		if t, ok := c.custom[name+"."+c.locale]; ok {
+			return t
+		}
+	}
+	if t, ok := c.custom[name]; ok {
+		return t
+	}
+	return builtin[name]
+}
+
+// Render renders the message called name with data. A custom message that
+// fails to render falls back to the built-in one, so the game output is
+// never lost to a typo; the error is still returned.
+func (c *Catalog) Render(name string, data Data) (string, error) {
+	t := c.template(name)
+	if t == nil {
+		return "", fmt.Errorf("unknown message %q", name)
+	}
+	var b strings.Builder
+	err := t.Execute(&b, data)
+	if err == nil {
+		return b.String(), nil
+	}
+	err = fmt.Errorf("failed to render message %q: %w", t.Name(), err)
+	b.Reset()
+	if builtinErr := builtin[name].Execute(&b, data); builtinErr != nil {
+		return "", builtinErr
+	}
+	return b.String(), err
+}
+
+// LoadFromConfigMap replaces the custom messages with the ones defined in
+// the given ConfigMap. A missing ConfigMap clears the custom messages. On
+// parse errors, the previous messages are kept.
+func (c *Catalog) LoadFromConfigMap(ctx context.Context, r client.Reader, key client.ObjectKey) error {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			c.SetCustom(nil)
+			return nil
+		}
+		return fmt.Errorf("failed to get messages configmap: %w", err)
+	}
+
+	templates, err := ParseMessages(cm.Data)
+	if err != nil {
+		return fmt.Errorf("invalid messages configmap %s: %w", key, err)
+	}
+	c.SetCustom(templates)
+	return nil
+}
@@ -0,0 +1,149 @@
+package messages
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+func TestCatalog_RenderBuiltin(t *testing.T) {
+	data := Data{
+		Theme:   theme.Classic,
+		Banner:  theme.Classic.Banner("VICTORY!", "🎉"),
+		Stats:   game.GameStats{Level: 2, Clicks: 12, Mines: 10, Score: 240},
+		Elapsed: "2m41s",
+		Team:    "blue",
+		Players: "Players: Alice (12)\n",
+	}
+	// A nil catalog renders the built-in messages
+	var c *Catalog
+	victory, err := c.Render(Victory, data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{"🎉 VICTORY! 🎉", "  Winning team: blue\n", "Time: 2m41s", "Score: 240", "  Players: Alice (12)\n\n  Congratulations!"} {
+		if !strings.Contains(victory, want) {
+			t.Errorf("expected %q in victory message, got:\n%s", want, victory)
+		}
+	}
+
+	defused, _ := c.Render(Defused, Data{Cell: game.Coordinate{X: 2, Y: 3}})
+	if defused != "Mine defused at (2, 3)" {
+		t.Errorf("unexpected defused message %q", defused)
+	}
+	if _, err := c.Render("nope", data); err == nil {
+		t.Error("expected error for unknown message")
+	}
+}
+
+func TestCatalog_RenderCustom(t *testing.T) {
+	templates, err := ParseMessages(map[string]string{
+		"victory":    "Well done, score {{.Stats.Score}}",
+		"victory.fr": `{{.Theme.Banner "VICTOIRE !" "🎉"}} Score : {{.Stats.Score}}`,
+		"explosion":  "{{.Stats.Level | printf \"%d\"}} {{len .Board}}",
+	})
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	data := Data{Theme: theme.Classic, Stats: game.GameStats{Score: 42}}
+
+	c := NewCatalog("fr")
+	c.SetCustom(templates)
+	if got, _ := c.Render(Victory, data); got != "🎉 VICTOIRE ! 🎉 Score : 42" {
+		t.Errorf("expected the localized message, got %q", got)
+	}
+	// Messages missing in the locale use the default custom message, then
+	// the built-in one
+	c = NewCatalog("de")
+	c.SetCustom(templates)
+	if got, _ := c.Render(Victory, data); got != "Well done, score 42" {
+		t.Errorf("expected the custom message, got %q", got)
+	}
+	if got, _ := c.Render(Resign, data); got != "Delete this pod to resign the game" {
+		t.Errorf("expected the built-in message, got %q", got)
+	}
+}
+
+func TestCatalog_RenderFailureFallsBack(t *testing.T) {
+	templates, err := ParseMessages(map[string]string{"victory": "{{with .Players}}{{index . 3}}{{end}}"})
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	c := NewCatalog("")
+	c.SetCustom(templates)
+
+	got, err := c.Render(Victory, Data{Players: "P"})
+	if err == nil {
+		t.Error("expected the render error to be reported")
+	}
+	if !strings.Contains(got, "Congratulations!") {
+		t.Errorf("expected the built-in message, got %q", got)
+	}
+}
+
+func TestParseMessages_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+	}{
+		{"unknown message", map[string]string{"confetti": "yay"}},
+		{"syntax error", map[string]string{"victory": "{{.Stats.Score"}},
+		{"unknown variable", map[string]string{"cell.fr": "{{.Mood}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMessages(tt.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadFromConfigMap(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultMessagesConfigMap, Namespace: "podsweeper-game"},
+		Data:       map[string]string{"cell": "KubeCon cell"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	key := client.ObjectKey{Namespace: "podsweeper-game", Name: DefaultMessagesConfigMap}
+
+	catalog := NewCatalog("")
+	if err := catalog.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if got, _ := catalog.Render(Cell, Data{}); got != "KubeCon cell" {
+		t.Errorf("expected the custom cell message, got %q", got)
+	}
+
+	// Invalid content keeps the previous messages
+	cm.Data["cell"] = "{{"
+	_ = c.Update(ctx, cm)
+	if err := catalog.LoadFromConfigMap(ctx, c, key); err == nil {
+		t.Error("expected error for invalid message")
+	}
+	if got, _ := catalog.Render(Cell, Data{}); got != "KubeCon cell" {
+		t.Error("expected previous messages to be kept on error")
+	}
+
+	// A missing ConfigMap restores the built-in messages
+	_ = c.Delete(ctx, cm)
+	if err := catalog.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if got, _ := catalog.Render(Cell, Data{}); got != "PodSweeper cell ready" {
+		t.Errorf("expected the built-in cell message, got %q", got)
+	}
+}
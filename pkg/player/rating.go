@@ -0,0 +1,392 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+const (
+	// DefaultRatingsConfigMap is the name of the ConfigMap persisting ratings.
+	DefaultRatingsConfigMap = "podsweeper-ratings"
+
+	// RatingsKey is the key holding the ratings JSON in the ConfigMap.
+	RatingsKey = "ratings.json"
+
+	// DefaultRating is the rating of a player's first game.
+	DefaultRating = 1200.0
+
+	// KFactor bounds how much a single game can move a rating.
+	KFactor = 32.0
+
+	// maxRecordedGames bounds the list of games already rated.
+	maxRecordedGames = 100
+
+	// maxSpeedrunsPerSeed bounds the speedrun times kept for each seed.
+	maxSpeedrunsPerSeed = 20
+)
+
+// Rating is the Elo-style rating of a player.
+type Rating struct {
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	Wins      int       `json:"wins"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Ratings holds every player's rating, keyed by player ID.
+type Ratings struct {
+	Players map[string]Rating `json:"players"`
+
+	// Recorded lists the IDs of the latest rated games so that a game is
+	// never rated twice.
+	Recorded []string `json:"recorded,omitempty"`
+
+	// Speedruns holds the best times of won speedruns, keyed by seed.
+	Speedruns map[string][]Speedrun `json:"speedruns,omitempty"`
+}
+
+// Speedrun is the time of a won speedrun.
+type Speedrun struct {
+	GameID string `json:"gameId"`
+	// Players are the IDs of the players who revealed cells.
+	Players []string      `json:"players,omitempty"`
+	Time    time.Duration `json:"time"`
+	At      time.Time     `json:"at"`
+}
+
+// SpeedrunEntry is a ranked speedrun.
+type SpeedrunEntry struct {
+	Rank         int      `json:"rank"`
+	Seed         int64    `json:"seed"`
+	Players      []string `json:"players"`
+	Time         string   `json:"time"`
+	Milliseconds int64    `json:"milliseconds"`
+}
+
+// LeaderboardEntry is a ranked player.
+type LeaderboardEntry struct {
+	Rank        int     `json:"rank"`
+	PlayerID    string  `json:"playerId"`
+	DisplayName string  `json:"displayName"`
+	Color       string  `json:"color"`
+	Rating      int     `json:"rating"`
+	Games       int     `json:"games"`
+	Wins        int     `json:"wins"`
+	WinRate     float64 `json:"winRate"`
+}
+
+// BoardRating is the rating of the board as an opponent: harder boards
+// (denser in 3BV, needing guesses, at higher hardening levels) rate higher.
+func BoardRating(state *game.GameState) float64 {
+	analysis := grid.AnalyzeBoard(state)
+	return 800 + 20*analysis.Score + 50*float64(state.Level)
+}
+
+// ExpectedScore is the probability of a player beating a board, per Elo.
+func ExpectedScore(player, board float64) float64 {
+	return 1 / (1 + math.Pow(10, (board-player)/400))
+}
+
+// Outcome scores a finished game between 0 and 1: 1 for a win, and partial
+// credit up to 0.5 for the share of safe cells revealed before losing.
+func Outcome(state *game.GameState) float64 {
+	if state.Status == game.StatusWon {
+		return 1
+	}
+	return 0.5 * state.Stats().Progress / 100
+}
+
+// Update applies a finished game to the ratings of the players who revealed
+// cells. Returns false if the game is not finished or was already rated.
+func (r *Ratings) Update(state *game.GameState, registry *Registry, now time.Time) bool {
+	if state.Status == game.StatusPlaying {
+		return false
+	}
+	id := state.ID()
+	for _, recorded := range r.Recorded {
+		if recorded == id {
+			return false
+		}
+	}
+	if r.Players == nil {
+		r.Players = map[string]Rating{}
+	}
+
+	board := BoardRating(state)
+	outcome := Outcome(state)
+
+	// Several usernames can map to the same player. Cheaters are rated as
+	// if they lost, whether or not they revealed anything.
+	players := make(map[string]bool)
+	for username := range state.Contributions() {
+		id := registry.Lookup(username).ID
+		players[id] = players[id] || state.IsCheater(username)
+	}
+	for _, username := range state.Cheaters() {
+		players[registry.Lookup(username).ID] = true
+	}
+	for playerID, cheated := range players {
+		rating, ok := r.Players[playerID]
+		if !ok {
+			rating.Rating = DefaultRating
+		}
+		score := outcome
+		if cheated {
+			score = 0
+		}
+		rating.Rating += KFactor * (score - ExpectedScore(rating.Rating, board))
+		rating.Games++
+		if state.Status == game.StatusWon && !cheated {
+			rating.Wins++
+		}
+		rating.UpdatedAt = now
+		r.Players[playerID] = rating
+	}
+
+	if state.Speedrun && state.Status == game.StatusWon && len(state.Cheats) == 0 {
+		r.recordSpeedrun(state, registry, now)
+	}
+
+	r.Recorded = append(r.Recorded, id)
+	if len(r.Recorded) > maxRecordedGames {
+		r.Recorded = r.Recorded[len(r.Recorded)-maxRecordedGames:]
+	}
+	return true
+}
+
+// recordSpeedrun keeps the time of a won speedrun if it's among the best
+// of its seed.
+func (r *Ratings) recordSpeedrun(state *game.GameState, registry *Registry, now time.Time) {
+	var players []string
+	seen := make(map[string]bool)
+	for username := range state.Contributions() {
+		if id := registry.Lookup(username).ID; !seen[id] {
+			seen[id] = true
+			players = append(players, id)
+		}
+	}
+	sort.Strings(players)
+
+	if r.Speedruns == nil {
+		r.Speedruns = map[string][]Speedrun{}
+	}
+	seed := strconv.FormatInt(state.Seed, 10)
+	runs := append(r.Speedruns[seed], Speedrun{
+		GameID:  state.ID(),
+		Players: players,
+		Time:    state.SpeedrunTime(now),
+		At:      now,
+	})
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time < runs[j].Time })
+	if len(runs) > maxSpeedrunsPerSeed {
+		runs = runs[:maxSpeedrunsPerSeed]
+	}
+	r.Speedruns[seed] = runs
+}
+
+// SpeedrunLeaderboard ranks the speedruns of a seed, fastest first, with
+// the display names of their players.
+func (r *Ratings) SpeedrunLeaderboard(seed int64, registry *Registry) []SpeedrunEntry {
+	registered := registeredPlayers(registry)
+	runs := r.Speedruns[strconv.FormatInt(seed, 10)]
+	entries := make([]SpeedrunEntry, 0, len(runs))
+	for i, run := range runs {
+		names := make([]string, 0, len(run.Players))
+		for _, id := range run.Players {
+			names = append(names, registered.byID(registry, id).DisplayName)
+		}
+		entries = append(entries, SpeedrunEntry{
+			Rank:         i + 1,
+			Seed:         seed,
+			Players:      names,
+			Time:         game.FormatSpeedrunTime(run.Time),
+			Milliseconds: run.Time.Milliseconds(),
+		})
+	}
+	return entries
+}
+
+// Leaderboard ranks players by rating.
+func (r *Ratings) Leaderboard(registry *Registry) []LeaderboardEntry {
+	registered := registeredPlayers(registry)
+	entries := make([]LeaderboardEntry, 0, len(r.Players))
+	for id, rating := range r.Players {
+		p := registered.byID(registry, id)
+		entry := LeaderboardEntry{
+			PlayerID:    id,
+			DisplayName: p.DisplayName,
+			Color:       p.Color,
+			Rating:      int(math.Round(rating.Rating)),
+			Games:       rating.Games,
+			Wins:        rating.Wins,
+		}
+		if rating.Games > 0 {
+			entry.WinRate = float64(rating.Wins) / float64(rating.Games)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		return entries[i].PlayerID < entries[j].PlayerID
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// playersByID indexes registered players by ID.
+type playersByID map[string]Player
+
+func registeredPlayers(registry *Registry) playersByID {
+	registered := make(playersByID)
+	if registry != nil {
+		for _, p := range registry.Players() {
+			registered[p.ID] = p
+		}
+	}
+	return registered
+}
+
+// byID returns the registered player with this ID, or the default player
+// of an unregistered username, as IDs of unregistered players are their
+// username.
+func (p playersByID) byID(registry *Registry, id string) Player {
+	if player, ok := p[id]; ok {
+		return player
+	}
+	return registry.Lookup(id)
+}
+
+// RatingStore persists ratings in a ConfigMap.
+type RatingStore struct {
+	client    client.Client
+	namespace string
+	name      string
+	registry  *Registry
+}
+
+// NewRatingStore creates a RatingStore. The registry resolves usernames to
+// players and may be nil.
+func NewRatingStore(c client.Client, namespace, name string, registry *Registry) *RatingStore {
+	return &RatingStore{client: c, namespace: namespace, name: name, registry: registry}
+}
+
+// Load reads the ratings. A missing ConfigMap yields empty ratings.
+func (s *RatingStore) Load(ctx context.Context) (*Ratings, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.name}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return &Ratings{Players: map[string]Rating{}}, nil
+		}
+		return nil, fmt.Errorf("failed to get ratings configmap: %w", err)
+	}
+	return parseRatings(cm)
+}
+
+func parseRatings(cm *corev1.ConfigMap) (*Ratings, error) {
+	ratings := &Ratings{Players: map[string]Rating{}}
+	if raw, ok := cm.Data[RatingsKey]; ok {
+		if err := json.Unmarshal([]byte(raw), ratings); err != nil {
+			return nil, fmt.Errorf("invalid ratings in configmap %s: %w", cm.Name, err)
+		}
+	}
+	return ratings, nil
+}
+
+// RecordGame applies a finished game to the persisted ratings.
+// Games already rated are ignored.
+func (s *RatingStore) RecordGame(ctx context.Context, state *game.GameState) error {
+	key := client.ObjectKey{Namespace: s.namespace, Name: s.name}
+	cm := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, key, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ratings configmap: %w", err)
+	}
+	create := errors.IsNotFound(err)
+
+	ratings, err := parseRatings(cm)
+	if err != nil {
+		return err
+	}
+	if !ratings.Update(state, s.registry, time.Now()) {
+		return nil
+	}
+
+	data, err := json.Marshal(ratings)
+	if err != nil {
+		return fmt.Errorf("failed to serialize ratings: %w", err)
+	}
+
+	if create {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":      "podsweeper",
+					"app.kubernetes.io/component": "ratings",
+				},
+			},
+			Data: map[string]string{RatingsKey: string(data)},
+		}
+		if err := s.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create ratings configmap: %w", err)
+		}
+		return nil
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[RatingsKey] = string(data)
+	if err := s.client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update ratings configmap: %w", err)
+	}
+	return nil
+}
+
+// LeaderboardHandler serves the leaderboard as JSON. With ?seed=N, it
+// serves the speedrun times of that seed instead.
+func (s *RatingStore) LeaderboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var seed int64
+		bySeed := r.URL.Query().Has("seed")
+		if bySeed {
+			var err error
+			if seed, err = strconv.ParseInt(r.URL.Query().Get("seed"), 10, 64); err != nil {
+				http.Error(w, "seed must be an integer", http.StatusBadRequest)
+				return
+			}
+		}
+
+		ratings, err := s.Load(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if bySeed {
+			_ = json.NewEncoder(w).Encode(ratings.SpeedrunLeaderboard(seed, s.registry))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ratings.Leaderboard(s.registry))
+	})
+}
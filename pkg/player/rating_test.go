@@ -0,0 +1,223 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	return scheme
+}
+
+func newFinishedGame(t *testing.T, won bool) *game.GameState {
+	t.Helper()
+	state := game.NewGameState(4, 42)
+	state.SetMine(1, 1)
+	if !won {
+		// Lose right after the first reveal
+		state.RevealBy(0, 0, "alice@example.com")
+		state.SetLost()
+		return state
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if !state.IsMine(x, y) {
+				state.RevealBy(x, y, "alice@example.com")
+			}
+		}
+	}
+	state.SetWon()
+	return state
+}
+
+func TestExpectedScore(t *testing.T) {
+	if got := ExpectedScore(1200, 1200); got != 0.5 {
+		t.Errorf("expected 0.5 against an equal board, got %v", got)
+	}
+	if ExpectedScore(1600, 1200) <= 0.9 {
+		t.Error("expected a much stronger player to be heavily favored")
+	}
+}
+
+func TestRatingsUpdate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Set([]Player{{ID: "alice", Usernames: []string{"alice@example.com"}}})
+
+	ratings := &Ratings{}
+	won := newFinishedGame(t, true)
+	if !ratings.Update(won, registry, time.Now()) {
+		t.Fatal("expected the finished game to be rated")
+	}
+	alice := ratings.Players["alice"]
+	if alice.Games != 1 || alice.Wins != 1 {
+		t.Errorf("expected 1 game and 1 win, got %+v", alice)
+	}
+	if alice.Rating <= DefaultRating {
+		t.Errorf("expected a win to raise the rating, got %v", alice.Rating)
+	}
+
+	if ratings.Update(won, registry, time.Now()) {
+		t.Error("expected the same game not to be rated twice")
+	}
+	if ratings.Players["alice"].Games != 1 {
+		t.Error("expected the games count to be unchanged")
+	}
+
+	lost := newFinishedGame(t, false)
+	lost.StartedAt = lost.StartedAt.Add(time.Hour)
+	before := ratings.Players["alice"].Rating
+	if !ratings.Update(lost, registry, time.Now()) {
+		t.Fatal("expected the second game to be rated")
+	}
+	if ratings.Players["alice"].Rating >= before {
+		t.Error("expected a loss to lower the rating")
+	}
+}
+
+func TestRatingsUpdateTaintsCheaters(t *testing.T) {
+	won := newFinishedGame(t, true)
+	won.RecordCheat("alice@example.com", "secrets/podsweeper-state", time.Now())
+	won.RecordCheat("mallory", "configmaps/map", time.Now())
+
+	ratings := &Ratings{}
+	if !ratings.Update(won, nil, time.Now()) {
+		t.Fatal("expected the finished game to be rated")
+	}
+	alice := ratings.Players["alice@example.com"]
+	if alice.Wins != 0 || alice.Rating >= DefaultRating {
+		t.Errorf("expected a cheater's win to count as a loss, got %+v", alice)
+	}
+	if mallory, ok := ratings.Players["mallory"]; !ok || mallory.Rating >= DefaultRating {
+		t.Errorf("expected cheaters who revealed nothing to be rated as losing, got %+v", mallory)
+	}
+}
+
+func TestRatingsUpdateIgnoresPlayingGames(t *testing.T) {
+	ratings := &Ratings{}
+	if ratings.Update(game.NewGameState(4, 42), nil, time.Now()) {
+		t.Error("expected a game in progress not to be rated")
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	registry := NewRegistry()
+	registry.Set([]Player{{ID: "alice", DisplayName: "Alice", Usernames: []string{"alice"}}})
+
+	ratings := &Ratings{Players: map[string]Rating{
+		"bob":   {Rating: 1150, Games: 2},
+		"alice": {Rating: 1300.4, Games: 4, Wins: 3},
+	}}
+	board := ratings.Leaderboard(registry)
+	if len(board) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(board))
+	}
+	if board[0].PlayerID != "alice" || board[0].Rank != 1 || board[0].Rating != 1300 {
+		t.Errorf("unexpected leader: %+v", board[0])
+	}
+	if board[0].DisplayName != "Alice" || board[0].WinRate != 0.75 {
+		t.Errorf("expected registered display name and win rate, got %+v", board[0])
+	}
+	if board[1].DisplayName != "bob" || board[1].Rank != 2 {
+		t.Errorf("expected default display name for bob, got %+v", board[1])
+	}
+}
+
+func TestRatingStoreRecordGame(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := NewRatingStore(c, "podsweeper-game", DefaultRatingsConfigMap, nil)
+	ctx := context.Background()
+
+	if err := store.RecordGame(ctx, newFinishedGame(t, true)); err != nil {
+		t.Fatalf("RecordGame failed: %v", err)
+	}
+	// Recording again updates the existing ConfigMap without rating twice
+	if err := store.RecordGame(ctx, newFinishedGame(t, true)); err != nil {
+		t.Fatalf("RecordGame failed: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: "podsweeper-game", Name: DefaultRatingsConfigMap}
+	if err := c.Get(ctx, key, cm); err != nil {
+		t.Fatalf("expected ratings configmap: %v", err)
+	}
+
+	ratings, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := ratings.Players["alice@example.com"].Games; got != 1 {
+		t.Errorf("expected 1 rated game, got %d", got)
+	}
+}
+
+func TestLeaderboardHandler(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := NewRatingStore(c, "podsweeper-game", DefaultRatingsConfigMap, nil)
+	if err := store.RecordGame(context.Background(), newFinishedGame(t, true)); err != nil {
+		t.Fatalf("RecordGame failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	store.LeaderboardHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leaderboard", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var entries []LeaderboardEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].DisplayName != "alice" {
+		t.Errorf("unexpected leaderboard: %+v", entries)
+	}
+}
+
+func TestSpeedrunLeaderboard(t *testing.T) {
+	registry := NewRegistry()
+	registry.Set([]Player{{ID: "alice", Usernames: []string{"alice@example.com"}, DisplayName: "Alice"}})
+	ratings := &Ratings{}
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i, d := range []time.Duration{90 * time.Second, 45*time.Second + 120*time.Millisecond} {
+		state := newFinishedGame(t, true)
+		state.Speedrun = true
+		state.StartedAt = start.Add(time.Duration(i) * time.Hour)
+		state.FinalTime = d
+		ratings.Update(state, registry, start)
+	}
+	lost := newFinishedGame(t, false)
+	lost.Speedrun = true
+	lost.StartedAt = start.Add(-time.Hour)
+	ratings.Update(lost, registry, start)
+
+	entries := ratings.SpeedrunLeaderboard(42, registry)
+	if len(entries) != 2 {
+		t.Fatalf("expected the two won speedruns, got %+v", entries)
+	}
+	if entries[0].Time != "0:45.12" || entries[0].Rank != 1 || entries[0].Players[0] != "Alice" {
+		t.Errorf("expected the fastest run first, got %+v", entries[0])
+	}
+	if got := ratings.SpeedrunLeaderboard(7, registry); len(got) != 0 {
+		t.Errorf("expected no runs for another seed, got %+v", got)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	store := NewRatingStore(c, "podsweeper-game", DefaultRatingsConfigMap, nil)
+	rec := httptest.NewRecorder()
+	store.LeaderboardHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leaderboard?seed=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid seed, got %d", rec.Code)
+	}
+}
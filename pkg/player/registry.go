@@ -0,0 +1,231 @@
+// Package player maps Kubernetes identities to PodSweeper players.
+package player
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultPlayersConfigMap is the name of the ConfigMap holding the player registry.
+const DefaultPlayersConfigMap = "podsweeper-players"
+
+// serviceAccountPrefix prefixes the usernames of ServiceAccounts.
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// colorRegex matches #RRGGBB colors.
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Palette is the set of colors assigned to players without an explicit one.
+var Palette = []string{
+	"#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4",
+	"#42d4f4", "#f032e6", "#bfef45", "#469990", "#9a6324",
+}
+
+// Player is a person (or bot) playing PodSweeper.
+type Player struct {
+	// ID is the registry key of the player, or the username for players
+	// not in the registry.
+	ID string `json:"id"`
+
+	// Usernames are the Kubernetes usernames (users or ServiceAccounts)
+	// that act as this player.
+	Usernames []string `json:"usernames,omitempty"`
+
+	// DisplayName is shown in messages, the leaderboard and the UI.
+	DisplayName string `json:"displayName"`
+
+	// Color is a #RRGGBB color used to tell players apart.
+	Color string `json:"color"`
+
+	// Turn is the position of the player in the turn order, from 1. Zero
+	// for players outside of it.
+	Turn int `json:"turn,omitempty"`
+}
+
+// Spec is the serialized form of a player, as stored in a ConfigMap.
+// Each key of the ConfigMap is a player ID and each value a YAML or JSON
+// document such as:
+//
+//	displayName: Alice
+//	color: "#e6194b"
+//	usernames:
+//	  - alice@example.com
+//	  - system:serviceaccount:team-a:alice
+//	turn: 2
+type Spec struct {
+	DisplayName string   `json:"displayName,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	Usernames   []string `json:"usernames"`
+	Turn        int      `json:"turn,omitempty"`
+}
+
+// DefaultDisplayName derives a readable name from a Kubernetes username:
+// "name (namespace)" for ServiceAccounts, the local part of e-mail addresses,
+// and the username itself otherwise.
+func DefaultDisplayName(username string) string {
+	if rest, ok := strings.CutPrefix(username, serviceAccountPrefix); ok {
+		if ns, name, ok := strings.Cut(rest, ":"); ok {
+			return fmt.Sprintf("%s (%s)", name, ns)
+		}
+	}
+	if local, _, ok := strings.Cut(username, "@"); ok && local != "" {
+		return local
+	}
+	return username
+}
+
+// DefaultColor picks a stable palette color for an identity.
+func DefaultColor(id string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return Palette[h.Sum32()%uint32(len(Palette))]
+}
+
+// ParsePlayers parses ConfigMap data into players.
+func ParsePlayers(data map[string]string) ([]Player, error) {
+	ids := make([]string, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	owners := make(map[string]string)
+	players := make([]Player, 0, len(data))
+	for _, id := range ids {
+		var spec Spec
+		if err := yaml.UnmarshalStrict([]byte(data[id]), &spec); err != nil {
+			return nil, fmt.Errorf("player %q: %w", id, err)
+		}
+		if len(spec.Usernames) == 0 {
+			return nil, fmt.Errorf("player %q: at least one username is required", id)
+		}
+		for _, u := range spec.Usernames {
+			if owner, ok := owners[u]; ok {
+				return nil, fmt.Errorf("player %q: username %q already belongs to player %q", id, u, owner)
+			}
+			owners[u] = id
+		}
+		if spec.Color != "" && !colorRegex.MatchString(spec.Color) {
+			return nil, fmt.Errorf("player %q: color %q is not a #RRGGBB color", id, spec.Color)
+		}
+		if spec.Turn < 0 {
+			return nil, fmt.Errorf("player %q: turn %d is negative", id, spec.Turn)
+		}
+
+		p := Player{ID: id, Usernames: spec.Usernames, DisplayName: spec.DisplayName, Color: spec.Color, Turn: spec.Turn}
+		if p.DisplayName == "" {
+			p.DisplayName = id
+		}
+		if p.Color == "" {
+			p.Color = DefaultColor(id)
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// Registry resolves Kubernetes usernames to players. Unknown usernames
+// resolve to a player derived from the username, so every move can be
+// attributed even without a registry.
+type Registry struct {
+	mu         sync.RWMutex
+	players    []Player
+	byUsername map[string]Player
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byUsername: map[string]Player{}}
+}
+
+// Lookup returns the player acting under the given username.
+// It is safe to call on a nil registry.
+func (r *Registry) Lookup(username string) Player {
+	if r != nil {
+		r.mu.RLock()
+		p, ok := r.byUsername[username]
+		r.mu.RUnlock()
+		if ok {
+			return p
+		}
+	}
+	return Player{
+		ID:          username,
+		Usernames:   []string{username},
+		DisplayName: DefaultDisplayName(username),
+		Color:       DefaultColor(username),
+	}
+}
+
+// DisplayName is a shortcut for Lookup(username).DisplayName.
+func (r *Registry) DisplayName(username string) string {
+	return r.Lookup(username).DisplayName
+}
+
+// Players returns the registered players, sorted by ID.
+func (r *Registry) Players() []Player {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	players := make([]Player, len(r.players))
+	copy(players, r.players)
+	return players
+}
+
+// TurnOrder returns the players in the turn order, by turn then ID.
+func (r *Registry) TurnOrder() []Player {
+	var order []Player
+	for _, p := range r.Players() {
+		if p.Turn > 0 {
+			order = append(order, p)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Turn < order[j].Turn })
+	return order
+}
+
+// Set replaces the registered players.
+func (r *Registry) Set(players []Player) {
+	byUsername := make(map[string]Player)
+	for _, p := range players {
+		for _, u := range p.Usernames {
+			byUsername[u] = p
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.players = append([]Player(nil), players...)
+	r.byUsername = byUsername
+}
+
+// LoadFromConfigMap replaces the players with the ones defined in the given
+// ConfigMap. A missing ConfigMap clears the registry. On parse errors, the
+// previous players are kept.
+func (r *Registry) LoadFromConfigMap(ctx context.Context, c client.Reader, key client.ObjectKey) error {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			r.Set(nil)
+			return nil
+		}
+		return fmt.Errorf("failed to get players configmap: %w", err)
+	}
+
+	players, err := ParsePlayers(cm.Data)
+	if err != nil {
+		return fmt.Errorf("invalid players configmap %s: %w", key, err)
+	}
+	r.Set(players)
+	return nil
+}
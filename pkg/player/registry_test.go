@@ -0,0 +1,157 @@
+package player
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDefaultDisplayName(t *testing.T) {
+	tests := []struct {
+		username string
+		expected string
+	}{
+		{"system:serviceaccount:team-a:bot", "bot (team-a)"},
+		{"alice@example.com", "alice"},
+		{"kubernetes-admin", "kubernetes-admin"},
+		{"@weird", "@weird"},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultDisplayName(tt.username); got != tt.expected {
+			t.Errorf("DefaultDisplayName(%q) = %q, expected %q", tt.username, got, tt.expected)
+		}
+	}
+}
+
+func TestDefaultColorIsStable(t *testing.T) {
+	a := DefaultColor("alice")
+	if a != DefaultColor("alice") {
+		t.Error("expected the same color for the same identity")
+	}
+	if !colorRegex.MatchString(a) {
+		t.Errorf("expected a #RRGGBB color, got %q", a)
+	}
+}
+
+func TestParsePlayers(t *testing.T) {
+	players, err := ParsePlayers(map[string]string{
+		"alice": "displayName: Alice\ncolor: \"#112233\"\nusernames: [alice@example.com, \"system:serviceaccount:team-a:alice\"]\n",
+		"bob":   `{"usernames": ["bob"]}`,
+	})
+	if err != nil {
+		t.Fatalf("ParsePlayers failed: %v", err)
+	}
+	if len(players) != 2 || players[0].ID != "alice" || players[1].ID != "bob" {
+		t.Fatalf("expected players sorted by ID, got %+v", players)
+	}
+	if players[0].DisplayName != "Alice" || players[0].Color != "#112233" {
+		t.Errorf("unexpected alice: %+v", players[0])
+	}
+	if players[1].DisplayName != "bob" || players[1].Color != DefaultColor("bob") {
+		t.Errorf("expected defaults for bob, got %+v", players[1])
+	}
+}
+
+func TestParsePlayersErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+		err  string
+	}{
+		{"invalid yaml", map[string]string{"a": "usernames: ["}, "player \"a\""},
+		{"unknown field", map[string]string{"a": "usernames: [a]\nteam: red\n"}, "unknown field"},
+		{"no username", map[string]string{"a": "displayName: A\n"}, "at least one username"},
+		{"bad color", map[string]string{"a": "usernames: [a]\ncolor: red\n"}, "#RRGGBB"},
+		{"shared username", map[string]string{"a": "usernames: [x]", "b": "usernames: [x]"}, "already belongs"},
+		{"negative turn", map[string]string{"a": "usernames: [a]\nturn: -1\n"}, "negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePlayers(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRegistryLookup(t *testing.T) {
+	r := NewRegistry()
+	r.Set([]Player{{ID: "alice", Usernames: []string{"alice@example.com", "alice-sa"}, DisplayName: "Alice", Color: "#000000"}})
+
+	if got := r.Lookup("alice-sa"); got.ID != "alice" || got.DisplayName != "Alice" {
+		t.Errorf("expected alice, got %+v", got)
+	}
+	if got := r.DisplayName("system:serviceaccount:ns:carol"); got != "carol (ns)" {
+		t.Errorf("expected fallback display name, got %q", got)
+	}
+
+	var nilRegistry *Registry
+	if got := nilRegistry.DisplayName("dave@example.com"); got != "dave" {
+		t.Errorf("expected nil registry to fall back, got %q", got)
+	}
+	if len(r.Players()) != 1 {
+		t.Errorf("expected 1 registered player, got %d", len(r.Players()))
+	}
+}
+
+func TestRegistry_TurnOrder(t *testing.T) {
+	r := NewRegistry()
+	r.Set([]Player{
+		{ID: "alice", Turn: 2},
+		{ID: "bob"},
+		{ID: "carol", Turn: 1},
+	})
+	order := r.TurnOrder()
+	if len(order) != 2 || order[0].ID != "carol" || order[1].ID != "alice" {
+		t.Errorf("expected carol then alice, got %+v", order)
+	}
+}
+
+func TestRegistry_LoadFromConfigMap(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultPlayersConfigMap, Namespace: "podsweeper-game"},
+		Data:       map[string]string{"alice": "displayName: Alice\nusernames: [alice@example.com]\n"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	key := client.ObjectKey{Namespace: "podsweeper-game", Name: DefaultPlayersConfigMap}
+
+	r := NewRegistry()
+	if err := r.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if r.DisplayName("alice@example.com") != "Alice" {
+		t.Error("expected alice to be loaded")
+	}
+
+	// Invalid content keeps the previous players
+	cm.Data["broken"] = "color: nope"
+	_ = c.Update(ctx, cm)
+	if err := r.LoadFromConfigMap(ctx, c, key); err == nil {
+		t.Error("expected error for invalid player")
+	}
+	if r.DisplayName("alice@example.com") != "Alice" {
+		t.Error("expected previous players to be kept on error")
+	}
+
+	// A missing ConfigMap clears the registry
+	_ = c.Delete(ctx, cm)
+	if err := r.LoadFromConfigMap(ctx, c, key); err != nil {
+		t.Fatalf("LoadFromConfigMap failed: %v", err)
+	}
+	if len(r.Players()) != 0 {
+		t.Error("expected players to be cleared")
+	}
+}
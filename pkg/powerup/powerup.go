@@ -0,0 +1,245 @@
+// Package powerup defines the power-ups players spend their charges on.
+// Charges are earned by long streaks of safe clicks (see
+// game.StreakPerCharge) and spent by annotating a cell pod with
+// AnnotationPowerUp, which targets that cell. The default player Role
+// doesn't allow annotating pods: the `gamemaster powerup` command annotates
+// with the credentials of its caller. Power-ups are pluggable: any type
+// implementing PowerUp can be registered.
+package powerup
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// AnnotationPowerUp is the annotation of a cell pod naming the power-up to
+// use on its cell.
+const AnnotationPowerUp = "podsweeper.io/power-up"
+
+const (
+	// NameRevealSafe reveals a random hidden safe cell.
+	NameRevealSafe = "reveal-safe"
+
+	// NameNeutralize defuses the targeted cell if it's a mine, at no cost,
+	// and reveals it otherwise.
+	NameNeutralize = "neutralize"
+
+	// NameSweep arms a sweep of the next row or column of pods deleted at
+	// once.
+	NameSweep = "sweep"
+)
+
+// Effect is what a power-up does to the board. The Gamemaster applies it
+// like the corresponding clicks, without losing the game.
+type Effect struct {
+	// Reveal are safe cells to reveal.
+	Reveal []game.Coordinate
+
+	// Neutralize are mines to defuse.
+	Neutralize []game.Coordinate
+
+	// Flag are mines to flag. Their pods are restored if players deleted
+	// them.
+	Flag []game.Coordinate
+
+	// Arm holds the power-up until the next move, which triggers it. Only
+	// Armable power-ups can be armed.
+	Arm bool
+}
+
+// IsEmpty reports whether the effect does nothing.
+func (e Effect) IsEmpty() bool {
+	return len(e.Reveal) == 0 && len(e.Neutralize) == 0 && len(e.Flag) == 0 && !e.Arm
+}
+
+// PowerUp is an effect players can buy with a charge.
+// Implementations must not modify the state they are given, and must only
+// use the provided RNG for random choices.
+type PowerUp interface {
+	// Name identifies the power-up in AnnotationPowerUp.
+	Name() string
+
+	// Description tells players what the power-up does.
+	Description() string
+
+	// Effect returns the effect of using the power-up on target, a hidden
+	// cell. An error refuses the use without spending the charge.
+	Effect(state *game.GameState, target game.Coordinate, rng *rand.Rand) (Effect, error)
+}
+
+// Armable is a power-up taking effect on the next move rather than on its
+// target. Its Effect arms it, then every move is offered to Trigger until
+// it fires.
+type Armable interface {
+	PowerUp
+
+	// Trigger returns the effect of the armed power-up on the move at
+	// target, and whether the move triggers it. deleted are the other
+	// hidden cells whose pods are being deleted along with the target's.
+	// An untriggered power-up stays armed and the move is played.
+	Trigger(state *game.GameState, target game.Coordinate, deleted []game.Coordinate) (Effect, bool)
+}
+
+var (
+	powerUpsMu sync.RWMutex
+	powerUps   = map[string]PowerUp{}
+)
+
+func init() {
+	Register(RevealSafe{})
+	Register(Neutralize{})
+	Register(Sweep{})
+}
+
+// Register makes a power-up available by its name.
+// Registering a power-up with an existing name replaces it.
+func Register(p PowerUp) {
+	powerUpsMu.Lock()
+	defer powerUpsMu.Unlock()
+	powerUps[p.Name()] = p
+}
+
+// Get returns the power-up registered under name.
+func Get(name string) (PowerUp, error) {
+	powerUpsMu.RLock()
+	defer powerUpsMu.RUnlock()
+	p, ok := powerUps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown power-up %q", name)
+	}
+	return p, nil
+}
+
+// All returns the registered power-ups, sorted by name.
+func All() []PowerUp {
+	powerUpsMu.RLock()
+	defer powerUpsMu.RUnlock()
+	all := make([]PowerUp, 0, len(powerUps))
+	for _, p := range powerUps {
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
+
+// RevealSafe reveals a random hidden safe cell, wherever the target is.
+type RevealSafe struct{}
+
+// Name implements PowerUp.
+func (RevealSafe) Name() string { return NameRevealSafe }
+
+// Description implements PowerUp.
+func (RevealSafe) Description() string { return "reveals a random hidden safe cell" }
+
+// Effect implements PowerUp.
+func (RevealSafe) Effect(state *game.GameState, _ game.Coordinate, rng *rand.Rand) (Effect, error) {
+	var safe []game.Coordinate
+	w, h := state.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if !state.IsMine(x, y) && !state.IsRevealed(x, y) {
+				safe = append(safe, game.Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	if len(safe) == 0 {
+		return Effect{}, fmt.Errorf("no hidden safe cell left")
+	}
+	return Effect{Reveal: []game.Coordinate{safe[rng.IntN(len(safe))]}}, nil
+}
+
+// Neutralize makes the target harmless: a mine is defused without costing
+// anything, a safe cell is revealed.
+type Neutralize struct{}
+
+// Name implements PowerUp.
+func (Neutralize) Name() string { return NameNeutralize }
+
+// Description implements PowerUp.
+func (Neutralize) Description() string {
+	return "defuses the targeted cell if it's a mine, reveals it otherwise"
+}
+
+// Effect implements PowerUp.
+func (Neutralize) Effect(state *game.GameState, target game.Coordinate, _ *rand.Rand) (Effect, error) {
+	if state.IsMine(target.X, target.Y) {
+		return Effect{Neutralize: []game.Coordinate{target}}, nil
+	}
+	return Effect{Reveal: []game.Coordinate{target}}, nil
+}
+
+// Sweep clears a whole row or column: once armed, the next move deleting
+// several pods of the same line, like
+//
+//	kubectl delete pods -l podsweeper.io/x=3
+//
+// reveals its safe cells and flags its mines instead of blowing them up.
+type Sweep struct{}
+
+// Name implements PowerUp.
+func (Sweep) Name() string { return NameSweep }
+
+// Description implements PowerUp.
+func (Sweep) Description() string {
+	return "arms a sweep of the next row or column deleted with kubectl delete pods -l podsweeper.io/x=N (or y=N): " +
+		"its safe cells are revealed and its mines flagged"
+}
+
+// Effect implements PowerUp.
+func (Sweep) Effect(state *game.GameState, _ game.Coordinate, _ *rand.Rand) (Effect, error) {
+	if state.PowerUps.Armed != "" {
+		return Effect{}, fmt.Errorf("%s is already armed", state.PowerUps.Armed)
+	}
+	return Effect{Arm: true}, nil
+}
+
+// Trigger implements Armable. The swept line is the row or column of the
+// target holding the most other deleted cells; a move deleting a single
+// pod doesn't trigger the sweep.
+func (Sweep) Trigger(state *game.GameState, target game.Coordinate, deleted []game.Coordinate) (Effect, bool) {
+	column, row := 0, 0
+	for _, c := range deleted {
+		switch {
+		case c == target:
+		case c.X == target.X:
+			column++
+		case c.Y == target.Y:
+			row++
+		}
+	}
+	if column == 0 && row == 0 {
+		return Effect{}, false
+	}
+	if column >= row {
+		return SweepLine(state, target.X, -1), true
+	}
+	return SweepLine(state, -1, target.Y), true
+}
+
+// SweepLine returns the effect of sweeping the column x, or the row y if x
+// is negative: hidden safe cells are revealed and hidden mines flagged.
+func SweepLine(state *game.GameState, x, y int) Effect {
+	var effect Effect
+	w, h := state.Dimensions()
+	for cx := 0; cx < w; cx++ {
+		for cy := 0; cy < h; cy++ {
+			if (x >= 0 && cx != x) || (x < 0 && cy != y) {
+				continue
+			}
+			if state.IsRevealed(cx, cy) || state.IsDefused(cx, cy) {
+				continue
+			}
+			c := game.Coordinate{X: cx, Y: cy}
+			if state.IsMine(cx, cy) {
+				effect.Flag = append(effect.Flag, c)
+			} else {
+				effect.Reveal = append(effect.Reveal, c)
+			}
+		}
+	}
+	return effect
+}
@@ -0,0 +1,149 @@
+package powerup
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// doubleReveal is a custom power-up revealing two random safe cells.
+type doubleReveal struct{}
+
+func (doubleReveal) Name() string        { return "double-reveal" }
+func (doubleReveal) Description() string { return "reveals two random safe cells" }
+func (doubleReveal) Effect(state *game.GameState, target game.Coordinate, rng *rand.Rand) (Effect, error) {
+	first, err := RevealSafe{}.Effect(state, target, rng)
+	if err != nil {
+		return Effect{}, err
+	}
+	second, err := RevealSafe{}.Effect(state, target, rng)
+	if err != nil {
+		return Effect{}, err
+	}
+	return Effect{Reveal: append(first.Reveal, second.Reveal...)}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{NameRevealSafe, NameNeutralize, NameSweep} {
+		if p, err := Get(name); err != nil || p.Name() != name {
+			t.Errorf("Get(%q) = %v, %v", name, p, err)
+		}
+	}
+	if _, err := Get("nuke"); err == nil {
+		t.Error("expected an unknown power-up to fail")
+	}
+
+	Register(doubleReveal{})
+	t.Cleanup(func() {
+		powerUpsMu.Lock()
+		defer powerUpsMu.Unlock()
+		delete(powerUps, "double-reveal")
+	})
+	all := All()
+	if len(all) != 4 || all[0].Name() != "double-reveal" || all[2].Name() != NameRevealSafe {
+		t.Errorf("expected the custom power-up registered and sorted, got %d power-ups", len(all))
+	}
+}
+
+func TestEffects(t *testing.T) {
+	// Mine at (1,1), (0,0) already revealed
+	state := game.NewGameState(3, 1)
+	state.SetMine(1, 1)
+	state.Reveal(0, 0)
+	rng := rand.New(rand.NewPCG(1, 2))
+	mine, safe := game.Coordinate{X: 1, Y: 1}, game.Coordinate{X: 2, Y: 2}
+
+	tests := []struct {
+		name    string
+		powerUp PowerUp
+		target  game.Coordinate
+		check   func(e Effect) bool
+	}{
+		{"neutralize a mine", Neutralize{}, mine, func(e Effect) bool {
+			return len(e.Reveal) == 0 && len(e.Neutralize) == 1 && e.Neutralize[0] == mine
+		}},
+		{"neutralize a safe cell", Neutralize{}, safe, func(e Effect) bool {
+			return len(e.Neutralize) == 0 && len(e.Reveal) == 1 && e.Reveal[0] == safe
+		}},
+		{"reveal a safe cell", RevealSafe{}, mine, func(e Effect) bool {
+			if len(e.Reveal) != 1 || len(e.Neutralize) != 0 {
+				return false
+			}
+			c := e.Reveal[0]
+			return !state.IsMine(c.X, c.Y) && !state.IsRevealed(c.X, c.Y)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effect, err := tt.powerUp.Effect(state, tt.target, rng)
+			if err != nil {
+				t.Fatalf("Effect failed: %v", err)
+			}
+			if !tt.check(effect) {
+				t.Errorf("unexpected effect %+v", effect)
+			}
+		})
+	}
+
+	// Nothing left to reveal
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if !state.IsMine(x, y) {
+				state.Reveal(x, y)
+			}
+		}
+	}
+	if _, err := (RevealSafe{}).Effect(state, mine, rng); err == nil {
+		t.Error("expected reveal-safe to fail without hidden safe cells")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	// Mine at (1,1), (1,3) already revealed on a 4x4 grid
+	state := game.NewGameState(4, 1)
+	state.SetMine(1, 1)
+	state.Reveal(1, 3)
+	target := game.Coordinate{X: 1, Y: 0}
+	c := func(x, y int) game.Coordinate { return game.Coordinate{X: x, Y: y} }
+
+	effect, err := Sweep{}.Effect(state, c(3, 3), nil)
+	if err != nil || !effect.Arm {
+		t.Fatalf("expected the sweep armed, got %+v, %v", effect, err)
+	}
+	state.PowerUps.Armed = NameSweep
+	if _, err := (Sweep{}).Effect(state, c(3, 3), nil); err == nil {
+		t.Error("expected arming twice to fail")
+	}
+
+	tests := []struct {
+		name        string
+		deleted     []game.Coordinate
+		wantTrigger bool
+		wantReveal  []game.Coordinate
+		wantFlag    []game.Coordinate
+	}{
+		{name: "single pod", deleted: []game.Coordinate{target}},
+		{name: "unrelated pods", deleted: []game.Coordinate{target, c(3, 3)}},
+		{
+			name: "column", deleted: []game.Coordinate{target, c(1, 1), c(1, 2)}, wantTrigger: true,
+			wantReveal: []game.Coordinate{c(1, 0), c(1, 2)}, wantFlag: []game.Coordinate{c(1, 1)},
+		},
+		{
+			name: "row", deleted: []game.Coordinate{target, c(0, 0), c(2, 0), c(3, 0), c(1, 1)}, wantTrigger: true,
+			wantReveal: []game.Coordinate{c(0, 0), c(1, 0), c(2, 0), c(3, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effect, ok := Sweep{}.Trigger(state, target, tt.deleted)
+			if ok != tt.wantTrigger {
+				t.Fatalf("Trigger() triggered = %v, want %v", ok, tt.wantTrigger)
+			}
+			if !reflect.DeepEqual(effect.Reveal, tt.wantReveal) || !reflect.DeepEqual(effect.Flag, tt.wantFlag) {
+				t.Errorf("Trigger() = %+v, want reveal %v and flag %v", effect, tt.wantReveal, tt.wantFlag)
+			}
+		})
+	}
+}
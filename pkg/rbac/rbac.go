@@ -0,0 +1,624 @@
+// Package rbac generates the minimal Roles PodSweeper runs with: the
+// Gamemaster gets exactly the pods, state Secret, game lock Lease, ConfigMaps,
+// events and NetworkPolicies it uses in the game namespace, and players may only list and delete pods,
+// plus exec into bomb pods in defusal mode.
+// It also compares these rules with what an identity was actually granted.
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/player"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+const (
+	// GamemasterName names the Gamemaster ServiceAccount, Role and RoleBinding.
+	GamemasterName = "podsweeper-gamemaster"
+
+	// LeaderElectionName names the leader election Lease and its Role.
+	LeaderElectionName = "podsweeper-gamemaster"
+
+	// HintNetworkPolicy names the NetworkPolicy guarding hint pods at higher levels.
+	HintNetworkPolicy = "podsweeper-hints"
+
+	// UIName names the Service, Ingress and Role exposing the board to spectators.
+	UIName = "podsweeper-ui"
+
+	// ShardName names the ServiceAccount, ClusterRole, Role and bindings
+	// of sharded Gamemasters.
+	ShardName = "podsweeper-gamemaster-shard"
+
+	// MetricsAuthName names the ClusterRole letting the Gamemaster
+	// authenticate and authorize the scrapers of its metrics endpoint.
+	MetricsAuthName = "podsweeper-metrics-auth"
+
+	// NamespaceSummaryName prefixes the ClusterRole and ClusterRoleBinding
+	// letting the Gamemaster annotate its game namespace with the summary
+	// of the game.
+	NamespaceSummaryName = "podsweeper-namespace-summary"
+
+	// NewGameName prefixes the ClusterRole and ClusterRoleBinding letting
+	// the Gamemaster watch its game namespace for new game requests.
+	NewGameName = "podsweeper-new-game"
+
+	// MetricsReaderName names the ClusterRole to bind to the scrapers of
+	// authenticated metrics endpoints, such as Prometheus.
+	MetricsReaderName = "podsweeper-metrics-reader"
+
+	// WebhookName names the Service of the webhook server, the Secret
+	// holding its self-managed certificates with their Role, and the
+	// default Validating and MutatingWebhookConfigurations the CA is
+	// patched into.
+	WebhookName = "podsweeper-webhook"
+
+	// PlayerServiceAccount is the ServiceAccount players play as.
+	PlayerServiceAccount = "player"
+
+	// PlayerRole is the Role granting players what playing requires.
+	PlayerRole = "podsweeper-player"
+)
+
+// Config names the resources the Gamemaster is granted access to.
+type Config struct {
+	// Namespace is the game namespace.
+	Namespace string
+
+	// StateSecret is the Secret holding the game state. Defaults to game.DefaultSecretName.
+	StateSecret string
+
+	// PlayersConfigMap maps usernames to players. Defaults to player.DefaultPlayersConfigMap.
+	PlayersConfigMap string
+
+	// MessagesConfigMap overrides the messages of the game pods. Defaults
+	// to messages.DefaultMessagesConfigMap.
+	MessagesConfigMap string
+
+	// RatingsConfigMap persists ratings. Defaults to player.DefaultRatingsConfigMap.
+	RatingsConfigMap string
+
+	// HintAggregator grants access to the ConfigMap of the hint
+	// aggregator, when it serves hints instead of hint pods.
+	HintAggregator bool
+
+	// HintScrambler grants access to the Secret holding the key the hints
+	// of hint pods are scrambled with at higher levels.
+	HintScrambler bool
+
+	// UINamespace holds the Service and Ingress exposing the board, the
+	// namespace the Gamemaster runs in. Empty when the board is not exposed.
+	UINamespace string
+
+	// LeaderElectionNamespace holds the leader election Lease. Empty when
+	// leader election is disabled.
+	LeaderElectionNamespace string
+
+	// KubeconfigSecret holds the kubeconfig of the cluster the board is
+	// played in, such as a vcluster. Empty when the board is played in
+	// the game namespace.
+	KubeconfigSecret string
+
+	// Archive grants access to the ConfigMap archiving the results of
+	// past games, for scheduled or archived games.
+	Archive bool
+
+	// JoinCodesSecret holds the join codes players redeem with the
+	// Gamemaster, which then registers them and binds their
+	// ServiceAccounts to the player Role. Empty when joining is disabled.
+	JoinCodesSecret string
+
+	// MetricsAuth grants the Gamemaster the cluster-wide reviews
+	// authenticating and authorizing the scrapers of its metrics endpoint.
+	MetricsAuth bool
+
+	// NamespaceSummary grants annotating the game namespace with the
+	// summary of the game.
+	NamespaceSummary bool
+
+	// NewGame grants watching the game namespace for new game requests
+	// and clearing them.
+	NewGame bool
+
+	// LevelCRD grants reading the PodSweeperLevels of the game namespace
+	// and writing their status.
+	LevelCRD bool
+
+	// WebhookCertsNamespace holds the Secret of the self-managed
+	// certificates of the webhook server, the namespace the Gamemaster
+	// runs in. Empty when the certificates are provided.
+	WebhookCertsNamespace string
+
+	// WebhookConfigurations names the Validating and
+	// MutatingWebhookConfigurations the self-managed CA is patched into.
+	// Defaults to WebhookName.
+	WebhookConfigurations []string
+}
+
+func (c Config) withDefaults() Config {
+	if c.Namespace == "" {
+		c.Namespace = game.DefaultNamespace
+	}
+	if c.StateSecret == "" {
+		c.StateSecret = game.DefaultSecretName
+	}
+	if c.PlayersConfigMap == "" {
+		c.PlayersConfigMap = player.DefaultPlayersConfigMap
+	}
+	if c.MessagesConfigMap == "" {
+		c.MessagesConfigMap = messages.DefaultMessagesConfigMap
+	}
+	if c.RatingsConfigMap == "" {
+		c.RatingsConfigMap = player.DefaultRatingsConfigMap
+	}
+	if len(c.WebhookConfigurations) == 0 {
+		c.WebhookConfigurations = []string{WebhookName}
+	}
+	return c
+}
+
+// GamemasterRules returns the rules the Gamemaster needs in the game namespace.
+// Create can't be restricted to resource names, so creating Secrets,
+// ConfigMaps, Leases and NetworkPolicies is granted on its own; everything else on
+// them is restricted to the named objects.
+func GamemasterRules(cfg Config) []rbacv1.PolicyRule {
+	cfg = cfg.withDefaults()
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch", "create", "delete", "patch"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{cfg.StateSecret},
+			Verbs: []string{"get", "list", "watch", "update", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{cfg.PlayersConfigMap, cfg.MessagesConfigMap},
+			Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{cfg.RatingsConfigMap},
+			Verbs: []string{"get", "update"}},
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{game.CheatConfigMapName},
+			Verbs: []string{"get", "update", "patch", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets", "configmaps"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"", "events.k8s.io"}, Resources: []string{"events"}, Verbs: []string{"create", "patch"}},
+		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"networkpolicies"}, ResourceNames: []string{HintNetworkPolicy},
+			Verbs: []string{"get", "patch", "delete"}},
+		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"networkpolicies"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, ResourceNames: []string{game.DefaultLockName},
+			Verbs: []string{"get", "update", "delete"}},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"create"}},
+	}
+	if cfg.HintAggregator {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"},
+			ResourceNames: []string{hints.Name}, Verbs: []string{"get", "update"}})
+	}
+	if cfg.HintScrambler {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"},
+			ResourceNames: []string{hints.ScrambleKeySecret}, Verbs: []string{"get"}})
+	}
+	if cfg.LevelCRD {
+		rules = append(rules,
+			rbacv1.PolicyRule{APIGroups: []string{"podsweeper.io"}, Resources: []string{"podsweeperlevels"},
+				Verbs: []string{"get", "list", "watch"}},
+			rbacv1.PolicyRule{APIGroups: []string{"podsweeper.io"}, Resources: []string{"podsweeperlevels/status"},
+				Verbs: []string{"update"}})
+	}
+	if cfg.KubeconfigSecret != "" {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"},
+			ResourceNames: []string{cfg.KubeconfigSecret}, Verbs: []string{"get"}})
+	}
+	if cfg.Archive {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"},
+			ResourceNames: []string{results.DefaultArchiveConfigMap}, Verbs: []string{"get", "update"}})
+	}
+	if cfg.JoinCodesSecret != "" {
+		rules = append(rules,
+			rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"},
+				ResourceNames: []string{cfg.JoinCodesSecret}, Verbs: []string{"get", "update"}},
+			rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"},
+				ResourceNames: []string{cfg.PlayersConfigMap}, Verbs: []string{"update"}},
+			rbacv1.PolicyRule{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"rolebindings"}, Verbs: []string{"create"}},
+			rbacv1.PolicyRule{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"roles"},
+				ResourceNames: []string{PlayerRole}, Verbs: []string{"bind"}},
+		)
+	}
+	return rules
+}
+
+// LeaderElectionRules returns the rules leader election needs in the
+// namespace of its Lease.
+func LeaderElectionRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, ResourceNames: []string{LeaderElectionName},
+			Verbs: []string{"get", "update"}},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"create"}},
+	}
+}
+
+// ShardRules returns the cluster-wide rules of sharded Gamemasters: those
+// of a Gamemaster in every game namespace, and finding game namespaces.
+func ShardRules() []rbacv1.PolicyRule {
+	return append(GamemasterRules(Config{}),
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"get", "list", "watch"}})
+}
+
+// ShardMembershipRules returns the rules sharded Gamemasters need in the
+// namespace of their member Leases. Members come and go, so Leases can't be
+// named.
+func ShardMembershipRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"},
+			Verbs: []string{"get", "list", "watch", "create", "update", "delete"}},
+	}
+}
+
+// MetricsAuthRules returns the cluster-wide rules of a Gamemaster reviewing
+// the tokens and permissions of the scrapers of its metrics endpoint.
+func MetricsAuthRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"authentication.k8s.io"}, Resources: []string{"tokenreviews"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"subjectaccessreviews"}, Verbs: []string{"create"}},
+	}
+}
+
+// NamespaceSummaryRules returns the cluster-wide rules of a Gamemaster
+// annotating its game namespace with the summary of the game.
+func NamespaceSummaryRules(namespace string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"namespaces"}, ResourceNames: []string{namespace},
+			Verbs: []string{"get", "patch"}},
+	}
+}
+
+// NewGameRules returns the cluster-wide rules of a Gamemaster watching
+// its game namespace for new game requests. Watches are restricted to the
+// game namespace by a metadata.name field selector, so it can be named.
+func NewGameRules(namespace string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"namespaces"}, ResourceNames: []string{namespace},
+			Verbs: []string{"get", "list", "watch", "patch"}},
+	}
+}
+
+// WebhookCertsRules returns the rules self-managed webhook certificates
+// need in the namespace of their Secret.
+func WebhookCertsRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{WebhookName},
+			Verbs: []string{"get", "update"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"create"}},
+	}
+}
+
+// WebhookConfigurationRules returns the cluster-wide rules of a Gamemaster
+// patching its self-managed CA into the named webhook configurations.
+func WebhookConfigurationRules(names []string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"admissionregistration.k8s.io"},
+			Resources:     []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations"},
+			ResourceNames: names, Verbs: []string{"get", "update"}},
+	}
+}
+
+// MetricsReaderRules returns the rules scrapers need to read authenticated
+// metrics endpoints.
+func MetricsReaderRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}}}
+}
+
+// UIRules returns the rules exposing the board needs in the namespace the
+// Gamemaster runs in.
+func UIRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"services"}, ResourceNames: []string{UIName},
+			Verbs: []string{"get", "patch"}},
+		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"ingresses"}, ResourceNames: []string{UIName},
+			Verbs: []string{"get", "patch"}},
+		{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"ingresses"}, Verbs: []string{"create"}},
+	}
+}
+
+// PlayerRules returns the rules of players: listing and deleting pods,
+// nothing else. Hints and game state must be found the hard way.
+func PlayerRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list", "delete"}},
+	}
+}
+
+// GamemasterObjects returns the Gamemaster ServiceAccount with its Roles and
+// RoleBindings, including leader election, the board exposure and the
+// metrics authentication when they are configured.
+func GamemasterObjects(cfg Config, labels map[string]string) []client.Object {
+	cfg = cfg.withDefaults()
+	meta := metav1.ObjectMeta{Name: GamemasterName, Namespace: cfg.Namespace, Labels: labels}
+	objs := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.Role{ObjectMeta: meta, Rules: GamemasterRules(cfg)},
+		RoleBinding(meta, GamemasterName, GamemasterName, cfg.Namespace),
+	}
+	if cfg.LeaderElectionNamespace != "" {
+		leMeta := metav1.ObjectMeta{Name: LeaderElectionName, Namespace: cfg.LeaderElectionNamespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.Role{ObjectMeta: leMeta, Rules: LeaderElectionRules()},
+			RoleBinding(leMeta, LeaderElectionName, GamemasterName, cfg.Namespace),
+		)
+	}
+	if cfg.UINamespace != "" {
+		uiMeta := metav1.ObjectMeta{Name: UIName, Namespace: cfg.UINamespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.Role{ObjectMeta: uiMeta, Rules: UIRules()},
+			RoleBinding(uiMeta, UIName, GamemasterName, cfg.Namespace),
+		)
+	}
+	if cfg.MetricsAuth {
+		// The ClusterRoles are shared by the Gamemasters of every game
+		// namespace, each bound by its own ClusterRoleBinding
+		authMeta := metav1.ObjectMeta{Name: MetricsAuthName, Labels: labels}
+		bindingMeta := metav1.ObjectMeta{Name: MetricsAuthName + "-" + cfg.Namespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.ClusterRole{ObjectMeta: authMeta, Rules: MetricsAuthRules()},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: bindingMeta,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: MetricsAuthName},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: GamemasterName, Namespace: cfg.Namespace}},
+			},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: MetricsReaderName, Labels: labels}, Rules: MetricsReaderRules()},
+		)
+	}
+	if cfg.NamespaceSummary {
+		// Namespaces are cluster-scoped, so each game namespace gets its
+		// own ClusterRole restricted to it
+		summaryMeta := metav1.ObjectMeta{Name: NamespaceSummaryName + "-" + cfg.Namespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.ClusterRole{ObjectMeta: summaryMeta, Rules: NamespaceSummaryRules(cfg.Namespace)},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: summaryMeta,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: summaryMeta.Name},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: GamemasterName, Namespace: cfg.Namespace}},
+			},
+		)
+	}
+	if cfg.NewGame {
+		newGameMeta := metav1.ObjectMeta{Name: NewGameName + "-" + cfg.Namespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.ClusterRole{ObjectMeta: newGameMeta, Rules: NewGameRules(cfg.Namespace)},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: newGameMeta,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: newGameMeta.Name},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: GamemasterName, Namespace: cfg.Namespace}},
+			},
+		)
+	}
+	if cfg.WebhookCertsNamespace != "" {
+		// Webhook configurations are cluster-scoped and name the webhooks
+		// of one game namespace, as namespace summaries do
+		certsMeta := metav1.ObjectMeta{Name: WebhookName, Namespace: cfg.WebhookCertsNamespace, Labels: labels}
+		configMeta := metav1.ObjectMeta{Name: WebhookName + "-" + cfg.Namespace, Labels: labels}
+		objs = append(objs,
+			&rbacv1.Role{ObjectMeta: certsMeta, Rules: WebhookCertsRules()},
+			RoleBinding(certsMeta, WebhookName, GamemasterName, cfg.Namespace),
+			&rbacv1.ClusterRole{ObjectMeta: configMeta, Rules: WebhookConfigurationRules(cfg.WebhookConfigurations)},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: configMeta,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: configMeta.Name},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: GamemasterName, Namespace: cfg.Namespace}},
+			},
+		)
+	}
+	return objs
+}
+
+// DefusalRules returns the rules players need on top of PlayerRules in
+// defusal mode: reading and exec'ing into the bomb pods. Bomb pods come and
+// go, so these rules can't be restricted to their names. They also let
+// players reveal cells by exec'ing into them, see spawner.AgentImage.
+func DefusalRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+	}
+}
+
+// ShardObjects returns the ServiceAccount of sharded Gamemasters running in
+// namespace, with their ClusterRole, membership Role and bindings.
+func ShardObjects(namespace string, labels map[string]string) []client.Object {
+	clusterMeta := metav1.ObjectMeta{Name: ShardName, Labels: labels}
+	meta := metav1.ObjectMeta{Name: ShardName, Namespace: namespace, Labels: labels}
+	return []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.ClusterRole{ObjectMeta: clusterMeta, Rules: ShardRules()},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: clusterMeta,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: ShardName},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: ShardName, Namespace: namespace}},
+		},
+		&rbacv1.Role{ObjectMeta: meta, Rules: ShardMembershipRules()},
+		RoleBinding(meta, ShardName, ShardName, namespace),
+	}
+}
+
+// PlayerObjects returns the player ServiceAccount with its Role and
+// RoleBinding, including DefusalRules for games in defusal mode.
+func PlayerObjects(namespace string, labels map[string]string, defusal bool) []client.Object {
+	rules := PlayerRules()
+	if defusal {
+		rules = append(rules, DefusalRules()...)
+	}
+	return PlayerRoleObjects(namespace, labels, rules)
+}
+
+// PlayerRoleObjects returns the player ServiceAccount with a Role of rules
+// and its RoleBinding, such as the player rules of a level.
+func PlayerRoleObjects(namespace string, labels map[string]string, rules []rbacv1.PolicyRule) []client.Object {
+	meta := metav1.ObjectMeta{Name: PlayerRole, Namespace: namespace, Labels: labels}
+	return []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: PlayerServiceAccount, Namespace: namespace, Labels: labels}},
+		&rbacv1.Role{ObjectMeta: meta, Rules: rules},
+		RoleBinding(meta, PlayerRole, PlayerServiceAccount, namespace),
+	}
+}
+
+// RoleBinding binds a Role to a ServiceAccount, possibly of another namespace.
+func RoleBinding(meta metav1.ObjectMeta, role, serviceAccount, serviceAccountNamespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: meta,
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccount,
+			Namespace: serviceAccountNamespace,
+		}},
+	}
+}
+
+// Report compares the rules an identity was granted with the rules it needs.
+type Report struct {
+	// Missing lists required permissions that are not granted, as
+	// "verb group/resource[/name]".
+	Missing []string
+
+	// Broad lists granted rules wider than PodSweeper ever needs, such as
+	// wildcards or reading every Secret of the namespace.
+	Broad []string
+
+	// Incomplete is set when the API server could not list every rule,
+	// so Missing may report permissions that are in fact granted.
+	Incomplete bool
+}
+
+// OK reports whether exactly the required permissions were found.
+func (r Report) OK() bool {
+	return len(r.Missing) == 0 && len(r.Broad) == 0
+}
+
+// Compare checks granted rules against required ones.
+func Compare(granted []authorizationv1.ResourceRule, required []rbacv1.PolicyRule) Report {
+	var report Report
+	for _, rule := range required {
+		names := rule.ResourceNames
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					for _, name := range names {
+						if !allowed(granted, group, resource, verb, name) {
+							report.Missing = append(report.Missing, permission(verb, group, resource, name))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, rule := range granted {
+		switch {
+		case slices.Contains(rule.Verbs, rbacv1.VerbAll),
+			slices.Contains(rule.Resources, rbacv1.ResourceAll),
+			slices.Contains(rule.APIGroups, rbacv1.APIGroupAll):
+			report.Broad = append(report.Broad, describe(rule))
+		case slices.Contains(rule.Resources, "secrets") && len(rule.ResourceNames) == 0 &&
+			slices.ContainsFunc(rule.Verbs, func(v string) bool { return v == "get" || v == "list" || v == "watch" }):
+			report.Broad = append(report.Broad, describe(rule))
+		}
+	}
+	return report
+}
+
+// Check reviews the rules granted to the client's identity in namespace and
+// compares them with the required ones.
+func Check(ctx context.Context, c client.Client, namespace string, required []rbacv1.PolicyRule) (Report, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	if err := c.Create(ctx, review); err != nil {
+		return Report{}, fmt.Errorf("failed to review granted rules: %w", err)
+	}
+	report := Compare(review.Status.ResourceRules, required)
+	report.Incomplete = review.Status.Incomplete
+	return report, nil
+}
+
+// Review asks the API server, with one SelfSubjectAccessReview per
+// permission, whether the client's identity has each of the required
+// rules in namespace, and returns the missing permissions as in
+// Report.Missing. Unlike Check, it sees every authorizer, not just RBAC.
+func Review(ctx context.Context, c client.Client, namespace string, required []rbacv1.PolicyRule) ([]string, error) {
+	var missing []string
+	for _, rule := range required {
+		names := rule.ResourceNames
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					for _, name := range names {
+						base, subresource, _ := strings.Cut(resource, "/")
+						review := &authorizationv1.SelfSubjectAccessReview{
+							Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+								ResourceAttributes: &authorizationv1.ResourceAttributes{
+									Namespace:   namespace,
+									Verb:        verb,
+									Group:       group,
+									Resource:    base,
+									Subresource: subresource,
+									Name:        name,
+								},
+							},
+						}
+						if err := c.Create(ctx, review); err != nil {
+							return nil, fmt.Errorf("failed to review access: %w", err)
+						}
+						if !review.Status.Allowed {
+							missing = append(missing, permission(verb, group, resource, name))
+						}
+					}
+				}
+			}
+		}
+	}
+	return missing, nil
+}
+
+// allowed reports whether a granted rule covers the permission.
+func allowed(granted []authorizationv1.ResourceRule, group, resource, verb, name string) bool {
+	for _, rule := range granted {
+		if matches(rule.APIGroups, group) && matches(rule.Resources, resource) && matches(rule.Verbs, verb) &&
+			(len(rule.ResourceNames) == 0 || (name != "" && slices.Contains(rule.ResourceNames, name))) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+func permission(verb, group, resource, name string) string {
+	p := verb + " " + resource
+	if group != "" {
+		p = verb + " " + group + "/" + resource
+	}
+	if name != "" {
+		p += "/" + name
+	}
+	return p
+}
+
+func describe(rule authorizationv1.ResourceRule) string {
+	s := fmt.Sprintf("%s on %s", strings.Join(rule.Verbs, ","), strings.Join(rule.Resources, ","))
+	if len(rule.ResourceNames) > 0 {
+		s += " named " + strings.Join(rule.ResourceNames, ",")
+	}
+	return s
+}
@@ -0,0 +1,334 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/hints"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+// granted converts policy rules to the rules a SelfSubjectRulesReview returns.
+func granted(rules []rbacv1.PolicyRule) []authorizationv1.ResourceRule {
+	out := make([]authorizationv1.ResourceRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, authorizationv1.ResourceRule{
+			Verbs: r.Verbs, APIGroups: r.APIGroups, Resources: r.Resources, ResourceNames: r.ResourceNames,
+		})
+	}
+	return out
+}
+
+func TestGamemasterRules_NoWildcards(t *testing.T) {
+	rules := GamemasterRules(Config{Namespace: "game", StateSecret: "state"})
+	for _, rule := range rules {
+		for _, values := range [][]string{rule.APIGroups, rule.Resources, rule.Verbs} {
+			if slices.Contains(values, "*") {
+				t.Errorf("unexpected wildcard in %+v", rule)
+			}
+		}
+		if slices.Contains(rule.Resources, "secrets") && len(rule.ResourceNames) == 0 &&
+			!slices.Equal(rule.Verbs, []string{"create"}) {
+			t.Errorf("secrets must be restricted to the state Secret, got %+v", rule)
+		}
+	}
+	if report := Compare(granted(rules), rules); !report.OK() {
+		t.Errorf("generated rules should satisfy themselves, got %+v", report)
+	}
+}
+
+func TestGamemasterRules_CheatConfigMap(t *testing.T) {
+	// The LevelManager applies the cheat sheet, the ConfigMapStore updates
+	// the level 0 games kept next to it
+	rules := GamemasterRules(Config{Namespace: "game"})
+	cheat := slices.ContainsFunc(rules, func(r rbacv1.PolicyRule) bool {
+		return slices.Equal(r.ResourceNames, []string{game.CheatConfigMapName}) &&
+			slices.Equal(r.Verbs, []string{"get", "update", "patch", "delete"})
+	})
+	if !cheat {
+		t.Errorf("expected the cheat ConfigMap to be updated and patched, got %+v", rules)
+	}
+}
+
+func TestGamemasterRules_HintAggregator(t *testing.T) {
+	withoutAggregator := GamemasterRules(Config{Namespace: "game"})
+	rules := GamemasterRules(Config{Namespace: "game", HintAggregator: true})
+	if len(rules) != len(withoutAggregator)+1 {
+		t.Fatalf("expected one more rule for the hint aggregator, got %+v", rules)
+	}
+	last := rules[len(rules)-1]
+	if !slices.Equal(last.Resources, []string{"configmaps"}) || !slices.Equal(last.ResourceNames, []string{hints.Name}) {
+		t.Errorf("expected access to the hints ConfigMap only, got %+v", last)
+	}
+}
+
+func TestGamemasterRules_KubeconfigSecret(t *testing.T) {
+	rules := GamemasterRules(Config{Namespace: "game", KubeconfigSecret: "vc-podsweeper"})
+	last := rules[len(rules)-1]
+	if !slices.Equal(last.Resources, []string{"secrets"}) || !slices.Equal(last.ResourceNames, []string{"vc-podsweeper"}) ||
+		!slices.Equal(last.Verbs, []string{"get"}) {
+		t.Errorf("expected read access to the kubeconfig Secret only, got %+v", last)
+	}
+}
+
+func TestGamemasterRules_LevelCRD(t *testing.T) {
+	rules := GamemasterRules(Config{Namespace: "game", LevelCRD: true})
+	status := slices.ContainsFunc(rules, func(r rbacv1.PolicyRule) bool {
+		return slices.Equal(r.Resources, []string{"podsweeperlevels/status"}) && slices.Equal(r.Verbs, []string{"update"})
+	})
+	watch := slices.ContainsFunc(rules, func(r rbacv1.PolicyRule) bool {
+		return slices.Equal(r.APIGroups, []string{"podsweeper.io"}) && slices.Equal(r.Resources, []string{"podsweeperlevels"}) &&
+			slices.Equal(r.Verbs, []string{"get", "list", "watch"})
+	})
+	if !status || !watch {
+		t.Errorf("expected access to the PodSweeperLevels and their status, got %+v", rules)
+	}
+}
+
+func TestGamemasterRules_Archive(t *testing.T) {
+	rules := GamemasterRules(Config{Namespace: "game", Archive: true})
+	last := rules[len(rules)-1]
+	if !slices.Equal(last.Resources, []string{"configmaps"}) ||
+		!slices.Equal(last.ResourceNames, []string{results.DefaultArchiveConfigMap}) ||
+		!slices.Equal(last.Verbs, []string{"get", "update"}) {
+		t.Errorf("expected access to the archive ConfigMap only, got %+v", last)
+	}
+}
+
+func TestGamemasterRules_JoinCodes(t *testing.T) {
+	rules := GamemasterRules(Config{Namespace: "game", JoinCodesSecret: "codes"})
+	bind := slices.ContainsFunc(rules, func(r rbacv1.PolicyRule) bool {
+		return slices.Equal(r.Resources, []string{"roles"}) && slices.Equal(r.ResourceNames, []string{PlayerRole}) &&
+			slices.Equal(r.Verbs, []string{"bind"})
+	})
+	if !bind {
+		t.Errorf("expected the Gamemaster to bind the player Role only, got %+v", rules)
+	}
+	if report := Compare(granted(rules), rules); !report.OK() {
+		t.Errorf("generated rules should satisfy themselves, got %+v", report)
+	}
+}
+
+func TestPlayerRules(t *testing.T) {
+	rules := PlayerRules()
+	if len(rules) != 1 || !slices.Equal(rules[0].Resources, []string{"pods"}) ||
+		!slices.Equal(rules[0].Verbs, []string{"list", "delete"}) {
+		t.Errorf("players should only list and delete pods, got %+v", rules)
+	}
+}
+
+func TestPlayerObjects(t *testing.T) {
+	for _, defusal := range []bool{false, true} {
+		objs := PlayerObjects("game", nil, defusal)
+		role, ok := objs[1].(*rbacv1.Role)
+		if len(objs) != 3 || !ok {
+			t.Fatalf("expected ServiceAccount, Role and RoleBinding, got %d objects", len(objs))
+		}
+		exec := slices.ContainsFunc(role.Rules, func(r rbacv1.PolicyRule) bool {
+			return slices.Contains(r.Resources, "pods/exec")
+		})
+		if exec != defusal {
+			t.Errorf("defusal %v: expected exec allowed %v, got %+v", defusal, defusal, role.Rules)
+		}
+	}
+}
+
+func TestGamemasterObjects(t *testing.T) {
+	if got := len(GamemasterObjects(Config{Namespace: "game"}, nil)); got != 3 {
+		t.Errorf("expected ServiceAccount, Role and RoleBinding, got %d objects", got)
+	}
+
+	objs := GamemasterObjects(Config{Namespace: "game", LeaderElectionNamespace: "system"}, nil)
+	if len(objs) != 5 {
+		t.Fatalf("expected leader election Role and RoleBinding, got %d objects", len(objs))
+	}
+	binding, ok := objs[4].(*rbacv1.RoleBinding)
+	if !ok {
+		t.Fatalf("expected a RoleBinding, got %T", objs[4])
+	}
+	if binding.Namespace != "system" || binding.Subjects[0].Namespace != "game" {
+		t.Errorf("expected the Lease binding in system for the game ServiceAccount, got %+v", binding)
+	}
+
+	objs = GamemasterObjects(Config{Namespace: "game", UINamespace: "podsweeper"}, nil)
+	if len(objs) != 5 {
+		t.Fatalf("expected UI Role and RoleBinding, got %d objects", len(objs))
+	}
+	if role, ok := objs[3].(*rbacv1.Role); !ok || role.Namespace != "podsweeper" || role.Name != UIName {
+		t.Errorf("expected the UI Role in the Gamemaster namespace, got %+v", objs[3])
+	}
+
+	objs = GamemasterObjects(Config{Namespace: "game", MetricsAuth: true}, nil)
+	if len(objs) != 6 {
+		t.Fatalf("expected metrics auth ClusterRole and ClusterRoleBinding and reader ClusterRole, got %d objects", len(objs))
+	}
+	if b, ok := objs[4].(*rbacv1.ClusterRoleBinding); !ok || b.Name != MetricsAuthName+"-game" ||
+		b.RoleRef.Name != MetricsAuthName || b.Subjects[0].Namespace != "game" {
+		t.Errorf("expected the game Gamemaster bound to the metrics auth ClusterRole, got %+v", objs[4])
+	}
+	if role, ok := objs[5].(*rbacv1.ClusterRole); !ok || role.Name != MetricsReaderName ||
+		!slices.Equal(role.Rules[0].NonResourceURLs, []string{"/metrics"}) {
+		t.Errorf("expected the metrics reader ClusterRole, got %+v", objs[5])
+	}
+
+	objs = GamemasterObjects(Config{Namespace: "game", NamespaceSummary: true}, nil)
+	if len(objs) != 5 {
+		t.Fatalf("expected namespace summary ClusterRole and ClusterRoleBinding, got %d objects", len(objs))
+	}
+	if role, ok := objs[3].(*rbacv1.ClusterRole); !ok || role.Name != NamespaceSummaryName+"-game" ||
+		!slices.Equal(role.Rules[0].ResourceNames, []string{"game"}) {
+		t.Errorf("expected the ClusterRole restricted to the game namespace, got %+v", objs[3])
+	}
+
+	objs = GamemasterObjects(Config{Namespace: "game", NewGame: true}, nil)
+	if len(objs) != 5 {
+		t.Fatalf("expected new game ClusterRole and ClusterRoleBinding, got %d objects", len(objs))
+	}
+	if role, ok := objs[3].(*rbacv1.ClusterRole); !ok || role.Name != NewGameName+"-game" ||
+		!slices.Equal(role.Rules[0].ResourceNames, []string{"game"}) || !slices.Contains(role.Rules[0].Verbs, "watch") {
+		t.Errorf("expected the ClusterRole watching the game namespace only, got %+v", objs[3])
+	}
+
+	objs = GamemasterObjects(Config{Namespace: "game", WebhookCertsNamespace: "podsweeper"}, nil)
+	if len(objs) != 7 {
+		t.Fatalf("expected webhook certs Role, RoleBinding, ClusterRole and ClusterRoleBinding, got %d objects", len(objs))
+	}
+	if role, ok := objs[3].(*rbacv1.Role); !ok || role.Namespace != "podsweeper" || role.Name != WebhookName {
+		t.Errorf("expected the webhook certs Role in the Gamemaster namespace, got %+v", objs[3])
+	}
+	if role, ok := objs[5].(*rbacv1.ClusterRole); !ok || role.Name != WebhookName+"-game" ||
+		!slices.Equal(role.Rules[0].ResourceNames, []string{WebhookName}) {
+		t.Errorf("expected the ClusterRole restricted to the webhook configurations, got %+v", objs[5])
+	}
+}
+
+func TestShardObjects(t *testing.T) {
+	objs := ShardObjects("system", nil)
+	if len(objs) != 5 {
+		t.Fatalf("expected ServiceAccount, ClusterRole, ClusterRoleBinding, Role and RoleBinding, got %d objects", len(objs))
+	}
+	role, ok := objs[1].(*rbacv1.ClusterRole)
+	if !ok {
+		t.Fatalf("expected a ClusterRole, got %T", objs[1])
+	}
+	namespaces := slices.ContainsFunc(role.Rules, func(r rbacv1.PolicyRule) bool {
+		return slices.Contains(r.Resources, "namespaces")
+	})
+	if !namespaces {
+		t.Errorf("expected sharded Gamemasters to list namespaces, got %+v", role.Rules)
+	}
+	if lease, ok := objs[3].(*rbacv1.Role); !ok || lease.Namespace != "system" {
+		t.Errorf("expected the membership Role in system, got %+v", objs[3])
+	}
+}
+
+func TestCompare(t *testing.T) {
+	required := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"state"}, Verbs: []string{"get"}},
+	}
+
+	tests := []struct {
+		name        string
+		granted     []authorizationv1.ResourceRule
+		wantMissing []string
+		wantBroad   int
+	}{
+		{
+			name:    "exact",
+			granted: granted(required),
+		},
+		{
+			name: "missing verb and wrong name",
+			granted: []authorizationv1.ResourceRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"other"}, Verbs: []string{"get"}},
+			},
+			wantMissing: []string{"delete pods", "get secrets/state"},
+		},
+		{
+			name: "wildcards",
+			granted: []authorizationv1.ResourceRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			},
+			wantBroad: 1,
+		},
+		{
+			name: "every secret readable",
+			granted: append(granted(required),
+				authorizationv1.ResourceRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}),
+			wantBroad: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(tt.granted, required)
+			if !slices.Equal(report.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", report.Missing, tt.wantMissing)
+			}
+			if len(report.Broad) != tt.wantBroad {
+				t.Errorf("Broad = %v, want %d entries", report.Broad, tt.wantBroad)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var namespace string
+	c := interceptor.NewClient(fake.NewClientBuilder().Build(), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectRulesReview)
+			namespace = review.Spec.Namespace
+			review.Status.ResourceRules = granted(PlayerRules())
+			return nil
+		},
+	})
+
+	report, err := Check(context.Background(), c, "game", PlayerRules())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("expected the granted rules to match, got %+v", report)
+	}
+	if namespace != "game" {
+		t.Errorf("expected the review to target the game namespace, got %q", namespace)
+	}
+}
+
+func TestReview(t *testing.T) {
+	var reviews []authorizationv1.ResourceAttributes
+	c := interceptor.NewClient(fake.NewClientBuilder().Build(), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			attrs := *review.Spec.ResourceAttributes
+			reviews = append(reviews, attrs)
+			review.Status.Allowed = attrs.Namespace == "game" && attrs.Verb != "delete"
+			return nil
+		},
+	})
+
+	required := append(PlayerRules(), DefusalRules()...)
+	missing, err := Review(context.Background(), c, "game", required)
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if !slices.Equal(missing, []string{"delete pods"}) {
+		t.Errorf("expected delete pods to be missing, got %v", missing)
+	}
+	if len(reviews) != 5 {
+		t.Fatalf("expected one review per permission, got %d", len(reviews))
+	}
+	if exec := reviews[4]; exec.Resource != "pods" || exec.Subresource != "exec" || exec.Verb != "create" {
+		t.Errorf("expected subresources to be reviewed as such, got %+v", exec)
+	}
+}
@@ -0,0 +1,177 @@
+// Package render draws boards for every surface showing them: the board
+// command and /board endpoint, the workshop dashboard, Gamemaster logs and
+// end-game pods. Boards are drawn from their board strings, as encoded by
+// GameState.ToBoardString, in plain ASCII, colored for terminals, or with
+// Unicode symbols, in the theme of the game.
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+// Format selects the characters and colors of rendered boards.
+type Format string
+
+const (
+	// Plain draws boards with their board characters, one per cell.
+	Plain Format = "plain"
+
+	// ANSI colors the board characters for terminals.
+	ANSI Format = "ansi"
+
+	// Unicode draws boards with Unicode symbols instead of board characters.
+	Unicode Format = "unicode"
+)
+
+// Formats lists the available formats.
+var Formats = []Format{Plain, ANSI, Unicode}
+
+// ParseFormat returns the format named s. Empty is Plain.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return Plain, nil
+	}
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q, expected %s, %s or %s", s, Plain, ANSI, Unicode)
+}
+
+// View selects what rendered boards disclose.
+type View int
+
+const (
+	// ViewFull shows the hidden mines, for the Gamemaster's own output.
+	ViewFull View = iota
+
+	// ViewPlayer hides the hidden mines, and shows the hints of hot/cold
+	// games as temperatures, as players see the board.
+	ViewPlayer
+
+	// ViewSolution shows the board cleared: the hidden safe cells with
+	// their hints, next to the mines.
+	ViewSolution
+)
+
+// cursorSGR highlights the cursor in ANSI boards, in reverse video.
+const cursorSGR = "7"
+
+// unicodeCells maps board characters to the symbols of Unicode boards.
+// Hints and temperatures keep their characters.
+var unicodeCells = map[byte]string{
+	game.BoardHiddenSafe: "■", game.BoardHiddenMine: "✱",
+	game.BoardFlaggedSafe: "⚑", game.BoardFlaggedMine: "⚑",
+	game.BoardQuestionedSafe: "?", game.BoardQuestionedMine: "?",
+	game.BoardRevealedMine: "✸", game.BoardDefusedMine: "◈",
+	'0': "·",
+}
+
+// Renderer draws boards. The zero Renderer draws the full board in plain
+// ASCII, in the Classic theme.
+type Renderer struct {
+	Format Format
+	// Theme styles the board. Defaults to theme.Classic.
+	Theme theme.Theme
+	View  View
+
+	// Cursor highlights a cell, such as the mine that ended the game: in
+	// reverse video in ANSI boards, or with a caret under it otherwise.
+	// Nil highlights no cell.
+	Cursor *game.Coordinate
+
+	// RLE compresses runs of identical cells, as game.WithRLE, for
+	// compact log values. Only plain boards without a cursor are
+	// compressed.
+	RLE bool
+}
+
+// Render draws the board of state.
+func (r Renderer) Render(state *game.GameState) string {
+	var opts []game.BoardStringOption
+	switch r.View {
+	case ViewPlayer:
+		opts = append(opts, game.WithPlayerView())
+	case ViewSolution:
+		opts = append(opts, game.WithSolution())
+	}
+	if r.RLE && r.format() == Plain && r.Cursor == nil && r.Theme != theme.Emoji {
+		return state.ToBoardString(append(opts, game.WithRLE())...)
+	}
+	return r.RenderString(state.ToBoardString(opts...))
+}
+
+// RenderString draws a board string, as encoded by GameState.ToBoardString
+// without RLE, such as a board recorded earlier. The View of r is ignored:
+// the board string already discloses what it shows.
+func (r Renderer) RenderString(board string) string {
+	t := r.Theme
+	if t == "" {
+		t = theme.Classic
+	}
+	var b strings.Builder
+	for y, row := range strings.Split(board, "\n") {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		caret := -1
+		for x := 0; x < len(row); x++ {
+			glyph := r.glyph(t, row[x])
+			cursor := r.Cursor != nil && r.Cursor.X == x && r.Cursor.Y == y
+			code, colored := t.Color(row[x])
+			switch {
+			case r.format() == ANSI && cursor:
+				if colored {
+					code += ";" + cursorSGR
+				} else {
+					code = cursorSGR
+				}
+				fmt.Fprintf(&b, "\x1b[%sm%s\x1b[0m", code, glyph)
+			case r.format() == ANSI && colored:
+				fmt.Fprintf(&b, "\x1b[%sm%s\x1b[0m", code, glyph)
+			default:
+				if cursor {
+					caret = x
+				}
+				b.WriteString(glyph)
+			}
+		}
+		if caret >= 0 {
+			fmt.Fprintf(&b, "\n%s^", strings.Repeat(" ", caret*cellWidth(t)))
+		}
+	}
+	return b.String()
+}
+
+// format returns the format of r, Plain if unset.
+func (r Renderer) format() Format {
+	if r.Format == "" {
+		return Plain
+	}
+	return r.Format
+}
+
+// glyph returns what draws board character c.
+func (r Renderer) glyph(t theme.Theme, c byte) string {
+	if e, ok := t.Cell(c); ok {
+		return e
+	}
+	if s, ok := unicodeCells[c]; ok && r.format() == Unicode {
+		return s
+	}
+	return string(c)
+}
+
+// cellWidth returns how many columns terminals draw each cell in: emoji
+// are twice as wide as characters.
+func cellWidth(t theme.Theme) int {
+	if t == theme.Emoji {
+		return 2
+	}
+	return 1
+}
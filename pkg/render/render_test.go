@@ -0,0 +1,92 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", Plain, false},
+		{"plain", Plain, false},
+		{"ansi", ANSI, false},
+		{"unicode", Unicode, false},
+		{"html", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	board := "1.\nf2"
+	cursor := &game.Coordinate{X: 1, Y: 1}
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		want     string
+	}{
+		{"zero", Renderer{}, board},
+		{"classic ansi", Renderer{Format: ANSI}, "\x1b[34m1\x1b[0m.\n\x1b[31mf\x1b[0m\x1b[32m2\x1b[0m"},
+		{"high contrast ansi", Renderer{Format: ANSI, Theme: theme.HighContrast},
+			"\x1b[1;97m1\x1b[0m.\n\x1b[1;93mf\x1b[0m\x1b[1;97m2\x1b[0m"},
+		{"emoji ignores ansi", Renderer{Format: ANSI, Theme: theme.Emoji}, "1️⃣🟦\n🚩2️⃣"},
+		{"unicode", Renderer{Format: Unicode}, "1■\n⚑2"},
+		{"plain cursor", Renderer{Cursor: cursor}, "1.\nf2\n ^"},
+		{"emoji cursor", Renderer{Theme: theme.Emoji, Cursor: cursor}, "1️⃣🟦\n🚩2️⃣\n  ^"},
+		{"ansi cursor", Renderer{Format: ANSI, Cursor: &game.Coordinate{X: 1, Y: 0}},
+			"\x1b[34m1\x1b[0m\x1b[7m.\x1b[0m\n\x1b[31mf\x1b[0m\x1b[32m2\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.renderer.RenderString(board); got != tt.want {
+				t.Errorf("RenderString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := (Renderer{Theme: theme.Emoji}).RenderString("HW\nC."); got != "🔥🌡️\n🧊🟦" {
+		t.Errorf("expected temperatures drawn with emoji, got %q", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	// M . . . . . with the four rightmost cells revealed
+	state := game.NewRectGameState(6, 1, 1)
+	state.SetMine(0, 0)
+	for x := 2; x < 6; x++ {
+		state.Reveal(x, 0)
+	}
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		want     string
+	}{
+		{"full", Renderer{}, "*.0000"},
+		{"player", Renderer{View: ViewPlayer}, "..0000"},
+		{"solution", Renderer{View: ViewSolution}, "*10000"},
+		{"unicode player", Renderer{View: ViewPlayer, Format: Unicode}, "■■····"},
+		{"rle", Renderer{RLE: true, View: ViewPlayer}, "..0{4}"},
+		{"rle needs plain boards", Renderer{RLE: true, View: ViewPlayer, Format: Unicode}, "■■····"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.renderer.Render(state); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
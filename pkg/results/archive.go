@@ -0,0 +1,191 @@
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+const (
+	// DefaultArchiveConfigMap is the ConfigMap of a game namespace keeping
+	// the results of the games already replaced by a new one.
+	DefaultArchiveConfigMap = "podsweeper-history"
+
+	// ArchiveKey is the key holding the results JSON in the ConfigMap.
+	ArchiveKey = "results.json"
+
+	// GameKeyPrefix prefixes the keys archiving each game, see GameKey.
+	GameKeyPrefix = "game-"
+
+	// MaxArchived bounds the results kept per namespace.
+	MaxArchived = 20
+)
+
+// Archived is a game kept in the archive, browsable once its namespace
+// moved on to other games.
+type Archived struct {
+	Result Result `json:"result"`
+
+	// Board is the final board, as encoded by GameState.ToBoardString with
+	// RLE, which game.FromBoardString decodes.
+	Board string `json:"board"`
+
+	Moves []Move `json:"moves,omitempty"`
+}
+
+// Archive keeps the results of the past games of a namespace in a
+// ConfigMap, the most recent MaxArchived of them, along with the final
+// board and moves of the games archived with ArchiveGame.
+type Archive struct {
+	Client    client.Client
+	Namespace string
+
+	// Name of the ConfigMap. Defaults to DefaultArchiveConfigMap.
+	Name string
+}
+
+func (a Archive) key() client.ObjectKey {
+	name := a.Name
+	if name == "" {
+		name = DefaultArchiveConfigMap
+	}
+	return client.ObjectKey{Namespace: a.Namespace, Name: name}
+}
+
+// Results returns the archived results, oldest first.
+func (a Archive) Results(ctx context.Context) ([]Result, error) {
+	cm := &corev1.ConfigMap{}
+	err := a.Client.Get(ctx, a.key(), cm)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history configmap: %w", err)
+	}
+
+	var archived []Result
+	if raw := cm.Data[ArchiveKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &archived); err != nil {
+			return nil, fmt.Errorf("invalid history configmap %s: %w", a.key(), err)
+		}
+	}
+	return archived, nil
+}
+
+// Game returns the archived game with id, nil if it isn't archived, or
+// was dropped from the archive.
+func (a Archive) Game(ctx context.Context, id string) (*Archived, error) {
+	cm := &corev1.ConfigMap{}
+	err := a.Client.Get(ctx, a.key(), cm)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history configmap: %w", err)
+	}
+	raw, ok := cm.Data[GameKey(id)]
+	if !ok {
+		return nil, nil
+	}
+	archived := &Archived{}
+	if err := json.Unmarshal([]byte(raw), archived); err != nil {
+		return nil, fmt.Errorf("invalid archive of game %s: %w", id, err)
+	}
+	return archived, nil
+}
+
+// Record appends the result of a game to the archive, or replaces the
+// result archived earlier for the same game.
+func (a Archive) Record(ctx context.Context, result Result) error {
+	return a.record(ctx, result, "")
+}
+
+// ArchiveGame archives a game with its final board and moves, replacing
+// any earlier archive of the same game.
+func (a Archive) ArchiveGame(ctx context.Context, g Game) error {
+	archived := Archived{
+		Result: ResultOf(g),
+		Board:  g.State.ToBoardString(game.WithRLE()),
+		Moves:  MovesOf(g),
+	}
+	raw, err := json.Marshal(archived)
+	if err != nil {
+		return fmt.Errorf("failed to encode game %s: %w", archived.Result.GameID, err)
+	}
+	return a.record(ctx, archived.Result, string(raw))
+}
+
+// record stores result, and the archive of its game unless empty, keeping
+// the games of the most recent MaxArchived results only.
+func (a Archive) record(ctx context.Context, result Result, archived string) error {
+	cm := &corev1.ConfigMap{}
+	err := a.Client.Get(ctx, a.key(), cm)
+	exists := err == nil
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get history configmap: %w", err)
+	}
+	if !exists {
+		key := a.key()
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	var results []Result
+	if raw := cm.Data[ArchiveKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &results); err != nil {
+			return fmt.Errorf("invalid history configmap %s: %w", a.key(), err)
+		}
+	}
+	i := slices.IndexFunc(results, func(r Result) bool { return r.GameID == result.GameID })
+	if i >= 0 {
+		results[i] = result
+	} else {
+		results = append(results, result)
+	}
+	if len(results) > MaxArchived {
+		results = results[len(results)-MaxArchived:]
+	}
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	cm.Data[ArchiveKey] = string(raw)
+	if archived != "" {
+		cm.Data[GameKey(result.GameID)] = archived
+	}
+	// Games dropped from the results are dropped from the archive
+	for key := range cm.Data {
+		id, ok := strings.CutPrefix(key, GameKeyPrefix)
+		id = strings.TrimSuffix(id, ".json")
+		if ok && !slices.ContainsFunc(results, func(r Result) bool { return r.GameID == id }) {
+			delete(cm.Data, key)
+		}
+	}
+
+	if !exists {
+		if err := a.Client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create history configmap: %w", err)
+		}
+		return nil
+	}
+	if err := a.Client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update history configmap: %w", err)
+	}
+	return nil
+}
+
+// GameKey returns the key of the ConfigMap archiving the game with id.
+func GameKey(id string) string {
+	return GameKeyPrefix + id + ".json"
+}
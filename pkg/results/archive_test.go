@@ -0,0 +1,85 @@
+package results
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	archive := Archive{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Namespace: "game"}
+
+	if got, err := archive.Results(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("expected an empty archive, got %v: %v", got, err)
+	}
+	for i := range MaxArchived + 2 {
+		if err := archive.Record(ctx, Result{GameID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	got, err := archive.Results(ctx)
+	if err != nil {
+		t.Fatalf("Results failed: %v", err)
+	}
+	if len(got) != MaxArchived || got[0].GameID != "2" || got[len(got)-1].GameID != fmt.Sprint(MaxArchived+1) {
+		t.Errorf("expected the last %d results, oldest first, got %+v", MaxArchived, got)
+	}
+}
+
+func TestArchiveGame(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	archive := Archive{Client: c, Namespace: "game"}
+
+	state := newFinishedGame(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC))
+	if err := archive.ArchiveGame(ctx, Game{Namespace: "game", State: state}); err != nil {
+		t.Fatalf("ArchiveGame failed: %v", err)
+	}
+	// Archiving the same game again replaces it
+	if err := archive.ArchiveGame(ctx, Game{Namespace: "game", State: state}); err != nil {
+		t.Fatalf("ArchiveGame failed: %v", err)
+	}
+	if got, _ := archive.Results(ctx); len(got) != 1 || got[0].GameID != state.ID() {
+		t.Errorf("expected the game to be archived once, got %+v", got)
+	}
+
+	archived, err := archive.Game(ctx, state.ID())
+	if err != nil || archived == nil {
+		t.Fatalf("expected the game to be archived: %v", err)
+	}
+	if len(archived.Moves) != 2 || archived.Result.Status != game.StatusLost {
+		t.Errorf("expected the result and both moves, got %+v", archived)
+	}
+	board, err := game.FromBoardString(archived.Board)
+	if err != nil || !board.IsMine(1, 1) || !board.IsRevealed(0, 0) {
+		t.Errorf("expected the final board to be restored, got %q: %v", archived.Board, err)
+	}
+	if archived, err := archive.Game(ctx, "unknown"); archived != nil || err != nil {
+		t.Errorf("expected no unknown game, got %+v: %v", archived, err)
+	}
+
+	// Games dropped from the results are dropped from the archive
+	for i := range MaxArchived {
+		if err := archive.Record(ctx, Result{GameID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	cm := &corev1.ConfigMap{}
+	_ = c.Get(ctx, archive.key(), cm)
+	if _, ok := cm.Data[GameKey(state.ID())]; ok {
+		t.Error("expected the oldest game to be dropped")
+	}
+}
@@ -0,0 +1,299 @@
+// Package results exports finished games and their moves as CSV or JSON,
+// for spreadsheets and post-workshop analysis.
+package results
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// Formats supported by the export.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Move outcomes.
+const (
+	OutcomeSafe    = "safe"
+	OutcomeMine    = "mine"
+	OutcomeDefused = "defused"
+)
+
+// Game is a finished game and the namespace it was played in.
+type Game struct {
+	Namespace string
+	State     *game.GameState
+}
+
+// History lists finished games.
+type History interface {
+	Finished(ctx context.Context) ([]Game, error)
+}
+
+// StoreHistory is the history of a single game store. The store only keeps
+// the current game, so the history holds at most one game.
+type StoreHistory struct {
+	Store     game.Store
+	Namespace string
+}
+
+// Finished returns the game of the store if it has ended.
+func (h StoreHistory) Finished(ctx context.Context) ([]Game, error) {
+	state, err := h.Store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game state: %w", err)
+	}
+	if state == nil || state.Status == game.StatusPlaying {
+		return nil, nil
+	}
+	return []Game{{Namespace: h.Namespace, State: state}}, nil
+}
+
+// Result is the outcome of a finished game, one spreadsheet row per game.
+type Result struct {
+	GameID         string          `json:"gameId"`
+	Namespace      string          `json:"namespace,omitempty"`
+	Status         game.GameStatus `json:"status"`
+	Level          int             `json:"level"`
+	Width          int             `json:"width"`
+	Height         int             `json:"height"`
+	Mines          int             `json:"mines"`
+	Clicks         int             `json:"clicks"`
+	Progress       float64         `json:"progress"`
+	Score          int             `json:"score"`
+	ElapsedSeconds float64         `json:"elapsedSeconds"`
+	StartedAt      time.Time       `json:"startedAt"`
+	EndedAt        time.Time       `json:"endedAt,omitzero"`
+	Players        []string        `json:"players,omitempty"`
+	Cheaters       []string        `json:"cheaters,omitempty"`
+	Resigned       bool            `json:"resigned,omitempty"`
+}
+
+// Move is a cell a player clicked, one spreadsheet row per move. Cells
+// revealed by the flood fill of an empty cell are not moves.
+type Move struct {
+	GameID    string    `json:"gameId"`
+	Namespace string    `json:"namespace,omitempty"`
+	Seq       int       `json:"seq"`
+	X         int       `json:"x"`
+	Y         int       `json:"y"`
+	Player    string    `json:"player,omitempty"`
+	At        time.Time `json:"at,omitzero"`
+	Outcome   string    `json:"outcome"`
+}
+
+// ResultOf summarizes a finished game.
+func ResultOf(g Game) Result {
+	stats := g.State.Stats()
+	players := make([]string, 0)
+	for player := range g.State.Contributions() {
+		players = append(players, player)
+	}
+	sort.Strings(players)
+
+	return Result{
+		GameID:         g.State.ID(),
+		Namespace:      g.Namespace,
+		Status:         stats.Status,
+		Level:          stats.Level,
+		Width:          stats.Width,
+		Height:         stats.Height,
+		Mines:          stats.Mines,
+		Clicks:         stats.Clicks,
+		Progress:       stats.Progress,
+		Score:          stats.Score,
+		ElapsedSeconds: stats.ElapsedSeconds,
+		StartedAt:      g.State.StartedAt,
+		EndedAt:        g.State.EndedAt,
+		Players:        players,
+		Cheaters:       stats.Cheaters,
+		Resigned:       stats.Resigned,
+	}
+}
+
+// MovesOf returns the moves of a game in the order they were played, from
+// its move log. Games without one are read from their cells: moves without
+// a time come last, by coordinates.
+func MovesOf(g Game) []Move {
+	if len(g.State.Moves) > 0 {
+		moves := make([]Move, len(g.State.Moves))
+		for i, m := range g.State.Moves {
+			moves[i] = Move{
+				GameID:    g.State.ID(),
+				Namespace: g.Namespace,
+				Seq:       i + 1,
+				X:         m.X,
+				Y:         m.Y,
+				Player:    m.By,
+				At:        m.At,
+				Outcome:   string(m.Result),
+			}
+		}
+		return moves
+	}
+
+	var moves []Move
+	w, h := g.State.Dimensions()
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			cell := g.State.Cells[x][y]
+			if (!cell.Revealed && !cell.Defused) || cell.Propagated {
+				continue
+			}
+			outcome := OutcomeSafe
+			switch {
+			case cell.Defused:
+				outcome = OutcomeDefused
+			case cell.Mine:
+				outcome = OutcomeMine
+			}
+			moves = append(moves, Move{
+				GameID:    g.State.ID(),
+				Namespace: g.Namespace,
+				X:         x,
+				Y:         y,
+				Player:    cell.RevealedBy,
+				At:        cell.RevealedAt,
+				Outcome:   outcome,
+			})
+		}
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		a, b := moves[i].At, moves[j].At
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return a.Before(b)
+	})
+	for i := range moves {
+		moves[i].Seq = i + 1
+	}
+	return moves
+}
+
+// Collect returns the results and moves of every finished game of history.
+func Collect(ctx context.Context, history History) ([]Result, []Move, error) {
+	games, err := history.Finished(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]Result, 0, len(games))
+	moves := make([]Move, 0)
+	for _, g := range games {
+		results = append(results, ResultOf(g))
+		moves = append(moves, MovesOf(g)...)
+	}
+	return results, moves, nil
+}
+
+// WriteResultsCSV writes results as CSV with a header row. Lists are
+// separated by spaces.
+func WriteResultsCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"game_id", "namespace", "status", "level", "width", "height", "mines", "clicks",
+		"progress", "score", "elapsed_seconds", "started_at", "ended_at", "players", "cheaters", "resigned"})
+	for _, r := range results {
+		_ = cw.Write([]string{
+			r.GameID, r.Namespace, string(r.Status), strconv.Itoa(r.Level), strconv.Itoa(r.Width),
+			strconv.Itoa(r.Height), strconv.Itoa(r.Mines), strconv.Itoa(r.Clicks),
+			strconv.FormatFloat(r.Progress, 'f', -1, 64), strconv.Itoa(r.Score),
+			strconv.FormatFloat(r.ElapsedSeconds, 'f', 0, 64), formatTime(r.StartedAt), formatTime(r.EndedAt),
+			strings.Join(r.Players, " "), strings.Join(r.Cheaters, " "), strconv.FormatBool(r.Resigned),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMovesCSV writes moves as CSV with a header row.
+func WriteMovesCSV(w io.Writer, moves []Move) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"game_id", "namespace", "seq", "x", "y", "player", "at", "outcome"})
+	for _, m := range moves {
+		_ = cw.Write([]string{
+			m.GameID, m.Namespace, strconv.Itoa(m.Seq), strconv.Itoa(m.X), strconv.Itoa(m.Y),
+			m.Player, formatTime(m.At), m.Outcome,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write writes the results, or the moves, of history in format.
+func Write(ctx context.Context, w io.Writer, history History, format string, moves bool) error {
+	if format != FormatCSV && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q, use %s or %s", format, FormatCSV, FormatJSON)
+	}
+	results, allMoves, err := Collect(ctx, history)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format == FormatJSON && moves:
+		return json.NewEncoder(w).Encode(allMoves)
+	case format == FormatJSON:
+		return json.NewEncoder(w).Encode(results)
+	case moves:
+		return WriteMovesCSV(w, allMoves)
+	default:
+		return WriteResultsCSV(w, results)
+	}
+}
+
+// Handler serves the export: ?format=csv|json (default csv) and
+// ?data=results|moves (default results).
+func Handler(history History) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = FormatCSV
+		}
+		data := r.URL.Query().Get("data")
+		if data != "" && data != "results" && data != "moves" {
+			http.Error(w, "data must be results or moves", http.StatusBadRequest)
+			return
+		}
+		if format != FormatCSV && format != FormatJSON {
+			http.Error(w, "format must be csv or json", http.StatusBadRequest)
+			return
+		}
+
+		if format == FormatJSON {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "text/csv")
+			name := "podsweeper-results.csv"
+			if data == "moves" {
+				name = "podsweeper-moves.csv"
+			}
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		}
+		if err := Write(r.Context(), w, history, format, data == "moves"); err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to export results")
+			http.Error(w, "failed to export results", http.StatusInternalServerError)
+		}
+	})
+}
+
+// formatTime formats t as RFC 3339, or empty if zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
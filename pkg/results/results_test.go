@@ -0,0 +1,139 @@
+package results
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// newFinishedGame returns a lost 3x3 game: alice opened (0,0) at t0, which
+// has a hint, then bob hit the mine at (1,1) a minute later.
+func newFinishedGame(t0 time.Time) *game.GameState {
+	state := game.NewGameState(3, 7)
+	state.StartedAt = t0
+	state.SetMine(1, 1)
+	state.RevealWith(0, 0, game.RevealInfo{By: "alice", At: t0})
+	state.RevealWith(1, 1, game.RevealInfo{By: "bob", At: t0.Add(time.Minute)})
+	state.SetLost()
+	return state
+}
+
+func TestResultOf(t *testing.T) {
+	t0 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := newFinishedGame(t0)
+	state.RecordCheat("bob", "secrets/podsweeper-state", t0)
+
+	r := ResultOf(Game{Namespace: "ws-1", State: state})
+	if r.GameID != state.ID() || r.Namespace != "ws-1" || r.Status != game.StatusLost {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if len(r.Players) != 2 || r.Players[0] != "alice" || r.Players[1] != "bob" {
+		t.Errorf("expected sorted players, got %v", r.Players)
+	}
+	if len(r.Cheaters) != 1 {
+		t.Errorf("expected bob to be reported as a cheater, got %v", r.Cheaters)
+	}
+}
+
+func TestMovesOf(t *testing.T) {
+	t0 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := newFinishedGame(t0)
+	// Revealed without a time: listed last
+	state.RevealWith(2, 0, game.RevealInfo{By: "carol"})
+	state.Cells[2][0].RevealedAt = time.Time{}
+	// Flood fill reveals are not moves
+	state.RevealWith(2, 2, game.RevealInfo{By: "alice", At: t0, Propagated: true})
+
+	moves := MovesOf(Game{State: state})
+	if len(moves) != 3 {
+		t.Fatalf("expected 3 moves, got %+v", moves)
+	}
+	want := []struct {
+		x, y    int
+		player  string
+		outcome string
+	}{
+		{0, 0, "alice", OutcomeSafe},
+		{1, 1, "bob", OutcomeMine},
+		{2, 0, "carol", OutcomeSafe},
+	}
+	for i, w := range want {
+		m := moves[i]
+		if m.Seq != i+1 || m.X != w.x || m.Y != w.y || m.Player != w.player || m.Outcome != w.outcome {
+			t.Errorf("move %d = %+v, want %+v", i, m, w)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	history := StoreHistory{Store: store, Namespace: "game"}
+
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, history, FormatCSV, false); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	rows, _ := csv.NewReader(&buf).ReadAll()
+	if len(rows) != 1 {
+		t.Errorf("expected only a header without finished games, got %v", rows)
+	}
+
+	_ = store.Save(ctx, newFinishedGame(time.Now()))
+	buf.Reset()
+	if err := Write(ctx, &buf, history, FormatCSV, true); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 3 || rows[0][0] != "game_id" || rows[2][7] != OutcomeMine {
+		t.Errorf("unexpected moves CSV %v: %v", rows, err)
+	}
+
+	buf.Reset()
+	if err := Write(ctx, &buf, history, FormatJSON, false); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var results []Result
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil || len(results) != 1 {
+		t.Errorf("unexpected results JSON %q: %v", buf.String(), err)
+	}
+
+	if err := Write(ctx, &buf, history, "xml", false); err == nil {
+		t.Error("expected an unsupported format to fail")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	store := game.NewMemoryStore()
+	_ = store.Save(ctx, newFinishedGame(time.Now()))
+	handler := Handler(StoreHistory{Store: store})
+
+	tests := []struct {
+		query       string
+		wantCode    int
+		wantContent string
+	}{
+		{"", http.StatusOK, "text/csv"},
+		{"?format=json&data=moves", http.StatusOK, "application/json"},
+		{"?format=xml", http.StatusBadRequest, ""},
+		{"?data=cells", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export"+tt.query, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("%q: expected %d, got %d", tt.query, tt.wantCode, rec.Code)
+		}
+		if tt.wantContent != "" && rec.Header().Get("Content-Type") != tt.wantContent {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.wantContent, rec.Header().Get("Content-Type"))
+		}
+	}
+}
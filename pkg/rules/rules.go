@@ -0,0 +1,182 @@
+// Package rules evaluates the custom rules of levels and variants, see
+// game.Rules. Rules are CEL expressions over the stats of the game, so new
+// game modes can be declared with presets instead of written in Go:
+//
+//	width: 16
+//	height: 16
+//	mineDensity: 0.15
+//	rules:
+//	  lose: elapsedSeconds > 300.0
+//	  score: score * 2
+//	  obstacles: level + 4
+//
+// Rules are evaluated with these variables:
+//
+//	level, width, height, mines, clicks, revealed, remainingSafe,
+//	flagged, hintPods, shifts, cheaters (int)
+//	progress, elapsedSeconds (double)
+//	hotCold, tutorial, speedrun (bool)
+//	score (int, the built-in score)
+package rules
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// MaxCost bounds the cost of evaluating a rule, so a rule can't stall the
+// Gamemaster.
+const MaxCost = 10000
+
+// The variables of rules, by type, see the package doc.
+var (
+	intVariables    = []string{"level", "width", "height", "mines", "clicks", "revealed", "remainingSafe", "flagged", "hintPods", "shifts", "cheaters", "score"}
+	doubleVariables = []string{"progress", "elapsedSeconds"}
+	boolVariables   = []string{"hotCold", "tutorial", "speedrun"}
+)
+
+// Program is a compiled game.Rules.
+type Program struct {
+	lose, score, obstacles cel.Program
+}
+
+// compiled caches the programs of the rules already seen, by rules.
+var compiled sync.Map
+
+// Compile compiles rules, checking that each rule has the expected type:
+// bool for Lose, int for Score and Obstacles.
+func Compile(rules game.Rules) (*Program, error) {
+	if p, ok := compiled.Load(rules); ok {
+		return p.(*Program), nil
+	}
+
+	var opts []cel.EnvOption
+	for _, v := range intVariables {
+		opts = append(opts, cel.Variable(v, cel.IntType))
+	}
+	for _, v := range doubleVariables {
+		opts = append(opts, cel.Variable(v, cel.DoubleType))
+	}
+	for _, v := range boolVariables {
+		opts = append(opts, cel.Variable(v, cel.BoolType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule environment: %w", err)
+	}
+
+	p := &Program{}
+	if p.lose, err = compile(env, "lose", rules.Lose, cel.BoolType); err != nil {
+		return nil, err
+	}
+	if p.score, err = compile(env, "score", rules.Score, cel.IntType); err != nil {
+		return nil, err
+	}
+	if p.obstacles, err = compile(env, "obstacles", rules.Obstacles, cel.IntType); err != nil {
+		return nil, err
+	}
+	compiled.Store(rules, p)
+	return p, nil
+}
+
+// compile compiles the rule called name, nil if empty.
+func compile(env *cel.Env, name, expr string, want *cel.Type) (cel.Program, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid %s rule %q: %w", name, expr, issues.Err())
+	}
+	if !ast.OutputType().IsExactType(want) {
+		return nil, fmt.Errorf("%s rule %q must be %s, not %s", name, expr, want, ast.OutputType())
+	}
+	prg, err := env.Program(ast, cel.CostLimit(MaxCost))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s rule %q: %w", name, expr, err)
+	}
+	return prg, nil
+}
+
+// variables returns the variables state is evaluated with.
+func variables(state *game.GameState) map[string]any {
+	stats := state.Stats()
+	return map[string]any{
+		"level":          stats.Level,
+		"width":          stats.Width,
+		"height":         stats.Height,
+		"mines":          stats.Mines,
+		"clicks":         stats.Clicks,
+		"revealed":       stats.RevealedCells,
+		"remainingSafe":  stats.RemainingSafe,
+		"flagged":        stats.FlaggedCells,
+		"hintPods":       stats.HintPodsPlaced,
+		"shifts":         state.Shifts,
+		"cheaters":       len(stats.Cheaters),
+		"score":          stats.Score - state.ScoreBonus,
+		"progress":       stats.Progress,
+		"elapsedSeconds": stats.ElapsedSeconds,
+		"hotCold":        state.HotCold(),
+		"tutorial":       state.Tutorial,
+		"speedrun":       state.Speedrun,
+	}
+}
+
+// eval evaluates prg against vars.
+func eval(prg cel.Program, vars map[string]any) (any, error) {
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return out.Value(), nil
+}
+
+// Apply evaluates the rules of state after a move: the score rule sets its
+// ScoreBonus, and it reports whether the lose rule ends the game. States
+// without rules are left alone.
+func Apply(state *game.GameState) (lost bool, err error) {
+	if state.Rules == nil {
+		return false, nil
+	}
+	p, err := Compile(*state.Rules)
+	if err != nil {
+		return false, err
+	}
+	vars := variables(state)
+	if p.score != nil {
+		score, err := eval(p.score, vars)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate the score rule: %w", err)
+		}
+		state.ScoreBonus = int(score.(int64)) - vars["score"].(int)
+	}
+	if p.lose == nil || state.Status != game.StatusPlaying {
+		return false, nil
+	}
+	lose, err := eval(p.lose, vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the lose rule: %w", err)
+	}
+	return lose.(bool), nil
+}
+
+// ObstacleLevel returns the level of the security obstacles of state: the
+// obstacles rule, or the game level without one.
+func ObstacleLevel(state *game.GameState) (int, error) {
+	if state.Rules == nil || state.Rules.Obstacles == "" {
+		return state.Level, nil
+	}
+	p, err := Compile(*state.Rules)
+	if err != nil {
+		return state.Level, err
+	}
+	level, err := eval(p.obstacles, variables(state))
+	if err != nil {
+		return state.Level, fmt.Errorf("failed to evaluate the obstacles rule: %w", err)
+	}
+	return int(level.(int64)), nil
+}
@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// newRuledGame returns a level 2 game with one safe cell revealed out of 3.
+func newRuledGame(rules game.Rules) *game.GameState {
+	state := game.NewGameState(2, 12345)
+	state.Level = 2
+	state.SetMine(0, 0)
+	state.Reveal(1, 1)
+	state.Rules = &rules
+	return state
+}
+
+func TestCompile(t *testing.T) {
+	if _, err := Compile(game.Rules{Lose: "clicks > 3 && !hotCold", Score: "score + int(progress)", Obstacles: "level + 4"}); err != nil {
+		t.Errorf("expected valid rules, got %v", err)
+	}
+	for _, rules := range []game.Rules{
+		{Lose: "clicks >"},
+		{Lose: "clicks"},
+		{Score: "score * 1.5"},
+		{Obstacles: "unknown + 1"},
+	} {
+		if _, err := Compile(rules); err == nil {
+			t.Errorf("expected %+v to be rejected", rules)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	state := newRuledGame(game.Rules{Lose: "clicks >= 2", Score: "score * 3"})
+	base := state.Stats().Score
+
+	lost, err := Apply(state)
+	if err != nil || lost {
+		t.Fatalf("expected the game to go on after one click, got %v, %v", lost, err)
+	}
+	if got := state.Stats().Score; got != 3*base {
+		t.Errorf("expected the score to be tripled to %d, got %d", 3*base, got)
+	}
+	// The bonus is recomputed from the built-in score, never compounded
+	_, _ = Apply(state)
+	if state.Stats().Score != 3*base {
+		t.Errorf("expected the score to stay %d, got %d", 3*base, state.Stats().Score)
+	}
+
+	state.Reveal(1, 0)
+	if lost, err := Apply(state); err != nil || !lost {
+		t.Errorf("expected the second click to lose, got %v, %v", lost, err)
+	}
+
+	if lost, err := Apply(game.NewGameState(2, 1)); err != nil || lost {
+		t.Errorf("expected games without rules to be left alone, got %v, %v", lost, err)
+	}
+}
+
+func TestObstacleLevel(t *testing.T) {
+	if level, err := ObstacleLevel(newRuledGame(game.Rules{})); err != nil || level != 2 {
+		t.Errorf("expected the game level without rule, got %d, %v", level, err)
+	}
+	if level, err := ObstacleLevel(newRuledGame(game.Rules{Obstacles: "level + 4"})); err != nil || level != 6 {
+		t.Errorf("expected level 6, got %d, %v", level, err)
+	}
+}
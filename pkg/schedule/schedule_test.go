@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, time.March, 4, 10, 31, 0, 0, time.UTC)},
+		{"monday morning", "0 9 * * 1", time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)},
+		{"named day", "0 9 * * mon", time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"later today", "45 10 * * *", time.Date(2026, time.March, 4, 10, 45, 0, 0, time.UTC)},
+		{"step", "*/20 * * * *", time.Date(2026, time.March, 4, 10, 40, 0, 0, time.UTC)},
+		{"range and list", "0 8-9,17 * * *", time.Date(2026, time.March, 4, 17, 0, 0, 0, time.UTC)},
+		{"weekdays", "0 9 * * mon-fri", time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC)},
+		{"next month", "0 0 1 * *", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 jan *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 13 * 5", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{"shorthand", "@weekly", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 feb *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.spec, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext_Location(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	s, _ := Parse("0 9 * * 1")
+	got := s.Next(time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC).In(paris))
+	if want := time.Date(2026, time.March, 9, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected 09:00 in Paris, got %v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * fun",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"@fortnightly",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected %q to be invalid", spec)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package scoring scores finished games on the classic Minesweeper metrics:
+// the 3BV of the board, the minimum number of clicks clearing it, the
+// efficiency of the players, 3BV per click, and a time-based score from the
+// 3BV cleared per second. Unlike the score of the game stats, they compare
+// games across board sizes.
+package scoring
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+// The annotations showing the score card, on the victory pod and the game
+// namespace.
+const (
+	// AnnotationThreeBV is the 3BV of the board.
+	AnnotationThreeBV = "podsweeper.io/3bv"
+
+	// AnnotationEfficiency is the efficiency of a won game, like "83%".
+	AnnotationEfficiency = "podsweeper.io/efficiency"
+
+	// AnnotationTimeScore is the time-based score of a won game.
+	AnnotationTimeScore = "podsweeper.io/time-score"
+)
+
+// AnnotationKeys lists the annotations of Annotations.
+var AnnotationKeys = []string{AnnotationThreeBV, AnnotationEfficiency, AnnotationTimeScore}
+
+// Score computes the score card of state. Efficiency and time score are
+// only given to won games.
+func Score(state *game.GameState) game.ScoreCard {
+	card := game.ScoreCard{
+		ThreeBV: grid.ThreeBV(state),
+		Clicks:  len(results.MovesOf(results.Game{State: state})),
+	}
+	if state.Status != game.StatusWon {
+		return card
+	}
+	if card.Clicks > 0 {
+		card.Efficiency = float64(100*card.ThreeBV) / float64(card.Clicks)
+	}
+	if seconds := state.Elapsed().Seconds(); seconds > 0 {
+		card.ThreeBVPerSecond = float64(card.ThreeBV) / seconds
+		card.TimeScore = int(math.Round(1000 * card.ThreeBVPerSecond))
+	}
+	return card
+}
+
+// Record scores the game of state once it ended, and writes the score card
+// into state.
+func Record(state *game.GameState) game.ScoreCard {
+	card := Score(state)
+	state.ScoreCard = &card
+	return card
+}
+
+// Annotations returns the annotations showing card, none for a nil card.
+func Annotations(card *game.ScoreCard) map[string]string {
+	if card == nil {
+		return nil
+	}
+	annotations := map[string]string{AnnotationThreeBV: strconv.Itoa(card.ThreeBV)}
+	if card.Efficiency > 0 {
+		annotations[AnnotationEfficiency] = strconv.FormatFloat(card.Efficiency, 'f', 0, 64) + "%"
+		annotations[AnnotationTimeScore] = strconv.Itoa(card.TimeScore)
+	}
+	return annotations
+}
@@ -0,0 +1,58 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+// newTestGame returns a 4x2 board with mines at (0,0), (1,0) and (3,1),
+// 3BV 5, with (0,1) played:
+//
+//	M M 2 1
+//	2 2 2 M
+func newTestGame() *game.GameState {
+	state := game.NewRectGameState(4, 2, 1)
+	state.SetMine(0, 0)
+	state.SetMine(1, 0)
+	state.SetMine(3, 1)
+	state.RevealBy(0, 1, "alice")
+	return state
+}
+
+func TestScore(t *testing.T) {
+	state := newTestGame()
+	state.SetLost()
+	card := Score(state)
+	if card.ThreeBV != 5 || card.Clicks != 1 || card.Efficiency != 0 || card.TimeScore != 0 {
+		t.Errorf("expected only 3BV and clicks for a lost game, got %+v", card)
+	}
+
+	state = newTestGame()
+	for _, c := range []game.Coordinate{{X: 1, Y: 1}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 3, Y: 0}} {
+		state.RevealBy(c.X, c.Y, "alice")
+	}
+	state.SetWon()
+	state.StartedAt = state.EndedAt.Add(-10 * time.Second)
+	card = Record(state)
+	if card.Efficiency != 100 || card.ThreeBVPerSecond != 0.5 || card.TimeScore != 500 {
+		t.Errorf("unexpected score card of a perfect game: %+v", card)
+	}
+	if state.ScoreCard == nil || *state.ScoreCard != card {
+		t.Errorf("expected the score card written into the state, got %+v", state.ScoreCard)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	if Annotations(nil) != nil {
+		t.Error("expected no annotations without a score card")
+	}
+	got := Annotations(&game.ScoreCard{ThreeBV: 10, Clicks: 12, Efficiency: 83.3, TimeScore: 420})
+	if got[AnnotationThreeBV] != "10" || got[AnnotationEfficiency] != "83%" || got[AnnotationTimeScore] != "420" {
+		t.Errorf("unexpected annotations %v", got)
+	}
+	if got := Annotations(&game.ScoreCard{ThreeBV: 10}); len(got) != 1 {
+		t.Errorf("expected only the 3BV of a lost game, got %v", got)
+	}
+}
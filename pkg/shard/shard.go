@@ -0,0 +1,181 @@
+// Package shard spreads games over active-active Gamemaster replicas. Each
+// replica announces itself with a Lease labeled with its group, and every
+// game namespace is owned by one live member, chosen by rendezvous hashing:
+// all replicas agree on the owner as soon as they see the same members,
+// and a member joining or leaving only moves the games it takes or held.
+package shard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// LabelGroup is the label of member Leases holding their group.
+	LabelGroup = "podsweeper.io/shard-group"
+
+	// LeasePrefix prefixes the names of member Leases.
+	LeasePrefix = "podsweeper-shard-"
+
+	// DefaultGroup is the group of replicas sharing games.
+	DefaultGroup = "podsweeper"
+
+	// DefaultLeaseDuration is how long a member stays live without
+	// renewing its Lease.
+	DefaultLeaseDuration = 15 * time.Second
+)
+
+// Owner returns the member owning key: the one with the highest hash of
+// the member and key. Empty without members.
+func Owner(key string, members []string) string {
+	var owner string
+	var best uint64
+	for _, m := range members {
+		if score := weight(m, key); owner == "" || score > best || (score == best && m < owner) {
+			owner, best = m, score
+		}
+	}
+	return owner
+}
+
+// weight hashes a member and a key.
+func weight(member, key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	// FNV spreads poorly on close inputs: finish with a 64-bit mixer
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Membership announces a replica in its group, and lists the live members.
+// It runs as a manager Runnable on every replica.
+type Membership struct {
+	// Client manages the Leases.
+	Client client.Client
+
+	// Namespace holds the member Leases.
+	Namespace string
+
+	// Group names the replicas sharing games. Defaults to DefaultGroup.
+	Group string
+
+	// Identity names this replica. It must be a valid DNS label, such as
+	// the pod name.
+	Identity string
+
+	// LeaseDuration defaults to DefaultLeaseDuration. The Lease is renewed
+	// three times per duration.
+	LeaseDuration time.Duration
+}
+
+// Start implements manager.Runnable. The Lease is deleted on shutdown,
+// handing the games of the replica over right away.
+func (m *Membership) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("shard")
+	ticker := time.NewTicker(m.leaseDuration() / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Renew(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to renew shard membership")
+		}
+		select {
+		case <-ctx.Done():
+			lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: m.leaseName(), Namespace: m.Namespace}}
+			if err := client.IgnoreNotFound(m.Client.Delete(context.Background(), lease)); err != nil {
+				logger.Error(err, "failed to leave shard group")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: every
+// replica is a member.
+func (m *Membership) NeedLeaderElection() bool {
+	return false
+}
+
+// Renew creates or renews the Lease of this replica.
+func (m *Membership) Renew(ctx context.Context, now time.Time) error {
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: m.Namespace, Name: m.leaseName()}
+	err := m.Client.Get(ctx, key, lease)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get shard lease: %w", err)
+	}
+	lease.Name, lease.Namespace = key.Name, key.Namespace
+	lease.Labels = map[string]string{LabelGroup: m.group()}
+	lease.Spec.HolderIdentity = ptr.To(m.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.leaseDuration().Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+
+	if errors.IsNotFound(err) {
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		if err := m.Client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create shard lease: %w", err)
+		}
+		return nil
+	}
+	if err := m.Client.Update(ctx, lease); err != nil {
+		return fmt.Errorf("failed to renew shard lease: %w", err)
+	}
+	return nil
+}
+
+// Members returns the identities of the live members of the group, sorted.
+func (m *Membership) Members(ctx context.Context, now time.Time) ([]string, error) {
+	leases := &coordinationv1.LeaseList{}
+	if err := m.Client.List(ctx, leases, client.InNamespace(m.Namespace), client.MatchingLabels{LabelGroup: m.group()}); err != nil {
+		return nil, fmt.Errorf("failed to list shard leases: %w", err)
+	}
+	var members []string
+	for _, lease := range leases.Items {
+		spec := lease.Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if now.Before(expiry) {
+			members = append(members, *spec.HolderIdentity)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func (m *Membership) leaseName() string {
+	return LeasePrefix + m.Identity
+}
+
+func (m *Membership) group() string {
+	if m.Group == "" {
+		return DefaultGroup
+	}
+	return m.Group
+}
+
+func (m *Membership) leaseDuration() time.Duration {
+	if m.LeaseDuration <= 0 {
+		return DefaultLeaseDuration
+	}
+	return m.LeaseDuration
+}
@@ -0,0 +1,90 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOwner(t *testing.T) {
+	members := []string{"gm-a", "gm-b", "gm-c"}
+	if Owner("ws-1", nil) != "" {
+		t.Error("expected no owner without members")
+	}
+
+	counts := map[string]int{}
+	moved := 0
+	for i := range 300 {
+		ns := fmt.Sprintf("ws-%d", i)
+		owner := Owner(ns, members)
+		if reversed := Owner(ns, []string{"gm-c", "gm-b", "gm-a"}); reversed != owner {
+			t.Fatalf("owner of %s depends on member order: %s vs %s", ns, owner, reversed)
+		}
+		counts[owner]++
+
+		// Only the games of a leaving member move
+		after := Owner(ns, []string{"gm-a", "gm-c"})
+		if owner != "gm-b" && after != owner {
+			t.Fatalf("%s moved from %s to %s", ns, owner, after)
+		}
+		if after != owner {
+			moved++
+		}
+	}
+	for _, m := range members {
+		if counts[m] < 60 {
+			t.Errorf("unbalanced shards: %v", counts)
+		}
+	}
+	if moved != counts["gm-b"] {
+		t.Errorf("expected the %d games of gm-b to move, %d did", counts["gm-b"], moved)
+	}
+}
+
+func TestMembership(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = coordinationv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	now := time.Now()
+
+	a := &Membership{Client: c, Namespace: "system", Identity: "gm-a"}
+	b := &Membership{Client: c, Namespace: "system", Identity: "gm-b"}
+	other := &Membership{Client: c, Namespace: "system", Group: "other", Identity: "gm-x"}
+	for _, m := range []*Membership{a, b, other} {
+		if err := m.Renew(ctx, now); err != nil {
+			t.Fatalf("Renew failed: %v", err)
+		}
+	}
+	// Renewing again updates the Lease
+	if err := a.Renew(ctx, now.Add(10*time.Second)); err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []string
+	}{
+		{"both live", now.Add(time.Second), []string{"gm-a", "gm-b"}},
+		{"b expired", now.Add(DefaultLeaseDuration + time.Second), []string{"gm-a"}},
+		{"all expired", now.Add(time.Minute), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members, err := a.Members(ctx, tt.at)
+			if err != nil {
+				t.Fatalf("Members failed: %v", err)
+			}
+			if !reflect.DeepEqual(members, tt.want) {
+				t.Errorf("Members() = %v, want %v", members, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package spawner
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrepullName names the DaemonSet pre-pulling the game images.
+const PrepullName = "podsweeper-prepull"
+
+// PrepullObjects returns the DaemonSet pulling the game images on every
+// node ahead of the games, so boards spawn in seconds instead of waiting
+// for image pulls: busybox for cells and end-of-game pods, and the hint
+// agent for hints and exec reveal cells, in init containers exiting at
+// once. The pods then run the pause image of the tiny profile, which pins
+// the images in the node caches. Their labels are not the game pod labels,
+// so clearing the board leaves them alone, but they belong out of the game
+// namespace, where players would see them.
+func PrepullObjects(namespace string, labels map[string]string) []client.Object {
+	podLabels := map[string]string{LabelApp: PrepullName}
+	requests := corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1m"),
+		corev1.ResourceMemory: resource.MustParse("8Mi"),
+	}}
+	container := func(name, image string) corev1.Container {
+		return corev1.Container{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: RestrictedSecurityContext(),
+			Resources:       requests,
+		}
+	}
+	busybox := container("busybox", CellImage)
+	busybox.Command = []string{"true"}
+	agent := container("hint-agent", AgentImage)
+	agent.Args = []string{"prepull"}
+
+	return []client.Object{&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: PrepullName, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					SecurityContext: RestrictedPodSecurityContext(),
+					InitContainers:  []corev1.Container{busybox, agent},
+					Containers:      []corev1.Container{container("pause", PauseImage)},
+				},
+			},
+		},
+	}}
+}
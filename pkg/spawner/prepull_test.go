@@ -0,0 +1,35 @@
+package spawner
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestPrepullObjects(t *testing.T) {
+	objs := PrepullObjects("podsweeper-system", nil)
+	if len(objs) != 1 {
+		t.Fatalf("expected the DaemonSet only, got %d objects", len(objs))
+	}
+	ds, ok := objs[0].(*appsv1.DaemonSet)
+	if !ok || ds.Namespace != "podsweeper-system" || ds.Name != PrepullName {
+		t.Fatalf("expected the pre-pull DaemonSet in podsweeper-system, got %+v", objs[0])
+	}
+
+	spec := ds.Spec.Template.Spec
+	images := map[string]bool{}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		images[c.Image] = true
+	}
+	for _, image := range []string{CellImage, AgentImage, PauseImage} {
+		if !images[image] {
+			t.Errorf("expected %s to be pre-pulled, got %v", image, images)
+		}
+	}
+	if ds.Spec.Template.Labels[LabelApp] == "podsweeper" {
+		t.Error("expected pre-pull pods not to look like game pods")
+	}
+	if v := RestrictedViolations(&spec); len(v) != 0 {
+		t.Errorf("expected restricted pods, got %v", v)
+	}
+}
@@ -0,0 +1,136 @@
+package spawner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+// Profile bundles the resource settings of games, to fit the cluster they
+// are played on.
+type Profile string
+
+const (
+	// ProfileDefault runs a container in every game pod.
+	ProfileDefault Profile = "default"
+
+	// ProfileTiny keeps games playable on laptops and tiny clusters, such
+	// as kind or minikube with 2 CPUs: cell and resign pods run the pause
+	// image and are never scheduled, boards are at most TinyMaxDimension
+	// wide and high, and end-of-game pods print their message and exit
+	// instead of sleeping forever.
+	ProfileTiny Profile = "tiny"
+
+	// PauseImage is the cell image of the tiny profile.
+	PauseImage = "registry.k8s.io/pause:3.10"
+
+	// SchedulingGate keeps the cell and resign pods of the tiny profile
+	// from being scheduled: players only need them to exist to delete them.
+	SchedulingGate = "podsweeper.io/unscheduled-cell"
+
+	// TinyMaxDimension is the largest width or height of tiny boards.
+	TinyMaxDimension = 8
+)
+
+// ParseProfile returns the profile named s, ProfileDefault if empty.
+func ParseProfile(s string) (Profile, error) {
+	switch p := Profile(s); p {
+	case "":
+		return ProfileDefault, nil
+	case ProfileDefault, ProfileTiny:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown profile %q, expected %s or %s", s, ProfileDefault, ProfileTiny)
+	}
+}
+
+// String implements flag.Value.
+func (p *Profile) String() string {
+	if *p == "" {
+		return string(ProfileDefault)
+	}
+	return string(*p)
+}
+
+// Set implements flag.Value.
+func (p *Profile) Set(s string) error {
+	parsed, err := ParseProfile(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Args returns the Gamemaster flags selecting profile p, none for the
+// default profile.
+func (p Profile) Args() []string {
+	if p == "" || p == ProfileDefault {
+		return nil
+	}
+	return []string{"--profile=" + string(p)}
+}
+
+// CellImage returns the image of cell and resign pods.
+func (p Profile) CellImage() string {
+	if p == ProfileTiny {
+		return PauseImage
+	}
+	return CellImage
+}
+
+// Board returns config shrunk to the boards of profile p, keeping its
+// aspect ratio and mine density.
+func (p Profile) Board(config grid.Config) grid.Config {
+	w, h := config.Dimensions()
+	if p != ProfileTiny || max(w, h) <= TinyMaxDimension {
+		return config
+	}
+	scale := float64(TinyMaxDimension) / float64(max(w, h))
+	config.Width = max(2, int(math.Round(float64(w)*scale)))
+	config.Height = max(2, int(math.Round(float64(h)*scale)))
+	cells := scale * scale
+	config.MinMineCount = int(float64(config.MinMineCount) * cells)
+	if config.MaxMineCount > 0 {
+		config.MaxMineCount = max(config.MinMineCount, int(math.Ceil(float64(config.MaxMineCount)*cells)))
+	}
+	if o := config.Opening; o != nil && (o.X >= config.Width || o.Y >= config.Height) {
+		config.Opening = nil
+	}
+	return config
+}
+
+// EndCommand returns the command of an end-of-game pod showing message.
+func (p Profile) EndCommand(message string) []string {
+	if p == ProfileTiny {
+		return []string{"sh", "-c", echo(message)}
+	}
+	return EchoCommand(message)
+}
+
+// EchoCommand returns the command of a pod showing message until deleted.
+func EchoCommand(message string) []string {
+	return []string{"sh", "-c", echo(message) + " && sleep infinity"}
+}
+
+// echo returns the shell command printing message, single-quoted so custom
+// messages can't run commands.
+func echo(message string) string {
+	return "echo '" + strings.ReplaceAll(message, "'", `'\''`) + "'"
+}
+
+// gate holds the pod off the scheduler in the tiny profile.
+func (p Profile) gate(pod *corev1.Pod) {
+	if p != ProfileTiny {
+		return
+	}
+	pod.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: SchedulingGate}}
+	// The pause image has no shell
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Command = nil
+	}
+}
@@ -0,0 +1,113 @@
+package spawner
+
+import (
+	"slices"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Profile
+		wantErr bool
+	}{
+		{"", ProfileDefault, false},
+		{"default", ProfileDefault, false},
+		{"tiny", ProfileTiny, false},
+		{"huge", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseProfile(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProfile(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseProfile(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileBoard(t *testing.T) {
+	tests := []struct {
+		profile   Profile
+		preset    grid.DifficultyPreset
+		wantW     int
+		wantH     int
+		wantMines int
+	}{
+		{ProfileDefault, grid.DifficultyHard, 16, 16, 51},
+		{ProfileTiny, grid.DifficultyEasy, 8, 8, 6},
+		{ProfileTiny, grid.DifficultyHard, 8, 8, 12},
+		{ProfileTiny, grid.DifficultyExpert, 8, 8, 16},
+		{ProfileTiny, grid.DifficultyPanorama, 8, 3, 4},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.profile)+"/"+string(tt.preset), func(t *testing.T) {
+			config := tt.profile.Board(grid.GetDifficultyConfig(tt.preset))
+			if w, h := config.Dimensions(); w != tt.wantW || h != tt.wantH {
+				t.Errorf("expected %dx%d, got %dx%d", tt.wantW, tt.wantH, w, h)
+			}
+			if err := config.Validate(); err != nil {
+				t.Fatalf("invalid board: %v", err)
+			}
+			if mines := config.CalculateMineCount(); mines != tt.wantMines {
+				t.Errorf("expected %d mines, got %d", tt.wantMines, mines)
+			}
+		})
+	}
+}
+
+func TestProfileTinyPods(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	spawner := NewGridSpawner(fakeClient, GridSpawnerConfig{Namespace: testNamespace, Profile: ProfileTiny})
+
+	for _, pod := range []string{"cell", "resign"} {
+		t.Run(pod, func(t *testing.T) {
+			p := spawner.BuildResignPod("game")
+			if pod == "cell" {
+				p = spawner.BuildCellPod(game.Coordinate{X: 1, Y: 2}, "game")
+			}
+			if len(p.Spec.SchedulingGates) != 1 || p.Spec.SchedulingGates[0].Name != SchedulingGate {
+				t.Errorf("expected the pod to be gated, got %v", p.Spec.SchedulingGates)
+			}
+			container := p.Spec.Containers[0]
+			if container.Image != PauseImage || container.Command != nil {
+				t.Errorf("expected a bare pause container, got %s %v", container.Image, container.Command)
+			}
+			if len(container.Resources.Requests) != 0 {
+				t.Errorf("expected no resource requests, got %v", container.Resources.Requests)
+			}
+		})
+	}
+
+	// The default profile keeps scheduled busybox cells
+	cell := NewGridSpawner(fakeClient, GridSpawnerConfig{Namespace: testNamespace}).BuildCellPod(game.Coordinate{}, "game")
+	if cell.Spec.SchedulingGates != nil || cell.Spec.Containers[0].Image != CellImage {
+		t.Errorf("expected a scheduled busybox cell, got %+v", cell.Spec)
+	}
+}
+
+func TestProfileEndCommand(t *testing.T) {
+	if cmd := ProfileDefault.EndCommand("BOOM"); !slices.Equal(cmd, []string{"sh", "-c", "echo 'BOOM' && sleep infinity"}) {
+		t.Errorf("expected the default end pod to keep running, got %v", cmd)
+	}
+	if cmd := ProfileTiny.EndCommand("BOOM"); !slices.Equal(cmd, []string{"sh", "-c", "echo 'BOOM'"}) {
+		t.Errorf("expected the tiny end pod to exit, got %v", cmd)
+	}
+	if cmd := EchoCommand("C'est gagné"); !slices.Equal(cmd, []string{"sh", "-c", `echo 'C'\''est gagné' && sleep infinity`}) {
+		t.Errorf("expected the quote to be escaped, got %v", cmd)
+	}
+	if args := ProfileTiny.Args(); !slices.Equal(args, []string{"--profile=tiny"}) {
+		t.Errorf("unexpected Gamemaster args %v", args)
+	}
+	if args := ProfileDefault.Args(); args != nil {
+		t.Errorf("expected no args for the default profile, got %v", args)
+	}
+}
@@ -0,0 +1,77 @@
+package spawner
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// NonRootUID is the user and group game containers run as ("nobody"), since
+// images like busybox would otherwise run as root.
+const NonRootUID = 65534
+
+// RestrictedPodSecurityContext returns the pod security context required by
+// the restricted Pod Security Standard, so games run in namespaces
+// enforcing it.
+func RestrictedPodSecurityContext() *corev1.PodSecurityContext {
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:   ptr.To(true),
+		RunAsUser:      ptr.To(int64(NonRootUID)),
+		RunAsGroup:     ptr.To(int64(NonRootUID)),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// RestrictedSecurityContext returns the container security context required
+// by the restricted Pod Security Standard, with a read-only root filesystem.
+func RestrictedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		RunAsNonRoot:             ptr.To(true),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
+
+// RestrictedViolations lists why a pod spec would be rejected by the
+// restricted Pod Security Standard, or lacks the read-only root filesystem
+// game pods also use. It returns nil if the spec complies.
+func RestrictedViolations(spec *corev1.PodSpec) []string {
+	var violations []string
+	psc := spec.SecurityContext
+	if psc == nil {
+		psc = &corev1.PodSecurityContext{}
+	}
+
+	for _, c := range spec.Containers {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if !ptr.Deref(sc.RunAsNonRoot, ptr.Deref(psc.RunAsNonRoot, false)) {
+			violations = append(violations, fmt.Sprintf("container %q must set runAsNonRoot", c.Name))
+		}
+		if ptr.Deref(sc.RunAsUser, ptr.Deref(psc.RunAsUser, -1)) == 0 {
+			violations = append(violations, fmt.Sprintf("container %q must not run as user 0", c.Name))
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, fmt.Sprintf("container %q must set allowPrivilegeEscalation=false", c.Name))
+		}
+		if sc.Capabilities == nil || len(sc.Capabilities.Drop) != 1 || sc.Capabilities.Drop[0] != "ALL" ||
+			len(sc.Capabilities.Add) > 0 {
+			violations = append(violations, fmt.Sprintf("container %q must drop ALL capabilities", c.Name))
+		}
+		profile := psc.SeccompProfile
+		if sc.SeccompProfile != nil {
+			profile = sc.SeccompProfile
+		}
+		if profile == nil || (profile.Type != corev1.SeccompProfileTypeRuntimeDefault && profile.Type != corev1.SeccompProfileTypeLocalhost) {
+			violations = append(violations, fmt.Sprintf("container %q must set a RuntimeDefault or Localhost seccomp profile", c.Name))
+		}
+		if !ptr.Deref(sc.ReadOnlyRootFilesystem, false) {
+			violations = append(violations, fmt.Sprintf("container %q must use a read-only root filesystem", c.Name))
+		}
+	}
+	return violations
+}
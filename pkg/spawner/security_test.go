@@ -0,0 +1,63 @@
+package spawner
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+)
+
+func TestRestrictedViolations(t *testing.T) {
+	compliant := func() corev1.PodSpec {
+		return corev1.PodSpec{
+			SecurityContext: RestrictedPodSecurityContext(),
+			Containers:      []corev1.Container{{Name: "c", SecurityContext: RestrictedSecurityContext()}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*corev1.PodSpec)
+		want   int
+	}{
+		{name: "compliant", mutate: func(*corev1.PodSpec) {}},
+		{name: "no security context", mutate: func(s *corev1.PodSpec) {
+			s.SecurityContext = nil
+			s.Containers[0].SecurityContext = nil
+		}, want: 5},
+		{name: "root user", mutate: func(s *corev1.PodSpec) { s.Containers[0].SecurityContext.RunAsUser = ptr.To(int64(0)) }, want: 1},
+		{name: "privilege escalation", mutate: func(s *corev1.PodSpec) {
+			s.Containers[0].SecurityContext.AllowPrivilegeEscalation = ptr.To(true)
+		}, want: 1},
+		{name: "added capability", mutate: func(s *corev1.PodSpec) {
+			s.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_ADMIN"}
+		}, want: 1},
+		{name: "unconfined seccomp", mutate: func(s *corev1.PodSpec) {
+			s.SecurityContext.SeccompProfile.Type = corev1.SeccompProfileTypeUnconfined
+		}, want: 1},
+		{name: "writable root filesystem", mutate: func(s *corev1.PodSpec) {
+			s.Containers[0].SecurityContext.ReadOnlyRootFilesystem = nil
+		}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := compliant()
+			tt.mutate(&spec)
+			if got := RestrictedViolations(&spec); len(got) != tt.want {
+				t.Errorf("RestrictedViolations() = %v, want %d violations", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGridSpawner_BuildCellPodIsRestricted(t *testing.T) {
+	s := NewGridSpawner(fake.NewClientBuilder().WithScheme(newTestScheme()).Build(), GridSpawnerConfig{Namespace: testNamespace})
+	pod := s.BuildCellPod(game.Coordinate{X: 1, Y: 2}, "game")
+	if violations := RestrictedViolations(&pod.Spec); len(violations) > 0 {
+		t.Errorf("cell pods must meet the restricted profile: %v", violations)
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/messages"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 const (
@@ -36,6 +39,33 @@ const (
 	// LabelGameID is the game session identifier.
 	LabelGameID = "podsweeper.io/game-id"
 
+	// AnnotationVersion is the annotation storing the Gamemaster version that spawned the pod.
+	AnnotationVersion = "podsweeper.io/version"
+
+	// AnnotationNextStep is the annotation suggesting the first command to
+	// run in tutorial mode.
+	AnnotationNextStep = "podsweeper.io/next-step"
+
+	// AgentImage is the cell image of exec reveal games: the hint agent,
+	// which doubles as the cell agent exposing the reveal command.
+	AgentImage = "ghcr.io/zwindler/podsweeper-hint-agent:latest"
+
+	// EnvRevealURL is the environment variable telling the cell agent the
+	// Gamemaster URL serving /reveal.
+	EnvRevealURL = "GAMEMASTER_URL"
+
+	// EnvCoordX and EnvCoordY are the environment variables telling the
+	// agents the coordinates of their cell, see LabelEnv.
+	EnvCoordX = "POD_X"
+	EnvCoordY = "POD_Y"
+
+	// EnvGameID is the environment variable telling the agents the game
+	// their pod belongs to, see LabelEnv.
+	EnvGameID = "GAME_ID"
+
+	// ResignPodName names the pod players delete to resign the game.
+	ResignPodName = "resign"
+
 	// DefaultBatchSize is the default number of pods to create in parallel.
 	DefaultBatchSize = 10
 
@@ -54,6 +84,9 @@ type GridSpawner struct {
 	batchSize     int
 	retryAttempts int
 	retryDelay    time.Duration
+	profile       Profile
+	revealURL     string
+	messages      *messages.Catalog
 }
 
 // GridSpawnerConfig holds configuration for the GridSpawner.
@@ -63,6 +96,16 @@ type GridSpawnerConfig struct {
 	BatchSize     int
 	RetryAttempts int
 	RetryDelay    time.Duration
+	// Profile shapes the pods to the cluster. Defaults to ProfileDefault.
+	Profile Profile
+	// RevealURL is the Gamemaster URL serving /reveal, such as
+	// http://podsweeper-ui.podsweeper-system:8080. When set, cells run the
+	// cell agent, so players can reveal them by exec'ing its reveal
+	// command instead of deleting them.
+	RevealURL string
+	// Messages renders the messages of the cell and resign pods. Nil
+	// renders the built-in messages.
+	Messages *messages.Catalog
 }
 
 // SpawnResult contains the result of a spawn operation.
@@ -76,8 +119,14 @@ type SpawnResult struct {
 
 // NewGridSpawner creates a new GridSpawner.
 func NewGridSpawner(c client.Client, config GridSpawnerConfig) *GridSpawner {
+	if config.Profile == "" {
+		config.Profile = ProfileDefault
+	}
+	if config.CellImage == "" && config.RevealURL != "" {
+		config.CellImage = AgentImage
+	}
 	if config.CellImage == "" {
-		config.CellImage = CellImage
+		config.CellImage = config.Profile.CellImage()
 	}
 	if config.BatchSize <= 0 {
 		config.BatchSize = DefaultBatchSize
@@ -99,29 +148,34 @@ func NewGridSpawner(c client.Client, config GridSpawnerConfig) *GridSpawner {
 		batchSize:     config.BatchSize,
 		retryAttempts: config.RetryAttempts,
 		retryDelay:    config.RetryDelay,
+		profile:       config.Profile,
+		revealURL:     config.RevealURL,
+		messages:      config.Messages,
 	}
 }
 
-// SpawnGrid creates all game pods for the given game state.
-// It creates pods in batches to avoid overwhelming the API server.
+// SpawnGrid creates all game pods for the given game state, recording the
+// UIDs of the cell pods in it. It creates pods in batches to avoid
+// overwhelming the API server.
 func (s *GridSpawner) SpawnGrid(ctx context.Context, state *game.GameState) (*SpawnResult, error) {
 	logger := log.FromContext(ctx)
 	start := time.Now()
 
 	result := &SpawnResult{
-		TotalPods: state.Size * state.Size,
+		TotalPods: state.TotalCells(),
 	}
 
 	// Generate all coordinates
 	coords := make([]game.Coordinate, 0, result.TotalPods)
-	for x := 0; x < state.Size; x++ {
-		for y := 0; y < state.Size; y++ {
+	width, height := state.Dimensions()
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
 			coords = append(coords, game.Coordinate{X: x, Y: y})
 		}
 	}
 
 	// Create pods in batches
-	gameID := fmt.Sprintf("%d-%d", state.Seed, state.StartedAt.Unix())
+	gameID := state.ID()
 
 	for i := 0; i < len(coords); i += s.batchSize {
 		end := i + s.batchSize
@@ -133,7 +187,7 @@ func (s *GridSpawner) SpawnGrid(ctx context.Context, state *game.GameState) (*Sp
 		logger.Info("spawning batch", "start", i, "end", end, "total", len(coords))
 
 		for _, coord := range batch {
-			if err := s.createPodWithRetry(ctx, coord, gameID); err != nil {
+			if err := s.createPodWithRetry(ctx, state, coord, gameID); err != nil {
 				logger.Error(err, "failed to create pod", "coord", coord)
 				result.FailedPods++
 				result.FailedCoords = append(result.FailedCoords, coord)
@@ -154,11 +208,45 @@ func (s *GridSpawner) SpawnGrid(ctx context.Context, state *game.GameState) (*Sp
 		return result, fmt.Errorf("failed to create %d pods", result.FailedPods)
 	}
 
+	if err := s.client.Create(ctx, s.BuildResignPod(gameID)); err != nil && !errors.IsAlreadyExists(err) {
+		return result, fmt.Errorf("failed to create resign pod: %w", err)
+	}
+
+	if state.Tutorial {
+		if err := s.suggestFirstStep(ctx, state); err != nil {
+			return result, err
+		}
+	}
+
 	return result, nil
 }
 
-// createPodWithRetry creates a single pod with retry logic.
-func (s *GridSpawner) createPodWithRetry(ctx context.Context, coord game.Coordinate, gameID string) error {
+// suggestFirstStep annotates the pod a tutorial player should delete first.
+func (s *GridSpawner) suggestFirstStep(ctx context.Context, state *game.GameState) error {
+	suggestion, ok := grid.SuggestMove(state)
+	if !ok {
+		return nil
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: s.namespace, Name: suggestion.Cell.PodName()}
+	if err := s.client.Get(ctx, key, pod); err != nil {
+		return fmt.Errorf("failed to get first tutorial pod: %w", err)
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[AnnotationNextStep] = "try: " + suggestion.Cell.DeleteCommand(s.namespace)
+	if err := s.client.Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("failed to annotate first tutorial pod: %w", err)
+	}
+	return nil
+}
+
+// createPodWithRetry creates a single pod with retry logic, and records its
+// UID in state.
+func (s *GridSpawner) createPodWithRetry(ctx context.Context, state *game.GameState, coord game.Coordinate, gameID string) error {
 	var lastErr error
 
 	for attempt := 0; attempt < s.retryAttempts; attempt++ {
@@ -170,24 +258,26 @@ func (s *GridSpawner) createPodWithRetry(ctx context.Context, coord game.Coordin
 			}
 		}
 
-		pod := s.buildCellPod(coord, gameID)
+		pod := s.BuildCellPod(coord, gameID)
 		if err := s.client.Create(ctx, pod); err != nil {
 			if errors.IsAlreadyExists(err) {
-				// Pod already exists, that's fine
+				// Pod already exists, that's fine, but its UID is unknown
+				state.SetPodUID(coord.X, coord.Y, "")
 				return nil
 			}
 			lastErr = err
 			continue
 		}
+		state.SetPodUID(coord.X, coord.Y, string(pod.UID))
 		return nil
 	}
 
 	return fmt.Errorf("after %d attempts: %w", s.retryAttempts, lastErr)
 }
 
-// buildCellPod creates the pod spec for a game cell.
-func (s *GridSpawner) buildCellPod(coord game.Coordinate, gameID string) *corev1.Pod {
-	return &corev1.Pod{
+// BuildCellPod creates the pod spec for a game cell.
+func (s *GridSpawner) BuildCellPod(coord game.Coordinate, gameID string) *corev1.Pod {
+	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      coord.PodName(),
 			Namespace: s.namespace,
@@ -198,19 +288,101 @@ func (s *GridSpawner) buildCellPod(coord game.Coordinate, gameID string) *corev1
 				LabelCoordY:    fmt.Sprintf("%d", coord.Y),
 				LabelGameID:    gameID,
 			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: RestrictedPodSecurityContext(),
 			Containers: []corev1.Container{
 				{
-					Name:  "cell",
-					Image: s.cellImage,
+					Name:            "cell",
+					Image:           s.cellImage,
+					SecurityContext: RestrictedSecurityContext(),
 					// The pod just sleeps - it's waiting to be deleted
-					Command: []string{"sh", "-c", "echo 'PodSweeper cell ready' && sleep infinity"},
+					Command: EchoCommand(s.message(messages.Cell, messages.Data{Cell: coord})),
 				},
 			},
 		},
 	}
+	if s.revealURL != "" {
+		// The cell agent waits to be revealed with its own coordinates
+		cell := &pod.Spec.Containers[0]
+		cell.Command = nil
+		cell.Args = []string{"cell"}
+		cell.Env = []corev1.EnvVar{
+			LabelEnv(EnvCoordX, LabelCoordX),
+			LabelEnv(EnvCoordY, LabelCoordY),
+			LabelEnv(EnvGameID, LabelGameID),
+			{Name: EnvRevealURL, Value: s.revealURL},
+		}
+	}
+	s.profile.gate(pod)
+	return pod
+}
+
+// LabelEnv returns the environment variable name, set to the label key of
+// the pod with the Downward API. Agents are configured from their pod
+// object this way, so the labels stay the single source of truth and
+// can't drift from what the agent was told.
+func LabelEnv(name, key string) corev1.EnvVar {
+	return fieldEnv(name, "metadata.labels['"+key+"']")
+}
+
+// AnnotationEnv returns the environment variable name, set to the
+// annotation key of the pod with the Downward API, see LabelEnv.
+func AnnotationEnv(name, key string) corev1.EnvVar {
+	return fieldEnv(name, "metadata.annotations['"+key+"']")
+}
+
+// fieldEnv returns the environment variable name, set to the field path of
+// the pod.
+func fieldEnv(name, path string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name:      name,
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: path}},
+	}
+}
+
+// BuildResignPod creates the pod spec of the resign pod: deleting it
+// concedes the game.
+func (s *GridSpawner) BuildResignPod(gameID string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ResignPodName,
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				LabelApp:       "podsweeper",
+				LabelComponent: "resign",
+				LabelGameID:    gameID,
+			},
+			Annotations: map[string]string{
+				AnnotationVersion: version.Version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: RestrictedPodSecurityContext(),
+			Containers: []corev1.Container{
+				{
+					Name:            "resign",
+					Image:           s.cellImage,
+					SecurityContext: RestrictedSecurityContext(),
+					Command:         EchoCommand(s.message(messages.Resign, messages.Data{})),
+				},
+			},
+		},
+	}
+	s.profile.gate(pod)
+	return pod
+}
+
+// message renders the message called name. Custom messages failing to
+// render fall back to the built-in ones.
+func (s *GridSpawner) message(name string, data messages.Data) string {
+	message, _ := s.messages.Render(name, data)
+	return message
 }
 
 // CleanupGrid removes all game pods from the namespace.
@@ -248,7 +420,8 @@ func (s *GridSpawner) CleanupGrid(ctx context.Context) error {
 	return lastErr
 }
 
-// WaitForPodsReady waits for all game pods to be in Running phase.
+// WaitForPodsReady waits for all game pods to be in Running phase, or
+// gated in the tiny profile.
 func (s *GridSpawner) WaitForPodsReady(ctx context.Context, expectedCount int, timeout time.Duration) error {
 	logger := log.FromContext(ctx)
 
@@ -266,7 +439,7 @@ func (s *GridSpawner) WaitForPodsReady(ctx context.Context, expectedCount int, t
 
 		runningCount := 0
 		for _, pod := range podList.Items {
-			if pod.Status.Phase == corev1.PodRunning {
+			if pod.Status.Phase == corev1.PodRunning || len(pod.Spec.SchedulingGates) > 0 {
 				runningCount++
 			}
 		}
@@ -277,6 +450,11 @@ func (s *GridSpawner) WaitForPodsReady(ctx context.Context, expectedCount int, t
 	})
 }
 
+// Profile returns the profile of the spawned pods.
+func (s *GridSpawner) Profile() Profile {
+	return s.profile
+}
+
 // Namespace returns the namespace where pods are spawned.
 func (s *GridSpawner) Namespace() string {
 	return s.namespace
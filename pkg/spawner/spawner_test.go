@@ -2,6 +2,7 @@ package spawner
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,9 +10,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/version"
 )
 
 const testNamespace = "podsweeper-game"
@@ -81,8 +85,15 @@ func TestGridSpawner_SpawnGrid(t *testing.T) {
 	ctx := context.Background()
 	scheme := newTestScheme()
 
+	// The API server sets the UIDs of the pods created
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				obj.SetUID(types.UID("uid-" + obj.GetName()))
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
 		Build()
 
 	spawner := NewGridSpawner(fakeClient, GridSpawnerConfig{
@@ -131,8 +142,18 @@ func TestGridSpawner_SpawnGrid(t *testing.T) {
 			if pod.Labels[LabelComponent] != "cell" {
 				t.Errorf("Pod %s component label = %q, want 'cell'", podName, pod.Labels[LabelComponent])
 			}
+			if uid := state.PodUID(x, y); uid == "" || uid != string(pod.UID) {
+				t.Errorf("Pod %s UID %q not recorded, got %q", podName, pod.UID, uid)
+			}
 		}
 	}
+
+	var resign corev1.Pod
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: ResignPodName, Namespace: testNamespace}, &resign); err != nil {
+		t.Errorf("Resign pod was not created: %v", err)
+	} else if resign.Labels[LabelGameID] != state.ID() {
+		t.Errorf("Resign pod game ID = %q, want %q", resign.Labels[LabelGameID], state.ID())
+	}
 }
 
 func TestGridSpawner_SpawnGridLarge(t *testing.T) {
@@ -167,6 +188,30 @@ func TestGridSpawner_SpawnGridLarge(t *testing.T) {
 	}
 }
 
+func TestGridSpawner_SpawnGridRectangular(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	spawner := NewGridSpawner(fakeClient, GridSpawnerConfig{Namespace: testNamespace})
+
+	// A wide board: 5 columns, 2 rows
+	state := game.NewRectGameState(5, 2, 12345)
+	result, err := spawner.SpawnGrid(ctx, state)
+	if err != nil {
+		t.Fatalf("SpawnGrid returned error: %v", err)
+	}
+	if result.TotalPods != 10 || result.CreatedPods != 10 {
+		t.Errorf("expected 10 pods for a 5x2 board, got %d spawned out of %d", result.CreatedPods, result.TotalPods)
+	}
+
+	pod := &corev1.Pod{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-4-1"}, pod); err != nil {
+		t.Errorf("expected the pod of the last column, got %v", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pod-1-4"}, pod); err == nil {
+		t.Error("expected no pod outside the board")
+	}
+}
+
 func TestGridSpawner_BuildCellPod(t *testing.T) {
 	scheme := newTestScheme()
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -179,7 +224,7 @@ func TestGridSpawner_BuildCellPod(t *testing.T) {
 	coord := game.Coordinate{X: 5, Y: 7}
 	gameID := "12345-1234567890"
 
-	pod := spawner.buildCellPod(coord, gameID)
+	pod := spawner.BuildCellPod(coord, gameID)
 
 	// Check name
 	if pod.Name != "pod-5-7" {
@@ -205,6 +250,11 @@ func TestGridSpawner_BuildCellPod(t *testing.T) {
 		}
 	}
 
+	// Check version annotation
+	if pod.Annotations[AnnotationVersion] != version.Version {
+		t.Errorf("pod.Annotations[%q] = %q, want %q", AnnotationVersion, pod.Annotations[AnnotationVersion], version.Version)
+	}
+
 	// Check container
 	if len(pod.Spec.Containers) != 1 {
 		t.Fatalf("expected 1 container, got %d", len(pod.Spec.Containers))
@@ -224,6 +274,43 @@ func TestGridSpawner_BuildCellPod(t *testing.T) {
 	}
 }
 
+func TestGridSpawner_BuildCellPodExecReveal(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	spawner := NewGridSpawner(fakeClient, GridSpawnerConfig{
+		Namespace: testNamespace,
+		RevealURL: "http://gamemaster:8080",
+	})
+
+	pod := spawner.BuildCellPod(game.Coordinate{X: 5, Y: 7}, "game")
+	container := pod.Spec.Containers[0]
+	if container.Image != AgentImage || container.Command != nil || len(container.Args) != 1 || container.Args[0] != "cell" {
+		t.Errorf("expected the cell agent, got %s %v %v", container.Image, container.Command, container.Args)
+	}
+	env := downwardEnv(pod)
+	if env[EnvCoordX] != "5" || env[EnvCoordY] != "7" || env[EnvGameID] != "game" || env[EnvRevealURL] != "http://gamemaster:8080" {
+		t.Errorf("expected the cell agent to know its coordinates, game and the Gamemaster, got %v", env)
+	}
+}
+
+// downwardEnv returns the environment of the first container of pod, as
+// the kubelet resolves it with the Downward API.
+func downwardEnv(pod *corev1.Pod) map[string]string {
+	env := map[string]string{}
+	for _, e := range pod.Spec.Containers[0].Env {
+		if e.ValueFrom == nil || e.ValueFrom.FieldRef == nil {
+			env[e.Name] = e.Value
+			continue
+		}
+		path := e.ValueFrom.FieldRef.FieldPath
+		if key, ok := strings.CutPrefix(path, "metadata.labels['"); ok {
+			env[e.Name] = pod.Labels[strings.TrimSuffix(key, "']")]
+		} else if key, ok := strings.CutPrefix(path, "metadata.annotations['"); ok {
+			env[e.Name] = pod.Annotations[strings.TrimSuffix(key, "']")]
+		}
+	}
+	return env
+}
+
 func TestGridSpawner_CleanupGrid(t *testing.T) {
 	ctx := context.Background()
 	scheme := newTestScheme()
@@ -384,3 +471,34 @@ func TestSpawnResult(t *testing.T) {
 		t.Errorf("len(FailedCoords) = %d, want 2", len(result.FailedCoords))
 	}
 }
+
+func TestGridSpawner_SpawnGridTutorial(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	spawner := NewGridSpawner(fakeClient, GridSpawnerConfig{Namespace: testNamespace})
+
+	state := game.NewGameState(4, 12345)
+	state.SetMine(3, 3)
+	state.Tutorial = true
+
+	if _, err := spawner.SpawnGrid(ctx, state); err != nil {
+		t.Fatalf("SpawnGrid returned error: %v", err)
+	}
+
+	var pods corev1.PodList
+	if err := fakeClient.List(ctx, &pods); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	var suggested []string
+	for _, pod := range pods.Items {
+		if next, ok := pod.Annotations[AnnotationNextStep]; ok {
+			suggested = append(suggested, pod.Name)
+			if next != "try: kubectl delete pod "+pod.Name+" -n "+testNamespace {
+				t.Errorf("unexpected next step %q", next)
+			}
+		}
+	}
+	if len(suggested) != 1 || suggested[0] == "pod-3-3" {
+		t.Errorf("expected a single safe suggested pod, got %v", suggested)
+	}
+}
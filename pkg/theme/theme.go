@@ -0,0 +1,159 @@
+// Package theme styles boards, hints and end-game messages for players
+// who need more contrast, can't tell red from green, or prefer emoji.
+// The theme is set per game and honored by every output: the boards drawn
+// by package render, hint pods and the explosion and victory pods.
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Theme selects how the game is rendered.
+type Theme string
+
+const (
+	// Classic is the default theme: ASCII boards, the usual minesweeper
+	// colors and emoji in end-game messages.
+	Classic Theme = "classic"
+
+	// HighContrast uses bold, bright colors on black and plain text
+	// instead of emoji, which screen readers spell out.
+	HighContrast Theme = "high-contrast"
+
+	// Colorblind uses the Okabe-Ito palette, which stays distinct for
+	// every kind of color blindness, and never relies on red versus green.
+	Colorblind Theme = "colorblind"
+
+	// Emoji draws boards and hints with emoji.
+	Emoji Theme = "emoji"
+)
+
+// Themes lists the available themes.
+var Themes = []Theme{Classic, HighContrast, Colorblind, Emoji}
+
+// Parse returns the theme named s. Empty is Classic.
+func Parse(s string) (Theme, error) {
+	if s == "" {
+		return Classic, nil
+	}
+	for _, t := range Themes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = string(t)
+	}
+	return "", fmt.Errorf("unknown theme %q, use one of %s", s, strings.Join(names, ", "))
+}
+
+// Of returns the theme named s, falling back to Classic for unknown names
+// so that a game is always rendered.
+func Of(s string) Theme {
+	t, err := Parse(s)
+	if err != nil {
+		return Classic
+	}
+	return t
+}
+
+// Palette holds the CSS colors of web pages.
+type Palette struct {
+	Background string
+	Foreground string
+	Panel      string
+	Border     string
+	Won        string
+	Lost       string
+}
+
+// Palette returns the web colors of the theme.
+func (t Theme) Palette() Palette {
+	switch t {
+	case HighContrast:
+		return Palette{Background: "#000000", Foreground: "#ffffff", Panel: "#000000", Border: "#ffffff",
+			Won: "#ffff00", Lost: "#ff00ff"}
+	case Colorblind:
+		return Palette{Background: "#1e1e2e", Foreground: "#ffffff", Panel: "#313244", Border: "#313244",
+			Won: "#0072b2", Lost: "#e69f00"}
+	default:
+		return Palette{Background: "#1e1e2e", Foreground: "#cdd6f4", Panel: "#313244", Border: "#313244",
+			Won: "#a6e3a1", Lost: "#f38ba8"}
+	}
+}
+
+// emojiCells maps board characters to emoji.
+var emojiCells = map[byte]string{
+	'.': "🟦", '*': "💣", 'f': "🚩", 'F': "🚩", 'q': "❓", 'Q': "❓", 'X': "💥", 'D': "🛡️",
+	'0': "⬜", '1': "1️⃣", '2': "2️⃣", '3': "3️⃣", '4': "4️⃣",
+	'5': "5️⃣", '6': "6️⃣", '7': "7️⃣", '8': "8️⃣",
+	'H': "🔥", 'W': "🌡️", 'C': "🧊",
+}
+
+// emojiTemperatures maps the hints of hot/cold games to emoji.
+var emojiTemperatures = map[string]string{"hot": "🔥", "warm": "🌡️", "cold": "🧊"}
+
+// ansiColors are the SGR parameters of board characters per theme. Hidden
+// cells are left uncolored.
+var ansiColors = map[Theme]map[byte]string{
+	Classic: {
+		'1': "34", '2': "32", '3': "31", '4': "35", '5': "33", '6': "36", '7': "37", '8': "90",
+		'X': "1;31", '*': "1;31", 'f': "31", 'F': "31", 'D': "33",
+		'H': "91", 'W': "33", 'C': "36",
+	},
+	HighContrast: {
+		'1': "1;97", '2': "1;97", '3': "1;97", '4': "1;97", '5': "1;97", '6': "1;97", '7': "1;97", '8': "1;97",
+		'X': "1;7", '*': "1;7", 'f': "1;93", 'F': "1;93", 'q': "1;93", 'Q': "1;93", 'D': "1;96",
+		'H': "1;97", 'W': "1;97", 'C': "1;97",
+	},
+	Colorblind: {
+		'1': "38;5;25", '2': "38;5;214", '3': "38;5;166", '4': "38;5;175",
+		'5': "38;5;36", '6': "38;5;74", '7': "38;5;227", '8': "38;5;244",
+		'X': "1;38;5;166", '*': "1;38;5;166", 'f': "38;5;214", 'F': "38;5;214", 'D': "38;5;74",
+		'H': "38;5;166", 'W': "38;5;214", 'C': "38;5;25",
+	},
+}
+
+// Cell returns the emoji drawing a board character, as encoded by
+// GameState.ToBoardString, in the Emoji theme. It returns false for the
+// other themes, which keep the board characters.
+func (t Theme) Cell(c byte) (string, bool) {
+	if t != Emoji {
+		return "", false
+	}
+	e, ok := emojiCells[c]
+	return e, ok
+}
+
+// Color returns the SGR parameters coloring a board character in
+// terminals. It returns false for hidden cells, and for the Emoji theme,
+// which is never colored.
+func (t Theme) Color(c byte) (string, bool) {
+	code, ok := ansiColors[t][c]
+	return code, ok
+}
+
+// Hint renders the value shown by a hint pod. Only the Emoji theme changes
+// it, so that scripts parsing hints keep working with the other themes.
+func (t Theme) Hint(value string) string {
+	if t == Emoji && len(value) == 1 {
+		if e, ok := emojiCells[value[0]]; ok {
+			return e
+		}
+	}
+	if e, ok := emojiTemperatures[value]; ok && t == Emoji {
+		return e
+	}
+	return value
+}
+
+// Banner renders the headline of an end-game pod, surrounded by emoji, or
+// by asterisks in the HighContrast theme.
+func (t Theme) Banner(text, emoji string) string {
+	if t == HighContrast {
+		return "*** " + text + " ***"
+	}
+	return emoji + " " + text + " " + emoji
+}
@@ -0,0 +1,105 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Theme
+		wantErr bool
+	}{
+		{"", Classic, false},
+		{"classic", Classic, false},
+		{"high-contrast", HighContrast, false},
+		{"colorblind", Colorblind, false},
+		{"emoji", Emoji, false},
+		{"neon", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if Of("neon") != Classic {
+		t.Error("expected unknown themes to fall back to classic")
+	}
+}
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		theme Theme
+		c     byte
+		want  string
+		ok    bool
+	}{
+		{Emoji, '1', "1️⃣", true},
+		{Emoji, 'H', "🔥", true},
+		{Emoji, '?', "", false},
+		{Classic, '1', "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.theme.Cell(tt.c); got != tt.want || ok != tt.ok {
+			t.Errorf("%s.Cell(%q) = %q, %v, want %q, %v", tt.theme, tt.c, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	if code, ok := Classic.Color('1'); !ok || code != "34" {
+		t.Errorf("Classic.Color(1) = %q, %v", code, ok)
+	}
+	if _, ok := Classic.Color('.'); ok {
+		t.Error("expected hidden cells to be left uncolored")
+	}
+	if _, ok := Emoji.Color('1'); ok {
+		t.Error("expected the Emoji theme to be left uncolored")
+	}
+}
+
+func TestColorblindAvoidsRedGreen(t *testing.T) {
+	colors := ansiColors[Colorblind]
+	for _, c := range []byte("12345678Xf") {
+		if code := colors[c]; strings.HasSuffix(code, "31") || strings.HasSuffix(code, "32") {
+			t.Errorf("cell %q uses red or green (%s)", c, code)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	if got := Emoji.Hint("3"); got != "3️⃣" {
+		t.Errorf("Emoji.Hint(3) = %q", got)
+	}
+	for _, th := range []Theme{Classic, HighContrast, Colorblind} {
+		if got := th.Hint("3"); got != "3" {
+			t.Errorf("%s.Hint(3) = %q, want 3", th, got)
+		}
+	}
+	if got := Emoji.Hint("cold"); got != "🧊" {
+		t.Errorf("Emoji.Hint(cold) = %q", got)
+	}
+	if got := Classic.Hint("cold"); got != "cold" {
+		t.Errorf("expected temperatures to be kept out of the Emoji theme, got %q", got)
+	}
+	if got := Emoji.Hint("?"); got != "?" {
+		t.Errorf("expected unknown hints to be kept, got %q", got)
+	}
+}
+
+func TestBanner(t *testing.T) {
+	if got := HighContrast.Banner("BOOM!", "💥"); got != "*** BOOM! ***" {
+		t.Errorf("HighContrast.Banner() = %q", got)
+	}
+	if got := Classic.Banner("BOOM!", "💥"); got != "💥 BOOM! 💥" {
+		t.Errorf("Classic.Banner() = %q", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package vcluster provisions a vcluster per game, so that each player gets
+// a whole virtual cluster to play in, as cluster-admin, without any access
+// to the host cluster beyond their game namespace.
+//
+// The vcluster is installed in the game namespace by a Helm Job. Its
+// kubeconfig is written by vcluster to the Secret named by
+// KubeconfigSecret, from which the Gamemaster plays the board inside the
+// vcluster while keeping the game state in the host namespace.
+package vcluster
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+const (
+	// DefaultName names the vcluster of a game, and its Service.
+	DefaultName = "podsweeper"
+
+	// DefaultChartVersion is the version of the vcluster Helm chart.
+	DefaultChartVersion = "0.24.0"
+
+	// ChartRepository hosts the vcluster Helm chart.
+	ChartRepository = "https://charts.loft.sh"
+
+	// DefaultHelmImage runs the installation Job.
+	DefaultHelmImage = "alpine/helm:3.17.3"
+
+	// InstallerName names the installation Job, its ServiceAccount and
+	// RoleBinding.
+	InstallerName = "podsweeper-vcluster-installer"
+
+	// PlayerRole is the Role letting players connect to the vcluster.
+	PlayerRole = "podsweeper-vcluster-player"
+
+	// BoardNamespace is the namespace of the board inside the vcluster.
+	BoardNamespace = "podsweeper-game"
+)
+
+// Config describes the vcluster of a game.
+type Config struct {
+	// Name of the vcluster. Defaults to DefaultName.
+	Name string
+
+	// ChartVersion defaults to DefaultChartVersion.
+	ChartVersion string
+
+	// HelmImage defaults to DefaultHelmImage.
+	HelmImage string
+}
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = DefaultName
+	}
+	if c.ChartVersion == "" {
+		c.ChartVersion = DefaultChartVersion
+	}
+	if c.HelmImage == "" {
+		c.HelmImage = DefaultHelmImage
+	}
+	return c
+}
+
+// KubeconfigSecret names the Secret vcluster writes its kubeconfig to.
+func KubeconfigSecret(name string) string {
+	return "vc-" + name
+}
+
+// Server returns the URL of the API server of the vcluster name inside the
+// host cluster. The kubeconfig of vcluster targets a local port-forward
+// instead.
+func Server(name, namespace string) string {
+	return fmt.Sprintf("https://%s.%s.svc:443", name, namespace)
+}
+
+// InstallObjects returns the Job installing the vcluster in namespace,
+// with a ServiceAccount administering the namespace. Installing again
+// upgrades the vcluster in place.
+func InstallObjects(namespace string, cfg Config, labels map[string]string) []client.Object {
+	cfg = cfg.withDefaults()
+	meta := metav1.ObjectMeta{Name: InstallerName, Namespace: namespace, Labels: labels}
+	return []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.RoleBinding{
+			ObjectMeta: meta,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: InstallerName, Namespace: namespace}},
+		},
+		&batchv1.Job{
+			ObjectMeta: meta,
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To(int32(3)),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: InstallerName,
+						RestartPolicy:      corev1.RestartPolicyNever,
+						SecurityContext:    spawner.RestrictedPodSecurityContext(),
+						Containers: []corev1.Container{{
+							Name:  "helm",
+							Image: cfg.HelmImage,
+							Args: []string{
+								"upgrade", "--install", cfg.Name, "vcluster",
+								"--repo", ChartRepository,
+								"--version", cfg.ChartVersion,
+								"--namespace", namespace,
+							},
+							// Helm caches the chart under HOME
+							Env:             []corev1.EnvVar{{Name: "HOME", Value: "/tmp"}},
+							VolumeMounts:    []corev1.VolumeMount{{Name: "tmp", MountPath: "/tmp"}},
+							SecurityContext: spawner.RestrictedSecurityContext(),
+						}},
+						Volumes: []corev1.Volume{{
+							Name:         "tmp",
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// PlayerObjects returns the Role and RoleBinding letting the player
+// ServiceAccount read the kubeconfig of the vcluster and port-forward to
+// it, to play as its cluster-admin:
+//
+//	kubectl port-forward svc/podsweeper 8443:443
+func PlayerObjects(namespace string, cfg Config, labels map[string]string) []client.Object {
+	cfg = cfg.withDefaults()
+	meta := metav1.ObjectMeta{Name: PlayerRole, Namespace: namespace, Labels: labels}
+	return []client.Object{
+		&rbacv1.Role{ObjectMeta: meta, Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{KubeconfigSecret(cfg.Name)},
+				Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"services"}, ResourceNames: []string{cfg.Name}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"pods/portforward"}, Verbs: []string{"create"}},
+		}},
+		rbac.RoleBinding(meta, PlayerRole, rbac.PlayerServiceAccount, namespace),
+	}
+}
+
+// GamemasterArgs returns the flags of a Gamemaster playing the board of
+// its game in the vcluster of its namespace.
+func GamemasterArgs(namespace string, cfg Config) []string {
+	cfg = cfg.withDefaults()
+	return []string{
+		"--game-kubeconfig-secret=" + KubeconfigSecret(cfg.Name),
+		"--game-kubeconfig-server=" + Server(cfg.Name, namespace),
+		"--board-namespace=" + BoardNamespace,
+	}
+}
@@ -0,0 +1,67 @@
+package vcluster
+
+import (
+	"slices"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/zwindler/podsweeper/pkg/spawner"
+)
+
+func TestInstallObjects(t *testing.T) {
+	objects := InstallObjects("ws-1", Config{}, nil)
+	var job *batchv1.Job
+	var binding *rbacv1.RoleBinding
+	for _, obj := range objects {
+		if obj.GetNamespace() != "ws-1" {
+			t.Errorf("expected %s in the game namespace, got %q", obj.GetName(), obj.GetNamespace())
+		}
+		switch o := obj.(type) {
+		case *batchv1.Job:
+			job = o
+		case *rbacv1.RoleBinding:
+			binding = o
+		}
+	}
+	if job == nil || binding == nil {
+		t.Fatalf("expected an installation Job and RoleBinding, got %v", objects)
+	}
+	if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != "admin" {
+		t.Errorf("expected the installer to administer the namespace only, got %+v", binding.RoleRef)
+	}
+	args := job.Spec.Template.Spec.Containers[0].Args
+	for _, want := range []string{DefaultName, "--version", DefaultChartVersion, "--namespace", "ws-1"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("expected %q in the helm args, got %v", want, args)
+		}
+	}
+	if violations := spawner.RestrictedViolations(&job.Spec.Template.Spec); len(violations) > 0 {
+		t.Errorf("expected the installer to run restricted, got %v", violations)
+	}
+}
+
+func TestPlayerObjects(t *testing.T) {
+	objects := PlayerObjects("ws-1", Config{Name: "vc"}, nil)
+	role, ok := objects[0].(*rbacv1.Role)
+	if !ok {
+		t.Fatalf("expected a Role, got %T", objects[0])
+	}
+	secrets := role.Rules[0]
+	if !slices.Equal(secrets.ResourceNames, []string{"vc-vc"}) || !slices.Equal(secrets.Verbs, []string{"get"}) {
+		t.Errorf("expected players to read the vcluster kubeconfig only, got %+v", secrets)
+	}
+}
+
+func TestGamemasterArgs(t *testing.T) {
+	args := GamemasterArgs("ws-1", Config{})
+	want := []string{
+		"--game-kubeconfig-secret=vc-podsweeper",
+		"--game-kubeconfig-server=https://podsweeper.ws-1.svc:443",
+		"--board-namespace=" + BoardNamespace,
+	}
+	if !slices.Equal(args, want) {
+		t.Errorf("expected %v, got %v", want, args)
+	}
+}
@@ -0,0 +1,58 @@
+// Package version exposes build information embedded at link time.
+//
+// Values are injected with ldflags, e.g.:
+//
+//	go build -ldflags "-X github.com/zwindler/podsweeper/pkg/version.Version=v1.0.0"
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// These variables are set at build time via -ldflags.
+var (
+	// Version is the semantic version or git describe output.
+	Version = "dev"
+
+	// Commit is the git commit the binary was built from.
+	Commit = "unknown"
+
+	// BuildDate is the RFC3339 build timestamp.
+	BuildDate = "unknown"
+)
+
+// Info holds the build information of a binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+}
+
+// String returns a one-line human readable representation of the build info.
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s, %s)", i.Version, i.Commit, i.BuildDate, i.GoVersion, i.Platform)
+}
+
+// Handler returns an HTTP handler serving the build information as JSON.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Get())
+	})
+}
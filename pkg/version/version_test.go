@@ -0,0 +1,53 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+
+	if info.Version != Version {
+		t.Errorf("expected version %q, got %q", Version, info.Version)
+	}
+	if info.Commit != Commit {
+		t.Errorf("expected commit %q, got %q", Commit, info.Commit)
+	}
+	if info.GoVersion == "" {
+		t.Error("expected GoVersion to be set")
+	}
+	if info.Platform == "" {
+		t.Error("expected Platform to be set")
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	info := Info{Version: "v1.2.3", Commit: "abc123", BuildDate: "2024-01-01T00:00:00Z", GoVersion: "go1.25", Platform: "linux/amd64"}
+
+	s := info.String()
+	for _, want := range []string{"v1.2.3", "abc123", "2024-01-01T00:00:00Z", "linux/amd64"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected %q in %q", want, s)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/version", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var info Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if info.Version != Version {
+		t.Errorf("expected version %q, got %q", Version, info.Version)
+	}
+}
@@ -0,0 +1,204 @@
+package workshop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/render"
+	"github.com/zwindler/podsweeper/pkg/results"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+// DefaultDashboardRefresh is how often the dashboard page reloads.
+const DefaultDashboardRefresh = 5 * time.Second
+
+// Attendee is the game of one workshop participant, as shown to instructors.
+type Attendee struct {
+	Namespace string         `json:"namespace"`
+	Workshop  string         `json:"workshop"`
+	Stats     game.GameStats `json:"stats,omitzero"`
+
+	// Board is the board as the player sees it, mines hidden, in the
+	// theme of the game.
+	Board string `json:"board,omitempty"`
+
+	// Theme is the accessibility theme of the game.
+	Theme theme.Theme `json:"theme,omitempty"`
+
+	// Error explains why the game could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// HasGame reports whether a game was found in the namespace.
+func (a Attendee) HasGame() bool {
+	return a.Board != ""
+}
+
+// Dashboard aggregates the games of every workshop namespace so that
+// instructors can follow the whole room at a glance.
+type Dashboard struct {
+	client   client.Client
+	workshop string
+	refresh  time.Duration
+}
+
+// NewDashboard creates a Dashboard for a workshop. An empty workshop name
+// shows the games of every workshop.
+func NewDashboard(c client.Client, workshop string) *Dashboard {
+	return &Dashboard{client: c, workshop: workshop, refresh: DefaultDashboardRefresh}
+}
+
+// Collect reads the game of every workshop namespace, sorted by namespace.
+// Games that can't be read are reported with an error instead of failing.
+func (d *Dashboard) Collect(ctx context.Context) ([]Attendee, error) {
+	namespaces, err := d.namespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attendees := make([]Attendee, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		attendee := Attendee{Namespace: ns.Name, Workshop: ns.Labels[LabelWorkshop]}
+		state, err := game.NewSecretStore(d.client, game.WithNamespace(ns.Name)).Load(ctx)
+		switch {
+		case err != nil:
+			attendee.Error = err.Error()
+		case state != nil:
+			attendee.Stats = state.Stats()
+			attendee.Theme = theme.Of(state.Theme)
+			attendee.Board = render.Renderer{Theme: attendee.Theme, View: render.ViewPlayer}.Render(state)
+		}
+		attendees = append(attendees, attendee)
+	}
+
+	sort.Slice(attendees, func(i, j int) bool { return attendees[i].Namespace < attendees[j].Namespace })
+	return attendees, nil
+}
+
+// Finished returns the finished games of the workshop, for results export.
+// Games that can't be read, and the games of bots, are skipped.
+func (d *Dashboard) Finished(ctx context.Context) ([]results.Game, error) {
+	namespaces, err := d.namespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []results.Game
+	for _, ns := range namespaces.Items {
+		if _, ok := ns.Labels[LabelRace]; ok {
+			continue
+		}
+		state, err := game.NewSecretStore(d.client, game.WithNamespace(ns.Name)).Load(ctx)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "skipping unreadable game", "namespace", ns.Name)
+			continue
+		}
+		if state != nil && state.Status != game.StatusPlaying {
+			games = append(games, results.Game{Namespace: ns.Name, State: state})
+		}
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Namespace < games[j].Namespace })
+	return games, nil
+}
+
+// namespaces lists the namespaces of the workshop.
+func (d *Dashboard) namespaces(ctx context.Context) (*corev1.NamespaceList, error) {
+	var selector client.ListOption = client.HasLabels{LabelWorkshop}
+	if d.workshop != "" {
+		selector = client.MatchingLabels{LabelWorkshop: d.workshop}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := d.client.List(ctx, namespaces, selector); err != nil {
+		return nil, fmt.Errorf("failed to list workshop namespaces: %w", err)
+	}
+	return namespaces, nil
+}
+
+// Handler serves the dashboard page on /, the attendees as JSON on
+// /api/games and the results of finished games on /api/export.
+func (d *Dashboard) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/api/export", results.Handler(d))
+	mux.HandleFunc("/api/games", func(w http.ResponseWriter, r *http.Request) {
+		attendees, err := d.collect(w, r)
+		if err != nil {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(attendees)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		attendees, err := d.collect(w, r)
+		if err != nil {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = dashboardTemplate.Execute(w, struct {
+			Workshop  string
+			Refresh   int
+			Attendees []Attendee
+		}{d.workshop, int(d.refresh.Seconds()), attendees})
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "failed to render dashboard")
+		}
+	})
+	return mux
+}
+
+// collect collects attendees, answering with an error if it fails.
+func (d *Dashboard) collect(w http.ResponseWriter, r *http.Request) ([]Attendee, error) {
+	attendees, err := d.Collect(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return attendees, err
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"duration": func(s game.GameStats) string { return s.Elapsed().Round(time.Second).String() },
+	"palette":  func(t theme.Theme) theme.Palette { return theme.Of(string(t)).Palette() },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>PodSweeper{{with .Workshop}} - {{.}}{{end}}</title>
+<style>
+body { font-family: sans-serif; background: #1e1e2e; color: #cdd6f4; }
+.games { display: flex; flex-wrap: wrap; gap: 1em; }
+.game { background: #313244; padding: 1em; border-radius: 8px; }
+.won { border: 2px solid #a6e3a1; }
+.lost { border: 2px solid #f38ba8; }
+pre { font-size: 14px; line-height: 1; }
+</style>
+</head>
+<body>
+<h1>PodSweeper{{with .Workshop}} - {{.}}{{end}}</h1>
+<div class="games">
+{{range .Attendees}}{{$p := palette .Theme}}<div class="game {{.Stats.Status}}" style="background: {{$p.Panel}}; color: {{$p.Foreground}};{{if eq .Stats.Status "won"}} border-color: {{$p.Won}};{{else if eq .Stats.Status "lost"}} border-color: {{$p.Lost}};{{end}}">
+<h2>{{.Namespace}}</h2>
+{{if .Error}}<p>⚠️ {{.Error}}</p>
+{{else if .HasGame}}<p>{{.Stats.Status}} · {{.Stats.Progress}}% · {{duration .Stats}} · {{.Stats.Clicks}} clicks</p>
+<pre>{{.Board}}</pre>
+{{else}}<p>No game yet</p>
+{{end}}</div>
+{{else}}<p>No workshop namespace found.</p>
+{{end}}</div>
+</body>
+</html>
+`))
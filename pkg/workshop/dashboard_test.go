@@ -0,0 +1,148 @@
+package workshop
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/theme"
+)
+
+func newDashboardTestClient(t *testing.T) client.Client {
+	t.Helper()
+	ns := func(name, workshop string) *corev1.Namespace {
+		n := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if workshop != "" {
+			n.Labels = map[string]string{LabelWorkshop: workshop}
+		}
+		return n
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(
+		ns("ws-2", "ws"), ns("ws-1", "ws"), ns("other-1", "other"), ns("default", ""),
+	).Build()
+
+	state := game.NewGameState(3, 1)
+	state.SetMine(2, 2)
+	state.Reveal(0, 0)
+	if err := game.NewSecretStore(c, game.WithNamespace("ws-1")).Save(context.Background(), state); err != nil {
+		t.Fatalf("failed to save game: %v", err)
+	}
+	return c
+}
+
+func TestDashboardCollect(t *testing.T) {
+	ctx := context.Background()
+	c := newDashboardTestClient(t)
+
+	attendees, err := NewDashboard(c, "ws").Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(attendees) != 2 || attendees[0].Namespace != "ws-1" || attendees[1].Namespace != "ws-2" {
+		t.Fatalf("unexpected attendees %+v", attendees)
+	}
+	if !attendees[0].HasGame() || attendees[0].Stats.RevealedCells != 1 {
+		t.Errorf("expected ws-1 game stats, got %+v", attendees[0])
+	}
+	if strings.ContainsRune(attendees[0].Board, game.BoardHiddenMine) {
+		t.Errorf("expected mines to be hidden, got %q", attendees[0].Board)
+	}
+	if attendees[0].Theme != theme.Classic {
+		t.Errorf("expected the classic theme by default, got %q", attendees[0].Theme)
+	}
+	if attendees[1].HasGame() {
+		t.Error("expected no game in ws-2")
+	}
+
+	all, err := NewDashboard(c, "").Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected every workshop namespace, got %d", len(all))
+	}
+}
+
+func TestDashboardHandler(t *testing.T) {
+	handler := NewDashboard(newDashboardTestClient(t), "ws").Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/games", nil))
+	var attendees []Attendee
+	if err := json.Unmarshal(rec.Body.Bytes(), &attendees); err != nil || len(attendees) != 2 {
+		t.Fatalf("unexpected JSON response %q: %v", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "ws-1") || !strings.Contains(body, "No game yet") {
+		t.Errorf("unexpected dashboard page (%d): %s", rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestDashboardFinished(t *testing.T) {
+	ctx := context.Background()
+	c := newDashboardTestClient(t)
+	d := NewDashboard(c, "ws")
+
+	games, err := d.Finished(ctx)
+	if err != nil {
+		t.Fatalf("Finished failed: %v", err)
+	}
+	if len(games) != 0 {
+		t.Errorf("expected games in progress to be left out, got %d", len(games))
+	}
+
+	state := game.NewGameState(3, 2)
+	state.SetMine(0, 0)
+	state.RevealBy(0, 0, "bob")
+	state.SetLost()
+	if err := game.NewSecretStore(c, game.WithNamespace("ws-2")).Save(ctx, state); err != nil {
+		t.Fatalf("failed to save game: %v", err)
+	}
+
+	games, err = d.Finished(ctx)
+	if err != nil {
+		t.Fatalf("Finished failed: %v", err)
+	}
+	if len(games) != 1 || games[0].Namespace != "ws-2" {
+		t.Fatalf("expected the lost game of ws-2, got %+v", games)
+	}
+
+	// Bots racing participants aren't ranked with them
+	bot := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "ws-2" + BotSuffix,
+		Labels: map[string]string{LabelWorkshop: "ws", LabelRace: "ws-2"},
+	}}
+	if err := c.Create(ctx, bot); err != nil {
+		t.Fatalf("failed to create bot namespace: %v", err)
+	}
+	if err := game.NewSecretStore(c, game.WithNamespace(bot.Name)).Save(ctx, state); err != nil {
+		t.Fatalf("failed to save game: %v", err)
+	}
+	if games, _ := d.Finished(ctx); len(games) != 1 {
+		t.Errorf("expected the game of the bot to be left out, got %+v", games)
+	}
+
+	rec := httptest.NewRecorder()
+	d.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/export?data=moves", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "ws-2,1,0,0,bob") {
+		t.Errorf("unexpected export (%d): %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,44 @@
+package workshop
+
+import (
+	"context"
+	"time"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/results"
+)
+
+const (
+	// HistoryConfigMap is the ConfigMap of a game namespace keeping the
+	// results of the games already replaced by Next.
+	HistoryConfigMap = results.DefaultArchiveConfigMap
+
+	// HistoryKey is the key holding the results JSON in the ConfigMap.
+	HistoryKey = results.ArchiveKey
+)
+
+// History returns the results of the past games of a namespace, oldest
+// first.
+func (p *Provisioner) History(ctx context.Context, ns string) ([]results.Result, error) {
+	return results.Archive{Client: p.client, Namespace: ns}.Results(ctx)
+}
+
+// recordResult archives a finished game in the history of its namespace.
+func (p *Provisioner) recordResult(ctx context.Context, ns string, state *game.GameState) error {
+	return results.Archive{Client: p.client, Namespace: ns}.ArchiveGame(ctx, results.Game{Namespace: ns, State: state})
+}
+
+// recentGames converts results for the handicap.
+func recentGames(history []results.Result) []grid.RecentGame {
+	recent := make([]grid.RecentGame, 0, len(history))
+	for _, r := range history {
+		recent = append(recent, grid.RecentGame{
+			Won:       r.Status == game.StatusWon,
+			Progress:  r.Progress,
+			Elapsed:   time.Duration(r.ElapsedSeconds * float64(time.Second)),
+			SafeCells: r.Width*r.Height - r.Mines,
+		})
+	}
+	return recent
+}
@@ -0,0 +1,30 @@
+package workshop
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestKubeconfig(t *testing.T) {
+	data, err := Kubeconfig("https://k8s.example.com:6443", []byte("ca"), "ws-1", "player", "secret-token")
+	if err != nil {
+		t.Fatalf("Kubeconfig failed: %v", err)
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatalf("invalid kubeconfig: %v", err)
+	}
+	ctx := config.Contexts[config.CurrentContext]
+	if ctx == nil || ctx.Namespace != "ws-1" || ctx.AuthInfo != "player" {
+		t.Fatalf("unexpected current context %+v", ctx)
+	}
+	if config.AuthInfos["player"].Token != "secret-token" {
+		t.Error("expected the token to be set")
+	}
+	cluster := config.Clusters[ctx.Cluster]
+	if cluster.Server != "https://k8s.example.com:6443" || string(cluster.CertificateAuthorityData) != "ca" {
+		t.Errorf("unexpected cluster %+v", cluster)
+	}
+}
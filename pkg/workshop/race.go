@@ -0,0 +1,178 @@
+package workshop
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+const (
+	// LabelRace is the label of bot namespaces holding the namespace of
+	// the participant their bot races.
+	LabelRace = "podsweeper.io/race"
+
+	// BotSuffix suffixes the namespace of a participant to name the
+	// namespace of the bot racing them.
+	BotSuffix = "-bot"
+
+	// DefaultBotSkill is the chance of bots to spot each cell proven safe.
+	DefaultBotSkill = 0.8
+
+	// DefaultBotInterval is the pace of bots, one cell per interval.
+	DefaultBotInterval = 5 * time.Second
+
+	// RaceMirror is the axis bot boards mirror the boards of participants
+	// across, so that bots can't be copied cell for cell.
+	RaceMirror = grid.MirrorVertical
+)
+
+// Verdict is the outcome of a race against a bot.
+type Verdict string
+
+const (
+	// VerdictRacing is a race still undecided.
+	VerdictRacing Verdict = "racing"
+	// VerdictHuman is a race won by the participant.
+	VerdictHuman Verdict = "human"
+	// VerdictBot is a race won by the bot.
+	VerdictBot Verdict = "bot"
+	// VerdictDraw is a race both lost, or won in the same time.
+	VerdictDraw Verdict = "draw"
+)
+
+// Race is a game raced against a bot.
+type Race struct {
+	Namespace string         `json:"namespace"`
+	Verdict   Verdict        `json:"verdict"`
+	Human     game.GameStats `json:"human,omitzero"`
+	Bot       game.GameStats `json:"bot,omitzero"`
+}
+
+// Referee declares the winner of a race as of now: the first to clear
+// their board, in game time, or the one left standing when the other hits
+// a mine. A race isn't decided while the one still playing may clear their
+// board faster than the other did.
+func Referee(human, bot *game.GameState, now time.Time) Verdict {
+	if human == nil || bot == nil {
+		return VerdictRacing
+	}
+	h, b := human.ElapsedAt(now), bot.ElapsedAt(now)
+	switch {
+	case human.Status == game.StatusWon && bot.Status == game.StatusWon:
+		if h == b {
+			return VerdictDraw
+		}
+		if h < b {
+			return VerdictHuman
+		}
+		return VerdictBot
+	case human.Status == game.StatusLost && bot.Status == game.StatusLost:
+		return VerdictDraw
+	case human.Status == game.StatusLost:
+		return VerdictBot
+	case bot.Status == game.StatusLost:
+		return VerdictHuman
+	case human.Status == game.StatusWon && b >= h:
+		return VerdictHuman
+	case bot.Status == game.StatusWon && h >= b:
+		return VerdictBot
+	default:
+		return VerdictRacing
+	}
+}
+
+// BotNamespace returns the namespace of the bot racing the i-th game.
+func (p *Provisioner) BotNamespace(i int) string {
+	return p.Namespace(i) + BotSuffix
+}
+
+// Races referees the race of every game against its bot.
+func (p *Provisioner) Races(ctx context.Context) ([]Race, error) {
+	now := time.Now()
+	races := make([]Race, 0, p.config.Games)
+	for i := 0; i < p.config.Games; i++ {
+		ns := p.Namespace(i)
+		human, err := game.NewSecretStore(p.client, game.WithNamespace(ns)).Load(ctx)
+		if err != nil {
+			return races, fmt.Errorf("failed to load the game of %s: %w", ns, err)
+		}
+		bot, err := game.NewSecretStore(p.client, game.WithNamespace(p.BotNamespace(i))).Load(ctx)
+		if err != nil {
+			return races, fmt.Errorf("failed to load the game of %s: %w", p.BotNamespace(i), err)
+		}
+		race := Race{Namespace: ns, Verdict: Referee(human, bot, now)}
+		if human != nil {
+			race.Human = human.Stats()
+		}
+		if bot != nil {
+			race.Bot = bot.Stats()
+		}
+		races = append(races, race)
+	}
+	return races, nil
+}
+
+// provisionBot sets up the namespace and Gamemaster of the bot racing the
+// i-th game.
+func (p *Provisioner) provisionBot(ctx context.Context, i int) error {
+	ns := p.BotNamespace(i)
+	objects := append([]client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   ns,
+			Labels: map[string]string{LabelWorkshop: p.config.Name, LabelRace: p.Namespace(i)},
+		}},
+	}, p.gamemasterObjects(ns)...)
+	for _, obj := range objects {
+		if err := p.client.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create %T %s: %w", obj, obj.GetName(), err)
+		}
+	}
+	return p.startBot(ctx, i)
+}
+
+// startBot starts the game of the bot of the i-th game on the mirror image
+// of the board of the participant, unless it is already racing it.
+func (p *Provisioner) startBot(ctx context.Context, i int) error {
+	human, err := game.NewSecretStore(p.client, game.WithNamespace(p.Namespace(i))).Load(ctx)
+	if err != nil {
+		return err
+	}
+	if human == nil {
+		return fmt.Errorf("no game in %s for its bot to race", p.Namespace(i))
+	}
+	ns := p.BotNamespace(i)
+	store := game.NewSecretStore(p.client, game.WithNamespace(ns))
+	existing, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.CurrentPhase().Spawned() {
+			return nil
+		}
+		return p.spawnGame(ctx, store, ns, existing)
+	}
+
+	state := grid.Mirror(human, RaceMirror)
+	// Bots can't cut wires, they play the regular game
+	state.Speedrun = human.Speedrun
+	state.Theme = human.Theme
+	return p.spawnGame(ctx, store, ns, state)
+}
+
+// botArgs returns the flags of the Gamemaster of a bot namespace.
+func (p *Provisioner) botArgs() []string {
+	return []string{
+		"--autoplay-interval=" + p.config.BotInterval.String(),
+		"--autoplay-skill=" + strconv.FormatFloat(p.config.BotSkill, 'f', -1, 64),
+	}
+}
@@ -0,0 +1,133 @@
+package workshop
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+)
+
+func TestReferee(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := t0.Add(10 * time.Minute)
+	// racer returns a game started at t0, ended after d with status
+	racer := func(status game.GameStatus, d time.Duration) *game.GameState {
+		state := game.NewGameState(3, 1)
+		state.StartedAt = t0
+		state.Status = status
+		if status != game.StatusPlaying {
+			state.EndedAt = t0.Add(d)
+		}
+		return state
+	}
+
+	tests := []struct {
+		name       string
+		human, bot *game.GameState
+		want       Verdict
+	}{
+		{"both playing", racer(game.StatusPlaying, 0), racer(game.StatusPlaying, 0), VerdictRacing},
+		{"no bot yet", racer(game.StatusPlaying, 0), nil, VerdictRacing},
+		{"human faster", racer(game.StatusWon, time.Minute), racer(game.StatusWon, 2*time.Minute), VerdictHuman},
+		{"bot faster", racer(game.StatusWon, 3*time.Minute), racer(game.StatusWon, 2*time.Minute), VerdictBot},
+		{"same time", racer(game.StatusWon, time.Minute), racer(game.StatusWon, time.Minute), VerdictDraw},
+		{"human won, bot slower", racer(game.StatusWon, time.Minute), racer(game.StatusPlaying, 0), VerdictHuman},
+		{"bot won, human slower", racer(game.StatusPlaying, 0), racer(game.StatusWon, time.Minute), VerdictBot},
+		{"bot hit a mine", racer(game.StatusPlaying, 0), racer(game.StatusLost, time.Minute), VerdictHuman},
+		{"human hit a mine", racer(game.StatusLost, time.Minute), racer(game.StatusPlaying, 0), VerdictBot},
+		{"both hit a mine", racer(game.StatusLost, time.Minute), racer(game.StatusLost, 2*time.Minute), VerdictDraw},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Referee(tt.human, tt.bot, now); got != tt.want {
+				t.Errorf("Referee() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// The human may still beat the time of the bot
+	bot := racer(game.StatusWon, 15*time.Minute)
+	if got := Referee(racer(game.StatusPlaying, 0), bot, now); got != VerdictRacing {
+		t.Errorf("expected the race to go on, got %s", got)
+	}
+}
+
+func TestProvisionerRace(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{Name: "ws", Games: 1, Race: true, BotSkill: 0.5, Server: "https://k8s.example.com:6443"})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: "ws-1-bot"}, &ns); err != nil || ns.Labels[LabelRace] != "ws-1" {
+		t.Fatalf("expected the bot namespace racing ws-1: %v", err)
+	}
+	var deploy appsv1.Deployment
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1-bot", Name: GamemasterName}, &deploy); err != nil {
+		t.Fatalf("expected a bot Gamemaster: %v", err)
+	}
+	if args := deploy.Spec.Template.Spec.Containers[0].Args; !slices.Contains(args, "--autoplay-skill=0.5") {
+		t.Errorf("expected the bot Gamemaster to play fair, got %v", args)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: GamemasterName}, &deploy); err != nil {
+		t.Fatalf("expected a Gamemaster: %v", err)
+	}
+	if args := deploy.Spec.Template.Spec.Containers[0].Args; slices.Contains(args, "--autoplay-skill=0.5") {
+		t.Errorf("expected the participant's game not to play itself, got %v", args)
+	}
+
+	humanStore := game.NewSecretStore(c, game.WithNamespace("ws-1"))
+	botStore := game.NewSecretStore(c, game.WithNamespace("ws-1-bot"))
+	assertMirrored := func() {
+		t.Helper()
+		human, _ := humanStore.Load(ctx)
+		bot, _ := botStore.Load(ctx)
+		if bot == nil || bot.Phase != game.PhaseReady {
+			t.Fatalf("expected the bot game to be Ready, got %v", bot)
+		}
+		mirror := grid.Mirror(human, RaceMirror)
+		w, h := mirror.Dimensions()
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if mirror.IsMine(x, y) != bot.IsMine(x, y) {
+					t.Fatalf("expected the bot to race the mirror image of the participant's board, (%d,%d) differs", x, y)
+				}
+			}
+		}
+	}
+	assertMirrored()
+
+	races, err := p.Races(ctx)
+	if err != nil || len(races) != 1 || races[0].Verdict != VerdictRacing {
+		t.Fatalf("expected the race to go on, got %+v: %v", races, err)
+	}
+
+	bot, _ := botStore.Load(ctx)
+	bot.SetLost()
+	_ = botStore.Save(ctx, bot)
+	if races, _ := p.Races(ctx); races[0].Verdict != VerdictHuman {
+		t.Errorf("expected the participant to win, got %s", races[0].Verdict)
+	}
+
+	// The next game of the participant is raced again
+	human, _ := humanStore.Load(ctx)
+	human.SetWon()
+	_ = humanStore.Save(ctx, human)
+	if _, err := p.Next(ctx); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	assertMirrored()
+}
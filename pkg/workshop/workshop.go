@@ -0,0 +1,531 @@
+// Package workshop provisions isolated games for classrooms: one namespace
+// per participant, each with its own Gamemaster, game, player
+// ServiceAccount and namespace-scoped kubeconfig.
+package workshop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/rbac"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/theme"
+	"github.com/zwindler/podsweeper/pkg/vcluster"
+)
+
+const (
+	// LabelWorkshop is the label holding the workshop name on provisioned namespaces.
+	LabelWorkshop = "podsweeper.io/workshop"
+
+	// PlayerServiceAccount is the ServiceAccount participants play as.
+	PlayerServiceAccount = rbac.PlayerServiceAccount
+
+	// PlayerRole is the Role granting participants what playing requires.
+	PlayerRole = rbac.PlayerRole
+
+	// GamemasterName names the Gamemaster Deployment, ServiceAccount and Role.
+	GamemasterName = rbac.GamemasterName
+
+	// DefaultGamemasterImage is the image of the per-game Gamemaster.
+	DefaultGamemasterImage = "ghcr.io/zwindler/podsweeper-gamemaster:latest"
+
+	// DefaultTokenTTL is how long participant kubeconfigs stay valid.
+	DefaultTokenTTL = 8 * time.Hour
+
+	// MaxGames bounds the number of games of a workshop.
+	MaxGames = 200
+
+	// podsGoneTimeout bounds the wait for the pods of a finished game to
+	// be deleted.
+	podsGoneTimeout = 2 * time.Minute
+)
+
+// Config describes a workshop.
+type Config struct {
+	// Name prefixes the namespaces, named <name>-1 to <name>-<games>.
+	Name string
+
+	// Games is the number of participants.
+	Games int
+
+	// Difficulties are assigned to games in turn. Defaults to easy.
+	Difficulties []grid.DifficultyPreset
+
+	// Presets resolves difficulties. Defaults to the built-in presets.
+	Presets *grid.PresetRegistry
+
+	// Speedrun starts every game in speedrun mode, with a live timer and
+	// times ranked per seed.
+	Speedrun bool
+
+	// Defusal starts every game in defusal mode, and lets participants
+	// exec into bomb pods.
+	Defusal bool
+
+	// HintDecay starts every game in hint decay mode: hints expire that
+	// long after they are revealed. Zero keeps hints for the whole game.
+	HintDecay time.Duration
+
+	// HintType is what the revealed cells of every game tell: counts of
+	// adjacent mines, or temperatures. Defaults to game.HintCount.
+	HintType game.HintType
+
+	// ShiftEvery starts every game in shifting mines mode: a hidden mine
+	// moves every that many moves. Zero keeps the mines in place.
+	ShiftEvery int
+
+	// Lives starts every game in multi-life mode: hitting a mine costs a
+	// life, and the game is lost with the last one. Zero plays with a
+	// single life.
+	Lives int
+
+	// TimeLimit starts every game in timed mode: the game is lost once it
+	// has been played that long. Zero plays without limit.
+	TimeLimit time.Duration
+
+	// Theme is the accessibility theme of every game. Defaults to classic.
+	Theme theme.Theme
+
+	// Adaptive tunes the density and size of each new board to the recent
+	// wins and times of its participant, from the history of the namespace.
+	Adaptive bool
+
+	// Sharded leaves the games to `gamemaster shard` replicas selecting
+	// the namespaces of the workshop by LabelWorkshop, instead of
+	// deploying a Gamemaster per game.
+	Sharded bool
+
+	// Profile shapes the games to the cluster, such as spawner.ProfileTiny
+	// for laptops. Defaults to spawner.ProfileDefault. Sharded Gamemasters
+	// must be run with the same --profile.
+	Profile spawner.Profile
+
+	// VCluster plays each game in a vcluster installed in its namespace,
+	// giving its participant a whole cluster as cluster-admin. The
+	// Gamemaster spawns the board once the vcluster is up. Not supported
+	// with Sharded.
+	VCluster bool
+
+	// Race pairs each game with a bot racing the participant on the
+	// mirror image of their board, in the namespace named by BotNamespace,
+	// where the Gamemaster plays it fair. Races are decided by Referee.
+	// Not supported with Sharded or VCluster.
+	Race bool
+
+	// BotSkill is the chance of bots to spot each cell proven safe, from
+	// 0 to 1. Defaults to DefaultBotSkill.
+	BotSkill float64
+
+	// BotInterval is the pace of bots. Defaults to DefaultBotInterval.
+	BotInterval time.Duration
+
+	// GamemasterImage defaults to DefaultGamemasterImage.
+	GamemasterImage string
+
+	// TokenTTL defaults to DefaultTokenTTL.
+	TokenTTL time.Duration
+
+	// Server is the API server URL written in kubeconfigs.
+	Server string
+
+	// CAData is the API server CA bundle written in kubeconfigs.
+	CAData []byte
+}
+
+// Seat is a provisioned game.
+type Seat struct {
+	Namespace  string
+	Difficulty grid.DifficultyPreset
+	Kubeconfig []byte
+}
+
+// Provisioner creates and tears down workshops.
+type Provisioner struct {
+	client client.Client
+	config Config
+}
+
+// NewProvisioner validates the config and creates a Provisioner.
+func NewProvisioner(c client.Client, config Config) (*Provisioner, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("workshop name is required")
+	}
+	if config.Games < 1 || config.Games > MaxGames {
+		return nil, fmt.Errorf("games must be between 1 and %d, got %d", MaxGames, config.Games)
+	}
+	if len(config.Difficulties) == 0 {
+		config.Difficulties = []grid.DifficultyPreset{grid.DifficultyEasy}
+	}
+	if config.Presets == nil {
+		config.Presets = grid.NewPresetRegistry()
+	}
+	for _, d := range config.Difficulties {
+		if _, ok := config.Presets.Get(d); !ok {
+			return nil, fmt.Errorf("unknown difficulty %q", d)
+		}
+	}
+	t, err := theme.Parse(string(config.Theme))
+	if err != nil {
+		return nil, err
+	}
+	config.Theme = t
+	if config.Profile, err = spawner.ParseProfile(string(config.Profile)); err != nil {
+		return nil, err
+	}
+	if config.VCluster && config.Sharded {
+		return nil, fmt.Errorf("vcluster games need a Gamemaster per game, they can't be sharded")
+	}
+	if config.Race && (config.Sharded || config.VCluster) {
+		return nil, fmt.Errorf("bots race with a Gamemaster per game, not sharded or in a vcluster")
+	}
+	if config.HintDecay < 0 {
+		return nil, fmt.Errorf("hint decay must not be negative, got %v", config.HintDecay)
+	}
+	if _, err := game.ParseHintType(string(config.HintType)); err != nil {
+		return nil, err
+	}
+	if config.ShiftEvery < 0 {
+		return nil, fmt.Errorf("mines must shift every 1 move or more, got %d", config.ShiftEvery)
+	}
+	if config.TimeLimit < 0 {
+		return nil, fmt.Errorf("time limit must not be negative, got %v", config.TimeLimit)
+	}
+	if config.Lives < 0 {
+		return nil, fmt.Errorf("lives must not be negative, got %d", config.Lives)
+	}
+	if config.BotSkill < 0 || config.BotSkill > 1 {
+		return nil, fmt.Errorf("bot skill must be between 0 and 1, got %g", config.BotSkill)
+	}
+	if config.BotSkill == 0 {
+		config.BotSkill = DefaultBotSkill
+	}
+	if config.BotInterval <= 0 {
+		config.BotInterval = DefaultBotInterval
+	}
+	if config.GamemasterImage == "" {
+		config.GamemasterImage = DefaultGamemasterImage
+	}
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = DefaultTokenTTL
+	}
+	return &Provisioner{client: c, config: config}, nil
+}
+
+// Namespace returns the namespace of the i-th game, starting at 0.
+func (p *Provisioner) Namespace(i int) string {
+	return fmt.Sprintf("%s-%d", p.config.Name, i+1)
+}
+
+// Up provisions every game. It can be run again to resume a partial
+// provisioning: existing objects and games are kept.
+func (p *Provisioner) Up(ctx context.Context) ([]Seat, error) {
+	seats := make([]Seat, 0, p.config.Games)
+	for i := 0; i < p.config.Games; i++ {
+		seat, err := p.provision(ctx, i)
+		if err != nil {
+			return seats, fmt.Errorf("failed to provision %s: %w", p.Namespace(i), err)
+		}
+		seats = append(seats, seat)
+	}
+	return seats, nil
+}
+
+// Down deletes every namespace of the workshop and returns how many were
+// deleted. Namespaces are found by label, so games of a workshop
+// provisioned with a larger count are removed too.
+func (p *Provisioner) Down(ctx context.Context) (int, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := p.client.List(ctx, namespaces, client.MatchingLabels{LabelWorkshop: p.config.Name}); err != nil {
+		return 0, fmt.Errorf("failed to list workshop namespaces: %w", err)
+	}
+
+	deleted := 0
+	for i := range namespaces.Items {
+		if err := p.client.Delete(ctx, &namespaces.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete namespace %s: %w", namespaces.Items[i].Name, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Next starts a new game in every namespace whose game has ended, after
+// recording the result of the finished game in the namespace history. It
+// returns the namespaces where a game was started.
+func (p *Provisioner) Next(ctx context.Context) ([]string, error) {
+	var started []string
+	for i := 0; i < p.config.Games; i++ {
+		ns := p.Namespace(i)
+		store := game.NewSecretStore(p.client, game.WithNamespace(ns))
+		state, err := store.Load(ctx)
+		if err != nil {
+			return started, fmt.Errorf("failed to load the game of %s: %w", ns, err)
+		}
+		if state == nil || state.Status == game.StatusPlaying {
+			continue
+		}
+
+		if err := p.recordResult(ctx, ns, state); err != nil {
+			return started, err
+		}
+		// The Gamemaster ignores the deletions while the finished game is
+		// stored. Boards in a vcluster are cleared by their Gamemaster.
+		if !p.config.VCluster {
+			if err := p.clearGamePods(ctx, ns); err != nil {
+				return started, err
+			}
+		}
+		if err := store.Delete(ctx); err != nil {
+			return started, err
+		}
+		if err := p.startGame(ctx, ns, p.config.Difficulties[i%len(p.config.Difficulties)]); err != nil {
+			return started, err
+		}
+		// The bot races the new board, whether it finished its game or not
+		if p.config.Race {
+			if err := p.clearGamePods(ctx, p.BotNamespace(i)); err != nil {
+				return started, err
+			}
+			if err := game.NewSecretStore(p.client, game.WithNamespace(p.BotNamespace(i))).Delete(ctx); err != nil {
+				return started, err
+			}
+			if err := p.startBot(ctx, i); err != nil {
+				return started, err
+			}
+		}
+		started = append(started, ns)
+	}
+	return started, nil
+}
+
+// clearGamePods deletes every pod of the game of ns, the Gamemaster aside,
+// and waits until they are gone so that the next game can reuse their names.
+func (p *Provisioner) clearGamePods(ctx context.Context, ns string) error {
+	selector := labels.SelectorFromSet(labels.Set{spawner.LabelApp: "podsweeper"})
+	notGamemaster, _ := labels.NewRequirement(spawner.LabelComponent, selection.NotEquals, []string{"gamemaster"})
+	selector = selector.Add(*notGamemaster)
+
+	return wait.PollUntilContextTimeout(ctx, time.Second, podsGoneTimeout, true, func(ctx context.Context) (bool, error) {
+		pods := &corev1.PodList{}
+		if err := p.client.List(ctx, pods, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, fmt.Errorf("failed to list game pods: %w", err)
+		}
+		for i := range pods.Items {
+			if pods.Items[i].DeletionTimestamp != nil {
+				continue
+			}
+			if err := p.client.Delete(ctx, &pods.Items[i]); err != nil && !errors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to delete pod %s: %w", pods.Items[i].Name, err)
+			}
+		}
+		return len(pods.Items) == 0, nil
+	})
+}
+
+// provision sets up the i-th game.
+func (p *Provisioner) provision(ctx context.Context, i int) (Seat, error) {
+	logger := log.FromContext(ctx)
+	ns := p.Namespace(i)
+	difficulty := p.config.Difficulties[i%len(p.config.Difficulties)]
+
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   ns,
+			Labels: map[string]string{LabelWorkshop: p.config.Name},
+		}},
+	}
+	if !p.config.Sharded {
+		objects = append(objects, p.gamemasterObjects(ns)...)
+	}
+	objects = append(objects, p.playerObjects(ns)...)
+	if p.config.VCluster {
+		objects = append(objects, vcluster.InstallObjects(ns, vcluster.Config{}, nil)...)
+	}
+	for _, obj := range objects {
+		if err := p.client.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return Seat{}, fmt.Errorf("failed to create %T %s: %w", obj, obj.GetName(), err)
+		}
+	}
+
+	if err := p.startGame(ctx, ns, difficulty); err != nil {
+		return Seat{}, err
+	}
+	if p.config.Race {
+		if err := p.provisionBot(ctx, i); err != nil {
+			return Seat{}, err
+		}
+	}
+
+	token, err := p.playerToken(ctx, ns)
+	if err != nil {
+		return Seat{}, err
+	}
+	kubeconfig, err := Kubeconfig(p.config.Server, p.config.CAData, ns, PlayerServiceAccount, token)
+	if err != nil {
+		return Seat{}, err
+	}
+
+	logger.Info("provisioned game", "namespace", ns, "difficulty", difficulty)
+	return Seat{Namespace: ns, Difficulty: difficulty, Kubeconfig: kubeconfig}, nil
+}
+
+// startGame generates and spawns the game, unless one already exists. The
+// spawning of a game interrupted by an earlier failure is resumed.
+func (p *Provisioner) startGame(ctx context.Context, ns string, difficulty grid.DifficultyPreset) error {
+	store := game.NewSecretStore(p.client, game.WithNamespace(ns))
+	existing, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.CurrentPhase().Spawned() || p.config.VCluster {
+			return nil
+		}
+		return p.spawnGame(ctx, store, ns, existing)
+	}
+
+	config, _ := p.config.Presets.Get(difficulty)
+	config.SecureSeed = true
+	if p.config.Adaptive {
+		history, err := p.History(ctx, ns)
+		if err != nil {
+			return err
+		}
+		handicap := grid.Handicap(recentGames(history))
+		config = grid.Adapt(config, handicap)
+		w, h := config.Dimensions()
+		log.FromContext(ctx).Info("adapted difficulty", "namespace", ns, "handicap", handicap,
+			"width", w, "height", h, "density", config.MineDensity)
+	}
+	config = p.config.Profile.Board(config)
+	gen, err := grid.NewGenerator(config)
+	if err != nil {
+		return fmt.Errorf("invalid difficulty %q: %w", difficulty, err)
+	}
+	state := gen.Generate()
+	state.Speedrun = p.config.Speedrun
+	state.Defusal = p.config.Defusal
+	state.HintDecay = p.config.HintDecay
+	state.ShiftEvery = p.config.ShiftEvery
+	state.Lives = p.config.Lives
+	state.TimeLimit = p.config.TimeLimit
+	state.HintType = p.config.HintType
+	state.Theme = string(p.config.Theme)
+
+	// The vcluster can't be reached from here: its Gamemaster spawns the
+	// board of the Pending game
+	if p.config.VCluster {
+		return store.Save(ctx, state)
+	}
+	return p.spawnGame(ctx, store, ns, state)
+}
+
+// spawnGame saves state and spawns its board. The Gamemaster defers moves
+// until the whole board is spawned.
+func (p *Provisioner) spawnGame(ctx context.Context, store game.Store, ns string, state *game.GameState) error {
+	if err := state.SetPhase(game.PhaseSpawning); err != nil {
+		return err
+	}
+	if err := store.Save(ctx, state); err != nil {
+		return err
+	}
+	s := spawner.NewGridSpawner(p.client, spawner.GridSpawnerConfig{Namespace: ns, Profile: p.config.Profile})
+	if _, err := s.SpawnGrid(ctx, state); err != nil {
+		return fmt.Errorf("failed to spawn grid: %w", err)
+	}
+	if err := state.SetPhase(game.PhaseReady); err != nil {
+		return err
+	}
+	return store.Save(ctx, state)
+}
+
+// playerToken requests a token for the player ServiceAccount.
+func (p *Provisioner) playerToken(ctx context.Context, ns string) (string, error) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: PlayerServiceAccount, Namespace: ns}}
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(p.config.TokenTTL.Seconds())),
+		},
+	}
+	if err := p.client.SubResource("token").Create(ctx, sa, request); err != nil {
+		return "", fmt.Errorf("failed to request player token: %w", err)
+	}
+	return request.Status.Token, nil
+}
+
+// gamemasterObjects returns the Gamemaster of a game, restricted to its namespace.
+func (p *Provisioner) gamemasterObjects(ns string) []client.Object {
+	labels := map[string]string{
+		spawner.LabelApp:       "podsweeper",
+		spawner.LabelComponent: "gamemaster",
+	}
+	meta := metav1.ObjectMeta{Name: GamemasterName, Namespace: ns, Labels: labels}
+
+	rbacConfig := rbac.Config{Namespace: ns}
+	if p.config.VCluster {
+		rbacConfig.KubeconfigSecret = vcluster.KubeconfigSecret(vcluster.DefaultName)
+	}
+	return append(rbac.GamemasterObjects(rbacConfig, labels),
+		&appsv1.Deployment{
+			ObjectMeta: meta,
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: GamemasterName,
+						// Game namespaces may enforce the restricted Pod Security Standard
+						SecurityContext: spawner.RestrictedPodSecurityContext(),
+						Containers: []corev1.Container{{
+							Name:            "gamemaster",
+							Image:           p.config.GamemasterImage,
+							Args:            p.gamemasterArgs(ns),
+							SecurityContext: spawner.RestrictedSecurityContext(),
+						}},
+					},
+				},
+			},
+		},
+	)
+}
+
+// gamemasterArgs returns the flags of the Gamemaster of ns, sharing the
+// settings of the workshop.
+func (p *Provisioner) gamemasterArgs(ns string) []string {
+	args := append([]string{"--namespace=" + ns}, game.Naming().Args()...)
+	if p.config.VCluster {
+		args = append(args, vcluster.GamemasterArgs(ns, vcluster.Config{})...)
+	}
+	if p.config.Race && strings.HasSuffix(ns, BotSuffix) {
+		args = append(args, p.botArgs()...)
+	}
+	return append(args, p.config.Profile.Args()...)
+}
+
+// playerObjects returns the participant's identity: listing and deleting
+// pods is allowed, reading hints or the game state Secret is not. With a
+// vcluster, the participant may also connect to it as cluster-admin.
+func (p *Provisioner) playerObjects(ns string) []client.Object {
+	objects := rbac.PlayerObjects(ns, nil, p.config.Defusal)
+	if p.config.VCluster {
+		objects = append(objects, vcluster.PlayerObjects(ns, vcluster.Config{}, nil)...)
+	}
+	return objects
+}
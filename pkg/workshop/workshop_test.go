@@ -0,0 +1,294 @@
+package workshop
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zwindler/podsweeper/pkg/game"
+	"github.com/zwindler/podsweeper/pkg/grid"
+	"github.com/zwindler/podsweeper/pkg/spawner"
+	"github.com/zwindler/podsweeper/pkg/vcluster"
+)
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
+	return scheme
+}
+
+func TestNewProvisionerValidates(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"missing name", Config{Games: 1}},
+		{"no games", Config{Name: "ws"}},
+		{"too many games", Config{Name: "ws", Games: MaxGames + 1}},
+		{"unknown difficulty", Config{Name: "ws", Games: 1, Difficulties: []grid.DifficultyPreset{"impossible"}}},
+		{"unknown theme", Config{Name: "ws", Games: 1, Theme: "neon"}},
+		{"unknown profile", Config{Name: "ws", Games: 1, Profile: "huge"}},
+		{"sharded vclusters", Config{Name: "ws", Games: 1, VCluster: true, Sharded: true}},
+		{"sharded race", Config{Name: "ws", Games: 1, Race: true, Sharded: true}},
+		{"bot skill", Config{Name: "ws", Games: 1, Race: true, BotSkill: 1.5}},
+		{"hint decay", Config{Name: "ws", Games: 1, HintDecay: -time.Second}},
+		{"shifting mines", Config{Name: "ws", Games: 1, ShiftEvery: -1}},
+		{"lives", Config{Name: "ws", Games: 1, Lives: -1}},
+		{"time limit", Config{Name: "ws", Games: 1, TimeLimit: -time.Minute}},
+		{"hint type", Config{Name: "ws", Games: 1, HintType: "lukewarm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProvisioner(c, tt.config); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestProvisionerUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{
+		Name:         "ws",
+		Games:        2,
+		Difficulties: []grid.DifficultyPreset{grid.DifficultyEasy, grid.DifficultyWide},
+		Server:       "https://k8s.example.com:6443",
+	})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+
+	seats, err := p.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(seats) != 2 || seats[0].Namespace != "ws-1" || seats[1].Namespace != "ws-2" {
+		t.Fatalf("unexpected seats: %+v", seats)
+	}
+	if seats[1].Difficulty != grid.DifficultyWide {
+		t.Errorf("expected difficulties in turn, got %s", seats[1].Difficulty)
+	}
+
+	for _, seat := range seats {
+		state, err := game.NewSecretStore(c, game.WithNamespace(seat.Namespace)).Load(ctx)
+		if err != nil || state == nil {
+			t.Fatalf("expected a game in %s: %v", seat.Namespace, err)
+		}
+		if state.Phase != game.PhaseReady {
+			t.Errorf("expected the game in %s to be Ready, got %s", seat.Namespace, state.Phase)
+		}
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(seat.Namespace)); err != nil {
+			t.Fatalf("failed to list pods: %v", err)
+		}
+		// One pod per cell, and the resign pod
+		if len(pods.Items) != state.TotalCells()+1 {
+			t.Errorf("expected %d cell pods in %s, got %d", state.TotalCells(), seat.Namespace, len(pods.Items)-1)
+		}
+		var role rbacv1.Role
+		if err := c.Get(ctx, client.ObjectKey{Namespace: seat.Namespace, Name: PlayerRole}, &role); err != nil {
+			t.Fatalf("expected player role: %v", err)
+		}
+		for _, rule := range role.Rules {
+			for _, r := range rule.Resources {
+				if r == "secrets" {
+					t.Error("players must not read the game state secret")
+				}
+			}
+		}
+		var deploy appsv1.Deployment
+		if err := c.Get(ctx, client.ObjectKey{Namespace: seat.Namespace, Name: GamemasterName}, &deploy); err != nil {
+			t.Fatalf("expected gamemaster deployment: %v", err)
+		}
+		if got := deploy.Spec.Template.Spec.Containers[0].Args; len(got) != 1 || got[0] != "--namespace="+seat.Namespace {
+			t.Errorf("unexpected gamemaster args %v", got)
+		}
+		if !strings.Contains(string(seat.Kubeconfig), "namespace: "+seat.Namespace) {
+			t.Errorf("expected kubeconfig scoped to %s", seat.Namespace)
+		}
+	}
+
+	// Running again keeps the existing games
+	before, _ := game.NewSecretStore(c, game.WithNamespace("ws-1")).Load(ctx)
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("second Up failed: %v", err)
+	}
+	after, _ := game.NewSecretStore(c, game.WithNamespace("ws-1")).Load(ctx)
+	if before.Seed != after.Seed {
+		t.Error("expected the existing game to be kept")
+	}
+
+	deleted, err := p.Down(ctx)
+	if err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 namespaces deleted, got %d", deleted)
+	}
+}
+
+func TestProvisionerNext(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{Name: "ws", Games: 2, Adaptive: true})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	started, err := p.Next(ctx)
+	if err != nil || len(started) != 0 {
+		t.Fatalf("expected no game to restart while playing, got %v: %v", started, err)
+	}
+
+	// ws-1 won quickly, twice
+	store := game.NewSecretStore(c, game.WithNamespace("ws-1"))
+	for round := 0; round < 2; round++ {
+		state, _ := store.Load(ctx)
+		state.StartedAt = time.Now().Add(-10 * time.Second)
+		state.SetWon()
+		if err := store.Save(ctx, state); err != nil {
+			t.Fatalf("failed to save game: %v", err)
+		}
+		started, err = p.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if len(started) != 1 || started[0] != "ws-1" {
+			t.Fatalf("expected a new game in ws-1 only, got %v", started)
+		}
+	}
+
+	history, err := p.History(ctx, "ws-1")
+	if err != nil || len(history) != 2 || history[0].Status != game.StatusWon {
+		t.Fatalf("expected 2 won games in the history, got %+v: %v", history, err)
+	}
+
+	state, _ := store.Load(ctx)
+	easy := grid.GetDifficultyConfig(grid.DifficultyEasy)
+	if state.Status != game.StatusPlaying || state.Size <= easy.Size {
+		t.Errorf("expected a larger board after fast wins, got %dx%d (%s)", state.Size, state.Size, state.Status)
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace("ws-1")); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) != state.TotalCells()+1 {
+		t.Errorf("expected only the %d pods of the new game, got %d", state.TotalCells()+1, len(pods.Items))
+	}
+}
+
+func TestProvisionerSharded(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{Name: "ws", Games: 1, Sharded: true, Server: "https://k8s.example.com:6443"})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: "ws-1"}, &ns); err != nil || ns.Labels[LabelWorkshop] != "ws" {
+		t.Fatalf("expected the namespace selectable by sharded Gamemasters: %v", err)
+	}
+	var deploy appsv1.Deployment
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: GamemasterName}, &deploy); err == nil {
+		t.Error("expected no Gamemaster per game")
+	}
+}
+
+func TestProvisionerTinyProfile(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{
+		Name:         "ws",
+		Games:        1,
+		Difficulties: []grid.DifficultyPreset{grid.DifficultyExpert},
+		Profile:      spawner.ProfileTiny,
+		Server:       "https://k8s.example.com:6443",
+	})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	state, _ := game.NewSecretStore(c, game.WithNamespace("ws-1")).Load(ctx)
+	if w, h := state.Dimensions(); w > spawner.TinyMaxDimension || h > spawner.TinyMaxDimension {
+		t.Errorf("expected a tiny board, got %dx%d", w, h)
+	}
+	var cell corev1.Pod
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: game.Coordinate{}.PodName()}, &cell); err != nil {
+		t.Fatalf("expected cell pods: %v", err)
+	}
+	if len(cell.Spec.SchedulingGates) == 0 {
+		t.Error("expected tiny cells to be gated")
+	}
+	var deploy appsv1.Deployment
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: GamemasterName}, &deploy); err != nil {
+		t.Fatalf("expected a Gamemaster: %v", err)
+	}
+	if args := deploy.Spec.Template.Spec.Containers[0].Args; !slices.Contains(args, "--profile=tiny") {
+		t.Errorf("expected the Gamemaster to run the tiny profile, got %v", args)
+	}
+}
+
+func TestProvisionerVCluster(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	p, err := NewProvisioner(c, Config{Name: "ws", Games: 1, VCluster: true, Server: "https://k8s.example.com:6443"})
+	if err != nil {
+		t.Fatalf("NewProvisioner failed: %v", err)
+	}
+	if _, err := p.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var job batchv1.Job
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: vcluster.InstallerName}, &job); err != nil {
+		t.Fatalf("expected the vcluster to be installed: %v", err)
+	}
+	// The board is left to the Gamemaster, inside the vcluster
+	state, _ := game.NewSecretStore(c, game.WithNamespace("ws-1")).Load(ctx)
+	if state == nil || state.Phase != game.PhasePending {
+		t.Fatalf("expected a Pending game, got %v", state)
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace("ws-1")); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("expected no board pods in the host namespace, got %d", len(pods.Items))
+	}
+	var deploy appsv1.Deployment
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: GamemasterName}, &deploy); err != nil {
+		t.Fatalf("expected a Gamemaster: %v", err)
+	}
+	if args := deploy.Spec.Template.Spec.Containers[0].Args; !slices.Contains(args, "--game-kubeconfig-secret=vc-podsweeper") {
+		t.Errorf("expected the Gamemaster to play in the vcluster, got %v", args)
+	}
+	var role rbacv1.Role
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ws-1", Name: vcluster.PlayerRole}, &role); err != nil {
+		t.Errorf("expected players to reach the vcluster: %v", err)
+	}
+}